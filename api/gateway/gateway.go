@@ -13,6 +13,7 @@ import (
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/meshplus/bitxhub-model/pb"
+	grpcproto "github.com/meshplus/bitxhub/api/grpc/proto"
 	"github.com/meshplus/bitxhub/internal/loggers"
 	"github.com/meshplus/bitxhub/internal/repo"
 	"github.com/rs/cors"
@@ -104,6 +105,10 @@ func (g *Gateway) Start() error {
 		if err != nil {
 			return fmt.Errorf("register chain broker handler failed: %w", err)
 		}
+		err = grpcproto.RegisterSimulatorHandler(g.ctx, g.mux, conn)
+		if err != nil {
+			return fmt.Errorf("register simulator handler failed: %w", err)
+		}
 
 		go func() {
 			err := g.server.ListenAndServeTLS(g.certFile, g.keyFile)
@@ -117,6 +122,10 @@ func (g *Gateway) Start() error {
 		if err != nil {
 			return fmt.Errorf("register chain broker handler from endpoint %s failed: %w", g.endpoint, err)
 		}
+		err = grpcproto.RegisterSimulatorHandlerFromEndpoint(g.ctx, g.mux, g.endpoint, opts)
+		if err != nil {
+			return fmt.Errorf("register simulator handler from endpoint %s failed: %w", g.endpoint, err)
+		}
 
 		go func() {
 			err := g.server.ListenAndServe()
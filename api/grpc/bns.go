@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/meshplus/bitxhub-model/pb"
+	grpcproto "github.com/meshplus/bitxhub/api/grpc/proto"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
+)
+
+var _ grpcproto.BnsServer = (*ChainBrokerService)(nil)
+
+func (cbs *ChainBrokerService) GetOwnershipHistory(ctx context.Context, req *grpcproto.GetOwnershipHistoryRequest) (*grpcproto.OwnershipHistory, error) {
+	events, err := cbs.api.Broker().GetOwnershipHistory(req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("get ownership history of %s failed: %w", req.Name, err)
+	}
+
+	ret := &grpcproto.OwnershipHistory{
+		Events: make([]*grpcproto.OwnershipEvent, 0, len(events)),
+	}
+	for _, event := range events {
+		ret.Events = append(ret.Events, &grpcproto.OwnershipEvent{
+			Name:      event.Name,
+			From:      event.From,
+			To:        event.To,
+			Type:      event.Type,
+			Timestamp: event.Timestamp,
+		})
+	}
+
+	return ret, nil
+}
+
+// resolveServiceID returns the service id a BNS name points to at the current height,
+// ids which are not BNS names are returned unchanged.
+func (cbs *ChainBrokerService) resolveServiceID(id string) (string, error) {
+	if !contracts.IsBnsName(id) {
+		return id, nil
+	}
+	resolved, err := cbs.api.Broker().ResolveName(id)
+	if err != nil {
+		return "", err
+	}
+	if resolved.ServiceID == "" {
+		return "", fmt.Errorf("domain %s is not bound to any service", id)
+	}
+	return resolved.ServiceID, nil
+}
+
+// resolveIBTPID resolves BNS names in an IBTP id of the form from-to-index
+func (cbs *ChainBrokerService) resolveIBTPID(id string) (string, error) {
+	parts := strings.Split(id, "-")
+	if len(parts) != 3 {
+		return id, nil
+	}
+	for i := 0; i < 2; i++ {
+		serviceID, err := cbs.resolveServiceID(parts[i])
+		if err != nil {
+			return "", fmt.Errorf("resolve %s: %w", parts[i], err)
+		}
+		parts[i] = serviceID
+	}
+	return strings.Join(parts, "-"), nil
+}
+
+func isIBTPSignReq(req *pb.GetSignsRequest) bool {
+	switch req.Type {
+	case pb.GetSignsRequest_MULTI_IBTP_REQUEST, pb.GetSignsRequest_MULTI_IBTP_RESPONSE,
+		pb.GetSignsRequest_TSS_IBTP_REQUEST, pb.GetSignsRequest_TSS_IBTP_RESPONSE:
+		return true
+	default:
+		return false
+	}
+}
@@ -16,6 +16,7 @@ import (
 	node_mgr "github.com/meshplus/bitxhub-core/node-mgr"
 	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
+	grpcproto "github.com/meshplus/bitxhub/api/grpc/proto"
 	"github.com/meshplus/bitxhub/internal/coreapi/api"
 	"github.com/meshplus/bitxhub/internal/ledger"
 	"github.com/meshplus/bitxhub/internal/loggers"
@@ -233,6 +234,9 @@ func (cbs *ChainBrokerService) Start() error {
 	}
 
 	pb.RegisterChainBrokerServer(cbs.server, cbs)
+	grpcproto.RegisterTssAuditServer(cbs.server, cbs)
+	grpcproto.RegisterSimulatorServer(cbs.server, cbs)
+	grpcproto.RegisterBnsServer(cbs.server, cbs)
 
 	cbs.logger.WithFields(logrus.Fields{
 		"port": cbs.config.Port.Grpc,
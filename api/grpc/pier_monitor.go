@@ -2,9 +2,12 @@ package grpc
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
+	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
 )
 
 func (cbs *ChainBrokerService) CheckMasterPier(ctx context.Context, req *pb.Address) (*pb.Response, error) {
@@ -77,6 +80,11 @@ func (cbs *ChainBrokerService) HeartBeat(ctx context.Context, req *pb.PierInfo)
 }
 
 func (cbs *ChainBrokerService) checkMasterPier(address string) (bool, error) {
+	// the lease recorded on chain decides the master pier if the appchain elects it by lease
+	if alive, ok := cbs.checkPierLease(address); ok {
+		return alive, nil
+	}
+
 	pmgr := cbs.api.Network().PierManager()
 	if pmgr.Piers().HasPier(address) {
 		// cbs.logger.Infoln("native master")
@@ -86,3 +94,21 @@ func (cbs *ChainBrokerService) checkMasterPier(address string) (bool, error) {
 		return pmgr.AskPierMaster(address)
 	}
 }
+
+// checkPierLease returns whether the pier lease of the appchain is alive, ok is false if the appchain has no lease
+func (cbs *ChainBrokerService) checkPierLease(chainID string) (alive bool, ok bool) {
+	lg := cbs.api.Broker().GetStateLedger().Copy()
+	exist, data := lg.GetState(constant.InterchainContractAddr.Address(), []byte(contracts.PierLeaseKey(chainID)))
+	if !exist {
+		return false, false
+	}
+	lease := &contracts.PierLease{}
+	if err := json.Unmarshal(data, lease); err != nil || lease.Token == 0 {
+		return false, false
+	}
+	meta, err := cbs.api.Chain().Meta()
+	if err != nil {
+		return false, false
+	}
+	return meta.Height < lease.ExpireHeight, true
+}
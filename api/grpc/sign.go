@@ -12,8 +12,11 @@ import (
 	"sync"
 	"time"
 
+	node_mgr "github.com/meshplus/bitxhub-core/node-mgr"
 	"github.com/meshplus/bitxhub-core/tss/conversion"
+	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
 	"github.com/meshplus/bitxhub/internal/model"
 	"github.com/meshplus/bitxhub/pkg/utils"
 	"github.com/sirupsen/logrus"
@@ -23,8 +26,15 @@ func (cbs *ChainBrokerService) GetMultiSigns(ctx context.Context, req *pb.GetSig
 	var (
 		wg     = sync.WaitGroup{}
 		result = make(map[string][]byte)
+		err    error
 	)
 
+	if isIBTPSignReq(req) {
+		if req.Content, err = cbs.resolveIBTPID(req.Content); err != nil {
+			return nil, err
+		}
+	}
+
 	signers := []string{}
 	for id := range cbs.api.Network().OtherPeers() {
 		signers = append(signers, strconv.Itoa(int(id)))
@@ -70,12 +80,16 @@ func (cbs *ChainBrokerService) GetTssSigns(ctx context.Context, req *pb.GetSigns
 	if !utils.IsTssReq(req) {
 		return nil, fmt.Errorf("req type is not tss req")
 	}
+	if req.Content, err = cbs.resolveIBTPID(req.Content); err != nil {
+		return nil, err
+	}
 
 	// 2. get tss info
 	signersALL, poolPk, tssFlag, err := cbs.getTssInfo()
 	if err != nil {
 		return nil, err
 	}
+	signersALL = cbs.filterTssFrozenParties(signersALL)
 
 	// 3. make a tss req with threshold signers
 	tssReq := &pb.GetSignsRequest{
@@ -226,6 +240,29 @@ func (cbs *ChainBrokerService) getLocalTssSign(tssFlag bool, tssReq *pb.GetSigns
 	return culprits, sign, keysignErr
 }
 
+// filterTssFrozenParties removes the parties frozen out of the tss signing group by governance
+func (cbs *ChainBrokerService) filterTssFrozenParties(parties []string) []string {
+	lg := cbs.api.Broker().GetStateLedger().Copy()
+	ret := make([]string, 0, len(parties))
+	for _, id := range parties {
+		var account string
+		if ok, data := lg.GetState(constant.NodeManagerContractAddr.Address(), []byte(node_mgr.VpNodeIdKey(id))); ok {
+			_ = json.Unmarshal(data, &account)
+		}
+		freeze := &contracts.TssFreeze{}
+		if ok, data := lg.GetState(constant.NodeManagerContractAddr.Address(), []byte(contracts.TssFreezeKey(account))); ok &&
+			json.Unmarshal(data, freeze) == nil && freeze.IsFrozen() {
+			cbs.logger.WithFields(logrus.Fields{
+				"party":   id,
+				"account": account,
+			}).Debug("skip the party frozen out of tss")
+			continue
+		}
+		ret = append(ret, id)
+	}
+	return ret
+}
+
 func (cbs *ChainBrokerService) getTssInfo() ([]string, *ecdsa.PublicKey, bool, error) {
 	signersALL := make([]string, 0)
 	var poolPkData []byte
@@ -0,0 +1,87 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/meshplus/bitxhub-model/pb"
+	grpcproto "github.com/meshplus/bitxhub/api/grpc/proto"
+	"github.com/meshplus/bitxhub/internal/executor"
+	types2 "github.com/meshplus/eth-kit/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ grpcproto.SimulatorServer = (*ChainBrokerService)(nil)
+
+// SimulateTransaction executes the bxh transaction against a copy of the latest state without submitting it
+func (cbs *ChainBrokerService) SimulateTransaction(_ context.Context, tx *pb.BxhTransaction) (*grpcproto.SimulationResult, error) {
+	if err := cbs.checkTransaction(tx); err != nil {
+		return nil, status.Newf(codes.InvalidArgument, "check transaction fail for %s", err.Error()).Err()
+	}
+
+	return cbs.simulateTransaction(tx)
+}
+
+// SimulateEthTransaction is SimulateTransaction for the signed rlp encoded eth transaction
+func (cbs *ChainBrokerService) SimulateEthTransaction(_ context.Context, req *grpcproto.SimulateEthTransactionRequest) (*grpcproto.SimulationResult, error) {
+	// Unmarshal reads the size from the first 8 bytes
+	if len(req.RawTx) < 8 {
+		return nil, status.New(codes.InvalidArgument, "eth transaction is too short").Err()
+	}
+
+	tx := &types2.EthTransaction{}
+	if err := tx.Unmarshal(req.RawTx); err != nil {
+		return nil, status.Newf(codes.InvalidArgument, "unmarshal eth transaction fail for %s", err.Error()).Err()
+	}
+
+	if tx.GetFrom() == nil {
+		return nil, status.New(codes.InvalidArgument, "verify signature failed").Err()
+	}
+
+	return cbs.simulateTransaction(tx)
+}
+
+func (cbs *ChainBrokerService) simulateTransaction(tx pb.Transaction) (*grpcproto.SimulationResult, error) {
+	result, err := cbs.api.Broker().SimulateTransaction(tx)
+	if err != nil {
+		return nil, fmt.Errorf("simulate transaction %s failed: %w", tx.GetHash().String(), err)
+	}
+
+	ret := &grpcproto.SimulationResult{
+		Receipt:   result.Receipt,
+		Events:    result.Events,
+		GasUsed:   result.GasUsed,
+		StateDiff: make([]*grpcproto.AccountDiff, 0, len(result.StateDiff)),
+	}
+	for _, diff := range result.StateDiff {
+		ret.StateDiff = append(ret.StateDiff, toAccountDiff(diff))
+	}
+
+	return ret, nil
+}
+
+func toAccountDiff(diff *executor.AccountDiff) *grpcproto.AccountDiff {
+	ret := &grpcproto.AccountDiff{
+		Address: diff.Address,
+		Storage: make([]*grpcproto.StorageDiff, 0, len(diff.Storage)),
+	}
+	if diff.Balance != nil {
+		ret.Balance = &grpcproto.BalanceDiff{From: diff.Balance.From, To: diff.Balance.To}
+	}
+	if diff.Nonce != nil {
+		ret.Nonce = &grpcproto.NonceDiff{From: diff.Nonce.From, To: diff.Nonce.To}
+	}
+	if diff.Code != nil {
+		ret.Code = &grpcproto.CodeDiff{From: diff.Code.From, To: diff.Code.To}
+	}
+	for _, storage := range diff.Storage {
+		ret.Storage = append(ret.Storage, &grpcproto.StorageDiff{
+			Key:  storage.Key,
+			From: storage.From,
+			To:   storage.To,
+		})
+	}
+
+	return ret
+}
@@ -17,12 +17,15 @@ func (cbs *ChainBrokerService) SubscribeAuditInfo(req *pb.AuditSubscriptionReque
 
 func (cbs *ChainBrokerService) handleAuditNodeSubscription(server pb.ChainBroker_SubscribeServer, auditNodeID string, blockStart uint64) error {
 	dataCh := make(chan *pb.AuditTxInfo)
+	errCh := make(chan error, 1)
 
 	go func() {
 		err := cbs.api.Audit().HandleAuditNodeSubscription(dataCh, auditNodeID, blockStart)
 		if err != nil {
 			cbs.logger.WithField("auditNodeID", auditNodeID).Errorf("Handle audit node subscription: %v", err)
 		}
+		errCh <- err
+		close(dataCh)
 	}()
 
 	for auditTxInfo := range dataCh {
@@ -34,5 +37,10 @@ func (cbs *ChainBrokerService) handleAuditNodeSubscription(server pb.ChainBroker
 		}
 	}
 
+	// the subscription is closed when the audit node is not available any more
+	if err := <-errCh; err != nil {
+		return fmt.Errorf("audit node subscription closed: %w", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,44 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	grpcproto "github.com/meshplus/bitxhub/api/grpc/proto"
+)
+
+var _ grpcproto.TssAuditServer = (*ChainBrokerService)(nil)
+
+func (cbs *ChainBrokerService) GetTssSignAuditRecords(ctx context.Context, req *grpcproto.GetTssSignAuditRecordsRequest) (*grpcproto.TssSignAuditRecords, error) {
+	records, err := cbs.api.Broker().GetTssSignAuditRecords(req.Start, req.End)
+	if err != nil {
+		return nil, fmt.Errorf("get tss sign audit records between %d and %d failed: %w", req.Start, req.End, err)
+	}
+
+	ret := &grpcproto.TssSignAuditRecords{
+		Records: make([]*grpcproto.TssSignAuditRecord, 0, len(records)),
+	}
+	for _, record := range records {
+		ret.Records = append(ret.Records, &grpcproto.TssSignAuditRecord{
+			Index:     record.Index,
+			MsgId:     record.MsgID,
+			Messages:  record.Messages,
+			Signers:   record.Signers,
+			Culprits:  record.Culprits,
+			Success:   record.Success,
+			Error:     record.Error,
+			Timestamp: record.Timestamp,
+		})
+	}
+
+	return ret, nil
+}
+
+func (cbs *ChainBrokerService) GetTssCulpritCounts(ctx context.Context, req *grpcproto.GetTssCulpritCountsRequest) (*grpcproto.TssCulpritCounts, error) {
+	counts, err := cbs.api.Broker().GetTssCulpritCounts()
+	if err != nil {
+		return nil, fmt.Errorf("get tss culprit counts failed: %w", err)
+	}
+
+	return &grpcproto.TssCulpritCounts{Counts: counts}, nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/meshplus/bitxhub/api/jsonrpc/namespaces/bns"
+	"github.com/meshplus/bitxhub/api/jsonrpc/namespaces/bxh"
 	"github.com/meshplus/bitxhub/api/jsonrpc/namespaces/eth"
 	"github.com/meshplus/bitxhub/api/jsonrpc/namespaces/eth/filters"
 	"github.com/meshplus/bitxhub/api/jsonrpc/namespaces/net"
@@ -18,6 +20,8 @@ const (
 	Web3Namespace = "web3"
 	EthNamespace  = "eth"
 	NetNamespace  = "net"
+	BnsNamespace  = "bns"
+	BxhNamespace  = "bxh"
 
 	apiVersion = "1.0"
 )
@@ -67,5 +71,23 @@ func GetAPIs(config *repo.Config, api api.CoreAPI, logger logrus.FieldLogger) ([
 		},
 	)
 
+	apis = append(apis,
+		rpc.API{
+			Namespace: BnsNamespace,
+			Version:   apiVersion,
+			Service:   bns.NewAPI(api, logger),
+			Public:    true,
+		},
+	)
+
+	apis = append(apis,
+		rpc.API{
+			Namespace: BxhNamespace,
+			Version:   apiVersion,
+			Service:   bxh.NewAPI(config, api, logger),
+			Public:    true,
+		},
+	)
+
 	return apis, nil
 }
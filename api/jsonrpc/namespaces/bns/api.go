@@ -0,0 +1,35 @@
+package bns
+
+import (
+	"github.com/meshplus/bitxhub/internal/coreapi/api"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
+	"github.com/sirupsen/logrus"
+)
+
+// PublicBnsAPI is the bns_ prefixed set of APIs resolving BitXHub name service domains.
+type PublicBnsAPI struct {
+	api    api.CoreAPI
+	logger logrus.FieldLogger
+}
+
+// NewAPI creates an instance of the BNS API.
+func NewAPI(api api.CoreAPI, logger logrus.FieldLogger) *PublicBnsAPI {
+	return &PublicBnsAPI{
+		api:    api,
+		logger: logger,
+	}
+}
+
+// Resolve returns the owner, service id and address a domain points to at the current height.
+func (b *PublicBnsAPI) Resolve(name string) (*contracts.ResolvedDomain, error) {
+	b.logger.Debugf("bns_resolve, name: %s", name)
+
+	return b.api.Broker().ResolveName(name)
+}
+
+// GetOwnershipHistory returns the registrations and transfers of a domain, oldest first.
+func (b *PublicBnsAPI) GetOwnershipHistory(name string) ([]*contracts.OwnershipEvent, error) {
+	b.logger.Debugf("bns_getOwnershipHistory, name: %s", name)
+
+	return b.api.Broker().GetOwnershipHistory(name)
+}
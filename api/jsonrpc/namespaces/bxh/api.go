@@ -0,0 +1,82 @@
+package bxh
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/coreapi/api"
+	"github.com/meshplus/bitxhub/internal/executor"
+	"github.com/meshplus/bitxhub/internal/repo"
+	types2 "github.com/meshplus/eth-kit/types"
+	"github.com/sirupsen/logrus"
+)
+
+// PublicBxhAPI is the bxh_ prefixed set of APIs for the BitXHub specific features.
+type PublicBxhAPI struct {
+	config *repo.Config
+	api    api.CoreAPI
+	logger logrus.FieldLogger
+}
+
+// NewAPI creates an instance of the BitXHub API.
+func NewAPI(config *repo.Config, api api.CoreAPI, logger logrus.FieldLogger) *PublicBxhAPI {
+	return &PublicBxhAPI{
+		config: config,
+		api:    api,
+		logger: logger,
+	}
+}
+
+// SimulateTransaction executes an EVM call against a copy of the latest state as eth_call does,
+// and returns the receipt, events, gas and state diff.
+func (b *PublicBxhAPI) SimulateTransaction(args types2.CallArgs) (*executor.SimulationResult, error) {
+	b.logger.Debugf("bxh_simulateTransaction, args: %v", args)
+
+	if args.Gas == nil || uint64(*args.Gas) < params.TxGas {
+		args.Gas = (*hexutil.Uint64)(&b.config.GasLimit)
+	}
+
+	tx := &types2.EthTransaction{}
+	tx.FromCallArgs(args)
+
+	return b.api.Broker().SimulateTransaction(tx)
+}
+
+// SimulateRawTransaction simulates a signed EVM transaction in the format of eth_sendRawTransaction.
+func (b *PublicBxhAPI) SimulateRawTransaction(data hexutil.Bytes) (*executor.SimulationResult, error) {
+	b.logger.Debugf("bxh_simulateRawTransaction")
+
+	// Unmarshal reads the size from the first 8 bytes
+	if len(data) < 8 {
+		return nil, fmt.Errorf("eth transaction is too short")
+	}
+
+	tx := &types2.EthTransaction{}
+	if err := tx.Unmarshal(data); err != nil {
+		return nil, err
+	}
+
+	if tx.GetFrom() == nil {
+		return nil, fmt.Errorf("verify signature failed")
+	}
+
+	return b.api.Broker().SimulateTransaction(tx)
+}
+
+// SimulateBxhTransaction simulates a signed BoltVM, WASM or transfer transaction.
+func (b *PublicBxhAPI) SimulateBxhTransaction(tx *pb.BxhTransaction) (*executor.SimulationResult, error) {
+	b.logger.Debugf("bxh_simulateBxhTransaction")
+
+	if tx == nil || tx.From == nil || tx.To == nil {
+		return nil, fmt.Errorf("tx from and to address can't be nil")
+	}
+
+	if err := tx.VerifySignature(); err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	tx.TransactionHash = tx.Hash()
+
+	return b.api.Broker().SimulateTransaction(tx)
+}
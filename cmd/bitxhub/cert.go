@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/asn1"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
@@ -21,6 +22,7 @@ import (
 	"github.com/meshplus/bitxhub-kit/crypto/asym"
 	"github.com/meshplus/bitxhub/internal/repo"
 	libp2pcert "github.com/meshplus/go-libp2p-cert"
+	"github.com/spf13/viper"
 	"github.com/urfave/cli"
 )
 
@@ -34,13 +36,26 @@ var certCMD = cli.Command{
 		parseCMD,
 		privCMD,
 		verifyCMD,
+		rotateCMD,
+		crlCMD,
 	},
 }
 
 var caCMD = cli.Command{
 	Name:  "ca",
 	Usage: "Generate ca cert and private key",
+	Flags: []cli.Flag{
+		algoFlag,
+	},
 	Action: func(ctx *cli.Context) error {
+		opt, err := certKeyType(ctx)
+		if err != nil {
+			return err
+		}
+		if opt == crypto.SM2 {
+			return generateSM2CA()
+		}
+
 		privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 		if err != nil {
 			return fmt.Errorf("generate key failed: %w", err)
@@ -88,7 +103,7 @@ var csrCMD = cli.Command{
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:     "key",
-			Usage:    "Specify Secp256r1 private key path",
+			Usage:    "Specify secp256r1 or SM2 private key path",
 			Required: true,
 		},
 		cli.StringFlag{
@@ -111,27 +126,42 @@ var csrCMD = cli.Command{
 			return fmt.Errorf("read private key error: %w", err)
 		}
 		block, _ := pem.Decode(privData)
-		privKey, err := x509.ParseECPrivateKey(block.Bytes)
-		if err != nil {
-			return fmt.Errorf("Error occurred when parsing private key. Please make sure it's secp256r1 private key.")
-		}
-
-		template := &x509.CertificateRequest{
-			Subject: pkix.Name{
-				Country:            []string{"CN"},
-				Locality:           []string{"HangZhou"},
-				Province:           []string{"ZheJiang"},
-				OrganizationalUnit: []string{"BitXHub"},
-				Organization:       []string{org},
-				StreetAddress:      []string{"street", "address"},
-				PostalCode:         []string{"324000"},
-				CommonName:         "BitXHub",
-			},
-			DNSNames: []string{"BitXHub"},
-		}
-		data, err := x509.CreateCertificateRequest(rand.Reader, template, privKey)
-		if err != nil {
-			return fmt.Errorf("create certificate request failed: %w", err)
+		if block == nil {
+			return fmt.Errorf("no pem data in %s", privPath)
+		}
+
+		subject := pkix.Name{
+			Country:            []string{"CN"},
+			Locality:           []string{"HangZhou"},
+			Province:           []string{"ZheJiang"},
+			OrganizationalUnit: []string{"BitXHub"},
+			Organization:       []string{org},
+			StreetAddress:      []string{"street", "address"},
+			PostalCode:         []string{"324000"},
+			CommonName:         "BitXHub",
+		}
+		dnsNames := []string{"BitXHub"}
+
+		var data []byte
+		if sm2Key, err := parseSM2PrivateKey(block); err == nil {
+			data, err = createSM2CSR(sm2Key, subject, dnsNames)
+			if err != nil {
+				return fmt.Errorf("create certificate request failed: %w", err)
+			}
+		} else {
+			privKey, err := x509.ParseECPrivateKey(block.Bytes)
+			if err != nil {
+				return fmt.Errorf("Error occurred when parsing private key. Please make sure it's secp256r1 or SM2 private key.")
+			}
+
+			template := &x509.CertificateRequest{
+				Subject:  subject,
+				DNSNames: dnsNames,
+			}
+			data, err = x509.CreateCertificateRequest(rand.Reader, template, privKey)
+			if err != nil {
+				return fmt.Errorf("create certificate request failed: %w", err)
+			}
 		}
 
 		name := getFileName(privPath)
@@ -162,7 +192,7 @@ var issueCMD = cli.Command{
 		},
 		cli.StringFlag{
 			Name:     "key",
-			Usage:    "Specify ca's secp256r1 private key path",
+			Usage:    "Specify ca's secp256r1 or SM2 private key path, the csr should be signed by key of the same algorithm",
 			Required: true,
 		},
 		cli.StringFlag{
@@ -187,65 +217,83 @@ var issueCMD = cli.Command{
 			return fmt.Errorf("read ca private key: %w", err)
 		}
 		block, _ := pem.Decode(privData)
-		privKey, err := x509.ParseECPrivateKey(block.Bytes)
-		if err != nil {
-			return fmt.Errorf("Error occurred when parsing private key. Please make sure it's secp256r1 private key.")
+		if block == nil {
+			return fmt.Errorf("no pem data in %s", privPath)
 		}
 
 		caCertData, err := ioutil.ReadFile(certPath)
 		if err != nil {
 			return fmt.Errorf("read ca cert error: %w", err)
 		}
-		block, _ = pem.Decode(caCertData)
-		caCert, err := x509.ParseCertificate(block.Bytes)
-		if err != nil {
-			return fmt.Errorf("parse ca cert: %w", err)
+		caBlock, _ := pem.Decode(caCertData)
+		if caBlock == nil {
+			return fmt.Errorf("no pem data in %s", certPath)
 		}
 
 		csrData, err := ioutil.ReadFile(csrPath)
 		if err != nil {
 			return fmt.Errorf("read csr: %w", err)
 		}
-
-		block, _ = pem.Decode(csrData)
-
-		csr, err := x509.ParseCertificateRequest(block.Bytes)
-		if err != nil {
-			return fmt.Errorf("parse csr: %w", err)
-		}
-
-		if err := csr.CheckSignature(); err != nil {
-			return fmt.Errorf("wrong csr sign: %w", err)
-		}
-
-		sn, err := rand.Int(rand.Reader, big.NewInt(1000000))
-		if err != nil {
-			return fmt.Errorf("generate rand number failed: %w", err)
-		}
-
-		notBefore := time.Now().Add(-5 * time.Minute).UTC()
-		template := &x509.Certificate{
-			Signature:             csr.Signature,
-			SignatureAlgorithm:    csr.SignatureAlgorithm,
-			PublicKey:             csr.PublicKey,
-			PublicKeyAlgorithm:    csr.PublicKeyAlgorithm,
-			SerialNumber:          sn,
-			NotBefore:             notBefore,
-			NotAfter:              notBefore.Add(50 * 365 * 24 * time.Hour).UTC(),
-			BasicConstraintsValid: true,
-			IsCA:                  isCA,
-			Issuer:                caCert.Subject,
-			KeyUsage: x509.KeyUsageDigitalSignature |
-				x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign |
-				x509.KeyUsageCRLSign,
-			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
-			Subject:     csr.Subject,
-			DNSNames:    csr.DNSNames,
-		}
-
-		x509certEncode, err := x509.CreateCertificate(rand.Reader, template, caCert, csr.PublicKey, privKey)
-		if err != nil {
-			return fmt.Errorf("create cert: %w", err)
+		csrBlock, _ := pem.Decode(csrData)
+		if csrBlock == nil {
+			return fmt.Errorf("no pem data in %s", csrPath)
+		}
+
+		var x509certEncode []byte
+		if sm2Key, err := parseSM2PrivateKey(block); err == nil {
+			x509certEncode, err = issueSM2Cert(csrBlock.Bytes, sm2Key, caBlock.Bytes, isCA)
+			if err != nil {
+				return err
+			}
+		} else {
+			privKey, err := x509.ParseECPrivateKey(block.Bytes)
+			if err != nil {
+				return fmt.Errorf("Error occurred when parsing private key. Please make sure it's secp256r1 or SM2 private key.")
+			}
+
+			caCert, err := x509.ParseCertificate(caBlock.Bytes)
+			if err != nil {
+				return fmt.Errorf("parse ca cert: %w", err)
+			}
+
+			csr, err := x509.ParseCertificateRequest(csrBlock.Bytes)
+			if err != nil {
+				return fmt.Errorf("parse csr: %w", err)
+			}
+
+			if err := csr.CheckSignature(); err != nil {
+				return fmt.Errorf("wrong csr sign: %w", err)
+			}
+
+			sn, err := rand.Int(rand.Reader, big.NewInt(1000000))
+			if err != nil {
+				return fmt.Errorf("generate rand number failed: %w", err)
+			}
+
+			notBefore := time.Now().Add(-5 * time.Minute).UTC()
+			template := &x509.Certificate{
+				Signature:             csr.Signature,
+				SignatureAlgorithm:    csr.SignatureAlgorithm,
+				PublicKey:             csr.PublicKey,
+				PublicKeyAlgorithm:    csr.PublicKeyAlgorithm,
+				SerialNumber:          sn,
+				NotBefore:             notBefore,
+				NotAfter:              notBefore.Add(50 * 365 * 24 * time.Hour).UTC(),
+				BasicConstraintsValid: true,
+				IsCA:                  isCA,
+				Issuer:                caCert.Subject,
+				KeyUsage: x509.KeyUsageDigitalSignature |
+					x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign |
+					x509.KeyUsageCRLSign,
+				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+				Subject:     csr.Subject,
+				DNSNames:    csr.DNSNames,
+			}
+
+			x509certEncode, err = x509.CreateCertificate(rand.Reader, template, caCert, csr.PublicKey, privKey)
+			if err != nil {
+				return fmt.Errorf("create cert: %w", err)
+			}
 		}
 		name := ctx.String("target_name")
 		if strings.EqualFold("", name) {
@@ -281,9 +329,18 @@ var parseCMD = cli.Command{
 			return fmt.Errorf("read certificate error: %w", err)
 		}
 		block, _ := pem.Decode(data)
+		if block == nil {
+			return fmt.Errorf("no pem data in %s", path)
+		}
 		cert, err := x509.ParseCertificate(block.Bytes)
 		if err != nil {
-			return fmt.Errorf("parse cert failed: %w", err)
+			// certifications of SM2 are not supported by the standard x509
+			ret, err := parseSM2Cert(block.Bytes)
+			if err != nil {
+				return err
+			}
+			fmt.Println(ret)
+			return nil
 		}
 
 		ret, err := json.Marshal(cert)
@@ -314,9 +371,14 @@ var privCMD = cli.Command{
 					Name:  "target",
 					Usage: "Specify target directory",
 				},
+				algoFlag,
 			},
 			Action: func(ctx *cli.Context) error {
-				return generatePrivKey(ctx, crypto.ECDSA_P256)
+				opt, err := certKeyType(ctx)
+				if err != nil {
+					return err
+				}
+				return generatePrivKey(ctx, opt)
 			},
 		},
 		{
@@ -367,18 +429,26 @@ var verifyCMD = cli.Command{
 		if err != nil {
 			return fmt.Errorf("read sub cert error: %w", err)
 		}
-		block, _ := pem.Decode(subCertData)
-		subCert, err := x509.ParseCertificate(block.Bytes)
-		if err != nil {
-			return fmt.Errorf("parse sub cert: %w", err)
+		subBlock, _ := pem.Decode(subCertData)
+		if subBlock == nil {
+			return fmt.Errorf("no pem data in %s", subPath)
 		}
 
 		caCertData, err := ioutil.ReadFile(caPath)
 		if err != nil {
 			return fmt.Errorf("read ca cert error: %w", err)
 		}
-		block, _ = pem.Decode(caCertData)
-		caCert, err := x509.ParseCertificate(block.Bytes)
+		caBlock, _ := pem.Decode(caCertData)
+		if caBlock == nil {
+			return fmt.Errorf("no pem data in %s", caPath)
+		}
+
+		subCert, err := x509.ParseCertificate(subBlock.Bytes)
+		if err != nil {
+			// certifications of SM2 are not supported by the standard x509
+			return verifySM2Cert(subBlock.Bytes, caBlock.Bytes)
+		}
+		caCert, err := x509.ParseCertificate(caBlock.Bytes)
 		if err != nil {
 			return fmt.Errorf("parse ca cert: %w", err)
 		}
@@ -387,6 +457,247 @@ var verifyCMD = cli.Command{
 	},
 }
 
+var rotateCMD = cli.Command{
+	Name:  "rotate",
+	Usage: "Issue a new node certification for the node key by agency, which is hot reloaded by the running node",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:     "key",
+			Usage:    "Specify agency's secp256r1 private key path",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     "cert",
+			Usage:    "Specify agency certification path",
+			Required: true,
+		},
+		cli.IntFlag{
+			Name:  "days",
+			Usage: "Specify days the new certification is valid for",
+			Value: 365,
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		repoRoot, err := repo.PathRootWithDefault(ctx.GlobalString("repo"))
+		if err != nil {
+			return fmt.Errorf("get repo path: %w", err)
+		}
+		config, err := repo.UnmarshalConfig(viper.New(), repoRoot, "")
+		if err != nil {
+			return fmt.Errorf("load bitxhub config: %w", err)
+		}
+
+		agencyKey, err := readECPrivateKey(ctx.String("key"))
+		if err != nil {
+			return fmt.Errorf("read agency private key: %w", err)
+		}
+		agencyCert, err := readCert(ctx.String("cert"))
+		if err != nil {
+			return fmt.Errorf("read agency cert: %w", err)
+		}
+		certs, err := libp2pcert.LoadCerts(repoRoot, config.NodeCertPath, config.AgencyCertPath, config.CACertPath)
+		if err != nil {
+			return fmt.Errorf("load certs of node: %w", err)
+		}
+		if !agencyCert.Equal(certs.AgencyCert) {
+			return fmt.Errorf("node certification should be issued by the agency in %s", config.AgencyCertPath)
+		}
+
+		// the node key is the libp2p identity of the node, which is kept so that the pid doesn't change
+		nodeKeyPath := filepath.Join(repoRoot, "certs/node.priv")
+		nodeKeyData, err := ioutil.ReadFile(nodeKeyPath)
+		if err != nil {
+			return fmt.Errorf("read %s error: %w", nodeKeyPath, err)
+		}
+		nodeKey, err := libp2pcert.ParsePrivateKey(nodeKeyData, crypto.ECDSA_P256)
+		if err != nil {
+			return fmt.Errorf("parse node private key: %w", err)
+		}
+
+		sn, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+		if err != nil {
+			return fmt.Errorf("generate rand number failed: %w", err)
+		}
+		notBefore := time.Now().Add(-5 * time.Minute).UTC()
+		template := &x509.Certificate{
+			SerialNumber:          sn,
+			NotBefore:             notBefore,
+			NotAfter:              notBefore.Add(time.Duration(ctx.Int("days")) * 24 * time.Hour).UTC(),
+			BasicConstraintsValid: true,
+			Issuer:                agencyCert.Subject,
+			KeyUsage:              certs.NodeCert.KeyUsage,
+			ExtKeyUsage:           certs.NodeCert.ExtKeyUsage,
+			Subject:               certs.NodeCert.Subject,
+			DNSNames:              certs.NodeCert.DNSNames,
+		}
+		certData, err := x509.CreateCertificate(rand.Reader, template, agencyCert, &nodeKey.K.PublicKey, agencyKey)
+		if err != nil {
+			return fmt.Errorf("create cert: %w", err)
+		}
+
+		// replace the node certification by renaming, so the node never reads a partial file
+		nodeCertPath := filepath.Join(repoRoot, config.NodeCertPath)
+		tmpPath := nodeCertPath + ".tmp"
+		if err := ioutil.WriteFile(tmpPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certData}), 0644); err != nil {
+			return fmt.Errorf("write %s failed: %w", tmpPath, err)
+		}
+		if err := os.Rename(tmpPath, nodeCertPath); err != nil {
+			return fmt.Errorf("replace %s failed: %w", nodeCertPath, err)
+		}
+
+		newCert, err := x509.ParseCertificate(certData)
+		if err != nil {
+			return fmt.Errorf("parse new cert: %w", err)
+		}
+		fmt.Printf("node certification %s is rotated to %s\n", repo.CertFingerprint(certs.NodeCert), repo.CertFingerprint(newCert))
+		return nil
+	},
+}
+
+var crlCMD = cli.Command{
+	Name:  "crl",
+	Usage: "Revoke certifications issued by ca or agency in CRL file",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:     "key",
+			Usage:    "Specify issuer's secp256r1 private key path",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     "cert",
+			Usage:    "Specify issuer certification path",
+			Required: true,
+		},
+		cli.StringSliceFlag{
+			Name:  "revoke",
+			Usage: "Specify path of the certification to revoke",
+		},
+		cli.StringFlag{
+			Name:     "path",
+			Usage:    "Specify CRL file path, CRLs of other issuers in it are kept",
+			Required: true,
+		},
+		cli.IntFlag{
+			Name:  "days",
+			Usage: "Specify days before the next update of CRL",
+			Value: 30,
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		path := ctx.String("path")
+
+		issuerKey, err := readECPrivateKey(ctx.String("key"))
+		if err != nil {
+			return fmt.Errorf("read issuer private key: %w", err)
+		}
+		issuerCert, err := readCert(ctx.String("cert"))
+		if err != nil {
+			return fmt.Errorf("read issuer cert: %w", err)
+		}
+
+		var (
+			others  []*pkix.CertificateList
+			revoked []pkix.RevokedCertificate
+		)
+		if fileutil.Exist(path) {
+			crls, err := repo.LoadCRLs(path)
+			if err != nil {
+				return fmt.Errorf("load crl file: %w", err)
+			}
+			for _, crl := range crls {
+				if issuerCert.CheckCRLSignature(crl) == nil {
+					revoked = append(revoked, crl.TBSCertList.RevokedCertificates...)
+				} else {
+					others = append(others, crl)
+				}
+			}
+		}
+
+		for _, certPath := range ctx.StringSlice("revoke") {
+			cert, err := readCert(certPath)
+			if err != nil {
+				return fmt.Errorf("read cert to revoke: %w", err)
+			}
+			if err := cert.CheckSignatureFrom(issuerCert); err != nil {
+				return fmt.Errorf("%s is not issued by the issuer: %w", certPath, err)
+			}
+			revoked = append(revoked, pkix.RevokedCertificate{
+				SerialNumber:   cert.SerialNumber,
+				RevocationTime: time.Now().UTC(),
+			})
+		}
+
+		now := time.Now().UTC()
+		crlData, err := issuerCert.CreateCRL(rand.Reader, issuerKey, revoked, now, now.Add(time.Duration(ctx.Int("days"))*24*time.Hour))
+		if err != nil {
+			return fmt.Errorf("create crl: %w", err)
+		}
+
+		var data []byte
+		for _, crl := range others {
+			der, err := asn1.Marshal(*crl)
+			if err != nil {
+				return fmt.Errorf("marshal crl: %w", err)
+			}
+			data = append(data, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})...)
+		}
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crlData})...)
+
+		tmpPath := path + ".tmp"
+		if err := ioutil.WriteFile(tmpPath, data, 0644); err != nil {
+			return fmt.Errorf("write %s failed: %w", tmpPath, err)
+		}
+		if err := os.Rename(tmpPath, path); err != nil {
+			return fmt.Errorf("replace %s failed: %w", path, err)
+		}
+
+		fmt.Printf("%d certifications are revoked by %s\n", len(revoked), issuerCert.Subject.CommonName)
+		return nil
+	},
+}
+
+func readECPrivateKey(path string) (*ecdsa.PrivateKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no pem data in %s", path)
+	}
+	privKey, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("Error occurred when parsing private key. Please make sure it's secp256r1 private key.")
+	}
+	return privKey, nil
+}
+
+func readCert(path string) (*x509.Certificate, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return libp2pcert.ParseCert(data)
+}
+
+var algoFlag = cli.StringFlag{
+	Name:  "algo",
+	Usage: "Specify crypto algorithm, ECDSA_P256 or SM2",
+	Value: "ECDSA_P256",
+}
+
+// certKeyType returns the key type of certifications, which are either secp256r1 or SM2
+func certKeyType(ctx *cli.Context) (crypto.KeyType, error) {
+	opt, err := crypto.CryptoNameToType(ctx.String("algo"))
+	if err != nil {
+		return 0, fmt.Errorf("change crypto name to type failed: %w", err)
+	}
+	if opt != crypto.ECDSA_P256 && opt != crypto.SM2 {
+		return 0, fmt.Errorf("unsupport crypto algo for certification: %s", ctx.String("algo"))
+	}
+	return opt, nil
+}
+
 func getFileName(path string) string {
 	def := "default"
 	name := filepath.Base(path)
@@ -429,7 +740,11 @@ func generatePrivKey(ctx *cli.Context, opt crypto.KeyType) error {
 		return fmt.Errorf("create %s failed: %w", path, err)
 	}
 
-	err = pem.Encode(f, &pem.Block{Type: "EC PRIVATE KEY", Bytes: priKeyEncode})
+	blockType := "EC PRIVATE KEY"
+	if opt == crypto.SM2 {
+		blockType = sm2PrivKeyType
+	}
+	err = pem.Encode(f, &pem.Block{Type: blockType, Bytes: priKeyEncode})
 	if err != nil {
 		return fmt.Errorf("pem encode error: %w", err)
 	}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"time"
+
+	"github.com/tjfoc/gmsm/sm2"
+	gmx509 "github.com/tjfoc/gmsm/x509"
+)
+
+// SM2 certifications are signed with SM3 by the x509 implementation of the national cryptography,
+// the keys are PKCS#8 encoded in the PEM blocks of "PRIVATE KEY"
+const sm2PrivKeyType = "PRIVATE KEY"
+
+func generateSM2CA() error {
+	privKey, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("generate key failed: %w", err)
+	}
+
+	privData, err := gmx509.WritePrivateKeyToPem(privKey, nil)
+	if err != nil {
+		return fmt.Errorf("marshal SM2 private key error: %w", err)
+	}
+	if err := ioutil.WriteFile("./ca.priv", privData, 0644); err != nil {
+		return fmt.Errorf("create ./ca.priv failed: %w", err)
+	}
+
+	sn, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return fmt.Errorf("generate rand number failed: %w", err)
+	}
+	keyHash := sha256.Sum256(sm2.Compress(&privKey.PublicKey))
+	notBefore := time.Now().Add(-5 * time.Minute).UTC()
+	template := &gmx509.Certificate{
+		SignatureAlgorithm:    gmx509.SM2WithSM3,
+		SerialNumber:          sn,
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(50 * 365 * 24 * time.Hour).UTC(),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage: gmx509.KeyUsageDigitalSignature |
+			gmx509.KeyUsageKeyEncipherment | gmx509.KeyUsageCertSign |
+			gmx509.KeyUsageCRLSign,
+		ExtKeyUsage: []gmx509.ExtKeyUsage{gmx509.ExtKeyUsageAny},
+		Subject: pkix.Name{
+			Country:            []string{"CN"},
+			Locality:           []string{"HangZhou"},
+			Province:           []string{"ZheJiang"},
+			OrganizationalUnit: []string{"BitXHub"},
+			Organization:       []string{"Hyperchain"},
+			StreetAddress:      []string{"street", "address"},
+			PostalCode:         []string{"324000"},
+			CommonName:         "bitxhub.cn",
+		},
+		SubjectKeyId: keyHash[:],
+	}
+
+	certData, err := gmx509.CreateCertificate(template, template, &privKey.PublicKey, privKey)
+	if err != nil {
+		return fmt.Errorf("create X.509v3 certificate failed: %w", err)
+	}
+
+	return ioutil.WriteFile("./ca.cert", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certData}), 0644)
+}
+
+// parseSM2PrivateKey parses the SM2 private key in the PEM block, it fails for keys of other algorithms
+func parseSM2PrivateKey(block *pem.Block) (*sm2.PrivateKey, error) {
+	if block.Type != sm2PrivKeyType {
+		return nil, fmt.Errorf("not SM2 private key")
+	}
+
+	return gmx509.ParsePKCS8UnecryptedPrivateKey(block.Bytes)
+}
+
+func createSM2CSR(privKey *sm2.PrivateKey, subject pkix.Name, dnsNames []string) ([]byte, error) {
+	template := &gmx509.CertificateRequest{
+		Subject:  subject,
+		DNSNames: dnsNames,
+	}
+
+	return gmx509.CreateCertificateRequest(rand.Reader, template, privKey)
+}
+
+func issueSM2Cert(csrData []byte, caKey *sm2.PrivateKey, caCertData []byte, isCA bool) ([]byte, error) {
+	caCert, err := gmx509.ParseCertificate(caCertData)
+	if err != nil {
+		return nil, fmt.Errorf("parse ca cert: %w", err)
+	}
+
+	csr, err := gmx509.ParseCertificateRequest(csrData)
+	if err != nil {
+		return nil, fmt.Errorf("parse csr: %w", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("wrong csr sign: %w", err)
+	}
+	// SM2 public keys are parsed as ECDSA public keys on the SM2 curve
+	pub, ok := csr.PublicKey.(*ecdsa.PublicKey)
+	if !ok || pub.Curve != sm2.P256Sm2() {
+		return nil, fmt.Errorf("csr of SM2 ca should be signed by SM2 private key")
+	}
+	pubKey := &sm2.PublicKey{Curve: pub.Curve, X: pub.X, Y: pub.Y}
+
+	sn, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return nil, fmt.Errorf("generate rand number failed: %w", err)
+	}
+
+	notBefore := time.Now().Add(-5 * time.Minute).UTC()
+	template := &gmx509.Certificate{
+		SignatureAlgorithm:    gmx509.SM2WithSM3,
+		SerialNumber:          sn,
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(50 * 365 * 24 * time.Hour).UTC(),
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+		Issuer:                caCert.Subject,
+		KeyUsage: gmx509.KeyUsageDigitalSignature |
+			gmx509.KeyUsageKeyEncipherment | gmx509.KeyUsageCertSign |
+			gmx509.KeyUsageCRLSign,
+		ExtKeyUsage: []gmx509.ExtKeyUsage{gmx509.ExtKeyUsageAny},
+		Subject:     csr.Subject,
+		DNSNames:    csr.DNSNames,
+	}
+
+	certData, err := gmx509.CreateCertificate(template, caCert, pubKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("create cert: %w", err)
+	}
+
+	return certData, nil
+}
+
+func parseSM2Cert(certData []byte) (string, error) {
+	cert, err := gmx509.ParseCertificate(certData)
+	if err != nil {
+		return "", fmt.Errorf("parse cert failed: %w", err)
+	}
+
+	ret, err := json.Marshal(cert)
+	if err != nil {
+		return "", fmt.Errorf("marshal cert error: %w", err)
+	}
+
+	return string(ret), nil
+}
+
+func verifySM2Cert(subCertData, caCertData []byte) error {
+	subCert, err := gmx509.ParseCertificate(subCertData)
+	if err != nil {
+		return fmt.Errorf("parse sub cert: %w", err)
+	}
+	caCert, err := gmx509.ParseCertificate(caCertData)
+	if err != nil {
+		return fmt.Errorf("parse ca cert: %w", err)
+	}
+
+	return subCert.CheckSignatureFrom(caCert)
+}
@@ -9,7 +9,6 @@ import (
 	appchainMgr "github.com/meshplus/bitxhub-core/appchain-mgr"
 	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
-	"github.com/tidwall/gjson"
 	"github.com/urfave/cli"
 )
 
@@ -88,16 +87,13 @@ func getChainByName(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get appchain by name %s: %w", name, err)
 	}
 
-	if receipt.IsSuccess() {
-		chain := &appchainMgr.Appchain{}
-		if err := json.Unmarshal(receipt.Ret, chain); err != nil {
-			return fmt.Errorf("unmarshal receipt error: %w", err)
-		}
-		printChain(chain)
-	} else {
-		color.Red("get chain error: %s\n", string(receipt.Ret))
+	chain := &appchainMgr.Appchain{}
+	if err := json.Unmarshal(receipt.Ret, chain); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
 	}
-	return nil
+	return printOutput(ctx, chain, func() {
+		printChain(chain)
+	})
 }
 
 func printChain(chain *appchainMgr.Appchain) {
@@ -125,16 +121,13 @@ func getChainStatusById(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get appchain status by ID %s: %w", id, err)
 	}
 
-	if receipt.IsSuccess() {
-		chain := &appchainMgr.Appchain{}
-		if err := json.Unmarshal(receipt.Ret, chain); err != nil {
-			return fmt.Errorf("unmarshal receipt error: %w", err)
-		}
-		color.Green("appchain %s is %s", chain.ID, string(chain.Status))
-	} else {
-		color.Red("get chain status error: %s\n", string(receipt.Ret))
+	chain := &appchainMgr.Appchain{}
+	if err := json.Unmarshal(receipt.Ret, chain); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
 	}
-	return nil
+	return printOutput(ctx, &statusResult{ID: chain.ID, Status: string(chain.Status)}, func() {
+		color.Green("appchain %s is %s", chain.ID, string(chain.Status))
+	})
 }
 
 func freezeAppchain(ctx *cli.Context) error {
@@ -146,13 +139,7 @@ func freezeAppchain(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when freeze appchain %s for %s: %w", id, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s", proposalId)
-	} else {
-		color.Red("freeze appchain error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printProposalResult(ctx, receipt)
 }
 
 func activateAppchain(ctx *cli.Context) error {
@@ -164,11 +151,5 @@ func activateAppchain(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when activate appchain %s for %s: %w", id, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s", proposalId)
-	} else {
-		color.Red("activate appchain error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printProposalResult(ctx, receipt)
 }
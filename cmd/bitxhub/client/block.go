@@ -41,9 +41,7 @@ func getBlockByHeight(ctx *cli.Context, height uint64) error {
 		return fmt.Errorf("httpGet from url %s failed: %w", url, err)
 	}
 
-	fmt.Println(prettyJson(string(data)))
-
-	return nil
+	return printRawOutput(ctx, data)
 }
 
 func getBlockByHash(ctx *cli.Context, hash string) error {
@@ -53,9 +51,7 @@ func getBlockByHash(ctx *cli.Context, hash string) error {
 		return fmt.Errorf("httpGet from url %s failed: %w", url, err)
 	}
 
-	fmt.Println(prettyJson(string(data)))
-
-	return nil
+	return printRawOutput(ctx, data)
 }
 
 func getLatestBlock(ctx *cli.Context) error {
@@ -65,6 +61,5 @@ func getLatestBlock(ctx *cli.Context) error {
 		return fmt.Errorf("httpGet from url %s failed: %w", url, err)
 	}
 
-	fmt.Println(prettyJson(string(data)))
-	return nil
+	return printRawOutput(ctx, data)
 }
@@ -0,0 +1,526 @@
+package client
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
+	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/urfave/cli"
+)
+
+func bnsCMD() cli.Command {
+	return cli.Command{
+		Name:  "bns",
+		Usage: "BitXHub name service command",
+		Subcommands: cli.Commands{
+			cli.Command{
+				Name:  "resolve",
+				Usage: "Resolve a domain to its owner, service id and address",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "name",
+						Usage:    "Specify domain name, e.g. service.appchain.hub",
+						Required: true,
+					},
+				},
+				Action: resolveDomain,
+			},
+			cli.Command{
+				Name:  "expires",
+				Usage: "Query expiration time of a first-level domain",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "name",
+						Usage:    "Specify domain name, e.g. appchain.hub",
+						Required: true,
+					},
+				},
+				Action: getDomainExpires,
+			},
+			cli.Command{
+				Name:  "transfer",
+				Usage: "Transfer a domain to another owner",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "name",
+						Usage:    "Specify domain name, e.g. appchain.hub",
+						Required: true,
+					},
+					cli.StringFlag{
+						Name:     "to",
+						Usage:    "Specify new owner address",
+						Required: true,
+					},
+				},
+				Action: transferDomain,
+			},
+			cli.Command{
+				Name:  "approve",
+				Usage: "Approve or revoke an operator managing all of your domains",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "operator",
+						Usage:    "Specify operator address",
+						Required: true,
+					},
+					cli.BoolFlag{
+						Name:  "revoke",
+						Usage: "Revoke the approval",
+					},
+				},
+				Action: setApprovalForAll,
+			},
+			cli.Command{
+				Name:  "subOwner",
+				Usage: "Change owner of a subdomain by the parent domain owner",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "parent",
+						Usage:    "Specify parent domain name, e.g. appchain.hub",
+						Required: true,
+					},
+					cli.StringFlag{
+						Name:     "son",
+						Usage:    "Specify subdomain label, e.g. service for service.appchain.hub",
+						Required: true,
+					},
+					cli.StringFlag{
+						Name:     "owner",
+						Usage:    "Specify new owner address",
+						Required: true,
+					},
+				},
+				Action: setSubDomainOwner,
+			},
+			cli.Command{
+				Name:  "history",
+				Usage: "Query ownership history of a domain",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "name",
+						Usage:    "Specify domain name, e.g. appchain.hub",
+						Required: true,
+					},
+				},
+				Action: getOwnershipHistory,
+			},
+			cli.Command{
+				Name:   "grace",
+				Usage:  "Query grace period after expiry",
+				Action: getGracePeriod,
+			},
+			cli.Command{
+				Name:  "auction",
+				Usage: "Name auction command",
+				Subcommands: cli.Commands{
+					cli.Command{
+						Name:   "config",
+						Usage:  "Query auction config",
+						Action: getAuctionConfig,
+					},
+					cli.Command{
+						Name:  "info",
+						Usage: "Query auction of a name",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "name",
+								Usage:    "Specify name without the root domain",
+								Required: true,
+							},
+						},
+						Action: getAuction,
+					},
+					cli.Command{
+						Name:  "start",
+						Usage: "Start auction of a name",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "name",
+								Usage:    "Specify name without the root domain",
+								Required: true,
+							},
+						},
+						Action: startAuction,
+					},
+					cli.Command{
+						Name:  "bid",
+						Usage: "Submit a sealed bid",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "name",
+								Usage:    "Specify name without the root domain",
+								Required: true,
+							},
+							cli.Uint64Flag{
+								Name:     "value",
+								Usage:    "Specify bid value, it is kept secret until reveal",
+								Required: true,
+							},
+							cli.StringFlag{
+								Name:     "salt",
+								Usage:    "Specify salt to seal the bid, it is needed on reveal",
+								Required: true,
+							},
+							cli.Uint64Flag{
+								Name:     "deposit",
+								Usage:    "Specify deposit locked for the bid, it should not be less than the value",
+								Required: true,
+							},
+						},
+						Action: bidAuction,
+					},
+					cli.Command{
+						Name:  "reveal",
+						Usage: "Reveal a sealed bid",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "name",
+								Usage:    "Specify name without the root domain",
+								Required: true,
+							},
+							cli.Uint64Flag{
+								Name:     "value",
+								Usage:    "Specify bid value",
+								Required: true,
+							},
+							cli.StringFlag{
+								Name:     "salt",
+								Usage:    "Specify salt used on bid",
+								Required: true,
+							},
+						},
+						Action: revealAuction,
+					},
+					cli.Command{
+						Name:  "finalize",
+						Usage: "Register the name for the auction winner",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "name",
+								Usage:    "Specify name without the root domain",
+								Required: true,
+							},
+							cli.Uint64Flag{
+								Name:     "duration",
+								Usage:    "Specify registration duration in seconds",
+								Required: true,
+							},
+							cli.StringFlag{
+								Name:  "resolver",
+								Usage: "Specify resolver contract address",
+								Value: constant.ServiceResolverContractAddr.Address().String(),
+							},
+						},
+						Action: finalizeAuction,
+					},
+				},
+			},
+		},
+	}
+}
+
+// domainExpiresResult is the output of querying domain expires
+type domainExpiresResult struct {
+	Name    string `json:"name"`
+	Expires uint64 `json:"expires"`
+}
+
+// gracePeriodResult is the output of querying grace period in seconds
+type gracePeriodResult struct {
+	GracePeriod uint64 `json:"grace_period"`
+}
+
+func resolveDomain(ctx *cli.Context) error {
+	resolved, err := resolveBnsName(ctx, ctx.String("name"))
+	if err != nil {
+		return err
+	}
+
+	return printOutput(ctx, resolved, func() {
+		var table [][]string
+		table = append(table, []string{"Name", "Owner", "ServiceID", "Addr", "Expires"})
+		table = append(table, []string{
+			resolved.Name,
+			resolved.Owner,
+			resolved.ServiceID,
+			resolved.Addr,
+			strconv.FormatUint(resolved.Expires, 10),
+		})
+		PrintTable(table, true)
+	})
+}
+
+func getDomainExpires(ctx *cli.Context) error {
+	name := ctx.String("name")
+
+	receipt, err := invokeBVMContractBySendView(ctx, constant.ServiceRegistryContractAddr.Address().String(), "GetDomainExpires", pb.String(name))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get expires of %s: %w", name, err)
+	}
+	result := &domainExpiresResult{Name: name, Expires: binary.BigEndian.Uint64(receipt.Ret)}
+	return printOutput(ctx, result, func() {
+		color.Green("domain %s expires at %d\n", result.Name, result.Expires)
+	})
+}
+
+func transferDomain(ctx *cli.Context) error {
+	name := ctx.String("name")
+	to := ctx.String("to")
+
+	receipt, err := invokeBVMContract(ctx, constant.ServiceRegistryContractAddr.Address().String(), "Transfer", pb.String(name), pb.String(to))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when transfer %s to %s: %w", name, to, err)
+	}
+	return printTxResult(ctx, receipt, "%s success\n", fmt.Sprintf("transfer %s to %s", name, to))
+}
+
+func setApprovalForAll(ctx *cli.Context) error {
+	operator := ctx.String("operator")
+	approved := !ctx.Bool("revoke")
+
+	receipt, err := invokeBVMContract(ctx, constant.ServiceRegistryContractAddr.Address().String(), "SetApprovalForAll", pb.String(operator), pb.Bool(approved))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when set approval for %s: %w", operator, err)
+	}
+	return printTxResult(ctx, receipt, "%s success\n", fmt.Sprintf("set approval %t for %s", approved, operator))
+}
+
+func setSubDomainOwner(ctx *cli.Context) error {
+	parent := ctx.String("parent")
+	son := ctx.String("son")
+	owner := ctx.String("owner")
+
+	receipt, err := invokeBVMContract(ctx, constant.ServiceRegistryContractAddr.Address().String(), "SetSubDomainOwner",
+		pb.String(parent), pb.String(son), pb.String(owner))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when set owner of %s.%s: %w", son, parent, err)
+	}
+	return printTxResult(ctx, receipt, "%s success\n", fmt.Sprintf("set owner of %s.%s to %s", son, parent, owner))
+}
+
+func getOwnershipHistory(ctx *cli.Context) error {
+	name := ctx.String("name")
+
+	receipt, err := invokeBVMContractBySendView(ctx, constant.ServiceRegistryContractAddr.Address().String(), "GetOwnershipHistory", pb.String(name))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get ownership history of %s: %w", name, err)
+	}
+
+	var history []*contracts.OwnershipEvent
+	if err := json.Unmarshal(receipt.Ret, &history); err != nil {
+		return fmt.Errorf("unmarshal ownership history error: %w", err)
+	}
+	return printOutput(ctx, history, func() {
+		var table [][]string
+		table = append(table, []string{"Type", "From", "To", "Timestamp"})
+		for _, event := range history {
+			table = append(table, []string{
+				event.Type,
+				event.From,
+				event.To,
+				strconv.FormatUint(event.Timestamp, 10),
+			})
+		}
+		PrintTable(table, true)
+	})
+}
+
+func getGracePeriod(ctx *cli.Context) error {
+	receipt, err := invokeBVMContractBySendView(ctx, constant.ServiceRegistryContractAddr.Address().String(), "GetGracePeriod")
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get grace period: %w", err)
+	}
+	result := &gracePeriodResult{GracePeriod: binary.BigEndian.Uint64(receipt.Ret)}
+	return printOutput(ctx, result, func() {
+		color.Green("grace period is %d seconds\n", result.GracePeriod)
+	})
+}
+
+func getAuctionConfig(ctx *cli.Context) error {
+	receipt, err := invokeBVMContractBySendView(ctx, constant.ServiceRegistryContractAddr.Address().String(), "GetAuctionConfig")
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get auction config: %w", err)
+	}
+
+	config := &contracts.AuctionConfig{}
+	if err := json.Unmarshal(receipt.Ret, config); err != nil {
+		return fmt.Errorf("unmarshal auction config error: %w", err)
+	}
+	return printOutput(ctx, config, func() {
+		if config.MaxNameLen == 0 {
+			color.Green("name auction is disabled\n")
+			return
+		}
+		var table [][]string
+		table = append(table, []string{"MaxNameLen", "BiddingPeriod", "RevealPeriod"})
+		table = append(table, []string{
+			strconv.FormatUint(config.MaxNameLen, 10),
+			strconv.FormatUint(config.BiddingPeriod, 10),
+			strconv.FormatUint(config.RevealPeriod, 10),
+		})
+		PrintTable(table, true)
+	})
+}
+
+func getAuction(ctx *cli.Context) error {
+	name := ctx.String("name")
+
+	receipt, err := invokeBVMContractBySendView(ctx, constant.ServiceRegistryContractAddr.Address().String(), "GetAuction", pb.String(name))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get auction of %s: %w", name, err)
+	}
+
+	auction := &contracts.NameAuction{}
+	if err := json.Unmarshal(receipt.Ret, auction); err != nil {
+		return fmt.Errorf("unmarshal auction error: %w", err)
+	}
+	return printOutput(ctx, auction, func() {
+		color.Green("auction of %s is %s, bidding ends at %d, reveal ends at %d\n", auction.Name, auction.Status, auction.BiddingEnd, auction.RevealEnd)
+		if auction.HighestBidder != "" {
+			color.Green("highest bidder is %s with %d, second bid is %d\n", auction.HighestBidder, auction.HighestBid, auction.SecondBid)
+		}
+
+		bidders := make([]string, 0, len(auction.Bids))
+		for bidder := range auction.Bids {
+			bidders = append(bidders, bidder)
+		}
+		sort.Strings(bidders)
+		var table [][]string
+		table = append(table, []string{"Bidder", "Deposit", "Revealed", "Value"})
+		for _, bidder := range bidders {
+			bid := auction.Bids[bidder]
+			table = append(table, []string{
+				bidder,
+				strconv.FormatUint(bid.Deposit, 10),
+				strconv.FormatBool(bid.Revealed),
+				strconv.FormatUint(bid.Value, 10),
+			})
+		}
+		PrintTable(table, true)
+	})
+}
+
+func startAuction(ctx *cli.Context) error {
+	name := ctx.String("name")
+
+	receipt, err := invokeBVMContract(ctx, constant.ServiceRegistryContractAddr.Address().String(), "StartAuction", pb.String(name))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when start auction of %s: %w", name, err)
+	}
+	return printTxResult(ctx, receipt, "%s success\n", fmt.Sprintf("start auction of %s", name))
+}
+
+func bidAuction(ctx *cli.Context) error {
+	name := ctx.String("name")
+	value := ctx.Uint64("value")
+	salt := ctx.String("salt")
+	deposit := ctx.Uint64("deposit")
+
+	bidder, err := getKeyAddress(ctx)
+	if err != nil {
+		return err
+	}
+	sealedHash := contracts.SealedBidHash(name, bidder, value, salt)
+	receipt, err := invokeBVMContract(ctx, constant.ServiceRegistryContractAddr.Address().String(), "Bid",
+		pb.String(name), pb.String(sealedHash), pb.Uint64(deposit))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when bid for %s: %w", name, err)
+	}
+	return printTxResult(ctx, receipt, "%s success\n", fmt.Sprintf("bid for %s", name))
+}
+
+func revealAuction(ctx *cli.Context) error {
+	name := ctx.String("name")
+	value := ctx.Uint64("value")
+	salt := ctx.String("salt")
+
+	receipt, err := invokeBVMContract(ctx, constant.ServiceRegistryContractAddr.Address().String(), "Reveal",
+		pb.String(name), pb.Uint64(value), pb.String(salt))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when reveal bid for %s: %w", name, err)
+	}
+	return printTxResult(ctx, receipt, "%s success\n", fmt.Sprintf("reveal bid for %s", name))
+}
+
+func finalizeAuction(ctx *cli.Context) error {
+	name := ctx.String("name")
+	duration := ctx.Uint64("duration")
+	resolver := ctx.String("resolver")
+
+	receipt, err := invokeBVMContract(ctx, constant.ServiceRegistryContractAddr.Address().String(), "FinalizeAuction",
+		pb.String(name), pb.Uint64(duration), pb.String(resolver))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when finalize auction of %s: %w", name, err)
+	}
+	return printTxResult(ctx, receipt, "%s success\n", fmt.Sprintf("finalize auction of %s", name))
+}
+
+func resolveBnsName(ctx *cli.Context, name string) (*contracts.ResolvedDomain, error) {
+	receipt, err := invokeBVMContractBySendView(ctx, constant.ServiceResolverContractAddr.Address().String(), "Resolve", pb.String(name))
+	if err != nil {
+		return nil, fmt.Errorf("resolve domain %s: %w", name, err)
+	}
+
+	resolved := &contracts.ResolvedDomain{}
+	if err := json.Unmarshal(receipt.Ret, resolved); err != nil {
+		return nil, fmt.Errorf("unmarshal resolved domain error: %w", err)
+	}
+	return resolved, nil
+}
+
+// resolveAddress returns the address a domain points to, other input is returned as it is
+func resolveAddress(ctx *cli.Context, addr string) (string, error) {
+	if !contracts.IsBnsName(addr) {
+		return addr, nil
+	}
+	resolved, err := resolveBnsName(ctx, addr)
+	if err != nil {
+		return "", err
+	}
+	if resolved.Addr == "" {
+		return "", fmt.Errorf("domain %s is not bound to any address", addr)
+	}
+	return resolved.Addr, nil
+}
+
+// resolveChainServiceID returns the chainID:serviceID a domain points to, other input is returned as it is
+func resolveChainServiceID(ctx *cli.Context, id string) (string, error) {
+	if !contracts.IsBnsName(id) {
+		return id, nil
+	}
+	resolved, err := resolveBnsName(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if resolved.ServiceID == "" {
+		return "", fmt.Errorf("domain %s is not bound to any service", id)
+	}
+	if _, chainID, serviceID, err := pb.ParseFullServiceID(resolved.ServiceID); err == nil {
+		return fmt.Sprintf("%s:%s", chainID, serviceID), nil
+	}
+	return resolved.ServiceID, nil
+}
+
+func getKeyAddress(ctx *cli.Context) (string, error) {
+	repoRoot, err := repo.PathRootWithDefault(ctx.GlobalString("repo"))
+	if err != nil {
+		return "", fmt.Errorf("pathRootWithDefault error: %w", err)
+	}
+	privKey, err := loadSigner(ctx, repo.GetKeyPath(repoRoot))
+	if err != nil {
+		return "", fmt.Errorf("wrong key: %w", err)
+	}
+	defer privKey.Close()
+	addr, err := privKey.PublicKey().Address()
+	if err != nil {
+		return "", fmt.Errorf("wrong private key: %w", err)
+	}
+	return addr.String(), nil
+}
@@ -42,6 +42,17 @@ func chainCMD() cli.Command {
 	}
 }
 
+// chainStatusResult is the output of querying chain status
+type chainStatusResult struct {
+	Status string `json:"status"`
+}
+
+// tpsResult is the output of querying tps between blocks
+type tpsResult struct {
+	TotalTxCount uint64  `json:"total_tx_count"`
+	Tps          float64 `json:"tps"`
+}
+
 func getChainMeta(ctx *cli.Context) error {
 	url := getURL(ctx, "chain_meta")
 
@@ -50,9 +61,7 @@ func getChainMeta(ctx *cli.Context) error {
 		return fmt.Errorf("httpGet from url %s error: %w", url, err)
 	}
 
-	fmt.Println(prettyJson(string(data)))
-
-	return nil
+	return printRawOutput(ctx, data)
 }
 
 func getChainStatus(ctx *cli.Context) error {
@@ -68,10 +77,9 @@ func getChainStatus(ctx *cli.Context) error {
 		return fmt.Errorf("wrong response: %w", err)
 	}
 
-	fmt.Println(ret)
-
-	return nil
-
+	return printOutput(ctx, &chainStatusResult{Status: ret}, func() {
+		fmt.Println(ret)
+	})
 }
 
 func getTps(ctx *cli.Context) error {
@@ -89,7 +97,12 @@ func getTps(ctx *cli.Context) error {
 		return fmt.Errorf("wrong response: %w", err)
 	}
 
-	fmt.Println(ret)
+	result := &tpsResult{}
+	if _, err := fmt.Sscanf(ret, "total tx count:%d, tps is %f", &result.TotalTxCount, &result.Tps); err != nil {
+		return fmt.Errorf("wrong tps %s: %w", ret, err)
+	}
 
-	return nil
+	return printOutput(ctx, result, func() {
+		fmt.Println(ret)
+	})
 }
@@ -1,6 +1,10 @@
 package client
 
-import "github.com/urfave/cli"
+import (
+	"time"
+
+	"github.com/urfave/cli"
+)
 
 var clientCMD = cli.Command{
 	Name:  "client",
@@ -23,7 +27,26 @@ var clientCMD = cli.Command{
 			Name:  "key",
 			Usage: "Specific access key file if https is enabled",
 		},
+		cli.BoolFlag{
+			Name:  "signer",
+			Usage: "Sign transactions by the signer configured in bitxhub.toml of the repo instead of the key file",
+		},
+		cli.StringFlag{
+			Name:  "output",
+			Usage: "Specify output format, one of table, json and yaml",
+			Value: outputTable,
+		},
+		cli.BoolFlag{
+			Name:  "wait",
+			Usage: "Wait until the receipt of the transaction is received and the proposal submitted by it is approved, rejected or paused",
+		},
+		cli.DurationFlag{
+			Name:  "wait-timeout",
+			Usage: "Specify timeout of waiting for the receipt and the proposal",
+			Value: 10 * time.Minute,
+		},
 	},
+	Before: checkOutput,
 	Subcommands: cli.Commands{
 		accountCMD(),
 		chainCMD(),
@@ -34,6 +57,7 @@ var clientCMD = cli.Command{
 		txCMD(),
 		validatorsCMD(),
 		governanceCMD(),
+		bnsCMD(),
 	},
 }
 
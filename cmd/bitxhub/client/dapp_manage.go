@@ -3,6 +3,7 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -11,7 +12,6 @@ import (
 	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
 	"github.com/meshplus/bitxhub/internal/executor/contracts"
-	"github.com/tidwall/gjson"
 	"github.com/urfave/cli"
 )
 
@@ -43,7 +43,7 @@ func dappMgrCMD() cli.Command {
 				Flags: []cli.Flag{
 					cli.StringFlag{
 						Name:     "id",
-						Usage:    "Specify dapp id",
+						Usage:    "Specify dapp id, a version can be pinned with id@version",
 						Required: true,
 					},
 				},
@@ -235,10 +235,95 @@ func dappMgrCMD() cli.Command {
 				},
 				Action: evaluateDapp,
 			},
+			cli.Command{
+				Name:  "versions",
+				Usage: "Query all versions of dapp",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "id",
+						Usage:    "Specify dapp id",
+						Required: true,
+					},
+				},
+				Action: getDappVersions,
+			},
+			cli.Command{
+				Name:  "version",
+				Usage: "Query a version of dapp",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "id",
+						Usage:    "Specify dapp id",
+						Required: true,
+					},
+					cli.Uint64Flag{
+						Name:     "version",
+						Usage:    "Specify dapp version, 0 means the current version",
+						Required: false,
+					},
+				},
+				Action: getDappVersion,
+			},
+			cli.Command{
+				Name:  "stageVersion",
+				Usage: "Record a new version of dapp which takes effect after being promoted",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "id",
+						Usage:    "Specify dapp id",
+						Required: true,
+					},
+					cli.StringFlag{
+						Name:     "url",
+						Usage:    "Specify dapp url",
+						Required: true,
+					},
+					cli.StringFlag{
+						Name:     "contractAddrs",
+						Usage:    "Specify dapp contract addr. If there are multiple contract addresses, separate them with ','",
+						Required: true,
+					},
+					cli.StringFlag{
+						Name:     "permission",
+						Usage:    "Specify the addr of users which are not allowed to see the dapp. If there are multiple contract addresses, separate them with ','",
+						Required: false,
+					},
+				},
+				Action: stageDappVersion,
+			},
+			cli.Command{
+				Name:  "promote",
+				Usage: "Promote a version of dapp, promoting an older version rolls the dapp back",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "id",
+						Usage:    "Specify dapp id",
+						Required: true,
+					},
+					cli.Uint64Flag{
+						Name:     "version",
+						Usage:    "Specify dapp version",
+						Required: true,
+					},
+					cli.StringFlag{
+						Name:     "reason",
+						Usage:    "Specify promote reason",
+						Required: false,
+					},
+				},
+				Action: promoteDappVersion,
+			},
 		},
 	}
 }
 
+// dappStatusResult is the output of querying dapp status
+type dappStatusResult struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	CurrentVersion uint64 `json:"current_version"`
+}
+
 func getDappStatusById(ctx *cli.Context) error {
 	id := ctx.String("id")
 
@@ -247,16 +332,13 @@ func getDappStatusById(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get dapp status by ID %s: %w", id, err)
 	}
 
-	if receipt.IsSuccess() {
-		dapp := &contracts.Dapp{}
-		if err := json.Unmarshal(receipt.Ret, dapp); err != nil {
-			return fmt.Errorf("unmarshal receipt error: %w", err)
-		}
-		color.Green("dapp %s is %s", dapp.DappID, string(dapp.Status))
-	} else {
-		color.Red("get dapp status error: %s\n", string(receipt.Ret))
+	dapp := &contracts.Dapp{}
+	if err := json.Unmarshal(receipt.Ret, dapp); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
 	}
-	return nil
+	return printOutput(ctx, &dappStatusResult{ID: dapp.DappID, Status: string(dapp.Status), CurrentVersion: dapp.CurrentVersion}, func() {
+		color.Green("dapp %s is %s, current version is %d", dapp.DappID, string(dapp.Status), dapp.CurrentVersion)
+	})
 }
 
 func getAllDapps(ctx *cli.Context) error {
@@ -265,16 +347,13 @@ func getAllDapps(ctx *cli.Context) error {
 		return fmt.Errorf("GetAllDapps error: %v", err)
 	}
 
-	if receipt.IsSuccess() {
-		var dapps []*contracts.Dapp
-		if err := json.Unmarshal(receipt.Ret, &dapps); err != nil {
-			return fmt.Errorf("unmarshal receipt error: %w", err)
-		}
-		printDapp(dapps)
-	} else {
-		color.Red("get dapp status error: %s\n", string(receipt.Ret))
+	var dapps []*contracts.Dapp
+	if err := json.Unmarshal(receipt.Ret, &dapps); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
 	}
-	return nil
+	return printOutput(ctx, dapps, func() {
+		printDapp(dapps)
+	})
 }
 
 func getPermissionDapps(ctx *cli.Context) error {
@@ -284,16 +363,13 @@ func getPermissionDapps(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get permission dapps for caller %s: %w", caller, err)
 	}
 
-	if receipt.IsSuccess() {
-		var dapps []*contracts.Dapp
-		if err := json.Unmarshal(receipt.Ret, &dapps); err != nil {
-			return fmt.Errorf("unmarshal receipt error: %w", err)
-		}
-		printDapp(dapps)
-	} else {
-		color.Red("get dapp status error: %s\n", string(receipt.Ret))
+	var dapps []*contracts.Dapp
+	if err := json.Unmarshal(receipt.Ret, &dapps); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
 	}
-	return nil
+	return printOutput(ctx, dapps, func() {
+		printDapp(dapps)
+	})
 }
 
 func getDappByOwnerAddr(ctx *cli.Context) error {
@@ -304,16 +380,13 @@ func getDappByOwnerAddr(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get dapps by owner %s: %w", addr, err)
 	}
 
-	if receipt.IsSuccess() {
-		var dapps []*contracts.Dapp
-		if err := json.Unmarshal(receipt.Ret, &dapps); err != nil {
-			return fmt.Errorf("unmarshal receipt error: %w", err)
-		}
-		printDapp(dapps)
-	} else {
-		color.Red("get dapp status error: %s\n", string(receipt.Ret))
+	var dapps []*contracts.Dapp
+	if err := json.Unmarshal(receipt.Ret, &dapps); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
 	}
-	return nil
+	return printOutput(ctx, dapps, func() {
+		printDapp(dapps)
+	})
 }
 
 func registerDapp(ctx *cli.Context) error {
@@ -339,16 +412,7 @@ func registerDapp(ctx *cli.Context) error {
 			name, typ, desc, url, contractAddrs, permissionStr, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		ret := &governance.GovernanceResult{}
-		if err := json.Unmarshal(receipt.Ret, ret); err != nil {
-			return err
-		}
-		color.Green("proposal id is %s, dapp id is %s", ret.ProposalID, ret.Extra)
-	} else {
-		color.Red("register dapp error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printProposalResultWithExtra(ctx, receipt, "dapp id")
 }
 
 func updateDapp(ctx *cli.Context) error {
@@ -366,42 +430,38 @@ func updateDapp(ctx *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("invoke BVM failed when get dapp %s: %w", id, err)
 	}
-	if receipt.IsSuccess() {
-		dapp := &contracts.Dapp{}
-		if err := json.Unmarshal(receipt.Ret, dapp); err != nil {
-			return fmt.Errorf("unmarshal receipt error: %w", err)
-		}
-		if name == "" {
-			name = dapp.Name
-		}
-		if desc == "" {
-			desc = dapp.Desc
-		}
-		if url == "" {
-			url = dapp.Url
-		}
-		if contractAddrs == "" {
-			for k := range dapp.ContractAddr {
-				if contractAddrs == "" {
-					contractAddrs = k
-				} else {
-					contractAddrs = fmt.Sprintf("%s,%s", contractAddrs, k)
-				}
+	dapp := &contracts.Dapp{}
+	if err := json.Unmarshal(receipt.Ret, dapp); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
+	}
+	if name == "" {
+		name = dapp.Name
+	}
+	if desc == "" {
+		desc = dapp.Desc
+	}
+	if url == "" {
+		url = dapp.Url
+	}
+	if contractAddrs == "" {
+		for k := range dapp.ContractAddr {
+			if contractAddrs == "" {
+				contractAddrs = k
+			} else {
+				contractAddrs = fmt.Sprintf("%s,%s", contractAddrs, k)
 			}
-
 		}
-		if permissionStr == "" {
-			for k := range dapp.Permission {
-				if permissionStr == "" {
-					permissionStr = k
-				} else {
-					permissionStr = fmt.Sprintf("%s,%s", permissionStr, k)
-				}
-			}
 
+	}
+	if permissionStr == "" {
+		for k := range dapp.Permission {
+			if permissionStr == "" {
+				permissionStr = k
+			} else {
+				permissionStr = fmt.Sprintf("%s,%s", permissionStr, k)
+			}
 		}
-	} else {
-		color.Red("get dapp info error: %s\n", string(receipt.Ret))
+
 	}
 
 	receipt, err = invokeBVMContract(ctx, constant.DappMgrContractAddr.String(), "UpdateDapp",
@@ -418,20 +478,10 @@ func updateDapp(ctx *cli.Context) error {
 			id, name, desc, url, contractAddrs, permissionStr, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		ret := &governance.GovernanceResult{}
-		if err := json.Unmarshal(receipt.Ret, ret); err != nil {
-			return err
-		}
-		if ret.ProposalID != "" {
-			color.Green("proposal id is %s", ret.ProposalID)
-		} else {
-			color.Green("update dapp success")
-		}
-	} else {
-		color.Red("update dapp error: %s\n", string(receipt.Ret))
+	if !hasProposal(receipt) {
+		return printTxResult(ctx, receipt, "update dapp success")
 	}
-	return nil
+	return printProposalResult(ctx, receipt)
 }
 
 func freezeDapp(ctx *cli.Context) error {
@@ -443,13 +493,7 @@ func freezeDapp(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when freeze dapp %s for %s: %w", id, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s", proposalId)
-	} else {
-		color.Red("freeze dapp error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printProposalResult(ctx, receipt)
 }
 
 func activateDapp(ctx *cli.Context) error {
@@ -461,13 +505,7 @@ func activateDapp(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when activate dapp %s for %s: %w", id, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s", proposalId)
-	} else {
-		color.Red("activate dapp error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printProposalResult(ctx, receipt)
 }
 
 func transferDapp(ctx *cli.Context) error {
@@ -480,13 +518,7 @@ func transferDapp(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when transfer dapp %s to %s for %s: %w", id, addr, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s", proposalId)
-	} else {
-		color.Red("transfer dapp error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printProposalResult(ctx, receipt)
 }
 
 func confirmDapp(ctx *cli.Context) error {
@@ -497,12 +529,7 @@ func confirmDapp(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when confirm transfer dapp %s: %w", id, err)
 	}
 
-	if receipt.IsSuccess() {
-		color.Green("confirm dapp transfer success")
-	} else {
-		color.Red("confirm dapp transfer error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printTxResult(ctx, receipt, "confirm dapp transfer success")
 }
 
 func evaluateDapp(ctx *cli.Context) error {
@@ -515,12 +542,109 @@ func evaluateDapp(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when evaluate dapp %s to score %f for %s: %w", id, score, desc, err)
 	}
 
-	if receipt.IsSuccess() {
-		color.Green("evaluate dapp success")
-	} else {
-		color.Red("evaluate dapp error: %s\n", string(receipt.Ret))
+	return printTxResult(ctx, receipt, "evaluate dapp success")
+}
+
+func getDappVersions(ctx *cli.Context) error {
+	id := ctx.String("id")
+
+	receipt, err := invokeBVMContractBySendView(ctx, constant.DappMgrContractAddr.String(), "GetDappVersions", pb.String(id))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get versions of dapp %s: %w", id, err)
+	}
+
+	var versions []*contracts.DappVersion
+	if err := json.Unmarshal(receipt.Ret, &versions); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
+	}
+	return printOutput(ctx, versions, func() {
+		printDappVersion(versions)
+	})
+}
+
+func getDappVersion(ctx *cli.Context) error {
+	id := ctx.String("id")
+	version := ctx.Uint64("version")
+
+	receipt, err := invokeBVMContractBySendView(ctx, constant.DappMgrContractAddr.String(), "GetDappVersion", pb.String(id), pb.Uint64(version))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get version %d of dapp %s: %w", version, id, err)
+	}
+
+	v := &contracts.DappVersion{}
+	if err := json.Unmarshal(receipt.Ret, v); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
+	}
+	return printOutput(ctx, v, func() {
+		printDappVersion([]*contracts.DappVersion{v})
+	})
+}
+
+func stageDappVersion(ctx *cli.Context) error {
+	id := ctx.String("id")
+	url := ctx.String("url")
+	contractAddrs := strings.TrimSpace(ctx.String("contractAddrs"))
+	permissionStr := strings.TrimSpace(ctx.String("permission"))
+
+	receipt, err := invokeBVMContract(ctx, constant.DappMgrContractAddr.String(), "StageDappVersion",
+		pb.String(id),
+		pb.String(url),
+		pb.String(contractAddrs),
+		pb.String(permissionStr),
+	)
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when stage dapp version \" id=%s,url=%s,contractAddrs=%s,permissionStr=%s \": %w",
+			id, url, contractAddrs, permissionStr, err)
+	}
+
+	ret := &governance.GovernanceResult{}
+	if err := json.Unmarshal(receipt.Ret, ret); err != nil {
+		return err
+	}
+	return printOutput(ctx, &txResult{TxHash: receipt.TxHash.String(), Extra: string(ret.Extra)}, func() {
+		color.Green("dapp version %s is staged", ret.Extra)
+	})
+}
+
+func promoteDappVersion(ctx *cli.Context) error {
+	id := ctx.String("id")
+	version := ctx.Uint64("version")
+	reason := ctx.String("reason")
+
+	receipt, err := invokeBVMContract(ctx, constant.DappMgrContractAddr.String(), "PromoteDappVersion", pb.String(id), pb.Uint64(version), pb.String(reason))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when promote version %d of dapp %s for %s: %w", version, id, reason, err)
+	}
+
+	return printProposalResult(ctx, receipt)
+}
+
+func printDappVersion(versions []*contracts.DappVersion) {
+	var table [][]string
+	table = append(table, []string{"Version", "Url", "ContractAddr", "Permission", "Creator", "Createtime"})
+
+	for _, v := range versions {
+		table = append(table, []string{
+			strconv.FormatUint(v.Version, 10),
+			v.Url,
+			printAddrSet(v.ContractAddr),
+			printAddrSet(v.Permission),
+			v.Creator,
+			strconv.Itoa(int(v.CreateTime)),
+		})
+	}
+
+	fmt.Println("========================================================================================")
+	PrintTable(table, true)
+}
+
+func printAddrSet(addrs map[string]struct{}) string {
+	var ret []string
+	for addr := range addrs {
+		ret = append(ret, addr)
 	}
-	return nil
+	sort.Strings(ret)
+	return strings.Join(ret, "\n")
 }
 
 func printDapp(dapps []*contracts.Dapp) {
@@ -11,7 +11,6 @@ import (
 	"github.com/Rican7/retry"
 	"github.com/Rican7/retry/strategy"
 	"github.com/cheynewallace/tabby"
-	"github.com/fatih/color"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
@@ -19,6 +18,7 @@ import (
 	"github.com/meshplus/bitxhub/internal/repo"
 	"github.com/tidwall/gjson"
 	"github.com/urfave/cli"
+	"os"
 )
 
 func governanceCMD() cli.Command {
@@ -129,16 +129,13 @@ func proposalStrategyCMD() cli.Command {
 						return fmt.Errorf("invoke BVM contract failed when get all proposal strategy: %w", err)
 					}
 
-					if receipt.IsSuccess() {
-						strategies := make([]*contracts.ProposalStrategy, 0)
-						if err := json.Unmarshal(receipt.Ret, &strategies); err != nil {
-							return fmt.Errorf(err.Error())
-						}
-						printProposalStrategy(strategies)
-					} else {
-						color.Red("get all proposal strategy error: %s\n", string(receipt.Ret))
+					strategies := make([]*contracts.ProposalStrategy, 0)
+					if err := json.Unmarshal(receipt.Ret, &strategies); err != nil {
+						return fmt.Errorf(err.Error())
 					}
-					return nil
+					return printOutput(ctx, strategies, func() {
+						printProposalStrategy(strategies)
+					})
 				},
 			},
 			cli.Command{
@@ -189,13 +186,7 @@ func proposalStrategyCMD() cli.Command {
 						return fmt.Errorf("invoke BVM contract failed when get all proposal strategy: %w", err)
 					}
 
-					if receipt.IsSuccess() {
-						proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-						color.Green("proposal id is %s\n", proposalId)
-					} else {
-						color.Red("update proposal strategy error: %s\n", string(receipt.Ret))
-					}
-					return nil
+					return printProposalResult(ctx, receipt)
 				},
 			},
 		},
@@ -211,12 +202,7 @@ func withdraw(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when withdraw proposal %s for %s: %w", id, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		color.Green("withdraw proposal successfully!\n")
-	} else {
-		color.Red("withdraw proposal  error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printTxResult(ctx, receipt, "withdraw proposal successfully!\n")
 }
 
 func vote(ctx *cli.Context) error {
@@ -233,12 +219,7 @@ func vote(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when vote proposal %s to %s for %s: %w", id, info, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		color.Green("vote successfully!\n")
-	} else {
-		color.Red("vote error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printTxResult(ctx, receipt, "vote successfully!\n")
 }
 
 func getProposals(ctx *cli.Context) error {
@@ -301,9 +282,9 @@ func getProposals(ctx *cli.Context) error {
 		}
 	}
 
-	printProposal(proposals)
-
-	return nil
+	return printOutput(ctx, proposals, func() {
+		printProposal(proposals)
+	})
 }
 
 func checkProposalArgs(id, typ, status, from, objId string) error {
@@ -454,28 +435,9 @@ func invokeBVMContract(ctx *cli.Context, contractAddr string, method string, arg
 
 	hash := gjson.Get(string(resp), "tx_hash").String()
 
-	var data []byte
-	if err = retry.Retry(func(attempt uint) error {
-		time.Sleep(2 * time.Second)
-		data, err = getTxReceipt(ctx, hash)
-		if err != nil {
-			fmt.Printf("the tx receipt has not been received yet: %v... retry later \n", err)
-			return err
-		} else {
-			m := make(map[string]interface{})
-			if err := json.Unmarshal(data, &m); err != nil {
-				fmt.Printf("the tx receipt has not been received yet: %v... retry later \n", err)
-				return err
-			}
-			if errInfo, ok := m["error"]; ok {
-				fmt.Printf("the tx receipt has not been received yet: %v... retry later \n", errInfo.(string))
-				return fmt.Errorf(errInfo.(string))
-			}
-			return nil
-		}
-	}, strategy.Wait(500*time.Millisecond),
-	); err != nil {
-		fmt.Println("get transaction receipt error: " + err.Error())
+	data, err := waitReceipt(ctx, hash)
+	if err != nil {
+		return nil, err
 	}
 
 	m := &runtime.JSONPb{OrigName: true, EmitDefaults: false, EnumsAsInts: true}
@@ -490,6 +452,28 @@ func invokeBVMContract(ctx *cli.Context, contractAddr string, method string, arg
 	return receipt, nil
 }
 
+// waitReceipt polls the receipt of the transaction until it is received or the wait timeout is reached
+func waitReceipt(ctx *cli.Context, hash string) ([]byte, error) {
+	var data []byte
+	deadline := time.Now().Add(ctx.GlobalDuration("wait-timeout"))
+	if err := retry.Retry(func(attempt uint) error {
+		time.Sleep(2 * time.Second)
+		var err error
+		data, err = getTxReceipt(ctx, hash)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "the tx receipt has not been received yet: %v... retry later \n", err)
+			return err
+		}
+		return nil
+	}, strategy.Wait(500*time.Millisecond), func(attempt uint) bool {
+		return time.Now().Before(deadline)
+	}); err != nil {
+		return nil, fmt.Errorf("get transaction receipt error: %w", err)
+	}
+
+	return data, nil
+}
+
 func invokeBVMContractBySendView(ctx *cli.Context, contractAddr string, method string, args ...*pb.Arg) (*pb.Receipt, error) {
 	repoRoot, err := repo.PathRootWithDefault(ctx.GlobalString("repo"))
 	if err != nil {
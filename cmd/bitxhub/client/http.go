@@ -43,6 +43,10 @@ func httpGet(ctx *cli.Context, url string) ([]byte, error) {
 		return nil, fmt.Errorf("close response body failed: %w", err)
 	}
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", resp.Status, string(c))
+	}
+
 	return c, nil
 }
 
@@ -77,6 +81,10 @@ func httpPost(ctx *cli.Context, url string, data []byte) ([]byte, error) {
 		return nil, fmt.Errorf("close response body failed: %w", err)
 	}
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", resp.Status, string(c))
+	}
+
 	return c, nil
 }
 
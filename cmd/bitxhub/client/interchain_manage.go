@@ -1,13 +1,14 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 
-	"github.com/fatih/color"
 	"github.com/meshplus/bitxhub-kit/types"
 	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
 	"github.com/meshplus/bitxhub/pkg/utils"
 	"github.com/urfave/cli"
 )
@@ -58,6 +59,188 @@ func interchainMgrCMD() cli.Command {
 				},
 				Action: getIbtpStatus,
 			},
+			cli.Command{
+				Name:  "receipts",
+				Usage: "Query receipt counter and receipts recorded out of order between two services",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "from",
+						Usage:    "Specify full source service id",
+						Required: true,
+					},
+					cli.StringFlag{
+						Name:     "to",
+						Usage:    "Specify full destination service id",
+						Required: true,
+					},
+				},
+				Action: getReceiptWindow,
+			},
+			cli.Command{
+				Name:  "lease",
+				Usage: "Query pier lease and fencing token of appchain",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "chain",
+						Usage:    "Specify appchain id",
+						Required: true,
+					},
+				},
+				Action: getPierLease,
+			},
+			cli.Command{
+				Name:  "fee",
+				Usage: "Interchain fee command",
+				Subcommands: cli.Commands{
+					cli.Command{
+						Name:  "schedule",
+						Usage: "Submit a proposal to set the fee of interchain requests to the destination chain, 0 removes the fee",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "chain",
+								Usage:    "Specify destination chain id",
+								Required: true,
+							},
+							cli.Uint64Flag{
+								Name:     "fee",
+								Usage:    "Specify fee of every interchain request",
+								Required: true,
+							},
+							cli.StringFlag{
+								Name:     "reason",
+								Usage:    "Specify reason to set fee schedule",
+								Required: false,
+							},
+						},
+						Action: setFeeSchedule,
+					},
+					cli.Command{
+						Name:   "schedules",
+						Usage:  "Query fee schedules of all destination chains",
+						Action: getFeeSchedules,
+					},
+					cli.Command{
+						Name:  "escrow",
+						Usage: "Query escrowed fee by full ibtp id",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "id",
+								Usage:    "Specify full ibtp id",
+								Required: true,
+							},
+						},
+						Action: getFeeEscrow,
+					},
+					cli.Command{
+						Name:  "attach",
+						Usage: "Attach more fee to an escrowed interchain request",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "id",
+								Usage:    "Specify full ibtp id",
+								Required: true,
+							},
+							cli.Uint64Flag{
+								Name:     "amount",
+								Usage:    "Specify amount of attached fee",
+								Required: true,
+							},
+						},
+						Action: attachFee,
+					},
+					cli.Command{
+						Name:  "refund",
+						Usage: "Refund the escrowed fee of a rolled back or failed interchain request",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "id",
+								Usage:    "Specify full ibtp id",
+								Required: true,
+							},
+						},
+						Action: refundFee,
+					},
+					cli.Command{
+						Name:  "deposit",
+						Usage: "Deposit the fee of interchain requests sent by the service, only the appchain admin can deposit",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "service",
+								Usage:    "Specify chain service id, i.e. chainID:serviceID",
+								Required: true,
+							},
+							cli.Uint64Flag{
+								Name:     "amount",
+								Usage:    "Specify amount of deposited fee",
+								Required: true,
+							},
+						},
+						Action: depositFee,
+					},
+					cli.Command{
+						Name:  "withdraw",
+						Usage: "Withdraw the fee deposited for the service, only the appchain admin can withdraw",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "service",
+								Usage:    "Specify chain service id, i.e. chainID:serviceID",
+								Required: true,
+							},
+							cli.Uint64Flag{
+								Name:     "amount",
+								Usage:    "Specify amount of withdrawn fee",
+								Required: true,
+							},
+						},
+						Action: withdrawFee,
+					},
+					cli.Command{
+						Name:  "balance",
+						Usage: "Query the fee deposited for the service",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "service",
+								Usage:    "Specify chain service id, i.e. chainID:serviceID",
+								Required: true,
+							},
+						},
+						Action: getFeeDeposit,
+					},
+				},
+			},
+			cli.Command{
+				Name:  "route",
+				Usage: "Inter-relay route command",
+				Subcommands: cli.Commands{
+					cli.Command{
+						Name:  "set",
+						Usage: "Submit a proposal to set the next hop of IBTPs to the destination bitxhub, empty next hop removes the route",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "bxh",
+								Usage:    "Specify destination bitxhub id",
+								Required: true,
+							},
+							cli.StringFlag{
+								Name:     "next_hop",
+								Usage:    "Specify neighbour bitxhub id which IBTPs are forwarded to",
+								Required: false,
+							},
+							cli.StringFlag{
+								Name:     "reason",
+								Usage:    "Specify reason to set route",
+								Required: false,
+							},
+						},
+						Action: setRoute,
+					},
+					cli.Command{
+						Name:   "list",
+						Usage:  "Query routes of all destination bitxhubs",
+						Action: getRoutes,
+					},
+				},
+			},
 		},
 	}
 }
@@ -70,16 +253,181 @@ func getInterchainCounter(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get interchainCounter by id %s: %w", id, err)
 	}
 
-	if receipt.IsSuccess() {
-		interchain := &pb.Interchain{}
-		if err := interchain.Unmarshal(receipt.Ret); err != nil {
-			return fmt.Errorf("unmarshal receipt error: %w", err)
-		}
+	interchain := &pb.Interchain{}
+	if err := interchain.Unmarshal(receipt.Ret); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
+	}
+	return printOutput(ctx, interchain, func() {
 		utils.PrettyPrint(interchain)
-	} else {
-		color.Red("get interchain counter error: %s\n", string(receipt.Ret))
+	})
+}
+
+func getReceiptWindow(ctx *cli.Context) error {
+	from := ctx.String("from")
+	to := ctx.String("to")
+
+	receipt, err := invokeBVMContractBySendView(ctx, constant.InterchainContractAddr.String(), "GetReceiptWindow", pb.String(from), pb.String(to))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get receipt window from %s to %s: %w", from, to, err)
+	}
+
+	info := &contracts.ReceiptWindowInfo{}
+	if err := json.Unmarshal(receipt.Ret, info); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
 	}
-	return nil
+	return printOutput(ctx, info, func() {
+		utils.PrettyPrint(info)
+	})
+}
+
+func getPierLease(ctx *cli.Context) error {
+	chainID := ctx.String("chain")
+
+	receipt, err := invokeBVMContractBySendView(ctx, constant.InterchainContractAddr.String(), "GetPierLease", pb.String(chainID))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get pier lease of %s: %w", chainID, err)
+	}
+
+	lease := &contracts.PierLease{}
+	if err := json.Unmarshal(receipt.Ret, lease); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
+	}
+	return printOutput(ctx, lease, func() {
+		utils.PrettyPrint(lease)
+	})
+}
+
+func setFeeSchedule(ctx *cli.Context) error {
+	chainID := ctx.String("chain")
+	fee := ctx.Uint64("fee")
+	reason := ctx.String("reason")
+
+	receipt, err := invokeBVMContract(ctx, constant.InterchainContractAddr.String(), "SetFeeSchedule", pb.String(chainID), pb.Uint64(fee), pb.String(reason))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when set fee schedule of %s: %w", chainID, err)
+	}
+
+	return printProposalResult(ctx, receipt)
+}
+
+func getFeeSchedules(ctx *cli.Context) error {
+	receipt, err := invokeBVMContractBySendView(ctx, constant.InterchainContractAddr.String(), "GetFeeSchedules")
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get fee schedules: %w", err)
+	}
+
+	schedules := make(map[string]uint64)
+	if err := json.Unmarshal(receipt.Ret, &schedules); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
+	}
+	return printOutput(ctx, schedules, func() {
+		utils.PrettyPrint(schedules)
+	})
+}
+
+func getFeeEscrow(ctx *cli.Context) error {
+	id := ctx.String("id")
+
+	receipt, err := invokeBVMContractBySendView(ctx, constant.InterchainContractAddr.String(), "GetFeeEscrow", pb.String(id))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get fee escrow by id %s: %w", id, err)
+	}
+
+	escrow := &contracts.FeeEscrow{}
+	if err := json.Unmarshal(receipt.Ret, escrow); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
+	}
+	return printOutput(ctx, escrow, func() {
+		utils.PrettyPrint(escrow)
+	})
+}
+
+func attachFee(ctx *cli.Context) error {
+	id := ctx.String("id")
+	amount := ctx.Uint64("amount")
+
+	receipt, err := invokeBVMContract(ctx, constant.InterchainContractAddr.String(), "AttachFee", pb.String(id), pb.Uint64(amount))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when attach fee to %s: %w", id, err)
+	}
+
+	return printTxResult(ctx, receipt, "attach fee %d to %s successfully", amount, id)
+}
+
+func refundFee(ctx *cli.Context) error {
+	id := ctx.String("id")
+
+	receipt, err := invokeBVMContract(ctx, constant.InterchainContractAddr.String(), "RefundFee", pb.String(id))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when refund fee of %s: %w", id, err)
+	}
+
+	return printTxResult(ctx, receipt, "refund fee of %s successfully", id)
+}
+
+func depositFee(ctx *cli.Context) error {
+	service := ctx.String("service")
+	amount := ctx.Uint64("amount")
+
+	receipt, err := invokeBVMContract(ctx, constant.InterchainContractAddr.String(), "DepositFee", pb.String(service), pb.Uint64(amount))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when deposit fee for %s: %w", service, err)
+	}
+
+	return printTxResult(ctx, receipt, "deposit fee %d for %s successfully", amount, service)
+}
+
+func withdrawFee(ctx *cli.Context) error {
+	service := ctx.String("service")
+	amount := ctx.Uint64("amount")
+
+	receipt, err := invokeBVMContract(ctx, constant.InterchainContractAddr.String(), "WithdrawFee", pb.String(service), pb.Uint64(amount))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when withdraw fee of %s: %w", service, err)
+	}
+
+	return printTxResult(ctx, receipt, "withdraw fee %d of %s successfully", amount, service)
+}
+
+func getFeeDeposit(ctx *cli.Context) error {
+	service := ctx.String("service")
+
+	receipt, err := invokeBVMContractBySendView(ctx, constant.InterchainContractAddr.String(), "GetFeeDeposit", pb.String(service))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get fee deposit of %s: %w", service, err)
+	}
+
+	return printOutput(ctx, string(receipt.Ret), func() {
+		fmt.Println(string(receipt.Ret))
+	})
+}
+
+func setRoute(ctx *cli.Context) error {
+	bxhID := ctx.String("bxh")
+	nextHop := ctx.String("next_hop")
+	reason := ctx.String("reason")
+
+	receipt, err := invokeBVMContract(ctx, constant.InterchainContractAddr.String(), "SetRoute", pb.String(bxhID), pb.String(nextHop), pb.String(reason))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when set route to %s: %w", bxhID, err)
+	}
+
+	return printProposalResult(ctx, receipt)
+}
+
+func getRoutes(ctx *cli.Context) error {
+	receipt, err := invokeBVMContractBySendView(ctx, constant.InterchainContractAddr.String(), "GetRoutes")
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get routes: %w", err)
+	}
+
+	routes := make(map[string]string)
+	if err := json.Unmarshal(receipt.Ret, &routes); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
+	}
+	return printOutput(ctx, routes, func() {
+		utils.PrettyPrint(routes)
+	})
 }
 
 func getIbtpTxHash(ctx *cli.Context) error {
@@ -91,14 +439,11 @@ func getIbtpTxHash(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get ibtp tx hash by id %s and is_req %v: %w", id, isReq, err)
 	}
 
-	if receipt.IsSuccess() {
-		hash := &types.Hash{}
-		hash.SetBytes(receipt.Ret)
+	hash := &types.Hash{}
+	hash.SetBytes(receipt.Ret)
+	return printOutput(ctx, &txResult{TxHash: hash.String()}, func() {
 		fmt.Println(hash.String())
-	} else {
-		color.Red("get interchain counter error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	})
 }
 
 func getIbtpStatus(ctx *cli.Context) error {
@@ -109,15 +454,12 @@ func getIbtpStatus(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get ibtp status by id %s: %w", id, err)
 	}
 
-	if receipt.IsSuccess() {
-		status := string(receipt.Ret)
-		res, err := strconv.Atoi(status)
-		if err != nil {
-			return err
-		}
-		fmt.Println("status is:", pb.TransactionStatus(res))
-	} else {
-		color.Red("get interchain counter error: %s\n", string(receipt.Ret))
+	status := string(receipt.Ret)
+	res, err := strconv.Atoi(status)
+	if err != nil {
+		return err
 	}
-	return nil
+	return printOutput(ctx, &statusResult{ID: id, Status: pb.TransactionStatus(res).String()}, func() {
+		fmt.Println("status is:", pb.TransactionStatus(res))
+	})
 }
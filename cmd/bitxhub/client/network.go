@@ -27,12 +27,5 @@ func network(ctx *cli.Context) error {
 		return fmt.Errorf("wrong response: %w", err)
 	}
 
-	retJson, err := prettyJson(ret)
-	if err != nil {
-		return fmt.Errorf("wrong response: %w", err)
-	}
-
-	fmt.Println(retJson)
-
-	return nil
+	return printRawOutput(ctx, []byte(ret))
 }
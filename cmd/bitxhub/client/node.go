@@ -27,12 +27,5 @@ func getValidators(ctx *cli.Context) error {
 		return err
 	}
 
-	retJson, err := prettyJson(ret)
-	if err != nil {
-		return fmt.Errorf("wrong response: %w", err)
-	}
-
-	fmt.Println(retJson)
-
-	return nil
+	return printRawOutput(ctx, []byte(ret))
 }
@@ -3,6 +3,7 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"strconv"
 	"strings"
 
@@ -10,7 +11,8 @@ import (
 	node_mgr "github.com/meshplus/bitxhub-core/node-mgr"
 	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
-	"github.com/tidwall/gjson"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
+	"github.com/meshplus/bitxhub/internal/repo"
 	"github.com/urfave/cli"
 )
 
@@ -53,7 +55,7 @@ func nodeMgrCMD() cli.Command {
 					},
 					cli.StringFlag{
 						Name:     "pid",
-						Usage:    "Specify vp node pid, only useful for vpNode",
+						Usage:    "Specify node pid, required for vpNode, an nvpNode with pid is allowed to follow the chain as a follower",
 						Required: false,
 					},
 					cli.Uint64Flag{
@@ -123,6 +125,38 @@ func nodeMgrCMD() cli.Command {
 				},
 				Action: logoutNode,
 			},
+			cli.Command{
+				Name:  "revoke_cert",
+				Usage: "Submit a proposal to revoke node or agency certification",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "cert",
+						Usage: "Specify certification path",
+					},
+					cli.StringFlag{
+						Name:  "fingerprint",
+						Usage: "Specify sha256 fingerprint of certification if the certification path is not specified",
+					},
+					cli.StringFlag{
+						Name:     "reason",
+						Usage:    "Specify revoke reason",
+						Required: false,
+					},
+				},
+				Action: revokeCert,
+			},
+			cli.Command{
+				Name:  "cert_revocation",
+				Usage: "Query revocation of certification by fingerprint",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "fingerprint",
+						Usage:    "Specify sha256 fingerprint of certification",
+						Required: true,
+					},
+				},
+				Action: getCertRevocation,
+			},
 		},
 	}
 }
@@ -135,16 +169,13 @@ func getNodeStatusByAccount(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get node status by account %s: %w", account, err)
 	}
 
-	if receipt.IsSuccess() {
-		node := &node_mgr.Node{}
-		if err := json.Unmarshal(receipt.Ret, node); err != nil {
-			return fmt.Errorf("unmarshal receipt error: %w", err)
-		}
-		color.Green("node %s is %s\n", node.Pid, string(node.Status))
-	} else {
-		color.Red("get node status error: %s\n", string(receipt.Ret))
+	node := &node_mgr.Node{}
+	if err := json.Unmarshal(receipt.Ret, node); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
 	}
-	return nil
+	return printOutput(ctx, &statusResult{ID: node.Account, Status: string(node.Status)}, func() {
+		color.Green("node %s is %s\n", node.Pid, string(node.Status))
+	})
 }
 
 func registerNode(ctx *cli.Context) error {
@@ -169,13 +200,7 @@ func registerNode(ctx *cli.Context) error {
 			account, typ, pid, vpNodeId, name, permission, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s\n", proposalId)
-	} else {
-		color.Red("register node error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printProposalResult(ctx, receipt)
 }
 
 func updateNode(ctx *cli.Context) error {
@@ -194,13 +219,7 @@ func updateNode(ctx *cli.Context) error {
 			account, name, permission, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s\n", proposalId)
-	} else {
-		color.Red("update node error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printProposalResult(ctx, receipt)
 }
 
 func logoutNode(ctx *cli.Context) error {
@@ -212,13 +231,51 @@ func logoutNode(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when logout node by account %s for %s: %w", account, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s\n", proposalId)
-	} else {
-		color.Red("logout node error: %s\n", string(receipt.Ret))
+	return printProposalResult(ctx, receipt)
+}
+
+func revokeCert(ctx *cli.Context) error {
+	fingerprint := ctx.String("fingerprint")
+	reason := ctx.String("reason")
+
+	if certPath := ctx.String("cert"); certPath != "" {
+		data, err := ioutil.ReadFile(certPath)
+		if err != nil {
+			return fmt.Errorf("read certification error: %w", err)
+		}
+		cert, err := repo.ParseCert(data)
+		if err != nil {
+			return fmt.Errorf("parse certification error: %w", err)
+		}
+		fingerprint = repo.CertFingerprint(cert)
+	}
+	if fingerprint == "" {
+		return fmt.Errorf("certification path or fingerprint should be specified")
+	}
+
+	receipt, err := invokeBVMContract(ctx, constant.NodeManagerContractAddr.Address().String(), "RevokeCert", pb.String(fingerprint), pb.String(reason))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when revoke certification %s for %s: %w", fingerprint, reason, err)
 	}
-	return nil
+
+	return printProposalResult(ctx, receipt)
+}
+
+func getCertRevocation(ctx *cli.Context) error {
+	fingerprint := ctx.String("fingerprint")
+
+	receipt, err := invokeBVMContractBySendView(ctx, constant.NodeManagerContractAddr.Address().String(), "GetCertRevocation", pb.String(fingerprint))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get revocation of certification %s: %w", fingerprint, err)
+	}
+
+	revocation := &contracts.CertRevocation{}
+	if err := json.Unmarshal(receipt.Ret, revocation); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
+	}
+	return printOutput(ctx, revocation, func() {
+		color.Green("certification %s is revoked at height %d for %s\n", revocation.Fingerprint, revocation.Height, revocation.Reason)
+	})
 }
 
 func allNode(ctx *cli.Context) error {
@@ -227,18 +284,15 @@ func allNode(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get all node info: %w", err)
 	}
 
-	if receipt.IsSuccess() {
-		nodes := make([]*node_mgr.Node, 0)
-		if receipt.Ret != nil {
-			if err := json.Unmarshal(receipt.Ret, &nodes); err != nil {
-				return fmt.Errorf("unmarshal nodes error: %w", err)
-			}
+	nodes := make([]*node_mgr.Node, 0)
+	if receipt.Ret != nil {
+		if err := json.Unmarshal(receipt.Ret, &nodes); err != nil {
+			return fmt.Errorf("unmarshal nodes error: %w", err)
 		}
-		printNode(nodes)
-	} else {
-		color.Red("query node info error: %s\n", string(receipt.Ret))
 	}
-	return nil
+	return printOutput(ctx, nodes, func() {
+		printNode(nodes)
+	})
 }
 
 func printNode(nodes []*node_mgr.Node) {
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/meshplus/bitxhub-core/governance"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
+	"github.com/tidwall/gjson"
+	"github.com/urfave/cli"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+
+	waitInterval = 2 * time.Second
+)
+
+// txResult is the output of the commands which send a transaction, the proposal fields
+// are set if the transaction submits a proposal and the status is set only in wait mode
+type txResult struct {
+	TxHash         string `json:"tx_hash"`
+	ProposalID     string `json:"proposal_id,omitempty"`
+	ProposalStatus string `json:"proposal_status,omitempty"`
+	EndReason      string `json:"end_reason,omitempty"`
+	Extra          string `json:"extra,omitempty"`
+}
+
+// statusResult is the output of the commands which query the status of a governed object
+type statusResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func checkOutput(ctx *cli.Context) error {
+	switch output := ctx.String("output"); output {
+	case outputTable, outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %s, it should be one of table, json and yaml", output)
+	}
+}
+
+func isTableOutput(ctx *cli.Context) bool {
+	output := ctx.GlobalString("output")
+	return output == "" || output == outputTable
+}
+
+// printOutput prints v as json or yaml, printTable is called instead for the table output
+func printOutput(ctx *cli.Context, v interface{}, printTable func()) error {
+	switch ctx.GlobalString("output") {
+	case outputJSON:
+		data, err := json.MarshalIndent(v, empty, tab)
+		if err != nil {
+			return fmt.Errorf("marshal output error: %w", err)
+		}
+		fmt.Println(string(data))
+	case outputYAML:
+		data, err := marshalYaml(v)
+		if err != nil {
+			return fmt.Errorf("marshal output error: %w", err)
+		}
+		fmt.Print(string(data))
+	default:
+		printTable()
+	}
+
+	return nil
+}
+
+// printRawOutput prints the json returned by the gateway, it is indented for the table output
+func printRawOutput(ctx *cli.Context, data []byte) error {
+	ret, err := prettyJson(string(data))
+	if err != nil {
+		return err
+	}
+
+	return printOutput(ctx, json.RawMessage(data), func() {
+		fmt.Println(ret)
+	})
+}
+
+// marshalYaml converts v to yaml through json, so the yaml output has the same fields as the json output
+func marshalYaml(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(obj)
+}
+
+// printTxResult prints the hash of the transaction, the message is printed for the table output
+func printTxResult(ctx *cli.Context, receipt *pb.Receipt, format string, a ...interface{}) error {
+	return printOutput(ctx, &txResult{TxHash: receipt.TxHash.String()}, func() {
+		color.Green(format, a...)
+	})
+}
+
+// printProposalResult prints the proposal submitted by the transaction, in wait mode it blocks until
+// the proposal is approved, rejected or paused and a proposal not approved is reported as an error
+func printProposalResult(ctx *cli.Context, receipt *pb.Receipt) error {
+	return printProposalResultWithExtra(ctx, receipt, "")
+}
+
+// printProposalResultWithExtra is printProposalResult for the transactions which return extra info
+// along with the proposal, extraName describes the extra info for the table output
+func printProposalResultWithExtra(ctx *cli.Context, receipt *pb.Receipt, extraName string) error {
+	// some governance calls take effect directly without submitting a proposal
+	if !hasProposal(receipt) {
+		return printTxResult(ctx, receipt, "invoke successfully: %s\n", string(receipt.Ret))
+	}
+	ret := &governance.GovernanceResult{}
+	if err := json.Unmarshal(receipt.Ret, ret); err != nil {
+		return fmt.Errorf("unmarshal governance result error: %w", err)
+	}
+	result := &txResult{
+		TxHash:     receipt.TxHash.String(),
+		ProposalID: ret.ProposalID,
+		Extra:      string(ret.Extra),
+	}
+	if isTableOutput(ctx) {
+		if extraName == "" {
+			color.Green("proposal id is %s\n", result.ProposalID)
+		} else {
+			color.Green("proposal id is %s, %s is %s\n", result.ProposalID, extraName, result.Extra)
+		}
+	}
+
+	if ctx.GlobalBool("wait") {
+		proposal, err := waitProposal(ctx, result.ProposalID)
+		if err != nil {
+			return err
+		}
+		result.ProposalStatus = string(proposal.Status)
+		result.EndReason = string(proposal.EndReason)
+		if isTableOutput(ctx) {
+			color.Green("proposal %s is %s: %s\n", result.ProposalID, result.ProposalStatus, result.EndReason)
+		}
+	}
+
+	if err := printOutput(ctx, result, func() {}); err != nil {
+		return err
+	}
+	if result.ProposalStatus != "" && result.ProposalStatus != string(contracts.APPROVED) {
+		return fmt.Errorf("proposal %s is %s: %s", result.ProposalID, result.ProposalStatus, result.EndReason)
+	}
+
+	return nil
+}
+
+// hasProposal checks whether the transaction submits a proposal
+func hasProposal(receipt *pb.Receipt) bool {
+	return gjson.Get(string(receipt.Ret), "proposal_id").String() != ""
+}
+
+// waitProposal polls the proposal until it is approved, rejected or paused or the wait timeout is reached,
+// a paused proposal is not voted any more until the object it is paused for is restored
+func waitProposal(ctx *cli.Context, id string) (*contracts.Proposal, error) {
+	deadline := time.Now().Add(ctx.GlobalDuration("wait-timeout"))
+	for {
+		proposals, err := getProposalsByConditions(ctx, "", "GetProposal", id)
+		if err != nil {
+			return nil, fmt.Errorf("get proposal %s error: %w", id, err)
+		}
+		if len(proposals) != 1 {
+			return nil, fmt.Errorf("proposal %s is not found", id)
+		}
+		if proposals[0].Status == contracts.APPROVED ||
+			proposals[0].Status == contracts.REJECTED ||
+			proposals[0].Status == contracts.PAUSED {
+			return &proposals[0], nil
+		}
+
+		if time.Now().Add(waitInterval).After(deadline) {
+			return nil, fmt.Errorf("wait for proposal %s timeout, it is still %s", id, proposals[0].Status)
+		}
+		fmt.Fprintf(os.Stderr, "proposal %s is %s... retry later\n", id, proposals[0].Status)
+		time.Sleep(waitInterval)
+	}
+}
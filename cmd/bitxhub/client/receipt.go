@@ -24,9 +24,7 @@ func getReceipt(ctx *cli.Context) error {
 		return err
 	}
 
-	fmt.Println(prettyJson(string(data)))
-
-	return nil
+	return printRawOutput(ctx, data)
 }
 
 func getTxReceipt(ctx *cli.Context, hash string) ([]byte, error) {
@@ -3,13 +3,14 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
 	"github.com/meshplus/bitxhub/internal/executor/contracts"
 	"github.com/meshplus/bitxhub/internal/repo"
-	"github.com/tidwall/gjson"
 	"github.com/urfave/cli"
 )
 
@@ -135,6 +136,138 @@ func roleMgrCMD() cli.Command {
 				},
 				Action: bindRole,
 			},
+			cli.Command{
+				Name:  "multisig",
+				Usage: "Multisig governance admin command",
+				Subcommands: cli.Commands{
+					cli.Command{
+						Name:  "register",
+						Usage: "Register a multisig governance admin",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "members",
+								Usage:    "Specify member addresses, multiple addresses are separated by commas",
+								Required: true,
+							},
+							cli.Uint64Flag{
+								Name:     "threshold",
+								Usage:    "Specify the number of members which must confirm a multisig tx",
+								Required: true,
+							},
+							cli.StringFlag{
+								Name:     "reason",
+								Usage:    "Specify register reason",
+								Required: false,
+							},
+						},
+						Action: registerMultiSigRole,
+					},
+					cli.Command{
+						Name:  "update",
+						Usage: "Update members of a multisig governance admin",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "id",
+								Usage:    "Specify role id",
+								Required: true,
+							},
+							cli.StringFlag{
+								Name:     "members",
+								Usage:    "Specify new member addresses, multiple addresses are separated by commas",
+								Required: true,
+							},
+							cli.Uint64Flag{
+								Name:     "threshold",
+								Usage:    "Specify new threshold",
+								Required: true,
+							},
+							cli.StringFlag{
+								Name:     "reason",
+								Usage:    "Specify update reason",
+								Required: false,
+							},
+						},
+						Action: updateMultiSigRole,
+					},
+					cli.Command{
+						Name:  "vote",
+						Usage: "Submit a vote on behalf of a multisig governance admin",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "id",
+								Usage:    "Specify role id",
+								Required: true,
+							},
+							cli.StringFlag{
+								Name:     "proposal",
+								Usage:    "Specify proposal id",
+								Required: true,
+							},
+							cli.StringFlag{
+								Name:     "info",
+								Usage:    "Specify voting information, approve or reject",
+								Required: true,
+							},
+							cli.StringFlag{
+								Name:     "reason",
+								Usage:    "Specify reason to vote",
+								Required: true,
+							},
+						},
+						Action: multiSigVote,
+					},
+					cli.Command{
+						Name:  "submit",
+						Usage: "Submit a bolt contract call on behalf of a multisig governance admin",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "id",
+								Usage:    "Specify role id",
+								Required: true,
+							},
+							cli.StringFlag{
+								Name:     "to",
+								Usage:    "Specify bolt contract address",
+								Required: true,
+							},
+							cli.StringFlag{
+								Name:     "method",
+								Usage:    "Specify contract method",
+								Required: true,
+							},
+							cli.StringSliceFlag{
+								Name:  "arg",
+								Usage: "Specify string args of the method in order",
+							},
+						},
+						Action: submitMultiSigTx,
+					},
+					cli.Command{
+						Name:  "confirm",
+						Usage: "Confirm a multisig tx",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "tx",
+								Usage:    "Specify multisig tx id",
+								Required: true,
+							},
+						},
+						Action: confirmMultiSigTx,
+					},
+					cli.Command{
+						Name:  "txs",
+						Usage: "Query multisig txs of a multisig governance admin",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:     "id",
+								Usage:    "Specify role id",
+								Required: true,
+							},
+						},
+						Action: getMultiSigTxs,
+					},
+				},
+			},
 		},
 	}
 }
@@ -147,16 +280,13 @@ func getRoleStatusById(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get role info by ID %s: %w", id, err)
 	}
 
-	if receipt.IsSuccess() {
-		role := &contracts.Role{}
-		if err := json.Unmarshal(receipt.Ret, role); err != nil {
-			return fmt.Errorf("unmarshal receipt error: %v", err)
-		}
-		color.Green("role %s is %s\n", role.ID, string(role.Status))
-	} else {
-		color.Red("get role status error: %s\n", string(receipt.Ret))
+	role := &contracts.Role{}
+	if err := json.Unmarshal(receipt.Ret, role); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %v", err)
 	}
-	return nil
+	return printOutput(ctx, &statusResult{ID: role.ID, Status: string(role.Status)}, func() {
+		color.Green("role %s is %s\n", role.ID, string(role.Status))
+	})
 }
 
 func registerRole(ctx *cli.Context) error {
@@ -171,13 +301,7 @@ func registerRole(ctx *cli.Context) error {
 			addr, typ, nodeAccount, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s\n", proposalId)
-	} else {
-		color.Red("register role error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printProposalResult(ctx, receipt)
 }
 
 func freezeRole(ctx *cli.Context) error {
@@ -189,13 +313,7 @@ func freezeRole(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when freeze role %s for %s: %w", id, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s\n", proposalId)
-	} else {
-		color.Red("freeze role error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printProposalResult(ctx, receipt)
 }
 
 func activateRole(ctx *cli.Context) error {
@@ -207,13 +325,7 @@ func activateRole(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when activate role %s for %s: %w", id, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s\n", proposalId)
-	} else {
-		color.Red("activate role error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printProposalResult(ctx, receipt)
 }
 
 func logoutRole(ctx *cli.Context) error {
@@ -225,13 +337,7 @@ func logoutRole(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when logout role %s for %s: %w", id, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s\n", proposalId)
-	} else {
-		color.Red("logout role error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printProposalResult(ctx, receipt)
 }
 
 func bindRole(ctx *cli.Context) error {
@@ -244,13 +350,7 @@ func bindRole(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when bind role %s with node %s for %s: %w", id, account, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s\n", proposalId)
-	} else {
-		color.Red("bind role error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printProposalResult(ctx, receipt)
 }
 
 func allRole(ctx *cli.Context) error {
@@ -259,23 +359,128 @@ func allRole(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get all roles: %w", err)
 	}
 
-	if ret.IsSuccess() {
-		roles := make([]*contracts.Role, 0)
-		if ret.Ret != nil {
-			if err := json.Unmarshal(ret.Ret, &roles); err != nil {
-				return fmt.Errorf("unmarshal roles error: %v", err)
-			}
+	roles := make([]*contracts.Role, 0)
+	if ret.Ret != nil {
+		if err := json.Unmarshal(ret.Ret, &roles); err != nil {
+			return fmt.Errorf("unmarshal roles error: %v", err)
 		}
+	}
+	return printOutput(ctx, roles, func() {
 		printRole(roles)
-	} else {
-		color.Red("query role info error: %s\n", string(ret.Ret))
+	})
+}
+
+func registerMultiSigRole(ctx *cli.Context) error {
+	members := ctx.String("members")
+	threshold := ctx.Uint64("threshold")
+	reason := ctx.String("reason")
+
+	receipt, err := invokeBVMContract(ctx, constant.RoleContractAddr.Address().String(), "RegisterMultiSigRole", pb.String(members), pb.Uint64(threshold), pb.String(reason))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when register multisig role \" members=%s,threshold=%d,reason=%s \": %w",
+			members, threshold, reason, err)
 	}
-	return nil
+
+	return printProposalResultWithExtra(ctx, receipt, "multisig role id")
+}
+
+func updateMultiSigRole(ctx *cli.Context) error {
+	id := ctx.String("id")
+	members := ctx.String("members")
+	threshold := ctx.Uint64("threshold")
+	reason := ctx.String("reason")
+
+	receipt, err := invokeBVMContract(ctx, constant.RoleContractAddr.Address().String(), "UpdateMultiSigMembers", pb.String(id), pb.String(members), pb.Uint64(threshold), pb.String(reason))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when update multisig role %s: %w", id, err)
+	}
+
+	return printProposalResult(ctx, receipt)
+}
+
+func multiSigVote(ctx *cli.Context) error {
+	args := []*pb.Arg{pb.String(ctx.String("proposal")), pb.String(ctx.String("info")), pb.String(ctx.String("reason"))}
+	return sendMultiSigTx(ctx, ctx.String("id"), constant.GovernanceContractAddr.Address().String(), "Vote", args)
+}
+
+func submitMultiSigTx(ctx *cli.Context) error {
+	var args []*pb.Arg
+	for _, arg := range ctx.StringSlice("arg") {
+		args = append(args, pb.String(arg))
+	}
+	return sendMultiSigTx(ctx, ctx.String("id"), ctx.String("to"), ctx.String("method"), args)
+}
+
+func sendMultiSigTx(ctx *cli.Context, id, to, method string, args []*pb.Arg) error {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("marshal args error: %w", err)
+	}
+
+	receipt, err := invokeBVMContract(ctx, constant.RoleContractAddr.Address().String(), "SubmitMultiSigTx", pb.String(id), pb.String(to), pb.String(method), pb.Bytes(data))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when submit multisig tx for role %s: %w", id, err)
+	}
+
+	return printMultiSigTxReceipt(ctx, receipt)
+}
+
+func confirmMultiSigTx(ctx *cli.Context) error {
+	txId := ctx.String("tx")
+
+	receipt, err := invokeBVMContract(ctx, constant.RoleContractAddr.Address().String(), "ConfirmMultiSigTx", pb.String(txId))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when confirm multisig tx %s: %w", txId, err)
+	}
+
+	return printMultiSigTxReceipt(ctx, receipt)
+}
+
+func printMultiSigTxReceipt(ctx *cli.Context, receipt *pb.Receipt) error {
+	tx := &contracts.MultiSigTx{}
+	if err := json.Unmarshal(receipt.Ret, tx); err != nil {
+		return fmt.Errorf("unmarshal multisig tx error: %w", err)
+	}
+	return printOutput(ctx, tx, func() {
+		if tx.Executed {
+			color.Green("multisig tx %s is executed\n", tx.ID)
+		} else {
+			color.Green("multisig tx %s is waiting for confirmations, confirmed by %s\n", tx.ID, strings.Join(tx.Confirmations, ","))
+		}
+	})
+}
+
+func getMultiSigTxs(ctx *cli.Context) error {
+	id := ctx.String("id")
+
+	receipt, err := invokeBVMContractBySendView(ctx, constant.RoleContractAddr.Address().String(), "GetMultiSigTxsByRole", pb.String(id))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get multisig txs of role %s: %w", id, err)
+	}
+
+	txs := make([]*contracts.MultiSigTx, 0)
+	if err := json.Unmarshal(receipt.Ret, &txs); err != nil {
+		return fmt.Errorf("unmarshal multisig txs error: %v", err)
+	}
+	var table [][]string
+	table = append(table, []string{"Id", "To", "Method", "Confirmations", "Executed"})
+	for _, tx := range txs {
+		table = append(table, []string{
+			tx.ID,
+			tx.To,
+			tx.Method,
+			strings.Join(tx.Confirmations, ","),
+			strconv.FormatBool(tx.Executed),
+		})
+	}
+	return printOutput(ctx, txs, func() {
+		PrintTable(table, true)
+	})
 }
 
 func printRole(roles []*contracts.Role) {
 	var table [][]string
-	table = append(table, []string{"RoleId", "type", "Status", "NodeAccount", "AppchainID"})
+	table = append(table, []string{"RoleId", "type", "Status", "NodeAccount", "AppchainID", "MultiSig"})
 
 	for _, r := range roles {
 		var typ string
@@ -285,12 +490,17 @@ func printRole(roles []*contracts.Role) {
 			typ = string(r.RoleType)
 
 		}
+		var multiSig string
+		if r.IsMultiSig() {
+			multiSig = fmt.Sprintf("%d/%d", r.Threshold, len(r.Members))
+		}
 		table = append(table, []string{
 			r.ID,
 			typ,
 			string(r.Status),
 			r.NodeAccount,
 			r.AppchainID,
+			multiSig,
 		})
 	}
 
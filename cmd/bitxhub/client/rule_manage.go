@@ -3,13 +3,14 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"strconv"
 
 	"github.com/fatih/color"
 	ruleMgr "github.com/meshplus/bitxhub-core/rule-mgr"
 	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
-	"github.com/tidwall/gjson"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
 	"github.com/urfave/cli"
 )
 
@@ -81,6 +82,40 @@ func ruleMgrCMD() cli.Command {
 				},
 				Action: getRuleStatus,
 			},
+			cli.Command{
+				Name:  "upgrade",
+				Usage: "Upgrade the code of wasm contract in place, governance admins submit a proposal if they are not the owner",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "addr",
+						Usage:    "Specify wasm contract addr",
+						Required: true,
+					},
+					cli.StringFlag{
+						Name:     "path",
+						Usage:    "Specify new wasm code path",
+						Required: true,
+					},
+					cli.StringFlag{
+						Name:     "reason",
+						Usage:    "upgrade reason",
+						Required: false,
+					},
+				},
+				Action: upgradeCode,
+			},
+			cli.Command{
+				Name:  "history",
+				Usage: "Query code history of wasm contract",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "addr",
+						Usage:    "Specify wasm contract addr",
+						Required: true,
+					},
+				},
+				Action: getCodeHistory,
+			},
 		},
 	}
 }
@@ -93,18 +128,15 @@ func getRulesList(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get rules list: %w", err)
 	}
 
-	if receipt.IsSuccess() {
-		rules := make([]*ruleMgr.Rule, 0)
-		if receipt.Ret != nil {
-			if err := json.Unmarshal(receipt.Ret, &rules); err != nil {
-				return fmt.Errorf("unmarshal rules error: %w", err)
-			}
+	rules := make([]*ruleMgr.Rule, 0)
+	if receipt.Ret != nil {
+		if err := json.Unmarshal(receipt.Ret, &rules); err != nil {
+			return fmt.Errorf("unmarshal rules error: %w", err)
 		}
-		printRule(rules)
-	} else {
-		color.Red("get rules error: %s\n", string(receipt.Ret))
 	}
-	return nil
+	return printOutput(ctx, rules, func() {
+		printRule(rules)
+	})
 }
 
 func getMasterRuleAddress(ctx *cli.Context) error {
@@ -119,12 +151,9 @@ func getMasterRuleAddress(ctx *cli.Context) error {
 		return err
 	}
 
-	if receipt.IsSuccess() {
+	return printOutput(ctx, rule, func() {
 		color.Green("available rule address is %s", rule.Address)
-	} else {
-		color.Red("get available rule address error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	})
 }
 
 func getRuleStatus(ctx *cli.Context) error {
@@ -136,16 +165,13 @@ func getRuleStatus(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get rule %s for chain %s: %w", ruleAddr, chainId, err)
 	}
 
-	if receipt.IsSuccess() {
-		rule := &ruleMgr.Rule{}
-		if err := json.Unmarshal(receipt.Ret, rule); err != nil {
-			return fmt.Errorf("unmarshal receipt error: %w", err)
-		}
-		color.Green("the rule %s is %s", ruleAddr, string(rule.Status))
-	} else {
-		color.Red("get rule status error: %s\n", string(receipt.Ret))
+	rule := &ruleMgr.Rule{}
+	if err := json.Unmarshal(receipt.Ret, rule); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
 	}
-	return nil
+	return printOutput(ctx, &statusResult{ID: ruleAddr, Status: string(rule.Status)}, func() {
+		color.Green("the rule %s is %s", ruleAddr, string(rule.Status))
+	})
 }
 
 func updateRule(ctx *cli.Context) error {
@@ -159,13 +185,55 @@ func updateRule(ctx *cli.Context) error {
 			id, addr, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s", proposalId)
-	} else {
-		color.Red("update rule error: %s\n", string(receipt.Ret))
+	return printProposalResult(ctx, receipt)
+}
+
+func upgradeCode(ctx *cli.Context) error {
+	addr := ctx.String("addr")
+	path := ctx.String("path")
+	reason := ctx.String("reason")
+
+	code, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read wasm code %s error: %w", path, err)
 	}
-	return nil
+
+	receipt, err := invokeBVMContract(ctx, constant.RuleManagerContractAddr.String(), "UpgradeCode", pb.String(addr), pb.Bytes(code), pb.String(reason))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when upgrade code of %s: %w", addr, err)
+	}
+
+	if !hasProposal(receipt) {
+		return printTxResult(ctx, receipt, "upgrade code of %s successfully", addr)
+	}
+	return printProposalResult(ctx, receipt)
+}
+
+func getCodeHistory(ctx *cli.Context) error {
+	addr := ctx.String("addr")
+
+	receipt, err := invokeBVMContractBySendView(ctx, constant.RuleManagerContractAddr.String(), "GetCodeHistory", pb.String(addr))
+	if err != nil {
+		return fmt.Errorf("invoke BVM contract failed when get code history of %s: %w", addr, err)
+	}
+
+	history := make([]*contracts.CodeRecord, 0)
+	if err := json.Unmarshal(receipt.Ret, &history); err != nil {
+		return fmt.Errorf("unmarshal code history error: %w", err)
+	}
+	return printOutput(ctx, history, func() {
+		var table [][]string
+		table = append(table, []string{"Version", "CodeHash", "Operator", "Timestamp"})
+		for _, r := range history {
+			table = append(table, []string{
+				strconv.FormatUint(r.Version, 10),
+				r.CodeHash,
+				r.Operator,
+				strconv.FormatInt(r.Timestamp, 10),
+			})
+		}
+		PrintTable(table, true)
+	})
 }
 
 func printRule(rules []*ruleMgr.Rule) {
@@ -9,7 +9,6 @@ import (
 	service_mgr "github.com/meshplus/bitxhub-core/service-mgr"
 	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
-	"github.com/tidwall/gjson"
 	"github.com/urfave/cli"
 )
 
@@ -24,7 +23,7 @@ func serviceMgrCMD() cli.Command {
 				Flags: []cli.Flag{
 					cli.StringFlag{
 						Name:     "id",
-						Usage:    "Specify chainService id",
+						Usage:    "Specify chainService id or BNS domain name",
 						Required: true,
 					},
 				},
@@ -48,7 +47,7 @@ func serviceMgrCMD() cli.Command {
 				Flags: []cli.Flag{
 					cli.StringFlag{
 						Name:     "id",
-						Usage:    "Specify chainService id",
+						Usage:    "Specify chainService id or BNS domain name",
 						Required: true,
 					},
 					cli.StringFlag{
@@ -65,7 +64,7 @@ func serviceMgrCMD() cli.Command {
 				Flags: []cli.Flag{
 					cli.StringFlag{
 						Name:     "id",
-						Usage:    "Specify chainService id",
+						Usage:    "Specify chainService id or BNS domain name",
 						Required: true,
 					},
 					cli.StringFlag{
@@ -82,7 +81,7 @@ func serviceMgrCMD() cli.Command {
 				Flags: []cli.Flag{
 					cli.StringFlag{
 						Name:     "id",
-						Usage:    "Specify service id",
+						Usage:    "Specify service id or BNS domain name",
 						Required: true,
 					},
 					cli.StringFlag{
@@ -102,8 +101,18 @@ func serviceMgrCMD() cli.Command {
 	}
 }
 
+// serviceStatusResult is the output of querying service status
+type serviceStatusResult struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	Available bool   `json:"available"`
+}
+
 func getServiceStatusById(ctx *cli.Context) error {
-	id := ctx.String("id")
+	id, err := resolveChainServiceID(ctx, ctx.String("id"))
+	if err != nil {
+		return err
+	}
 
 	receipt, err := invokeBVMContractBySendView(ctx, constant.ServiceMgrContractAddr.String(), "GetServiceInfo", pb.String(id))
 	if err != nil {
@@ -115,16 +124,18 @@ func getServiceStatusById(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get service status by id %s: %w", id, err)
 	}
 
-	if receipt.IsSuccess() && receipt1.IsSuccess() {
-		service := &service_mgr.Service{}
-		if err := json.Unmarshal(receipt.Ret, service); err != nil {
-			return fmt.Errorf("unmarshal receipt error: %w", err)
-		}
-		color.Green("service %s is %s, Available is %s", fmt.Sprintf("%s:%s", service.ChainID, service.ServiceID), string(service.Status), string(receipt1.Ret))
-	} else {
-		color.Red("get service status error: %s\n", string(receipt.Ret))
+	service := &service_mgr.Service{}
+	if err := json.Unmarshal(receipt.Ret, service); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
 	}
-	return nil
+	result := &serviceStatusResult{
+		ID:        fmt.Sprintf("%s:%s", service.ChainID, service.ServiceID),
+		Status:    string(service.Status),
+		Available: string(receipt1.Ret) == "true",
+	}
+	return printOutput(ctx, result, func() {
+		color.Green("service %s is %s, Available is %s", result.ID, result.Status, string(receipt1.Ret))
+	})
 }
 
 func getServiceByChainID(ctx *cli.Context) error {
@@ -135,20 +146,20 @@ func getServiceByChainID(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when get service by appchainID %s: %w", chainID, err)
 	}
 
-	if receipt.IsSuccess() {
-		var services []*service_mgr.Service
-		if err := json.Unmarshal(receipt.Ret, &services); err != nil {
-			return fmt.Errorf("unmarshal receipt error: %w", err)
-		}
-		printService(services)
-	} else {
-		color.Red("get service status error: %s\n", string(receipt.Ret))
+	var services []*service_mgr.Service
+	if err := json.Unmarshal(receipt.Ret, &services); err != nil {
+		return fmt.Errorf("unmarshal receipt error: %w", err)
 	}
-	return nil
+	return printOutput(ctx, services, func() {
+		printService(services)
+	})
 }
 
 func freezeService(ctx *cli.Context) error {
-	id := ctx.String("id")
+	id, err := resolveChainServiceID(ctx, ctx.String("id"))
+	if err != nil {
+		return err
+	}
 	reason := ctx.String("reason")
 
 	receipt, err := invokeBVMContract(ctx, constant.ServiceMgrContractAddr.String(), "FreezeService", pb.String(id), pb.String(reason))
@@ -156,17 +167,14 @@ func freezeService(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when freeze service %s for %s: %w", id, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s", proposalId)
-	} else {
-		color.Red("freeze service error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printProposalResult(ctx, receipt)
 }
 
 func activateService(ctx *cli.Context) error {
-	id := ctx.String("id")
+	id, err := resolveChainServiceID(ctx, ctx.String("id"))
+	if err != nil {
+		return err
+	}
 	reason := ctx.String("reason")
 
 	receipt, err := invokeBVMContract(ctx, constant.ServiceMgrContractAddr.String(), "ActivateService", pb.String(id), pb.String(reason))
@@ -174,17 +182,14 @@ func activateService(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when activate service %s for %s: %w", id, reason, err)
 	}
 
-	if receipt.IsSuccess() {
-		proposalId := gjson.Get(string(receipt.Ret), "proposal_id").String()
-		color.Green("proposal id is %s", proposalId)
-	} else {
-		color.Red("activate service error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printProposalResult(ctx, receipt)
 }
 
 func evaluateService(ctx *cli.Context) error {
-	id := ctx.String("id")
+	id, err := resolveChainServiceID(ctx, ctx.String("id"))
+	if err != nil {
+		return err
+	}
 	desc := ctx.String("desc")
 	score := ctx.Float64("score")
 
@@ -193,12 +198,7 @@ func evaluateService(ctx *cli.Context) error {
 		return fmt.Errorf("invoke BVM contract failed when evaluate service %s to score %f for %s: %w", id, score, desc, err)
 	}
 
-	if receipt.IsSuccess() {
-		color.Green("evaluate service success")
-	} else {
-		color.Red("evaluate service error: %s\n", string(receipt.Ret))
-	}
-	return nil
+	return printTxResult(ctx, receipt, "evaluate service success")
 }
 
 func printService(services []*service_mgr.Service) {
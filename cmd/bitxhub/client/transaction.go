@@ -1,21 +1,33 @@
 package client
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"math/big"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/meshplus/bitxhub-kit/crypto"
 	"github.com/meshplus/bitxhub-kit/types"
 	"github.com/meshplus/bitxhub-model/pb"
+	grpcproto "github.com/meshplus/bitxhub/api/grpc/proto"
 	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/meshplus/bitxhub/pkg/signer"
+	"github.com/spf13/viper"
+	"github.com/tidwall/gjson"
 	"github.com/urfave/cli"
 )
 
 const (
-	sendTx   = "transaction"
-	sendView = "view"
+	sendTx      = "transaction"
+	sendView    = "view"
+	simulateTx  = "simulate"
+	simulateEth = "simulate/eth"
 )
 
 func txCMD() cli.Command {
@@ -23,6 +35,60 @@ func txCMD() cli.Command {
 		Name:   "tx",
 		Usage:  "Query transaction by transaction hash",
 		Action: getTransaction,
+		Subcommands: []cli.Command{
+			{
+				Name:  "simulate",
+				Usage: "Execute transaction against a copy of the latest state and show the receipt, events, gas and state diff",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "key",
+						Usage:    "Specify sender private key path, the key of the repo is used by default",
+						Required: false,
+					},
+					cli.StringFlag{
+						Name:     "to",
+						Usage:    "Specify target address or BNS domain name, leave it empty to deploy evm contract",
+						Required: false,
+					},
+					cli.StringFlag{
+						Name:  "vm",
+						Usage: "Specify vm type, one of bvm, wasm and evm",
+						Value: "bvm",
+					},
+					cli.StringFlag{
+						Name:     "method",
+						Usage:    "Specify method of bvm or wasm contract, transfer is simulated if it is empty",
+						Required: false,
+					},
+					cli.StringSliceFlag{
+						Name:     "arg",
+						Usage:    "Specify argument of bvm or wasm contract in the form of type:value, type is one of string, bytes(hex), bool, int32, int64, uint32, uint64 and float64",
+						Required: false,
+					},
+					cli.StringFlag{
+						Name:     "data",
+						Usage:    "Specify hex input data of evm transaction",
+						Required: false,
+					},
+					cli.StringFlag{
+						Name:  "amount",
+						Usage: "Specify transfer amount",
+						Value: "0",
+					},
+					cli.Uint64Flag{
+						Name:  "gas",
+						Usage: "Specify gas limit of evm transaction",
+						Value: 1000000,
+					},
+					cli.StringFlag{
+						Name:  "gas-price",
+						Usage: "Specify gas price of evm transaction",
+						Value: "50000",
+					},
+				},
+				Action: simulateTransaction,
+			},
+		},
 	}
 
 }
@@ -41,19 +107,18 @@ func getTransaction(ctx *cli.Context) error {
 		return fmt.Errorf("get transaction %s failed: %w", hash, err)
 	}
 
-	fmt.Println(string(data))
-
-	return nil
+	return printRawOutput(ctx, data)
 }
 
 func sendTxOrView(ctx *cli.Context, sendType, toString string, amount *big.Int, txType uint64, keyPath string, vmType uint64, method string, args ...*pb.Arg) ([]byte, error) {
 
-	key, err := repo.LoadKey(keyPath)
+	privKey, err := loadSigner(ctx, keyPath)
 	if err != nil {
 		return nil, fmt.Errorf("wrong key: %w", err)
 	}
+	defer privKey.Close()
 
-	from, err := key.PrivKey.PublicKey().Address()
+	from, err := privKey.PublicKey().Address()
 	if err != nil {
 		return nil, fmt.Errorf("wrong private key: %w", err)
 	}
@@ -80,42 +145,194 @@ func sendTxOrView(ctx *cli.Context, sendType, toString string, amount *big.Int,
 		return nil, fmt.Errorf("marshal transaction data error: %w", err)
 	}
 
-	getNonceUrl := getURL(ctx, fmt.Sprintf("pendingNonce/%s", from.String()))
+	nonce, err := getPendingNonce(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &pb.BxhTransaction{
+		From:      from,
+		To:        to,
+		Timestamp: time.Now().UnixNano(),
+		Nonce:     nonce,
+		Payload:   payload,
+	}
+
+	if err := tx.Sign(privKey); err != nil {
+		return nil, fmt.Errorf("sign tx error: %s", err)
+	}
+
+	reqData, err := json.Marshal(tx)
+	if err != nil {
+		return nil, fmt.Errorf("marshal tx error: %w", err)
+	}
+
+	url := getURL(ctx, sendType)
+
+	resp, err := httpPost(ctx, url, reqData)
+	if err != nil {
+		return nil, fmt.Errorf("httpPost %s to url %s failed: %w", reqData, url, err)
+	}
+
+	return resp, nil
+}
+
+func getPendingNonce(ctx *cli.Context, addr *types.Address) (uint64, error) {
+	getNonceUrl := getURL(ctx, fmt.Sprintf("pendingNonce/%s", addr.String()))
 
 	encodedNonce, err := httpGet(ctx, getNonceUrl)
 	if err != nil {
-		return nil, fmt.Errorf("httpGet from url %s failed: %w", getNonceUrl, err)
+		return 0, fmt.Errorf("httpGet from url %s failed: %w", getNonceUrl, err)
 	}
 
 	ret, err := parseResponse(encodedNonce)
 	if err != nil {
-		return nil, fmt.Errorf("wrong response: %w", err)
+		return 0, fmt.Errorf("wrong response: %w", err)
 	}
 
 	nonce, err := strconv.ParseUint(ret, 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("parse pending nonce :%w", err)
+		return 0, fmt.Errorf("parse pending nonce :%w", err)
 	}
 
-	tx := &pb.BxhTransaction{
-		From:      from,
-		To:        to,
-		Timestamp: time.Now().UnixNano(),
-		Nonce:     nonce,
-		Payload:   payload,
+	return nonce, nil
+}
+
+func simulateTransaction(ctx *cli.Context) error {
+	keyPath := ctx.String("key")
+	if keyPath == "" {
+		repoRoot, err := repo.PathRootWithDefault(ctx.GlobalString("repo"))
+		if err != nil {
+			return fmt.Errorf("pathRootWithDefault error: %w", err)
+		}
+		keyPath = repo.GetKeyPath(repoRoot)
 	}
 
-	if err := tx.Sign(key.PrivKey); err != nil {
-		return nil, fmt.Errorf("sign tx error: %s", err)
+	to := ctx.String("to")
+	if to != "" {
+		var err error
+		if to, err = resolveAddress(ctx, to); err != nil {
+			return err
+		}
 	}
 
-	reqData, err := json.Marshal(tx)
+	amount, ok := new(big.Int).SetString(ctx.String("amount"), 10)
+	if !ok {
+		return fmt.Errorf("invalid amount")
+	}
+
+	var (
+		resp []byte
+		err  error
+	)
+	switch vm := ctx.String("vm"); vm {
+	case "bvm", "wasm":
+		if to == "" {
+			return fmt.Errorf("target address is required by %s transaction", vm)
+		}
+		args, err := parseArgs(ctx.StringSlice("arg"))
+		if err != nil {
+			return err
+		}
+		txType := pb.TransactionData_INVOKE
+		if ctx.String("method") == "" {
+			txType = pb.TransactionData_NORMAL
+		}
+		vmType := pb.TransactionData_BVM
+		if vm == "wasm" {
+			vmType = pb.TransactionData_XVM
+		}
+		resp, err = sendTxOrView(ctx, simulateTx, to, amount, uint64(txType), keyPath, uint64(vmType), ctx.String("method"), args...)
+		if err != nil {
+			return fmt.Errorf("simulate transaction: %w", err)
+		}
+	case "evm":
+		resp, err = simulateEthTransaction(ctx, keyPath, to, amount)
+		if err != nil {
+			return fmt.Errorf("simulate transaction: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported vm type %s", vm)
+	}
+
+	// the simulation result always has the receipt, otherwise the response is an error
+	if !gjson.GetBytes(resp, "receipt").Exists() {
+		return fmt.Errorf("simulate transaction: %s", string(resp))
+	}
+
+	return printRawOutput(ctx, resp)
+}
+
+// simulateEthTransaction signs the legacy eth transaction by the signer, which must be a secp256k1 key
+func simulateEthTransaction(ctx *cli.Context, keyPath, to string, amount *big.Int) ([]byte, error) {
+	privKey, err := loadSigner(ctx, keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("wrong key: %w", err)
+	}
+	defer privKey.Close()
+
+	if privKey.Type() != crypto.Secp256k1 {
+		return nil, fmt.Errorf("evm transaction can only be signed by secp256k1 key")
+	}
+	from, err := privKey.PublicKey().Address()
+	if err != nil {
+		return nil, fmt.Errorf("wrong private key: %w", err)
+	}
+
+	data, err := hexutil.Decode(ctx.String("data"))
+	if err != nil && ctx.String("data") != "" {
+		return nil, fmt.Errorf("invalid data: %w", err)
+	}
+	gasPrice, ok := new(big.Int).SetString(ctx.String("gas-price"), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid gas price")
+	}
+	nonce, err := getPendingNonce(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := getChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	txData := &ethtypes.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Gas:      ctx.Uint64("gas"),
+		Value:    amount,
+		Data:     data,
+	}
+	if to != "" {
+		if !common.IsHexAddress(to) {
+			return nil, fmt.Errorf("invalid target address %s", to)
+		}
+		addr := common.HexToAddress(to)
+		txData.To = &addr
+	}
+
+	ethSigner := ethtypes.NewEIP155Signer(chainID)
+	tx := ethtypes.NewTx(txData)
+	hash := ethSigner.Hash(tx)
+	sig, err := privKey.Sign(hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("sign tx error: %w", err)
+	}
+	tx, err = tx.WithSignature(ethSigner, sig)
+	if err != nil {
+		return nil, fmt.Errorf("sign tx error: %w", err)
+	}
+	raw, err := tx.MarshalBinary()
 	if err != nil {
 		return nil, fmt.Errorf("marshal tx error: %w", err)
 	}
 
-	url := getURL(ctx, sendType)
+	reqData, err := json.Marshal(&grpcproto.SimulateEthTransactionRequest{RawTx: raw})
+	if err != nil {
+		return nil, fmt.Errorf("marshal tx error: %w", err)
+	}
 
+	url := getURL(ctx, simulateEth)
 	resp, err := httpPost(ctx, url, reqData)
 	if err != nil {
 		return nil, fmt.Errorf("httpPost %s to url %s failed: %w", reqData, url, err)
@@ -123,3 +340,104 @@ func sendTxOrView(ctx *cli.Context, sendType, toString string, amount *big.Int,
 
 	return resp, nil
 }
+
+func getChainID(ctx *cli.Context) (*big.Int, error) {
+	url := getURL(ctx, "get_chain_id")
+	resp, err := httpPost(ctx, url, []byte("{}"))
+	if err != nil {
+		return nil, fmt.Errorf("httpPost to url %s failed: %w", url, err)
+	}
+
+	ret, err := parseResponse(resp)
+	if err != nil {
+		return nil, fmt.Errorf("wrong response: %w", err)
+	}
+	if len(ret) != 8 {
+		return nil, fmt.Errorf("wrong chain id: %s", string(resp))
+	}
+
+	return new(big.Int).SetUint64(binary.LittleEndian.Uint64([]byte(ret))), nil
+}
+
+// parseArgs parses contract arguments in the form of type:value
+func parseArgs(args []string) ([]*pb.Arg, error) {
+	ret := make([]*pb.Arg, 0, len(args))
+	for _, arg := range args {
+		idx := strings.Index(arg, ":")
+		if idx == -1 {
+			return nil, fmt.Errorf("argument %s is not in the form of type:value", arg)
+		}
+		typ, value := arg[:idx], arg[idx+1:]
+
+		var err error
+		switch typ {
+		case "string":
+			ret = append(ret, pb.String(value))
+		case "bytes":
+			var data []byte
+			if data, err = hexutil.Decode(value); err == nil {
+				ret = append(ret, pb.Bytes(data))
+			}
+		case "bool":
+			var b bool
+			if b, err = strconv.ParseBool(value); err == nil {
+				ret = append(ret, pb.Bool(b))
+			}
+		case "int32":
+			var i int64
+			if i, err = strconv.ParseInt(value, 10, 32); err == nil {
+				ret = append(ret, pb.Int32(int32(i)))
+			}
+		case "int64":
+			var i int64
+			if i, err = strconv.ParseInt(value, 10, 64); err == nil {
+				ret = append(ret, pb.Int64(i))
+			}
+		case "uint32":
+			var i uint64
+			if i, err = strconv.ParseUint(value, 10, 32); err == nil {
+				ret = append(ret, pb.Uint32(uint32(i)))
+			}
+		case "uint64":
+			var i uint64
+			if i, err = strconv.ParseUint(value, 10, 64); err == nil {
+				ret = append(ret, pb.Uint64(i))
+			}
+		case "float64":
+			var f float64
+			if f, err = strconv.ParseFloat(value, 64); err == nil {
+				ret = append(ret, pb.Float64(f))
+			}
+		default:
+			return nil, fmt.Errorf("unsupported argument type %s", typ)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %s: %w", arg, err)
+		}
+	}
+
+	return ret, nil
+}
+
+// loadSigner loads the signer of the admin, which is the key file, or the signer configured
+// in bitxhub.toml of the repo if the signer flag is set
+func loadSigner(ctx *cli.Context, keyPath string) (signer.Signer, error) {
+	if !ctx.GlobalBool("signer") {
+		key, err := repo.LoadKey(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		return key.PrivKey, nil
+	}
+
+	repoRoot, err := repo.PathRootWithDefault(ctx.GlobalString("repo"))
+	if err != nil {
+		return nil, fmt.Errorf("pathRootWithDefault error: %w", err)
+	}
+	config, err := repo.UnmarshalConfig(viper.New(), repoRoot, "")
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal bitxhub config error: %w", err)
+	}
+
+	return repo.LoadSigner(repoRoot, "", config.Signer)
+}
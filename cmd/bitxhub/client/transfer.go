@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/meshplus/bitxhub-model/pb"
 	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/tidwall/gjson"
 	"github.com/urfave/cli"
 )
 
@@ -20,7 +23,7 @@ func transferCMD() cli.Command {
 			},
 			cli.StringFlag{
 				Name:     "to",
-				Usage:    "Specify transfer target address",
+				Usage:    "Specify transfer target address or BNS domain name",
 				Required: true,
 			},
 			cli.StringFlag{
@@ -35,7 +38,10 @@ func transferCMD() cli.Command {
 }
 
 func transferBalance(ctx *cli.Context) error {
-	toString := ctx.String("to")
+	toString, err := resolveAddress(ctx, ctx.String("to"))
+	if err != nil {
+		return err
+	}
 	amountStr := ctx.String("amount")
 	keyPath := ctx.String("key")
 	var txType uint64 = 0
@@ -59,6 +65,25 @@ func transferBalance(ctx *cli.Context) error {
 		return fmt.Errorf("send transaction: %w", err)
 	}
 
-	fmt.Println(string(resp))
+	if !ctx.GlobalBool("wait") {
+		return printRawOutput(ctx, resp)
+	}
+
+	data, err := waitReceipt(ctx, gjson.GetBytes(resp, "tx_hash").String())
+	if err != nil {
+		return err
+	}
+	m := &runtime.JSONPb{OrigName: true, EmitDefaults: false, EnumsAsInts: true}
+	receipt := &pb.Receipt{}
+	if err := m.Unmarshal(data, receipt); err != nil {
+		return fmt.Errorf("jsonpb unmarshal receipt error: %w", err)
+	}
+	if err := printRawOutput(ctx, data); err != nil {
+		return err
+	}
+	if !receipt.IsSuccess() {
+		return fmt.Errorf("transfer failed: %s", string(receipt.Ret))
+	}
+
 	return nil
 }
@@ -1,15 +1,20 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/meshplus/bitxhub-kit/crypto"
 	"github.com/meshplus/bitxhub-kit/crypto/asym"
 	"github.com/meshplus/bitxhub-kit/fileutil"
 	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/meshplus/bitxhub/pkg/signer"
 	"github.com/urfave/cli"
 )
 
@@ -33,7 +38,7 @@ func keyCMD() cli.Command {
 					},
 					cli.StringFlag{
 						Name:     "algo",
-						Usage:    "Specify crypto algorithm",
+						Usage:    "Specify crypto algorithm, one of Secp256k1, ECDSA_P256, ECDSA_P384, ECDSA_P521 and SM2",
 						Value:    "Secp256k1",
 						Required: false,
 					},
@@ -74,6 +79,28 @@ func keyCMD() cli.Command {
 				},
 				Action: getAddress,
 			},
+			{
+				Name:  "serve",
+				Usage: "Serve BitXHub private key as a remote signer on the unix socket",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "path",
+						Usage:    "Specify private key path",
+						Required: true,
+					},
+					cli.StringFlag{
+						Name:     "passwd",
+						Usage:    "Specify password",
+						Required: false,
+					},
+					cli.StringFlag{
+						Name:     "socket",
+						Usage:    "Specify unix socket path",
+						Required: true,
+					},
+				},
+				Action: serveKey,
+			},
 		},
 	}
 }
@@ -174,3 +201,49 @@ func getAddress(ctx *cli.Context) error {
 
 	return nil
 }
+
+func serveKey(ctx *cli.Context) error {
+	privPath := ctx.String("path")
+	passwd := ctx.String("passwd")
+	socket := ctx.String("socket")
+	if passwd == "" {
+		passwd = repo.DefaultPasswd
+	}
+
+	privKey, err := asym.RestorePrivateKey(privPath, passwd)
+	if err != nil {
+		return fmt.Errorf("restore private key failed: %w", err)
+	}
+
+	// remove the socket left by the last run
+	if info, err := os.Stat(socket); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return fmt.Errorf("%s exists and is not a socket", socket)
+		}
+		if err := os.Remove(socket); err != nil {
+			return fmt.Errorf("remove socket %s: %w", socket, err)
+		}
+	}
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", socket, err)
+	}
+	if err := os.Chmod(socket, 0600); err != nil {
+		_ = l.Close()
+		return fmt.Errorf("chmod socket %s: %w", socket, err)
+	}
+
+	var stop = make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		<-stop
+		_ = l.Close()
+	}()
+
+	fmt.Printf("remote signer is listening on %s\n", socket)
+	if err := signer.ServeRemote(l, privKey); err != nil && !errors.Is(err, net.ErrClosed) {
+		return fmt.Errorf("serve remote signer: %w", err)
+	}
+
+	return nil
+}
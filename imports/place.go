@@ -2,6 +2,7 @@ package imports
 
 import (
 	_ "github.com/meshplus/bitxhub/pkg/order/etcdraft"
+	_ "github.com/meshplus/bitxhub/pkg/order/follower"
 	_ "github.com/meshplus/bitxhub/pkg/order/smart_bft"
 	_ "github.com/meshplus/bitxhub/pkg/order/solo"
 )
@@ -138,6 +138,10 @@ func GenerateBitXHubWithoutOrder(rep *repo.Repo) (*BitXHub, error) {
 	}
 
 	supportCryptoTypeToName := asym.GetConfiguredKeyType()
+	// blocks, IBTPs and transactions of the node are signed by the node key
+	if _, ok := supportCryptoTypeToName[rep.Key.PrivKey.Type()]; !ok {
+		return nil, fmt.Errorf("key type %d of node is not in configured crypto algorithms", rep.Key.PrivKey.Type())
+	}
 	printType := "Supported crypto type:"
 	for _, name := range supportCryptoTypeToName {
 		printType = fmt.Sprintf("%s%s ", printType, name)
@@ -215,6 +219,7 @@ func GenerateBitXHubWithoutOrder(rep *repo.Repo) (*BitXHub, error) {
 	if err != nil {
 		return nil, fmt.Errorf("create peer manager: %w", err)
 	}
+	peerMgr.WatchNodeEvent(txExec)
 
 	tssMgr := &tssmgr.TssMgr{}
 	if rep.Config.Tss.EnableTSS {
@@ -347,6 +352,10 @@ func (bxh *BitXHub) Stop() error {
 
 	bxh.Cancel()
 
+	if err := bxh.repo.Key.PrivKey.Close(); err != nil {
+		bxh.logger.Errorf("close signer: %v", err)
+	}
+
 	bxh.logger.Info("Bitxhub stopped")
 
 	return nil
@@ -387,6 +396,11 @@ func (bxh *BitXHub) ReConfig(repo *repo.Repo) {
 			bxh.logger.Errorf("reconfig PeerMgr failed: %v", err)
 		}
 	}
+	if repo.Certs != nil {
+		if err := bxh.PeerMgr.ReConfig(repo.Certs); err != nil {
+			bxh.logger.Errorf("reconfig PeerMgr with rotated certs failed: %v", err)
+		}
+	}
 }
 
 func (bxh *BitXHub) printLogo() {
@@ -3,6 +3,7 @@ package app
 import (
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -12,11 +13,13 @@ import (
 	"github.com/Rican7/retry"
 	"github.com/Rican7/retry/strategy"
 	"github.com/meshplus/bitxhub-core/governance"
+	node_mgr "github.com/meshplus/bitxhub-core/node-mgr"
 	orderPeerMgr "github.com/meshplus/bitxhub-core/peer-mgr"
 	"github.com/meshplus/bitxhub-core/tss/message"
 	"github.com/meshplus/bitxhub-model/pb"
 	"github.com/meshplus/bitxhub/internal/model/events"
 	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/meshplus/bitxhub/pkg/peermgr"
 	"github.com/sirupsen/logrus"
 )
 
@@ -46,6 +49,7 @@ func (bxh *BitXHub) listenEvent() {
 	configCh := make(chan *repo.Repo)
 	tssMsgCh := make(chan *pb.Message)
 	tssKeygenReqCh := make(chan *pb.Message)
+	tssCulpritCh := make(chan tssmgr.CulpritEvent)
 
 	blockSub := bxh.BlockExecutor.SubscribeBlockEvent(blockCh)
 	orderMsgSub := bxh.PeerMgr.SubscribeOrderMessage(orderMsgCh)
@@ -53,6 +57,7 @@ func (bxh *BitXHub) listenEvent() {
 	configSub := bxh.repo.SubscribeConfigChange(configCh)
 	tssSub := bxh.PeerMgr.SubscribeTssMessage(tssMsgCh)
 	tssKeygenReqSub := bxh.PeerMgr.SubscribeTssKeygenReq(tssKeygenReqCh)
+	tssCulpritSub := bxh.TssMgr.SubscribeCulprit(tssCulpritCh)
 
 	defer blockSub.Unsubscribe()
 	defer orderMsgSub.Unsubscribe()
@@ -60,6 +65,7 @@ func (bxh *BitXHub) listenEvent() {
 	defer configSub.Unsubscribe()
 	defer tssSub.Unsubscribe()
 	defer tssKeygenReqSub.Unsubscribe()
+	defer tssCulpritSub.Unsubscribe()
 
 	for {
 		select {
@@ -129,6 +135,8 @@ func (bxh *BitXHub) listenEvent() {
 		case ev := <-blockCh:
 			go bxh.Order.ReportState(ev.Block.BlockHeader.Number, ev.Block.BlockHash, ev.TxHashList)
 			go bxh.Router.PutBlockAndMeta(ev.Block, ev.InterchainMeta)
+			go bxh.refreshTss(ev.Block)
+			go bxh.announceBlock(ev.Block)
 		case ev := <-orderMsgCh:
 			go func() {
 				if err := bxh.Order.Step(ev.Data); err != nil {
@@ -137,6 +145,9 @@ func (bxh *BitXHub) listenEvent() {
 			}()
 		case ev := <-nodeCh:
 			go func() {
+				if ev.NodeType == node_mgr.NVPNode {
+					return
+				}
 				switch ev.NodeEventType {
 				case governance.EventLogout:
 					// order
@@ -173,11 +184,26 @@ func (bxh *BitXHub) listenEvent() {
 						}
 
 					}
+					// reshare the current key to keep tss pubkey unchanged, keygen only if it can not be reshared.
+					// Keygen needs all the nodes, so a node failing to reshare must not start it alone,
+					// only ErrKeyNotReshareable, which all the nodes agreeing on the key get, falls back to keygen.
+					time1 := time.Now()
+					bxh.logger.Infof("...... tss req reshare")
+					if err := bxh.TssMgr.Reshare(bxh.Order.Quorum() - 1); err == nil {
+						bxh.logger.Infof("=============================reshare time: %v", time.Since(time1))
+						return
+					} else if !errors.Is(err, tssmgr.ErrKeyNotReshareable) {
+						bxh.logger.Errorf("tss reshare error, keep the current key: %v", err)
+						return
+					} else {
+						bxh.logger.Warnf("tss key can not be reshared, regenerate the key: %v", err)
+					}
+
 					// update threshold
 					bxh.TssMgr.UpdateThreshold(bxh.Order.Quorum() - 1)
 
 					// keygen
-					time1 := time.Now()
+					time1 = time.Now()
 					bxh.logger.Infof("...... tss req key gen")
 					if err := bxh.TssMgr.Keygen(true); err != nil {
 						bxh.logger.Errorf("tss key generate error: %v", err)
@@ -187,6 +213,8 @@ func (bxh *BitXHub) listenEvent() {
 					bxh.logger.Infof("=============================keygen time: %v", timeKeygen)
 				}
 			}()
+		case ev := <-tssCulpritCh:
+			go bxh.handleTssCulprit(ev)
 		case config := <-configCh:
 			bxh.ReConfig(config)
 		case <-bxh.Ctx.Done():
@@ -194,3 +222,22 @@ func (bxh *BitXHub) listenEvent() {
 		}
 	}
 }
+
+// announceBlock gossips the header of the committed block to audit and follower nodes,
+// followers only relay the announcements of vp nodes
+func (bxh *BitXHub) announceBlock(block *pb.Block) {
+	if bxh.repo.Config.Solo || bxh.repo.NetworkConfig.Follower {
+		return
+	}
+	data, err := block.BlockHeader.Marshal()
+	if err != nil {
+		bxh.logger.Errorf("marshal block header error: %v", err)
+		return
+	}
+	if err := bxh.PeerMgr.Publish(peermgr.BlockTopic, data); err != nil {
+		bxh.logger.WithFields(logrus.Fields{
+			"height": block.BlockHeader.Number,
+			"error":  err,
+		}).Warn("Announce block failed")
+	}
+}
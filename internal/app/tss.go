@@ -0,0 +1,173 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Rican7/retry"
+	"github.com/Rican7/retry/strategy"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
+	"github.com/meshplus/bitxhub/pkg/tssmgr"
+	"github.com/sirupsen/logrus"
+)
+
+// refreshTss proactively refreshes the tss shares at the end of each refresh epoch.
+// All the parties must refresh together, so it is skipped while the node is syncing or catching up blocks.
+func (bxh *BitXHub) refreshTss(block *pb.Block) {
+	height := block.BlockHeader.Number
+	epoch := bxh.repo.Config.TssPolicy.RefreshEpoch
+	if !bxh.repo.Config.Tss.EnableTSS || epoch == 0 || height%epoch != 0 {
+		return
+	}
+	if err := bxh.Order.Ready(); err != nil {
+		bxh.logger.WithFields(logrus.Fields{
+			"height": height,
+			"err":    err,
+		}).Warn("skip tss refresh, order is not ready")
+		return
+	}
+	if latest := bxh.Ledger.GetChainMeta().Height; latest > height {
+		bxh.logger.WithFields(logrus.Fields{
+			"height": height,
+			"latest": latest,
+		}).Warn("skip tss refresh, block is not the latest")
+		return
+	}
+	if age := time.Since(blockTime(block)); age > bxh.repo.Config.Tss.KeyGenTimeout {
+		bxh.logger.WithFields(logrus.Fields{
+			"height": height,
+			"age":    age,
+		}).Warn("skip tss refresh, block is too old")
+		return
+	}
+
+	time1 := time.Now()
+	bxh.logger.Infof("...... tss refresh shares at height %d", height)
+	if err := bxh.TssMgr.Refresh(height / epoch); err != nil {
+		bxh.logger.WithFields(logrus.Fields{
+			"height": height,
+			"err":    err,
+		}).Warn("tss refresh shares error")
+		return
+	}
+	bxh.logger.Infof("=============================refresh time: %v", time.Since(time1))
+}
+
+// blockTime returns the time the block was generated, solo and raft stamp blocks in nanoseconds while smart_bft in seconds
+func blockTime(block *pb.Block) time.Time {
+	ts := block.BlockHeader.Timestamp
+	if ts < 1e12 {
+		return time.Unix(ts, 0)
+	}
+	return time.Unix(0, ts)
+}
+
+// handleTssCulprit reports the node to governance once it is blamed freeze threshold times in tss key sign.
+// It is reported again on every later blame until the report is recorded on chain, so a lost report is retried.
+func (bxh *BitXHub) handleTssCulprit(ev tssmgr.CulpritEvent) {
+	threshold := bxh.repo.Config.TssPolicy.FreezeThreshold
+	if threshold == 0 || ev.Count < threshold {
+		return
+	}
+
+	account, err := bxh.tssPartyAccount(ev.PartyID)
+	if err != nil {
+		bxh.logger.WithFields(logrus.Fields{
+			"party": ev.PartyID,
+			"err":   err,
+		}).Error("report tss culprit error")
+		return
+	}
+	if bxh.isTssCulpritReported(account) {
+		return
+	}
+
+	if err := retry.Retry(func(attempt uint) error {
+		return bxh.reportTssCulprit(account, ev.Count)
+	}, strategy.Limit(3), strategy.Wait(time.Second)); err != nil {
+		bxh.logger.WithFields(logrus.Fields{
+			"party": ev.PartyID,
+			"count": ev.Count,
+			"err":   err,
+		}).Error("report tss culprit error")
+		return
+	}
+	bxh.logger.WithFields(logrus.Fields{
+		"party": ev.PartyID,
+		"count": ev.Count,
+	}).Info("report tss culprit")
+}
+
+// isTssCulpritReported returns true if the local node has reported the account or it is frozen out of tss
+func (bxh *BitXHub) isTssCulpritReported(account string) bool {
+	lg := bxh.Ledger.Copy()
+	if ok, _ := lg.GetState(constant.NodeManagerContractAddr.Address(), []byte(contracts.TssFreezeKey(account))); ok {
+		return true
+	}
+	ok, data := lg.GetState(constant.NodeManagerContractAddr.Address(), []byte(contracts.TssCulpritKey(account)))
+	if !ok {
+		return false
+	}
+	reporters := make(map[string]string)
+	if err := json.Unmarshal(data, &reporters); err != nil {
+		return false
+	}
+	_, ok = reporters[bxh.GetPrivKey().Address]
+	return ok
+}
+
+func (bxh *BitXHub) tssPartyAccount(partyID string) (string, error) {
+	id, err := strconv.ParseUint(partyID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("illegal party id %s: %w", partyID, err)
+	}
+	for _, node := range bxh.repo.NetworkConfig.Nodes {
+		if node.ID == id {
+			return node.Account, nil
+		}
+	}
+	return "", fmt.Errorf("account of node %d is not found in network config", id)
+}
+
+func (bxh *BitXHub) reportTssCulprit(account string, count uint64) error {
+	invokePayload := &pb.InvokePayload{
+		Method: "ReportTssCulprit",
+		Args: []*pb.Arg{
+			pb.String(account),
+			pb.String(fmt.Sprintf("blamed %d times in tss key sign", count)),
+		},
+	}
+	invokePayloadData, err := invokePayload.Marshal()
+	if err != nil {
+		return fmt.Errorf("marshal invoke payload error: %w", err)
+	}
+	data := &pb.TransactionData{
+		Type:    pb.TransactionData_INVOKE,
+		VmType:  pb.TransactionData_BVM,
+		Payload: invokePayloadData,
+	}
+	payload, err := data.Marshal()
+	if err != nil {
+		return fmt.Errorf("marshal transaction data error: %w", err)
+	}
+
+	key := bxh.GetPrivKey()
+	tx := &pb.BxhTransaction{
+		From:      types.NewAddressByStr(key.Address),
+		To:        constant.NodeManagerContractAddr.Address(),
+		Timestamp: time.Now().UnixNano(),
+		Nonce:     bxh.Order.GetPendingNonceByAccount(key.Address),
+		Payload:   payload,
+	}
+	if err := tx.Sign(key.PrivKey); err != nil {
+		return fmt.Errorf("sign tx error: %w", err)
+	}
+	tx.TransactionHash = tx.Hash()
+
+	return bxh.Order.Prepare(tx)
+}
@@ -7,9 +7,12 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/meshplus/bitxhub-kit/types"
 	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/executor"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
 	"github.com/meshplus/bitxhub/internal/model/events"
 	"github.com/meshplus/bitxhub/internal/repo"
 	"github.com/meshplus/bitxhub/pkg/peermgr"
+	"github.com/meshplus/bitxhub/pkg/tssmgr"
 	"github.com/meshplus/eth-kit/ledger"
 )
 
@@ -26,6 +29,10 @@ type CoreAPI interface {
 type BrokerAPI interface {
 	HandleTransaction(tx pb.Transaction) error
 	HandleView(tx pb.Transaction) (*pb.Receipt, error)
+
+	// SimulateTransaction executes the tx against a copy of the latest state and returns the state diff
+	SimulateTransaction(tx pb.Transaction) (*executor.SimulationResult, error)
+
 	GetTransaction(*types.Hash) (pb.Transaction, error)
 	GetTransactionMeta(*types.Hash) (*pb.TransactionMeta, error)
 	GetReceipt(*types.Hash) (*pb.Receipt, error)
@@ -36,6 +43,11 @@ type BrokerAPI interface {
 	GetPoolTransaction(hash *types.Hash) pb.Transaction
 	GetStateLedger() ledger.StateLedger
 
+	// ResolveName resolves a BNS name at the current height
+	ResolveName(name string) (*contracts.ResolvedDomain, error)
+	// GetOwnershipHistory returns the registrations and transfers of a BNS name
+	GetOwnershipHistory(name string) ([]*contracts.OwnershipEvent, error)
+
 	// AddPier
 	AddPier(pierID string) (chan *pb.InterchainTxWrappers, error)
 
@@ -58,6 +70,13 @@ type BrokerAPI interface {
 	GetQuorum() uint64
 	GetTssPubkey() (string, *ecdsa.PublicKey, error)
 	GetTssInfo() (*pb.TssInfo, error)
+
+	// GetTssSignAuditRecords returns the tss key sign requests recorded by the local node with index in [start, end]
+	GetTssSignAuditRecords(start, end uint64) ([]*tssmgr.SignAuditRecord, error)
+
+	// GetTssCulpritCounts returns the times each party has been blamed in tss key sign on the local node
+	GetTssCulpritCounts() (map[string]uint64, error)
+
 	GetPrivKey() *repo.Key
 }
 
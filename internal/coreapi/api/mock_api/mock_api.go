@@ -14,9 +14,12 @@ import (
 	types "github.com/meshplus/bitxhub-kit/types"
 	pb "github.com/meshplus/bitxhub-model/pb"
 	api "github.com/meshplus/bitxhub/internal/coreapi/api"
+	"github.com/meshplus/bitxhub/internal/executor"
+	contracts "github.com/meshplus/bitxhub/internal/executor/contracts"
 	events "github.com/meshplus/bitxhub/internal/model/events"
 	repo "github.com/meshplus/bitxhub/internal/repo"
 	peermgr "github.com/meshplus/bitxhub/pkg/peermgr"
+	tssmgr "github.com/meshplus/bitxhub/pkg/tssmgr"
 	ledger "github.com/meshplus/eth-kit/ledger"
 )
 
@@ -266,6 +269,21 @@ func (mr *MockBrokerAPIMockRecorder) GetInterchainTxWrappers(did, begin, end, ch
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInterchainTxWrappers", reflect.TypeOf((*MockBrokerAPI)(nil).GetInterchainTxWrappers), did, begin, end, ch)
 }
 
+// GetOwnershipHistory mocks base method.
+func (m *MockBrokerAPI) GetOwnershipHistory(name string) ([]*contracts.OwnershipEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOwnershipHistory", name)
+	ret0, _ := ret[0].([]*contracts.OwnershipEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOwnershipHistory indicates an expected call of GetOwnershipHistory.
+func (mr *MockBrokerAPIMockRecorder) GetOwnershipHistory(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOwnershipHistory", reflect.TypeOf((*MockBrokerAPI)(nil).GetOwnershipHistory), name)
+}
+
 // GetPendingNonceByAccount mocks base method.
 func (m *MockBrokerAPI) GetPendingNonceByAccount(account string) uint64 {
 	m.ctrl.T.Helper()
@@ -382,6 +400,21 @@ func (mr *MockBrokerAPIMockRecorder) GetStateLedger() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStateLedger", reflect.TypeOf((*MockBrokerAPI)(nil).GetStateLedger))
 }
 
+// SimulateTransaction mocks base method.
+func (m *MockBrokerAPI) SimulateTransaction(tx pb.Transaction) (*executor.SimulationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimulateTransaction", tx)
+	ret0, _ := ret[0].(*executor.SimulationResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimulateTransaction indicates an expected call of SimulateTransaction.
+func (mr *MockBrokerAPIMockRecorder) SimulateTransaction(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulateTransaction", reflect.TypeOf((*MockBrokerAPI)(nil).SimulateTransaction), tx)
+}
+
 // GetTransaction mocks base method.
 func (m *MockBrokerAPI) GetTransaction(arg0 *types.Hash) (pb.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -412,6 +445,21 @@ func (mr *MockBrokerAPIMockRecorder) GetTransactionMeta(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionMeta", reflect.TypeOf((*MockBrokerAPI)(nil).GetTransactionMeta), arg0)
 }
 
+// GetTssCulpritCounts mocks base method.
+func (m *MockBrokerAPI) GetTssCulpritCounts() (map[string]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTssCulpritCounts")
+	ret0, _ := ret[0].(map[string]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTssCulpritCounts indicates an expected call of GetTssCulpritCounts.
+func (mr *MockBrokerAPIMockRecorder) GetTssCulpritCounts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTssCulpritCounts", reflect.TypeOf((*MockBrokerAPI)(nil).GetTssCulpritCounts))
+}
+
 // GetTssInfo mocks base method.
 func (m *MockBrokerAPI) GetTssInfo() (*pb.TssInfo, error) {
 	m.ctrl.T.Helper()
@@ -443,6 +491,21 @@ func (mr *MockBrokerAPIMockRecorder) GetTssPubkey() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTssPubkey", reflect.TypeOf((*MockBrokerAPI)(nil).GetTssPubkey))
 }
 
+// GetTssSignAuditRecords mocks base method.
+func (m *MockBrokerAPI) GetTssSignAuditRecords(start, end uint64) ([]*tssmgr.SignAuditRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTssSignAuditRecords", start, end)
+	ret0, _ := ret[0].([]*tssmgr.SignAuditRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTssSignAuditRecords indicates an expected call of GetTssSignAuditRecords.
+func (mr *MockBrokerAPIMockRecorder) GetTssSignAuditRecords(start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTssSignAuditRecords", reflect.TypeOf((*MockBrokerAPI)(nil).GetTssSignAuditRecords), start, end)
+}
+
 // HandleTransaction mocks base method.
 func (m *MockBrokerAPI) HandleTransaction(tx pb.Transaction) error {
 	m.ctrl.T.Helper()
@@ -498,6 +561,21 @@ func (mr *MockBrokerAPIMockRecorder) RemovePier(pierID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePier", reflect.TypeOf((*MockBrokerAPI)(nil).RemovePier), pierID)
 }
 
+// ResolveName mocks base method.
+func (m *MockBrokerAPI) ResolveName(name string) (*contracts.ResolvedDomain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveName", name)
+	ret0, _ := ret[0].(*contracts.ResolvedDomain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveName indicates an expected call of ResolveName.
+func (mr *MockBrokerAPIMockRecorder) ResolveName(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveName", reflect.TypeOf((*MockBrokerAPI)(nil).ResolveName), name)
+}
+
 // SetTssNotParties mocks base method.
 func (m *MockBrokerAPI) SetTssNotParties(tssReq *pb.GetSignsRequest, singers []string) error {
 	m.ctrl.T.Helper()
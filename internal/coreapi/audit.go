@@ -5,10 +5,12 @@ import (
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/meshplus/bitxhub-core/governance"
 	nodemgr "github.com/meshplus/bitxhub-core/node-mgr"
 	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
 	"github.com/meshplus/bitxhub/internal/coreapi/api"
+	"github.com/meshplus/bitxhub/internal/model/events"
 	"github.com/meshplus/bitxhub/pkg/utils"
 )
 
@@ -27,10 +29,13 @@ func (api *AuditAPI) HandleAuditNodeSubscription(dataCh chan<- *pb.AuditTxInfo,
 		return fmt.Errorf("get permit chains error: %w", err)
 	}
 
-	// 2. subscribe to real-time audit info
+	// 2. subscribe to real-time audit info and the governance of the audit node
 	auditTxInfoCh := make(chan *pb.AuditTxInfo, 1024)
 	sub := api.SubscribeAuditEvent(auditTxInfoCh)
 	defer sub.Unsubscribe()
+	nodeCh := make(chan events.NodeEvent, 16)
+	nodeSub := api.bxh.BlockExecutor.SubscribeNodeEvent(nodeCh)
+	defer nodeSub.Unsubscribe()
 
 	// 3. send historical audit info from the current block height
 	blockCur := api.bxh.Ledger.GetChainMeta().Height
@@ -76,8 +81,18 @@ func (api *AuditAPI) HandleAuditNodeSubscription(dataCh chan<- *pb.AuditTxInfo,
 		}
 	}
 
-	// 4. send real-time audit info
-	for auditTxinfo := range auditTxInfoCh {
+	// 4. send real-time audit info until the audit node is logouted or its audit admin binding is paused
+	for {
+		var auditTxinfo *pb.AuditTxInfo
+		select {
+		case ev := <-nodeCh:
+			if isAuditNodeRevoked(ev, auditNodeID) {
+				return fmt.Errorf("audit node %s is not available: %s", auditNodeID, ev.NodeEventType)
+			}
+			continue
+		case auditTxinfo = <-auditTxInfoCh:
+		}
+
 		isPermited := false
 		for nodeID := range auditNodeIDMap {
 			if _, ok := auditTxinfo.RelatedNodeIDList[nodeID]; ok {
@@ -97,8 +112,13 @@ func (api *AuditAPI) HandleAuditNodeSubscription(dataCh chan<- *pb.AuditTxInfo,
 			dataCh <- auditTxinfo
 		}
 	}
+}
 
-	return nil
+func isAuditNodeRevoked(ev events.NodeEvent, auditNodeID string) bool {
+	if ev.NodeType != nodemgr.NVPNode || ev.NodeAccount != auditNodeID {
+		return false
+	}
+	return ev.NodeEventType == governance.EventLogout || ev.NodeEventType == governance.EventPause
 }
 
 func (api *AuditAPI) getPermitChains(auditNodeID string) (map[string]struct{}, map[string]struct{}, error) {
@@ -19,9 +19,12 @@ import (
 	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
 	"github.com/meshplus/bitxhub/internal/coreapi/api"
+	"github.com/meshplus/bitxhub/internal/executor"
 	"github.com/meshplus/bitxhub/internal/executor/contracts"
 	"github.com/meshplus/bitxhub/internal/model"
 	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/meshplus/bitxhub/pkg/peermgr"
+	"github.com/meshplus/bitxhub/pkg/tssmgr"
 	"github.com/meshplus/bitxhub/pkg/utils"
 	"github.com/meshplus/eth-kit/ledger"
 	solsha3 "github.com/miguelmota/go-solidity-sha3"
@@ -44,6 +47,14 @@ func (b *BrokerAPI) GetTssInfo() (*pb.TssInfo, error) {
 	return b.bxh.TssMgr.GetTssInfo()
 }
 
+func (b *BrokerAPI) GetTssSignAuditRecords(start, end uint64) ([]*tssmgr.SignAuditRecord, error) {
+	return b.bxh.TssMgr.GetSignAuditRecords(start, end)
+}
+
+func (b *BrokerAPI) GetTssCulpritCounts() (map[string]uint64, error) {
+	return b.bxh.TssMgr.GetCulpritCounts()
+}
+
 func (b *BrokerAPI) GetPrivKey() *repo.Key {
 	return b.bxh.GetPrivKey()
 }
@@ -79,6 +90,101 @@ func (b *BrokerAPI) HandleView(tx pb.Transaction) (*pb.Receipt, error) {
 	return receipts[0], nil
 }
 
+func (b *BrokerAPI) SimulateTransaction(tx pb.Transaction) (*executor.SimulationResult, error) {
+	if tx.GetHash() == nil {
+		return nil, fmt.Errorf("transaction hash is nil")
+	}
+
+	b.logger.WithFields(logrus.Fields{
+		"hash": tx.GetHash().String(),
+	}).Debugf("Receive simulation")
+
+	return b.bxh.ViewExecutor.SimulateTransaction(tx)
+}
+
+// ResolveName resolves a BNS name against the state and the time of the latest block
+func (b *BrokerAPI) ResolveName(name string) (*contracts.ResolvedDomain, error) {
+	if !contracts.IsBnsName(name) {
+		return nil, fmt.Errorf("%s is not a domain name", name)
+	}
+
+	ret, err := b.viewBnsContract(constant.ServiceResolverContractAddr.Address(), "Resolve", pb.String(name))
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s failed: %w", name, err)
+	}
+
+	resolved := &contracts.ResolvedDomain{}
+	if err := json.Unmarshal(ret, resolved); err != nil {
+		return nil, fmt.Errorf("unmarshal resolved domain error: %w", err)
+	}
+	return resolved, nil
+}
+
+// GetOwnershipHistory returns the registrations and transfers of a BNS name, oldest first
+func (b *BrokerAPI) GetOwnershipHistory(name string) ([]*contracts.OwnershipEvent, error) {
+	if !contracts.IsBnsName(name) {
+		return nil, fmt.Errorf("%s is not a domain name", name)
+	}
+
+	ret, err := b.viewBnsContract(constant.ServiceRegistryContractAddr.Address(), "GetOwnershipHistory", pb.String(name))
+	if err != nil {
+		return nil, fmt.Errorf("get ownership history of %s failed: %w", name, err)
+	}
+
+	var events []*contracts.OwnershipEvent
+	if err := json.Unmarshal(ret, &events); err != nil {
+		return nil, fmt.Errorf("unmarshal ownership history error: %w", err)
+	}
+	return events, nil
+}
+
+// viewBnsContract calls a method of a BNS contract in a view transaction and returns its result
+func (b *BrokerAPI) viewBnsContract(to *types.Address, method string, args ...*pb.Arg) ([]byte, error) {
+	invokePayload := &pb.InvokePayload{
+		Method: method,
+		Args:   args,
+	}
+	invokePayloadData, err := invokePayload.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("marshal invoke payload error: %w", err)
+	}
+	data := &pb.TransactionData{
+		Type:    pb.TransactionData_INVOKE,
+		VmType:  pb.TransactionData_BVM,
+		Payload: invokePayloadData,
+	}
+	payload, err := data.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("marshal transaction data error: %w", err)
+	}
+	// names are viewed at the time of the latest block, as the interchain contract resolves
+	// names in IBTPs at the time of their transactions
+	meta := b.bxh.Ledger.GetChainMeta()
+	block, err := b.bxh.Ledger.GetBlock(meta.Height, false)
+	if err != nil {
+		return nil, fmt.Errorf("get block %d error: %w", meta.Height, err)
+	}
+	tx := &pb.BxhTransaction{
+		From:      types.NewAddressByStr(b.bxh.GetPrivKey().Address),
+		To:        to,
+		Timestamp: block.BlockHeader.Timestamp,
+		Payload:   payload,
+	}
+	tx.TransactionHash = tx.Hash()
+
+	receipt, err := b.HandleView(tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return nil, fmt.Errorf("no receipt")
+	}
+	if !receipt.IsSuccess() {
+		return nil, fmt.Errorf("%s", string(receipt.Ret))
+	}
+	return receipt.Ret, nil
+}
+
 func (b *BrokerAPI) GetTransaction(hash *types.Hash) (pb.Transaction, error) {
 	return b.bxh.Ledger.GetTransaction(hash)
 }
@@ -270,11 +376,13 @@ func (b *BrokerAPI) requestIBTPSignPeer(pid uint64, id string, typ pb.GetSignsRe
 		return "", nil, fmt.Errorf("send message to %d failed: %w", pid, err)
 	}
 	if resp == nil || resp.Type != pb.Message_FETCH_IBTP_SIGN_ACK {
+		b.bxh.PeerMgr.ReportPeer(pid, peermgr.InvalidResponse)
 		return "", nil, fmt.Errorf("invalid fetch ibtp sign resp")
 	}
 
 	data := model.MerkleWrapperSign{}
 	if err := data.Unmarshal(resp.Data); err != nil {
+		b.bxh.PeerMgr.ReportPeer(pid, peermgr.InvalidResponse)
 		return "", nil, fmt.Errorf("unmarshal merkle wrapper sign error: %w", err)
 	}
 
@@ -337,11 +445,13 @@ func (b *BrokerAPI) requestBlockHeaderSignFromPeer(pid uint64, height string) (s
 	}
 
 	if resp == nil || resp.Type != pb.Message_FETCH_BLOCK_SIGN_ACK {
+		b.bxh.PeerMgr.ReportPeer(pid, peermgr.InvalidResponse)
 		return "", nil, fmt.Errorf("invalid fetch block header sign resp")
 	}
 
 	data := model.MerkleWrapperSign{}
 	if err := data.Unmarshal(resp.Data); err != nil {
+		b.bxh.PeerMgr.ReportPeer(pid, peermgr.InvalidResponse)
 		return "", nil, fmt.Errorf("unmarsahl merkle wrapper sign error: %w", err)
 	}
 
@@ -360,11 +470,13 @@ func (b *BrokerAPI) requestBurnSignFromPeer(pid uint64, hash string) (string, []
 	}
 
 	if resp == nil || resp.Type != pb.Message_FETCH_BURN_SIGN_ACK {
+		b.bxh.PeerMgr.ReportPeer(pid, peermgr.InvalidResponse)
 		return "", nil, fmt.Errorf("invalid fetch minter sign resp")
 	}
 
 	data := model.MerkleWrapperSign{}
 	if err := data.Unmarshal(resp.Data); err != nil {
+		b.bxh.PeerMgr.ReportPeer(pid, peermgr.InvalidResponse)
 		return "", nil, fmt.Errorf("unmarshal merkle wrapper sign error: %w", err)
 	}
 
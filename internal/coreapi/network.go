@@ -13,9 +13,16 @@ type NetworkAPI CoreAPI
 
 var _ api.NetworkAPI = (*NetworkAPI)(nil)
 
-// PeerInfo collects the peers' info in p2p network.
+// PeerInfo collects the peers' info along with their scores in p2p network.
 func (network *NetworkAPI) PeerInfo() ([]byte, error) {
-	peerInfo := network.bxh.PeerMgr.OrderPeers()
+	scores := network.bxh.PeerMgr.PeerScores()
+	peerInfo := make(map[uint64]*peermgr.PeerInfo)
+	for id, vpInfo := range network.bxh.PeerMgr.OrderPeers() {
+		peerInfo[id] = &peermgr.PeerInfo{
+			VpInfo: vpInfo,
+			Score:  scores[id],
+		}
+	}
 
 	data, err := json.Marshal(peerInfo)
 	if err != nil {
@@ -420,7 +420,7 @@ func (am *AppchainManager) RegisterAppchain(chainID string, chainName string, pu
 	if res := CheckRuleAddress(am.Persister, masterRuleAddr, chainType); !res.Ok {
 		return res
 	}
-	if !ruleMgr.IsDefault(masterRuleAddr, chainType) && strings.Trim(masterRuleUrl, " ") == "" {
+	if !ruleMgr.IsDefault(masterRuleAddr, chainType) && !IsSchemeRule(masterRuleAddr) && strings.Trim(masterRuleUrl, " ") == "" {
 		return boltvm.Error(boltvm.AppchainEmptyRuleUrlCode, string(boltvm.AppchainEmptyRuleUrlMsg))
 	}
 
@@ -0,0 +1,485 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	appchainMgr "github.com/meshplus/bitxhub-core/appchain-mgr"
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/governance"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	BRIDGE_PAIR_PREFIX    = "bridge-pair"
+	BRIDGE_WRAPPED_PREFIX = "bridge-wrapped"
+	BRIDGE_BALANCE_PREFIX = "bridge-balance"
+	BRIDGE_LOCK_PREFIX    = "bridge-lock"
+	BRIDGE_RELEASE_PREFIX = "bridge-release"
+	BRIDGE_NONCE_KEY      = "bridge-nonce"
+
+	BridgeEventLock    = "lock"
+	BridgeEventRelease = "release"
+
+	ReleasePending  = "pending"
+	ReleaseReleased = "released"
+)
+
+// TokenPair binds the token locked on the origin appchain to the wrapped asset minted on the relay chain
+type TokenPair struct {
+	ChainID      string `json:"chain_id"`
+	OriginToken  string `json:"origin_token"`
+	WrappedToken string `json:"wrapped_token"`
+	// address of the rule verifying the lock and release events of the pair, see BridgeProofVerifier
+	VerifierRule string `json:"verifier_rule"`
+
+	// max supply of the wrapped asset, 0 means no cap
+	SupplyCap uint64 `json:"supply_cap"`
+	// max amount minted and burned in every window, 0 means no limit
+	RateLimit uint64 `json:"rate_limit"`
+	// length of rate limit window in seconds
+	Window uint64 `json:"window"`
+
+	Supply       uint64 `json:"supply"`
+	WindowStart  int64  `json:"window_start"`
+	WindowAmount uint64 `json:"window_amount"`
+}
+
+// BridgeEvent is the lock or release of tokens on the origin appchain, which is proven by the appchain
+type BridgeEvent struct {
+	Type      string `json:"type"`
+	ID        string `json:"id"`
+	Token     string `json:"token"`
+	Recipient string `json:"recipient"`
+	Amount    uint64 `json:"amount"`
+}
+
+// Release is the burn of wrapped asset waiting to be released on the origin appchain,
+// its id is the nonce which the escrow on the origin appchain uses against replay
+type Release struct {
+	ID        string `json:"id"`
+	ChainID   string `json:"chain_id"`
+	Token     string `json:"token"`
+	Burner    string `json:"burner"`
+	Recipient string `json:"recipient"`
+	Amount    uint64 `json:"amount"`
+	Status    string `json:"status"`
+}
+
+// BridgeProofVerifier verifies the proof of the json encoded BridgeEvent on the appchain, ruleAddr is the
+// verifier rule of the token pair. The interchain rules of appchains verify IBTPs and are never used for bridge events.
+type BridgeProofVerifier func(stub boltvm.Stub, app *appchainMgr.Appchain, ruleAddr string, event, proof []byte) error
+
+var (
+	bridgeVerifierLock sync.RWMutex
+	bridgeVerifiers    = make(map[string]BridgeProofVerifier)
+)
+
+// RegisterBridgeProofVerifier registers the native verifier of the rule address,
+// the verifier rules without native verifier are run by the validation engine
+func RegisterBridgeProofVerifier(ruleAddr string, verifier BridgeProofVerifier) {
+	bridgeVerifierLock.Lock()
+	defer bridgeVerifierLock.Unlock()
+	bridgeVerifiers[ruleAddr] = verifier
+}
+
+func getBridgeProofVerifier(ruleAddr string) BridgeProofVerifier {
+	bridgeVerifierLock.RLock()
+	defer bridgeVerifierLock.RUnlock()
+	if verifier, ok := bridgeVerifiers[ruleAddr]; ok {
+		return verifier
+	}
+	return verifyByRule
+}
+
+// verifyByRule runs the verifier rule deployed for bridge events, which gets the proof, the event as payload
+// and the trust root of the appchain
+func verifyByRule(stub boltvm.Stub, app *appchainMgr.Appchain, ruleAddr string, event, proof []byte) error {
+	ok, _, err := stub.ValidationEngine().Validate(ruleAddr, app.ID, proof, event, string(app.TrustRoot))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("proof is rejected by rule %s", ruleAddr)
+	}
+	return nil
+}
+
+// AssetBridge locks tokens on the origin appchain and mints the wrapped asset on the relay chain,
+// which is burned to release the tokens back
+type AssetBridge struct {
+	boltvm.Stub
+}
+
+// SetTokenPair submits a proposal to register the token pair or update its verifier rule, supply cap and rate limit
+func (ab *AssetBridge) SetTokenPair(chainID, originToken, wrappedToken, verifierRule string, supplyCap, rateLimit, window uint64, reason string) *boltvm.Response {
+	if err := checkPermission(ab.Stub, []string{string(PermissionAdmin)}, "", ab.Caller(), nil); err != nil {
+		return boltvm.Error(boltvm.AssetNoPermissionCode, fmt.Sprintf("only governance admin can set token pair: %v", err))
+	}
+	if chainID == "" || originToken == "" || wrappedToken == "" || verifierRule == "" {
+		return boltvm.Error(boltvm.AssetInternalErrCode, "chain id, origin token, wrapped token and verifier rule should not be empty")
+	}
+	if rateLimit != 0 && window == 0 {
+		return boltvm.Error(boltvm.AssetInternalErrCode, "window of rate limit should be greater than 0")
+	}
+	if _, err := ab.getAppchain(chainID); err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, err.Error())
+	}
+	if res := ab.CrossInvoke(constant.RuleManagerContractAddr.Address().String(), "GetRuleByAddr", pb.String(chainID), pb.String(verifierRule)); res.Ok {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("rule %s verifies the ibtps of appchain %s, not bridge events", verifierRule, chainID))
+	}
+	pairID := TokenPairID(chainID, originToken)
+	if err := ab.checkWrappedToken(pairID, wrappedToken); err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, err.Error())
+	}
+
+	extra, err := json.Marshal(TokenPair{
+		ChainID:      chainID,
+		OriginToken:  originToken,
+		WrappedToken: wrappedToken,
+		VerifierRule: verifierRule,
+		SupplyCap:    supplyCap,
+		RateLimit:    rateLimit,
+		Window:       window,
+	})
+	if err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("marshal token pair error: %v", err))
+	}
+
+	res := ab.CrossInvoke(constant.GovernanceContractAddr.Address().String(), "SubmitProposal",
+		pb.String(ab.Caller()),
+		pb.String(string(governance.EventUpdate)),
+		pb.String(string(AssetMgr)),
+		pb.String(pairID),
+		pb.String(""), // no last status
+		pb.String(reason),
+		pb.Bytes(extra),
+	)
+	if !res.Ok {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("submit proposal error: %s", string(res.Result)))
+	}
+
+	ab.CrossInvoke(constant.GovernanceContractAddr.Address().String(), "ZeroPermission", pb.String(string(res.Result)))
+
+	return getGovernanceRet(string(res.Result), nil)
+}
+
+// Manage applies the approved token pair proposal
+func (ab *AssetBridge) Manage(eventTyp, proposalResult, _, _ string, extra []byte) *boltvm.Response {
+	specificAddrs := []string{constant.GovernanceContractAddr.Address().String()}
+	addrsData, err := json.Marshal(specificAddrs)
+	if err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("marshal specificAddrs error: %v", err))
+	}
+	if err := checkPermission(ab.Stub, []string{string(PermissionSpecific)}, "", ab.CurrentCaller(), addrsData); err != nil {
+		return boltvm.Error(boltvm.AssetNoPermissionCode, fmt.Sprintf("no permission to manage asset bridge: %v", err))
+	}
+
+	if proposalResult != string(APPROVED) || eventTyp != string(governance.EventUpdate) {
+		return boltvm.Success(nil)
+	}
+
+	update := &TokenPair{}
+	if err := json.Unmarshal(extra, update); err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("unmarshal token pair error: %v", err))
+	}
+	pairID := TokenPairID(update.ChainID, update.OriginToken)
+	// another proposal may have bound the wrapped token since this one was submitted
+	if err := ab.checkWrappedToken(pairID, update.WrappedToken); err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, err.Error())
+	}
+	pair, ok := ab.getTokenPair(pairID)
+	if !ok {
+		pair = update
+		ab.Set(BridgeWrappedKey(pair.WrappedToken), []byte(pairID))
+	}
+	pair.VerifierRule = update.VerifierRule
+	pair.SupplyCap = update.SupplyCap
+	pair.RateLimit = update.RateLimit
+	pair.Window = update.Window
+	ab.SetObject(BridgePairKey(pairID), *pair)
+
+	return boltvm.Success(nil)
+}
+
+// Mint mints the wrapped asset to the recipient of the lock event proven by the origin appchain,
+// every lock event is minted only once
+func (ab *AssetBridge) Mint(chainID string, eventData []byte, proof []byte) *boltvm.Response {
+	event := &BridgeEvent{}
+	if err := json.Unmarshal(eventData, event); err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("unmarshal lock event error: %v", err))
+	}
+	if event.Type != BridgeEventLock || event.ID == "" || event.Recipient == "" || event.Amount == 0 {
+		return boltvm.Error(boltvm.AssetInternalErrCode, "invalid lock event")
+	}
+	pair, ok := ab.getTokenPair(TokenPairID(chainID, event.Token))
+	if !ok {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("token %s of appchain %s is not bridged", event.Token, chainID))
+	}
+	if ab.Has(BridgeLockKey(chainID, event.ID)) {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("lock %s of appchain %s has been minted", event.ID, chainID))
+	}
+	if err := ab.verifyEvent(pair, eventData, proof); err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("verify lock event error: %v", err))
+	}
+	if pair.SupplyCap != 0 && pair.Supply+event.Amount > pair.SupplyCap {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("supply of %s exceeds the cap %d", pair.WrappedToken, pair.SupplyCap))
+	}
+	if err := ab.consumeRateLimit(pair, event.Amount); err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, err.Error())
+	}
+
+	pair.Supply += event.Amount
+	ab.SetObject(BridgePairKey(TokenPairID(chainID, event.Token)), *pair)
+	ab.addBalance(pair.WrappedToken, event.Recipient, event.Amount)
+	ab.Set(BridgeLockKey(chainID, event.ID), []byte(ab.GetTxHash().String()))
+
+	ab.Logger().WithFields(logrus.Fields{
+		"chain_id":  chainID,
+		"lock":      event.ID,
+		"wrapped":   pair.WrappedToken,
+		"recipient": event.Recipient,
+		"amount":    event.Amount,
+	}).Info("Wrapped asset is minted")
+
+	return boltvm.Success(nil)
+}
+
+// Burn burns the wrapped asset of caller and creates the release to the recipient on the origin appchain
+func (ab *AssetBridge) Burn(wrappedToken, recipient string, amount uint64) *boltvm.Response {
+	if recipient == "" || amount == 0 {
+		return boltvm.Error(boltvm.AssetInternalErrCode, "recipient should not be empty and amount should be greater than 0")
+	}
+	ok, pairID := ab.Get(BridgeWrappedKey(wrappedToken))
+	if !ok {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("wrapped token %s does not exist", wrappedToken))
+	}
+	pair, _ := ab.getTokenPair(string(pairID))
+
+	caller := ab.Caller()
+	if err := ab.subBalance(wrappedToken, caller, amount); err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, err.Error())
+	}
+	if err := ab.consumeRateLimit(pair, amount); err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, err.Error())
+	}
+	pair.Supply -= amount
+	ab.SetObject(BridgePairKey(string(pairID)), *pair)
+
+	release := &Release{
+		ID:        fmt.Sprintf("%s:%d", pairID, ab.incNonce()),
+		ChainID:   pair.ChainID,
+		Token:     pair.OriginToken,
+		Burner:    caller,
+		Recipient: recipient,
+		Amount:    amount,
+		Status:    ReleasePending,
+	}
+	ab.SetObject(BridgeReleaseKey(release.ID), *release)
+
+	data, err := json.Marshal(release)
+	if err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+// ConfirmRelease finalizes the release with the release event proven by the origin appchain
+func (ab *AssetBridge) ConfirmRelease(releaseID string, eventData []byte, proof []byte) *boltvm.Response {
+	release := &Release{}
+	if !ab.GetObject(BridgeReleaseKey(releaseID), release) {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("release %s does not exist", releaseID))
+	}
+	if release.Status != ReleasePending {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("release %s has been %s", releaseID, release.Status))
+	}
+
+	event := &BridgeEvent{}
+	if err := json.Unmarshal(eventData, event); err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("unmarshal release event error: %v", err))
+	}
+	if event.Type != BridgeEventRelease || event.ID != release.ID || event.Token != release.Token ||
+		event.Recipient != release.Recipient || event.Amount != release.Amount {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("release event does not match release %s", releaseID))
+	}
+	pair, ok := ab.getTokenPair(TokenPairID(release.ChainID, release.Token))
+	if !ok {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("token %s of appchain %s is not bridged", release.Token, release.ChainID))
+	}
+	if err := ab.verifyEvent(pair, eventData, proof); err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("verify release event error: %v", err))
+	}
+
+	release.Status = ReleaseReleased
+	ab.SetObject(BridgeReleaseKey(releaseID), *release)
+
+	return boltvm.Success(nil)
+}
+
+// Transfer transfers the wrapped asset of caller on the relay chain
+func (ab *AssetBridge) Transfer(wrappedToken, to string, amount uint64) *boltvm.Response {
+	if !ab.Has(BridgeWrappedKey(wrappedToken)) {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("wrapped token %s does not exist", wrappedToken))
+	}
+	if err := ab.subBalance(wrappedToken, ab.Caller(), amount); err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, err.Error())
+	}
+	ab.addBalance(wrappedToken, to, amount)
+
+	return boltvm.Success(nil)
+}
+
+// GetTokenPair returns the token pair of the origin token on the appchain
+func (ab *AssetBridge) GetTokenPair(chainID, originToken string) *boltvm.Response {
+	pair, ok := ab.getTokenPair(TokenPairID(chainID, originToken))
+	if !ok {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("token %s of appchain %s is not bridged", originToken, chainID))
+	}
+	data, err := json.Marshal(pair)
+	if err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+// GetRelease returns the release, relayers release the tokens on the origin appchain with it
+func (ab *AssetBridge) GetRelease(releaseID string) *boltvm.Response {
+	release := &Release{}
+	if !ab.GetObject(BridgeReleaseKey(releaseID), release) {
+		return boltvm.Error(boltvm.AssetInternalErrCode, fmt.Sprintf("release %s does not exist", releaseID))
+	}
+	data, err := json.Marshal(release)
+	if err != nil {
+		return boltvm.Error(boltvm.AssetInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+// IsMinted checks whether the lock event of appchain has been minted
+func (ab *AssetBridge) IsMinted(chainID, lockID string) *boltvm.Response {
+	if ab.Has(BridgeLockKey(chainID, lockID)) {
+		return boltvm.Success([]byte(TRUE))
+	}
+	return boltvm.Success([]byte(FALSE))
+}
+
+// BalanceOf returns the wrapped asset balance of the account
+func (ab *AssetBridge) BalanceOf(wrappedToken, account string) *boltvm.Response {
+	return boltvm.Success([]byte(fmt.Sprintf("%d", ab.getBalance(wrappedToken, account))))
+}
+
+// verifyEvent verifies the event of the appchain with the verifier rule of the token pair
+func (ab *AssetBridge) verifyEvent(pair *TokenPair, event, proof []byte) error {
+	app, err := ab.getAppchain(pair.ChainID)
+	if err != nil {
+		return err
+	}
+	if !app.IsAvailable() {
+		return fmt.Errorf("appchain %s is not available", pair.ChainID)
+	}
+	if pair.VerifierRule == "" {
+		return fmt.Errorf("token pair %s has no verifier rule", TokenPairID(pair.ChainID, pair.OriginToken))
+	}
+	return getBridgeProofVerifier(pair.VerifierRule)(ab.Stub, app, pair.VerifierRule, event, proof)
+}
+
+// checkWrappedToken checks the wrapped token is bound to no other pair and the wrapped token of the pair is not changed
+func (ab *AssetBridge) checkWrappedToken(pairID, wrappedToken string) error {
+	if ok, data := ab.Get(BridgeWrappedKey(wrappedToken)); ok && string(data) != pairID {
+		return fmt.Errorf("wrapped token %s is bound to %s", wrappedToken, string(data))
+	}
+	if pair, ok := ab.getTokenPair(pairID); ok && pair.WrappedToken != wrappedToken {
+		return fmt.Errorf("wrapped token of %s can not be changed", pairID)
+	}
+	return nil
+}
+
+// consumeRateLimit counts the amount into the current window of token pair
+func (ab *AssetBridge) consumeRateLimit(pair *TokenPair, amount uint64) error {
+	if pair.RateLimit == 0 {
+		return nil
+	}
+	now := ab.GetTxTimeStamp()
+	if now-pair.WindowStart >= int64(pair.Window)*SecondTime {
+		pair.WindowStart = now
+		pair.WindowAmount = 0
+	}
+	if pair.WindowAmount+amount > pair.RateLimit {
+		return fmt.Errorf("amount of %s exceeds the rate limit %d in %d seconds", pair.WrappedToken, pair.RateLimit, pair.Window)
+	}
+	pair.WindowAmount += amount
+	return nil
+}
+
+func (ab *AssetBridge) getAppchain(chainID string) (*appchainMgr.Appchain, error) {
+	res := ab.CrossInvoke(constant.AppchainMgrContractAddr.Address().String(), "GetAppchain", pb.String(chainID))
+	if !res.Ok {
+		return nil, fmt.Errorf("get appchain %s error: %s", chainID, string(res.Result))
+	}
+	app := &appchainMgr.Appchain{}
+	if err := json.Unmarshal(res.Result, app); err != nil {
+		return nil, fmt.Errorf("unmarshal appchain error: %w", err)
+	}
+	return app, nil
+}
+
+func (ab *AssetBridge) getTokenPair(pairID string) (*TokenPair, bool) {
+	pair := &TokenPair{}
+	ok := ab.GetObject(BridgePairKey(pairID), pair)
+	return pair, ok
+}
+
+func (ab *AssetBridge) getBalance(wrappedToken, account string) uint64 {
+	var balance uint64
+	_ = ab.GetObject(BridgeBalanceKey(wrappedToken, account), &balance)
+	return balance
+}
+
+func (ab *AssetBridge) addBalance(wrappedToken, account string, amount uint64) {
+	ab.SetObject(BridgeBalanceKey(wrappedToken, account), ab.getBalance(wrappedToken, account)+amount)
+}
+
+func (ab *AssetBridge) subBalance(wrappedToken, account string, amount uint64) error {
+	balance := ab.getBalance(wrappedToken, account)
+	if balance < amount {
+		return fmt.Errorf("insufficient %s of %s: have %d want %d", wrappedToken, account, balance, amount)
+	}
+	ab.SetObject(BridgeBalanceKey(wrappedToken, account), balance-amount)
+	return nil
+}
+
+func (ab *AssetBridge) incNonce() uint64 {
+	var nonce uint64
+	_ = ab.GetObject(BRIDGE_NONCE_KEY, &nonce)
+	nonce++
+	ab.SetObject(BRIDGE_NONCE_KEY, nonce)
+	return nonce
+}
+
+func TokenPairID(chainID, originToken string) string {
+	return fmt.Sprintf("%s:%s", chainID, strings.ToLower(originToken))
+}
+
+func BridgePairKey(pairID string) string {
+	return fmt.Sprintf("%s-%s", BRIDGE_PAIR_PREFIX, pairID)
+}
+
+func BridgeWrappedKey(wrappedToken string) string {
+	return fmt.Sprintf("%s-%s", BRIDGE_WRAPPED_PREFIX, wrappedToken)
+}
+
+func BridgeBalanceKey(wrappedToken, account string) string {
+	return fmt.Sprintf("%s-%s-%s", BRIDGE_BALANCE_PREFIX, wrappedToken, account)
+}
+
+func BridgeLockKey(chainID, lockID string) string {
+	return fmt.Sprintf("%s-%s-%s", BRIDGE_LOCK_PREFIX, chainID, lockID)
+}
+
+func BridgeReleaseKey(releaseID string) string {
+	return fmt.Sprintf("%s-%s", BRIDGE_RELEASE_PREFIX, releaseID)
+}
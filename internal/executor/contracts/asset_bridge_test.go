@@ -0,0 +1,278 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	appchainMgr "github.com/meshplus/bitxhub-core/appchain-mgr"
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/boltvm/mock_stub"
+	"github.com/meshplus/bitxhub-core/governance"
+	ruleMgr "github.com/meshplus/bitxhub-core/rule-mgr"
+	"github.com/meshplus/bitxhub-core/validator/mock_validator"
+	"github.com/meshplus/bitxhub-kit/log"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	bridgeChainID   = "appchain-bridge"
+	bridgeToken     = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	bridgeWrapped   = "wUSDC"
+	bridgeRule      = "0x00000000000000000000000000000000000000a5"
+	bridgeIBTPRule  = "0x00000000000000000000000000000000000000a6"
+	bridgeProof     = "valid-proof"
+	bridgeUser      = "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b993"
+	bridgeRecipient = "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b994"
+)
+
+func bridgePrepare(t *testing.T) (*AssetBridge, *mock_stub.MockStub) {
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+	engine := mock_validator.NewMockEngine(mockCtl)
+
+	state := make(map[string][]byte)
+	mockStub.EXPECT().Get(gomock.Any()).DoAndReturn(func(key string) (bool, []byte) {
+		v, ok := state[key]
+		return ok, v
+	}).AnyTimes()
+	mockStub.EXPECT().Has(gomock.Any()).DoAndReturn(func(key string) bool {
+		_, ok := state[key]
+		return ok
+	}).AnyTimes()
+	mockStub.EXPECT().Set(gomock.Any(), gomock.Any()).DoAndReturn(func(key string, value []byte) {
+		state[key] = value
+	}).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).DoAndReturn(func(key string, ret interface{}) bool {
+		v, ok := state[key]
+		if !ok {
+			return false
+		}
+		assert.Nil(t, json.Unmarshal(v, ret))
+		return true
+	}).AnyTimes()
+	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).DoAndReturn(func(key string, value interface{}) {
+		data, err := json.Marshal(value)
+		assert.Nil(t, err)
+		state[key] = data
+	}).AnyTimes()
+
+	app, err := json.Marshal(&appchainMgr.Appchain{ID: bridgeChainID, Status: governance.GovernanceAvailable})
+	assert.Nil(t, err)
+	rule, err := json.Marshal(&ruleMgr.Rule{Address: bridgeIBTPRule, ChainID: bridgeChainID, Master: true})
+	assert.Nil(t, err)
+	mockStub.EXPECT().CrossInvoke(constant.AppchainMgrContractAddr.Address().String(), "GetAppchain", gomock.Any()).DoAndReturn(
+		func(addr, method string, args ...interface{}) *boltvm.Response {
+			return boltvm.Success(app)
+		}).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.RuleManagerContractAddr.Address().String(), "GetRuleByAddr", gomock.Any(), gomock.Any()).DoAndReturn(
+		func(addr, method string, args ...*pb.Arg) *boltvm.Response {
+			if string(args[1].Value) == bridgeIBTPRule {
+				return boltvm.Success(rule)
+			}
+			return boltvm.Error(boltvm.RuleInternalErrCode, "rule does not exist")
+		}).AnyTimes()
+	mockStub.EXPECT().ValidationEngine().Return(engine).AnyTimes()
+	engine.EXPECT().Validate(bridgeRule, bridgeChainID, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(address, from string, proof, payload []byte, validators string) (bool, uint64, error) {
+			return string(proof) == bridgeProof, 0, nil
+		}).AnyTimes()
+
+	mockStub.EXPECT().CurrentCaller().Return(constant.GovernanceContractAddr.Address().String()).AnyTimes()
+	mockStub.EXPECT().GetTxHash().Return(types.NewHash([]byte("tx"))).AnyTimes()
+	mockStub.EXPECT().Logger().Return(log.NewWithModule("contracts")).AnyTimes()
+
+	return &AssetBridge{Stub: mockStub}, mockStub
+}
+
+func setTokenPair(t *testing.T, ab *AssetBridge, supplyCap, rateLimit, window uint64) {
+	extra, err := json.Marshal(TokenPair{
+		ChainID:      bridgeChainID,
+		OriginToken:  bridgeToken,
+		WrappedToken: bridgeWrapped,
+		VerifierRule: bridgeRule,
+		SupplyCap:    supplyCap,
+		RateLimit:    rateLimit,
+		Window:       window,
+	})
+	assert.Nil(t, err)
+	res := ab.Manage(string(governance.EventUpdate), string(APPROVED), "", TokenPairID(bridgeChainID, bridgeToken), extra)
+	assert.True(t, res.Ok, string(res.Result))
+}
+
+func lockEvent(t *testing.T, id string, amount uint64) []byte {
+	data, err := json.Marshal(BridgeEvent{Type: BridgeEventLock, ID: id, Token: bridgeToken, Recipient: bridgeUser, Amount: amount})
+	assert.Nil(t, err)
+	return data
+}
+
+func TestAssetBridge_SetTokenPair(t *testing.T) {
+	ab, mockStub := bridgePrepare(t)
+
+	mockStub.EXPECT().Caller().Return(adminAddr).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte(FALSE))).Times(1)
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte(TRUE))).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.GovernanceContractAddr.Address().String(), "SubmitProposal",
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte("proposal-0"))).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.GovernanceContractAddr.Address().String(), "ZeroPermission", gomock.Any()).Return(boltvm.Success(nil)).AnyTimes()
+
+	// not governance admin
+	res := ab.SetTokenPair(bridgeChainID, bridgeToken, bridgeWrapped, bridgeRule, 100, 10, 60, reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = ab.SetTokenPair(bridgeChainID, "", bridgeWrapped, bridgeRule, 100, 10, 60, reason)
+	assert.False(t, res.Ok, string(res.Result))
+	res = ab.SetTokenPair(bridgeChainID, bridgeToken, bridgeWrapped, bridgeRule, 100, 10, 0, reason)
+	assert.False(t, res.Ok, string(res.Result))
+	res = ab.SetTokenPair(bridgeChainID, bridgeToken, bridgeWrapped, "", 100, 10, 60, reason)
+	assert.False(t, res.Ok, string(res.Result))
+	// the interchain rule of appchain can't verify bridge events
+	res = ab.SetTokenPair(bridgeChainID, bridgeToken, bridgeWrapped, bridgeIBTPRule, 100, 10, 60, reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = ab.SetTokenPair(bridgeChainID, bridgeToken, bridgeWrapped, bridgeRule, 100, 10, 60, reason)
+	assert.True(t, res.Ok, string(res.Result))
+	ret := &governance.GovernanceResult{}
+	assert.Nil(t, json.Unmarshal(res.Result, ret))
+	assert.Equal(t, "proposal-0", ret.ProposalID)
+
+	// wrapped token is bound to one pair
+	setTokenPair(t, ab, 100, 10, 60)
+	res = ab.SetTokenPair(bridgeChainID, "0x01", bridgeWrapped, bridgeRule, 100, 10, 60, reason)
+	assert.False(t, res.Ok, string(res.Result))
+	res = ab.SetTokenPair(bridgeChainID, bridgeToken, "wETH", bridgeRule, 100, 10, 60, reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// proposals submitted before the wrapped token is bound are not applied
+	extra, err := json.Marshal(TokenPair{ChainID: bridgeChainID, OriginToken: "0x01", WrappedToken: bridgeWrapped, VerifierRule: bridgeRule})
+	assert.Nil(t, err)
+	res = ab.Manage(string(governance.EventUpdate), string(APPROVED), "", TokenPairID(bridgeChainID, "0x01"), extra)
+	assert.False(t, res.Ok, string(res.Result))
+	extra, err = json.Marshal(TokenPair{ChainID: bridgeChainID, OriginToken: bridgeToken, WrappedToken: "wETH", VerifierRule: bridgeRule})
+	assert.Nil(t, err)
+	res = ab.Manage(string(governance.EventUpdate), string(APPROVED), "", TokenPairID(bridgeChainID, bridgeToken), extra)
+	assert.False(t, res.Ok, string(res.Result))
+	res = ab.GetTokenPair(bridgeChainID, "0x01")
+	assert.False(t, res.Ok, string(res.Result))
+	res = ab.GetTokenPair(bridgeChainID, bridgeToken)
+	assert.True(t, res.Ok, string(res.Result))
+	pair := &TokenPair{}
+	assert.Nil(t, json.Unmarshal(res.Result, pair))
+	assert.Equal(t, bridgeWrapped, pair.WrappedToken)
+	assert.Equal(t, bridgeRule, pair.VerifierRule)
+}
+
+func TestAssetBridge_MintAndBurn(t *testing.T) {
+	ab, mockStub := bridgePrepare(t)
+
+	var now int64
+	mockStub.EXPECT().GetTxTimeStamp().DoAndReturn(func() int64 { return now }).AnyTimes()
+	caller := bridgeUser
+	mockStub.EXPECT().Caller().DoAndReturn(func() string { return caller }).AnyTimes()
+
+	// token is not bridged
+	res := ab.Mint(bridgeChainID, lockEvent(t, "lock-1", 10), []byte(bridgeProof))
+	assert.False(t, res.Ok, string(res.Result))
+
+	setTokenPair(t, ab, 100, 50, 60)
+
+	// invalid proof
+	res = ab.Mint(bridgeChainID, lockEvent(t, "lock-1", 10), []byte("invalid"))
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = ab.Mint(bridgeChainID, lockEvent(t, "lock-1", 10), []byte(bridgeProof))
+	assert.True(t, res.Ok, string(res.Result))
+	res = ab.BalanceOf(bridgeWrapped, bridgeUser)
+	assert.Equal(t, "10", string(res.Result))
+	res = ab.IsMinted(bridgeChainID, "lock-1")
+	assert.Equal(t, TRUE, string(res.Result))
+
+	// replayed lock
+	res = ab.Mint(bridgeChainID, lockEvent(t, "lock-1", 10), []byte(bridgeProof))
+	assert.False(t, res.Ok, string(res.Result))
+
+	// rate limit in the window
+	res = ab.Mint(bridgeChainID, lockEvent(t, "lock-2", 41), []byte(bridgeProof))
+	assert.False(t, res.Ok, string(res.Result))
+	res = ab.Mint(bridgeChainID, lockEvent(t, "lock-2", 40), []byte(bridgeProof))
+	assert.True(t, res.Ok, string(res.Result))
+
+	// supply cap in the next window
+	now = 61 * SecondTime
+	res = ab.Mint(bridgeChainID, lockEvent(t, "lock-3", 51), []byte(bridgeProof))
+	assert.False(t, res.Ok, string(res.Result))
+	res = ab.Mint(bridgeChainID, lockEvent(t, "lock-3", 50), []byte(bridgeProof))
+	assert.True(t, res.Ok, string(res.Result))
+	res = ab.Mint(bridgeChainID, lockEvent(t, "lock-4", 1), []byte(bridgeProof))
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = ab.Transfer(bridgeWrapped, bridgeRecipient, 30)
+	assert.True(t, res.Ok, string(res.Result))
+	res = ab.Transfer(bridgeWrapped, bridgeRecipient, 100)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// burn to release on the origin appchain
+	now = 200 * SecondTime
+	res = ab.Burn(bridgeWrapped, bridgeRecipient, 100)
+	assert.False(t, res.Ok, string(res.Result))
+	res = ab.Burn(bridgeWrapped, bridgeRecipient, 20)
+	assert.True(t, res.Ok, string(res.Result))
+	release := &Release{}
+	assert.Nil(t, json.Unmarshal(res.Result, release))
+	assert.Equal(t, fmt.Sprintf("%s:1", TokenPairID(bridgeChainID, bridgeToken)), release.ID)
+	assert.Equal(t, bridgeToken, release.Token)
+	assert.Equal(t, ReleasePending, release.Status)
+
+	res = ab.BalanceOf(bridgeWrapped, bridgeUser)
+	assert.Equal(t, "50", string(res.Result))
+	res = ab.GetTokenPair(bridgeChainID, bridgeToken)
+	assert.True(t, res.Ok, string(res.Result))
+	pair := &TokenPair{}
+	assert.Nil(t, json.Unmarshal(res.Result, pair))
+	assert.Equal(t, uint64(80), pair.Supply)
+
+	// release is confirmed only once with the matched event
+	event, err := json.Marshal(BridgeEvent{Type: BridgeEventRelease, ID: release.ID, Token: bridgeToken, Recipient: bridgeRecipient, Amount: 21})
+	assert.Nil(t, err)
+	res = ab.ConfirmRelease(release.ID, event, []byte(bridgeProof))
+	assert.False(t, res.Ok, string(res.Result))
+	event, err = json.Marshal(BridgeEvent{Type: BridgeEventRelease, ID: release.ID, Token: bridgeToken, Recipient: bridgeRecipient, Amount: 20})
+	assert.Nil(t, err)
+	res = ab.ConfirmRelease(release.ID, event, []byte(bridgeProof))
+	assert.True(t, res.Ok, string(res.Result))
+	res = ab.ConfirmRelease(release.ID, event, []byte(bridgeProof))
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = ab.GetRelease(release.ID)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Nil(t, json.Unmarshal(res.Result, release))
+	assert.Equal(t, ReleaseReleased, release.Status)
+}
+
+func TestAssetBridge_RegisterBridgeProofVerifier(t *testing.T) {
+	ab, mockStub := bridgePrepare(t)
+	mockStub.EXPECT().GetTxTimeStamp().Return(int64(0)).AnyTimes()
+	setTokenPair(t, ab, 0, 0, 0)
+
+	RegisterBridgeProofVerifier(bridgeRule, func(stub boltvm.Stub, app *appchainMgr.Appchain, ruleAddr string, event, proof []byte) error {
+		if _, err := strconv.Atoi(string(proof)); err != nil {
+			return err
+		}
+		return nil
+	})
+	defer func() {
+		bridgeVerifierLock.Lock()
+		delete(bridgeVerifiers, bridgeRule)
+		bridgeVerifierLock.Unlock()
+	}()
+
+	res := ab.Mint(bridgeChainID, lockEvent(t, "lock-1", 10), []byte(bridgeProof))
+	assert.False(t, res.Ok, string(res.Result))
+	res = ab.Mint(bridgeChainID, lockEvent(t, "lock-1", 10), []byte("1"))
+	assert.True(t, res.Ok, string(res.Result))
+}
@@ -26,8 +26,18 @@ const (
 	InterchainSwapAddrKey = "interchain_swap_addr_key"
 	ProxyAddrKey          = "proxy_addr_key"
 	EthTxHashPrefix       = "eth-hash"
+	EthHeaderPrefix       = "eth-header"
+	EthCanonicalPrefix    = "eth-canonical"
+	EthHeadKey            = "eth-head"
 )
 
+// EthHeaderRecord is the part of an ethereum block header accepted by the light client oracle
+// which is kept in the ledger, so receipt proofs are checked against the same headers on every node
+type EthHeaderRecord struct {
+	Number      uint64      `json:"number"`
+	ReceiptHash common.Hash `json:"receipt_hash"`
+}
+
 type ContractAddr struct {
 	Addr string `json:"addr"`
 }
@@ -116,6 +126,23 @@ func (ehm *EthHeaderManager) InsertBlockHeaders(headersData []byte) *boltvm.Resp
 	if err != nil {
 		return boltvm.Error(boltvm.AssetInternalErrCode, err.Error())
 	}
+
+	var head uint64
+	ehm.GetObject(EthHeadKey, &head)
+	for _, header := range headers {
+		number := header.Number.Uint64()
+		ehm.SetObject(EthHeaderKey(header.Hash().String()), &EthHeaderRecord{
+			Number:      number,
+			ReceiptHash: header.ReceiptHash,
+		})
+		// the later inserted header of the same number is the one on the canonical chain after reorg
+		ehm.SetObject(EthCanonicalKey(number), header.Hash().String())
+		if number > head {
+			head = number
+		}
+	}
+	ehm.SetObject(EthHeadKey, head)
+
 	return boltvm.Success([]byte(strconv.Itoa(num)))
 }
 
@@ -139,6 +166,9 @@ func (ehm *EthHeaderManager) GetBlockHeader(hash string) *boltvm.Response {
 	return boltvm.Success(data)
 }
 
+// Mint mints the relay chain asset of the escrow lock on ethereum.
+//
+// Deprecated: use AssetBridge, which supports any appchain token registered by governance.
 func (ehm *EthHeaderManager) Mint(receiptData []byte, _ []byte) *boltvm.Response {
 	var (
 		interchainSwapAddr *ContractAddr
@@ -289,3 +319,11 @@ func (ehm *EthHeaderManager) unpackEscrowsLock(receipt *types.Receipt) (*ec.Escr
 func EthTxKey(hash string) string {
 	return fmt.Sprintf("%s-%s", EthTxHashPrefix, hash)
 }
+
+func EthHeaderKey(hash string) string {
+	return fmt.Sprintf("%s-%s", EthHeaderPrefix, hash)
+}
+
+func EthCanonicalKey(number uint64) string {
+	return fmt.Sprintf("%s-%d", EthCanonicalPrefix, number)
+}
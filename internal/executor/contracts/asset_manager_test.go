@@ -34,7 +34,9 @@ func TestEthHeaderManager(t *testing.T) {
 	contractAddr := &ContractAddr{address}
 	mockCtl := gomock.NewController(t)
 	mockStub := mock_stub.NewMockStub(mockCtl)
+	mockStub.EXPECT().SetObject(EthHeadKey, uint64(10105114)).Return().Times(1)
 	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).Return().AnyTimes()
+	mockStub.EXPECT().GetObject(EthHeadKey, gomock.Any()).Return(false).AnyTimes()
 	mockStub.EXPECT().GetObject(EscrowsAddrKey+pier, gomock.Any()).SetArg(1, *contractAddr).Return(true).AnyTimes()
 	mockStub.EXPECT().GetObject(InterchainSwapAddrKey, gomock.Any()).SetArg(1, *contractAddr).Return(true)
 	mockStub.EXPECT().GetObject(InterchainSwapAddrKey, gomock.Any()).SetArg(1, contractAddr).Return(true).AnyTimes()
@@ -51,6 +51,11 @@ type Dapp struct {
 	OwnerAddr    string              `json:"owner_addr"`
 	CreateTime   int64               `json:"create_time"`
 
+	// CurrentVersion is the promoted version whose url, contracts and permission are in effect,
+	// LatestVersion is the newest version recorded for the dapp
+	CurrentVersion uint64 `json:"current_version"`
+	LatestVersion  uint64 `json:"latest_version"`
+
 	Score             float64                                 `json:"score"`
 	EvaluationRecords map[string]*governance.EvaluationRecord `json:"evaluation_records"`
 	TransferRecords   []*pb.TransferRecord                    `json:"transfer_records"`
@@ -65,6 +70,8 @@ type UpdateDappInfo struct {
 	Url          UpdateInfo    `json:"url"`
 	ContractAddr UpdateMapInfo `json:"contract_addr"`
 	Permission   UpdateMapInfo `json:"permission"`
+	// Version is set when the update promotes an existing version
+	Version uint64 `json:"version,omitempty"`
 }
 
 var dappStateMap = map[governance.EventType][]governance.GovernanceStatus{
@@ -316,6 +323,7 @@ func (dm *DappManager) Manage(eventTyp, proposalResult, lastStatus, objId string
 				return boltvm.Error(boltvm.DappInternalErrCode, "the dapp is not exist")
 			}
 			dapp.CreateTime = dm.GetTxTimeStamp()
+			dm.initVersion(dapp)
 			dm.register(dapp)
 		case string(governance.EventUpdate):
 			updateInfo := &UpdateDappInfo{}
@@ -330,6 +338,9 @@ func (dm *DappManager) Manage(eventTyp, proposalResult, lastStatus, objId string
 				dm.freeContractAddr(updateInfo.ContractAddr.OldInfo)
 				dm.occupyContractAddr(updateInfo.ContractAddr.NewInfo, objId)
 			}
+			if err := dm.recordUpdateVersion(objId, updateInfo); err != nil {
+				return boltvm.Error(boltvm.DappInternalErrCode, fmt.Sprintf("record version error: %v", err))
+			}
 			if err := dm.update(&Dapp{
 				DappID:       objId,
 				Name:         updateInfo.DappName.NewInfo.(string),
@@ -462,28 +473,8 @@ func (dm *DappManager) UpdateDapp(id, name, desc, url, conAddrs, permits, reason
 	// update desc do not need proposal
 	updateName := newDapp.Name != oldDapp.Name
 	updateUrl := newDapp.Url != oldDapp.Url
-	updateContract := false
-	if len(newDapp.ContractAddr) != len(oldDapp.ContractAddr) {
-		updateContract = true
-	} else {
-		for addr := range oldDapp.ContractAddr {
-			if _, ok := newDapp.ContractAddr[addr]; !ok {
-				updateContract = true
-				break
-			}
-		}
-	}
-	updatePermission := false
-	if len(newDapp.Permission) != len(oldDapp.Permission) {
-		updatePermission = true
-	} else {
-		for addr := range oldDapp.Permission {
-			if _, ok := newDapp.Permission[addr]; !ok {
-				updatePermission = true
-				break
-			}
-		}
-	}
+	updateContract := isAddrSetChanged(oldDapp.ContractAddr, newDapp.ContractAddr)
+	updatePermission := isAddrSetChanged(oldDapp.Permission, newDapp.Permission)
 	if !updateName && !updateUrl && !updateContract && !updatePermission {
 		if err := dm.update(newDapp); err != nil {
 			return boltvm.Error(boltvm.DappInternalErrCode, fmt.Sprintf("update error: %v", err))
@@ -496,6 +487,13 @@ func (dm *DappManager) UpdateDapp(id, name, desc, url, conAddrs, permits, reason
 		return getGovernanceRet("", nil)
 	}
 
+	return dm.submitUpdate(oldDapp, newDapp, updateName, updateUrl, updateContract, updatePermission, 0, reason)
+}
+
+func (dm *DappManager) submitUpdate(oldDapp, newDapp *Dapp, updateName, updateUrl, updateContract, updatePermission bool, version uint64, reason string) *boltvm.Response {
+	event := governance.EventUpdate
+	id := oldDapp.DappID
+
 	// 5. pre store dapp contract addr
 	if updateName {
 		dm.occupyDappName(newDapp.Name, id)
@@ -531,6 +529,7 @@ func (dm *DappManager) UpdateDapp(id, name, desc, url, conAddrs, permits, reason
 			NewInfo: newDapp.Permission,
 			IsEdit:  updatePermission,
 		},
+		Version: version,
 	}
 	updateDappData, err := json.Marshal(updateDappInfo)
 	if err != nil {
@@ -713,10 +712,11 @@ func (dm *DappManager) EvaluateDapp(id, desc string, score float64) *boltvm.Resp
 
 // ========================== Query interface ========================
 // GetDapp returns dapp info by dapp id
+// id can pin a version with the form "dappID@version", then the url, contract addr and permission
+// of that version are returned instead of the current ones
 func (dm *DappManager) GetDapp(id string) *boltvm.Response {
-	dapp := &Dapp{}
-	ok := dm.GetObject(DappKey(id), dapp)
-	if !ok {
+	dapp, err := dm.getPinnedDapp(id)
+	if err != nil {
 		return boltvm.Error(boltvm.DappNonexistentDappCode, fmt.Sprintf(string(boltvm.DappNonexistentDappMsg), id))
 	}
 
@@ -867,10 +867,10 @@ func (dm *DappManager) IsAvailable(dappID string) *boltvm.Response {
 	return boltvm.Success([]byte(strconv.FormatBool(dm.isAvailable(dappID))))
 }
 
+// isAvailable also accepts a pinned "dappID@version", which requires the version to exist
 func (dm *DappManager) isAvailable(dappID string) bool {
-	dapp := &Dapp{}
-	ok := dm.GetObject(DappKey(dappID), dapp)
-	if !ok {
+	dapp, err := dm.getPinnedDapp(dappID)
+	if err != nil {
 		return false
 	} else {
 		return dapp.IsAvailable()
@@ -981,6 +981,18 @@ func (dm *DappManager) checkDappInfo(dapp *Dapp, isRegister bool) *boltvm.Respon
 	return boltvm.Success(nil)
 }
 
+func isAddrSetChanged(oldSet, newSet map[string]struct{}) bool {
+	if len(oldSet) != len(newSet) {
+		return true
+	}
+	for addr := range oldSet {
+		if _, ok := newSet[addr]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
 func DappKey(id string) string {
 	return fmt.Sprintf("%s-%s", DappPrefix, id)
 }
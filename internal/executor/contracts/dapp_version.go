@@ -0,0 +1,277 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/governance"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DappVersionPrefix must not start with DappPrefix, otherwise versions would be listed as dapps
+	DappVersionPrefix = "version-dapp"
+	DappVersionSep    = "@"
+)
+
+// DappVersion is an immutable snapshot of the parts of a dapp that decide which contracts users call
+type DappVersion struct {
+	DappID       string              `json:"dapp_id"`
+	Version      uint64              `json:"version"`
+	Url          string              `json:"url"`
+	ContractAddr map[string]struct{} `json:"contract_addr"`
+	Permission   map[string]struct{} `json:"permission"`
+	Creator      string              `json:"creator"`
+	CreateTime   int64               `json:"create_time"`
+}
+
+// initVersion records the first version of a dapp, it also covers dapps registered before versioning
+func (dm *DappManager) initVersion(dapp *Dapp) {
+	if dapp.LatestVersion != 0 {
+		return
+	}
+	version := dm.addVersion(dapp, dapp.Url, dapp.ContractAddr, dapp.Permission, dapp.OwnerAddr)
+	dapp.CurrentVersion = version.Version
+}
+
+// addVersion stores a new version record and bumps the latest version of the dapp (not stored)
+func (dm *DappManager) addVersion(dapp *Dapp, url string, contractAddr, permission map[string]struct{}, creator string) *DappVersion {
+	dapp.LatestVersion++
+	version := &DappVersion{
+		DappID:       dapp.DappID,
+		Version:      dapp.LatestVersion,
+		Url:          url,
+		ContractAddr: contractAddr,
+		Permission:   permission,
+		Creator:      creator,
+		CreateTime:   dm.GetTxTimeStamp(),
+	}
+	dm.SetObject(DappVersionKey(dapp.DappID, version.Version), *version)
+
+	dm.Logger().WithFields(logrus.Fields{
+		"id":      dapp.DappID,
+		"version": version.Version,
+	}).Info("Dapp version is recorded")
+	return version
+}
+
+// recordUpdateVersion moves the current version of the dapp after an update proposal is approved:
+// a promotion points to the promoted version, a direct update of url, contracts or permission creates a new version
+func (dm *DappManager) recordUpdateVersion(dappID string, updateInfo *UpdateDappInfo) error {
+	if updateInfo.Version == 0 && !updateInfo.Url.IsEdit && !updateInfo.ContractAddr.IsEdit && !updateInfo.Permission.IsEdit {
+		return nil
+	}
+
+	dapp := &Dapp{}
+	if ok := dm.GetObject(DappKey(dappID), dapp); !ok {
+		return fmt.Errorf("the dapp is not exist")
+	}
+
+	if updateInfo.Version != 0 {
+		if _, ok := dm.getVersion(dapp, updateInfo.Version); !ok {
+			return fmt.Errorf("the version %d of dapp %s is not exist", updateInfo.Version, dappID)
+		}
+		dapp.CurrentVersion = updateInfo.Version
+	} else {
+		dm.initVersion(dapp)
+		version := dm.addVersion(dapp, updateInfo.Url.NewInfo.(string), updateInfo.ContractAddr.NewInfo, updateInfo.Permission.NewInfo, dapp.OwnerAddr)
+		dapp.CurrentVersion = version.Version
+	}
+
+	dm.SetObject(DappKey(dappID), *dapp)
+	return nil
+}
+
+// getVersion returns the given version of the dapp, 0 means the current version.
+// Dapps registered before versioning have an implicit version 1 built from their current info.
+func (dm *DappManager) getVersion(dapp *Dapp, version uint64) (*DappVersion, bool) {
+	if dapp.LatestVersion == 0 {
+		if version > 1 {
+			return nil, false
+		}
+		return &DappVersion{
+			DappID:       dapp.DappID,
+			Version:      1,
+			Url:          dapp.Url,
+			ContractAddr: dapp.ContractAddr,
+			Permission:   dapp.Permission,
+			Creator:      dapp.OwnerAddr,
+			CreateTime:   dapp.CreateTime,
+		}, true
+	}
+
+	if version == 0 {
+		version = dapp.CurrentVersion
+	}
+	ret := &DappVersion{}
+	if ok := dm.GetObject(DappVersionKey(dapp.DappID, version), ret); !ok {
+		return nil, false
+	}
+	return ret, true
+}
+
+// getPinnedDapp returns the dapp referenced by "dappID" or "dappID@version"
+func (dm *DappManager) getPinnedDapp(ref string) (*Dapp, error) {
+	id, version, err := ParseDappRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	dapp := &Dapp{}
+	if ok := dm.GetObject(DappKey(id), dapp); !ok {
+		return nil, fmt.Errorf("the dapp %s is not exist", id)
+	}
+	if version == 0 {
+		return dapp, nil
+	}
+
+	v, ok := dm.getVersion(dapp, version)
+	if !ok {
+		return nil, fmt.Errorf("the version %d of dapp %s is not exist", version, id)
+	}
+	dapp.Url = v.Url
+	dapp.ContractAddr = v.ContractAddr
+	dapp.Permission = v.Permission
+	return dapp, nil
+}
+
+// StageDappVersion records a new version of the dapp without putting it into effect,
+// the version takes effect after PromoteDappVersion is approved
+func (dm *DappManager) StageDappVersion(id, url, conAddrs, permits string) *boltvm.Response {
+	// 1. get dapp
+	dapp := &Dapp{}
+	if ok := dm.GetObject(DappKey(id), dapp); !ok {
+		return boltvm.Error(boltvm.DappNonexistentDappCode, fmt.Sprintf(string(boltvm.DappNonexistentDappMsg), id))
+	}
+	if dapp.Status == governance.GovernanceUnavailable || dapp.Status == governance.GovernanceRegisting {
+		return boltvm.Error(boltvm.DappStatusErrorCode, fmt.Sprintf("the dapp %s is %s and can not stage a version", id, string(dapp.Status)))
+	}
+
+	// 2. check permission: PermissionSelf
+	if err := dm.checkPermission([]string{string(PermissionSelf)}, dapp.OwnerAddr, dm.CurrentCaller(), nil); err != nil {
+		return boltvm.Error(boltvm.DappNoPermissionCode, fmt.Sprintf(string(boltvm.DappNoPermissionMsg), dm.CurrentCaller(), err.Error()))
+	}
+
+	// 3. check info, contract addrs are occupied only when the version is promoted
+	staged := dm.packageDappInfo(id, dapp.Name, string(dapp.Type), dapp.Desc, url, conAddrs, permits, dapp.OwnerAddr, dapp.Score, dapp.CreateTime, dapp.EvaluationRecords, dapp.TransferRecords, dapp.Status)
+	if res := dm.checkDappInfo(staged, false); !res.Ok {
+		return res
+	}
+
+	// 4. record version
+	dm.initVersion(dapp)
+	version := dm.addVersion(dapp, staged.Url, staged.ContractAddr, staged.Permission, dm.Caller())
+	dm.SetObject(DappKey(id), *dapp)
+
+	if dm.EnableAudit() {
+		if err := dm.postAuditDappEvent(id); err != nil {
+			return boltvm.Error(boltvm.DappInternalErrCode, fmt.Sprintf("post audit dapp event error: %v", err))
+		}
+	}
+	return getGovernanceRet("", []byte(strconv.FormatUint(version.Version, 10)))
+}
+
+// PromoteDappVersion submits an update proposal which puts the given version into effect,
+// promoting an older version rolls the dapp back
+func (dm *DappManager) PromoteDappVersion(id string, version uint64, reason string) *boltvm.Response {
+	// 1. governance pre: check if exist and status
+	oldDapp, be := dm.governancePre(id, governance.EventUpdate)
+	if be != nil {
+		return boltvm.Error(be.Code, string(be.Msg))
+	}
+
+	// 2. check permission: PermissionSelf
+	if err := dm.checkPermission([]string{string(PermissionSelf)}, oldDapp.OwnerAddr, dm.CurrentCaller(), nil); err != nil {
+		return boltvm.Error(boltvm.DappNoPermissionCode, fmt.Sprintf(string(boltvm.DappNoPermissionMsg), dm.CurrentCaller(), err.Error()))
+	}
+
+	// 3. get version
+	if version == 0 {
+		return boltvm.Error(boltvm.DappInternalErrCode, "the version to promote should be greater than 0")
+	}
+	if version == oldDapp.CurrentVersion {
+		return boltvm.Error(boltvm.DappInternalErrCode, fmt.Sprintf("the version %d of dapp %s is already in effect", version, id))
+	}
+	v, ok := dm.getVersion(oldDapp, version)
+	if !ok {
+		return boltvm.Error(boltvm.DappInternalErrCode, fmt.Sprintf("the version %d of dapp %s is not exist", version, id))
+	}
+
+	// 4. check info, the contracts may be occupied by other dapps since the version was recorded
+	newDapp := dm.packageDappInfo(id, oldDapp.Name, string(oldDapp.Type), oldDapp.Desc, v.Url, "", "", oldDapp.OwnerAddr, oldDapp.Score, oldDapp.CreateTime, oldDapp.EvaluationRecords, oldDapp.TransferRecords, oldDapp.Status)
+	newDapp.ContractAddr = v.ContractAddr
+	newDapp.Permission = v.Permission
+	if res := dm.checkDappInfo(newDapp, false); !res.Ok {
+		return res
+	}
+
+	return dm.submitUpdate(oldDapp, newDapp, false, oldDapp.Url != newDapp.Url,
+		isAddrSetChanged(oldDapp.ContractAddr, newDapp.ContractAddr), isAddrSetChanged(oldDapp.Permission, newDapp.Permission), version, reason)
+}
+
+// GetDappVersion returns the given version of the dapp, 0 means the current version
+func (dm *DappManager) GetDappVersion(id string, version uint64) *boltvm.Response {
+	dapp := &Dapp{}
+	if ok := dm.GetObject(DappKey(id), dapp); !ok {
+		return boltvm.Error(boltvm.DappNonexistentDappCode, fmt.Sprintf(string(boltvm.DappNonexistentDappMsg), id))
+	}
+
+	v, ok := dm.getVersion(dapp, version)
+	if !ok {
+		return boltvm.Error(boltvm.DappInternalErrCode, fmt.Sprintf("the version %d of dapp %s is not exist", version, id))
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return boltvm.Error(boltvm.DappInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+// GetDappVersions returns all versions of the dapp in ascending order
+func (dm *DappManager) GetDappVersions(id string) *boltvm.Response {
+	dapp := &Dapp{}
+	if ok := dm.GetObject(DappKey(id), dapp); !ok {
+		return boltvm.Error(boltvm.DappNonexistentDappCode, fmt.Sprintf(string(boltvm.DappNonexistentDappMsg), id))
+	}
+
+	ret := make([]*DappVersion, 0)
+	latest := dapp.LatestVersion
+	if latest == 0 {
+		latest = 1
+	}
+	for i := uint64(1); i <= latest; i++ {
+		v, ok := dm.getVersion(dapp, i)
+		if !ok {
+			return boltvm.Error(boltvm.DappInternalErrCode, fmt.Sprintf("the version %d of dapp %s is not exist", i, id))
+		}
+		ret = append(ret, v)
+	}
+
+	data, err := json.Marshal(ret)
+	if err != nil {
+		return boltvm.Error(boltvm.DappInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+// ParseDappRef splits "dappID@version" into dapp id and version, version is 0 if not pinned
+func ParseDappRef(ref string) (string, uint64, error) {
+	idx := strings.LastIndex(ref, DappVersionSep)
+	if idx == -1 {
+		return ref, 0, nil
+	}
+
+	version, err := strconv.ParseUint(ref[idx+1:], 10, 64)
+	if err != nil || version == 0 {
+		return "", 0, fmt.Errorf("illegal dapp version in %s", ref)
+	}
+	return ref[:idx], version, nil
+}
+
+func DappVersionKey(id string, version uint64) string {
+	return fmt.Sprintf("%s-%s-%d", DappVersionPrefix, id, version)
+}
@@ -0,0 +1,245 @@
+package contracts
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/governance"
+	"github.com/meshplus/bitxhub-kit/log"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDappRef(t *testing.T) {
+	id, version, err := ParseDappRef(dappID)
+	assert.Nil(t, err)
+	assert.Equal(t, dappID, id)
+	assert.Equal(t, uint64(0), version)
+
+	id, version, err = ParseDappRef(dappID + "@2")
+	assert.Nil(t, err)
+	assert.Equal(t, dappID, id)
+	assert.Equal(t, uint64(2), version)
+
+	_, _, err = ParseDappRef(dappID + "@0")
+	assert.NotNil(t, err)
+	_, _, err = ParseDappRef(dappID + "@v1")
+	assert.NotNil(t, err)
+}
+
+func TestDappManager_StageDappVersion(t *testing.T) {
+	dm, mockStub, dapps, dappsData := dappPrepare(t)
+
+	mockStub.EXPECT().GetObject(DappKey(dapps[0].DappID), gomock.Any()).SetArg(1, *dapps[0]).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(DappKey(dapps[3].DappID), gomock.Any()).SetArg(1, *dapps[3]).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(DappOccupyNameKey(dapps[0].Name), gomock.Any()).SetArg(1, dapps[0].DappID).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(DappOccupyContractKey(conAddr2), gomock.Any()).SetArg(1, dapps[1].DappID).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	mockStub.EXPECT().CurrentCaller().Return(noAdminAddr).Times(1)
+	mockStub.EXPECT().CurrentCaller().Return(ownerAddr).AnyTimes()
+	mockStub.EXPECT().Caller().Return(ownerAddr).AnyTimes()
+	mockStub.EXPECT().GetTxTimeStamp().Return(int64(0)).AnyTimes()
+	mockStub.EXPECT().GetAccount(gomock.Any()).Return(mockAccount(t)).AnyTimes()
+	mockStub.EXPECT().Logger().Return(log.NewWithModule("contracts")).AnyTimes()
+	mockStub.EXPECT().PostEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockStub.EXPECT().Get(gomock.Any()).Return(true, dappsData[0]).AnyTimes()
+	mockStub.EXPECT().EnableAudit().Return(true).AnyTimes()
+
+	stored := make(map[string]interface{})
+	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).DoAndReturn(func(key string, value interface{}) {
+		stored[key] = value
+	}).AnyTimes()
+
+	// nonexistent dapp
+	res := dm.StageDappVersion("id", "url", conAddr1, "")
+	assert.False(t, res.Ok, string(res.Result))
+	// registering dapp
+	res = dm.StageDappVersion(dapps[3].DappID, "url", conAddr1, "")
+	assert.False(t, res.Ok, string(res.Result))
+	// not the owner
+	res = dm.StageDappVersion(dapps[0].DappID, "url", conAddr1, "")
+	assert.False(t, res.Ok, string(res.Result))
+	// contract occupied by other dapp
+	res = dm.StageDappVersion(dapps[0].DappID, "url", conAddr2, "")
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = dm.StageDappVersion(dapps[0].DappID, "url2", conAddr1, "")
+	assert.True(t, res.Ok, string(res.Result))
+	ret := &governance.GovernanceResult{}
+	assert.Nil(t, json.Unmarshal(res.Result, ret))
+	assert.Equal(t, "2", string(ret.Extra))
+
+	// the current info of an unversioned dapp becomes version 1
+	base := stored[DappVersionKey(dapps[0].DappID, 1)].(DappVersion)
+	assert.Equal(t, "url", base.Url)
+	staged := stored[DappVersionKey(dapps[0].DappID, 2)].(DappVersion)
+	assert.Equal(t, "url2", staged.Url)
+	dapp := stored[DappKey(dapps[0].DappID)].(Dapp)
+	assert.Equal(t, uint64(1), dapp.CurrentVersion)
+	assert.Equal(t, uint64(2), dapp.LatestVersion)
+	assert.Equal(t, "url", dapp.Url)
+}
+
+func TestDappManager_PromoteDappVersion(t *testing.T) {
+	dm, mockStub, dapps, dappsData := dappPrepare(t)
+
+	dapp := *dapps[0]
+	dapp.CurrentVersion = 1
+	dapp.LatestVersion = 2
+	mockStub.EXPECT().GetObject(DappKey(dapp.DappID), gomock.Any()).SetArg(1, dapp).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(DappVersionKey(dapp.DappID, 2), gomock.Any()).SetArg(1, DappVersion{
+		DappID:       dapp.DappID,
+		Version:      2,
+		Url:          "url2",
+		ContractAddr: map[string]struct{}{conAddr1: {}},
+		Permission:   map[string]struct{}{},
+	}).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(DappOccupyNameKey(dapp.Name), gomock.Any()).SetArg(1, dapp.DappID).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	mockStub.EXPECT().CurrentCaller().Return(ownerAddr).AnyTimes()
+	mockStub.EXPECT().Caller().Return(ownerAddr).AnyTimes()
+	mockStub.EXPECT().GetAccount(gomock.Any()).Return(mockAccount(t)).AnyTimes()
+	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).Return().AnyTimes()
+	mockStub.EXPECT().PostEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockStub.EXPECT().Get(gomock.Any()).Return(true, dappsData[0]).AnyTimes()
+	mockStub.EXPECT().EnableAudit().Return(true).AnyTimes()
+
+	var extra []byte
+	mockStub.EXPECT().CrossInvoke(constant.GovernanceContractAddr.String(), "SubmitProposal",
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(addr, method string, args ...*pb.Arg) *boltvm.Response {
+			extra = args[len(args)-1].Value
+			return boltvm.Success(nil)
+		}).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(gomock.Eq(constant.GovernanceContractAddr.Address().String()), gomock.Eq("ZeroPermission"),
+		gomock.Any()).Return(boltvm.Success(nil)).AnyTimes()
+
+	// already in effect
+	res := dm.PromoteDappVersion(dapp.DappID, 1, reason)
+	assert.False(t, res.Ok, string(res.Result))
+	// nonexistent version
+	res = dm.PromoteDappVersion(dapp.DappID, 3, reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = dm.PromoteDappVersion(dapp.DappID, 2, reason)
+	assert.True(t, res.Ok, string(res.Result))
+	updateInfo := &UpdateDappInfo{}
+	assert.Nil(t, json.Unmarshal(extra, updateInfo))
+	assert.Equal(t, uint64(2), updateInfo.Version)
+	assert.True(t, updateInfo.Url.IsEdit)
+	assert.Equal(t, "url2", updateInfo.Url.NewInfo)
+	assert.False(t, updateInfo.ContractAddr.IsEdit)
+}
+
+func TestDappManager_ManagePromote(t *testing.T) {
+	dm, mockStub, dapps, dappsData := dappPrepare(t)
+
+	dapp := *dapps[4]
+	dapp.CurrentVersion = 2
+	dapp.LatestVersion = 2
+	mockStub.EXPECT().GetObject(DappKey(dapp.DappID), gomock.Any()).SetArg(1, dapp).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(DappVersionKey(dapp.DappID, 1), gomock.Any()).Return(true).AnyTimes()
+	mockStub.EXPECT().CurrentCaller().Return(constant.GovernanceContractAddr.Address().String()).AnyTimes()
+	mockStub.EXPECT().GetTxTimeStamp().Return(int64(0)).AnyTimes()
+	mockStub.EXPECT().Logger().Return(log.NewWithModule("contracts")).AnyTimes()
+	mockStub.EXPECT().Delete(gomock.Any()).Return().AnyTimes()
+	mockStub.EXPECT().PostEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockStub.EXPECT().Get(gomock.Any()).Return(true, dappsData[4]).AnyTimes()
+	mockStub.EXPECT().EnableAudit().Return(true).AnyTimes()
+
+	var saved []Dapp
+	mockStub.EXPECT().SetObject(DappKey(dapp.DappID), gomock.Any()).DoAndReturn(func(key string, value interface{}) {
+		saved = append(saved, value.(Dapp))
+	}).AnyTimes()
+	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).Return().AnyTimes()
+
+	// roll back to version 1
+	updateInfo := &UpdateDappInfo{
+		DappName:     UpdateInfo{OldInfo: dapp.Name, NewInfo: dapp.Name},
+		Desc:         UpdateInfo{OldInfo: dapp.Desc, NewInfo: dapp.Desc},
+		Url:          UpdateInfo{OldInfo: dapp.Url, NewInfo: "url1", IsEdit: true},
+		ContractAddr: UpdateMapInfo{OldInfo: dapp.ContractAddr, NewInfo: dapp.ContractAddr},
+		Permission:   UpdateMapInfo{OldInfo: dapp.Permission, NewInfo: dapp.Permission},
+		Version:      1,
+	}
+	data, err := json.Marshal(updateInfo)
+	assert.Nil(t, err)
+	res := dm.Manage(string(governance.EventUpdate), string(APPROVED), string(governance.GovernanceAvailable), dapp.DappID, data)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, uint64(1), saved[len(saved)-2].CurrentVersion)
+	assert.Equal(t, uint64(2), saved[len(saved)-2].LatestVersion)
+
+	// a direct update creates version 3
+	updateInfo.Version = 0
+	data, err = json.Marshal(updateInfo)
+	assert.Nil(t, err)
+	res = dm.Manage(string(governance.EventUpdate), string(APPROVED), string(governance.GovernanceAvailable), dapp.DappID, data)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, uint64(3), saved[len(saved)-2].CurrentVersion)
+	assert.Equal(t, uint64(3), saved[len(saved)-2].LatestVersion)
+}
+
+func TestDappManager_QueryVersion(t *testing.T) {
+	dm, mockStub, dapps, _ := dappPrepare(t)
+
+	dapp := *dapps[0]
+	dapp.CurrentVersion = 2
+	dapp.LatestVersion = 2
+	mockStub.EXPECT().GetObject(DappKey(dapp.DappID), gomock.Any()).SetArg(1, dapp).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(DappKey(dapps[1].DappID), gomock.Any()).SetArg(1, *dapps[1]).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(DappVersionKey(dapp.DappID, 1), gomock.Any()).SetArg(1, DappVersion{
+		DappID:       dapp.DappID,
+		Version:      1,
+		Url:          "url1",
+		ContractAddr: map[string]struct{}{conAddr2: {}},
+	}).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(DappVersionKey(dapp.DappID, 2), gomock.Any()).SetArg(1, DappVersion{
+		DappID:       dapp.DappID,
+		Version:      2,
+		Url:          dapp.Url,
+		ContractAddr: dapp.ContractAddr,
+	}).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+
+	res := dm.GetDappVersions(dapp.DappID)
+	assert.True(t, res.Ok, string(res.Result))
+	var versions []*DappVersion
+	assert.Nil(t, json.Unmarshal(res.Result, &versions))
+	assert.Equal(t, 2, len(versions))
+	assert.Equal(t, "url1", versions[0].Url)
+
+	res = dm.GetDappVersion(dapp.DappID, 0)
+	assert.True(t, res.Ok, string(res.Result))
+	version := &DappVersion{}
+	assert.Nil(t, json.Unmarshal(res.Result, version))
+	assert.Equal(t, uint64(2), version.Version)
+
+	res = dm.GetDappVersion(dapp.DappID, 3)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// unversioned dapp has an implicit version 1
+	res = dm.GetDappVersions(dapps[1].DappID)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Nil(t, json.Unmarshal(res.Result, &versions))
+	assert.Equal(t, 1, len(versions))
+
+	// pin a version
+	res = dm.GetDapp(dapp.DappID + "@1")
+	assert.True(t, res.Ok, string(res.Result))
+	pinned := &Dapp{}
+	assert.Nil(t, json.Unmarshal(res.Result, pinned))
+	assert.Equal(t, "url1", pinned.Url)
+	_, ok := pinned.ContractAddr[conAddr2]
+	assert.True(t, ok)
+
+	res = dm.GetDapp(dapp.DappID + "@3")
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = dm.IsAvailable(dapp.DappID + "@1")
+	assert.Equal(t, "true", string(res.Result))
+	res = dm.IsAvailable(dapp.DappID + "@3")
+	assert.Equal(t, "false", string(res.Result))
+}
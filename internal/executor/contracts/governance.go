@@ -40,6 +40,8 @@ const (
 	ProposalStrategyMgr ProposalType = repo.ProposalStrategyMgr
 	DappMgr             ProposalType = repo.DappMgr
 	BnsMgr              ProposalType = repo.BnsMgr
+	InterchainMgr       ProposalType = repo.InterchainMgr
+	AssetMgr            ProposalType = repo.AssetMgr
 
 	PROPOSED ProposalStatus = "proposed"
 	APPROVED ProposalStatus = "approve"
@@ -181,6 +183,8 @@ func (g *Governance) SubmitProposal(from, eventTyp, typ, objId, objLastStatus, r
 		constant.GovernanceContractAddr.Address().String(),
 		constant.ProposalStrategyMgrContractAddr.Address().String(),
 		constant.ServiceRegistryContractAddr.Address().String(),
+		constant.InterchainContractAddr.Address().String(),
+		constant.AssetExchangeContractAddr.Address().String(),
 	}
 	addrsData, err := json.Marshal(specificAddrs)
 	if err != nil {
@@ -680,6 +684,18 @@ func (g *Governance) manageObj(proposalTyp ProposalType, eventType, nextEventTyp
 			return fmt.Errorf("invoke Manager error: %s", string(res.Result))
 		}
 		return nil
+	case InterchainMgr:
+		res := g.CrossInvoke(constant.InterchainContractAddr.Address().String(), "Manage", pb.String(string(eventType)), pb.String(string(nextEventType)), pb.String(string(objLastStatus)), pb.String(objId), pb.Bytes(extra))
+		if !res.Ok {
+			return fmt.Errorf("invoke Manager error: %s", string(res.Result))
+		}
+		return nil
+	case AssetMgr:
+		res := g.CrossInvoke(constant.AssetExchangeContractAddr.Address().String(), "Manage", pb.String(string(eventType)), pb.String(string(nextEventType)), pb.String(string(objLastStatus)), pb.String(objId), pb.Bytes(extra))
+		if !res.Ok {
+			return fmt.Errorf("invoke Manager error: %s", string(res.Result))
+		}
+		return nil
 	default: // APPCHAIN_MGR
 		res := g.CrossInvoke(constant.AppchainMgrContractAddr.Address().String(), "Manage", pb.String(string(eventType)), pb.String(string(nextEventType)), pb.String(string(objLastStatus)), pb.String(objId), pb.Bytes(extra))
 		if !res.Ok {
@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/governance"
 	service_mgr "github.com/meshplus/bitxhub-core/service-mgr"
 	"github.com/meshplus/bitxhub-kit/types"
 	"github.com/meshplus/bitxhub-model/constant"
@@ -29,6 +30,14 @@ const (
 type InterchainManager struct {
 	boltvm.Stub
 	ServiceCache *sync.Map
+	// FencingToken is the pier lease token the IBTP is submitted with
+	FencingToken uint64
+}
+
+// ResolvedIBTP is the service ids which the BNS names in the From and To of an IBTP are resolved to
+type ResolvedIBTP struct {
+	From string `json:"from"`
+	To   string `json:"to"`
 }
 
 type BxhValidators struct {
@@ -160,6 +169,27 @@ func (x *InterchainManager) GetInterchain(id string) *boltvm.Response {
 	return boltvm.Success(data)
 }
 
+// Manage applies the fee schedule or route after the proposal is approved
+func (x *InterchainManager) Manage(eventTyp, proposalResult, _, objId string, extra []byte) *boltvm.Response {
+	specificAddrs := []string{constant.GovernanceContractAddr.Address().String()}
+	addrsData, err := json.Marshal(specificAddrs)
+	if err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("marshal specificAddrs error: %v", err))
+	}
+	if err := checkPermission(x.Stub, []string{string(PermissionSpecific)}, "", x.CurrentCaller(), addrsData); err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("no permission to manage interchain: %v", err))
+	}
+
+	if proposalResult != string(APPROVED) || eventTyp != string(governance.EventUpdate) {
+		return boltvm.Success(nil)
+	}
+
+	if isRouteObjID(objId) {
+		return x.manageRoute(extra)
+	}
+	return x.manageFeeSchedule(extra)
+}
+
 func (x *InterchainManager) HandleIBTPData(input []byte) *boltvm.Response {
 	ibtp := &pb.IBTP{}
 	err := ibtp.Unmarshal(input)
@@ -171,6 +201,11 @@ func (x *InterchainManager) HandleIBTPData(input []byte) *boltvm.Response {
 }
 
 func (x *InterchainManager) HandleIBTP(ibtp *pb.IBTP) *boltvm.Response {
+	ibtp, bxhErr := x.resolveIBTPNames(ibtp)
+	if bxhErr != nil {
+		return boltvm.Error(bxhErr.Code, string(bxhErr.Msg))
+	}
+
 	// Pier should retry if checkIBTP failed
 	interchain, isBatch, targetErr, bxhErr := x.checkIBTP(ibtp)
 	if bxhErr != nil {
@@ -181,6 +216,9 @@ func (x *InterchainManager) HandleIBTP(ibtp *pb.IBTP) *boltvm.Response {
 	var err error
 	if pb.IBTP_REQUEST == ibtp.Category() {
 		change, err = x.beginTransaction(ibtp, targetErr != nil)
+		if err == nil {
+			err = x.escrowFee(ibtp)
+		}
 	} else if pb.IBTP_RESPONSE == ibtp.Category() {
 		change, err = x.reportTransaction(ibtp)
 	}
@@ -189,7 +227,10 @@ func (x *InterchainManager) HandleIBTP(ibtp *pb.IBTP) *boltvm.Response {
 	}
 	x.notifySrcDst(ibtp, change, isBatch)
 
-	ret := x.ProcessIBTP(ibtp, interchain, targetErr != nil, isBatch, change.CurStatus, change.ChildIBTPIDs)
+	ret, err := x.ProcessIBTP(ibtp, interchain, targetErr != nil, isBatch, change.CurStatus, change.ChildIBTPIDs)
+	if err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
 
 	if x.EnableAudit() {
 		if err := x.postAuditInterchainEvent(ibtp.From); err != nil {
@@ -202,6 +243,49 @@ func (x *InterchainManager) HandleIBTP(ibtp *pb.IBTP) *boltvm.Response {
 	return boltvm.Success(ret)
 }
 
+// resolveIBTPNames returns a copy of the IBTP whose BNS names in From and To are replaced by the service ids
+// they point to at the time of the transaction, the signed IBTP is left unchanged. The resolution is recorded
+// so that the IBTP is indexed by the same service ids out of the contract
+func (x *InterchainManager) resolveIBTPNames(ibtp *pb.IBTP) (*pb.IBTP, *boltvm.BxhError) {
+	if !IsBnsName(ibtp.From) && !IsBnsName(ibtp.To) {
+		return ibtp, nil
+	}
+
+	from, err := x.resolveServiceName(ibtp.From)
+	if err != nil {
+		return nil, boltvm.BError(boltvm.InterchainInvalidIBTPParseSourceErrorCode, fmt.Sprintf(string(boltvm.InterchainInvalidIBTPParseSourceErrorMsg), err.Error()))
+	}
+	to, err := x.resolveServiceName(ibtp.To)
+	if err != nil {
+		return nil, boltvm.BError(boltvm.InterchainInvalidIBTPParseDestErrorCode, fmt.Sprintf(string(boltvm.InterchainInvalidIBTPParseDestErrorMsg), err.Error()))
+	}
+
+	resolved := *ibtp
+	resolved.From = from
+	resolved.To = to
+	x.SetObject(ResolvedIBTPKey(x.GetTxHash().String()), &ResolvedIBTP{From: from, To: to})
+	return &resolved, nil
+}
+
+// resolveServiceName returns the service id the BNS name is bound to, ids which are not BNS names are returned unchanged
+func (x *InterchainManager) resolveServiceName(id string) (string, error) {
+	if !IsBnsName(id) {
+		return id, nil
+	}
+	res := x.CrossInvoke(constant.ServiceResolverContractAddr.Address().String(), "Resolve", pb.String(id))
+	if !res.Ok {
+		return "", fmt.Errorf("resolve %s: %s", id, string(res.Result))
+	}
+	resolved := &ResolvedDomain{}
+	if err := json.Unmarshal(res.Result, resolved); err != nil {
+		return "", fmt.Errorf("unmarshal resolved domain %s: %w", id, err)
+	}
+	if resolved.ServiceID == "" {
+		return "", fmt.Errorf("domain %s is not bound to any service", id)
+	}
+	return resolved.ServiceID, nil
+}
+
 func (x *InterchainManager) checkIBTP(ibtp *pb.IBTP) (*pb.Interchain, bool, *boltvm.BxhError, *boltvm.BxhError) {
 	var (
 		targetError *boltvm.BxhError
@@ -222,6 +306,18 @@ func (x *InterchainManager) checkIBTP(ibtp *pb.IBTP) (*pb.Interchain, bool, *bol
 	if err != nil {
 		return nil, isBatch, nil, boltvm.BError(boltvm.InterchainInternalErrCode, err.Error())
 	}
+	// the pier submitting the IBTP should hold the lease if its appchain elects the master pier by lease:
+	// requests are submitted by the pier of source chain, receipts and notifications by the pier of destination chain
+	leaseChainService := srcChainService
+	if ibtp.Category() == pb.IBTP_RESPONSE || isNotification {
+		leaseChainService = dstChainService
+	}
+	if leaseChainService.IsLocal {
+		if err := x.checkFencingToken(leaseChainService.ChainId); err != nil {
+			return nil, isBatch, nil, err
+		}
+	}
+
 	if pb.IBTP_REQUEST == ibtp.Category() && !isNotification {
 		// if src chain service is from appchain registered in current bitxhub && not notification for src chain service rollback, check service index
 		if srcChainService.IsLocal {
@@ -244,11 +340,12 @@ func (x *InterchainManager) checkIBTP(ibtp *pb.IBTP) (*pb.Interchain, bool, *bol
 				}
 			}
 		} else {
-			if !dstChainService.IsLocal {
+			// IBTPs to other bitxhubs are forwarded to the next hop
+			if !dstChainService.IsLocal && !x.isForwardable(srcChainService.BxhId, dstChainService.BxhId) {
 				return nil, isBatch, nil, boltvm.BError(boltvm.InterchainInvalidIBTPNotInCurBXHCode, fmt.Sprintf(string(boltvm.InterchainInvalidIBTPNotInCurBXHMsg), ibtp.ID()))
 			}
 
-			if err := x.checkBitXHubAvailability(srcChainService.BxhId); err != nil {
+			if err := x.checkBitXHubAvailability(x.nextHop(srcChainService.BxhId)); err != nil {
 				return nil, isBatch, nil, boltvm.BError(boltvm.InterchainSourceBitXHubNotAvailableCode, fmt.Sprintf(string(boltvm.InterchainSourceBitXHubNotAvailableMsg), srcChainService.BxhId, err))
 			}
 
@@ -269,12 +366,14 @@ func (x *InterchainManager) checkIBTP(ibtp *pb.IBTP) (*pb.Interchain, bool, *bol
 			srcService, _ := x.getServiceByID(srcChainService.getChainServiceId())
 			isBatch = !srcService.Ordered
 		} else {
-			if !dstChainService.IsLocal {
+			// receipts and notifications go back to the source bitxhub through the next hop
+			if !dstChainService.IsLocal && !x.isForwardable(dstChainService.BxhId, srcChainService.BxhId) {
 				return nil, isBatch, nil, boltvm.BError(boltvm.InterchainInvalidIBTPNotInCurBXHCode, fmt.Sprintf(string(boltvm.InterchainInvalidIBTPNotInCurBXHMsg), ibtp.ID()))
 			}
 		}
-		// todo(lrx): adapt receipt batch
-		if err := checkIndex(interchain.ReceiptCounter[dstChainService.getFullServiceId()]+1, ibtp.Index); err != nil {
+		// receipts of unordered services can arrive out of order within the receipt window
+		window := x.getReceiptWindow(srcChainService.getFullServiceId(), dstChainService.getFullServiceId())
+		if err := checkReceiptIndex(interchain.ReceiptCounter[dstChainService.getFullServiceId()], window, ibtp.Index, isBatch); err != nil {
 			return nil, isBatch, nil, err
 		}
 	} else {
@@ -323,7 +422,7 @@ func (x *InterchainManager) checkTargetAvailability(srcChainService, dstChainSer
 			}
 			isBatch = !dstService.Ordered
 		} else {
-			if err := x.checkBitXHubAvailability(dstChainService.BxhId); err != nil {
+			if err := x.checkBitXHubAvailability(x.nextHop(dstChainService.BxhId)); err != nil {
 				return isBatch, boltvm.BError(boltvm.InterchainTargetBitXHubNotAvailableCode, fmt.Sprintf(string(boltvm.InterchainTargetBitXHubNotAvailableMsg), dstChainService.BxhId, err))
 			}
 		}
@@ -349,7 +448,7 @@ func (x *InterchainManager) isFinalGlobalStatus(curStatus pb.TransactionStatus)
 	return pb.IsFinalStatus(curStatus)
 }
 func (x *InterchainManager) ProcessIBTP(ibtp *pb.IBTP, interchain *pb.Interchain,
-	isTargetFail, isBatch bool, curStatus pb.TransactionStatus, childIbtps []string) []byte {
+	isTargetFail, isBatch bool, curStatus pb.TransactionStatus, childIbtps []string) ([]byte, error) {
 	srcChainService, _ := x.parseChainService(ibtp.From)
 	dstChainService, _ := x.parseChainService(ibtp.To)
 
@@ -363,10 +462,10 @@ func (x *InterchainManager) ProcessIBTP(ibtp *pb.IBTP, interchain *pb.Interchain
 		interchain.InterchainCounter[ibtp.To]++
 		x.setInterchain(ibtp.From, interchain)
 		x.AddObject(IndexMapKey(getIBTPID(ibtp.From, ibtp.To, ibtp.Index)), x.GetTxHash())
-		if dstChainService.ChainId == dstChainService.BxhId {
+		if dstChainService.IsLocal && dstChainService.ChainId == dstChainService.BxhId {
 			data, _ := ibtp.Marshal()
 			res := x.CrossInvoke(constant.InterBrokerContractAddr.Address().String(), "InvokeInterchain", pb.Bytes(data))
-			return res.Result
+			return res.Result, nil
 		}
 
 		ic, _ := x.getInterchain(ibtp.To)
@@ -381,17 +480,22 @@ func (x *InterchainManager) ProcessIBTP(ibtp *pb.IBTP, interchain *pb.Interchain
 				x.Logger().WithFields(logrus.Fields{"status": curStatus.String()}).Info("start handleMultiIbtpInterchain")
 				// when global state reaches the final status, add all child ibtp's dst srcInterchain counter
 				if err := x.handleMultiIbtpInterchain(childIbtps, srcChainService, ibtp, curStatus); err != nil {
-					x.Logger().Errorf("handleMultiIbtpInterchain: parse childIbtps err:%s", err)
-					return nil
+					x.Logger().Errorf("handleMultiIbtpInterchain: handle childIbtps err:%s", err)
+					return nil, err
 				}
 			} else {
 				// for single IBTP
-				x.setDestInterchain(ibtp.From, ibtp.To, ibtp.Index, interchain)
+				if err := x.setDestInterchain(ibtp.From, ibtp.To, ibtp.Index, interchain); err != nil {
+					return nil, err
+				}
 				result := true
 				if ibtp.Type == pb.IBTP_RECEIPT_FAILURE || isNotification {
 					result = false
 				}
 				x.recordService(ibtp.From, ibtp.To, result)
+				if err := x.settleFee(ibtp.ID(), curStatus); err != nil {
+					return nil, err
+				}
 			}
 		}
 
@@ -402,14 +506,14 @@ func (x *InterchainManager) ProcessIBTP(ibtp *pb.IBTP, interchain *pb.Interchain
 	if isBatch {
 		res := &boltvm.Response{}
 		res.Result = []byte("batch_ibtp")
-		return res.Result
+		return res.Result, nil
 	}
 	if isTargetFail {
 		res := &boltvm.Response{}
 		res.Result = []byte("begin_failure")
-		return res.Result
+		return res.Result, nil
 	}
-	return nil
+	return nil, nil
 }
 
 func (x *InterchainManager) handleMultiIbtpInterchain(childIbtps []string,
@@ -420,7 +524,9 @@ func (x *InterchainManager) handleMultiIbtpInterchain(childIbtps []string,
 			return err
 		}
 		fromInterchain, _ := x.getInterchain(from)
-		x.setDestInterchain(from, to, index, fromInterchain)
+		if err := x.setDestInterchain(from, to, index, fromInterchain); err != nil {
+			return err
+		}
 		if srcChainService.ChainId == srcChainService.BxhId {
 			data, _ := ibtp.Marshal()
 			x.CrossInvoke(constant.InterBrokerContractAddr.Address().String(), "InvokeReceipt", pb.Bytes(data))
@@ -430,16 +536,30 @@ func (x *InterchainManager) handleMultiIbtpInterchain(childIbtps []string,
 			result = false
 		}
 		x.recordService(from, to, result)
+		if err := x.settleFee(ibtpId, globalState); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func (x *InterchainManager) setDestInterchain(from, to string, index uint64, interchain *pb.Interchain) {
-	interchain.ReceiptCounter[to] = index
+func (x *InterchainManager) setDestInterchain(from, to string, index uint64, interchain *pb.Interchain) error {
+	window := x.getReceiptWindow(from, to)
+	wasEmpty := window.isEmpty()
+	counter, err := window.record(interchain.ReceiptCounter[to], index)
+	if err != nil {
+		return fmt.Errorf("record receipt %s: %w", getIBTPID(from, to, index), err)
+	}
+	if !wasEmpty || !window.isEmpty() {
+		x.SetObject(ReceiptWindowKey(from, to), *window)
+	}
+
+	interchain.ReceiptCounter[to] = counter
 	x.setInterchain(from, interchain)
 	ic, _ := x.getInterchain(to)
-	ic.SourceReceiptCounter[from] = index
+	ic.SourceReceiptCounter[from] = counter
 	x.setInterchain(to, ic)
+	return nil
 }
 
 // update service record
@@ -495,7 +615,9 @@ func (x *InterchainManager) beginTransaction(ibtp *pb.IBTP, isFailed bool) (*pb.
 		if err != nil {
 			return nil, err
 		}
-		if currentBxhId == bxhID0 {
+		// only the destination bitxhub rolls back the transaction after timeout,
+		// the source bitxhub and relay chains on the way wait for its notification
+		if currentBxhId != bxhID1 {
 			timeoutHeight = 0
 		}
 		res = x.CrossInvoke(constant.TransactionMgrContractAddr.Address().String(), "BeginInterBitXHub", pb.String(txId), pb.Uint64(timeoutHeight), pb.Bytes(ibtp.Extra), pb.Bool(isFailed))
@@ -593,6 +715,10 @@ func IndexReceiptMapKey(id string) string {
 	return fmt.Sprintf("index-receipt-tx-%s", id)
 }
 
+func ResolvedIBTPKey(txHash string) string {
+	return fmt.Sprintf("resolved-ibtp-%s", txHash)
+}
+
 func (x *InterchainManager) parseChainService(id string) (*ChainService, error) {
 	splits := strings.Split(id, ":")
 
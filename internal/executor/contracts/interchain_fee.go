@@ -0,0 +1,348 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/governance"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/eth-kit/ledger"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	FEE_SCHEDULE_KEY   = "fee-schedule"
+	FEE_ESCROW_PREFIX  = "fee-escrow"
+	FEE_DEPOSIT_PREFIX = "fee-deposit"
+
+	FeeEscrowed = "escrowed"
+	FeePaid     = "paid"
+	FeeRefunded = "refunded"
+)
+
+// FeeSchedule is the fee escrowed for every IBTP request to the destination chain
+type FeeSchedule struct {
+	ChainID string `json:"chain_id"`
+	Fee     uint64 `json:"fee"`
+}
+
+// FeeEscrow holds the fee of an IBTP request until the transaction reaches a final status:
+// the relayer delivering the successful receipt gets the fee, otherwise the fee is returned to
+// the deposit of source service and the fees attached by other accounts are refunded to them
+type FeeEscrow struct {
+	IBTPID     string            `json:"ibtp_id"`
+	Service    string            `json:"service"`     // chain service id of the source service
+	ServiceFee uint64            `json:"service_fee"` // fee drawn from the deposit of source service
+	Payers     map[string]uint64 `json:"payers"`      // accounts attaching fees
+	Amount     uint64            `json:"amount"`
+	Payee      string            `json:"payee"`
+	Status     string            `json:"status"`
+}
+
+// SetFeeSchedule submits a proposal to set the fee of IBTP requests to the destination chain
+func (x *InterchainManager) SetFeeSchedule(chainID string, fee uint64, reason string) *boltvm.Response {
+	if err := checkPermission(x.Stub, []string{string(PermissionAdmin)}, "", x.Caller(), nil); err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("only governance admin can set fee schedule: %v", err))
+	}
+	if chainID == "" {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, "destination chain id should not be empty")
+	}
+
+	extra, err := json.Marshal(FeeSchedule{ChainID: chainID, Fee: fee})
+	if err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("marshal fee schedule error: %v", err))
+	}
+
+	res := x.CrossInvoke(constant.GovernanceContractAddr.Address().String(), "SubmitProposal",
+		pb.String(x.Caller()),
+		pb.String(string(governance.EventUpdate)),
+		pb.String(string(InterchainMgr)),
+		pb.String(FeeScheduleObjID(chainID)),
+		pb.String(""), // no last status
+		pb.String(reason),
+		pb.Bytes(extra),
+	)
+	if !res.Ok {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("submit proposal error: %s", string(res.Result)))
+	}
+
+	x.CrossInvoke(constant.GovernanceContractAddr.Address().String(), "ZeroPermission", pb.String(string(res.Result)))
+
+	return getGovernanceRet(string(res.Result), nil)
+}
+
+func (x *InterchainManager) manageFeeSchedule(extra []byte) *boltvm.Response {
+	schedule := &FeeSchedule{}
+	if err := json.Unmarshal(extra, schedule); err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("unmarshal fee schedule error: %v", err))
+	}
+	schedules := x.getFeeSchedules()
+	if schedule.Fee == 0 {
+		delete(schedules, schedule.ChainID)
+	} else {
+		schedules[schedule.ChainID] = schedule.Fee
+	}
+	x.SetObject(FEE_SCHEDULE_KEY, schedules)
+
+	return boltvm.Success(nil)
+}
+
+// GetFeeSchedules returns the fee of IBTP requests to every destination chain
+func (x *InterchainManager) GetFeeSchedules() *boltvm.Response {
+	data, err := json.Marshal(x.getFeeSchedules())
+	if err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+// AttachFee adds more fee to an escrowed IBTP request to speed up the relay
+func (x *InterchainManager) AttachFee(ibtpID string, amount uint64) *boltvm.Response {
+	escrow := &FeeEscrow{}
+	if ok := x.GetObject(FeeEscrowKey(ibtpID), escrow); !ok || escrow.Status != FeeEscrowed {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("ibtp %s has no escrowed fee", ibtpID))
+	}
+	if amount == 0 {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, "attached fee should be greater than 0")
+	}
+
+	caller := x.Caller()
+	if err := x.transferFee(caller, constant.InterchainContractAddr.Address().String(), amount); err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+	if escrow.Payers == nil {
+		escrow.Payers = make(map[string]uint64)
+	}
+	escrow.Payers[caller] += amount
+	escrow.Amount += amount
+	x.SetObject(FeeEscrowKey(ibtpID), *escrow)
+
+	return boltvm.Success(nil)
+}
+
+// GetFeeEscrow returns the fee escrow of the IBTP request
+func (x *InterchainManager) GetFeeEscrow(ibtpID string) *boltvm.Response {
+	escrow := &FeeEscrow{}
+	if ok := x.GetObject(FeeEscrowKey(ibtpID), escrow); !ok {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("ibtp %s has no fee escrow", ibtpID))
+	}
+
+	data, err := json.Marshal(escrow)
+	if err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+// DepositFee deposits the fee of IBTP requests sent by the service, only the admins of its appchain can deposit
+func (x *InterchainManager) DepositFee(chainServiceID string, amount uint64) *boltvm.Response {
+	if err := x.checkServiceOwner(chainServiceID); err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+	if amount == 0 {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, "deposited fee should be greater than 0")
+	}
+
+	if err := x.transferFee(x.Caller(), constant.InterchainContractAddr.Address().String(), amount); err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+	x.SetObject(FeeDepositKey(chainServiceID), x.getFeeDeposit(chainServiceID)+amount)
+
+	return boltvm.Success(nil)
+}
+
+// WithdrawFee withdraws the fee deposited for the service to the admin of its appchain
+func (x *InterchainManager) WithdrawFee(chainServiceID string, amount uint64) *boltvm.Response {
+	if err := x.checkServiceOwner(chainServiceID); err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+	deposit := x.getFeeDeposit(chainServiceID)
+	if amount == 0 || amount > deposit {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("withdrawn fee should be in [1, %d]", deposit))
+	}
+
+	if err := x.transferFee(constant.InterchainContractAddr.Address().String(), x.Caller(), amount); err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+	x.SetObject(FeeDepositKey(chainServiceID), deposit-amount)
+
+	return boltvm.Success(nil)
+}
+
+// GetFeeDeposit returns the fee deposited for the service
+func (x *InterchainManager) GetFeeDeposit(chainServiceID string) *boltvm.Response {
+	return boltvm.Success([]byte(strconv.FormatUint(x.getFeeDeposit(chainServiceID), 10)))
+}
+
+// RefundFee refunds the escrowed fee of the IBTP request which is rolled back or failed,
+// so that the fee of request timed out is refunded even if no pier relays the rollback
+func (x *InterchainManager) RefundFee(ibtpID string) *boltvm.Response {
+	escrow := &FeeEscrow{}
+	if ok := x.GetObject(FeeEscrowKey(ibtpID), escrow); !ok || escrow.Status != FeeEscrowed {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("ibtp %s has no escrowed fee", ibtpID))
+	}
+
+	res := x.CrossInvoke(constant.TransactionMgrContractAddr.Address().String(), "GetStatus", pb.String(ibtpID))
+	if !res.Ok {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("get status of ibtp %s error: %s", ibtpID, string(res.Result)))
+	}
+	val, err := strconv.Atoi(string(res.Result))
+	if err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("parse status of ibtp %s error: %v", ibtpID, err))
+	}
+	status := pb.TransactionStatus(val)
+	switch status {
+	case pb.TransactionStatus_BEGIN_ROLLBACK, pb.TransactionStatus_ROLLBACK, pb.TransactionStatus_FAILURE:
+	default:
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("fee of ibtp %s in status %s can not be refunded", ibtpID, status.String()))
+	}
+
+	if err := x.settleFee(ibtpID, status); err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+
+	return boltvm.Success(nil)
+}
+
+// escrowFee draws the fee of destination chain from the deposit of the source service
+func (x *InterchainManager) escrowFee(ibtp *pb.IBTP) error {
+	srcChainService, err := x.parseChainService(ibtp.From)
+	if err != nil {
+		return err
+	}
+	dstChainService, err := x.parseChainService(ibtp.To)
+	if err != nil {
+		return err
+	}
+	// the fee is escrowed on the bitxhub of source chain only,
+	// and the requests to bitxhub services have no relayed receipt
+	if !srcChainService.IsLocal || dstChainService.ChainId == dstChainService.BxhId {
+		return nil
+	}
+
+	fee := x.getFeeSchedules()[dstChainService.ChainId]
+	if fee == 0 {
+		return nil
+	}
+	// notifications of inter-bitxhub rollback reuse the id of the escrowed request
+	if ok := x.GetObject(FeeEscrowKey(ibtp.ID()), &FeeEscrow{}); ok {
+		return nil
+	}
+
+	service := srcChainService.getChainServiceId()
+	deposit := x.getFeeDeposit(service)
+	if deposit < fee {
+		return fmt.Errorf("insufficient fee deposit of service %s: have %d want %d", service, deposit, fee)
+	}
+	x.SetObject(FeeDepositKey(service), deposit-fee)
+	x.SetObject(FeeEscrowKey(ibtp.ID()), FeeEscrow{
+		IBTPID:     ibtp.ID(),
+		Service:    service,
+		ServiceFee: fee,
+		Payers:     make(map[string]uint64),
+		Amount:     fee,
+		Status:     FeeEscrowed,
+	})
+
+	return nil
+}
+
+// settleFee pays the escrowed fee to the relayer if the transaction succeeded, otherwise returns
+// the fee to the deposit of source service and refunds the attached fees
+func (x *InterchainManager) settleFee(ibtpID string, status pb.TransactionStatus) error {
+	escrow := &FeeEscrow{}
+	if ok := x.GetObject(FeeEscrowKey(ibtpID), escrow); !ok || escrow.Status != FeeEscrowed || escrow.Amount == 0 {
+		return nil
+	}
+
+	contractAddr := constant.InterchainContractAddr.Address().String()
+	if status == pb.TransactionStatus_SUCCESS {
+		escrow.Payee = x.Caller()
+		escrow.Status = FeePaid
+		if err := x.transferFee(contractAddr, escrow.Payee, escrow.Amount); err != nil {
+			return fmt.Errorf("pay interchain fee of ibtp %s: %w", ibtpID, err)
+		}
+	} else {
+		escrow.Status = FeeRefunded
+		if escrow.ServiceFee != 0 {
+			x.SetObject(FeeDepositKey(escrow.Service), x.getFeeDeposit(escrow.Service)+escrow.ServiceFee)
+		}
+		payers := make([]string, 0, len(escrow.Payers))
+		for payer := range escrow.Payers {
+			payers = append(payers, payer)
+		}
+		sort.Strings(payers)
+		for _, payer := range payers {
+			if err := x.transferFee(contractAddr, payer, escrow.Payers[payer]); err != nil {
+				return fmt.Errorf("refund interchain fee of ibtp %s: %w", ibtpID, err)
+			}
+		}
+	}
+	x.SetObject(FeeEscrowKey(ibtpID), *escrow)
+
+	x.Logger().WithFields(logrus.Fields{
+		"id":     ibtpID,
+		"amount": escrow.Amount,
+		"status": escrow.Status,
+	}).Info("Interchain fee is settled")
+
+	return nil
+}
+
+// checkServiceOwner checks the caller is the admin of the appchain the service belongs to
+func (x *InterchainManager) checkServiceOwner(chainServiceID string) error {
+	splits := strings.Split(chainServiceID, ":")
+	if len(splits) != 2 || splits[0] == "" || splits[1] == "" {
+		return fmt.Errorf("invalid chain service id %s", chainServiceID)
+	}
+
+	res := x.CrossInvoke(constant.AppchainMgrContractAddr.Address().String(), "GetAdminByChainId", pb.String(splits[0]))
+	if !res.Ok {
+		return fmt.Errorf("get admins of chain %s error: %s", splits[0], string(res.Result))
+	}
+	if err := checkPermission(x.Stub, []string{string(PermissionSpecific)}, "", x.Caller(), res.Result); err != nil {
+		return fmt.Errorf("only admins of chain %s can manage fee deposit of service %s: %w", splits[0], chainServiceID, err)
+	}
+	return nil
+}
+
+func (x *InterchainManager) transferFee(from, to string, amount uint64) error {
+	value := new(big.Int).SetUint64(amount)
+	fromAccount := x.GetAccount(from).(ledger.IAccount)
+	if fromAccount.GetBalance().Cmp(value) < 0 {
+		return fmt.Errorf("insufficient balance of %s for interchain fee: have %v want %v", from, fromAccount.GetBalance(), value)
+	}
+	fromAccount.SubBalance(value)
+	x.GetAccount(to).(ledger.IAccount).AddBalance(value)
+	return nil
+}
+
+func (x *InterchainManager) getFeeDeposit(chainServiceID string) uint64 {
+	var deposit uint64
+	_ = x.GetObject(FeeDepositKey(chainServiceID), &deposit)
+	return deposit
+}
+
+func (x *InterchainManager) getFeeSchedules() map[string]uint64 {
+	schedules := make(map[string]uint64)
+	_ = x.GetObject(FEE_SCHEDULE_KEY, &schedules)
+	return schedules
+}
+
+func FeeScheduleObjID(chainID string) string {
+	return fmt.Sprintf("%s-%s", FEE_SCHEDULE_KEY, chainID)
+}
+
+func FeeEscrowKey(ibtpID string) string {
+	return fmt.Sprintf("%s-%s", FEE_ESCROW_PREFIX, ibtpID)
+}
+
+func FeeDepositKey(chainServiceID string) string {
+	return fmt.Sprintf("%s-%s", FEE_DEPOSIT_PREFIX, chainServiceID)
+}
@@ -0,0 +1,269 @@
+package contracts
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/boltvm/mock_stub"
+	"github.com/meshplus/bitxhub-core/governance"
+	"github.com/meshplus/bitxhub-kit/log"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/eth-kit/ledger"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	feePayer   = "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b991"
+	feeRelayer = "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b992"
+)
+
+func feePrepare(t *testing.T) (*InterchainManager, *mock_stub.MockStub, map[string]ledger.IAccount) {
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+
+	accounts := map[string]ledger.IAccount{
+		feePayer:   mockAccount(t),
+		feeRelayer: mockAccount(t),
+		constant.InterchainContractAddr.Address().String(): mockAccount(t),
+	}
+	mockStub.EXPECT().GetAccount(gomock.Any()).DoAndReturn(func(addr string) interface{} {
+		return accounts[addr]
+	}).AnyTimes()
+	mockStub.EXPECT().Get(BitXHubID).Return(true, []byte("bxh")).AnyTimes()
+	mockStub.EXPECT().Logger().Return(log.NewWithModule("contracts")).AnyTimes()
+
+	return &InterchainManager{Stub: mockStub}, mockStub, accounts
+}
+
+func TestInterchainManager_SetFeeSchedule(t *testing.T) {
+	im, mockStub, _ := feePrepare(t)
+
+	mockStub.EXPECT().Caller().Return(feePayer).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte(FALSE))).Times(1)
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte(TRUE))).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.GovernanceContractAddr.Address().String(), "SubmitProposal",
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte("proposal-0"))).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.GovernanceContractAddr.Address().String(), "ZeroPermission", gomock.Any()).Return(boltvm.Success(nil)).AnyTimes()
+
+	// not governance admin
+	res := im.SetFeeSchedule(dstChainID, 10, reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = im.SetFeeSchedule("", 10, reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = im.SetFeeSchedule(dstChainID, 10, reason)
+	assert.True(t, res.Ok, string(res.Result))
+	ret := &governance.GovernanceResult{}
+	assert.Nil(t, json.Unmarshal(res.Result, ret))
+	assert.Equal(t, "proposal-0", ret.ProposalID)
+}
+
+func TestInterchainManager_ManageFeeSchedule(t *testing.T) {
+	im, mockStub, _ := feePrepare(t)
+
+	mockStub.EXPECT().CurrentCaller().Return(feePayer).Times(1)
+	mockStub.EXPECT().CurrentCaller().Return(constant.GovernanceContractAddr.Address().String()).AnyTimes()
+	mockStub.EXPECT().GetObject(FEE_SCHEDULE_KEY, gomock.Any()).DoAndReturn(func(key string, ret interface{}) bool {
+		*ret.(*map[string]uint64) = map[string]uint64{srcChainID: 5}
+		return true
+	}).AnyTimes()
+	var schedules map[string]uint64
+	mockStub.EXPECT().SetObject(FEE_SCHEDULE_KEY, gomock.Any()).DoAndReturn(func(key string, value interface{}) {
+		schedules = value.(map[string]uint64)
+	}).AnyTimes()
+
+	extra, err := json.Marshal(FeeSchedule{ChainID: dstChainID, Fee: 10})
+	assert.Nil(t, err)
+
+	// not called by governance contract
+	res := im.Manage(string(governance.EventUpdate), string(APPROVED), "", FeeScheduleObjID(dstChainID), extra)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = im.Manage(string(governance.EventUpdate), string(REJECTED), "", FeeScheduleObjID(dstChainID), extra)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Nil(t, schedules)
+
+	res = im.Manage(string(governance.EventUpdate), string(APPROVED), "", FeeScheduleObjID(dstChainID), extra)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, map[string]uint64{srcChainID: 5, dstChainID: 10}, schedules)
+
+	// zero fee removes the schedule
+	extra, err = json.Marshal(FeeSchedule{ChainID: srcChainID, Fee: 0})
+	assert.Nil(t, err)
+	res = im.Manage(string(governance.EventUpdate), string(APPROVED), "", FeeScheduleObjID(srcChainID), extra)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, map[string]uint64{}, schedules)
+}
+
+// feeStore keeps the objects set by the interchain manager in memory
+func feeStore(t *testing.T, mockStub *mock_stub.MockStub) {
+	store := make(map[string][]byte)
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).DoAndReturn(func(key string, ret interface{}) bool {
+		data, ok := store[key]
+		if !ok {
+			return false
+		}
+		assert.Nil(t, json.Unmarshal(data, ret))
+		return true
+	}).AnyTimes()
+	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).DoAndReturn(func(key string, value interface{}) {
+		data, err := json.Marshal(value)
+		assert.Nil(t, err)
+		store[key] = data
+	}).AnyTimes()
+}
+
+func getFeeEscrow(t *testing.T, im *InterchainManager, ibtpID string) *FeeEscrow {
+	res := im.GetFeeEscrow(ibtpID)
+	assert.True(t, res.Ok, string(res.Result))
+	escrow := &FeeEscrow{}
+	assert.Nil(t, json.Unmarshal(res.Result, escrow))
+	return escrow
+}
+
+func TestInterchainManager_FeeDeposit(t *testing.T) {
+	im, mockStub, accounts := feePrepare(t)
+	feeStore(t, mockStub)
+	srcChainService, _ := mockChainService()
+	service := srcChainService.getChainServiceId()
+	contractAddr := constant.InterchainContractAddr.Address().String()
+	accounts[feePayer].AddBalance(big.NewInt(100))
+
+	admins, err := json.Marshal([]string{feePayer})
+	assert.Nil(t, err)
+	mockStub.EXPECT().CrossInvoke(constant.AppchainMgrContractAddr.Address().String(), "GetAdminByChainId", pb.String(srcChainID)).Return(boltvm.Success(admins)).AnyTimes()
+
+	// only admins of the appchain manage the deposit
+	mockStub.EXPECT().Caller().Return(feeRelayer).Times(2)
+	res := im.DepositFee(service, 10)
+	assert.False(t, res.Ok, string(res.Result))
+	res = im.WithdrawFee(service, 10)
+	assert.False(t, res.Ok, string(res.Result))
+
+	mockStub.EXPECT().Caller().Return(feePayer).AnyTimes()
+	res = im.DepositFee(srcChainID, 10)
+	assert.False(t, res.Ok, string(res.Result))
+	res = im.DepositFee(service, 200)
+	assert.False(t, res.Ok, string(res.Result))
+	res = im.DepositFee(service, 60)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, uint64(40), accounts[feePayer].GetBalance().Uint64())
+	assert.Equal(t, uint64(60), accounts[contractAddr].GetBalance().Uint64())
+
+	res = im.WithdrawFee(service, 70)
+	assert.False(t, res.Ok, string(res.Result))
+	res = im.WithdrawFee(service, 20)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, uint64(60), accounts[feePayer].GetBalance().Uint64())
+
+	res = im.GetFeeDeposit(service)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, "40", string(res.Result))
+}
+
+func TestInterchainManager_EscrowAndSettleFee(t *testing.T) {
+	im, mockStub, accounts := feePrepare(t)
+	feeStore(t, mockStub)
+	srcChainService, dstChainService := mockChainService()
+	service := srcChainService.getChainServiceId()
+	contractAddr := constant.InterchainContractAddr.Address().String()
+	accounts[feePayer].AddBalance(big.NewInt(100))
+	accounts[contractAddr].AddBalance(big.NewInt(40))
+	im.SetObject(FEE_SCHEDULE_KEY, map[string]uint64{dstChainID: 30})
+
+	// the pier submitting the request pays nothing, the fee is drawn from the deposit of source service
+	ibtp := &pb.IBTP{From: srcChainService.getFullServiceId(), To: dstChainService.getFullServiceId(), Index: 1}
+	im.SetObject(FeeDepositKey(service), uint64(20))
+	assert.NotNil(t, im.escrowFee(ibtp))
+	im.SetObject(FeeDepositKey(service), uint64(40))
+	assert.Nil(t, im.escrowFee(ibtp))
+	escrow := getFeeEscrow(t, im, ibtp.ID())
+	assert.Equal(t, FeeEscrowed, escrow.Status)
+	assert.Equal(t, service, escrow.Service)
+	assert.Equal(t, uint64(30), escrow.ServiceFee)
+	assert.Equal(t, "10", string(im.GetFeeDeposit(service).Result))
+
+	// the source service attaches more fee
+	mockStub.EXPECT().Caller().Return(feePayer).Times(2)
+	res := im.AttachFee(ibtp.ID(), 200)
+	assert.False(t, res.Ok, string(res.Result))
+	res = im.AttachFee(ibtp.ID(), 20)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, uint64(50), getFeeEscrow(t, im, ibtp.ID()).Amount)
+	assert.Equal(t, uint64(80), accounts[feePayer].GetBalance().Uint64())
+
+	// escrowed request is not charged again
+	assert.Nil(t, im.escrowFee(ibtp))
+	assert.Equal(t, "10", string(im.GetFeeDeposit(service).Result))
+
+	// the relayer delivering successful receipt gets the fee
+	mockStub.EXPECT().Caller().Return(feeRelayer).Times(1)
+	assert.Nil(t, im.settleFee(ibtp.ID(), pb.TransactionStatus_SUCCESS))
+	escrow = getFeeEscrow(t, im, ibtp.ID())
+	assert.Equal(t, FeePaid, escrow.Status)
+	assert.Equal(t, feeRelayer, escrow.Payee)
+	assert.Equal(t, uint64(50), accounts[feeRelayer].GetBalance().Uint64())
+	assert.Equal(t, uint64(10), accounts[contractAddr].GetBalance().Uint64())
+
+	// settled fee is not paid twice
+	assert.Nil(t, im.settleFee(ibtp.ID(), pb.TransactionStatus_SUCCESS))
+	assert.Equal(t, uint64(50), accounts[feeRelayer].GetBalance().Uint64())
+
+	// failed payment is surfaced
+	im.SetObject(FeeEscrowKey("id"), FeeEscrow{IBTPID: "id", Amount: 100, Status: FeeEscrowed})
+	mockStub.EXPECT().Caller().Return(feeRelayer).Times(1)
+	assert.NotNil(t, im.settleFee("id", pb.TransactionStatus_SUCCESS))
+}
+
+func TestInterchainManager_RefundFee(t *testing.T) {
+	im, mockStub, accounts := feePrepare(t)
+	feeStore(t, mockStub)
+	srcChainService, _ := mockChainService()
+	service := srcChainService.getChainServiceId()
+	contractAddr := constant.InterchainContractAddr.Address().String()
+	accounts[contractAddr].AddBalance(big.NewInt(70))
+	im.SetObject(FeeDepositKey(service), uint64(5))
+
+	escrow := FeeEscrow{
+		IBTPID:     "id",
+		Service:    service,
+		ServiceFee: 20,
+		Payers:     map[string]uint64{feePayer: 30, feeRelayer: 20},
+		Amount:     70,
+		Status:     FeeEscrowed,
+	}
+	im.SetObject(FeeEscrowKey("id"), escrow)
+
+	// the rolled back request returns the fee to the deposit and refunds the attached fees
+	assert.Nil(t, im.settleFee("id", pb.TransactionStatus_ROLLBACK))
+	assert.Equal(t, FeeRefunded, getFeeEscrow(t, im, "id").Status)
+	assert.Equal(t, "25", string(im.GetFeeDeposit(service).Result))
+	assert.Equal(t, uint64(30), accounts[feePayer].GetBalance().Uint64())
+	assert.Equal(t, uint64(20), accounts[feeRelayer].GetBalance().Uint64())
+	// the deposit stays in the contract
+	assert.Equal(t, uint64(20), accounts[contractAddr].GetBalance().Uint64())
+
+	// the fee of timed out request is refunded without relayed rollback
+	im.SetObject(FeeEscrowKey("id1"), FeeEscrow{IBTPID: "id1", Service: service, ServiceFee: 10, Amount: 10, Status: FeeEscrowed})
+	mockStub.EXPECT().CrossInvoke(constant.TransactionMgrContractAddr.Address().String(), "GetStatus", pb.String("id1")).
+		Return(boltvm.Success([]byte(strconv.Itoa(int(pb.TransactionStatus_BEGIN))))).Times(1)
+	res := im.RefundFee("id1")
+	assert.False(t, res.Ok, string(res.Result))
+
+	mockStub.EXPECT().CrossInvoke(constant.TransactionMgrContractAddr.Address().String(), "GetStatus", pb.String("id1")).
+		Return(boltvm.Success([]byte(strconv.Itoa(int(pb.TransactionStatus_BEGIN_ROLLBACK))))).Times(1)
+	res = im.RefundFee("id1")
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, FeeRefunded, getFeeEscrow(t, im, "id1").Status)
+	assert.Equal(t, "35", string(im.GetFeeDeposit(service).Result))
+
+	// refunded fee can not be refunded again
+	res = im.RefundFee("id1")
+	assert.False(t, res.Ok, string(res.Result))
+}
@@ -0,0 +1,185 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+)
+
+const (
+	PIER_LEASE_PREFIX = "pier-lease"
+
+	// MaxPierLeaseTTL is the longest term of a pier lease in blocks
+	MaxPierLeaseTTL = 1000
+)
+
+// PierLease makes the holder the master pier of the appchain until the expire height. Every new lease of
+// the appchain gets a greater fencing token, IBTPs submitted with a stale token are rejected, so a pier
+// which lost its lease can't relay IBTPs even if it still believes it is the master.
+type PierLease struct {
+	ChainID      string `json:"chain_id"`
+	Holder       string `json:"holder"`
+	Token        uint64 `json:"token"`
+	ExpireHeight uint64 `json:"expire_height"`
+}
+
+// IBTPTxPayload is the payload of the transactions carrying IBTPs. The payload is covered by the
+// signature of the pier, while the extra of the transaction carries the unsigned proof of IBTP.
+type IBTPTxPayload struct {
+	FencingToken uint64 `json:"fencing_token"`
+}
+
+// ParseFencingToken returns the fencing token in the payload of IBTP transaction, 0 if there is none
+func ParseFencingToken(payload []byte) uint64 {
+	if len(payload) == 0 {
+		return 0
+	}
+	txPayload := &IBTPTxPayload{}
+	if err := json.Unmarshal(payload, txPayload); err != nil {
+		return 0
+	}
+	return txPayload.FencingToken
+}
+
+func (l *PierLease) isAlive(height uint64) bool {
+	return height < l.ExpireHeight
+}
+
+// AcquirePierLease makes the holder the master pier of the appchain for ttl blocks. The lease is renewed if
+// the holder has it already, and a new fencing token is handed out if the last lease has expired.
+func (x *InterchainManager) AcquirePierLease(chainID, holder string, ttl uint64) *boltvm.Response {
+	if err := x.checkLeaseArgs(chainID, holder, ttl); err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+
+	height := x.GetCurrentHeight()
+	lease, ok := x.getPierLease(chainID)
+	if !ok {
+		lease = &PierLease{ChainID: chainID}
+	}
+	if lease.isAlive(height) && lease.Holder != holder {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("pier lease of chain %s is held by %s until height %d", chainID, lease.Holder, lease.ExpireHeight))
+	}
+	if !lease.isAlive(height) {
+		lease.Holder = holder
+		lease.Token++
+	}
+	lease.ExpireHeight = height + ttl
+	x.SetObject(PierLeaseKey(chainID), *lease)
+
+	return x.pierLeaseRet(lease)
+}
+
+// RenewPierLease extends the lease of the holder for ttl blocks before it expires
+func (x *InterchainManager) RenewPierLease(chainID, holder string, token, ttl uint64) *boltvm.Response {
+	if err := x.checkLeaseArgs(chainID, holder, ttl); err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+
+	lease, err := x.checkLeaseHolder(chainID, holder, token)
+	if err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+	lease.ExpireHeight = x.GetCurrentHeight() + ttl
+	x.SetObject(PierLeaseKey(chainID), *lease)
+
+	return x.pierLeaseRet(lease)
+}
+
+// ReleasePierLease gives up the lease of the holder, so that another pier can be the master at once
+func (x *InterchainManager) ReleasePierLease(chainID, holder string, token uint64) *boltvm.Response {
+	if err := x.checkLeaseArgs(chainID, holder, 1); err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+
+	lease, err := x.checkLeaseHolder(chainID, holder, token)
+	if err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+	lease.ExpireHeight = x.GetCurrentHeight()
+	x.SetObject(PierLeaseKey(chainID), *lease)
+
+	return boltvm.Success(nil)
+}
+
+// GetPierLease returns the last pier lease of the appchain
+func (x *InterchainManager) GetPierLease(chainID string) *boltvm.Response {
+	lease, ok := x.getPierLease(chainID)
+	if !ok {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("chain %s has no pier lease", chainID))
+	}
+	return x.pierLeaseRet(lease)
+}
+
+func (x *InterchainManager) getPierLease(chainID string) (*PierLease, bool) {
+	lease := &PierLease{}
+	// no lease has been handed out if the token is 0
+	if ok := x.GetObject(PierLeaseKey(chainID), lease); !ok || lease.Token == 0 {
+		return nil, false
+	}
+	return lease, true
+}
+
+func (x *InterchainManager) pierLeaseRet(lease *PierLease) *boltvm.Response {
+	data, err := json.Marshal(lease)
+	if err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+// checkLeaseArgs checks the lease request, which is only allowed to be sent by the admins of appchain
+func (x *InterchainManager) checkLeaseArgs(chainID, holder string, ttl uint64) error {
+	if chainID == "" || holder == "" {
+		return fmt.Errorf("chain id and holder of pier lease should not be empty")
+	}
+	if ttl == 0 || ttl > MaxPierLeaseTTL {
+		return fmt.Errorf("ttl of pier lease should be in [1, %d]", MaxPierLeaseTTL)
+	}
+
+	res := x.CrossInvoke(constant.AppchainMgrContractAddr.Address().String(), "GetAdminByChainId", pb.String(chainID))
+	if !res.Ok {
+		return fmt.Errorf("get admins of chain %s error: %s", chainID, string(res.Result))
+	}
+	if err := checkPermission(x.Stub, []string{string(PermissionSpecific)}, "", x.Caller(), res.Result); err != nil {
+		return fmt.Errorf("only admins of chain %s can manage pier lease: %w", chainID, err)
+	}
+	return nil
+}
+
+func (x *InterchainManager) checkLeaseHolder(chainID, holder string, token uint64) (*PierLease, error) {
+	lease, ok := x.getPierLease(chainID)
+	if !ok {
+		return nil, fmt.Errorf("chain %s has no pier lease", chainID)
+	}
+	if lease.Holder != holder || lease.Token != token {
+		return nil, fmt.Errorf("pier lease of chain %s is held by %s with token %d", chainID, lease.Holder, lease.Token)
+	}
+	if !lease.isAlive(x.GetCurrentHeight()) {
+		return nil, fmt.Errorf("pier lease of chain %s expired at height %d", chainID, lease.ExpireHeight)
+	}
+	return lease, nil
+}
+
+// checkFencingToken rejects the IBTPs of appchain electing its master pier by lease, unless they are
+// submitted with the token of the alive lease
+func (x *InterchainManager) checkFencingToken(chainID string) *boltvm.BxhError {
+	lease, ok := x.getPierLease(chainID)
+	if !ok {
+		return nil
+	}
+	if x.FencingToken != lease.Token {
+		return boltvm.BError(boltvm.InterchainInternalErrCode, fmt.Sprintf("stale fencing token %d of chain %s, the current one is %d", x.FencingToken, chainID, lease.Token))
+	}
+	if !lease.isAlive(x.GetCurrentHeight()) {
+		return boltvm.BError(boltvm.InterchainInternalErrCode, fmt.Sprintf("pier lease of chain %s expired at height %d", chainID, lease.ExpireHeight))
+	}
+	return nil
+}
+
+func PierLeaseKey(chainID string) string {
+	return fmt.Sprintf("%s-%s", PIER_LEASE_PREFIX, chainID)
+}
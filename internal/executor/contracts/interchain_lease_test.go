@@ -0,0 +1,127 @@
+package contracts
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/boltvm/mock_stub"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	pierAdmin = "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b993"
+	pierA     = "pier-a"
+	pierB     = "pier-b"
+)
+
+func leasePrepare(t *testing.T) (*InterchainManager, *mock_stub.MockStub, *uint64) {
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+
+	height := uint64(100)
+	store := make(map[string][]byte)
+	mockStub.EXPECT().GetCurrentHeight().DoAndReturn(func() uint64 { return height }).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).DoAndReturn(func(key string, ret interface{}) bool {
+		data, ok := store[key]
+		if !ok {
+			return false
+		}
+		assert.Nil(t, json.Unmarshal(data, ret))
+		return true
+	}).AnyTimes()
+	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).DoAndReturn(func(key string, value interface{}) {
+		data, err := json.Marshal(value)
+		assert.Nil(t, err)
+		store[key] = data
+	}).AnyTimes()
+	admins, err := json.Marshal([]string{pierAdmin})
+	assert.Nil(t, err)
+	mockStub.EXPECT().CrossInvoke(constant.AppchainMgrContractAddr.Address().String(), "GetAdminByChainId", gomock.Any()).Return(boltvm.Success(admins)).AnyTimes()
+
+	return &InterchainManager{Stub: mockStub}, mockStub, &height
+}
+
+func TestInterchainManager_PierLease(t *testing.T) {
+	im, mockStub, height := leasePrepare(t)
+
+	mockStub.EXPECT().Caller().Return(feePayer).Times(1)
+	mockStub.EXPECT().Caller().Return(pierAdmin).AnyTimes()
+
+	// not admin of the chain
+	res := im.AcquirePierLease(srcChainID, pierA, 10)
+	assert.False(t, res.Ok, string(res.Result))
+	res = im.AcquirePierLease(srcChainID, pierA, MaxPierLeaseTTL+1)
+	assert.False(t, res.Ok, string(res.Result))
+	res = im.GetPierLease(srcChainID)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = im.AcquirePierLease(srcChainID, pierA, 10)
+	assert.True(t, res.Ok, string(res.Result))
+	lease := &PierLease{}
+	assert.Nil(t, json.Unmarshal(res.Result, lease))
+	assert.Equal(t, PierLease{ChainID: srcChainID, Holder: pierA, Token: 1, ExpireHeight: 110}, *lease)
+
+	// the alive lease is held by pier a
+	res = im.AcquirePierLease(srcChainID, pierB, 10)
+	assert.False(t, res.Ok, string(res.Result))
+	res = im.RenewPierLease(srcChainID, pierB, 1, 10)
+	assert.False(t, res.Ok, string(res.Result))
+
+	*height = 105
+	res = im.RenewPierLease(srcChainID, pierA, 1, 10)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Nil(t, json.Unmarshal(res.Result, lease))
+	assert.Equal(t, uint64(1), lease.Token)
+	assert.Equal(t, uint64(115), lease.ExpireHeight)
+
+	// pier b gets a new token after the lease of pier a expires
+	*height = 115
+	res = im.RenewPierLease(srcChainID, pierA, 1, 10)
+	assert.False(t, res.Ok, string(res.Result))
+	res = im.AcquirePierLease(srcChainID, pierB, 10)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Nil(t, json.Unmarshal(res.Result, lease))
+	assert.Equal(t, PierLease{ChainID: srcChainID, Holder: pierB, Token: 2, ExpireHeight: 125}, *lease)
+
+	// released lease can be acquired at once
+	res = im.ReleasePierLease(srcChainID, pierB, 1)
+	assert.False(t, res.Ok, string(res.Result))
+	res = im.ReleasePierLease(srcChainID, pierB, 2)
+	assert.True(t, res.Ok, string(res.Result))
+	res = im.AcquirePierLease(srcChainID, pierA, 10)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Nil(t, json.Unmarshal(res.Result, lease))
+	assert.Equal(t, uint64(3), lease.Token)
+}
+
+func TestInterchainManager_CheckFencingToken(t *testing.T) {
+	im, mockStub, height := leasePrepare(t)
+	mockStub.EXPECT().Caller().Return(pierAdmin).AnyTimes()
+
+	// chains without lease are not fenced
+	assert.Nil(t, im.checkFencingToken(srcChainID))
+
+	res := im.AcquirePierLease(srcChainID, pierA, 10)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.NotNil(t, im.checkFencingToken(srcChainID))
+
+	payload, err := json.Marshal(IBTPTxPayload{FencingToken: 1})
+	assert.Nil(t, err)
+	im.FencingToken = ParseFencingToken(payload)
+	assert.Nil(t, im.checkFencingToken(srcChainID))
+
+	// the stale master is rejected after a new lease is handed out
+	*height = 110
+	assert.NotNil(t, im.checkFencingToken(srcChainID))
+	res = im.AcquirePierLease(srcChainID, pierB, 10)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.NotNil(t, im.checkFencingToken(srcChainID))
+	im.FencingToken = 2
+	assert.Nil(t, im.checkFencingToken(srcChainID))
+
+	assert.Equal(t, uint64(0), ParseFencingToken(nil))
+	assert.Equal(t, uint64(0), ParseFencingToken([]byte("invalid")))
+}
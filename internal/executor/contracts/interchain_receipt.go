@@ -0,0 +1,163 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+
+	"github.com/meshplus/bitxhub-core/boltvm"
+)
+
+const (
+	RECEIPT_WINDOW_PREFIX = "receipt-window"
+	// ReceiptBatchWindow is how far ahead of the receipt counter a receipt of an unordered service can be
+	ReceiptBatchWindow = 1024
+)
+
+// ReceiptWindow records the receipts of unordered services which arrive ahead of the receipt counter.
+// Bit k of Bitmap marks that the receipt with index ReceiptCounter+1+k has been recorded,
+// so ReceiptCounter stays the highest index below which all receipts are recorded.
+type ReceiptWindow struct {
+	Bitmap []uint64 `json:"bitmap"`
+}
+
+type ReceiptWindowInfo struct {
+	ReceiptCounter uint64   `json:"receipt_counter"`
+	Received       []uint64 `json:"received"`
+}
+
+func (w *ReceiptWindow) isSet(offset uint64) bool {
+	if offset/64 >= uint64(len(w.Bitmap)) {
+		return false
+	}
+	return w.Bitmap[offset/64]&(1<<(offset%64)) != 0
+}
+
+func (w *ReceiptWindow) set(offset uint64) {
+	for offset/64 >= uint64(len(w.Bitmap)) {
+		w.Bitmap = append(w.Bitmap, 0)
+	}
+	w.Bitmap[offset/64] |= 1 << (offset % 64)
+}
+
+func (w *ReceiptWindow) isEmpty() bool {
+	for _, word := range w.Bitmap {
+		if word != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// advance drops the continuous recorded receipts at the head of the window and returns the number of them
+func (w *ReceiptWindow) advance() uint64 {
+	var n uint64
+	for _, word := range w.Bitmap {
+		ones := uint64(bits.TrailingZeros64(^word))
+		n += ones
+		if ones < 64 {
+			break
+		}
+	}
+	w.shift(n)
+	return n
+}
+
+func (w *ReceiptWindow) shift(n uint64) {
+	words, offset := int(n/64), n%64
+	if words >= len(w.Bitmap) {
+		w.Bitmap = nil
+		return
+	}
+
+	bitmap := make([]uint64, len(w.Bitmap)-words)
+	for i := range bitmap {
+		bitmap[i] = w.Bitmap[i+words] >> offset
+		if offset != 0 && i+words+1 < len(w.Bitmap) {
+			bitmap[i] |= w.Bitmap[i+words+1] << (64 - offset)
+		}
+	}
+	for len(bitmap) != 0 && bitmap[len(bitmap)-1] == 0 {
+		bitmap = bitmap[:len(bitmap)-1]
+	}
+	w.Bitmap = bitmap
+}
+
+// record marks the receipt with the given index and returns the new receipt counter,
+// receipts beyond the window are rejected and the window is left unchanged
+func (w *ReceiptWindow) record(counter, index uint64) (uint64, error) {
+	if index <= counter {
+		return counter, nil
+	}
+
+	offset := index - counter - 1
+	if offset >= ReceiptBatchWindow {
+		return counter, fmt.Errorf("receipt index %d is beyond the receipt window of counter %d", index, counter)
+	}
+	w.set(offset)
+	return counter + w.advance(), nil
+}
+
+// received returns the recorded indexes ahead of the counter
+func (w *ReceiptWindow) received(counter uint64) []uint64 {
+	ret := make([]uint64, 0)
+	for i, word := range w.Bitmap {
+		for word != 0 {
+			k := uint64(bits.TrailingZeros64(word))
+			ret = append(ret, counter+1+uint64(i)*64+k)
+			word &= word - 1
+		}
+	}
+	return ret
+}
+
+// checkReceiptIndex checks the index of receipt: ordered services must submit receipts one by one,
+// unordered services can submit receipts out of order within the window
+func checkReceiptIndex(counter uint64, window *ReceiptWindow, index uint64, isBatch bool) *boltvm.BxhError {
+	if !isBatch {
+		return checkIndex(counter+1, index)
+	}
+
+	if index <= counter {
+		return boltvm.BError(boltvm.InterchainIbtpIndexExistCode, fmt.Sprintf(string(boltvm.InterchainIbtpIndexExistMsg), counter+1, index))
+	}
+	offset := index - counter - 1
+	if offset >= ReceiptBatchWindow {
+		return boltvm.BError(boltvm.InterchainIbtpIndexWrongCode, fmt.Sprintf(string(boltvm.InterchainIbtpIndexWrongMsg), counter+1, index))
+	}
+	if window.isSet(offset) {
+		return boltvm.BError(boltvm.InterchainIbtpIndexExistCode, fmt.Sprintf(string(boltvm.InterchainIbtpIndexExistMsg), counter+1, index))
+	}
+
+	return nil
+}
+
+func (x *InterchainManager) getReceiptWindow(from, to string) *ReceiptWindow {
+	window := &ReceiptWindow{}
+	_ = x.GetObject(ReceiptWindowKey(from, to), window)
+	return window
+}
+
+// GetReceiptWindow returns the receipt counter from source service to destination service
+// and the receipts recorded ahead of it
+func (x *InterchainManager) GetReceiptWindow(from, to string) *boltvm.Response {
+	interchain, ok := x.getInterchain(from)
+	if !ok {
+		return boltvm.Error(boltvm.InterchainNonexistentInterchainCode, fmt.Sprintf(string(boltvm.InterchainNonexistentInterchainMsg), from))
+	}
+
+	counter := interchain.ReceiptCounter[to]
+	info := &ReceiptWindowInfo{
+		ReceiptCounter: counter,
+		Received:       x.getReceiptWindow(from, to).received(counter),
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+func ReceiptWindowKey(from, to string) string {
+	return fmt.Sprintf("%s-%s-%s", RECEIPT_WINDOW_PREFIX, from, to)
+}
@@ -0,0 +1,98 @@
+package contracts
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/boltvm/mock_stub"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiptWindow_Record(t *testing.T) {
+	window := &ReceiptWindow{}
+
+	// out of order receipts are kept in the window
+	counter, _ := window.record(10, 13)
+	assert.Equal(t, uint64(10), counter)
+	counter, _ = window.record(counter, 12)
+	assert.Equal(t, uint64(10), counter)
+	counter, _ = window.record(counter, 80)
+	assert.Equal(t, uint64(10), counter)
+	assert.Equal(t, []uint64{12, 13, 80}, window.received(counter))
+
+	// the missing receipt moves the counter over all continuous recorded receipts
+	counter, _ = window.record(counter, 11)
+	assert.Equal(t, uint64(13), counter)
+	assert.Equal(t, []uint64{80}, window.received(counter))
+	assert.True(t, window.isSet(80-13-1))
+
+	for i := uint64(14); i < 80; i++ {
+		counter, _ = window.record(counter, i)
+	}
+	assert.Equal(t, uint64(80), counter)
+	assert.True(t, window.isEmpty())
+
+	// recorded receipt is ignored
+	counter, _ = window.record(counter, 70)
+	assert.Equal(t, uint64(80), counter)
+
+	// receipt beyond the window is rejected and the recorded receipts are kept
+	counter, err := window.record(counter, 82)
+	assert.Nil(t, err)
+	_, err = window.record(counter, 80+ReceiptBatchWindow+1)
+	assert.NotNil(t, err)
+	assert.Equal(t, []uint64{82}, window.received(counter))
+}
+
+func TestCheckReceiptIndex(t *testing.T) {
+	window := &ReceiptWindow{}
+	window.record(10, 12)
+
+	// ordered service
+	assert.Nil(t, checkReceiptIndex(10, window, 11, false))
+	assert.Equal(t, boltvm.InterchainIbtpIndexWrongCode, checkReceiptIndex(10, window, 13, false).Code)
+	assert.Equal(t, boltvm.InterchainIbtpIndexExistCode, checkReceiptIndex(10, window, 10, false).Code)
+
+	// unordered service
+	assert.Nil(t, checkReceiptIndex(10, window, 11, true))
+	assert.Nil(t, checkReceiptIndex(10, window, 13, true))
+	assert.Equal(t, boltvm.InterchainIbtpIndexExistCode, checkReceiptIndex(10, window, 12, true).Code)
+	assert.Equal(t, boltvm.InterchainIbtpIndexExistCode, checkReceiptIndex(10, window, 9, true).Code)
+	assert.Equal(t, boltvm.InterchainIbtpIndexWrongCode, checkReceiptIndex(10, window, 11+ReceiptBatchWindow, true).Code)
+}
+
+func TestInterchainManager_GetReceiptWindow(t *testing.T) {
+	srcChainService, dstChainService := mockChainService()
+	from := srcChainService.getFullServiceId()
+	to := dstChainService.getFullServiceId()
+
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+	im := &InterchainManager{Stub: mockStub}
+
+	interchain := pb.Interchain{
+		ID:             from,
+		ReceiptCounter: map[string]uint64{to: 3},
+	}
+	data, err := interchain.Marshal()
+	assert.Nil(t, err)
+	window := &ReceiptWindow{}
+	window.record(3, 6)
+
+	mockStub.EXPECT().Get(serviceKey(to)).Return(false, nil).AnyTimes()
+	mockStub.EXPECT().Get(serviceKey(from)).Return(true, data).AnyTimes()
+	mockStub.EXPECT().GetObject(ReceiptWindowKey(from, to), gomock.Any()).SetArg(1, *window).Return(true).AnyTimes()
+
+	res := im.GetReceiptWindow(to, from)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = im.GetReceiptWindow(from, to)
+	assert.True(t, res.Ok, string(res.Result))
+	info := &ReceiptWindowInfo{}
+	assert.Nil(t, json.Unmarshal(res.Result, info))
+	assert.Equal(t, uint64(3), info.ReceiptCounter)
+	assert.Equal(t, []uint64{6}, info.Received)
+}
@@ -0,0 +1,134 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/governance"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+)
+
+const ROUTE_TABLE_KEY = "route-table"
+
+// Route is the neighbour relay chain which IBTPs to the destination bitxhub are forwarded to,
+// bitxhubs registered in current bitxhub are neighbours and need no route
+type Route struct {
+	BxhID   string `json:"bxh_id"`
+	NextHop string `json:"next_hop"`
+}
+
+// SetRoute submits a proposal to set the next hop of IBTPs to the destination bitxhub,
+// an empty next hop removes the route
+func (x *InterchainManager) SetRoute(bxhID, nextHop, reason string) *boltvm.Response {
+	if err := checkPermission(x.Stub, []string{string(PermissionAdmin)}, "", x.Caller(), nil); err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("only governance admin can set route: %v", err))
+	}
+
+	localID, err := x.getBitXHubID()
+	if err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+	if bxhID == "" || bxhID == localID {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("invalid destination bitxhub %s", bxhID))
+	}
+	if nextHop != "" {
+		if nextHop == bxhID || nextHop == localID {
+			return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("invalid next hop %s to bitxhub %s", nextHop, bxhID))
+		}
+		if err := x.checkBitXHubAvailability(nextHop); err != nil {
+			return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("next hop should be a neighbour: %v", err))
+		}
+	} else if _, ok := x.getRoutes()[bxhID]; !ok {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("route to bitxhub %s does not exist", bxhID))
+	}
+
+	extra, err := json.Marshal(Route{BxhID: bxhID, NextHop: nextHop})
+	if err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("marshal route error: %v", err))
+	}
+
+	res := x.CrossInvoke(constant.GovernanceContractAddr.Address().String(), "SubmitProposal",
+		pb.String(x.Caller()),
+		pb.String(string(governance.EventUpdate)),
+		pb.String(string(InterchainMgr)),
+		pb.String(RouteObjID(bxhID)),
+		pb.String(""), // no last status
+		pb.String(reason),
+		pb.Bytes(extra),
+	)
+	if !res.Ok {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("submit proposal error: %s", string(res.Result)))
+	}
+
+	x.CrossInvoke(constant.GovernanceContractAddr.Address().String(), "ZeroPermission", pb.String(string(res.Result)))
+
+	return getGovernanceRet(string(res.Result), nil)
+}
+
+func (x *InterchainManager) manageRoute(extra []byte) *boltvm.Response {
+	route := &Route{}
+	if err := json.Unmarshal(extra, route); err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, fmt.Sprintf("unmarshal route error: %v", err))
+	}
+	routes := x.getRoutes()
+	if route.NextHop == "" {
+		delete(routes, route.BxhID)
+	} else {
+		routes[route.BxhID] = route.NextHop
+	}
+	x.SetObject(ROUTE_TABLE_KEY, routes)
+
+	return boltvm.Success(nil)
+}
+
+// GetRoutes returns the next hop of every destination bitxhub
+func (x *InterchainManager) GetRoutes() *boltvm.Response {
+	data, err := json.Marshal(x.getRoutes())
+	if err != nil {
+		return boltvm.Error(boltvm.InterchainInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+// GetNextHop returns the neighbour relay chain which IBTPs to the bitxhub are forwarded to
+func (x *InterchainManager) GetNextHop(bxhID string) *boltvm.Response {
+	return boltvm.Success([]byte(x.nextHop(bxhID)))
+}
+
+func (x *InterchainManager) getRoutes() map[string]string {
+	routes := make(map[string]string)
+	_ = x.GetObject(ROUTE_TABLE_KEY, &routes)
+	return routes
+}
+
+// nextHop returns the neighbour relay chain on the way to the bitxhub,
+// IBTPs from the bitxhub also come from it
+func (x *InterchainManager) nextHop(bxhID string) string {
+	if hop, ok := x.getRoutes()[bxhID]; ok {
+		return hop
+	}
+	return bxhID
+}
+
+// isForwardable checks whether IBTPs from the bitxhub can be forwarded to another bitxhub,
+// which is either a neighbour or reachable through the routing table
+func (x *InterchainManager) isForwardable(from, to string) bool {
+	if from == to {
+		return false
+	}
+	if _, ok := x.getRoutes()[to]; ok {
+		return true
+	}
+	return x.checkBitXHubAvailability(to) == nil
+}
+
+func RouteObjID(bxhID string) string {
+	return fmt.Sprintf("%s-%s", ROUTE_TABLE_KEY, bxhID)
+}
+
+func isRouteObjID(objId string) bool {
+	return strings.HasPrefix(objId, ROUTE_TABLE_KEY+"-")
+}
@@ -0,0 +1,154 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/boltvm/mock_stub"
+	"github.com/meshplus/bitxhub-core/governance"
+	"github.com/meshplus/bitxhub-kit/log"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	routeSrcBxh  = "bxh1"
+	routeHopBxh  = "bxh2"
+	routeDstBxh  = "bxh3"
+	routeNoneBxh = "bxh4"
+)
+
+func routePrepare(t *testing.T) (*InterchainManager, *mock_stub.MockStub) {
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+
+	mockStub.EXPECT().Get(BitXHubID).Return(true, []byte("bxh")).AnyTimes()
+	mockStub.EXPECT().Logger().Return(log.NewWithModule("contracts")).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.AppchainMgrContractAddr.Address().String(), "IsAvailableBitxhub", gomock.Any()).DoAndReturn(
+		func(addr, method string, args ...*pb.Arg) *boltvm.Response {
+			switch string(args[0].Value) {
+			case routeSrcBxh, routeHopBxh:
+				return boltvm.Success([]byte(TRUE))
+			}
+			return boltvm.Success([]byte(FALSE))
+		}).AnyTimes()
+
+	return &InterchainManager{Stub: mockStub}, mockStub
+}
+
+func TestInterchainManager_SetRoute(t *testing.T) {
+	im, mockStub := routePrepare(t)
+
+	mockStub.EXPECT().Caller().Return(adminAddr).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte(FALSE))).Times(1)
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte(TRUE))).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.GovernanceContractAddr.Address().String(), "SubmitProposal",
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte("proposal-0"))).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.GovernanceContractAddr.Address().String(), "ZeroPermission", gomock.Any()).Return(boltvm.Success(nil)).AnyTimes()
+	mockStub.EXPECT().GetObject(ROUTE_TABLE_KEY, gomock.Any()).Return(false).AnyTimes()
+
+	// not governance admin
+	res := im.SetRoute(routeDstBxh, routeHopBxh, reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// illegal destination
+	res = im.SetRoute("bxh", routeHopBxh, reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// illegal next hop
+	res = im.SetRoute(routeDstBxh, routeDstBxh, reason)
+	assert.False(t, res.Ok, string(res.Result))
+	res = im.SetRoute(routeDstBxh, routeNoneBxh, reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// remove nonexistent route
+	res = im.SetRoute(routeDstBxh, "", reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = im.SetRoute(routeDstBxh, routeHopBxh, reason)
+	assert.True(t, res.Ok, string(res.Result))
+	ret := &governance.GovernanceResult{}
+	assert.Nil(t, json.Unmarshal(res.Result, ret))
+	assert.Equal(t, "proposal-0", ret.ProposalID)
+}
+
+func TestInterchainManager_ManageRoute(t *testing.T) {
+	im, mockStub := routePrepare(t)
+
+	mockStub.EXPECT().CurrentCaller().Return(constant.GovernanceContractAddr.Address().String()).AnyTimes()
+	routes := map[string]string{}
+	mockStub.EXPECT().GetObject(ROUTE_TABLE_KEY, gomock.Any()).DoAndReturn(func(key string, ret interface{}) bool {
+		m := make(map[string]string)
+		for k, v := range routes {
+			m[k] = v
+		}
+		*ret.(*map[string]string) = m
+		return true
+	}).AnyTimes()
+	mockStub.EXPECT().SetObject(ROUTE_TABLE_KEY, gomock.Any()).DoAndReturn(func(key string, value interface{}) {
+		routes = value.(map[string]string)
+	}).AnyTimes()
+
+	extra, err := json.Marshal(Route{BxhID: routeDstBxh, NextHop: routeHopBxh})
+	assert.Nil(t, err)
+	res := im.Manage(string(governance.EventUpdate), string(APPROVED), "", RouteObjID(routeDstBxh), extra)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, map[string]string{routeDstBxh: routeHopBxh}, routes)
+
+	res = im.GetNextHop(routeDstBxh)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, routeHopBxh, string(res.Result))
+	res = im.GetNextHop(routeSrcBxh)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, routeSrcBxh, string(res.Result))
+
+	res = im.GetRoutes()
+	assert.True(t, res.Ok, string(res.Result))
+	ret := make(map[string]string)
+	assert.Nil(t, json.Unmarshal(res.Result, &ret))
+	assert.Equal(t, routes, ret)
+
+	// empty next hop removes the route
+	extra, err = json.Marshal(Route{BxhID: routeDstBxh})
+	assert.Nil(t, err)
+	res = im.Manage(string(governance.EventUpdate), string(APPROVED), "", RouteObjID(routeDstBxh), extra)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, map[string]string{}, routes)
+}
+
+func TestInterchainManager_CheckForwardedIBTP(t *testing.T) {
+	im, mockStub := routePrepare(t)
+
+	from := fmt.Sprintf("%s:appchain1:service", routeSrcBxh)
+	to := fmt.Sprintf("%s:appchain2:service", routeDstBxh)
+	mockStub.EXPECT().Get(gomock.Any()).Return(false, nil).AnyTimes()
+	mockStub.EXPECT().GetObject(ROUTE_TABLE_KEY, gomock.Any()).SetArg(1, map[string]string{routeDstBxh: routeHopBxh}).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+
+	// request from the source bitxhub is forwarded to the next hop
+	ibtp := &pb.IBTP{From: from, To: to, Index: 1, Type: pb.IBTP_INTERCHAIN}
+	_, _, targetErr, bxhErr := im.checkIBTP(ibtp)
+	assert.Nil(t, bxhErr)
+	assert.Nil(t, targetErr)
+
+	// receipt from the destination bitxhub is forwarded back to the source bitxhub
+	ibtp.Type = pb.IBTP_RECEIPT_SUCCESS
+	_, _, _, bxhErr = im.checkIBTP(ibtp)
+	assert.Nil(t, bxhErr)
+
+	// unreachable destination
+	ibtp = &pb.IBTP{From: from, To: fmt.Sprintf("%s:appchain2:service", routeNoneBxh), Index: 1, Type: pb.IBTP_INTERCHAIN}
+	_, _, _, bxhErr = im.checkIBTP(ibtp)
+	assert.NotNil(t, bxhErr)
+	assert.Equal(t, boltvm.InterchainInvalidIBTPNotInCurBXHCode, bxhErr.Code)
+
+	// source bitxhub is reached through the next hop which is not available
+	ibtp = &pb.IBTP{From: fmt.Sprintf("%s:appchain2:service", routeNoneBxh), To: from, Index: 1, Type: pb.IBTP_INTERCHAIN}
+	_, _, _, bxhErr = im.checkIBTP(ibtp)
+	assert.NotNil(t, bxhErr)
+	assert.Equal(t, boltvm.InterchainSourceBitXHubNotAvailableCode, bxhErr.Code)
+}
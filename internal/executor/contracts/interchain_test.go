@@ -67,7 +67,7 @@ func TestInterchainManager_Register(t *testing.T) {
 	o3 := mockStub.EXPECT().Get(service_mgr.ServiceKey(srcChainService.getFullServiceId())).Return(true, data1).Times(2)
 	gomock.InOrder(o1, o2, o3)
 
-	im := &InterchainManager{mockStub, &sync.Map{}}
+	im := &InterchainManager{Stub: mockStub, ServiceCache: &sync.Map{}}
 
 	mockStub.EXPECT().PostEvent(gomock.Any(), gomock.Any()).AnyTimes()
 	mockStub.EXPECT().EnableAudit().Return(true).AnyTimes()
@@ -117,7 +117,7 @@ func TestInterchainManager_GetInterchain(t *testing.T) {
 	o2 := mockStub.EXPECT().Get(service_mgr.ServiceKey(srcChainService.getFullServiceId())).Return(true, data0)
 	gomock.InOrder(o1, o2)
 
-	im := &InterchainManager{mockStub, &sync.Map{}}
+	im := &InterchainManager{Stub: mockStub, ServiceCache: &sync.Map{}}
 
 	res := im.GetInterchain(srcChainService.getFullServiceId())
 	assert.False(t, res.Ok)
@@ -298,7 +298,7 @@ func TestInterchainManager_HandleIBTP(t *testing.T) {
 	mockStub.EXPECT().PostEvent(gomock.Any(), gomock.Any()).AnyTimes()
 	mockStub.EXPECT().EnableAudit().Return(true).AnyTimes()
 
-	im := &InterchainManager{mockStub, &sync.Map{}}
+	im := &InterchainManager{Stub: mockStub, ServiceCache: &sync.Map{}}
 	ibtp := &pb.IBTP{}
 
 	res := im.HandleIBTP(ibtp)
@@ -492,7 +492,7 @@ func TestInterchainManager_HandleIBTP(t *testing.T) {
 func TestInterchainManager_DeleteInterchain(t *testing.T) {
 	mockCtl := gomock.NewController(t)
 	mockStub := mock_stub.NewMockStub(mockCtl)
-	im := &InterchainManager{mockStub, &sync.Map{}}
+	im := &InterchainManager{Stub: mockStub, ServiceCache: &sync.Map{}}
 
 	mockStub.EXPECT().Delete(gomock.Any())
 	mockStub.EXPECT().PostEvent(gomock.Any(), gomock.Any()).AnyTimes()
@@ -510,7 +510,7 @@ func TestInterchainManager_GetIBTPByID(t *testing.T) {
 	from := types.NewAddress([]byte{0}).String()
 
 	mockStub.EXPECT().Caller().Return(from).AnyTimes()
-	im := &InterchainManager{mockStub, &sync.Map{}}
+	im := &InterchainManager{Stub: mockStub, ServiceCache: &sync.Map{}}
 
 	res := im.GetIBTPByID("a", true)
 	assert.False(t, res.Ok)
@@ -537,7 +537,7 @@ func TestInterchainManager_GetIBTPByID(t *testing.T) {
 func TestInterchainManager_HandleIBTPData(t *testing.T) {
 	mockCtl := gomock.NewController(t)
 	mockStub := mock_stub.NewMockStub(mockCtl)
-	im := &InterchainManager{mockStub, &sync.Map{}}
+	im := &InterchainManager{Stub: mockStub, ServiceCache: &sync.Map{}}
 
 	srcChainService, dstChainService := mockChainService()
 	ibtp := &pb.IBTP{
@@ -555,10 +555,63 @@ func TestInterchainManager_HandleIBTPData(t *testing.T) {
 	assert.False(t, res.Ok, string(res.Result))
 }
 
+func TestInterchainManager_ResolveIBTPNames(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+	im := &InterchainManager{Stub: mockStub, ServiceCache: &sync.Map{}}
+
+	srcChainService, dstChainService := mockChainService()
+	txHash := types.NewHash([]byte{1})
+	resolved, err := json.Marshal(&ResolvedDomain{Name: "dst.bank.hub", ServiceID: dstChainService.getFullServiceId()})
+	require.Nil(t, err)
+	unbound, err := json.Marshal(&ResolvedDomain{Name: "unbound.bank.hub"})
+	require.Nil(t, err)
+
+	resolverAddr := constant.ServiceResolverContractAddr.Address().String()
+	mockStub.EXPECT().GetTxHash().Return(txHash).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(resolverAddr, "Resolve", pb.String("dst.bank.hub")).Return(boltvm.Success(resolved)).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(resolverAddr, "Resolve", pb.String("unbound.bank.hub")).Return(boltvm.Success(unbound)).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(resolverAddr, "Resolve", pb.String("expired.bank.hub")).Return(boltvm.Error(boltvm.BnsErrCode, "The domain bank.hub is expired")).AnyTimes()
+	mockStub.EXPECT().SetObject(ResolvedIBTPKey(txHash.String()), &ResolvedIBTP{
+		From: srcChainService.getFullServiceId(),
+		To:   dstChainService.getFullServiceId(),
+	}).Times(1)
+
+	// ibtps without names are left as they are
+	ibtp := &pb.IBTP{
+		From:  srcChainService.getFullServiceId(),
+		To:    dstChainService.getFullServiceId(),
+		Index: 1,
+		Type:  pb.IBTP_INTERCHAIN,
+	}
+	ret, bxhErr := im.resolveIBTPNames(ibtp)
+	require.Nil(t, bxhErr)
+	require.True(t, ret == ibtp)
+
+	// names are resolved on a copy of the signed ibtp
+	ibtp.To = "dst.bank.hub"
+	ret, bxhErr = im.resolveIBTPNames(ibtp)
+	require.Nil(t, bxhErr)
+	require.Equal(t, dstChainService.getFullServiceId(), ret.To)
+	require.Equal(t, srcChainService.getFullServiceId(), ret.From)
+	require.Equal(t, "dst.bank.hub", ibtp.To)
+
+	ibtp.To = "unbound.bank.hub"
+	_, bxhErr = im.resolveIBTPNames(ibtp)
+	require.NotNil(t, bxhErr)
+	require.Equal(t, boltvm.InterchainInvalidIBTPParseDestErrorCode, bxhErr.Code)
+
+	ibtp.To = dstChainService.getFullServiceId()
+	ibtp.From = "expired.bank.hub"
+	_, bxhErr = im.resolveIBTPNames(ibtp)
+	require.NotNil(t, bxhErr)
+	require.Equal(t, boltvm.InterchainInvalidIBTPParseSourceErrorCode, bxhErr.Code)
+}
+
 func TestInterchainManager_GetAllServiceIDs(t *testing.T) {
 	mockCtl := gomock.NewController(t)
 	mockStub := mock_stub.NewMockStub(mockCtl)
-	im := &InterchainManager{mockStub, &sync.Map{}}
+	im := &InterchainManager{Stub: mockStub, ServiceCache: &sync.Map{}}
 
 	mockStub.EXPECT().Query(gomock.Any()).Return(false, nil).Times(1)
 	res := im.GetAllServiceIDs()
@@ -643,7 +696,7 @@ func TestInterchainManager_GetAllServiceIDs(t *testing.T) {
 //	mockStub.EXPECT().PostInterchainEvent(gomock.Any()).AnyTimes()
 //	mockStub.EXPECT().GetTxHash().Return(&types.Hash{}).AnyTimes()
 //
-//	im := &InterchainManager{mockStub, &sync.Map{}}
+//	im := &InterchainManager{Stub: mockStub, ServiceCache: &sync.Map{}}
 //
 //	ibtp := &pb.IBTP{
 //		From:    appchainMethod + "-" + appchainMethod,
@@ -0,0 +1,108 @@
+package contracts
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/governance"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+)
+
+const CERT_REVOCATION_PREFIX = "cert-revocation"
+
+// CertRevocation revokes the node or agency certificate with the fingerprint, which is the hex encoded
+// sha256 of the DER certificate. Nodes refuse connections from peers presenting a revoked certificate.
+type CertRevocation struct {
+	Fingerprint string `json:"fingerprint"`
+	Reason      string `json:"reason"`
+	Height      uint64 `json:"height"`
+}
+
+// RevokeCert submits a proposal to revoke the node or agency certificate with the fingerprint
+func (nm *NodeManager) RevokeCert(fingerprint, reason string) *boltvm.Response {
+	if err := nm.checkPermission([]string{string(PermissionAdmin)}, "", nm.CurrentCaller(), nil); err != nil {
+		return boltvm.Error(boltvm.NodeNoPermissionCode, fmt.Sprintf(string(boltvm.NodeNoPermissionMsg), nm.CurrentCaller(), fmt.Sprintf("check permission error:%v", err)))
+	}
+
+	fingerprint = strings.ToLower(fingerprint)
+	if fp, err := hex.DecodeString(fingerprint); err != nil || len(fp) != 32 {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf("invalid certificate fingerprint %s", fingerprint))
+	}
+	if _, ok := nm.getCertRevocation(fingerprint); ok {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf("certificate %s has been revoked", fingerprint))
+	}
+
+	extra, err := json.Marshal(CertRevocation{Fingerprint: fingerprint, Reason: reason})
+	if err != nil {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf("marshal cert revocation error: %v", err))
+	}
+
+	res := nm.CrossInvoke(constant.GovernanceContractAddr.Address().String(), "SubmitProposal",
+		pb.String(nm.Caller()),
+		pb.String(string(governance.EventUpdate)),
+		pb.String(string(NodeMgr)),
+		pb.String(CertRevocationObjID(fingerprint)),
+		pb.String(""), // no last status
+		pb.String(reason),
+		pb.Bytes(extra),
+	)
+	if !res.Ok {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf("submit proposal error: %s", string(res.Result)))
+	}
+
+	nm.CrossInvoke(constant.GovernanceContractAddr.Address().String(), "ZeroPermission", pb.String(string(res.Result)))
+
+	return getGovernanceRet(string(res.Result), nil)
+}
+
+func (nm *NodeManager) manageCertRevocation(eventTyp, proposalResult string, extra []byte) *boltvm.Response {
+	if proposalResult != string(APPROVED) || eventTyp != string(governance.EventUpdate) {
+		return boltvm.Success(nil)
+	}
+
+	revocation := &CertRevocation{}
+	if err := json.Unmarshal(extra, revocation); err != nil {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf("unmarshal cert revocation error: %v", err))
+	}
+	revocation.Height = nm.GetCurrentHeight()
+	nm.SetObject(CertRevocationKey(revocation.Fingerprint), *revocation)
+
+	return boltvm.Success(nil)
+}
+
+// GetCertRevocation returns the revocation of the certificate with the fingerprint
+func (nm *NodeManager) GetCertRevocation(fingerprint string) *boltvm.Response {
+	revocation, ok := nm.getCertRevocation(strings.ToLower(fingerprint))
+	if !ok {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf("certificate %s is not revoked", fingerprint))
+	}
+	data, err := json.Marshal(revocation)
+	if err != nil {
+		return boltvm.Error(boltvm.NodeInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+func (nm *NodeManager) getCertRevocation(fingerprint string) (*CertRevocation, bool) {
+	revocation := &CertRevocation{}
+	if ok := nm.GetObject(CertRevocationKey(fingerprint), revocation); !ok {
+		return nil, false
+	}
+	return revocation, true
+}
+
+func CertRevocationKey(fingerprint string) string {
+	return fmt.Sprintf("%s-%s", CERT_REVOCATION_PREFIX, fingerprint)
+}
+
+func CertRevocationObjID(fingerprint string) string {
+	return CertRevocationKey(fingerprint)
+}
+
+func isCertRevocationObjID(objId string) bool {
+	return strings.HasPrefix(objId, CERT_REVOCATION_PREFIX+"-")
+}
@@ -0,0 +1,85 @@
+package contracts
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/boltvm/mock_stub"
+	"github.com/meshplus/bitxhub-core/governance"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const certFingerprint = "9f41dd84524bf8a42f8ab58ecfca6e1752d6fd93fe8dc00af4c71963c97db59f"
+
+func TestNodeManager_RevokeCert(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+	nm := &NodeManager{Stub: mockStub}
+
+	store := make(map[string][]byte)
+	mockStub.EXPECT().GetCurrentHeight().Return(uint64(10)).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).DoAndReturn(func(key string, ret interface{}) bool {
+		data, ok := store[key]
+		if !ok {
+			return false
+		}
+		assert.Nil(t, json.Unmarshal(data, ret))
+		return true
+	}).AnyTimes()
+	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).DoAndReturn(func(key string, value interface{}) {
+		data, err := json.Marshal(value)
+		assert.Nil(t, err)
+		store[key] = data
+	}).AnyTimes()
+	mockStub.EXPECT().Caller().Return(adminAddr).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte(FALSE))).Times(1)
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte(TRUE))).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.GovernanceContractAddr.Address().String(), "SubmitProposal",
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte("proposal-0"))).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.GovernanceContractAddr.Address().String(), "ZeroPermission", gomock.Any()).Return(boltvm.Success(nil)).AnyTimes()
+
+	caller := adminAddr
+	mockStub.EXPECT().CurrentCaller().DoAndReturn(func() string { return caller }).AnyTimes()
+
+	// not governance admin
+	res := nm.RevokeCert(certFingerprint, reason)
+	assert.False(t, res.Ok, string(res.Result))
+	// illegal fingerprint
+	res = nm.RevokeCert("fingerprint", reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = nm.RevokeCert(strings.ToUpper(certFingerprint), reason)
+	assert.True(t, res.Ok, string(res.Result))
+	ret := &governance.GovernanceResult{}
+	assert.Nil(t, json.Unmarshal(res.Result, ret))
+	assert.Equal(t, "proposal-0", ret.ProposalID)
+
+	res = nm.GetCertRevocation(certFingerprint)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// rejected proposal revokes nothing
+	caller = constant.GovernanceContractAddr.Address().String()
+	extra, err := json.Marshal(CertRevocation{Fingerprint: certFingerprint, Reason: reason})
+	assert.Nil(t, err)
+	res = nm.Manage(string(governance.EventUpdate), string(REJECTED), "", CertRevocationObjID(certFingerprint), extra)
+	assert.True(t, res.Ok, string(res.Result))
+	res = nm.GetCertRevocation(certFingerprint)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = nm.Manage(string(governance.EventUpdate), string(APPROVED), "", CertRevocationObjID(certFingerprint), extra)
+	assert.True(t, res.Ok, string(res.Result))
+	res = nm.GetCertRevocation(certFingerprint)
+	assert.True(t, res.Ok, string(res.Result))
+	revocation := &CertRevocation{}
+	assert.Nil(t, json.Unmarshal(res.Result, revocation))
+	assert.Equal(t, CertRevocation{Fingerprint: certFingerprint, Reason: reason, Height: 10}, *revocation)
+
+	// revoked certificate can't be revoked again
+	caller = adminAddr
+	res = nm.RevokeCert(certFingerprint, reason)
+	assert.False(t, res.Ok, string(res.Result))
+}
@@ -3,6 +3,7 @@ package contracts
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -29,6 +30,8 @@ type UpdateNodeInfo struct {
 
 const (
 	MinimumVPNode = 4
+
+	TssCulpritPrefix = "tss-culprit"
 )
 
 func (nm *NodeManager) checkPermission(permissions []string, nodeAccount, regulatorAddr string, specificAddrsData []byte) error {
@@ -147,6 +150,15 @@ func (nm *NodeManager) Manage(eventTyp, proposalResult, lastStatus, objId string
 		return boltvm.Error(boltvm.NodeNoPermissionCode, fmt.Sprintf(string(boltvm.NodeNoPermissionMsg), nm.CurrentCaller(), fmt.Sprintf("check permission error:%v", err)))
 	}
 
+	// certificate revocations are not node objects
+	if isCertRevocationObjID(objId) {
+		return nm.manageCertRevocation(eventTyp, proposalResult, extra)
+	}
+	// tss freezes keep the node status
+	if isTssFreezeObjID(objId) {
+		return nm.manageTssFreeze(eventTyp, proposalResult, extra)
+	}
+
 	// 2. change status
 	ok, errData := nm.NodeManager.ChangeStatus(objId, proposalResult, lastStatus, nil)
 	if !ok {
@@ -185,6 +197,8 @@ func (nm *NodeManager) processApproveProposal(eventTyp string, nodeInfo *nodemgr
 		if nodeInfo.NodeType == nodemgr.VPNode {
 			nodeEvent := &events.NodeEvent{
 				NodeId:        nodeInfo.VPNodeId,
+				NodeAccount:   nodeInfo.Account,
+				NodeType:      nodeInfo.NodeType,
 				NodeEventType: governance.EventType(eventTyp),
 			}
 			nm.PostEvent(pb.Event_NODEMGR, nodeEvent)
@@ -223,11 +237,19 @@ func (nm *NodeManager) processApproveProposal(eventTyp string, nodeInfo *nodemgr
 		case nodemgr.VPNode:
 			nodeEvent := &events.NodeEvent{
 				NodeId:        nodeInfo.VPNodeId,
+				NodeAccount:   nodeInfo.Account,
+				NodeType:      nodeInfo.NodeType,
 				NodeEventType: governance.EventType(eventTyp),
 			}
 			nm.PostEvent(pb.Event_NODEMGR, nodeEvent)
 		case nodemgr.NVPNode:
 			nm.CrossInvoke(constant.RoleContractAddr.Address().String(), "PauseAuditAdmin", pb.String(objId))
+			nodeEvent := &events.NodeEvent{
+				NodeAccount:   objId,
+				NodeType:      nodeInfo.NodeType,
+				NodeEventType: governance.EventType(eventTyp),
+			}
+			nm.PostEvent(pb.Event_NODEMGR, nodeEvent)
 		}
 	}
 	return boltvm.Success(nil)
@@ -244,6 +266,9 @@ func (nm *NodeManager) processRejectProposal(eventTyp string, nodeInfo *nodemgr.
 			nm.freeNodePid(nodeInfo.Pid)
 		case nodemgr.NVPNode:
 			nm.freeNodeName(nodeInfo.Name)
+			if nodeInfo.Pid != "" {
+				nm.freeNodePid(nodeInfo.Pid)
+			}
 		}
 	case string(governance.EventUpdate):
 		nodeUpdateInfo := &UpdateNodeInfo{}
@@ -351,6 +376,9 @@ func (nm *NodeManager) RegisterNode(nodeAccount, nodeType, nodePid string, nodeV
 		nm.occupyNodePid(nodePid, nodeAccount)
 	case nodemgr.NVPNode:
 		nm.occupyNodeName(nodeName, nodeAccount)
+		if nodePid != "" {
+			nm.occupyNodePid(nodePid, nodeAccount)
+		}
 	}
 
 	// 6. submit proposal
@@ -416,13 +444,18 @@ func (nm *NodeManager) RegisterNode(nodeAccount, nodeType, nodePid string, nodeV
 //   - forbidden: logout cannot be logged out again
 func (nm *NodeManager) LogoutNode(nodeAccount, reason string) *boltvm.Response {
 	nm.NodeManager.Persister = nm.Stub
-	event := governance.EventLogout
 
 	// 1. check permission: PermissionAdmin
 	if err := nm.checkPermission([]string{string(PermissionAdmin)}, nodeAccount, nm.CurrentCaller(), nil); err != nil {
 		return boltvm.Error(boltvm.NodeNoPermissionCode, fmt.Sprintf(string(boltvm.NodeNoPermissionMsg), nm.CurrentCaller(), err.Error()))
 	}
 
+	return nm.logoutNode(nodeAccount, reason)
+}
+
+func (nm *NodeManager) logoutNode(nodeAccount, reason string) *boltvm.Response {
+	event := governance.EventLogout
+
 	// 2. governancePre: check status
 	nodeInfo, be := nm.NodeManager.GovernancePre(nodeAccount, event, nil)
 	if be != nil {
@@ -498,6 +531,93 @@ func (nm *NodeManager) LogoutNode(nodeAccount, reason string) *boltvm.Response {
 	return getGovernanceRet(string(res.Result), nil)
 }
 
+// =========== ReportTssCulprit is called by an available vp node to report a vp node which is blamed repeatedly in tss key sign.
+// Once more than 1/3 of the available vp nodes report the node, a proposal is submitted to freeze it out of
+// the tss signing group, the node is still a validator.
+func (nm *NodeManager) ReportTssCulprit(nodeAccount, reason string) *boltvm.Response {
+	nm.NodeManager.Persister = nm.Stub
+	reporter := nm.Caller()
+
+	// 1. check permission: available vp node
+	if _, err := nm.checkAvailableVPNode(reporter); err != nil {
+		return boltvm.Error(boltvm.NodeNoPermissionCode, fmt.Sprintf(string(boltvm.NodeNoPermissionMsg), reporter, err.Error()))
+	}
+	if reporter == nodeAccount {
+		return boltvm.Error(boltvm.NodeNoPermissionCode, fmt.Sprintf(string(boltvm.NodeNoPermissionMsg), reporter, "can not report itself"))
+	}
+
+	// 2. check culprit
+	node, err := nm.checkAvailableVPNode(nodeAccount)
+	if err != nil {
+		return boltvm.Error(boltvm.NodeIllegalAccountCode, fmt.Sprintf(string(boltvm.NodeIllegalAccountMsg), nodeAccount, err.Error()))
+	}
+	if freeze, ok := nm.getTssFreeze(nodeAccount); ok {
+		return boltvm.Error(boltvm.NodeIllegalAccountCode, fmt.Sprintf(string(boltvm.NodeIllegalAccountMsg), nodeAccount, fmt.Sprintf("node is %s out of tss", freeze.Status)))
+	}
+
+	// 3. record reporter
+	reporters := make(map[string]string)
+	_ = nm.GetObject(TssCulpritKey(nodeAccount), &reporters)
+	reporters[reporter] = reason
+
+	ok, data := nm.NodeManager.CountAvailable([]byte(nodemgr.VPNode))
+	if !ok {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf("count available nodes error: %s", string(data)))
+	}
+	vpNum, err := strconv.Atoi(string(data))
+	if err != nil {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf("get vp node num error: %v", err))
+	}
+	if len(reporters) <= (vpNum-1)/3 {
+		nm.SetObject(TssCulpritKey(nodeAccount), reporters)
+		return boltvm.Success(nil)
+	}
+
+	// 4. at least one honest vp node reports the culprit, submit freeze proposal
+	accounts := make([]string, 0, len(reporters))
+	for account := range reporters {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+	res := nm.freezeTssNode(node, fmt.Sprintf("tss culprit reported by %s: %s", strings.Join(accounts, ","), reason))
+	if !res.Ok {
+		return res
+	}
+	nm.Delete(TssCulpritKey(nodeAccount))
+
+	return res
+}
+
+// GetTssCulpritReporters returns the vp nodes which have reported the node as tss culprit with their reasons
+func (nm *NodeManager) GetTssCulpritReporters(nodeAccount string) *boltvm.Response {
+	reporters := make(map[string]string)
+	_ = nm.GetObject(TssCulpritKey(nodeAccount), &reporters)
+	data, err := json.Marshal(reporters)
+	if err != nil {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf(string(boltvm.NodeInternalErrMsg), err.Error()))
+	}
+	return boltvm.Success(data)
+}
+
+func (nm *NodeManager) checkAvailableVPNode(nodeAccount string) (*nodemgr.Node, error) {
+	node, err := nm.NodeManager.QueryById(nodeAccount, nil)
+	if err != nil {
+		return nil, err
+	}
+	nodeInfo := node.(*nodemgr.Node)
+	if nodeInfo.NodeType != nodemgr.VPNode {
+		return nil, fmt.Errorf("node %s is not a vp node", nodeAccount)
+	}
+	if !nodeInfo.IsAvailable() {
+		return nil, fmt.Errorf("node %s is not available", nodeAccount)
+	}
+	return nodeInfo, nil
+}
+
+func TssCulpritKey(nodeAccount string) string {
+	return fmt.Sprintf("%s-%s", TssCulpritPrefix, nodeAccount)
+}
+
 // =========== UpdateNode updates audit node
 func (nm *NodeManager) UpdateNode(nodeAccount, nodeName, permitStr, reason string) *boltvm.Response {
 	nm.NodeManager.Persister = nm.Stub
@@ -942,7 +1062,14 @@ func (nm *NodeManager) checkNodeInfo(node *nodemgr.Node, isRegister bool) *boltv
 			}
 		}
 
-		// 6. check permission
+		// 6. check the pid of follower
+		if isRegister && node.Pid != "" {
+			if ok, nodeAccount := nm.isOccupiedPid(node.Pid); ok {
+				return boltvm.Error(boltvm.NodeDuplicatePidCode, fmt.Sprintf(string(boltvm.NodeDuplicatePidMsg), node.Pid, nodeAccount))
+			}
+		}
+
+		// 7. check permission
 		if len(node.Permissions) == 0 {
 			return boltvm.Error(boltvm.NodeEmptyPermissionCode, string(boltvm.NodeEmptyPermissionMsg))
 		}
@@ -81,6 +81,41 @@ func TestNodeManager_RegisterNode(t *testing.T) {
 	assert.True(t, res.Ok, string(res.Result))
 }
 
+func TestNodeManager_RegisterFollowerNode(t *testing.T) {
+	nm, mockStub, nvpNodes, _ := nvpNodePrepare(t)
+
+	followerAccount := fmt.Sprintf("%s%d", NVP_NODE_ACCOUNT[0:len(NVP_NODE_ACCOUNT)-1], 9)
+	followerName := fmt.Sprintf("%s%d", NODE_NAME, 9)
+	followerPid := "QmbmD1kzdsxRiawxu7bRrteDgW1ituXupR8GH6E2EUAHY4"
+	mockStub.EXPECT().GetObject(node_mgr.NodeOccupyPidKey(NODE_PID), gomock.Any()).SetArg(1, nvpNodes[1].Account).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	mockStub.EXPECT().Caller().Return("").AnyTimes()
+	mockStub.EXPECT().CurrentCaller().Return(adminAddr).AnyTimes()
+	mockStub.EXPECT().SetObject(node_mgr.NodeOccupyPidKey(followerPid), followerAccount).Times(1)
+	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.String(), "IsAnyAvailableAdmin", pb.String(adminAddr), pb.String(string(GovernanceAdmin))).Return(boltvm.Success([]byte(TRUE))).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.Address().String(), "CheckOccupiedAccount",
+		gomock.Any()).Return(boltvm.Success([]byte(""))).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.Address().String(), "OccupyAccount",
+		gomock.Any(), gomock.Any()).Return(boltvm.Success(nil)).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.AppchainMgrContractAddr.Address().String(), "GetAppchain", gomock.Any()).Return(boltvm.Success(nil)).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(gomock.Eq(constant.GovernanceContractAddr.Address().String()), gomock.Eq("SubmitProposal"),
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(boltvm.Success(nil)).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(gomock.Eq(constant.GovernanceContractAddr.Address().String()), gomock.Eq("ZeroPermission"),
+		gomock.Any()).Return(boltvm.Success(nil)).AnyTimes()
+	mockStub.EXPECT().Logger().Return(log.NewWithModule("contracts")).AnyTimes()
+	mockStub.EXPECT().EnableAudit().Return(false).AnyTimes()
+	mockStub.EXPECT().GetTxTimeStamp().Return(int64(1)).AnyTimes()
+
+	// 1. the pid is occupied
+	res := nm.RegisterNode(followerAccount, string(node_mgr.NVPNode), NODE_PID, 0, followerName, appchainID, reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// 2. the pid of follower is occupied by the registration
+	res = nm.RegisterNode(followerAccount, string(node_mgr.NVPNode), followerPid, 0, followerName, appchainID, reason)
+	assert.True(t, res.Ok, string(res.Result))
+}
+
 func TestNodeManager_LogoutNode(t *testing.T) {
 	nm, mockStub, nodes, nodesData := vpNodePrepare(t)
 
@@ -159,6 +194,62 @@ func TestNodeManager_LogoutNode(t *testing.T) {
 	assert.True(t, res.Ok, string(res.Result))
 }
 
+func TestNodeManager_ReportTssCulprit(t *testing.T) {
+	nm, mockStub, nodes, _ := vpNodePrepare(t)
+
+	accountMap := orderedmap.New()
+	for i := 0; i < 6; i++ {
+		accountMap.Set(nodes[i].Account, struct{}{})
+	}
+	mockStub.EXPECT().GetObject(node_mgr.NodeTypeKey(string(node_mgr.VPNode)), gomock.Any()).SetArg(1, *accountMap).Return(true).AnyTimes()
+	for _, node := range nodes {
+		mockStub.EXPECT().GetObject(node_mgr.NodeKey(node.Account), gomock.Any()).SetArg(1, *node).Return(true).AnyTimes()
+	}
+	reporters := map[string]string{nodes[2].Account: reason}
+	mockStub.EXPECT().GetObject(TssFreezeKey(nodes[1].Account), gomock.Any()).Return(false).AnyTimes()
+	mockStub.EXPECT().GetObject(TssCulpritKey(nodes[1].Account), gomock.Any()).Return(false).Times(1)
+	mockStub.EXPECT().GetObject(TssCulpritKey(nodes[1].Account), gomock.Any()).SetArg(1, reporters).Return(true).AnyTimes()
+
+	mockStub.EXPECT().Caller().Return(nodes[5].Account).Times(1)
+	mockStub.EXPECT().Caller().Return(nodes[2].Account).Times(3)
+	mockStub.EXPECT().Caller().Return(nodes[3].Account).AnyTimes()
+
+	mockStub.EXPECT().SetObject(TssCulpritKey(nodes[1].Account), gomock.Any()).Times(1)
+	mockStub.EXPECT().SetObject(TssFreezeKey(nodes[1].Account), gomock.Any()).Times(1)
+	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).AnyTimes()
+	mockStub.EXPECT().Delete(TssCulpritKey(nodes[1].Account)).Times(1)
+	mockStub.EXPECT().CrossInvoke(gomock.Eq(constant.GovernanceContractAddr.Address().String()), gomock.Eq("SubmitProposal"),
+		gomock.Any(), gomock.Eq(pb.String(string(governance.EventFreeze))), gomock.Any(), gomock.Eq(pb.String(TssFreezeObjID(nodes[1].Account))), gomock.Any(), gomock.Any(), gomock.Any()).Return(boltvm.Success(nil)).Times(1)
+	mockStub.EXPECT().CrossInvoke(gomock.Eq(constant.GovernanceContractAddr.Address().String()), gomock.Eq("ZeroPermission"),
+		gomock.Any()).Return(boltvm.Success(nil)).AnyTimes()
+	mockStub.EXPECT().Logger().Return(log.NewWithModule("contracts")).AnyTimes()
+	mockStub.EXPECT().EnableAudit().Return(false).AnyTimes()
+	mockStub.EXPECT().Query(gomock.Any()).Return(false, nil).AnyTimes()
+
+	// 1. reporter is not an available vp node
+	res := nm.ReportTssCulprit(nodes[1].Account, reason)
+	assert.False(t, res.Ok, string(res.Result))
+	// 2. report itself
+	res = nm.ReportTssCulprit(nodes[2].Account, reason)
+	assert.False(t, res.Ok, string(res.Result))
+	// 3. culprit is not available
+	res = nm.ReportTssCulprit(nodes[6].Account, reason)
+	assert.False(t, res.Ok, string(res.Result))
+	// 4. reported by 1 of 5 vp nodes, only record the reporter
+	res = nm.ReportTssCulprit(nodes[1].Account, reason)
+	assert.True(t, res.Ok, string(res.Result))
+
+	// 5. reported by 2 of 5 vp nodes, submit freeze proposal
+	res = nm.ReportTssCulprit(nodes[1].Account, reason)
+	assert.True(t, res.Ok, string(res.Result))
+
+	res = nm.GetTssCulpritReporters(nodes[1].Account)
+	assert.True(t, res.Ok, string(res.Result))
+	ret := make(map[string]string)
+	assert.Nil(t, json.Unmarshal(res.Result, &ret))
+	assert.Equal(t, reporters, ret)
+}
+
 func TestNodeManager_UpdateNode(t *testing.T) {
 	nm, mockStub, vpNodes, _ := vpNodePrepare(t)
 	_, _, nvpNodes, _ := nvpNodePrepare(t)
@@ -0,0 +1,154 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/governance"
+	nodemgr "github.com/meshplus/bitxhub-core/node-mgr"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+)
+
+const TSS_FREEZE_PREFIX = "tss-freeze"
+
+// TssFreeze freezes the vp node out of the tss signing group, the node is still a validator.
+// Status is freezing while the freeze proposal is being voted, frozen once it is approved and
+// activating while the proposal to unfreeze the node is being voted.
+type TssFreeze struct {
+	Account  string                      `json:"account"`
+	VPNodeId uint64                      `json:"vp_node_id"`
+	Status   governance.GovernanceStatus `json:"status"`
+	Reason   string                      `json:"reason"`
+	Height   uint64                      `json:"height"`
+}
+
+func (f *TssFreeze) IsFrozen() bool {
+	return f.Status == governance.GovernanceFrozen || f.Status == governance.GovernanceActivating
+}
+
+// freezeTssNode submits a proposal to freeze the vp node out of the tss signing group
+func (nm *NodeManager) freezeTssNode(node *nodemgr.Node, reason string) *boltvm.Response {
+	freeze := TssFreeze{
+		Account:  node.Account,
+		VPNodeId: node.VPNodeId,
+		Status:   governance.GovernanceFreezing,
+		Reason:   reason,
+	}
+	extra, err := json.Marshal(freeze)
+	if err != nil {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf("marshal tss freeze error: %v", err))
+	}
+
+	res := nm.CrossInvoke(constant.GovernanceContractAddr.Address().String(), "SubmitProposal",
+		pb.String(nm.Caller()),
+		pb.String(string(governance.EventFreeze)),
+		pb.String(string(NodeMgr)),
+		pb.String(TssFreezeObjID(node.Account)),
+		pb.String(""), // no last status
+		pb.String(reason),
+		pb.Bytes(extra),
+	)
+	if !res.Ok {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf("submit proposal error: %s", string(res.Result)))
+	}
+	nm.SetObject(TssFreezeKey(node.Account), freeze)
+
+	return getGovernanceRet(string(res.Result), nil)
+}
+
+// UnfreezeTssNode submits a proposal to bring the vp node frozen by tss culprit reports back to the tss signing group
+func (nm *NodeManager) UnfreezeTssNode(nodeAccount, reason string) *boltvm.Response {
+	if err := nm.checkPermission([]string{string(PermissionAdmin)}, "", nm.CurrentCaller(), nil); err != nil {
+		return boltvm.Error(boltvm.NodeNoPermissionCode, fmt.Sprintf(string(boltvm.NodeNoPermissionMsg), nm.CurrentCaller(), fmt.Sprintf("check permission error:%v", err)))
+	}
+
+	freeze, ok := nm.getTssFreeze(nodeAccount)
+	if !ok || freeze.Status != governance.GovernanceFrozen {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf("node %s is not frozen out of tss", nodeAccount))
+	}
+	freeze.Status = governance.GovernanceActivating
+	extra, err := json.Marshal(freeze)
+	if err != nil {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf("marshal tss freeze error: %v", err))
+	}
+
+	res := nm.CrossInvoke(constant.GovernanceContractAddr.Address().String(), "SubmitProposal",
+		pb.String(nm.Caller()),
+		pb.String(string(governance.EventActivate)),
+		pb.String(string(NodeMgr)),
+		pb.String(TssFreezeObjID(nodeAccount)),
+		pb.String(string(governance.GovernanceFrozen)),
+		pb.String(reason),
+		pb.Bytes(extra),
+	)
+	if !res.Ok {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf("submit proposal error: %s", string(res.Result)))
+	}
+	nm.SetObject(TssFreezeKey(nodeAccount), *freeze)
+
+	return getGovernanceRet(string(res.Result), nil)
+}
+
+func (nm *NodeManager) manageTssFreeze(eventTyp, proposalResult string, extra []byte) *boltvm.Response {
+	freeze := &TssFreeze{}
+	if err := json.Unmarshal(extra, freeze); err != nil {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf("unmarshal tss freeze error: %v", err))
+	}
+
+	approved := proposalResult == string(APPROVED)
+	switch eventTyp {
+	case string(governance.EventFreeze):
+		if !approved {
+			nm.Delete(TssFreezeKey(freeze.Account))
+			return boltvm.Success(nil)
+		}
+		freeze.Status = governance.GovernanceFrozen
+		freeze.Height = nm.GetCurrentHeight()
+		nm.SetObject(TssFreezeKey(freeze.Account), *freeze)
+	case string(governance.EventActivate):
+		if approved {
+			nm.Delete(TssFreezeKey(freeze.Account))
+			return boltvm.Success(nil)
+		}
+		freeze.Status = governance.GovernanceFrozen
+		nm.SetObject(TssFreezeKey(freeze.Account), *freeze)
+	}
+
+	return boltvm.Success(nil)
+}
+
+// GetTssFreeze returns the tss freeze of the vp node
+func (nm *NodeManager) GetTssFreeze(nodeAccount string) *boltvm.Response {
+	freeze, ok := nm.getTssFreeze(nodeAccount)
+	if !ok {
+		return boltvm.Error(boltvm.NodeInternalErrCode, fmt.Sprintf("node %s is not frozen out of tss", nodeAccount))
+	}
+	data, err := json.Marshal(freeze)
+	if err != nil {
+		return boltvm.Error(boltvm.NodeInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+func (nm *NodeManager) getTssFreeze(nodeAccount string) (*TssFreeze, bool) {
+	freeze := &TssFreeze{}
+	if ok := nm.GetObject(TssFreezeKey(nodeAccount), freeze); !ok {
+		return nil, false
+	}
+	return freeze, true
+}
+
+func TssFreezeKey(nodeAccount string) string {
+	return fmt.Sprintf("%s-%s", TSS_FREEZE_PREFIX, nodeAccount)
+}
+
+func TssFreezeObjID(nodeAccount string) string {
+	return TssFreezeKey(nodeAccount)
+}
+
+func isTssFreezeObjID(objId string) bool {
+	return strings.HasPrefix(objId, TSS_FREEZE_PREFIX+"-")
+}
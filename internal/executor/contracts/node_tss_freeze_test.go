@@ -0,0 +1,106 @@
+package contracts
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/boltvm/mock_stub"
+	"github.com/meshplus/bitxhub-core/governance"
+	nodemgr "github.com/meshplus/bitxhub-core/node-mgr"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeManager_TssFreeze(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+	nm := &NodeManager{Stub: mockStub}
+
+	store := make(map[string][]byte)
+	mockStub.EXPECT().GetCurrentHeight().Return(uint64(10)).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).DoAndReturn(func(key string, ret interface{}) bool {
+		data, ok := store[key]
+		if !ok {
+			return false
+		}
+		assert.Nil(t, json.Unmarshal(data, ret))
+		return true
+	}).AnyTimes()
+	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).DoAndReturn(func(key string, value interface{}) {
+		data, err := json.Marshal(value)
+		assert.Nil(t, err)
+		store[key] = data
+	}).AnyTimes()
+	mockStub.EXPECT().Delete(gomock.Any()).DoAndReturn(func(key string) {
+		delete(store, key)
+	}).AnyTimes()
+	mockStub.EXPECT().Caller().Return(adminAddr).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte(FALSE))).Times(1)
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte(TRUE))).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.GovernanceContractAddr.Address().String(), "SubmitProposal",
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte("proposal-0"))).AnyTimes()
+
+	caller := adminAddr
+	mockStub.EXPECT().CurrentCaller().DoAndReturn(func() string { return caller }).AnyTimes()
+
+	node := &nodemgr.Node{Account: adminAddr, VPNodeId: 2}
+	res := nm.freezeTssNode(node, reason)
+	assert.True(t, res.Ok, string(res.Result))
+	res = nm.GetTssFreeze(node.Account)
+	assert.True(t, res.Ok, string(res.Result))
+	freeze := &TssFreeze{}
+	assert.Nil(t, json.Unmarshal(res.Result, freeze))
+	assert.Equal(t, governance.GovernanceFreezing, freeze.Status)
+	assert.False(t, freeze.IsFrozen())
+
+	// not governance admin
+	res = nm.UnfreezeTssNode(node.Account, reason)
+	assert.False(t, res.Ok, string(res.Result))
+	// freezing node can't be unfrozen
+	res = nm.UnfreezeTssNode(node.Account, reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// rejected proposal freezes nothing
+	caller = constant.GovernanceContractAddr.Address().String()
+	extra, err := json.Marshal(freeze)
+	assert.Nil(t, err)
+	res = nm.Manage(string(governance.EventFreeze), string(REJECTED), "", TssFreezeObjID(node.Account), extra)
+	assert.True(t, res.Ok, string(res.Result))
+	res = nm.GetTssFreeze(node.Account)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = nm.Manage(string(governance.EventFreeze), string(APPROVED), "", TssFreezeObjID(node.Account), extra)
+	assert.True(t, res.Ok, string(res.Result))
+	res = nm.GetTssFreeze(node.Account)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Nil(t, json.Unmarshal(res.Result, freeze))
+	assert.Equal(t, TssFreeze{Account: node.Account, VPNodeId: 2, Status: governance.GovernanceFrozen, Reason: reason, Height: 10}, *freeze)
+	assert.True(t, freeze.IsFrozen())
+
+	caller = adminAddr
+	res = nm.UnfreezeTssNode(node.Account, reason)
+	assert.True(t, res.Ok, string(res.Result))
+	res = nm.GetTssFreeze(node.Account)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Nil(t, json.Unmarshal(res.Result, freeze))
+	assert.Equal(t, governance.GovernanceActivating, freeze.Status)
+	assert.True(t, freeze.IsFrozen())
+
+	// rejected unfreeze keeps the node frozen
+	caller = constant.GovernanceContractAddr.Address().String()
+	extra, err = json.Marshal(freeze)
+	assert.Nil(t, err)
+	res = nm.Manage(string(governance.EventActivate), string(REJECTED), string(governance.GovernanceFrozen), TssFreezeObjID(node.Account), extra)
+	assert.True(t, res.Ok, string(res.Result))
+	res = nm.GetTssFreeze(node.Account)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Nil(t, json.Unmarshal(res.Result, freeze))
+	assert.Equal(t, governance.GovernanceFrozen, freeze.Status)
+
+	res = nm.Manage(string(governance.EventActivate), string(APPROVED), string(governance.GovernanceFrozen), TssFreezeObjID(node.Account), extra)
+	assert.True(t, res.Ok, string(res.Result))
+	res = nm.GetTssFreeze(node.Account)
+	assert.False(t, res.Ok, string(res.Result))
+}
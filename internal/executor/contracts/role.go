@@ -15,6 +15,7 @@ import (
 	"github.com/meshplus/bitxhub-kit/types"
 	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/model/events"
 	"github.com/meshplus/bitxhub/internal/repo"
 	"github.com/meshplus/eth-kit/ledger"
 	"github.com/sirupsen/logrus"
@@ -49,6 +50,9 @@ type Role struct {
 
 	// 	GovernanceAdmin info
 	Weight uint64 `json:"weight" toml:"weight"`
+	// MultiSig GovernanceAdmin info: the role only acts once Threshold of Members confirm
+	Members   []string `toml:"members" json:"members"`
+	Threshold uint64   `toml:"threshold" json:"threshold"`
 
 	// AuditAdmin info
 	NodeAccount string `toml:"node_account" json:"node_account"`
@@ -66,9 +70,10 @@ type RoleManager struct {
 
 var roleStateMap = map[governance.EventType][]governance.GovernanceStatus{
 	governance.EventRegister: {governance.GovernanceUnavailable},
+	governance.EventUpdate:   {governance.GovernanceAvailable},
 	governance.EventFreeze:   {governance.GovernanceAvailable},
 	governance.EventActivate: {governance.GovernanceFrozen},
-	governance.EventLogout:   {governance.GovernanceAvailable, governance.GovernanceFreezing, governance.GovernanceActivating, governance.GovernanceFrozen, governance.GovernanceBinding},
+	governance.EventLogout:   {governance.GovernanceAvailable, governance.GovernanceUpdating, governance.GovernanceFreezing, governance.GovernanceActivating, governance.GovernanceFrozen, governance.GovernanceBinding},
 	governance.EventBind:     {governance.GovernanceFrozen},
 	governance.EventPause:    {governance.GovernanceAvailable, governance.GovernanceRegisting, governance.GovernanceBinding},
 	governance.EventUnpause:  {governance.GovernanceRegisting, governance.GovernanceBinding},
@@ -76,6 +81,7 @@ var roleStateMap = map[governance.EventType][]governance.GovernanceStatus{
 
 var roleAvailableMap = map[governance.GovernanceStatus]struct{}{
 	governance.GovernanceAvailable: {},
+	governance.GovernanceUpdating:  {},
 	governance.GovernanceFreezing:  {},
 }
 
@@ -96,6 +102,11 @@ func (role *Role) setFSM(lastStatus governance.GovernanceStatus) {
 			{Name: string(governance.EventApprove), Src: []string{string(governance.GovernanceRegisting)}, Dst: string(governance.GovernanceAvailable)},
 			{Name: string(governance.EventReject), Src: []string{string(governance.GovernanceRegisting)}, Dst: string(lastStatus)},
 
+			// update 1
+			{Name: string(governance.EventUpdate), Src: []string{string(governance.GovernanceAvailable)}, Dst: string(governance.GovernanceUpdating)},
+			{Name: string(governance.EventApprove), Src: []string{string(governance.GovernanceUpdating)}, Dst: string(governance.GovernanceAvailable)},
+			{Name: string(governance.EventReject), Src: []string{string(governance.GovernanceUpdating)}, Dst: string(lastStatus)},
+
 			// freeze 2
 			{Name: string(governance.EventFreeze), Src: []string{string(governance.GovernanceAvailable), string(governance.GovernanceActivating), string(governance.GovernanceLogouting)}, Dst: string(governance.GovernanceFreezing)},
 			{Name: string(governance.EventApprove), Src: []string{string(governance.GovernanceFreezing)}, Dst: string(governance.GovernanceFrozen)},
@@ -107,7 +118,7 @@ func (role *Role) setFSM(lastStatus governance.GovernanceStatus) {
 			{Name: string(governance.EventReject), Src: []string{string(governance.GovernanceActivating)}, Dst: string(lastStatus)},
 
 			// logout 3
-			{Name: string(governance.EventLogout), Src: []string{string(governance.GovernanceAvailable), string(governance.GovernanceFreezing), string(governance.GovernanceFrozen), string(governance.GovernanceActivating), string(governance.GovernanceBinding)}, Dst: string(governance.GovernanceLogouting)},
+			{Name: string(governance.EventLogout), Src: []string{string(governance.GovernanceAvailable), string(governance.GovernanceUpdating), string(governance.GovernanceFreezing), string(governance.GovernanceFrozen), string(governance.GovernanceActivating), string(governance.GovernanceBinding)}, Dst: string(governance.GovernanceLogouting)},
 			{Name: string(governance.EventApprove), Src: []string{string(governance.GovernanceLogouting)}, Dst: string(governance.GovernanceForbidden)},
 			{Name: string(governance.EventReject), Src: []string{string(governance.GovernanceLogouting)}, Dst: string(lastStatus)},
 
@@ -230,7 +241,7 @@ func (rm *RoleManager) register(roleInfo *Role) error {
 
 // Manage does some subsequent operations when the proposal is over
 // extra: update - role info
-func (rm *RoleManager) Manage(eventTyp, proposalResult, lastStatus, objId string, _ []byte) *boltvm.Response {
+func (rm *RoleManager) Manage(eventTyp, proposalResult, lastStatus, objId string, extra []byte) *boltvm.Response {
 	// 1. check permission: PermissionSpecific(GovernanceContractAddr)
 	specificAddrs := []string{constant.GovernanceContractAddr.Address().String()}
 	addrsData, err := json.Marshal(specificAddrs)
@@ -259,7 +270,7 @@ func (rm *RoleManager) Manage(eventTyp, proposalResult, lastStatus, objId string
 	}
 	switch role.RoleType {
 	case GovernanceAdmin:
-		if bvmErr = rm.handleGovernanceAdmin(eventTyp, proposalResult, objId, role); !bvmErr.Ok {
+		if bvmErr = rm.handleGovernanceAdmin(eventTyp, proposalResult, objId, role, extra); !bvmErr.Ok {
 			return bvmErr
 		}
 	case AuditAdmin:
@@ -277,8 +288,14 @@ func (rm *RoleManager) Manage(eventTyp, proposalResult, lastStatus, objId string
 	return boltvm.Success(nil)
 }
 
-func (rm *RoleManager) handleGovernanceAdmin(eventTyp, proposalResult, objId string, role *Role) *boltvm.Response {
+func (rm *RoleManager) handleGovernanceAdmin(eventTyp, proposalResult, objId string, role *Role, extra []byte) *boltvm.Response {
 	switch eventTyp {
+	case string(governance.EventUpdate):
+		if proposalResult == string(APPROVED) {
+			if err := rm.updateMultiSigMembers(role, extra); err != nil {
+				return boltvm.Error(boltvm.RoleInternalErrCode, fmt.Sprintf("update multisig members error: %v", err))
+			}
+		}
 	case string(governance.EventRegister):
 		if proposalResult == string(APPROVED) {
 			if err := rm.register(role); err != nil {
@@ -440,8 +457,6 @@ func (rm *RoleManager) updateStrategyInfo() *boltvm.BxhError {
 
 // RegisterRole registers role info, returns proposal id and error
 func (rm *RoleManager) RegisterRole(roleId, roleType, nodeAccount, reason string) *boltvm.Response {
-	event := string(governance.EventRegister)
-
 	// 1. check permission
 	if err := rm.checkPermission([]string{string(PermissionAdmin)}, roleId, rm.CurrentCaller(), nil); err != nil {
 		return boltvm.Error(boltvm.RoleNoPermissionCode, fmt.Sprintf(string(boltvm.RoleNoPermissionMsg), rm.CurrentCaller(), fmt.Sprintf("check permission error:%v", err)))
@@ -459,8 +474,14 @@ func (rm *RoleManager) RegisterRole(roleId, roleType, nodeAccount, reason string
 		return res
 	}
 
+	return rm.registerRole(role, reason)
+}
+
+func (rm *RoleManager) registerRole(role *Role, reason string) *boltvm.Response {
+	event := string(governance.EventRegister)
+
 	// 3. check status
-	if _, bxhErr := rm.governancePre(roleId, governance.EventType(event)); bxhErr != nil {
+	if _, bxhErr := rm.governancePre(role.ID, governance.EventType(event)); bxhErr != nil {
 		return boltvm.Error(bxhErr.Code, string(bxhErr.Msg))
 	}
 
@@ -487,8 +508,8 @@ func (rm *RoleManager) RegisterRole(roleId, roleType, nodeAccount, reason string
 	}
 
 	// 7. node bind
-	if AuditAdmin == RoleType(roleType) {
-		if res := rm.CrossInvoke(constant.NodeManagerContractAddr.Address().String(), "BindNode", pb.String(nodeAccount), pb.String(role.ID)); !res.Ok {
+	if AuditAdmin == role.RoleType {
+		if res := rm.CrossInvoke(constant.NodeManagerContractAddr.Address().String(), "BindNode", pb.String(role.NodeAccount), pb.String(role.ID)); !res.Ok {
 			return res
 		}
 	}
@@ -497,7 +518,7 @@ func (rm *RoleManager) RegisterRole(roleId, roleType, nodeAccount, reason string
 	rm.CrossInvoke(constant.GovernanceContractAddr.Address().String(), "ZeroPermission", pb.String(string(res.Result)))
 
 	if rm.EnableAudit() {
-		if err := rm.postAuditRoleEvent(roleId); err != nil {
+		if err := rm.postAuditRoleEvent(role.ID); err != nil {
 			return boltvm.Error(boltvm.RoleInternalErrCode, fmt.Sprintf("post audit role event error: %v", err))
 		}
 	}
@@ -706,7 +727,7 @@ func (rm *RoleManager) basicGovernance(roleId, reason string, permissions []stri
 	case AppchainAdmin:
 		return boltvm.Error(boltvm.RoleNonsupportAppchainAdminCode, fmt.Sprintf(string(boltvm.RoleNonsupportAppchainAdminMsg), roleId, event))
 	case AuditAdmin:
-		if event == governance.EventFreeze || event == governance.EventActivate {
+		if event == governance.EventFreeze || event == governance.EventActivate || event == governance.EventUpdate {
 			return boltvm.Error(boltvm.RoleNonsupportAuditAdminCode, fmt.Sprintf(string(boltvm.RoleNonsupportAuditAdminMsg), roleId, event))
 		}
 		// The original audit node must have been logoutted when we initiate the audit node binding for the audit administrator.
@@ -719,6 +740,9 @@ func (rm *RoleManager) basicGovernance(roleId, reason string, permissions []stri
 		if event == governance.EventBind {
 			return boltvm.Error(boltvm.RoleNonsupportGovernanceAdminCode, fmt.Sprintf(string(boltvm.RoleNonsupportGovernanceAdminMsg), roleId, event))
 		}
+		if event == governance.EventUpdate && !role.IsMultiSig() {
+			return boltvm.Error(boltvm.RoleNonsupportGovernanceAdminCode, fmt.Sprintf(string(boltvm.RoleNonsupportGovernanceAdminMsg), roleId, event))
+		}
 		if role.Weight == repo.SuperAdminWeight {
 			return boltvm.Error(boltvm.RoleNonsupportSuperAdminCode, fmt.Sprintf(string(boltvm.RoleNonsupportSuperAdminMsg), roleId, event))
 		}
@@ -808,6 +832,7 @@ func (rm *RoleManager) pauseAuditAdmin(roleId string) *boltvm.Response {
 	}
 
 	// 4. post event
+	rm.postPauseNodeEvent(role.NodeAccount)
 	if rm.EnableAudit() {
 		if err := rm.postAuditRoleEvent(roleId); err != nil {
 			return boltvm.Error(boltvm.RoleInternalErrCode, fmt.Sprintf("post audit role event error: %v", err))
@@ -817,6 +842,18 @@ func (rm *RoleManager) pauseAuditAdmin(roleId string) *boltvm.Response {
 	return getGovernanceRet("", nil)
 }
 
+// postPauseNodeEvent notifies that the binding of the audit node is paused
+func (rm *RoleManager) postPauseNodeEvent(nodeAccount string) {
+	if nodeAccount == "" {
+		return
+	}
+	rm.PostEvent(pb.Event_NODEMGR, &events.NodeEvent{
+		NodeAccount:   nodeAccount,
+		NodeType:      nodemgr.NVPNode,
+		NodeEventType: governance.EventPause,
+	})
+}
+
 // PauseAuditAdminBinding pause audit admin binding proposal when the audit node is logouting
 func (rm *RoleManager) PauseAuditAdminBinding(nodeId string) *boltvm.Response {
 	// 1. check permission: PermissionSpecific (NodeManagerContractAddr)
@@ -842,6 +879,7 @@ func (rm *RoleManager) PauseAuditAdminBinding(nodeId string) *boltvm.Response {
 		return boltvm.Error(boltvm.RoleInternalErrCode, fmt.Sprintf("cross invoke LockLowPriorityProposal error: %s", string(res.Result)))
 	}
 
+	rm.postPauseNodeEvent(nodeId)
 	if rm.EnableAudit() {
 		if err := rm.postAuditRoleEvent(role.ID); err != nil {
 			return boltvm.Error(boltvm.RoleInternalErrCode, fmt.Sprintf("post audit role event error: %v", err))
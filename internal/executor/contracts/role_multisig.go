@@ -0,0 +1,328 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/governance"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	RoleMultiSigTxPrefix      = "multisig-tx"
+	RoleMultiSigTxCountPrefix = "multisig-count"
+)
+
+// MultiSigMembers is the member set of a multisig governance admin
+type MultiSigMembers struct {
+	Members   []string `json:"members"`
+	Threshold uint64   `json:"threshold"`
+}
+
+// MultiSigTx is a bolt contract call submitted on behalf of a multisig governance admin.
+// It is dispatched with the multisig account as caller once Threshold members have confirmed it.
+type MultiSigTx struct {
+	ID            string    `json:"id"`
+	RoleID        string    `json:"role_id"`
+	To            string    `json:"to"`
+	Method        string    `json:"method"`
+	Args          []*pb.Arg `json:"args"`
+	Confirmations []string  `json:"confirmations"`
+	Executed      bool      `json:"executed"`
+	Result        []byte    `json:"result"`
+	CreateTime    int64     `json:"create_time"`
+}
+
+// multiSigInvoker is implemented by stubs which are able to invoke a contract on behalf of another account
+type multiSigInvoker interface {
+	CrossInvokeAs(caller, address, method string, args ...*pb.Arg) *boltvm.Response
+}
+
+func (role *Role) IsMultiSig() bool {
+	return len(role.Members) != 0
+}
+
+func (role *Role) isMember(addr string) bool {
+	for _, m := range role.Members {
+		if m == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// MultiSigAddress derives the account of a multisig admin from its initial members and threshold.
+// Nobody holds the private key of the derived account, so it can only act through confirmed multisig txs.
+func MultiSigAddress(members []string, threshold uint64) string {
+	sorted := make([]string, len(members))
+	copy(sorted, members)
+	sort.Strings(sorted)
+
+	data := []byte(fmt.Sprintf("%s-%d", strings.Join(sorted, ","), threshold))
+	return types.NewAddress(crypto.Keccak256(data)[12:]).String()
+}
+
+func parseMultiSigMembers(members string, threshold uint64) (*MultiSigMembers, error) {
+	ret := &MultiSigMembers{Threshold: threshold}
+	exist := make(map[string]struct{})
+	for _, m := range strings.Split(members, ",") {
+		m = strings.TrimSpace(m)
+		if m == "" {
+			continue
+		}
+		if _, err := types.HexDecodeString(m); err != nil {
+			return nil, fmt.Errorf("illegal member %s: %v", m, err)
+		}
+		if _, ok := exist[m]; ok {
+			return nil, fmt.Errorf("duplicate member %s", m)
+		}
+		exist[m] = struct{}{}
+		ret.Members = append(ret.Members, m)
+	}
+
+	if len(ret.Members) == 0 {
+		return nil, fmt.Errorf("multisig members can not be empty")
+	}
+	if threshold == 0 || threshold > uint64(len(ret.Members)) {
+		return nil, fmt.Errorf("illegal threshold %d for %d members", threshold, len(ret.Members))
+	}
+
+	return ret, nil
+}
+
+// RegisterMultiSigRole registers a governance admin backed by an M-of-N multisig account.
+// members is a comma-separated list of member addresses and threshold is M.
+func (rm *RoleManager) RegisterMultiSigRole(members string, threshold uint64, reason string) *boltvm.Response {
+	// 1. check permission
+	if err := rm.checkPermission([]string{string(PermissionAdmin)}, "", rm.CurrentCaller(), nil); err != nil {
+		return boltvm.Error(boltvm.RoleNoPermissionCode, fmt.Sprintf(string(boltvm.RoleNoPermissionMsg), rm.CurrentCaller(), fmt.Sprintf("check permission error:%v", err)))
+	}
+
+	// 2. check info
+	ms, err := parseMultiSigMembers(members, threshold)
+	if err != nil {
+		return boltvm.Error(boltvm.RoleIllegalRoleIDCode, fmt.Sprintf(string(boltvm.RoleIllegalRoleIDMsg), members, err.Error()))
+	}
+	role := &Role{
+		ID:        MultiSigAddress(ms.Members, ms.Threshold),
+		RoleType:  GovernanceAdmin,
+		Weight:    repo.NormalAdminWeight,
+		Members:   ms.Members,
+		Threshold: ms.Threshold,
+		Status:    governance.GovernanceUnavailable,
+	}
+	if res := rm.checkRoleInfo(role); !res.Ok {
+		return res
+	}
+
+	return rm.registerRole(role, reason)
+}
+
+// UpdateMultiSigMembers rotates the members and threshold of a multisig governance admin through governance.
+// The account of the role stays the same.
+func (rm *RoleManager) UpdateMultiSigMembers(roleId, members string, threshold uint64, reason string) *boltvm.Response {
+	ms, err := parseMultiSigMembers(members, threshold)
+	if err != nil {
+		return boltvm.Error(boltvm.RoleIllegalRoleIDCode, fmt.Sprintf(string(boltvm.RoleIllegalRoleIDMsg), members, err.Error()))
+	}
+	extra, err := json.Marshal(ms)
+	if err != nil {
+		return boltvm.Error(boltvm.RoleInternalErrCode, err.Error())
+	}
+
+	res := rm.basicGovernance(roleId, reason, []string{string(PermissionAdmin), string(PermissionSelf)}, governance.EventUpdate, extra)
+	if !res.Ok {
+		return res
+	}
+	var gr *governance.GovernanceResult
+	if err := json.Unmarshal(res.Result, &gr); err != nil {
+		return boltvm.Error(boltvm.RoleInternalErrCode, err.Error())
+	}
+
+	rm.CrossInvoke(constant.GovernanceContractAddr.Address().String(), "ZeroPermission", pb.String(gr.ProposalID))
+
+	return res
+}
+
+func (rm *RoleManager) updateMultiSigMembers(role *Role, extra []byte) error {
+	ms := &MultiSigMembers{}
+	if err := json.Unmarshal(extra, ms); err != nil {
+		return fmt.Errorf("unmarshal multisig members error: %v", err)
+	}
+
+	role.Members = ms.Members
+	role.Threshold = ms.Threshold
+	rm.SetObject(RoleKey(role.ID), *role)
+
+	rm.Logger().WithFields(logrus.Fields{
+		"id":        role.ID,
+		"members":   role.Members,
+		"threshold": role.Threshold,
+	}).Info("Multisig members are updated")
+	return nil
+}
+
+// SubmitMultiSigTx submits a bolt contract call on behalf of a multisig governance admin.
+// The caller must be a member of the role and its confirmation is recorded at once.
+// args is the json encoding of the invoke args.
+func (rm *RoleManager) SubmitMultiSigTx(roleId, to, method string, args []byte) *boltvm.Response {
+	role, res := rm.getMultiSigRole(roleId)
+	if !res.Ok {
+		return res
+	}
+	caller := rm.Caller()
+	if !role.isMember(caller) {
+		return boltvm.Error(boltvm.RoleNoPermissionCode, fmt.Sprintf(string(boltvm.RoleNoPermissionMsg), caller, fmt.Sprintf("not a member of multisig role %s", roleId)))
+	}
+
+	var invokeArgs []*pb.Arg
+	if len(args) != 0 {
+		if err := json.Unmarshal(args, &invokeArgs); err != nil {
+			return boltvm.Error(boltvm.RoleInternalErrCode, fmt.Sprintf("unmarshal invoke args error: %v", err))
+		}
+	}
+
+	var count uint64
+	_ = rm.GetObject(MultiSigTxCountKey(roleId), &count)
+	tx := &MultiSigTx{
+		ID:            fmt.Sprintf("%s-%d", roleId, count),
+		RoleID:        roleId,
+		To:            to,
+		Method:        method,
+		Args:          invokeArgs,
+		Confirmations: []string{caller},
+		CreateTime:    rm.GetTxTimeStamp(),
+	}
+	rm.SetObject(MultiSigTxCountKey(roleId), count+1)
+
+	return rm.tryExecuteMultiSigTx(role, tx)
+}
+
+// ConfirmMultiSigTx confirms a pending multisig tx, the tx is executed once enough members have confirmed it
+func (rm *RoleManager) ConfirmMultiSigTx(txId string) *boltvm.Response {
+	tx := &MultiSigTx{}
+	if ok := rm.GetObject(MultiSigTxKey(txId), tx); !ok {
+		return boltvm.Error(boltvm.RoleInternalErrCode, fmt.Sprintf("the multisig tx(%s) does not exist", txId))
+	}
+	if tx.Executed {
+		return boltvm.Error(boltvm.RoleInternalErrCode, fmt.Sprintf("the multisig tx(%s) has been executed", txId))
+	}
+
+	role, res := rm.getMultiSigRole(tx.RoleID)
+	if !res.Ok {
+		return res
+	}
+	caller := rm.Caller()
+	if !role.isMember(caller) {
+		return boltvm.Error(boltvm.RoleNoPermissionCode, fmt.Sprintf(string(boltvm.RoleNoPermissionMsg), caller, fmt.Sprintf("not a member of multisig role %s", tx.RoleID)))
+	}
+	for _, c := range tx.Confirmations {
+		if c == caller {
+			return boltvm.Error(boltvm.RoleInternalErrCode, fmt.Sprintf("%s has confirmed the multisig tx(%s)", c, txId))
+		}
+	}
+	tx.Confirmations = append(tx.Confirmations, caller)
+
+	return rm.tryExecuteMultiSigTx(role, tx)
+}
+
+// tryExecuteMultiSigTx dispatches the tx if the confirmations of current members reach the threshold.
+// Confirmations of members which have been rotated out are not counted.
+func (rm *RoleManager) tryExecuteMultiSigTx(role *Role, tx *MultiSigTx) *boltvm.Response {
+	var confirmed uint64
+	for _, c := range tx.Confirmations {
+		if role.isMember(c) {
+			confirmed++
+		}
+	}
+
+	if confirmed >= role.Threshold {
+		invoker, ok := rm.Stub.(multiSigInvoker)
+		if !ok {
+			return boltvm.Error(boltvm.RoleInternalErrCode, "the stub does not support multisig invoke")
+		}
+		res := invoker.CrossInvokeAs(role.ID, tx.To, tx.Method, tx.Args...)
+		if !res.Ok {
+			return boltvm.Error(boltvm.RoleInternalErrCode, fmt.Sprintf("execute multisig tx(%s) error: %s", tx.ID, string(res.Result)))
+		}
+		tx.Executed = true
+		tx.Result = res.Result
+
+		rm.Logger().WithFields(logrus.Fields{
+			"id":     tx.ID,
+			"to":     tx.To,
+			"method": tx.Method,
+		}).Info("Multisig tx is executed")
+	}
+	rm.SetObject(MultiSigTxKey(tx.ID), *tx)
+
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return boltvm.Error(boltvm.RoleInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+func (rm *RoleManager) getMultiSigRole(roleId string) (*Role, *boltvm.Response) {
+	role := &Role{}
+	if ok := rm.GetObject(RoleKey(roleId), role); !ok {
+		return nil, boltvm.Error(boltvm.RoleNonexistentRoleCode, fmt.Sprintf(string(boltvm.RoleNonexistentRoleMsg), roleId))
+	}
+	if !role.IsMultiSig() {
+		return nil, boltvm.Error(boltvm.RoleIllegalRoleTypeCode, fmt.Sprintf(string(boltvm.RoleIllegalRoleTypeMsg), "not multisig"))
+	}
+	if !role.IsAvailable() {
+		return nil, boltvm.Error(boltvm.RoleStatusErrorCode, fmt.Sprintf(string(boltvm.RoleStatusErrorMsg), roleId, string(role.Status), "multisig tx"))
+	}
+	return role, boltvm.Success(nil)
+}
+
+// GetMultiSigTx query a multisig tx by id
+func (rm *RoleManager) GetMultiSigTx(txId string) *boltvm.Response {
+	tx := &MultiSigTx{}
+	if ok := rm.GetObject(MultiSigTxKey(txId), tx); !ok {
+		return boltvm.Error(boltvm.RoleInternalErrCode, fmt.Sprintf("the multisig tx(%s) does not exist", txId))
+	}
+
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return boltvm.Error(boltvm.RoleInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+// GetMultiSigTxsByRole query all multisig txs of a multisig role
+func (rm *RoleManager) GetMultiSigTxsByRole(roleId string) *boltvm.Response {
+	var count uint64
+	_ = rm.GetObject(MultiSigTxCountKey(roleId), &count)
+
+	ret := make([]*MultiSigTx, 0, count)
+	for i := uint64(0); i < count; i++ {
+		tx := &MultiSigTx{}
+		if ok := rm.GetObject(MultiSigTxKey(fmt.Sprintf("%s-%d", roleId, i)), tx); ok {
+			ret = append(ret, tx)
+		}
+	}
+
+	data, err := json.Marshal(ret)
+	if err != nil {
+		return boltvm.Error(boltvm.RoleInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+func MultiSigTxKey(id string) string {
+	return fmt.Sprintf("%s-%s", RoleMultiSigTxPrefix, id)
+}
+
+func MultiSigTxCountKey(roleId string) string {
+	return fmt.Sprintf("%s-%s", RoleMultiSigTxCountPrefix, roleId)
+}
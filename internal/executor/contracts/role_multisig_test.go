@@ -0,0 +1,196 @@
+package contracts
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/boltvm/mock_stub"
+	"github.com/meshplus/bitxhub-core/governance"
+	"github.com/meshplus/bitxhub-kit/log"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+var multiSigMembers = []string{
+	"0xc7F999b83Af6DF9e67d0a37Ee7e900bF38b3D020",
+	"0xc7F999b83Af6DF9e67d0a37Ee7e900bF38b3D021",
+	"0xc7F999b83Af6DF9e67d0a37Ee7e900bF38b3D022",
+}
+
+type multiSigMockStub struct {
+	*mock_stub.MockStub
+	invokedBy []string
+}
+
+func (s *multiSigMockStub) CrossInvokeAs(caller, address, method string, args ...*pb.Arg) *boltvm.Response {
+	s.invokedBy = append(s.invokedBy, caller)
+	return boltvm.Success(nil)
+}
+
+func multiSigRolePrepare(t *testing.T) (*RoleManager, *multiSigMockStub, *Role) {
+	mockCtl := gomock.NewController(t)
+	stub := &multiSigMockStub{MockStub: mock_stub.NewMockStub(mockCtl)}
+	rm := &RoleManager{Stub: stub}
+
+	role := &Role{
+		ID:        MultiSigAddress(multiSigMembers, 2),
+		RoleType:  GovernanceAdmin,
+		Weight:    repo.NormalAdminWeight,
+		Members:   multiSigMembers,
+		Threshold: 2,
+		Status:    governance.GovernanceAvailable,
+	}
+	stub.EXPECT().Logger().Return(log.NewWithModule("contracts")).AnyTimes()
+	stub.EXPECT().GetTxTimeStamp().Return(int64(0)).AnyTimes()
+	stub.EXPECT().SetObject(gomock.Any(), gomock.Any()).AnyTimes()
+	stub.EXPECT().GetObject(RoleKey(role.ID), gomock.Any()).SetArg(1, *role).Return(true).AnyTimes()
+
+	return rm, stub, role
+}
+
+func TestMultiSigAddress(t *testing.T) {
+	addr := MultiSigAddress(multiSigMembers, 2)
+	reversed := []string{multiSigMembers[2], multiSigMembers[1], multiSigMembers[0]}
+	assert.Equal(t, addr, MultiSigAddress(reversed, 2))
+	assert.NotEqual(t, addr, MultiSigAddress(multiSigMembers, 3))
+
+	_, err := parseMultiSigMembers(strings.Join(multiSigMembers, ","), 4)
+	assert.NotNil(t, err)
+	_, err = parseMultiSigMembers(multiSigMembers[0]+","+multiSigMembers[0], 1)
+	assert.NotNil(t, err)
+	ms, err := parseMultiSigMembers(strings.Join(multiSigMembers, ","), 2)
+	assert.Nil(t, err)
+	assert.Equal(t, multiSigMembers, ms.Members)
+}
+
+func TestRoleManager_RegisterMultiSigRole(t *testing.T) {
+	rm, mockStub, gRoles, gRolesData, _, _ := rolePrepare(t)
+
+	mockStub.EXPECT().CurrentCaller().Return(noAdminAddr).Times(1)
+	mockStub.EXPECT().CurrentCaller().Return(gRoles[3].ID).AnyTimes()
+	mockStub.EXPECT().Caller().Return(gRoles[3].ID).AnyTimes()
+	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(gomock.Eq(constant.GovernanceContractAddr.Address().String()), gomock.Eq("SubmitProposal"),
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(boltvm.Success(nil)).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(gomock.Eq(constant.GovernanceContractAddr.Address().String()), gomock.Eq("ZeroPermission"),
+		gomock.Any()).Return(boltvm.Success(nil)).AnyTimes()
+	mockStub.EXPECT().Logger().Return(log.NewWithModule("contracts")).AnyTimes()
+	mockStub.EXPECT().PostEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockStub.EXPECT().EnableAudit().Return(true).AnyTimes()
+	mockStub.EXPECT().Get(gomock.Any()).Return(true, gRolesData[0]).AnyTimes()
+	mockStub.EXPECT().GetObject(RoleKey(gRoles[3].ID), gomock.Any()).SetArg(1, *gRoles[3]).Return(true).AnyTimes()
+	multiSigRole := Role{
+		ID:        MultiSigAddress(multiSigMembers, 2),
+		RoleType:  GovernanceAdmin,
+		Members:   multiSigMembers,
+		Threshold: 2,
+		Status:    governance.GovernanceUnavailable,
+	}
+	mockStub.EXPECT().GetObject(RoleKey(multiSigRole.ID), gomock.Any()).Return(false).Times(1)
+	mockStub.EXPECT().GetObject(RoleKey(multiSigRole.ID), gomock.Any()).SetArg(1, multiSigRole).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+
+	members := strings.Join(multiSigMembers, ",")
+
+	// check permission error
+	res := rm.RegisterMultiSigRole(members, 2, reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// illegal threshold
+	res = rm.RegisterMultiSigRole(members, 0, reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// ok
+	res = rm.RegisterMultiSigRole(members, 2, reason)
+	assert.True(t, res.Ok, string(res.Result))
+	gr := &governance.GovernanceResult{}
+	err := json.Unmarshal(res.Result, gr)
+	assert.Nil(t, err)
+	assert.Equal(t, MultiSigAddress(multiSigMembers, 2), string(gr.Extra))
+}
+
+func TestRoleManager_UpdateMultiSigMembers(t *testing.T) {
+	rm, mockStub, role := multiSigRolePrepare(t)
+
+	mockStub.EXPECT().CurrentCaller().Return(role.ID).AnyTimes()
+	mockStub.EXPECT().Caller().Return(role.ID).AnyTimes()
+	mockStub.EXPECT().EnableAudit().Return(false).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(gomock.Eq(constant.GovernanceContractAddr.Address().String()), gomock.Eq("SubmitProposal"),
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte("proposal-id"))).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(gomock.Eq(constant.GovernanceContractAddr.Address().String()), gomock.Eq("ZeroPermission"),
+		gomock.Any()).Return(boltvm.Success(nil)).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte(TRUE))).AnyTimes()
+
+	// illegal members
+	res := rm.UpdateMultiSigMembers(role.ID, "", 1, reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// ok
+	res = rm.UpdateMultiSigMembers(role.ID, strings.Join(multiSigMembers[:2], ","), 1, reason)
+	assert.True(t, res.Ok, string(res.Result))
+
+	// apply
+	ms := &MultiSigMembers{Members: multiSigMembers[:2], Threshold: 1}
+	extra, err := json.Marshal(ms)
+	assert.Nil(t, err)
+	err = rm.updateMultiSigMembers(role, extra)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), role.Threshold)
+	assert.Equal(t, 2, len(role.Members))
+}
+
+func TestRoleManager_MultiSigTx(t *testing.T) {
+	rm, stub, role := multiSigRolePrepare(t)
+
+	args, err := json.Marshal([]*pb.Arg{pb.String("proposal-id"), pb.String(BallotApprove), pb.String(reason)})
+	assert.Nil(t, err)
+	pendingTx := MultiSigTx{
+		ID:            role.ID + "-0",
+		RoleID:        role.ID,
+		To:            constant.GovernanceContractAddr.Address().String(),
+		Method:        "Vote",
+		Confirmations: []string{multiSigMembers[0]},
+	}
+	stub.EXPECT().GetObject(MultiSigTxCountKey(role.ID), gomock.Any()).Return(false).AnyTimes()
+	stub.EXPECT().GetObject(MultiSigTxKey(pendingTx.ID), gomock.Any()).SetArg(1, pendingTx).Return(true).AnyTimes()
+	stub.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+
+	// not member
+	stub.EXPECT().Caller().Return(noAdminAddr).Times(1)
+	res := rm.SubmitMultiSigTx(role.ID, pendingTx.To, pendingTx.Method, args)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// submitted but not executed
+	stub.EXPECT().Caller().Return(multiSigMembers[0]).Times(1)
+	res = rm.SubmitMultiSigTx(role.ID, pendingTx.To, pendingTx.Method, args)
+	assert.True(t, res.Ok, string(res.Result))
+	tx := &MultiSigTx{}
+	assert.Nil(t, json.Unmarshal(res.Result, tx))
+	assert.False(t, tx.Executed)
+	assert.Equal(t, 0, len(stub.invokedBy))
+
+	// repeat confirmation
+	stub.EXPECT().Caller().Return(multiSigMembers[0]).AnyTimes()
+	res = rm.ConfirmMultiSigTx(pendingTx.ID)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// executed on behalf of the multisig role
+	rm2, stub2, _ := multiSigRolePrepare(t)
+	stub2.EXPECT().GetObject(MultiSigTxKey(pendingTx.ID), gomock.Any()).SetArg(1, pendingTx).Return(true).AnyTimes()
+	stub2.EXPECT().Caller().Return(multiSigMembers[1]).AnyTimes()
+	res = rm2.ConfirmMultiSigTx(pendingTx.ID)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Nil(t, json.Unmarshal(res.Result, tx))
+	assert.True(t, tx.Executed)
+	assert.Equal(t, []string{role.ID}, stub2.invokedBy)
+
+	// nonexistent tx
+	stub2.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	res = rm2.ConfirmMultiSigTx("nonexistent")
+	assert.False(t, res.Ok, string(res.Result))
+}
@@ -0,0 +1,180 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/governance"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/pkg/vm/wasm"
+	"github.com/meshplus/eth-kit/ledger"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	CODE_HISTORY_PREFIX = "code-history"
+	CODE_UPGRADE_PREFIX = "code-upgrade"
+)
+
+// CodeRecord is a version of the wasm contract code
+type CodeRecord struct {
+	Version   uint64 `json:"version"`
+	CodeHash  string `json:"code_hash"`
+	Operator  string `json:"operator"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// ValidatorEvictor is implemented by the validation engine which caches the validators instantiated
+// from the code of rules, the validators of an upgraded rule are evicted so that the new code is run
+type ValidatorEvictor interface {
+	Evict(address string)
+}
+
+// CodeUpgrade is the new code of the wasm contract waiting for governance
+type CodeUpgrade struct {
+	Address string `json:"address"`
+	Code    []byte `json:"code"`
+}
+
+// UpgradeCode replaces the code of wasm contract in place, so rules bound by appchains keep the same address.
+// The owner of contract upgrades it directly, while governance admins submit a proposal
+func (rm *RuleManager) UpgradeCode(contractAddr string, code []byte, reason string) *boltvm.Response {
+	contract, err := rm.getWasmContract(contractAddr)
+	if err != nil {
+		return boltvm.Error(boltvm.RuleNonexistentRuleCode, fmt.Sprintf(string(boltvm.RuleNonexistentRuleMsg), contractAddr))
+	}
+	if err := wasm.ValidateCode(code); err != nil {
+		return boltvm.Error(boltvm.RuleInternalErrCode, fmt.Sprintf("invalid wasm code: %v", err))
+	}
+	if wasm.CodeHash(code).String() == contract.Hash.String() {
+		return boltvm.Error(boltvm.RuleInternalErrCode, "the code is the same as the current one")
+	}
+
+	caller := rm.Caller()
+	if contract.Owner != "" && contract.Owner == caller {
+		rm.upgradeCode(contractAddr, contract, code, caller)
+		return getGovernanceRet("", nil)
+	}
+
+	if err := rm.checkPermission([]string{string(PermissionAdmin)}, "", caller, nil); err != nil {
+		return boltvm.Error(boltvm.RuleNoPermissionCode, fmt.Sprintf(string(boltvm.RuleNoPermissionMsg), caller, err.Error()))
+	}
+
+	extra, err := json.Marshal(CodeUpgrade{Address: contractAddr, Code: code})
+	if err != nil {
+		return boltvm.Error(boltvm.RuleInternalErrCode, fmt.Sprintf("marshal code upgrade error: %v", err))
+	}
+	res := rm.CrossInvoke(constant.GovernanceContractAddr.Address().String(), "SubmitProposal",
+		pb.String(caller),
+		pb.String(string(governance.EventUpdate)),
+		pb.String(string(RuleMgr)),
+		pb.String(CodeUpgradeObjID(contractAddr)),
+		pb.String(""), // no last status
+		pb.String(reason),
+		pb.Bytes(extra),
+	)
+	if !res.Ok {
+		return boltvm.Error(boltvm.RuleInternalErrCode, fmt.Sprintf("cross invoke SubmitProposal error: %s", string(res.Result)))
+	}
+
+	rm.CrossInvoke(constant.GovernanceContractAddr.Address().String(), "ZeroPermission", pb.String(string(res.Result)))
+
+	return getGovernanceRet(string(res.Result), nil)
+}
+
+func (rm *RuleManager) manageCodeUpgrade(proposalResult string, extra []byte) *boltvm.Response {
+	if proposalResult != string(APPROVED) {
+		return boltvm.Success(nil)
+	}
+
+	upgrade := &CodeUpgrade{}
+	if err := json.Unmarshal(extra, upgrade); err != nil {
+		return boltvm.Error(boltvm.RuleInternalErrCode, fmt.Sprintf("unmarshal code upgrade error: %v", err))
+	}
+	contract, err := rm.getWasmContract(upgrade.Address)
+	if err != nil {
+		return boltvm.Error(boltvm.RuleInternalErrCode, err.Error())
+	}
+	rm.upgradeCode(upgrade.Address, contract, upgrade.Code, constant.GovernanceContractAddr.Address().String())
+
+	return boltvm.Success(nil)
+}
+
+// GetCodeHistory returns all code versions of the wasm contract which has been upgraded
+func (rm *RuleManager) GetCodeHistory(contractAddr string) *boltvm.Response {
+	history := make([]*CodeRecord, 0)
+	_ = rm.GetObject(CodeHistoryKey(contractAddr), &history)
+
+	data, err := json.Marshal(history)
+	if err != nil {
+		return boltvm.Error(boltvm.RuleInternalErrCode, err.Error())
+	}
+	return boltvm.Success(data)
+}
+
+// upgradeCode replaces the code and marks the contract to run its migrate method on the next invocation.
+// Rules are run by the validation engine which never runs migrate, so the cached validators are evicted instead
+func (rm *RuleManager) upgradeCode(contractAddr string, contract *wasm.Contract, code []byte, operator string) {
+	history := make([]*CodeRecord, 0)
+	_ = rm.GetObject(CodeHistoryKey(contractAddr), &history)
+	if len(history) == 0 {
+		history = append(history, &CodeRecord{
+			Version:  contract.Version,
+			CodeHash: contract.Hash.String(),
+			Operator: contract.Owner,
+		})
+	}
+
+	contract.Code = code
+	contract.Hash = *wasm.CodeHash(code)
+	contract.Version++
+	contract.Migrating = true
+	data, _ := json.Marshal(contract)
+	rm.GetAccount(contractAddr).(ledger.IAccount).SetCodeAndHash(data)
+
+	history = append(history, &CodeRecord{
+		Version:   contract.Version,
+		CodeHash:  contract.Hash.String(),
+		Operator:  operator,
+		Timestamp: rm.GetTxTimeStamp(),
+	})
+	rm.SetObject(CodeHistoryKey(contractAddr), history)
+
+	if evictor, ok := rm.ValidationEngine().(ValidatorEvictor); ok {
+		evictor.Evict(contractAddr)
+	}
+
+	rm.Logger().WithFields(logrus.Fields{
+		"address": contractAddr,
+		"version": contract.Version,
+		"hash":    contract.Hash.String(),
+	}).Info("Wasm contract code is upgraded")
+}
+
+func (rm *RuleManager) getWasmContract(contractAddr string) (*wasm.Contract, error) {
+	if _, err := types.HexDecodeString(contractAddr); err != nil {
+		return nil, fmt.Errorf("illegal contract address %s: %w", contractAddr, err)
+	}
+	code := rm.GetAccount(contractAddr).(ledger.IAccount).Code()
+	contract := &wasm.Contract{}
+	if err := json.Unmarshal(code, contract); err != nil || len(contract.Code) == 0 {
+		return nil, fmt.Errorf("%s is not a wasm contract", contractAddr)
+	}
+	return contract, nil
+}
+
+func CodeUpgradeObjID(contractAddr string) string {
+	return fmt.Sprintf("%s-%s", CODE_UPGRADE_PREFIX, contractAddr)
+}
+
+func isCodeUpgradeObjID(objId string) bool {
+	return strings.HasPrefix(objId, CODE_UPGRADE_PREFIX+"-")
+}
+
+func CodeHistoryKey(contractAddr string) string {
+	return fmt.Sprintf("%s-%s", CODE_HISTORY_PREFIX, contractAddr)
+}
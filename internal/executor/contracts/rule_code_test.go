@@ -0,0 +1,169 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/bytecodealliance/wasmtime-go"
+	"github.com/golang/mock/gomock"
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/boltvm/mock_stub"
+	"github.com/meshplus/bitxhub-core/governance"
+	"github.com/meshplus/bitxhub-kit/log"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/pkg/vm/wasm"
+	ledger2 "github.com/meshplus/eth-kit/ledger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const codeTestWat = `
+(module
+  (memory (export "memory") 1)
+  (func (export "allocate") (param i32) (result i32)
+    (i32.const 1024))
+  (func (export "deallocate") (param i32 i32))
+  (func (export "version") (result i32)
+    (i32.const %d)))
+`
+
+// evictEngine records the rules whose validators are evicted
+type evictEngine struct {
+	evicted []string
+}
+
+func (e *evictEngine) Validate(address, from string, proof, payload []byte, validators string) (bool, uint64, error) {
+	return true, 0, nil
+}
+
+func (e *evictEngine) Evict(address string) {
+	e.evicted = append(e.evicted, address)
+}
+
+func codePrepare(t *testing.T) (*RuleManager, *mock_stub.MockStub, ledger2.IAccount, string, []byte) {
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+	rm := &RuleManager{Stub: mockStub}
+
+	account := mockAccount(t)
+	code := wasmCode(t, 1)
+	data, err := json.Marshal(&wasm.Contract{
+		Code:    code,
+		Hash:    *wasm.CodeHash(code),
+		Owner:   appchainAdminAddr,
+		Version: 1,
+	})
+	require.Nil(t, err)
+	account.SetCodeAndHash(data)
+
+	history := make([]*CodeRecord, 0)
+	mockStub.EXPECT().GetAccount(gomock.Any()).Return(account).AnyTimes()
+	mockStub.EXPECT().GetTxTimeStamp().Return(int64(1)).AnyTimes()
+	mockStub.EXPECT().Logger().Return(log.NewWithModule("contracts")).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).DoAndReturn(func(key string, ret interface{}) bool {
+		*ret.(*[]*CodeRecord) = append([]*CodeRecord{}, history...)
+		return len(history) != 0
+	}).AnyTimes()
+	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).DoAndReturn(func(key string, value interface{}) {
+		history = value.([]*CodeRecord)
+	}).AnyTimes()
+
+	return rm, mockStub, account, types.NewAddress([]byte{1}).String(), wasmCode(t, 2)
+}
+
+func wasmCode(t *testing.T, version int) []byte {
+	code, err := wasmtime.Wat2Wasm(fmt.Sprintf(codeTestWat, version))
+	require.Nil(t, err)
+	return code
+}
+
+func TestRuleManager_UpgradeCodeByOwner(t *testing.T) {
+	rm, mockStub, account, addr, code := codePrepare(t)
+	engine := &evictEngine{}
+	mockStub.EXPECT().Caller().Return(appchainAdminAddr).AnyTimes()
+	mockStub.EXPECT().ValidationEngine().Return(engine).AnyTimes()
+
+	// invalid code
+	res := rm.UpgradeCode(addr, []byte("code"), reason)
+	assert.False(t, res.Ok, string(res.Result))
+	// same code
+	res = rm.UpgradeCode(addr, wasmCode(t, 1), reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = rm.UpgradeCode(addr, code, reason)
+	assert.True(t, res.Ok, string(res.Result))
+	ret := &governance.GovernanceResult{}
+	assert.Nil(t, json.Unmarshal(res.Result, ret))
+	assert.Equal(t, "", ret.ProposalID)
+
+	contract := &wasm.Contract{}
+	assert.Nil(t, json.Unmarshal(account.Code(), contract))
+	assert.Equal(t, code, contract.Code)
+	assert.Equal(t, uint64(2), contract.Version)
+	assert.Equal(t, wasm.CodeHash(code).String(), contract.Hash.String())
+	assert.True(t, contract.Migrating)
+	assert.Equal(t, []string{addr}, engine.evicted)
+
+	res = rm.GetCodeHistory(addr)
+	assert.True(t, res.Ok, string(res.Result))
+	history := make([]*CodeRecord, 0)
+	assert.Nil(t, json.Unmarshal(res.Result, &history))
+	assert.Equal(t, 2, len(history))
+	assert.Equal(t, wasm.CodeHash(wasmCode(t, 1)).String(), history[0].CodeHash)
+	assert.Equal(t, contract.Hash.String(), history[1].CodeHash)
+	assert.Equal(t, appchainAdminAddr, history[1].Operator)
+}
+
+func TestRuleManager_UpgradeCodeByGovernance(t *testing.T) {
+	rm, mockStub, account, addr, code := codePrepare(t)
+	engine := &evictEngine{}
+	mockStub.EXPECT().Caller().Return(adminAddr).AnyTimes()
+	mockStub.EXPECT().ValidationEngine().Return(engine).AnyTimes()
+	mockStub.EXPECT().CurrentCaller().Return(constant.GovernanceContractAddr.Address().String()).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte(FALSE))).Times(1)
+	mockStub.EXPECT().CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", gomock.Any(), gomock.Any()).Return(boltvm.Success([]byte(TRUE))).AnyTimes()
+	var extra []byte
+	mockStub.EXPECT().CrossInvoke(constant.GovernanceContractAddr.Address().String(), "SubmitProposal",
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(addr, method string, args ...*pb.Arg) *boltvm.Response {
+			extra = args[6].Value
+			return boltvm.Success([]byte("proposal-0"))
+		}).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.GovernanceContractAddr.Address().String(), "ZeroPermission", gomock.Any()).Return(boltvm.Success(nil)).AnyTimes()
+
+	// not owner or governance admin
+	res := rm.UpgradeCode(addr, code, reason)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = rm.UpgradeCode(addr, code, reason)
+	assert.True(t, res.Ok, string(res.Result))
+	ret := &governance.GovernanceResult{}
+	assert.Nil(t, json.Unmarshal(res.Result, ret))
+	assert.Equal(t, "proposal-0", ret.ProposalID)
+
+	// rejected proposal keeps the code
+	res = rm.Manage(string(governance.EventUpdate), string(REJECTED), "", CodeUpgradeObjID(addr), extra)
+	assert.True(t, res.Ok, string(res.Result))
+	contract := &wasm.Contract{}
+	assert.Nil(t, json.Unmarshal(account.Code(), contract))
+	assert.Equal(t, uint64(1), contract.Version)
+	assert.Equal(t, 0, len(engine.evicted))
+
+	res = rm.Manage(string(governance.EventUpdate), string(APPROVED), "", CodeUpgradeObjID(addr), extra)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Nil(t, json.Unmarshal(account.Code(), contract))
+	assert.Equal(t, code, contract.Code)
+	assert.Equal(t, uint64(2), contract.Version)
+	assert.Equal(t, appchainAdminAddr, contract.Owner)
+	assert.Equal(t, []string{addr}, engine.evicted)
+
+	res = rm.GetCodeHistory(addr)
+	assert.True(t, res.Ok, string(res.Result))
+	history := make([]*CodeRecord, 0)
+	assert.Nil(t, json.Unmarshal(res.Result, &history))
+	assert.Equal(t, 2, len(history))
+	assert.Equal(t, constant.GovernanceContractAddr.Address().String(), history[1].Operator)
+}
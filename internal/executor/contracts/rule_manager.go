@@ -18,6 +18,23 @@ import (
 	"github.com/meshplus/eth-kit/ledger"
 )
 
+// Built-in proof verification schemes, appchains binding these addresses are verified
+// natively by the proof pool instead of running a WASM rule
+const (
+	MPTReceiptRuleAddr      = "0x00000000000000000000000000000000000000b0"
+	BLSAggregateRuleAddr    = "0x00000000000000000000000000000000000000b1"
+	Ed25519MultiSigRuleAddr = "0x00000000000000000000000000000000000000b2"
+)
+
+// IsSchemeRule checks whether the rule address is a built-in proof verification scheme
+func IsSchemeRule(addr string) bool {
+	switch addr {
+	case MPTReceiptRuleAddr, BLSAggregateRuleAddr, Ed25519MultiSigRuleAddr:
+		return true
+	}
+	return false
+}
+
 // RuleManager is the contract manage validation rules
 type RuleManager struct {
 	boltvm.Stub
@@ -76,8 +93,8 @@ func (rm *RuleManager) checkPermission(permissions []string, appchainID string,
 }
 
 // =========== Manage does some subsequent operations when the proposal is over
-// Currently here are only update master rule events
-// extra: update :UpdateMasterRuleInfo
+// Currently here are only update master rule events and wasm code upgrade events
+// extra: update :UpdateMasterRuleInfo or CodeUpgrade
 func (rm *RuleManager) Manage(eventTyp, proposalResult, lastStatus, chainRuleID string, extra []byte) *boltvm.Response {
 	rm.RuleManager.Persister = rm.Stub
 
@@ -93,6 +110,12 @@ func (rm *RuleManager) Manage(eventTyp, proposalResult, lastStatus, chainRuleID
 	}
 
 	// 2. other operation
+	if isCodeUpgradeObjID(chainRuleID) {
+		if eventTyp != string(governance.EventUpdate) {
+			return boltvm.Success(nil)
+		}
+		return rm.manageCodeUpgrade(proposalResult, extra)
+	}
 	switch eventTyp {
 	case string(governance.EventUpdate):
 		info := &UpdateMasterRuleInfo{}
@@ -534,7 +557,7 @@ func (rm *RuleManager) IsAvailableRule(chainID, ruleAddress string) *boltvm.Resp
 }
 
 func CheckRuleAddress(persister governance.Persister, addr, chainType string) *boltvm.Response {
-	if ruleMgr.IsDefault(addr, chainType) {
+	if ruleMgr.IsDefault(addr, chainType) || IsSchemeRule(addr) {
 		return boltvm.Success(nil)
 	}
 
@@ -23,6 +23,7 @@ import (
 	"github.com/meshplus/bitxhub-model/pb"
 	"github.com/meshplus/bitxhub/internal/ledger"
 	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/meshplus/bitxhub/pkg/signer"
 	ledger2 "github.com/meshplus/eth-kit/ledger"
 	libp2pcert "github.com/meshplus/go-libp2p-cert"
 	"github.com/stretchr/testify/assert"
@@ -600,7 +601,7 @@ BcNwjTDCxyxLNjFKQfMAc6sY6iJs+Ma59WZyC/4uhjE=
 
 	rep := &repo.Repo{
 		Key: &repo.Key{
-			PrivKey: privKey,
+			PrivKey: signer.NewLocalSigner(privKey),
 			Address: address.String(),
 		},
 		Config: &repo.Config{},
@@ -33,6 +33,11 @@ const (
 	LevelTwo             = 2
 	RegisteredDomain     = 1
 	ReviewedDomain       = 0
+	OwnershipPrefix      = "ownership"
+
+	OwnershipRegister  = "register"
+	OwnershipTransfer  = "transfer"
+	OwnershipSubDomain = "subDomainOwner"
 )
 
 type ServiceRegistry struct {
@@ -70,10 +75,21 @@ type SubDomainProposalData struct {
 }
 
 type ServDomain struct {
-	Name       string `json:"name"`
-	Level      int    `json:"level"`
-	Status     int    `json:"status"`
-	ParentName string `json:"parent_name"`
+	Name       string            `json:"name"`
+	Level      int               `json:"level"`
+	Status     int               `json:"status"`
+	ParentName string            `json:"parent_name"`
+	Owner      string            `json:"owner,omitempty"`
+	History    []*OwnershipEvent `json:"history,omitempty"`
+}
+
+// OwnershipEvent records a change of domain owner
+type OwnershipEvent struct {
+	Name      string `json:"name"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Type      string `json:"type"`
+	Timestamp uint64 `json:"timestamp"`
 }
 
 // Register a first-level domain name
@@ -94,16 +110,21 @@ func (sr ServiceRegistry) Register(name string, duration uint64, resolver string
 		return boltvm.Error(boltvm.BnsErrCode, "The duration can not be zero")
 	}
 
+	if sr.getAuctionConfig().isAuctionName(name) {
+		return boltvm.Error(boltvm.BnsErrCode, "The domain id can only be acquired by auction")
+	}
+
 	account := sr.GetAccount(owner).(ledger.IAccount)
 	balance := account.GetBalance().Uint64()
 
 	level1Domain := sr.getLevel1Domain()
 	registerName := generateSubDomain(RootDomain, name)
-	if level1Domain[registerName]+GRACEPERIOD > uint64(sr.GetTxTimeStamp()/SecondTime) || uint64(sr.GetTxTimeStamp()/SecondTime)+duration+GRACEPERIOD < uint64(sr.GetTxTimeStamp()/SecondTime)+GRACEPERIOD {
+	now := sr.now()
+	if level1Domain[registerName]+sr.getGracePeriod() > now || now+duration < now {
 		return boltvm.Error(boltvm.BnsErrCode, "The domain id registered or in GRACEPERIOD")
 	}
 
-	price, err := sr.getPrice(name, level1Domain[name], duration)
+	price, err := sr.getPrice(name, level1Domain[registerName], duration)
 	if err != nil {
 		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("get register price: %v", err))
 	}
@@ -111,22 +132,34 @@ func (sr ServiceRegistry) Register(name string, duration uint64, resolver string
 	if balance < registerCost {
 		return boltvm.Error(boltvm.BnsErrCode, "Not enough Bitxhub Token provided")
 	}
+	if err := sr.register(name, owner, resolver, duration); err != nil {
+		return boltvm.Error(boltvm.BnsErrCode, err.Error())
+	}
+
+	account.SubBalance(new(big.Int).SetUint64(registerCost))
+
+	return boltvm.Success(nil)
+}
+
+func (sr ServiceRegistry) register(name string, owner string, resolver string, duration uint64) error {
+	registerName := generateSubDomain(RootDomain, name)
+	prevRec := ServDomainRec{}
+	sr.GetObject(registerName, &prevRec)
 	sr.setSubDomainOwner(RootDomain, name, owner)
 	sr.setRecord(registerName, owner, resolver, RootDomain)
+	sr.recordOwnership(registerName, prevRec.Owner, owner, OwnershipRegister)
 
-	level1Domain[registerName] = uint64(sr.GetTxTimeStamp()/SecondTime) + duration
+	level1Domain := sr.getLevel1Domain()
+	level1Domain[registerName] = sr.now() + duration
 	sr.SetObject(Level1Domain, level1Domain)
 
 	res := sr.CrossInvoke(resolver, "SetServDomainData",
 		pb.String(registerName),
 		pb.Uint64(1), pb.String(owner), pb.String(""), pb.String(""), pb.String(""))
 	if !res.Ok {
-		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("register servDomainData error: %v", err))
+		return fmt.Errorf("register servDomainData error: %s", string(res.Result))
 	}
-
-	account.SubBalance(new(big.Int).SetUint64(registerCost))
-
-	return boltvm.Success(nil)
+	return nil
 }
 
 // Renew First-level domain name renewal
@@ -140,13 +173,23 @@ func (sr ServiceRegistry) Renew(name string, duration uint64) *boltvm.Response {
 	if !sr.checkNameAvailable(name) {
 		return boltvm.Error(boltvm.BnsErrCode, "The domain must register first")
 	}
-	account := sr.GetAccount(sr.Caller()).(ledger.IAccount)
+	caller := sr.Caller()
+	account := sr.GetAccount(caller).(ledger.IAccount)
 	balance := account.GetBalance().Uint64()
 
 	level1Domain := sr.getLevel1Domain()
-	if level1Domain[name]+GRACEPERIOD < uint64(sr.GetTxTimeStamp()/SecondTime) || uint64(sr.GetTxTimeStamp()/SecondTime)+duration+GRACEPERIOD < uint64(sr.GetTxTimeStamp()/SecondTime)+GRACEPERIOD {
+	now := sr.now()
+	if level1Domain[name]+sr.getGracePeriod() < now || now+duration < now {
 		return boltvm.Error(boltvm.BnsErrCode, "The domain id must registered in GRACEPERIOD ")
 	}
+	// once expired, only the previous owner can take the name back
+	if level1Domain[name] < now {
+		servDomainRec := ServDomainRec{}
+		sr.GetObject(name, &servDomainRec)
+		if servDomainRec.Owner != caller {
+			return boltvm.Error(boltvm.BnsErrCode, "The domain id in GRACEPERIOD can only be renewed by the previous owner")
+		}
+	}
 	price, err := sr.getPrice(name, level1Domain[name], duration)
 	if err != nil {
 		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("get register price: %v", err))
@@ -266,6 +309,7 @@ func (sr ServiceRegistry) manageRegisterApprove(extra []byte) error {
 	resolver := domainData.Resolver
 
 	sonDomain := sr.setSubRecord(parentName, sonName, owner, resolver)
+	sr.recordOwnership(sonDomain, "", owner, OwnershipRegister)
 
 	res := sr.CrossInvoke(resolver, "SetServDomainData",
 		pb.String(sonDomain),
@@ -312,6 +356,100 @@ func (sr ServiceRegistry) manageRegisterReject(extra []byte) error {
 	return nil
 }
 
+// Transfer Change the owner of a domain, the caller must be the owner or an approved operator
+func (sr ServiceRegistry) Transfer(name string, newOwner string) *boltvm.Response {
+	if name == "" || !sr.checkNameAvailable(name) {
+		return boltvm.Error(boltvm.BnsErrCode, "The domain id must be registered")
+	}
+	if !checkBxhAddress(newOwner) {
+		return boltvm.Error(boltvm.BnsErrCode, "The address is not valid")
+	}
+	if !sr.authorised(name) {
+		return boltvm.Error(boltvm.BnsErrCode, "The domain name does not belong to you")
+	}
+	servDomainRec := ServDomainRec{}
+	if !sr.GetObject(name, &servDomainRec) {
+		return boltvm.Error(boltvm.BnsErrCode, "there is not exist key")
+	}
+	if servDomainRec.Parent == RootDomain && sr.getLevel1Domain()[name] < sr.now() {
+		return boltvm.Error(boltvm.BnsErrCode, "The domain id is expired, renew it first")
+	}
+	if servDomainRec.Owner == newOwner {
+		return boltvm.Error(boltvm.BnsErrCode, "The new owner is the same as the current owner")
+	}
+
+	from := servDomainRec.Owner
+	servDomainRec.Owner = newOwner
+	sr.SetObject(name, servDomainRec)
+	sr.recordOwnership(name, from, newOwner, OwnershipTransfer)
+	return boltvm.Success(nil)
+}
+
+// SetApprovalForAll Approve or revoke an operator managing all domains of the caller
+func (sr ServiceRegistry) SetApprovalForAll(operator string, approved bool) *boltvm.Response {
+	caller := sr.Caller()
+	if !checkBxhAddress(operator) || operator == caller {
+		return boltvm.Error(boltvm.BnsErrCode, "The operator address is not valid")
+	}
+	// approvals of the BNS contracts themselves are reserved for genesis
+	if operator == constant.ServiceRegistryContractAddr.Address().String() ||
+		operator == constant.ServiceResolverContractAddr.Address().String() {
+		return boltvm.Error(boltvm.BnsErrCode, "The operator can not be a BNS contract")
+	}
+	permissionController := make(map[string]map[string]bool)
+	sr.GetObject(PermissionController, &permissionController)
+	if permissionController[caller] == nil {
+		permissionController[caller] = make(map[string]bool)
+	}
+	if approved {
+		permissionController[caller][operator] = true
+	} else {
+		delete(permissionController[caller], operator)
+	}
+	sr.SetObject(PermissionController, permissionController)
+	return boltvm.Success(nil)
+}
+
+// SetSubDomainOwner Change the owner of an existing subdomain by the parent domain owner
+func (sr ServiceRegistry) SetSubDomainOwner(parentName string, sonName string, owner string) *boltvm.Response {
+	if parentName == "" || sonName == "" {
+		return boltvm.Error(boltvm.BnsErrCode, "The domain name can not be an empty string")
+	}
+	if !checkBxhAddress(owner) {
+		return boltvm.Error(boltvm.BnsErrCode, "The address is not valid")
+	}
+	if !sr.checkNameAvailable(parentName) {
+		return boltvm.Error(boltvm.BnsErrCode, "The domain must register first")
+	}
+	if !sr.authorised(parentName) {
+		return boltvm.Error(boltvm.BnsErrCode, "The domain name does not belong to you")
+	}
+	parentDomainRec := ServDomainRec{}
+	sr.GetObject(parentName, &parentDomainRec)
+	sonDomain := generateSubDomain(parentName, sonName)
+	if !parentDomainRec.SubDomain[sonDomain] {
+		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("The subdomain %s does not exist", sonDomain))
+	}
+
+	sonDomainRec := ServDomainRec{}
+	sr.GetObject(sonDomain, &sonDomainRec)
+	sr.setSubDomainOwner(parentName, sonName, owner)
+	sr.recordOwnership(sonDomain, sonDomainRec.Owner, owner, OwnershipSubDomain)
+	return boltvm.Success(nil)
+}
+
+// GetOwnershipHistory Get the owner changes of a domain in time order
+func (sr ServiceRegistry) GetOwnershipHistory(name string) *boltvm.Response {
+	if name == "" {
+		return boltvm.Error(boltvm.BnsErrCode, "The domain id can not be an empty string")
+	}
+	historyBytes, err := json.Marshal(sr.getOwnershipHistory(name))
+	if err != nil {
+		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("marshal ownership history error: %v", err))
+	}
+	return boltvm.Success(historyBytes)
+}
+
 func (sr ServiceRegistry) DeleteSecondDomain(name string) *boltvm.Response {
 	if name == "" {
 		return boltvm.Error(boltvm.BnsErrCode, "The domain name can not be an empty string")
@@ -349,9 +487,7 @@ func (sr ServiceRegistry) DeleteSecondDomain(name string) *boltvm.Response {
 
 // SetPriceLevel Update registration price
 func (sr ServiceRegistry) SetPriceLevel(price1Letter uint64, price2Letter uint64, price3Letter uint64, price4Letter uint64, price5Letter uint64) *boltvm.Response {
-	addr := sr.Caller()
-	res := sr.CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", pb.String(addr), pb.String(string(GovernanceAdmin)))
-	if !res.Ok || "false" == string(res.Result) {
+	if !sr.isGovernanceAdmin() {
 		return boltvm.Error(boltvm.GovernanceInternalErrCode, "you have no permission")
 	}
 	priceLevel := PriceLevel{
@@ -382,9 +518,7 @@ func (sr ServiceRegistry) SetTokenPrice(tokenPrice uint64) *boltvm.Response {
 	if tokenPrice == 0 {
 		return boltvm.Error(boltvm.BnsErrCode, "The Token Price can not be zero")
 	}
-	addr := sr.Caller()
-	res := sr.CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", pb.String(addr), pb.String(string(GovernanceAdmin)))
-	if !res.Ok || "false" == string(res.Result) {
+	if !sr.isGovernanceAdmin() {
 		return boltvm.Error(boltvm.GovernanceInternalErrCode, "you have no permission")
 	}
 	sr.SetObject(BitxhubTokenPrice, tokenPrice)
@@ -522,14 +656,32 @@ func (sr ServiceRegistry) getPrice(name string, expires uint64, duration uint64)
 	return price, nil
 }
 
-func (sr ServiceRegistry) premium(_ string, _ uint64, _ uint64) uint64 {
-	return 0
+// premium is charged on top of the base price for a name released less than
+// PremiumPeriod seconds ago, it decays linearly from StartPremium to zero.
+func (sr ServiceRegistry) premium(_ string, expires uint64, _ uint64) uint64 {
+	config := PremiumConfig{}
+	if expires == 0 || !sr.GetObject(PremiumConfigKey, &config) || config.PremiumPeriod == 0 {
+		return 0
+	}
+	releaseTime := expires + sr.getGracePeriod()
+	now := sr.now()
+	if now < releaseTime || now-releaseTime >= config.PremiumPeriod {
+		return 0
+	}
+	left := new(big.Int).SetUint64(config.PremiumPeriod - (now - releaseTime))
+	premium := new(big.Int).Mul(new(big.Int).SetUint64(config.StartPremium), left)
+	return premium.Div(premium, new(big.Int).SetUint64(config.PremiumPeriod)).Uint64()
 }
 
 func (sr ServiceRegistry) attoCYNToWei(amount uint64, tokenPrice uint64) uint64 {
 	return amount * 1e8 / tokenPrice
 }
 
+func (sr ServiceRegistry) isGovernanceAdmin() bool {
+	res := sr.CrossInvoke(constant.RoleContractAddr.Address().String(), "IsAnyAvailableAdmin", pb.String(sr.Caller()), pb.String(string(GovernanceAdmin)))
+	return res.Ok && "false" != string(res.Result)
+}
+
 func (sr ServiceRegistry) getLevel1Domain() map[string]uint64 {
 	servDomain := make(map[string]uint64)
 	ok := sr.GetObject(Level1Domain, &servDomain)
@@ -539,6 +691,28 @@ func (sr ServiceRegistry) getLevel1Domain() map[string]uint64 {
 	return servDomain
 }
 
+func OwnershipKey(name string) string {
+	return fmt.Sprintf("%s-%s", OwnershipPrefix, name)
+}
+
+func (sr ServiceRegistry) getOwnershipHistory(name string) []*OwnershipEvent {
+	history := make([]*OwnershipEvent, 0)
+	sr.GetObject(OwnershipKey(name), &history)
+	return history
+}
+
+func (sr ServiceRegistry) recordOwnership(name string, from string, to string, typ string) {
+	history := sr.getOwnershipHistory(name)
+	history = append(history, &OwnershipEvent{
+		Name:      name,
+		From:      from,
+		To:        to,
+		Type:      typ,
+		Timestamp: sr.now(),
+	})
+	sr.SetObject(OwnershipKey(name), history)
+}
+
 func (sr ServiceRegistry) authorised(name string) bool {
 	servDomainRec := ServDomainRec{}
 	caller := sr.Caller()
@@ -551,7 +725,9 @@ func (sr ServiceRegistry) authorised(name string) bool {
 	if !ok {
 		return false
 	}
-	return servDomainRec.Owner == caller || permissionController[sr.CurrentCaller()][string(constant.ServiceRegistryContractAddr)]
+	return servDomainRec.Owner == caller ||
+		permissionController[servDomainRec.Owner][caller] ||
+		permissionController[sr.CurrentCaller()][string(constant.ServiceRegistryContractAddr)]
 }
 
 func (sr ServiceRegistry) setRecord(name string, owner string, resolver string, parent string) {
@@ -635,26 +811,32 @@ func (sr ServiceRegistry) GetAllDomains() *boltvm.Response {
 	ok := sr.GetObject(Level1Domain, &servDomain)
 	if ok {
 		for firstDomain := range servDomain {
+			serviceDomainFirst := ServDomainRec{}
+			ok := sr.GetObject(firstDomain, &serviceDomainFirst)
+			if !ok {
+				return boltvm.Error(boltvm.BnsErrCode, "there is not exist key")
+			}
 			first := ServDomain{
 				Name:       firstDomain,
 				Level:      LevelOne,
 				Status:     RegisteredDomain,
 				ParentName: root.Name,
+				Owner:      serviceDomainFirst.Owner,
+				History:    sr.getOwnershipHistory(firstDomain),
 			}
 			res = append(res, &first)
 
-			serviceDomainFirst := ServDomainRec{}
-			ok := sr.GetObject(firstDomain, &serviceDomainFirst)
-			if !ok {
-				return boltvm.Error(boltvm.BnsErrCode, "there is not exist key")
-			}
 			for secondDomain := range serviceDomainFirst.SubDomain {
 				if serviceDomainFirst.SubDomain[secondDomain] {
+					serviceDomainSecond := ServDomainRec{}
+					sr.GetObject(secondDomain, &serviceDomainSecond)
 					second := ServDomain{
 						Name:       secondDomain,
 						Level:      LevelTwo,
 						Status:     RegisteredDomain,
 						ParentName: first.Name,
+						Owner:      serviceDomainSecond.Owner,
+						History:    sr.getOwnershipHistory(secondDomain),
 					}
 					res = append(res, &second)
 				}
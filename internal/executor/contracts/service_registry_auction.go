@@ -0,0 +1,376 @@
+package contracts
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/eth-kit/ledger"
+)
+
+const (
+	GracePeriodKey   = "gracePeriod"
+	PremiumConfigKey = "premiumConfig"
+	AuctionConfigKey = "auctionConfig"
+	AuctionPrefix    = "auction"
+
+	AuctionBidding   = "bidding"
+	AuctionRevealing = "revealing"
+	AuctionEnded     = "ended"
+	AuctionFinalized = "finalized"
+)
+
+// PremiumConfig describes the temporary premium charged for a name that has
+// just been released: it starts at StartPremium (in the same unit as PriceLevel)
+// and decays linearly to zero over PremiumPeriod seconds.
+type PremiumConfig struct {
+	StartPremium  uint64 `json:"start_premium"`
+	PremiumPeriod uint64 `json:"premium_period"`
+}
+
+// AuctionConfig describes which first-level names are sold by sealed-bid auction.
+// Names no longer than MaxNameLen can only be acquired through an auction,
+// a MaxNameLen of zero disables auctions.
+type AuctionConfig struct {
+	MaxNameLen    uint64 `json:"max_name_len"`
+	BiddingPeriod uint64 `json:"bidding_period"`
+	RevealPeriod  uint64 `json:"reveal_period"`
+}
+
+type SealedBid struct {
+	SealedHash string `json:"sealed_hash"`
+	Deposit    uint64 `json:"deposit"`
+	Value      uint64 `json:"value"`
+	Revealed   bool   `json:"revealed"`
+}
+
+// NameAuction is a Vickrey auction: the highest revealed bidder wins and pays
+// the second highest revealed bid, but never less than the registration price.
+type NameAuction struct {
+	Name          string                `json:"name"`
+	StartTime     uint64                `json:"start_time"`
+	BiddingEnd    uint64                `json:"bidding_end"`
+	RevealEnd     uint64                `json:"reveal_end"`
+	Bids          map[string]*SealedBid `json:"bids"`
+	HighestBidder string                `json:"highest_bidder"`
+	HighestBid    uint64                `json:"highest_bid"`
+	SecondBid     uint64                `json:"second_bid"`
+	Status        string                `json:"status"`
+}
+
+func AuctionKey(name string) string {
+	return fmt.Sprintf("%s-%s", AuctionPrefix, name)
+}
+
+// SealedBidHash returns the commitment a bidder submits during the bidding period.
+func SealedBidHash(name, bidder string, value uint64, salt string) string {
+	data := fmt.Sprintf("%s-%s-%d-%s", name, bidder, value, salt)
+	return fmt.Sprintf("0x%x", crypto.Keccak256([]byte(data)))
+}
+
+// SetGracePeriod Update the period after expiry during which only the previous owner can renew
+func (sr ServiceRegistry) SetGracePeriod(period uint64) *boltvm.Response {
+	if !sr.isGovernanceAdmin() {
+		return boltvm.Error(boltvm.GovernanceInternalErrCode, "you have no permission")
+	}
+	sr.SetObject(GracePeriodKey, period)
+	return boltvm.Success(nil)
+}
+
+func (sr ServiceRegistry) GetGracePeriod() *boltvm.Response {
+	res := make([]byte, 8)
+	binary.BigEndian.PutUint64(res, sr.getGracePeriod())
+	return boltvm.Success(res)
+}
+
+func (sr ServiceRegistry) SetPremiumConfig(startPremium uint64, premiumPeriod uint64) *boltvm.Response {
+	if !sr.isGovernanceAdmin() {
+		return boltvm.Error(boltvm.GovernanceInternalErrCode, "you have no permission")
+	}
+	if startPremium != 0 && premiumPeriod == 0 {
+		return boltvm.Error(boltvm.BnsErrCode, "The premium period can not be zero")
+	}
+	sr.SetObject(PremiumConfigKey, PremiumConfig{
+		StartPremium:  startPremium,
+		PremiumPeriod: premiumPeriod,
+	})
+	return boltvm.Success(nil)
+}
+
+func (sr ServiceRegistry) GetPremiumConfig() *boltvm.Response {
+	config := PremiumConfig{}
+	sr.GetObject(PremiumConfigKey, &config)
+	data, err := json.Marshal(config)
+	if err != nil {
+		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("marshal premium config error: %v", err))
+	}
+	return boltvm.Success(data)
+}
+
+func (sr ServiceRegistry) SetAuctionConfig(maxNameLen uint64, biddingPeriod uint64, revealPeriod uint64) *boltvm.Response {
+	if !sr.isGovernanceAdmin() {
+		return boltvm.Error(boltvm.GovernanceInternalErrCode, "you have no permission")
+	}
+	if maxNameLen != 0 && (biddingPeriod == 0 || revealPeriod == 0) {
+		return boltvm.Error(boltvm.BnsErrCode, "The bidding period and reveal period can not be zero")
+	}
+	sr.SetObject(AuctionConfigKey, AuctionConfig{
+		MaxNameLen:    maxNameLen,
+		BiddingPeriod: biddingPeriod,
+		RevealPeriod:  revealPeriod,
+	})
+	return boltvm.Success(nil)
+}
+
+func (sr ServiceRegistry) GetAuctionConfig() *boltvm.Response {
+	data, err := json.Marshal(sr.getAuctionConfig())
+	if err != nil {
+		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("marshal auction config error: %v", err))
+	}
+	return boltvm.Success(data)
+}
+
+// StartAuction Open the bidding period of a first-level domain name
+func (sr ServiceRegistry) StartAuction(name string) *boltvm.Response {
+	if name == "" {
+		return boltvm.Error(boltvm.BnsErrCode, "The domain id can not be an empty string")
+	}
+	config := sr.getAuctionConfig()
+	if !config.isAuctionName(name) {
+		return boltvm.Error(boltvm.BnsErrCode, "The domain id is not sold by auction")
+	}
+	now := sr.now()
+	level1Domain := sr.getLevel1Domain()
+	if level1Domain[generateSubDomain(RootDomain, name)]+sr.getGracePeriod() > now {
+		return boltvm.Error(boltvm.BnsErrCode, "The domain id registered or in GRACEPERIOD")
+	}
+
+	auction := &NameAuction{}
+	if sr.GetObject(AuctionKey(name), auction) {
+		if status := auction.status(now); status != AuctionFinalized && !auction.abandoned(now) {
+			return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("The auction of %s is %s", name, status))
+		}
+	}
+
+	auction = &NameAuction{
+		Name:       name,
+		StartTime:  now,
+		BiddingEnd: now + config.BiddingPeriod,
+		RevealEnd:  now + config.BiddingPeriod + config.RevealPeriod,
+		Bids:       make(map[string]*SealedBid),
+		Status:     AuctionBidding,
+	}
+	sr.SetObject(AuctionKey(name), auction)
+	return boltvm.Success(nil)
+}
+
+// Bid Submit a sealed bid and lock the deposit, the deposit must cover the bid value.
+// The deposit is forfeited if the bid is not revealed in the reveal period.
+func (sr ServiceRegistry) Bid(name string, sealedHash string, deposit uint64) *boltvm.Response {
+	bidder := sr.Caller()
+	auction, err := sr.getAuction(name)
+	if err != nil {
+		return boltvm.Error(boltvm.BnsErrCode, err.Error())
+	}
+	if status := auction.status(sr.now()); status != AuctionBidding {
+		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("The auction of %s is %s", name, status))
+	}
+	if sealedHash == "" || deposit == 0 {
+		return boltvm.Error(boltvm.BnsErrCode, "The sealed hash and deposit can not be empty")
+	}
+	if _, ok := auction.Bids[bidder]; ok {
+		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("%s has already bid for %s", bidder, name))
+	}
+
+	account := sr.GetAccount(bidder).(ledger.IAccount)
+	if account.GetBalance().Uint64() < deposit {
+		return boltvm.Error(boltvm.BnsErrCode, "Not enough Bitxhub Token provided")
+	}
+	account.SubBalance(new(big.Int).SetUint64(deposit))
+
+	auction.Bids[bidder] = &SealedBid{
+		SealedHash: sealedHash,
+		Deposit:    deposit,
+	}
+	sr.SetObject(AuctionKey(name), auction)
+	return boltvm.Success(nil)
+}
+
+// Reveal Open a sealed bid. Losing bids are refunded immediately, a bid value
+// larger than its deposit only counts up to the deposit.
+func (sr ServiceRegistry) Reveal(name string, value uint64, salt string) *boltvm.Response {
+	bidder := sr.Caller()
+	auction, err := sr.getAuction(name)
+	if err != nil {
+		return boltvm.Error(boltvm.BnsErrCode, err.Error())
+	}
+	if status := auction.status(sr.now()); status != AuctionRevealing {
+		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("The auction of %s is %s", name, status))
+	}
+	bid, ok := auction.Bids[bidder]
+	if !ok {
+		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("%s has no bid for %s", bidder, name))
+	}
+	if bid.Revealed {
+		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("%s has already revealed the bid", bidder))
+	}
+	if SealedBidHash(name, bidder, value, salt) != bid.SealedHash {
+		return boltvm.Error(boltvm.BnsErrCode, "The revealed bid does not match the sealed hash")
+	}
+
+	if value > bid.Deposit {
+		value = bid.Deposit
+	}
+	bid.Revealed = true
+	bid.Value = value
+
+	switch {
+	case value > auction.HighestBid:
+		if auction.HighestBidder != "" {
+			sr.refund(auction.HighestBidder, auction.Bids[auction.HighestBidder].Deposit)
+		}
+		auction.SecondBid = auction.HighestBid
+		auction.HighestBid = value
+		auction.HighestBidder = bidder
+	case value > auction.SecondBid:
+		auction.SecondBid = value
+		sr.refund(bidder, bid.Deposit)
+	default:
+		sr.refund(bidder, bid.Deposit)
+	}
+	sr.SetObject(AuctionKey(name), auction)
+	return boltvm.Success(nil)
+}
+
+// FinalizeAuction Register the name for the auction winner. The winner pays the
+// second highest bid or the registration price, whichever is higher, and gets
+// the rest of the deposit back. The winner must finalize within one more reveal
+// period after the auction ends, otherwise the deposit is forfeited like the
+// unrevealed ones, so that bidders can't bid without committing to pay.
+func (sr ServiceRegistry) FinalizeAuction(name string, duration uint64, resolver string) *boltvm.Response {
+	winner := sr.Caller()
+	now := sr.now()
+	auction, err := sr.getAuction(name)
+	if err != nil {
+		return boltvm.Error(boltvm.BnsErrCode, err.Error())
+	}
+	if status := auction.status(now); status != AuctionEnded {
+		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("The auction of %s is %s", name, status))
+	}
+	if auction.abandoned(now) {
+		return boltvm.Error(boltvm.BnsErrCode, "The auction has no winner or the claim period is over")
+	}
+	if auction.HighestBidder != winner {
+		return boltvm.Error(boltvm.BnsErrCode, "Only the auction winner can finalize the auction")
+	}
+	if duration == 0 {
+		return boltvm.Error(boltvm.BnsErrCode, "The duration can not be zero")
+	}
+	if resolver == "" || !sr.checkResolverAddress(resolver) {
+		return boltvm.Error(boltvm.BnsErrCode, "The resolver is not in the list")
+	}
+
+	price, err := sr.getPrice(name, 0, duration)
+	if err != nil {
+		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("get register price: %v", err))
+	}
+	cost := price.base + price.premium
+	if auction.SecondBid > cost {
+		cost = auction.SecondBid
+	}
+
+	deposit := auction.Bids[winner].Deposit
+	account := sr.GetAccount(winner).(ledger.IAccount)
+	if cost > deposit {
+		if account.GetBalance().Uint64() < cost-deposit {
+			return boltvm.Error(boltvm.BnsErrCode, "Not enough Bitxhub Token provided")
+		}
+		account.SubBalance(new(big.Int).SetUint64(cost - deposit))
+	} else {
+		account.AddBalance(new(big.Int).SetUint64(deposit - cost))
+	}
+
+	if err := sr.register(name, winner, resolver, duration); err != nil {
+		return boltvm.Error(boltvm.BnsErrCode, err.Error())
+	}
+
+	auction.Status = AuctionFinalized
+	sr.SetObject(AuctionKey(name), auction)
+	return boltvm.Success(nil)
+}
+
+func (sr ServiceRegistry) GetAuction(name string) *boltvm.Response {
+	auction, err := sr.getAuction(name)
+	if err != nil {
+		return boltvm.Error(boltvm.BnsErrCode, err.Error())
+	}
+	if auction.Status != AuctionFinalized {
+		auction.Status = auction.status(sr.now())
+	}
+	data, err := json.Marshal(auction)
+	if err != nil {
+		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("marshal auction error: %v", err))
+	}
+	return boltvm.Success(data)
+}
+
+func (sr ServiceRegistry) getAuction(name string) (*NameAuction, error) {
+	auction := &NameAuction{}
+	if !sr.GetObject(AuctionKey(name), auction) {
+		return nil, fmt.Errorf("The auction of %s does not exist", name)
+	}
+	if auction.Bids == nil {
+		auction.Bids = make(map[string]*SealedBid)
+	}
+	return auction, nil
+}
+
+func (sr ServiceRegistry) getAuctionConfig() AuctionConfig {
+	config := AuctionConfig{}
+	sr.GetObject(AuctionConfigKey, &config)
+	return config
+}
+
+func (sr ServiceRegistry) getGracePeriod() uint64 {
+	period := GRACEPERIOD
+	sr.GetObject(GracePeriodKey, &period)
+	return period
+}
+
+func (sr ServiceRegistry) refund(addr string, amount uint64) {
+	account := sr.GetAccount(addr).(ledger.IAccount)
+	account.AddBalance(new(big.Int).SetUint64(amount))
+}
+
+func (sr ServiceRegistry) now() uint64 {
+	return uint64(sr.GetTxTimeStamp() / SecondTime)
+}
+
+func (c AuctionConfig) isAuctionName(name string) bool {
+	return c.MaxNameLen != 0 && uint64(len(name)) <= c.MaxNameLen
+}
+
+func (a *NameAuction) status(now uint64) string {
+	switch {
+	case a.Status == AuctionFinalized:
+		return AuctionFinalized
+	case now < a.BiddingEnd:
+		return AuctionBidding
+	case now < a.RevealEnd:
+		return AuctionRevealing
+	default:
+		return AuctionEnded
+	}
+}
+
+// abandoned reports whether an ended auction can be restarted: nobody revealed
+// a bid, or the winner did not finalize within one more reveal period.
+func (a *NameAuction) abandoned(now uint64) bool {
+	if a.status(now) != AuctionEnded {
+		return false
+	}
+	return a.HighestBidder == "" || now >= a.RevealEnd+(a.RevealEnd-a.BiddingEnd)
+}
@@ -0,0 +1,247 @@
+package contracts
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/meshplus/bitxhub-core/boltvm"
+	"github.com/meshplus/bitxhub-core/boltvm/mock_stub"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/eth-kit/ledger"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	bidder1 = "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b997"
+	bidder2 = "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b998"
+)
+
+func auctionPrepare(t *testing.T, now int64) (*ServiceRegistry, *mock_stub.MockStub) {
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+	mockStub.EXPECT().GetTxTimeStamp().Return(now * SecondTime).AnyTimes()
+	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).AnyTimes()
+	return &ServiceRegistry{mockStub}, mockStub
+}
+
+func TestNameAuction_Status(t *testing.T) {
+	auction := &NameAuction{BiddingEnd: 10, RevealEnd: 20}
+	assert.Equal(t, AuctionBidding, auction.status(5))
+	assert.Equal(t, AuctionRevealing, auction.status(10))
+	assert.Equal(t, AuctionEnded, auction.status(20))
+
+	// no revealed bid
+	assert.True(t, auction.abandoned(20))
+	// the winner can finalize within one more reveal period
+	auction.HighestBidder = bidder1
+	assert.False(t, auction.abandoned(25))
+	assert.True(t, auction.abandoned(30))
+
+	auction.Status = AuctionFinalized
+	assert.Equal(t, AuctionFinalized, auction.status(5))
+
+	assert.Equal(t, SealedBidHash("ab", bidder1, 10, "salt"), SealedBidHash("ab", bidder1, 10, "salt"))
+	assert.NotEqual(t, SealedBidHash("ab", bidder1, 10, "salt"), SealedBidHash("ab", bidder2, 10, "salt"))
+}
+
+func TestServiceRegistry_StartAuction(t *testing.T) {
+	now := int64(1e9)
+	sr, mockStub := auctionPrepare(t, now)
+	config := AuctionConfig{MaxNameLen: 3, BiddingPeriod: 10, RevealPeriod: 10}
+	mockStub.EXPECT().GetObject(AuctionConfigKey, gomock.Any()).SetArg(1, config).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(AuctionKey("ab"), gomock.Any()).SetArg(1, NameAuction{BiddingEnd: uint64(now) + 5, RevealEnd: uint64(now) + 15}).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+
+	// not an auction name
+	res := sr.StartAuction("abcd")
+	assert.False(t, res.Ok, string(res.Result))
+
+	// auction is still in progress
+	res = sr.StartAuction("ab")
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = sr.StartAuction("abc")
+	assert.True(t, res.Ok, string(res.Result))
+}
+
+func TestServiceRegistry_RestartAbandonedAuction(t *testing.T) {
+	now := int64(1e9)
+	sr, mockStub := auctionPrepare(t, now)
+	config := AuctionConfig{MaxNameLen: 3, BiddingPeriod: 10, RevealPeriod: 10}
+	mockStub.EXPECT().GetObject(AuctionConfigKey, gomock.Any()).SetArg(1, config).Return(true).AnyTimes()
+	auction := NameAuction{
+		Name:          "ab",
+		BiddingEnd:    uint64(now) - 20,
+		RevealEnd:     uint64(now) - 10,
+		HighestBidder: bidder1,
+		HighestBid:    50,
+		Bids: map[string]*SealedBid{
+			bidder1: {Deposit: 60, Value: 50, Revealed: true},
+			bidder2: {SealedHash: "0x1", Deposit: 40},
+		},
+	}
+	mockStub.EXPECT().GetObject(AuctionKey("ab"), gomock.Any()).SetArg(1, auction).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	accounts := map[string]ledger.IAccount{bidder1: mockAccount(t), bidder2: mockAccount(t)}
+	mockStub.EXPECT().GetAccount(gomock.Any()).DoAndReturn(func(addr string) interface{} {
+		return accounts[addr]
+	}).AnyTimes()
+
+	// the winner did not finalize, the deposits of the winner and the unrevealed bidder are forfeited
+	res := sr.StartAuction("ab")
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, uint64(0), accounts[bidder1].GetBalance().Uint64())
+	assert.Equal(t, uint64(0), accounts[bidder2].GetBalance().Uint64())
+
+	// the winner can't claim the name after the claim period
+	mockStub.EXPECT().Caller().Return(bidder1).AnyTimes()
+	res = sr.FinalizeAuction("ab", 1, "resolver")
+	assert.False(t, res.Ok, string(res.Result))
+}
+
+func TestServiceRegistry_Bid(t *testing.T) {
+	sr, mockStub := auctionPrepare(t, 100)
+	auction := NameAuction{
+		Name:       "ab",
+		BiddingEnd: 110,
+		RevealEnd:  120,
+		Bids:       map[string]*SealedBid{bidder2: {SealedHash: "0x1", Deposit: 10}},
+	}
+	mockStub.EXPECT().GetObject(AuctionKey("ab"), gomock.Any()).SetArg(1, auction).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	account := mockAccount(t)
+	account.AddBalance(big.NewInt(100))
+	mockStub.EXPECT().GetAccount(gomock.Any()).Return(account).AnyTimes()
+
+	// nonexistent auction
+	mockStub.EXPECT().Caller().Return(bidder1).Times(1)
+	res := sr.Bid("abc", "0x1", 10)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// repeated bid
+	mockStub.EXPECT().Caller().Return(bidder2).Times(1)
+	res = sr.Bid("ab", "0x1", 10)
+	assert.False(t, res.Ok, string(res.Result))
+
+	mockStub.EXPECT().Caller().Return(bidder1).AnyTimes()
+	// not enough balance
+	res = sr.Bid("ab", SealedBidHash("ab", bidder1, 50, "salt"), 200)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = sr.Bid("ab", SealedBidHash("ab", bidder1, 50, "salt"), 60)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, uint64(40), account.GetBalance().Uint64())
+}
+
+func TestServiceRegistry_Reveal(t *testing.T) {
+	sr, mockStub := auctionPrepare(t, 115)
+	auction := NameAuction{
+		Name:          "ab",
+		BiddingEnd:    110,
+		RevealEnd:     120,
+		HighestBidder: bidder2,
+		HighestBid:    30,
+		Bids: map[string]*SealedBid{
+			bidder1: {SealedHash: SealedBidHash("ab", bidder1, 50, "salt"), Deposit: 60},
+			bidder2: {SealedHash: "0x1", Deposit: 40, Value: 30, Revealed: true},
+		},
+	}
+	mockStub.EXPECT().GetObject(AuctionKey("ab"), gomock.Any()).SetArg(1, auction).Return(true).AnyTimes()
+	account := mockAccount(t)
+	mockStub.EXPECT().GetAccount(gomock.Any()).Return(account).AnyTimes()
+
+	// already revealed
+	mockStub.EXPECT().Caller().Return(bidder2).Times(1)
+	res := sr.Reveal("ab", 30, "salt")
+	assert.False(t, res.Ok, string(res.Result))
+
+	mockStub.EXPECT().Caller().Return(bidder1).AnyTimes()
+	// mismatched salt
+	res = sr.Reveal("ab", 50, "pepper")
+	assert.False(t, res.Ok, string(res.Result))
+
+	// outbid the previous highest bidder who gets the deposit back
+	res = sr.Reveal("ab", 50, "salt")
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, uint64(40), account.GetBalance().Uint64())
+}
+
+func TestServiceRegistry_FinalizeAuction(t *testing.T) {
+	sr, mockStub := auctionPrepare(t, 125)
+	auction := NameAuction{
+		Name:          "ab",
+		BiddingEnd:    110,
+		RevealEnd:     120,
+		HighestBidder: bidder1,
+		HighestBid:    50,
+		SecondBid:     30,
+		Bids: map[string]*SealedBid{
+			bidder1: {Deposit: 60, Value: 50, Revealed: true},
+		},
+	}
+	resolver := constant.ServiceResolverContractAddr.Address().String()
+	mockStub.EXPECT().GetObject(AuctionKey("ab"), gomock.Any()).SetArg(1, auction).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(ResolverMap, gomock.Any()).SetArg(1, map[string]bool{resolver: true}).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(PriceLenLevel, gomock.Any()).SetArg(1, PriceLevel{Price2Letter: 1}).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(BitxhubTokenPrice, gomock.Any()).SetArg(1, uint64(1e8)).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(resolver, "SetServDomainData", gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any(), gomock.Any()).Return(boltvm.Success(nil)).AnyTimes()
+	account := mockAccount(t)
+	mockStub.EXPECT().GetAccount(gomock.Any()).Return(account).AnyTimes()
+
+	// not the winner
+	mockStub.EXPECT().Caller().Return(bidder2).Times(1)
+	res := sr.FinalizeAuction("ab", 10, resolver)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// the winner pays the second highest bid
+	mockStub.EXPECT().Caller().Return(bidder1).AnyTimes()
+	res = sr.FinalizeAuction("ab", 10, resolver)
+	assert.True(t, res.Ok, string(res.Result))
+	assert.Equal(t, uint64(30), account.GetBalance().Uint64())
+}
+
+func TestServiceRegistry_RenewInGracePeriod(t *testing.T) {
+	sr, mockStub := auctionPrepare(t, 200)
+	mockStub.EXPECT().Has("ab.hub").Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(Level1Domain, gomock.Any()).SetArg(1, map[string]uint64{"ab.hub": 100}).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject("ab.hub", gomock.Any()).SetArg(1, ServDomainRec{Owner: bidder1}).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(PriceLenLevel, gomock.Any()).SetArg(1, PriceLevel{Price5Letter: 1}).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(BitxhubTokenPrice, gomock.Any()).SetArg(1, uint64(1e8)).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	account := mockAccount(t)
+	account.AddBalance(big.NewInt(100))
+	mockStub.EXPECT().GetAccount(gomock.Any()).Return(account).AnyTimes()
+
+	mockStub.EXPECT().Caller().Return(bidder2).Times(1)
+	res := sr.Renew("ab.hub", 10)
+	assert.False(t, res.Ok, string(res.Result))
+
+	mockStub.EXPECT().Caller().Return(bidder1).Times(1)
+	res = sr.Renew("ab.hub", 10)
+	assert.True(t, res.Ok, string(res.Result))
+}
+
+func TestServiceRegistry_Premium(t *testing.T) {
+	sr, mockStub := auctionPrepare(t, 1000)
+	mockStub.EXPECT().GetObject(GracePeriodKey, gomock.Any()).SetArg(1, uint64(100)).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(PremiumConfigKey, gomock.Any()).SetArg(1, PremiumConfig{StartPremium: 1000, PremiumPeriod: 400}).Return(true).AnyTimes()
+
+	// in grace period
+	assert.Equal(t, uint64(0), sr.premium("ab", 950, 1))
+	// released 100 seconds ago
+	assert.Equal(t, uint64(750), sr.premium("ab", 800, 1))
+	// premium period is over
+	assert.Equal(t, uint64(0), sr.premium("ab", 100, 1))
+	// never registered
+	assert.Equal(t, uint64(0), sr.premium("ab", 0, 1))
+
+	var config PremiumConfig
+	res := sr.GetPremiumConfig()
+	assert.True(t, res.Ok)
+	assert.Nil(t, json.Unmarshal(res.Result, &config))
+	assert.Equal(t, uint64(1000), config.StartPremium)
+}
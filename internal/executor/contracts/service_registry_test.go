@@ -167,7 +167,119 @@ func TestGetSubDomain(t *testing.T) {
 	assert.Equal(t, two, []string{"a.a.hub", "b.a.hub"})
 }
 
-// todo: TestGetAllDomains
+func TestGetAllDomains(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+
+	owner := "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b997"
+	mockStub.EXPECT().GetObject(Level1Domain, gomock.Any()).SetArg(1, map[string]uint64{"a.hub": 100000}).Return(true)
+	mockStub.EXPECT().GetObject("a.hub", gomock.Any()).SetArg(1, ServDomainRec{
+		Owner:     owner,
+		SubDomain: map[string]bool{"b.a.hub": true},
+	}).Return(true)
+	mockStub.EXPECT().GetObject("b.a.hub", gomock.Any()).SetArg(1, ServDomainRec{Owner: owner, Parent: "a.hub"}).Return(true)
+	history := []*OwnershipEvent{{Name: "a.hub", To: owner, Type: OwnershipRegister}}
+	mockStub.EXPECT().GetObject(OwnershipKey("a.hub"), gomock.Any()).SetArg(1, history).Return(true)
+	mockStub.EXPECT().GetObject(OwnershipKey("b.a.hub"), gomock.Any()).Return(false)
+
+	sr := &ServiceRegistry{mockStub}
+	res := sr.GetAllDomains()
+	assert.True(t, res.Ok, string(res.Result))
+	var domains []*ServDomain
+	assert.Nil(t, json.Unmarshal(res.Result, &domains))
+	assert.Equal(t, 3, len(domains))
+	assert.Equal(t, "b.a.hub", domains[1].Name)
+	assert.Equal(t, owner, domains[1].Owner)
+	assert.Equal(t, "a.hub", domains[2].Name)
+	assert.Equal(t, 1, len(domains[2].History))
+}
+
+func TestTransfer(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+
+	owner := "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b997"
+	operator := "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b998"
+	newOwner := "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b999"
+	permissionController := map[string]map[string]bool{owner: {operator: true}}
+	mockStub.EXPECT().Has("a.hub").Return(true).AnyTimes()
+	mockStub.EXPECT().GetTxTimeStamp().Return(int64(100) * SecondTime).AnyTimes()
+	mockStub.EXPECT().CurrentCaller().Return(newOwner).AnyTimes()
+	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).AnyTimes()
+	mockStub.EXPECT().GetObject("a.hub", gomock.Any()).SetArg(1, ServDomainRec{Owner: owner, Parent: RootDomain}).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(PermissionController, gomock.Any()).SetArg(1, permissionController).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(Level1Domain, gomock.Any()).SetArg(1, map[string]uint64{"a.hub": 1000}).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(OwnershipKey("a.hub"), gomock.Any()).Return(false).AnyTimes()
+
+	sr := &ServiceRegistry{mockStub}
+
+	// not authorised
+	mockStub.EXPECT().Caller().Return(newOwner).Times(1)
+	res := sr.Transfer("a.hub", newOwner)
+	assert.False(t, res.Ok, string(res.Result))
+
+	// invalid new owner
+	res = sr.Transfer("a.hub", "abc")
+	assert.False(t, res.Ok, string(res.Result))
+
+	// approved operator
+	mockStub.EXPECT().Caller().Return(operator).Times(1)
+	res = sr.Transfer("a.hub", newOwner)
+	assert.True(t, res.Ok, string(res.Result))
+
+	mockStub.EXPECT().Caller().Return(owner).Times(1)
+	res = sr.Transfer("a.hub", newOwner)
+	assert.True(t, res.Ok, string(res.Result))
+}
+
+func TestSetApprovalForAll(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+
+	owner := "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b997"
+	operator := "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b998"
+	mockStub.EXPECT().Caller().Return(owner).AnyTimes()
+	mockStub.EXPECT().GetObject(PermissionController, gomock.Any()).Return(false).AnyTimes()
+	mockStub.EXPECT().SetObject(PermissionController, map[string]map[string]bool{owner: {operator: true}}).Times(1)
+
+	sr := &ServiceRegistry{mockStub}
+	res := sr.SetApprovalForAll(owner, true)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = sr.SetApprovalForAll(constant.ServiceResolverContractAddr.Address().String(), true)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = sr.SetApprovalForAll(operator, true)
+	assert.True(t, res.Ok, string(res.Result))
+}
+
+func TestSetSubDomainOwner(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+
+	owner := "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b997"
+	newOwner := "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b999"
+	mockStub.EXPECT().Has("a.hub").Return(true).AnyTimes()
+	mockStub.EXPECT().Caller().Return(owner).AnyTimes()
+	mockStub.EXPECT().CurrentCaller().Return(owner).AnyTimes()
+	mockStub.EXPECT().GetTxTimeStamp().Return(int64(100) * SecondTime).AnyTimes()
+	mockStub.EXPECT().SetObject(gomock.Any(), gomock.Any()).AnyTimes()
+	mockStub.EXPECT().GetObject(PermissionController, gomock.Any()).SetArg(1, map[string]map[string]bool{}).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject("a.hub", gomock.Any()).SetArg(1, ServDomainRec{
+		Owner:     owner,
+		Parent:    RootDomain,
+		SubDomain: map[string]bool{"b.a.hub": true},
+	}).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject("b.a.hub", gomock.Any()).SetArg(1, ServDomainRec{Owner: owner, Parent: "a.hub"}).Return(true).AnyTimes()
+	mockStub.EXPECT().GetObject(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+
+	sr := &ServiceRegistry{mockStub}
+	res := sr.SetSubDomainOwner("a.hub", "c", newOwner)
+	assert.False(t, res.Ok, string(res.Result))
+
+	res = sr.SetSubDomainOwner("a.hub", "b", newOwner)
+	assert.True(t, res.Ok, string(res.Result))
+}
 
 func TestIsApproved(t *testing.T) {
 	mockCtl := gomock.NewController(t)
@@ -1,6 +1,7 @@
 package contracts
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -21,6 +22,15 @@ type ServiceResolver struct {
 	boltvm.Stub
 }
 
+// ResolvedDomain is what a BNS name points to at the current height
+type ResolvedDomain struct {
+	Name      string `json:"name"`
+	Owner     string `json:"owner"`
+	ServiceID string `json:"service_id"`
+	Addr      string `json:"addr"`
+	Expires   uint64 `json:"expires"`
+}
+
 type ServDomainData struct {
 	Addr        map[uint64]string `json:"addr"`
 	ServiceName string            `json:"serviceName"`
@@ -156,6 +166,47 @@ func (sr ServiceResolver) GetServiceName(name string) *boltvm.Response {
 	return boltvm.Success([]byte(servDomainData.ServiceName))
 }
 
+// Resolve Get the service id and address of a registered and unexpired domain
+func (sr ServiceResolver) Resolve(name string) *boltvm.Response {
+	if !IsBnsName(name) {
+		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("%s is not a domain name", name))
+	}
+	res := sr.CrossInvoke(constant.ServiceRegistryContractAddr.Address().String(), "Owner", pb.String(name))
+	if !res.Ok {
+		return boltvm.Error(boltvm.BnsErrCode, "The domain id must be registered")
+	}
+	owner := string(res.Result)
+
+	// subdomains live as long as their first-level domain
+	nameArr := strings.Split(name, ".")
+	level1Name := strings.Join(nameArr[len(nameArr)-2:], ".")
+	res = sr.CrossInvoke(constant.ServiceRegistryContractAddr.Address().String(), "GetDomainExpires", pb.String(level1Name))
+	if !res.Ok {
+		return boltvm.Error(boltvm.BnsErrCode, string(res.Result))
+	}
+	expires := binary.BigEndian.Uint64(res.Result)
+	if expires < uint64(sr.GetTxTimeStamp()/SecondTime) {
+		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("The domain %s is expired", level1Name))
+	}
+
+	servDomainData, err := sr.getDataByDomain(name)
+	if err != nil {
+		return boltvm.Error(boltvm.BnsErrCode, "there is not exist key")
+	}
+	resolved := ResolvedDomain{
+		Name:      name,
+		Owner:     owner,
+		ServiceID: servDomainData.ServiceName,
+		Addr:      servDomainData.Addr[1],
+		Expires:   expires,
+	}
+	resolvedBytes, err := json.Marshal(resolved)
+	if err != nil {
+		return boltvm.Error(boltvm.BnsErrCode, fmt.Sprintf("marshal resolved domain error: %v", err))
+	}
+	return boltvm.Success(resolvedBytes)
+}
+
 func (sr ServiceResolver) DeleteServDomainData(name string) *boltvm.Response {
 	if !sr.authorised(name) {
 		return boltvm.Error(boltvm.BnsErrCode, "The domain name does not belong to you")
@@ -174,6 +225,11 @@ func (sr ServiceResolver) DeleteServDomainData(name string) *boltvm.Response {
 	return boltvm.Success(nil)
 }
 
+// IsBnsName reports whether the id is a domain name under the root domain
+func IsBnsName(id string) bool {
+	return strings.HasSuffix(id, "."+RootDomain) && !strings.Contains(id, ":")
+}
+
 func (sr ServiceResolver) getDataByDomain(name string) (ServDomainData, error) {
 	servDomainData := ServDomainData{}
 	exist := sr.GetObject(name, &servDomainData)
@@ -215,7 +271,18 @@ func (sr ServiceResolver) authorised(name string) bool {
 		return false
 	}
 	caller := sr.Caller()
-	return owner == caller || isApprove
+	if owner == caller || isApprove {
+		return true
+	}
+
+	// the caller may be an operator approved by the owner
+	res = sr.CrossInvoke(constant.ServiceRegistryContractAddr.Address().String(), "IsApproved",
+		pb.String(owner), pb.String(caller))
+	if !res.Ok {
+		return false
+	}
+	isOperator, err := strconv.ParseBool(string(res.Result))
+	return err == nil && isOperator
 }
 
 func (sr ServiceResolver) checkDomainAvaliable(name string) bool {
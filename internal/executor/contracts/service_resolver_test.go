@@ -1,6 +1,7 @@
 package contracts
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"testing"
 
@@ -84,3 +85,38 @@ func TestGetReverseName(t *testing.T) {
 	res = sr.GetReverseName("1356:xxx:xx")
 	assert.Equal(t, string(res.Result), "null")
 }
+
+func TestResolve(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+
+	owner := "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b997"
+	expires := make([]byte, 8)
+	binary.BigEndian.PutUint64(expires, 1000)
+	value := ServDomainData{
+		Addr:        map[uint64]string{1: owner},
+		ServiceName: "1356:chain0:service0",
+	}
+	mockStub.EXPECT().CrossInvoke(constant.ServiceRegistryContractAddr.Address().String(), "Owner",
+		pb.String("service.appchain.hub")).Return(boltvm.Success([]byte(owner))).AnyTimes()
+	mockStub.EXPECT().CrossInvoke(constant.ServiceRegistryContractAddr.Address().String(), "GetDomainExpires",
+		pb.String("appchain.hub")).Return(boltvm.Success(expires)).AnyTimes()
+	mockStub.EXPECT().GetObject("service.appchain.hub", gomock.Any()).SetArg(1, value).Return(true).AnyTimes()
+	sr := &ServiceResolver{mockStub}
+
+	res := sr.Resolve("1356:chain0:service0")
+	assert.False(t, res.Ok, string(res.Result))
+
+	// expired
+	mockStub.EXPECT().GetTxTimeStamp().Return(int64(2000) * SecondTime).Times(1)
+	res = sr.Resolve("service.appchain.hub")
+	assert.False(t, res.Ok, string(res.Result))
+
+	mockStub.EXPECT().GetTxTimeStamp().Return(int64(500) * SecondTime).Times(1)
+	res = sr.Resolve("service.appchain.hub")
+	assert.True(t, res.Ok, string(res.Result))
+	resolved := &ResolvedDomain{}
+	assert.Nil(t, json.Unmarshal(res.Result, resolved))
+	assert.Equal(t, "1356:chain0:service0", resolved.ServiceID)
+	assert.Equal(t, owner, resolved.Addr)
+}
@@ -183,8 +183,13 @@ func (t *TransactionManager) BeginInterBitXHub(txId string, timeoutHeight uint64
 
 	change := pb.StatusChange{}
 	var record pb.TransactionRecord
-	ok, _ := t.Get(TxInfoKey(txId))
+	ok, recordData := t.Get(TxInfoKey(txId))
 	if ok {
+		// the record is kept on every bitxhub the IBTP passes through,
+		// notifications from the destination bitxhub update them hop by hop
+		if err := record.Unmarshal(recordData); err != nil {
+			return boltvm.Error(boltvm.TransactionInternalErrCode, err.Error())
+		}
 		bxhProof := &pb.BxhProof{}
 		if err := bxhProof.Unmarshal(proof); err != nil {
 			return boltvm.Error(boltvm.TransactionStateErrCode, fmt.Sprintf("unmarshal proof from dst BitXHub for ibtp %s failed: %s", txId, err.Error()))
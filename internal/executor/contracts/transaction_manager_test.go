@@ -2,6 +2,7 @@ package contracts
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"testing"
 
@@ -333,6 +334,49 @@ func TestTransactionManager_Begin(t *testing.T) {
 	assert.Equal(t, 0, len(statusChange.ChildIBTPIDs))
 }
 
+func TestTransactionManager_BeginInterBitXHub(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	mockStub := mock_stub.NewMockStub(mockCtl)
+
+	id := "bxh1:chain0:service0-bxh3:chain1:service1-1"
+	records := make(map[string][]byte)
+	mockStub.EXPECT().GetCurrentHeight().Return(uint64(100)).AnyTimes()
+	mockStub.EXPECT().CurrentCaller().Return(constant.InterchainContractAddr.Address().String()).AnyTimes()
+	mockStub.EXPECT().Get(gomock.Any()).DoAndReturn(func(key string) (bool, []byte) {
+		data, ok := records[key]
+		return ok, data
+	}).AnyTimes()
+	mockStub.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(func(key string, value []byte) {
+		records[key] = value
+	}).AnyTimes()
+	im := &TransactionManager{Stub: mockStub}
+
+	res := im.BeginInterBitXHub(id, 0, nil, false)
+	assert.True(t, res.Ok, string(res.Result))
+	statusChange := pb.StatusChange{}
+	assert.Nil(t, statusChange.Unmarshal(res.Result))
+	assert.Equal(t, pb.TransactionStatus_BEGIN, statusChange.CurStatus)
+
+	// notification from the destination bitxhub
+	proof, err := (&pb.BxhProof{TxStatus: pb.TransactionStatus_BEGIN_ROLLBACK}).Marshal()
+	assert.Nil(t, err)
+	res = im.BeginInterBitXHub(id, 0, proof, false)
+	assert.True(t, res.Ok, string(res.Result))
+	statusChange = pb.StatusChange{}
+	assert.Nil(t, statusChange.Unmarshal(res.Result))
+	assert.Equal(t, pb.TransactionStatus_BEGIN, statusChange.PrevStatus)
+	assert.Equal(t, pb.TransactionStatus_ROLLBACK, statusChange.CurStatus)
+
+	record := pb.TransactionRecord{}
+	assert.Nil(t, record.Unmarshal(records[TxInfoKey(id)]))
+	assert.Equal(t, pb.TransactionStatus_ROLLBACK, record.Status)
+	assert.Equal(t, uint64(math.MaxUint64), record.Height)
+
+	// rolled back transaction can not be notified again
+	res = im.BeginInterBitXHub(id, 0, proof, false)
+	assert.False(t, res.Ok, string(res.Result))
+}
+
 func TestTransactionManager_Report(t *testing.T) {
 	mockCtl := gomock.NewController(t)
 	mockStub := mock_stub.NewMockStub(mockCtl)
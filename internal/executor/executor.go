@@ -23,6 +23,7 @@ import (
 	"github.com/meshplus/bitxhub/internal/repo"
 	"github.com/meshplus/bitxhub/pkg/proof"
 	"github.com/meshplus/bitxhub/pkg/vm/boltvm"
+	"github.com/meshplus/bitxhub/pkg/vm/evm"
 	vm "github.com/meshplus/eth-kit/evm"
 	ledger2 "github.com/meshplus/eth-kit/ledger"
 	"github.com/sirupsen/logrus"
@@ -61,6 +62,7 @@ type BlockExecutor struct {
 	cancel             context.CancelFunc
 
 	evm         *vm.EVM
+	evmTracer   *evm.StaticTracer
 	evmMaxSize  uint64
 	evmChainCfg *params.ChainConfig
 	gasLimit    uint64
@@ -118,8 +120,10 @@ func New(chainLedger *ledger.Ledger, logger logrus.FieldLogger, client *appchain
 		blockExecutor.evmMaxSize = config.EvmMaxSize
 	}
 
+	evm.RegisterBoltPrecompiles()
+	blockExecutor.evmTracer = evm.NewStaticTracer()
 	blockExecutor.evm = newEvm(1, uint64(0), blockExecutor.evmChainCfg, blockExecutor.ledger,
-		blockExecutor.ledger.ChainLedger, blockExecutor.admins[0], blockExecutor.evmMaxSize)
+		blockExecutor.ledger.ChainLedger, blockExecutor.admins[0], blockExecutor.evmMaxSize, blockExecutor.evmTracer)
 
 	blockExecutor.txsExecutor = txsExecutor(blockExecutor.applyTx, blockExecutor.registerBoltContracts, logger)
 
@@ -215,26 +219,13 @@ func (exec *BlockExecutor) ApplyReadonlyTransactions(txs []pb.Transaction) []*pb
 	exec.lock.Lock()
 	defer exec.lock.Unlock()
 
-	meta := exec.ledger.GetChainMeta()
-	block, err := exec.ledger.GetBlock(meta.Height, false)
+	meta, block, err := exec.prepareReadonlyState()
 	if err != nil {
-		exec.logger.Errorf("fail to get block at %d: %v", meta.Height, err.Error())
+		exec.logger.Error(err.Error())
 		return nil
 	}
 
-	switch sl := exec.ledger.StateLedger.(type) {
-	case *ledger2.ComplexStateLedger:
-		newSl, err := sl.StateAt(block.BlockHeader.StateRoot)
-		if err != nil {
-			exec.logger.Errorf("fail to new state ledger at %s: %v", meta.BlockHash.String(), err.Error())
-			return nil
-		}
-		exec.ledger.StateLedger = newSl
-	}
-
-	exec.ledger.PrepareBlock(meta.BlockHash, meta.Height)
-	exec.evm = newEvm(meta.Height, uint64(block.BlockHeader.Timestamp), exec.evmChainCfg,
-		exec.ledger.StateLedger, exec.ledger.ChainLedger, exec.admins[0], exec.evmMaxSize)
+	exec.prepareReadonlyEvm(meta, block)
 	for i, tx := range txs {
 		receipt := exec.applyTransaction(i, tx, "", nil)
 
@@ -251,6 +242,74 @@ func (exec *BlockExecutor) ApplyReadonlyTransactions(txs []pb.Transaction) []*pb
 	return receipts
 }
 
+// SimulateTransaction executes the transaction against the state of the latest block as
+// ApplyReadonlyTransactions does, and returns the changes it makes to the state along with the receipt
+func (exec *BlockExecutor) SimulateTransaction(tx pb.Transaction) (*SimulationResult, error) {
+	// interchain transactions executed in parallel need the simple ledger to record changes
+	if exec.supportParallel && tx.IsIBTP() {
+		return nil, fmt.Errorf("simulating interchain transaction is not supported by parallel executor")
+	}
+
+	exec.lock.Lock()
+	defer exec.lock.Unlock()
+
+	meta, block, err := exec.prepareReadonlyState()
+	if err != nil {
+		return nil, err
+	}
+
+	// charge the gas fee as the block executor does, the view executor is created without gas price
+	gasPrice := exec.bxhGasPrice
+	exec.bxhGasPrice = new(big.Int).SetUint64(exec.config.Genesis.BvmGasPrice)
+	defer func() {
+		exec.bxhGasPrice = gasPrice
+	}()
+
+	recorder := newStateRecorder(exec.ledger.StateLedger)
+	exec.ledger.StateLedger = recorder
+	defer func() {
+		exec.ledger.StateLedger = recorder.StateLedger
+		// clear potential write to ledger
+		exec.ledger.Clear()
+	}()
+
+	exec.prepareReadonlyEvm(meta, block)
+	receipt := exec.applyTransaction(0, tx, "", nil)
+
+	return &SimulationResult{
+		Receipt:   receipt,
+		Events:    exec.ledger.Events(tx.GetHash().String()),
+		GasUsed:   receipt.GasUsed,
+		StateDiff: recorder.diff(),
+	}, nil
+}
+
+// prepareReadonlyState switches the complex state ledger to the state of the latest block
+func (exec *BlockExecutor) prepareReadonlyState() (*pb.ChainMeta, *pb.Block, error) {
+	meta := exec.ledger.GetChainMeta()
+	block, err := exec.ledger.GetBlock(meta.Height, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fail to get block at %d: %w", meta.Height, err)
+	}
+
+	switch sl := exec.ledger.StateLedger.(type) {
+	case *ledger2.ComplexStateLedger:
+		newSl, err := sl.StateAt(block.BlockHeader.StateRoot)
+		if err != nil {
+			return nil, nil, fmt.Errorf("fail to new state ledger at %s: %w", meta.BlockHash.String(), err)
+		}
+		exec.ledger.StateLedger = newSl
+	}
+
+	return meta, block, nil
+}
+
+func (exec *BlockExecutor) prepareReadonlyEvm(meta *pb.ChainMeta, block *pb.Block) {
+	exec.ledger.PrepareBlock(meta.BlockHash, meta.Height)
+	exec.evm = newEvm(meta.Height, uint64(block.BlockHeader.Timestamp), exec.evmChainCfg,
+		exec.ledger.StateLedger, exec.ledger.ChainLedger, exec.admins[0], exec.evmMaxSize, exec.evmTracer)
+}
+
 func (exec *BlockExecutor) listenExecuteEvent() {
 	for {
 		select {
@@ -396,6 +455,12 @@ func (exec *BlockExecutor) registerBoltContracts() map[string]agency.Contract {
 			Address:  constant.EthHeaderMgrContractAddr.Address().String(),
 			Contract: contracts.NewEthHeaderManager(exec.client.EthOracle),
 		},
+		{
+			Enabled:  true,
+			Name:     "asset bridge service",
+			Address:  constant.AssetExchangeContractAddr.Address().String(),
+			Contract: &contracts.AssetBridge{},
+		},
 		{
 			Enabled:  true,
 			Name:     "node manager service",
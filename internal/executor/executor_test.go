@@ -33,6 +33,7 @@ import (
 	"github.com/meshplus/bitxhub/internal/ledger/mock_ledger"
 	"github.com/meshplus/bitxhub/internal/model/events"
 	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/meshplus/bitxhub/pkg/signer"
 	ledger2 "github.com/meshplus/eth-kit/ledger"
 	types2 "github.com/meshplus/eth-kit/types"
 	types3 "github.com/meshplus/eth-kit/types"
@@ -628,6 +629,81 @@ func TestBlockExecutor_ExecuteBlock_Transfer(t *testing.T) {
 	require.Nil(t, receipts[0].Ret)
 }
 
+func TestBlockExecutor_SimulateTransaction(t *testing.T) {
+	config := generateMockConfig(t)
+	repoRoot, err := ioutil.TempDir("", "executor")
+	require.Nil(t, err)
+
+	blockchainStorage, err := leveldb.New(filepath.Join(repoRoot, "storage"))
+	require.Nil(t, err)
+	ldb, err := leveldb.New(filepath.Join(repoRoot, "ledger"))
+	require.Nil(t, err)
+
+	accountCache, err := ledger.NewAccountCache()
+	assert.Nil(t, err)
+	logger := log.NewWithModule("executor_test")
+	blockFile, err := blockfile.NewBlockFile(repoRoot, logger)
+	assert.Nil(t, err)
+	ldg, err := ledger.New(createMockRepo(t), blockchainStorage, ldb, blockFile, accountCache, log.NewWithModule("ledger"))
+	require.Nil(t, err)
+
+	_, from := loadAdminKey(t)
+	config.Genesis.BvmGasPrice = 5000000
+	balance := new(big.Int).SetInt64(GasNormalTx*5000000 + 10)
+	ldg.SetBalance(from, balance)
+	account, journal := ldg.FlushDirtyData()
+	err = ldg.Commit(1, account, journal)
+	require.Nil(t, err)
+	err = ldg.PersistExecutionResult(mockBlock(1, nil), nil, &pb.InterchainMeta{})
+	require.Nil(t, err)
+
+	// the gas fee is charged by the gas price in genesis as the view executor has none
+	exec, err := New(ldg, log.NewWithModule("executor"), &appchain.Client{}, config, big.NewInt(0))
+	require.Nil(t, err)
+
+	tx := mockTransferTx(t)
+	result, err := exec.SimulateTransaction(tx)
+	require.Nil(t, err)
+	require.Equal(t, pb.Receipt_SUCCESS, result.Receipt.Status)
+	require.EqualValues(t, GasNormalTx, result.GasUsed)
+
+	diffs := make(map[string]*AccountDiff)
+	for _, diff := range result.StateDiff {
+		diffs[diff.Address] = diff
+	}
+	require.Equal(t, 3, len(diffs))
+
+	fromDiff := diffs[from.String()]
+	require.NotNil(t, fromDiff)
+	require.Equal(t, &BalanceDiff{From: balance.String(), To: "9"}, fromDiff.Balance)
+	require.Equal(t, &NonceDiff{From: 0, To: 1}, fromDiff.Nonce)
+
+	toDiff := diffs[tx.GetTo().String()]
+	require.NotNil(t, toDiff)
+	require.Equal(t, &BalanceDiff{From: "0", To: "1"}, toDiff.Balance)
+	require.Nil(t, toDiff.Nonce)
+
+	adminDiff := diffs[types.NewAddress([]byte{byte(1)}).String()]
+	require.NotNil(t, adminDiff)
+	require.Equal(t, &BalanceDiff{From: "0", To: fmt.Sprintf("%d", GasNormalTx*5000000)}, adminDiff.Balance)
+
+	// the state is not changed by simulation
+	require.Equal(t, balance, ldg.GetBalance(from))
+	require.EqualValues(t, 0, ldg.GetNonce(from))
+	require.EqualValues(t, 0, ldg.GetBalance(tx.GetTo()).Uint64())
+
+	// failed transactions are simulated as well
+	tx = mockTransferTx(t)
+	ldg.SetBalance(from, big.NewInt(0))
+	result, err = exec.SimulateTransaction(tx)
+	require.Nil(t, err)
+	require.Equal(t, pb.Receipt_FAILED, result.Receipt.Status)
+	require.Equal(t, 1, len(result.StateDiff))
+	require.Equal(t, from.String(), result.StateDiff[0].Address)
+	require.Nil(t, result.StateDiff[0].Balance)
+	require.Equal(t, &NonceDiff{From: 0, To: 1}, result.StateDiff[0].Nonce)
+}
+
 func mockTransferTx(t *testing.T) pb.Transaction {
 	privKey, from := loadAdminKey(t)
 	to := randAddress(t)
@@ -844,7 +920,7 @@ BcNwjTDCxyxLNjFKQfMAc6sY6iJs+Ma59WZyC/4uhjE=
 
 	rep := &repo.Repo{
 		Key: &repo.Key{
-			PrivKey: privKey,
+			PrivKey: signer.NewLocalSigner(privKey),
 			Address: address.String(),
 		},
 		Config: &repo.Config{},
@@ -19,6 +19,7 @@ import (
 	"github.com/meshplus/bitxhub/pkg/utils"
 	"github.com/meshplus/bitxhub/pkg/vm"
 	"github.com/meshplus/bitxhub/pkg/vm/boltvm"
+	"github.com/meshplus/bitxhub/pkg/vm/evm"
 	"github.com/meshplus/bitxhub/pkg/vm/wasm"
 	"github.com/meshplus/bitxhub/pkg/vm/wasm/vmledger"
 
@@ -141,7 +142,7 @@ func (exec *BlockExecutor) processExecuteEvent(blockWrapper *BlockWrapper) {
 	}).Infof("verify proof elapsed")
 
 	exec.evm = newEvm(block.Height(), uint64(block.BlockHeader.Timestamp), exec.evmChainCfg, exec.ledger.StateLedger,
-		exec.ledger.ChainLedger, exec.admins[0], exec.evmMaxSize)
+		exec.ledger.ChainLedger, exec.admins[0], exec.evmMaxSize, exec.evmTracer)
 
 	exec.ledger.PrepareBlock(block.BlockHash, block.Height())
 	current2 := time.Now()
@@ -600,6 +601,7 @@ func (exec *BlockExecutor) applyBxhTransaction(i int, tx *pb.BxhTransaction, inv
 			context := make(map[string]interface{})
 			store := wasm.NewStore()
 			libs := vmledger.NewLedgerWasmLibs(context, store)
+			context[vmledger.CROSS_INVOKER] = boltvm.NewReadOnlyInvoker(ctx, exec.validationEngine, exec.getContracts(opt))
 			instance, err = wasm.New(ctx, libs, context, store)
 			if err != nil {
 				return nil, GasFailedTx, err
@@ -618,7 +620,7 @@ func (exec *BlockExecutor) applyBxhTransaction(i int, tx *pb.BxhTransaction, inv
 	}
 }
 
-func (exec *BlockExecutor) applyEthTransaction(_ int, tx *types2.EthTransaction) *pb.Receipt {
+func (exec *BlockExecutor) applyEthTransaction(i int, tx *types2.EthTransaction) *pb.Receipt {
 	receipt := &pb.Receipt{
 		Version: tx.GetVersion(),
 		TxHash:  tx.GetHash(),
@@ -629,7 +631,9 @@ func (exec *BlockExecutor) applyEthTransaction(_ int, tx *types2.EthTransaction)
 	statedb := exec.ledger.StateLedger
 	txContext := vm1.NewEVMTxContext(msg)
 	snapshot := statedb.Snapshot()
-	exec.evm.Reset(txContext, exec.ledger.StateLedger)
+	ctx := vm.NewContext(tx, uint64(i), nil, exec.currentHeight, exec.ledger, exec.logger, exec.config.EnableAudit, nil)
+	invoker := boltvm.NewEVMInvoker(ctx, exec.validationEngine, exec.registerBoltContracts())
+	exec.evm.Reset(txContext, evm.WithBoltInvoker(exec.ledger.StateLedger, invoker, exec.evmTracer))
 	exec.logger.Debugf("msg gas: %v", msg.Gas())
 	result, err := vm1.ApplyMessage(exec.evm, msg, gp)
 	if err != nil {
@@ -755,10 +759,10 @@ func (exec *BlockExecutor) getChanger(opt *agency.TxOpt) *ledger.ChangeInstance
 	return nil
 }
 
-func newEvm(number uint64, timestamp uint64, chainCfg *params.ChainConfig, db ledger2.StateLedger, chainLedger ledger2.ChainLedger, admin string, maxCodeSize uint64) *vm1.EVM {
+func newEvm(number uint64, timestamp uint64, chainCfg *params.ChainConfig, db ledger2.StateLedger, chainLedger ledger2.ChainLedger, admin string, maxCodeSize uint64, tracer vm1.Tracer) *vm1.EVM {
 	blkCtx := vm1.NewEVMBlockContext(number, timestamp, maxCodeSize, db, chainLedger, admin)
 
-	return vm1.NewEVM(blkCtx, vm1.TxContext{}, db, chainCfg, vm1.Config{})
+	return vm1.NewEVM(blkCtx, vm1.TxContext{}, db, chainCfg, vm1.Config{Debug: true, Tracer: tracer})
 }
 
 func (exec *BlockExecutor) payGasFee(tx pb.Transaction, gasUsed uint64) error {
@@ -830,6 +834,27 @@ func (exec *BlockExecutor) getTimeoutList(height uint64) []string {
 	return list
 }
 
+// resolvedIBTP returns the ibtp of the transaction whose BNS names are replaced by the service ids
+// which the interchain contract resolved them to
+func (exec *BlockExecutor) resolvedIBTP(tx pb.Transaction) *pb.IBTP {
+	ibtp := tx.GetIBTP()
+	if !contracts.IsBnsName(ibtp.From) && !contracts.IsBnsName(ibtp.To) {
+		return ibtp
+	}
+	ok, data := exec.ledger.GetState(constant.InterchainContractAddr.Address(), []byte(contracts.ResolvedIBTPKey(tx.GetHash().String())))
+	if !ok {
+		return ibtp
+	}
+	names := &contracts.ResolvedIBTP{}
+	if err := json.Unmarshal(data, names); err != nil {
+		return ibtp
+	}
+	resolved := *ibtp
+	resolved.From = names.From
+	resolved.To = names.To
+	return &resolved
+}
+
 func (exec *BlockExecutor) setTimeoutList(height uint64, txList []pb.Transaction, invalidMap map[string]bool, failMap map[string]bool, bxhId string) error {
 	addTimeoutListMap := make(map[uint64]string, len(txList))
 	removeTimeoutListMap := make(map[uint64]string, len(txList))
@@ -842,7 +867,7 @@ func (exec *BlockExecutor) setTimeoutList(height uint64, txList []pb.Transaction
 			if !tx.IsIBTP() {
 				continue
 			}
-			ibtp := tx.GetIBTP()
+			ibtp := exec.resolvedIBTP(tx)
 
 			// if bxh is destAppchain, needn't add into timeoutList
 			if exec.isDstChainFromBxh(ibtp.To, bxhId) {
@@ -139,6 +139,11 @@ func (oracle *EthLightChainOracle) VerifyProof(receipt *types.Receipt, proof []b
 		return fmt.Errorf("not enough confirmed")
 	}
 
+	return VerifyReceiptProof(header.ReceiptHash, receipt, proof)
+}
+
+// VerifyReceiptProof checks the Merkle-Patricia proof of the receipt against the receipt root of its block
+func VerifyReceiptProof(receiptHash common.Hash, receipt *types.Receipt, proof []byte) error {
 	keyBuf := bytes.Buffer{}
 	keyBuf.Reset()
 	if err := rlp.Encode(&keyBuf, receipt.TransactionIndex); err != nil {
@@ -148,7 +153,7 @@ func (oracle *EthLightChainOracle) VerifyProof(receipt *types.Receipt, proof []b
 	if err := rlp.DecodeBytes(proof, nodeList); err != nil {
 		return err
 	}
-	value, err := trie.VerifyProof(header.ReceiptHash, keyBuf.Bytes(), nodeList.NodeSet())
+	value, err := trie.VerifyProof(receiptHash, keyBuf.Bytes(), nodeList.NodeSet())
 	if err != nil {
 		return err
 	}
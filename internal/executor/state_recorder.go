@@ -0,0 +1,306 @@
+package executor
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/meshplus/bitxhub-kit/types"
+	ledger2 "github.com/meshplus/eth-kit/ledger"
+)
+
+// stateRecorder wraps the state ledger and records the balances, nonces, code and storage
+// of accounts before they are first modified, so the changes can be diffed after execution
+type stateRecorder struct {
+	ledger2.StateLedger
+
+	lock     sync.Mutex
+	accounts map[string]*accountRecord
+}
+
+type accountRecord struct {
+	address *types.Address
+	balance *big.Int
+	nonce   *uint64
+	code    *[]byte
+	storage map[string]*storageRecord
+}
+
+type storageRecord struct {
+	key   []byte
+	exist bool
+	value []byte
+}
+
+var _ ledger2.StateLedger = (*stateRecorder)(nil)
+
+func newStateRecorder(stateLedger ledger2.StateLedger) *stateRecorder {
+	return &stateRecorder{
+		StateLedger: stateLedger,
+		accounts:    make(map[string]*accountRecord),
+	}
+}
+
+func (r *stateRecorder) GetOrCreateAccount(addr *types.Address) ledger2.IAccount {
+	return r.wrapAccount(r.StateLedger.GetOrCreateAccount(addr))
+}
+
+func (r *stateRecorder) GetAccount(addr *types.Address) ledger2.IAccount {
+	return r.wrapAccount(r.StateLedger.GetAccount(addr))
+}
+
+func (r *stateRecorder) SetBalance(addr *types.Address, value *big.Int) {
+	r.recordBalance(addr)
+	r.StateLedger.SetBalance(addr, value)
+}
+
+func (r *stateRecorder) SetState(addr *types.Address, key []byte, value []byte, changer interface{}) {
+	r.recordState(addr, key)
+	r.StateLedger.SetState(addr, key, value, changer)
+}
+
+func (r *stateRecorder) AddState(addr *types.Address, key []byte, value []byte) {
+	r.recordState(addr, key)
+	r.StateLedger.AddState(addr, key, value)
+}
+
+func (r *stateRecorder) SetCode(addr *types.Address, code []byte) {
+	r.recordCode(addr)
+	r.StateLedger.SetCode(addr, code)
+}
+
+func (r *stateRecorder) SetNonce(addr *types.Address, nonce uint64) {
+	r.recordNonce(addr)
+	r.StateLedger.SetNonce(addr, nonce)
+}
+
+func (r *stateRecorder) CreateEVMAccount(addr common.Address) {
+	address := types.NewAddress(addr.Bytes())
+	r.recordBalance(address)
+	r.recordNonce(address)
+	r.recordCode(address)
+	r.StateLedger.CreateEVMAccount(addr)
+}
+
+func (r *stateRecorder) SubEVMBalance(addr common.Address, amount *big.Int) {
+	r.recordBalance(types.NewAddress(addr.Bytes()))
+	r.StateLedger.SubEVMBalance(addr, amount)
+}
+
+func (r *stateRecorder) AddEVMBalance(addr common.Address, amount *big.Int) {
+	r.recordBalance(types.NewAddress(addr.Bytes()))
+	r.StateLedger.AddEVMBalance(addr, amount)
+}
+
+func (r *stateRecorder) SetEVMNonce(addr common.Address, nonce uint64) {
+	r.recordNonce(types.NewAddress(addr.Bytes()))
+	r.StateLedger.SetEVMNonce(addr, nonce)
+}
+
+func (r *stateRecorder) SetEVMCode(addr common.Address, code []byte) {
+	r.recordCode(types.NewAddress(addr.Bytes()))
+	r.StateLedger.SetEVMCode(addr, code)
+}
+
+// SetEVMState records the evm storage by the same key as SetState, both ledgers store it that way
+func (r *stateRecorder) SetEVMState(addr common.Address, key, value common.Hash) {
+	r.recordState(types.NewAddress(addr.Bytes()), key.Bytes())
+	r.StateLedger.SetEVMState(addr, key, value)
+}
+
+func (r *stateRecorder) SuisideEVM(addr common.Address) bool {
+	r.recordBalance(types.NewAddress(addr.Bytes()))
+	return r.StateLedger.SuisideEVM(addr)
+}
+
+func (r *stateRecorder) wrapAccount(account ledger2.IAccount) ledger2.IAccount {
+	if account == nil {
+		return nil
+	}
+
+	return &recordedAccount{IAccount: account, recorder: r}
+}
+
+func (r *stateRecorder) getRecord(addr *types.Address) *accountRecord {
+	record, ok := r.accounts[addr.String()]
+	if !ok {
+		record = &accountRecord{
+			address: types.NewAddress(addr.Bytes()),
+			storage: make(map[string]*storageRecord),
+		}
+		r.accounts[addr.String()] = record
+	}
+
+	return record
+}
+
+func (r *stateRecorder) recordBalance(addr *types.Address) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	record := r.getRecord(addr)
+	if record.balance == nil {
+		record.balance = new(big.Int).Set(r.StateLedger.GetBalance(addr))
+	}
+}
+
+func (r *stateRecorder) recordNonce(addr *types.Address) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	record := r.getRecord(addr)
+	if record.nonce == nil {
+		nonce := r.StateLedger.GetNonce(addr)
+		record.nonce = &nonce
+	}
+}
+
+func (r *stateRecorder) recordCode(addr *types.Address) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	record := r.getRecord(addr)
+	if record.code == nil {
+		code := common.CopyBytes(r.StateLedger.GetCode(addr))
+		record.code = &code
+	}
+}
+
+func (r *stateRecorder) recordState(addr *types.Address, key []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	record := r.getRecord(addr)
+	if _, ok := record.storage[string(key)]; !ok {
+		exist, value := r.StateLedger.GetState(addr, key)
+		record.storage[string(key)] = &storageRecord{
+			key:   common.CopyBytes(key),
+			exist: exist,
+			value: common.CopyBytes(value),
+		}
+	}
+}
+
+// diff compares the recorded values with the current state, the accounts are sorted by address
+// and the storage by key, values changed and then restored are not reported
+func (r *stateRecorder) diff() []*AccountDiff {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	addrs := make([]string, 0, len(r.accounts))
+	for addr := range r.accounts {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	diffs := make([]*AccountDiff, 0)
+	for _, addr := range addrs {
+		record := r.accounts[addr]
+		diff := &AccountDiff{Address: addr}
+		changed := false
+
+		if record.balance != nil {
+			balance := r.StateLedger.GetBalance(record.address)
+			if record.balance.Cmp(balance) != 0 {
+				diff.Balance = &BalanceDiff{From: record.balance.String(), To: balance.String()}
+				changed = true
+			}
+		}
+
+		if record.nonce != nil {
+			nonce := r.StateLedger.GetNonce(record.address)
+			if *record.nonce != nonce {
+				diff.Nonce = &NonceDiff{From: *record.nonce, To: nonce}
+				changed = true
+			}
+		}
+
+		if record.code != nil {
+			code := r.StateLedger.GetCode(record.address)
+			if !bytes.Equal(*record.code, code) {
+				diff.Code = &CodeDiff{From: codeHash(*record.code), To: codeHash(code)}
+				changed = true
+			}
+		}
+
+		keys := make([]string, 0, len(record.storage))
+		for key := range record.storage {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			prev := record.storage[key]
+			exist, value := r.StateLedger.GetState(record.address, prev.key)
+			if prev.exist == exist && bytes.Equal(prev.value, value) {
+				continue
+			}
+			storageDiff := &StorageDiff{Key: prev.key}
+			if prev.exist {
+				storageDiff.From = prev.value
+			}
+			if exist {
+				storageDiff.To = common.CopyBytes(value)
+			}
+			diff.Storage = append(diff.Storage, storageDiff)
+			changed = true
+		}
+
+		if changed {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	return diffs
+}
+
+func codeHash(code []byte) string {
+	if len(code) == 0 {
+		return ""
+	}
+
+	return crypto.Keccak256Hash(code).String()
+}
+
+// recordedAccount records the values of the account before they are modified through it
+type recordedAccount struct {
+	ledger2.IAccount
+	recorder *stateRecorder
+}
+
+func (a *recordedAccount) SetState(key []byte, value []byte, changer interface{}) {
+	a.recorder.recordState(a.GetAddress(), key)
+	a.IAccount.SetState(key, value, changer)
+}
+
+func (a *recordedAccount) AddState(key []byte, value []byte) {
+	a.recorder.recordState(a.GetAddress(), key)
+	a.IAccount.AddState(key, value)
+}
+
+func (a *recordedAccount) SetCodeAndHash(code []byte) {
+	a.recorder.recordCode(a.GetAddress())
+	a.IAccount.SetCodeAndHash(code)
+}
+
+func (a *recordedAccount) SetNonce(nonce uint64) {
+	a.recorder.recordNonce(a.GetAddress())
+	a.IAccount.SetNonce(nonce)
+}
+
+func (a *recordedAccount) SetBalance(balance *big.Int) {
+	a.recorder.recordBalance(a.GetAddress())
+	a.IAccount.SetBalance(balance)
+}
+
+func (a *recordedAccount) SubBalance(amount *big.Int) {
+	a.recorder.recordBalance(a.GetAddress())
+	a.IAccount.SubBalance(amount)
+}
+
+func (a *recordedAccount) AddBalance(amount *big.Int) {
+	a.recorder.recordBalance(a.GetAddress())
+	a.IAccount.AddBalance(amount)
+}
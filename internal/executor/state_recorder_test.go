@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/meshplus/bitxhub-kit/log"
+	"github.com/meshplus/bitxhub-kit/storage/blockfile"
+	"github.com/meshplus/bitxhub-kit/storage/leveldb"
+	"github.com/meshplus/bitxhub/internal/ledger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateRecorder_Diff(t *testing.T) {
+	repoRoot, err := ioutil.TempDir("", "executor")
+	require.Nil(t, err)
+
+	blockchainStorage, err := leveldb.New(filepath.Join(repoRoot, "storage"))
+	require.Nil(t, err)
+	ldb, err := leveldb.New(filepath.Join(repoRoot, "ledger"))
+	require.Nil(t, err)
+	accountCache, err := ledger.NewAccountCache()
+	require.Nil(t, err)
+	blockFile, err := blockfile.NewBlockFile(repoRoot, log.NewWithModule("executor_test"))
+	require.Nil(t, err)
+	ldg, err := ledger.New(createMockRepo(t), blockchainStorage, ldb, blockFile, accountCache, log.NewWithModule("ledger"))
+	require.Nil(t, err)
+
+	addr1 := randAddress(t)
+	addr2 := randAddress(t)
+	code := []byte("code")
+	ldg.SetBalance(addr1, big.NewInt(100))
+	ldg.SetState(addr1, []byte("a"), []byte("1"), nil)
+	ldg.SetState(addr1, []byte("b"), []byte("2"), nil)
+	ldg.SetState(addr1, []byte("c"), []byte("3"), nil)
+	ldg.SetCode(addr2, code)
+
+	recorder := newStateRecorder(ldg.StateLedger)
+	require.Equal(t, 0, len(recorder.diff()))
+
+	// changes through both the ledger and the accounts are recorded
+	recorder.SetBalance(addr1, big.NewInt(90))
+	recorder.SetNonce(addr1, 1)
+	recorder.SetState(addr1, []byte("a"), []byte("10"), nil)
+	recorder.GetOrCreateAccount(addr1).SetState([]byte("b"), nil, nil)
+	recorder.GetOrCreateAccount(addr1).AddState([]byte("d"), []byte("4"))
+	recorder.GetOrCreateAccount(addr2).AddBalance(big.NewInt(10))
+	evmKey := common.BytesToHash([]byte("key"))
+	evmValue := common.BytesToHash([]byte("value"))
+	recorder.SetEVMState(common.BytesToAddress(addr2.Bytes()), evmKey, evmValue)
+	recorder.SetEVMCode(common.BytesToAddress(addr2.Bytes()), []byte("new code"))
+
+	// values restored are not reported
+	recorder.SetState(addr1, []byte("c"), []byte("30"), nil)
+	recorder.SetState(addr1, []byte("c"), []byte("3"), nil)
+
+	diffs := make(map[string]*AccountDiff)
+	for _, diff := range recorder.diff() {
+		diffs[diff.Address] = diff
+	}
+	require.Equal(t, 2, len(diffs))
+
+	diff1 := diffs[addr1.String()]
+	require.NotNil(t, diff1)
+	require.Equal(t, &BalanceDiff{From: "100", To: "90"}, diff1.Balance)
+	require.Equal(t, &NonceDiff{From: 0, To: 1}, diff1.Nonce)
+	require.Nil(t, diff1.Code)
+	require.Equal(t, []*StorageDiff{
+		{Key: []byte("a"), From: []byte("1"), To: []byte("10")},
+		{Key: []byte("b"), From: []byte("2")},
+		{Key: []byte("d"), To: []byte("4")},
+	}, diff1.Storage)
+
+	diff2 := diffs[addr2.String()]
+	require.NotNil(t, diff2)
+	require.Equal(t, &BalanceDiff{From: "0", To: "10"}, diff2.Balance)
+	require.Nil(t, diff2.Nonce)
+	require.Equal(t, &CodeDiff{
+		From: crypto.Keccak256Hash(code).String(),
+		To:   crypto.Keccak256Hash([]byte("new code")).String(),
+	}, diff2.Code)
+	require.Equal(t, []*StorageDiff{{Key: evmKey.Bytes(), To: evmValue.Bytes()}}, diff2.Storage)
+}
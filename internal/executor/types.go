@@ -1,6 +1,7 @@
 package executor
 
 import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/meshplus/bitxhub-core/agency"
 	"github.com/meshplus/bitxhub-model/pb"
@@ -20,6 +21,9 @@ type Executor interface {
 	// ApplyReadonlyTransactions execute readonly tx
 	ApplyReadonlyTransactions(txs []pb.Transaction) []*pb.Receipt
 
+	// SimulateTransaction execute tx against a copy of the state and return the state diff
+	SimulateTransaction(tx pb.Transaction) (*SimulationResult, error)
+
 	// SubscribeBlockEvent
 	SubscribeBlockEvent(chan<- events.ExecutedEvent) event.Subscription
 
@@ -37,3 +41,45 @@ type Executor interface {
 
 	GetBoltContracts() map[string]agency.Contract
 }
+
+// SimulationResult is the result of a transaction executed against a copy of the state
+type SimulationResult struct {
+	Receipt   *pb.Receipt    `json:"receipt"`
+	Events    []*pb.Event    `json:"events"`
+	GasUsed   uint64         `json:"gas_used"`
+	StateDiff []*AccountDiff `json:"state_diff"`
+}
+
+// AccountDiff is the changes a transaction makes to an account, fields which are not changed are omitted
+type AccountDiff struct {
+	Address string         `json:"address"`
+	Balance *BalanceDiff   `json:"balance,omitempty"`
+	Nonce   *NonceDiff     `json:"nonce,omitempty"`
+	Code    *CodeDiff      `json:"code,omitempty"`
+	Storage []*StorageDiff `json:"storage,omitempty"`
+}
+
+// BalanceDiff is the balance before and after the transaction in decimal
+type BalanceDiff struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// NonceDiff is the nonce before and after the transaction
+type NonceDiff struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+// CodeDiff is the hash of the code before and after the transaction, empty if there is no code
+type CodeDiff struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// StorageDiff is the value of a storage key before and after the transaction, omitted if the key does not exist
+type StorageDiff struct {
+	Key  hexutil.Bytes `json:"key"`
+	From hexutil.Bytes `json:"from,omitempty"`
+	To   hexutil.Bytes `json:"to,omitempty"`
+}
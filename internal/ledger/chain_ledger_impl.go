@@ -339,7 +339,7 @@ func (l *ChainLedgerImpl) prepareTransactions(batcher storage.Batch, block *pb.B
 func (l *ChainLedgerImpl) prepareBlock(batcher storage.Batch, block *pb.Block) ([]byte, error) {
 	// Generate block header signature
 	if block.Signature == nil {
-		signed, err := l.repo.Key.PrivKey.Sign(block.BlockHash.Bytes())
+		signed, err := repo.Sign(l.repo.Key.PrivKey, block.BlockHash.Bytes())
 		if err != nil {
 			return nil, fmt.Errorf("sign block %s failed: %w", block.BlockHash.String(), err)
 		}
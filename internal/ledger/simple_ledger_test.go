@@ -23,6 +23,7 @@ import (
 	"github.com/meshplus/bitxhub-kit/types"
 	"github.com/meshplus/bitxhub-model/pb"
 	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/meshplus/bitxhub/pkg/signer"
 	"github.com/meshplus/eth-kit/ledger"
 	ledger1 "github.com/meshplus/eth-kit/ledger"
 	libp2pcert "github.com/meshplus/go-libp2p-cert"
@@ -1037,7 +1038,7 @@ BcNwjTDCxyxLNjFKQfMAc6sY6iJs+Ma59WZyC/4uhjE=
 
 	return &repo.Repo{
 		Key: &repo.Key{
-			PrivKey: privKey,
+			PrivKey: signer.NewLocalSigner(privKey),
 			Address: address.String(),
 		},
 		Config: &repo.Config{
@@ -2,6 +2,7 @@ package events
 
 import (
 	"github.com/meshplus/bitxhub-core/governance"
+	nodemgr "github.com/meshplus/bitxhub-core/node-mgr"
 	"github.com/meshplus/bitxhub-kit/types"
 	"github.com/meshplus/bitxhub-model/pb"
 )
@@ -21,7 +22,11 @@ type CheckpointEvent struct {
 	Digest types.Hash
 }
 
+// NodeEvent is posted when the governance of a node changes its availability,
+// NodeId is only set for vp nodes
 type NodeEvent struct {
 	NodeId        uint64
+	NodeAccount   string
+	NodeType      nodemgr.NodeType
 	NodeEventType governance.EventType
 }
@@ -0,0 +1,224 @@
+package repo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/fsnotify/fsnotify"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/meshplus/bitxhub/pkg/crypto/sm2"
+	libp2pcert "github.com/meshplus/go-libp2p-cert"
+)
+
+// CertFingerprint returns the hex encoded sha256 of the DER certificate, which identifies
+// the certificate in the on-chain revocation list
+func CertFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckCertKey checks that the certificate is issued for the libp2p public key
+func CheckCertKey(cert *x509.Certificate, pubKey crypto.PubKey) error {
+	var certKey []byte
+	var err error
+	if sm2.IsSM2Cert(cert) {
+		certKey, err = sm2.MarshalCertPublicKey(cert)
+	} else {
+		certKey, err = x509.MarshalPKIXPublicKey(cert.PublicKey)
+	}
+	if err != nil {
+		return fmt.Errorf("marshal public key of certificate: %w", err)
+	}
+	// the raw ecdsa and SM2 public keys of libp2p are PKIX encoded
+	raw, err := pubKey.Raw()
+	if err != nil {
+		return fmt.Errorf("get raw public key: %w", err)
+	}
+	if !bytes.Equal(certKey, raw) {
+		return fmt.Errorf("certificate is not issued for the public key")
+	}
+	return nil
+}
+
+// ParseCert parses the PEM encoded certificate, SM2 certificates are parsed by the x509 of the
+// national cryptography since the standard x509 does not support the SM2 curve
+func ParseCert(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("empty block")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err == nil {
+		return cert, nil
+	}
+	if sm2Cert, sm2Err := sm2.ParseCert(block.Bytes); sm2Err == nil {
+		return sm2Cert, nil
+	}
+	return nil, err
+}
+
+// VerifyCertSign checks that the certificate is signed by the issuer and not expired
+func VerifyCertSign(cert *x509.Certificate, issuer *x509.Certificate) error {
+	if !sm2.IsSM2Cert(issuer) {
+		return libp2pcert.VerifySign(cert, issuer)
+	}
+
+	if err := sm2.CheckSignatureFrom(cert, issuer); err != nil {
+		return fmt.Errorf("check sign: %w", err)
+	}
+	if cert.NotBefore.After(time.Now()) || cert.NotAfter.Before(time.Now()) {
+		return fmt.Errorf("certs expired")
+	}
+	return nil
+}
+
+// CheckCRLSignature checks that the CRL is signed by the issuer
+func CheckCRLSignature(issuer *x509.Certificate, crl *pkix.CertificateList) error {
+	if sm2.IsSM2Cert(issuer) {
+		return sm2.CheckCRLSignature(issuer, crl)
+	}
+	return issuer.CheckCRLSignature(crl)
+}
+
+// LoadCerts loads the node, agency and ca certs in the repo without verifying them
+func LoadCerts(repoRoot string, config Cert) (*libp2pcert.Certs, error) {
+	load := func(path string) (*x509.Certificate, []byte, error) {
+		data, err := ioutil.ReadFile(filepath.Join(repoRoot, path))
+		if err != nil {
+			return nil, nil, fmt.Errorf("read certs: %w", err)
+		}
+		cert, err := ParseCert(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse certs: %w", err)
+		}
+		return cert, data, nil
+	}
+
+	nodeCert, nodeCertData, err := load(config.NodeCertPath)
+	if err != nil {
+		return nil, fmt.Errorf("load node certs: %w", err)
+	}
+	agencyCert, agencyCertData, err := load(config.AgencyCertPath)
+	if err != nil {
+		return nil, fmt.Errorf("load agency certs: %w", err)
+	}
+	caCert, caCertData, err := load(config.CACertPath)
+	if err != nil {
+		return nil, fmt.Errorf("load ca certs: %w", err)
+	}
+
+	return &libp2pcert.Certs{
+		NodeCertData:   nodeCertData,
+		AgencyCertData: agencyCertData,
+		CACertData:     caCertData,
+		NodeCert:       nodeCert,
+		AgencyCert:     agencyCert,
+		CACert:         caCert,
+	}, nil
+}
+
+// LoadCRLs parses the PEM encoded CRLs in the file, which may be signed by the ca or several agencies
+func LoadCRLs(path string) ([]*pkix.CertificateList, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read crl file: %w", err)
+	}
+
+	var crls []*pkix.CertificateList
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "X509 CRL" {
+			continue
+		}
+		crl, err := x509.ParseDERCRL(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse crl: %w", err)
+		}
+		crls = append(crls, crl)
+	}
+	return crls, nil
+}
+
+// LoadNodeCerts loads the certs in the repo, the node certificate should be issued by the agency
+// for the libp2p key of the node
+func LoadNodeCerts(repoRoot string, config Cert, key *Key) (*libp2pcert.Certs, error) {
+	certs, err := LoadCerts(repoRoot, config)
+	if err != nil {
+		return nil, fmt.Errorf("load certs failed: %w", err)
+	}
+	if err := VerifyCertSign(certs.NodeCert, certs.AgencyCert); err != nil {
+		return nil, fmt.Errorf("verify node certificate: %w", err)
+	}
+	if key != nil && key.Libp2pPrivKey != nil {
+		if err := CheckCertKey(certs.NodeCert, key.Libp2pPrivKey.GetPublic()); err != nil {
+			return nil, fmt.Errorf("check node certificate: %w", err)
+		}
+	}
+	return certs, nil
+}
+
+// WatchCerts reloads the certs once the node certificate is replaced, e.g. by `bitxhub cert rotate`,
+// and sends them to the feed so that new connections are secured with the new certificate
+func WatchCerts(repoRoot string, config Cert, key *Key, feed *event.Feed) {
+	nodeCertPath := filepath.Clean(filepath.Join(repoRoot, config.NodeCertPath))
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		fmt.Println("create certs watcher: ", err)
+		return
+	}
+	// watch the directory, since the certificate is replaced by renaming
+	if err := watcher.Add(filepath.Dir(nodeCertPath)); err != nil {
+		fmt.Println("watch certs: ", err)
+		watcher.Close()
+		return
+	}
+
+	// several events are notified while the file is written
+	last, _ := ioutil.ReadFile(nodeCertPath)
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case ev, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(ev.Name) != nodeCertPath || ev.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				certs, err := LoadNodeCerts(repoRoot, config, key)
+				if err != nil {
+					fmt.Println("reload certs: ", err)
+					continue
+				}
+				if bytes.Equal(certs.NodeCertData, last) {
+					continue
+				}
+				last = certs.NodeCertData
+				fmt.Println("node certificate changed: ", ev.String())
+
+				feed.Send(&Repo{Certs: certs})
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				fmt.Println("certs watcher error: ", err)
+			}
+		}
+	}()
+}
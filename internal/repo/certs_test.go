@@ -0,0 +1,162 @@
+package repo
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/meshplus/bitxhub/pkg/crypto/sm2"
+	libp2pcert "github.com/meshplus/go-libp2p-cert"
+	"github.com/stretchr/testify/require"
+	gmsm2 "github.com/tjfoc/gmsm/sm2"
+	gmx509 "github.com/tjfoc/gmsm/x509"
+)
+
+func writeTestCert(t *testing.T, path string, key *ecdsa.PrivateKey, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, isCA bool) *x509.Certificate {
+	template, err := libp2pcert.GenerateCert(key, isCA, "BitXHub")
+	require.Nil(t, err)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	data, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	require.Nil(t, err)
+	require.Nil(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: data}), 0644))
+	cert, err := x509.ParseCertificate(data)
+	require.Nil(t, err)
+	return cert
+}
+
+func TestWatchCerts(t *testing.T) {
+	repoRoot, err := ioutil.TempDir("", "certs")
+	require.Nil(t, err)
+	defer os.RemoveAll(repoRoot)
+	require.Nil(t, os.Mkdir(filepath.Join(repoRoot, "certs"), 0755))
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	agencyKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	nodeKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	libp2pPrivKey, _, err := crypto.ECDSAKeyPairFromKey(nodeKey)
+	require.Nil(t, err)
+	key := &Key{Libp2pPrivKey: libp2pPrivKey}
+
+	config := Cert{
+		NodeCertPath:   "certs/node.cert",
+		AgencyCertPath: "certs/agency.cert",
+		CACertPath:     "certs/ca.cert",
+	}
+	nodeCertPath := filepath.Join(repoRoot, config.NodeCertPath)
+	caCert := writeTestCert(t, filepath.Join(repoRoot, config.CACertPath), caKey, nil, nil, true)
+	agencyCert := writeTestCert(t, filepath.Join(repoRoot, config.AgencyCertPath), agencyKey, caCert, caKey, true)
+	nodeCert := writeTestCert(t, nodeCertPath, nodeKey, agencyCert, agencyKey, false)
+
+	certs, err := LoadNodeCerts(repoRoot, config, key)
+	require.Nil(t, err)
+	require.Equal(t, CertFingerprint(nodeCert), CertFingerprint(certs.NodeCert))
+	require.Len(t, CertFingerprint(nodeCert), 64)
+
+	feed := &event.Feed{}
+	ch := make(chan *Repo, 10)
+	sub := feed.Subscribe(ch)
+	defer sub.Unsubscribe()
+	WatchCerts(repoRoot, config, key, feed)
+
+	// certs issued for other keys are not reloaded
+	writeTestCert(t, nodeCertPath, otherKey, agencyCert, agencyKey, false)
+	_, err = LoadNodeCerts(repoRoot, config, key)
+	require.NotNil(t, err)
+	select {
+	case <-ch:
+		require.Fail(t, "certs for other key are reloaded")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	// the rotated certificate replaces the old one by renaming
+	tmpPath := nodeCertPath + ".tmp"
+	rotated := writeTestCert(t, tmpPath, nodeKey, agencyCert, agencyKey, false)
+	require.Nil(t, os.Rename(tmpPath, nodeCertPath))
+	select {
+	case r := <-ch:
+		require.NotNil(t, r.Certs)
+		require.Equal(t, CertFingerprint(rotated), CertFingerprint(r.Certs.NodeCert))
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "rotated certs are not reloaded")
+	}
+}
+
+func writeSM2TestCert(t *testing.T, path string, key *gmsm2.PrivateKey, parent *gmx509.Certificate, parentKey *gmsm2.PrivateKey) *gmx509.Certificate {
+	template := &gmx509.Certificate{
+		SignatureAlgorithm:    gmx509.SM2WithSM3,
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              gmx509.KeyUsageDigitalSignature | gmx509.KeyUsageCertSign,
+		Subject:               pkix.Name{Organization: []string{"BitXHub"}},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	data, err := gmx509.CreateCertificate(template, parent, &key.PublicKey, parentKey)
+	require.Nil(t, err)
+	require.Nil(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: data}), 0644))
+	cert, err := gmx509.ParseCertificate(data)
+	require.Nil(t, err)
+	return cert
+}
+
+func TestLoadNodeCerts_SM2(t *testing.T) {
+	repoRoot, err := ioutil.TempDir("", "certs")
+	require.Nil(t, err)
+	defer os.RemoveAll(repoRoot)
+	require.Nil(t, os.Mkdir(filepath.Join(repoRoot, "certs"), 0755))
+
+	var keys []*gmsm2.PrivateKey
+	for i := 0; i < 4; i++ {
+		key, err := gmsm2.GenerateKey(rand.Reader)
+		require.Nil(t, err)
+		keys = append(keys, key)
+	}
+	libp2pPrivKey, _, err := sm2.Libp2pKeyPairFromKey(keys[2])
+	require.Nil(t, err)
+	key := &Key{Libp2pPrivKey: libp2pPrivKey}
+
+	config := Cert{
+		NodeCertPath:   "certs/node.cert",
+		AgencyCertPath: "certs/agency.cert",
+		CACertPath:     "certs/ca.cert",
+	}
+	caCert := writeSM2TestCert(t, filepath.Join(repoRoot, config.CACertPath), keys[0], nil, nil)
+	agencyCert := writeSM2TestCert(t, filepath.Join(repoRoot, config.AgencyCertPath), keys[1], caCert, keys[0])
+	nodeCert := writeSM2TestCert(t, filepath.Join(repoRoot, config.NodeCertPath), keys[2], agencyCert, keys[1])
+
+	certs, err := LoadNodeCerts(repoRoot, config, key)
+	require.Nil(t, err)
+	require.True(t, sm2.IsSM2Cert(certs.CACert))
+	require.Equal(t, nodeCert.Raw, certs.NodeCert.Raw)
+	require.Nil(t, VerifyCertSign(certs.AgencyCert, certs.CACert))
+
+	// the node certificate should be issued by the agency for the node key
+	writeSM2TestCert(t, filepath.Join(repoRoot, config.NodeCertPath), keys[2], caCert, keys[0])
+	_, err = LoadNodeCerts(repoRoot, config, key)
+	require.NotNil(t, err)
+	writeSM2TestCert(t, filepath.Join(repoRoot, config.NodeCertPath), keys[3], agencyCert, keys[1])
+	_, err = LoadNodeCerts(repoRoot, config, key)
+	require.NotNil(t, err)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/fsnotify/fsnotify"
 	"github.com/meshplus/bitxhub-core/tss"
+	"github.com/meshplus/bitxhub/pkg/signer"
 	"github.com/mitchellh/go-homedir"
 	ma "github.com/multiformats/go-multiaddr"
 	"github.com/spf13/viper"
@@ -57,31 +58,64 @@ const (
 	ProposalStrategyMgr = "proposal_strategy_mgr"
 	DappMgr             = "dapp_mgr"
 	BnsMgr              = "bns_mgr"
+	InterchainMgr       = "interchain_mgr"
+	AssetMgr            = "asset_mgr"
 	AllMgr              = "all_mgr"
 )
 
 type Config struct {
-	RepoRoot string `json:"repo_root"`
-	Title    string `json:"title"`
-	Solo     bool   `json:"solo"`
-	Port     `json:"port"`
-	PProf    `json:"pprof"`
-	Monitor  `json:"monitor"`
-	Limiter  `json:"limiter"`
-	Appchain `json:"appchain"`
-	Gateway  `json:"gateway"`
-	Ping     `json:"ping"`
-	Log      `json:"log"`
-	Cert     `json:"cert"`
-	Txpool   `json:"txpool"`
-	Order    `json:"order"`
-	Executor `json:"executor"`
-	Ledger   `json:"ledger"`
-	Genesis  `json:"genesis"`
-	Security Security      `toml:"security" json:"security"`
-	License  License       `toml:"license" json:"license"`
-	Crypto   Crypto        `toml:"crypto" json:"crypto"`
-	Tss      tss.TssConfig `toml:"tss" json:"tss"`
+	RepoRoot  string `json:"repo_root"`
+	Title     string `json:"title"`
+	Solo      bool   `json:"solo"`
+	Port      `json:"port"`
+	PProf     `json:"pprof"`
+	Monitor   `json:"monitor"`
+	Limiter   `json:"limiter"`
+	Appchain  `json:"appchain"`
+	Gateway   `json:"gateway"`
+	Ping      `json:"ping"`
+	Log       `json:"log"`
+	Cert      `json:"cert"`
+	Txpool    `json:"txpool"`
+	Order     `json:"order"`
+	Executor  `json:"executor"`
+	Ledger    `json:"ledger"`
+	Genesis   `json:"genesis"`
+	Security  Security      `toml:"security" json:"security"`
+	License   License       `toml:"license" json:"license"`
+	Crypto    Crypto        `toml:"crypto" json:"crypto"`
+	Signer    signer.Config `toml:"signer" json:"signer"`
+	Tss       tss.TssConfig `toml:"tss" json:"tss"`
+	TssPolicy TssPolicy     `mapstructure:"tss_policy" toml:"tss_policy" json:"tss_policy"`
+	PeerScore PeerScore     `mapstructure:"peer_score" toml:"peer_score" json:"peer_score"`
+}
+
+// TssPolicy configures the proactive refresh of tss shares and the handling of misbehaving signers
+type TssPolicy struct {
+	// RefreshEpoch is the number of blocks between two refreshes of tss shares, 0 disables refreshing
+	RefreshEpoch uint64 `mapstructure:"refresh_epoch" toml:"refresh_epoch" json:"refresh_epoch"`
+	// FreezeThreshold is the times a node is blamed in key sign requests before it is reported to governance,
+	// 0 disables reporting
+	FreezeThreshold uint64 `mapstructure:"freeze_threshold" toml:"freeze_threshold" json:"freeze_threshold"`
+}
+
+// PeerScore configures the health scoring of vp peers, a peer whose score drops to
+// the ban threshold is disconnected and refused for the ban duration
+type PeerScore struct {
+	EnableBan bool `mapstructure:"enable_ban" toml:"enable_ban" json:"enable_ban"`
+	// BanThreshold is the negative score at which a peer is banned
+	BanThreshold float64       `mapstructure:"ban_threshold" toml:"ban_threshold" json:"ban_threshold"`
+	BanDuration  time.Duration `mapstructure:"ban_duration" toml:"ban_duration" json:"ban_duration"`
+	// DecayHalfLife is the time it takes for the penalties of a peer to be halved
+	DecayHalfLife time.Duration `mapstructure:"decay_half_life" toml:"decay_half_life" json:"decay_half_life"`
+	// MaxLatency is the ping rtt above which a peer is penalized as slow
+	MaxLatency time.Duration `mapstructure:"max_latency" toml:"max_latency" json:"max_latency"`
+	// SlowResponsePenalty and FailedRequestPenalty penalize transport failures, which are 0 by default
+	// so that vp peers which are merely unreachable, e.g. restarting or partitioned, are never banned
+	SlowResponsePenalty      float64 `mapstructure:"slow_response_penalty" toml:"slow_response_penalty" json:"slow_response_penalty"`
+	FailedRequestPenalty     float64 `mapstructure:"failed_request_penalty" toml:"failed_request_penalty" json:"failed_request_penalty"`
+	InvalidResponsePenalty   float64 `mapstructure:"invalid_response_penalty" toml:"invalid_response_penalty" json:"invalid_response_penalty"`
+	ProtocolViolationPenalty float64 `mapstructure:"protocol_violation_penalty" toml:"protocol_violation_penalty" json:"protocol_violation_penalty"`
 }
 
 // Security are files used to setup connection with tls
@@ -185,6 +219,8 @@ type Cert struct {
 	NodeCertPath   string `mapstructure:"node_cert_path" json:"node_cert_path"`
 	AgencyCertPath string `mapstructure:"agency_cert_path" json:"agency_cert_path"`
 	CACertPath     string `mapstructure:"ca_cert_path" json:"ca_cert_path"`
+	// CRLPath is the PEM file of CRLs signed by the ca or agencies, empty disables the CRL checking
+	CRLPath string `mapstructure:"crl_path" json:"crl_path"`
 }
 
 type Txpool struct {
@@ -295,6 +331,22 @@ func DefaultConfig() (*Config, error) {
 			MultiLdbThresholdStr:  "100GB",
 		},
 		Crypto: Crypto{Algorithms: []string{"Secp256k1"}},
+		Signer: signer.Config{Type: signer.LocalType},
+		TssPolicy: TssPolicy{
+			RefreshEpoch:    0,
+			FreezeThreshold: 5,
+		},
+		PeerScore: PeerScore{
+			EnableBan:                true,
+			BanThreshold:             -100,
+			BanDuration:              5 * time.Minute,
+			DecayHalfLife:            10 * time.Minute,
+			MaxLatency:               time.Second,
+			SlowResponsePenalty:      0,
+			FailedRequestPenalty:     0,
+			InvalidResponsePenalty:   10,
+			ProtocolViolationPenalty: 20,
+		},
 	}, nil
 }
 
@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
@@ -9,12 +10,16 @@ import (
 	"github.com/libp2p/go-libp2p-core/crypto"
 	crypto2 "github.com/meshplus/bitxhub-kit/crypto"
 	"github.com/meshplus/bitxhub-kit/crypto/asym"
+	"github.com/meshplus/bitxhub-kit/crypto/asym/ecdsa"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub/pkg/crypto/sm2"
+	"github.com/meshplus/bitxhub/pkg/signer"
 	libp2pcert "github.com/meshplus/go-libp2p-cert"
 )
 
 type Key struct {
-	Address       string             `json:"address"`
-	PrivKey       crypto2.PrivateKey `json:"priv_key"`
+	Address       string        `json:"address"`
+	PrivKey       signer.Signer `json:"priv_key"`
 	Libp2pPrivKey crypto.PrivKey
 }
 
@@ -31,43 +36,112 @@ func LoadKey(path string) (*Key, error) {
 
 	return &Key{
 		Address: address.String(),
-		PrivKey: privKey,
+		PrivKey: signer.NewLocalSigner(privKey),
 	}, nil
 }
 
-func loadPrivKey(repoRoot string, passwd string) (*Key, error) {
-	if strings.TrimSpace(passwd) == "" {
-		passwd = DefaultPasswd
-	}
-
-	privKey, err := asym.RestorePrivateKey(filepath.Join(repoRoot, KeyName), passwd)
+func loadPrivKey(repoRoot string, passwd string, config signer.Config) (*Key, error) {
+	privKey, err := LoadSigner(repoRoot, passwd, config)
 	if err != nil {
-		return nil, fmt.Errorf("restore private key failed: %w", err)
+		return nil, err
 	}
 
 	address, err := privKey.PublicKey().Address()
 	if err != nil {
+		_ = privKey.Close()
 		return nil, fmt.Errorf("get address from public key failed: %w", err)
 	}
 
 	nodeKeyData, err := ioutil.ReadFile(filepath.Join(repoRoot, "certs/node.priv"))
 	if err != nil {
+		_ = privKey.Close()
 		return nil, fmt.Errorf("read %s error: %w", filepath.Join(repoRoot, "certs/node.priv"), err)
 	}
 
-	nodePrivKey, err := libp2pcert.ParsePrivateKey(nodeKeyData, crypto2.ECDSA_P256)
+	libp2pPrivKey, err := ParseNodeKey(nodeKeyData)
 	if err != nil {
+		_ = privKey.Close()
 		return nil, fmt.Errorf("parse private key failed: %w", err)
 	}
 
-	libp2pPrivKey, _, err := crypto.ECDSAKeyPairFromKey(nodePrivKey.K)
-	if err != nil {
-		return nil, fmt.Errorf("generate ecdsa key failed: %w", err)
-	}
-
 	return &Key{
 		Address:       address.String(),
 		PrivKey:       privKey,
 		Libp2pPrivKey: libp2pPrivKey,
 	}, nil
 }
+
+// LoadSigner loads the signer configured in bitxhub.toml, the local signer restores key.json
+// of the repo with the password
+func LoadSigner(repoRoot string, passwd string, config signer.Config) (signer.Signer, error) {
+	var local crypto2.PrivateKey
+	if config.Type == "" || config.Type == signer.LocalType {
+		if strings.TrimSpace(passwd) == "" {
+			passwd = DefaultPasswd
+		}
+
+		privKey, err := asym.RestorePrivateKey(filepath.Join(repoRoot, KeyName), passwd)
+		if err != nil {
+			return nil, fmt.Errorf("restore private key failed: %w", err)
+		}
+		local = privKey
+	}
+
+	if config.Remote.Socket != "" && !filepath.IsAbs(config.Remote.Socket) {
+		config.Remote.Socket = filepath.Join(repoRoot, config.Remote.Socket)
+	}
+
+	privKey, err := signer.New(config, local)
+	if err != nil {
+		return nil, fmt.Errorf("load %s signer failed: %w", config.Type, err)
+	}
+
+	return privKey, nil
+}
+
+// ParseNodeKey parses the PEM encoded libp2p node key, which is either a SM2 key or a ECDSA P256 key
+func ParseNodeKey(data []byte) (crypto.PrivKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("empty block")
+	}
+
+	if sm2PrivKey, err := sm2.UnmarshalLibp2pPrivKey(block.Bytes); err == nil {
+		return sm2PrivKey, nil
+	}
+
+	nodePrivKey, err := libp2pcert.ParsePrivateKey(data, crypto2.ECDSA_P256)
+	if err != nil {
+		return nil, err
+	}
+
+	libp2pPrivKey, _, err := crypto.ECDSAKeyPairFromKey(nodePrivKey.K)
+	if err != nil {
+		return nil, fmt.Errorf("generate ecdsa key failed: %w", err)
+	}
+
+	return libp2pPrivKey, nil
+}
+
+// Sign signs the digest with the node key. Secp256k1 signatures are kept in the 65 bytes
+// recoverable format expected by appchains, and signatures of other key types are prefixed
+// with the key type, so that the signer is recovered from the public key in the signature.
+func Sign(privKey signer.Signer, digest []byte) ([]byte, error) {
+	if privKey.Type() == crypto2.Secp256k1 {
+		return privKey.Sign(digest)
+	}
+
+	return asym.SignWithType(privKey, digest)
+}
+
+// VerifySign verifies the signature made by Sign against the signer address
+func VerifySign(sig, digest []byte, from types.Address) (bool, error) {
+	if len(sig) == ecdsa.SignatureLength {
+		return asym.Verify(crypto2.Secp256k1, sig, digest, from)
+	}
+	if len(sig) == 0 {
+		return false, fmt.Errorf("empty signature")
+	}
+
+	return asym.VerifyWithType(sig, digest, from)
+}
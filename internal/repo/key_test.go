@@ -1,9 +1,21 @@
 package repo
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
 
+	pb "github.com/libp2p/go-libp2p-core/crypto/pb"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub/pkg/crypto/sm2"
+	"github.com/meshplus/bitxhub/pkg/signer"
 	"github.com/stretchr/testify/require"
+	gmsm "github.com/tjfoc/gmsm/sm2"
+	"github.com/tjfoc/gmsm/x509"
 )
 
 func TestLoadKey(t *testing.T) {
@@ -11,17 +23,76 @@ func TestLoadKey(t *testing.T) {
 	_, err := LoadKey(path)
 	require.Nil(t, err)
 
-	_, err = loadPrivKey("testdata", "")
+	_, err = loadPrivKey("testdata", "", signer.Config{Type: signer.LocalType})
 	require.Nil(t, err)
 
 	path = ""
 	_, err = LoadKey(path)
 	require.NotNil(t, err)
 
-	_, err = loadPrivKey("testd", "")
+	_, err = loadPrivKey("testd", "", signer.Config{Type: signer.LocalType})
 	require.NotNil(t, err)
 
-	_, err = loadPrivKey("", "")
+	_, err = loadPrivKey("", "", signer.Config{Type: signer.LocalType})
 	require.NotNil(t, err)
 
 }
+
+func TestParseNodeKey(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/certs/node.priv")
+	require.Nil(t, err)
+	privKey, err := ParseNodeKey(data)
+	require.Nil(t, err)
+	require.Equal(t, pb.KeyType_ECDSA, privKey.Type())
+
+	sm2Key, err := gmsm.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	data, err = x509.WritePrivateKeyToPem(sm2Key, nil)
+	require.Nil(t, err)
+	privKey, err = ParseNodeKey(data)
+	require.Nil(t, err)
+	require.Equal(t, sm2.KeyType_SM2, privKey.Type())
+
+	_, err = ParseNodeKey([]byte("node key"))
+	require.NotNil(t, err)
+}
+
+func TestLoadSigner(t *testing.T) {
+	key, err := LoadKey("testdata/key.json")
+	require.Nil(t, err)
+
+	privKey, err := LoadSigner("testdata", "", signer.Config{Type: signer.LocalType})
+	require.Nil(t, err)
+	addr, err := privKey.PublicKey().Address()
+	require.Nil(t, err)
+	require.Equal(t, key.Address, addr.String())
+
+	// the socket of the remote signer is relative to the repo root
+	dir, err := ioutil.TempDir("", "repo")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	l, err := net.Listen("unix", filepath.Join(dir, "signer.sock"))
+	require.Nil(t, err)
+	defer l.Close()
+	go func() {
+		_ = signer.ServeRemote(l, key.PrivKey)
+	}()
+
+	config := signer.Config{Type: signer.RemoteType, Remote: signer.RemoteConfig{Socket: "signer.sock"}}
+	privKey, err = LoadSigner(dir, "", config)
+	require.Nil(t, err)
+	defer privKey.Close()
+	addr, err = privKey.PublicKey().Address()
+	require.Nil(t, err)
+	require.Equal(t, key.Address, addr.String())
+
+	digest := sha256.Sum256([]byte("block"))
+	sig, err := Sign(privKey, digest[:])
+	require.Nil(t, err)
+	ok, err := VerifySign(sig, digest[:], *types.NewAddressByStr(key.Address))
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	_, err = LoadSigner(dir, "", signer.Config{Type: signer.LocalType})
+	require.NotNil(t, err)
+}
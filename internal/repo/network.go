@@ -8,12 +8,9 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
-	crypto2 "github.com/meshplus/bitxhub-kit/crypto"
 	"github.com/meshplus/bitxhub-kit/types"
 	"github.com/meshplus/bitxhub-model/pb"
-	libp2pcert "github.com/meshplus/go-libp2p-cert"
 	ma "github.com/multiformats/go-multiaddr"
 	"github.com/pelletier/go-toml"
 	"github.com/spf13/viper"
@@ -26,6 +23,11 @@ type NetworkConfig struct {
 	LocalAddr string          `toml:"local_addr, omitempty" json:"local_addr"`
 	Nodes     []*NetworkNodes `toml:"nodes" json:"nodes"`
 	Genesis   Genesis         `toml:"genesis, omitempty" json:"genesis"`
+
+	// Follower marks the node as a follower which is not one of the vp nodes, it listens on
+	// FollowerAddr and follows the chain from the vp nodes without joining consensus
+	Follower     bool   `toml:"follower" json:"follower"`
+	FollowerAddr string `mapstructure:"follower_addr" toml:"follower_addr,omitempty" json:"follower_addr"`
 }
 
 type NetworkNodes struct {
@@ -44,6 +46,9 @@ func loadNetworkConfig(viper *viper.Viper, repoRoot string, genesis Genesis) (*N
 
 	for _, node := range networkConfig.Nodes {
 		if node.ID == networkConfig.ID {
+			if networkConfig.Follower {
+				return nil, fmt.Errorf("the id %d of follower is used by vp node", node.ID)
+			}
 			if len(node.Hosts) == 0 {
 				return nil, fmt.Errorf("no hosts found by node:%d", node.ID)
 			}
@@ -61,6 +66,14 @@ func loadNetworkConfig(viper *viper.Viper, repoRoot string, genesis Genesis) (*N
 		}
 	}
 
+	if networkConfig.Follower {
+		if networkConfig.FollowerAddr == "" {
+			return nil, fmt.Errorf("lack of follower address")
+		}
+		networkConfig.LocalAddr = networkConfig.FollowerAddr
+		return networkConfig, nil
+	}
+
 	if networkConfig.LocalAddr == "" {
 		return nil, fmt.Errorf("lack of local address")
 	}
@@ -184,17 +197,12 @@ func GetPidFromPrivFile(privPath string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("read private key error: %w", err)
 	}
-	privKey, err := libp2pcert.ParsePrivateKey(data, crypto2.ECDSA_P256)
+	privKey, err := ParseNodeKey(data)
 	if err != nil {
 		return "", fmt.Errorf("parse private key failed: %w", err)
 	}
 
-	_, pk, err := crypto.KeyPairFromStdKey(privKey.K)
-	if err != nil {
-		return "", fmt.Errorf("wrap standard library private key to libp2p keys failed: %w", err)
-	}
-
-	pid, err := peer.IDFromPublicKey(pk)
+	pid, err := peer.IDFromPublicKey(privKey.GetPublic())
 	if err != nil {
 		return "", fmt.Errorf("get peer ID failed: %w", err)
 	}
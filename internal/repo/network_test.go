@@ -1,6 +1,10 @@
 package repo
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/meshplus/bitxhub-model/pb"
@@ -49,6 +53,35 @@ func TestNetworkConfig(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestFollowerNetworkConfig(t *testing.T) {
+	repoRoot, err := ioutil.TempDir("", "follower")
+	require.Nil(t, err)
+	defer os.RemoveAll(repoRoot)
+
+	data, err := ioutil.ReadFile("./testdata/network.toml")
+	require.Nil(t, err)
+	network := strings.Replace(string(data), "id = 1", "id = 5\nfollower = true", 1)
+	require.Nil(t, ioutil.WriteFile(filepath.Join(repoRoot, "network.toml"), []byte(network), 0644))
+
+	// lack of follower address
+	_, err = loadNetworkConfig(viper.New(), repoRoot, Genesis{})
+	require.NotNil(t, err)
+
+	network = strings.Replace(network, "follower = true", "follower = true\nfollower_addr = \"/ip4/0.0.0.0/tcp/4005\"", 1)
+	require.Nil(t, ioutil.WriteFile(filepath.Join(repoRoot, "network.toml"), []byte(network), 0644))
+	cfg, err := loadNetworkConfig(viper.New(), repoRoot, Genesis{})
+	require.Nil(t, err)
+	require.True(t, cfg.Follower)
+	require.Equal(t, "/ip4/0.0.0.0/tcp/4005", cfg.LocalAddr)
+	require.Equal(t, 4, len(cfg.GetVpInfos()))
+
+	// the id of follower is used by vp node
+	network = strings.Replace(network, "id = 5", "id = 1", 1)
+	require.Nil(t, ioutil.WriteFile(filepath.Join(repoRoot, "network.toml"), []byte(network), 0644))
+	_, err = loadNetworkConfig(viper.New(), repoRoot, Genesis{})
+	require.NotNil(t, err)
+}
+
 func TestRewriteNetworkConfig(t *testing.T) {
 	infos := make(map[uint64]*pb.VpInfo, 0)
 	{
@@ -58,12 +58,12 @@ func Load(repoRoot string, passwd string, configPath string, networkPath string)
 		}
 	}
 
-	certs, err := libp2pcert.LoadCerts(repoRoot, config.NodeCertPath, config.AgencyCertPath, config.CACertPath)
+	certs, err := LoadCerts(repoRoot, config.Cert)
 	if err != nil {
 		return nil, fmt.Errorf("load certs failed: %w", err)
 	}
 
-	key, err := loadPrivKey(repoRoot, passwd)
+	key, err := loadPrivKey(repoRoot, passwd, config.Signer)
 	if err != nil {
 		return nil, fmt.Errorf("load private key: %w", err)
 	}
@@ -81,6 +81,9 @@ func Load(repoRoot string, passwd string, configPath string, networkPath string)
 	// watch network.toml on changed
 	WatchNetworkConfig(nViper, &repo.ConfigChangeFeed)
 
+	// watch node certificate on rotated
+	WatchCerts(repoRoot, config.Cert, key, &repo.ConfigChangeFeed)
+
 	return repo, nil
 }
 
@@ -208,7 +211,9 @@ func CheckManageModule(moduleTyp string) error {
 		moduleTyp != ProposalStrategyMgr &&
 		moduleTyp != NodeMgr &&
 		moduleTyp != ServiceMgr &&
-		moduleTyp != BnsMgr {
+		moduleTyp != BnsMgr &&
+		moduleTyp != InterchainMgr &&
+		moduleTyp != AssetMgr {
 		return fmt.Errorf("illegal manage module type")
 	}
 	return nil
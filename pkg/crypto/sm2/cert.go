@@ -0,0 +1,71 @@
+package sm2
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+
+	"github.com/tjfoc/gmsm/sm2"
+	gmx509 "github.com/tjfoc/gmsm/x509"
+)
+
+// SM2 certificates are parsed by the x509 of the national cryptography and carried as standard
+// certificates, whose public keys are ECDSA public keys on the SM2 curve. Their signatures are
+// checked by the x509 of the national cryptography against the raw certificates.
+
+// IsSM2Cert reports whether the certificate is issued for a SM2 public key
+func IsSM2Cert(cert *x509.Certificate) bool {
+	pub, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	return ok && pub.Curve == sm2.P256Sm2()
+}
+
+// ParseCert parses the DER encoded SM2 certificate
+func ParseCert(der []byte) (*x509.Certificate, error) {
+	cert, err := gmx509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := cert.ToX509Certificate()
+	if !IsSM2Cert(ret) {
+		return nil, fmt.Errorf("not SM2 certificate")
+	}
+
+	return ret, nil
+}
+
+// CheckSignatureFrom checks that the certificate is signed by the SM2 issuer
+func CheckSignatureFrom(cert, issuer *x509.Certificate) error {
+	c, err := gmx509.ParseCertificate(cert.Raw)
+	if err != nil {
+		return err
+	}
+	parent, err := gmx509.ParseCertificate(issuer.Raw)
+	if err != nil {
+		return err
+	}
+
+	return c.CheckSignatureFrom(parent)
+}
+
+// CheckCRLSignature checks that the CRL is signed by the SM2 issuer
+func CheckCRLSignature(issuer *x509.Certificate, crl *pkix.CertificateList) error {
+	parent, err := gmx509.ParseCertificate(issuer.Raw)
+	if err != nil {
+		return err
+	}
+
+	return parent.CheckCRLSignature(crl)
+}
+
+// MarshalCertPublicKey returns the PKIX DER encoded public key of the SM2 certificate,
+// which is the same as the raw libp2p public key
+func MarshalCertPublicKey(cert *x509.Certificate) ([]byte, error) {
+	pub, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok || pub.Curve != sm2.P256Sm2() {
+		return nil, fmt.Errorf("not SM2 certificate")
+	}
+
+	return gmx509.MarshalSm2PublicKey(&sm2.PublicKey{Curve: pub.Curve, X: pub.X, Y: pub.Y})
+}
@@ -0,0 +1,131 @@
+package sm2
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	pb "github.com/libp2p/go-libp2p-core/crypto/pb"
+	"github.com/tjfoc/gmsm/sm2"
+	"github.com/tjfoc/gmsm/x509"
+)
+
+// KeyType_SM2 is the libp2p key type of SM2 keys, which follows the key types defined by libp2p
+const KeyType_SM2 = pb.KeyType(7)
+
+var _ crypto.PrivKey = (*Libp2pPrivKey)(nil)
+var _ crypto.PubKey = (*Libp2pPubKey)(nil)
+
+// SM2 keys are registered to libp2p, so that peers with SM2 identity keys are resolved from
+// the public keys exchanged in the handshake
+func init() {
+	crypto.PubKeyUnmarshallers[KeyType_SM2] = UnmarshalLibp2pPubKey
+	crypto.PrivKeyUnmarshallers[KeyType_SM2] = UnmarshalLibp2pPrivKey
+}
+
+// Libp2pPrivKey is the SM2 private key used as the libp2p identity of the node
+type Libp2pPrivKey struct {
+	K *sm2.PrivateKey
+}
+
+// Libp2pPubKey is the SM2 public key of libp2p peers
+type Libp2pPubKey struct {
+	K *sm2.PublicKey
+}
+
+// Libp2pKeyPairFromKey wraps the SM2 private key to libp2p keys
+func Libp2pKeyPairFromKey(priv *sm2.PrivateKey) (crypto.PrivKey, crypto.PubKey, error) {
+	if priv == nil {
+		return nil, nil, fmt.Errorf("nil sm2 private key")
+	}
+
+	return &Libp2pPrivKey{K: priv}, &Libp2pPubKey{K: &priv.PublicKey}, nil
+}
+
+// UnmarshalLibp2pPrivKey parses the PKCS#8 DER encoded private key
+func UnmarshalLibp2pPrivKey(data []byte) (crypto.PrivKey, error) {
+	priv, err := x509.ParsePKCS8UnecryptedPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Libp2pPrivKey{K: priv}, nil
+}
+
+// UnmarshalLibp2pPubKey parses the PKIX DER encoded public key
+func UnmarshalLibp2pPubKey(data []byte) (crypto.PubKey, error) {
+	pub, err := x509.ParseSm2PublicKey(data)
+	if err != nil {
+		return nil, err
+	}
+	if pub.X == nil || !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, fmt.Errorf("invalid sm2 public key")
+	}
+
+	return &Libp2pPubKey{K: pub}, nil
+}
+
+func (k *Libp2pPrivKey) Bytes() ([]byte, error) {
+	return crypto.MarshalPrivateKey(k)
+}
+
+func (k *Libp2pPrivKey) Equals(o crypto.Key) bool {
+	return keyEquals(k, o)
+}
+
+// Raw returns the PKCS#8 DER encoded private key
+func (k *Libp2pPrivKey) Raw() ([]byte, error) {
+	return x509.MarshalSm2UnecryptedPrivateKey(k.K)
+}
+
+func (k *Libp2pPrivKey) Type() pb.KeyType {
+	return KeyType_SM2
+}
+
+// Sign signs the data with SM3 and the default user id
+func (k *Libp2pPrivKey) Sign(data []byte) ([]byte, error) {
+	return k.K.Sign(rand.Reader, data, nil)
+}
+
+func (k *Libp2pPrivKey) GetPublic() crypto.PubKey {
+	return &Libp2pPubKey{K: &k.K.PublicKey}
+}
+
+func (k *Libp2pPubKey) Bytes() ([]byte, error) {
+	return crypto.MarshalPublicKey(k)
+}
+
+func (k *Libp2pPubKey) Equals(o crypto.Key) bool {
+	return keyEquals(k, o)
+}
+
+// Raw returns the PKIX DER encoded public key, which is the same as the public key in certificates
+func (k *Libp2pPubKey) Raw() ([]byte, error) {
+	return x509.MarshalSm2PublicKey(k.K)
+}
+
+func (k *Libp2pPubKey) Type() pb.KeyType {
+	return KeyType_SM2
+}
+
+func (k *Libp2pPubKey) Verify(data []byte, sig []byte) (bool, error) {
+	return k.K.Verify(data, sig), nil
+}
+
+func keyEquals(k1, k2 crypto.Key) bool {
+	if k1.Type() != k2.Type() {
+		return false
+	}
+
+	a, err := k1.Raw()
+	if err != nil {
+		return false
+	}
+	b, err := k2.Raw()
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(a, b)
+}
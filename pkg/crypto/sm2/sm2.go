@@ -0,0 +1,190 @@
+package sm2
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/asn1"
+	"fmt"
+
+	"github.com/meshplus/bitxhub-kit/crypto"
+	"github.com/meshplus/bitxhub-kit/crypto/asym"
+	"github.com/meshplus/bitxhub-kit/crypto/asym/ecdsa"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/tjfoc/gmsm/sm2"
+	"github.com/tjfoc/gmsm/sm3"
+	"github.com/tjfoc/gmsm/x509"
+)
+
+var _ crypto.PrivateKey = (*PrivateKey)(nil)
+var _ crypto.PublicKey = (*PublicKey)(nil)
+
+// SM2 is registered to asym once the package is imported, so that SM2 keys are supported
+// by key store, transaction signatures and the configured crypto algorithms
+func init() {
+	asym.CryptoM[crypto.SM2] = &asym.Crypto{
+		Constructor: func(opt crypto.KeyType) (crypto.PrivateKey, error) {
+			return New()
+		},
+		Verify: Verify,
+		UnmarshalPrivateKey: func(data []byte, opt crypto.KeyType) (crypto.PrivateKey, error) {
+			return UnmarshalPrivateKey(data)
+		},
+	}
+}
+
+// PrivateKey SM2 private key, digests are signed with SM3 and the default user id
+type PrivateKey struct {
+	K *sm2.PrivateKey
+}
+
+// PublicKey SM2 public key
+type PublicKey struct {
+	K *sm2.PublicKey
+}
+
+// New generates a SM2 private key
+func New() (*PrivateKey, error) {
+	priv, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PrivateKey{K: priv}, nil
+}
+
+// UnmarshalPrivateKey parses the PKCS#8 DER encoded private key
+func UnmarshalPrivateKey(data []byte) (*PrivateKey, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty private key data")
+	}
+
+	priv, err := x509.ParsePKCS8UnecryptedPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PrivateKey{K: priv}, nil
+}
+
+// UnmarshalPublicKey parses the uncompressed public key
+func UnmarshalPublicKey(data []byte) (*PublicKey, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty public key data")
+	}
+
+	curve := sm2.P256Sm2()
+	x, y := elliptic.Unmarshal(curve, data)
+	if x == nil {
+		return nil, fmt.Errorf("invalid sm2 public key")
+	}
+
+	return &PublicKey{K: &sm2.PublicKey{Curve: curve, X: x, Y: y}}, nil
+}
+
+// Bytes returns the PKCS#8 DER encoded private key
+func (priv *PrivateKey) Bytes() ([]byte, error) {
+	if priv.K == nil {
+		return nil, fmt.Errorf("SM2PrivateKey.K is nil")
+	}
+
+	return x509.MarshalSm2UnecryptedPrivateKey(priv.K)
+}
+
+func (priv *PrivateKey) Type() crypto.KeyType {
+	return crypto.SM2
+}
+
+func (priv *PrivateKey) PublicKey() crypto.PublicKey {
+	return &PublicKey{K: &priv.K.PublicKey}
+}
+
+// Sign signs the digest, the public key is carried in the signature like ECDSA signatures,
+// so that the signer can be recovered from it
+func (priv *PrivateKey) Sign(digest []byte) ([]byte, error) {
+	r, s, err := sm2.Sm2Sign(priv.K, digest, nil, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	pubBytes, err := priv.PublicKey().Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(ecdsa.Sig{Pub: pubBytes, R: r, S: s})
+}
+
+// Bytes returns the uncompressed public key
+func (pub *PublicKey) Bytes() ([]byte, error) {
+	if pub.K == nil {
+		return nil, fmt.Errorf("SM2PublicKey.K is nil")
+	}
+
+	return elliptic.Marshal(pub.K.Curve, pub.K.X, pub.K.Y), nil
+}
+
+func (pub *PublicKey) Type() crypto.KeyType {
+	return crypto.SM2
+}
+
+// Address returns the last 20 bytes of the SM3 hash of the public key
+func (pub *PublicKey) Address() (*types.Address, error) {
+	data, err := pub.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := sm3.Sm3Sum(data[1:])
+
+	return types.NewAddress(ret[12:]), nil
+}
+
+func (pub *PublicKey) Verify(digest []byte, sig []byte) (bool, error) {
+	if sig == nil {
+		return false, fmt.Errorf("nil signature")
+	}
+
+	sigStruct := &ecdsa.Sig{}
+	if _, err := asn1.Unmarshal(sig, sigStruct); err != nil {
+		return false, err
+	}
+
+	if !sm2.Sm2Verify(pub.K, digest, nil, sigStruct.R, sigStruct.S) {
+		return false, fmt.Errorf("invalid signature")
+	}
+
+	return true, nil
+}
+
+// RecoverPublicKey returns the public key carried in the signature
+func RecoverPublicKey(sig []byte) (*PublicKey, error) {
+	sigStruct := &ecdsa.Sig{}
+	if _, err := asn1.Unmarshal(sig, sigStruct); err != nil {
+		return nil, err
+	}
+
+	return UnmarshalPublicKey(sigStruct.Pub)
+}
+
+// Verify verifies the signature of the digest is signed by the address
+func Verify(opt crypto.KeyType, sig, digest []byte, from types.Address) (bool, error) {
+	if opt != crypto.SM2 {
+		return false, fmt.Errorf("wrong algorithm type")
+	}
+
+	pubKey, err := RecoverPublicKey(sig)
+	if err != nil {
+		return false, err
+	}
+
+	expected, err := pubKey.Address()
+	if err != nil {
+		return false, err
+	}
+
+	if expected.String() != from.String() {
+		return false, fmt.Errorf("wrong signer for this signature")
+	}
+
+	return pubKey.Verify(digest, sig)
+}
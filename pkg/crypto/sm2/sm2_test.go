@@ -0,0 +1,172 @@
+package sm2
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	crypto2 "github.com/meshplus/bitxhub-kit/crypto"
+	"github.com/meshplus/bitxhub-kit/crypto/asym"
+	"github.com/stretchr/testify/require"
+	"github.com/tjfoc/gmsm/sm2"
+	gmx509 "github.com/tjfoc/gmsm/x509"
+)
+
+func TestSM2(t *testing.T) {
+	require.True(t, asym.SupportedKeyType(crypto2.SM2))
+
+	priv, err := asym.GenerateKeyPair(crypto2.SM2)
+	require.Nil(t, err)
+	require.Equal(t, crypto2.KeyType(crypto2.SM2), priv.Type())
+	require.Equal(t, crypto2.KeyType(crypto2.SM2), priv.PublicKey().Type())
+
+	addr, err := priv.PublicKey().Address()
+	require.Nil(t, err)
+	pubBytes, err := priv.PublicKey().Bytes()
+	require.Nil(t, err)
+	pub, err := UnmarshalPublicKey(pubBytes)
+	require.Nil(t, err)
+	pubAddr, err := pub.Address()
+	require.Nil(t, err)
+	require.Equal(t, addr.String(), pubAddr.String())
+
+	digest := sha256.Sum256([]byte("bitxhub"))
+	sig, err := priv.Sign(digest[:])
+	require.Nil(t, err)
+	ok, err := priv.PublicKey().Verify(digest[:], sig)
+	require.Nil(t, err)
+	require.True(t, ok)
+	ok, err = asym.Verify(crypto2.SM2, sig, digest[:], *addr)
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	// signatures are verified against the signer and the digest
+	other, err := New()
+	require.Nil(t, err)
+	otherAddr, err := other.PublicKey().Address()
+	require.Nil(t, err)
+	_, err = asym.Verify(crypto2.SM2, sig, digest[:], *otherAddr)
+	require.NotNil(t, err)
+	_, err = priv.PublicKey().Verify(digest[1:], sig)
+	require.NotNil(t, err)
+
+	// signatures with key type
+	typedSig, err := asym.SignWithType(priv, digest[:])
+	require.Nil(t, err)
+	ok, err = asym.VerifyWithType(typedSig, digest[:], *addr)
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	// key store
+	dir, err := ioutil.TempDir("", "sm2")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "key.json")
+	require.Nil(t, asym.StorePrivateKey(priv, path, "bitxhub"))
+	restored, err := asym.RestorePrivateKey(path, "bitxhub")
+	require.Nil(t, err)
+	restoredAddr, err := restored.PublicKey().Address()
+	require.Nil(t, err)
+	require.Equal(t, addr.String(), restoredAddr.String())
+}
+
+func TestLibp2pKey(t *testing.T) {
+	priv, err := New()
+	require.Nil(t, err)
+	privKey, pubKey, err := Libp2pKeyPairFromKey(priv.K)
+	require.Nil(t, err)
+	require.True(t, pubKey.Equals(privKey.GetPublic()))
+
+	data := []byte("handshake")
+	sig, err := privKey.Sign(data)
+	require.Nil(t, err)
+	ok, err := pubKey.Verify(data, sig)
+	require.Nil(t, err)
+	require.True(t, ok)
+	ok, err = pubKey.Verify([]byte("other"), sig)
+	require.Nil(t, err)
+	require.False(t, ok)
+
+	// keys are exchanged by the libp2p encoding
+	pubData, err := crypto.MarshalPublicKey(pubKey)
+	require.Nil(t, err)
+	unmarshaledPub, err := crypto.UnmarshalPublicKey(pubData)
+	require.Nil(t, err)
+	require.True(t, pubKey.Equals(unmarshaledPub))
+	privData, err := crypto.MarshalPrivateKey(privKey)
+	require.Nil(t, err)
+	unmarshaledPriv, err := crypto.UnmarshalPrivateKey(privData)
+	require.Nil(t, err)
+	require.True(t, privKey.Equals(unmarshaledPriv))
+
+	pid, err := peer.IDFromPrivateKey(privKey)
+	require.Nil(t, err)
+	require.True(t, pid.MatchesPublicKey(unmarshaledPub))
+}
+
+func issueCert(t *testing.T, key *sm2.PrivateKey, parent *gmx509.Certificate, parentKey *sm2.PrivateKey) *gmx509.Certificate {
+	template := &gmx509.Certificate{
+		SignatureAlgorithm:    gmx509.SM2WithSM3,
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+		KeyUsage:              gmx509.KeyUsageDigitalSignature | gmx509.KeyUsageCertSign | gmx509.KeyUsageCRLSign,
+		Subject:               pkix.Name{Organization: []string{"BitXHub"}},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	data, err := gmx509.CreateCertificate(template, parent, &key.PublicKey, parentKey)
+	require.Nil(t, err)
+	cert, err := gmx509.ParseCertificate(data)
+	require.Nil(t, err)
+	return cert
+}
+
+func TestCert(t *testing.T) {
+	caKey, err := sm2.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	nodeKey, err := sm2.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	gmCACert := issueCert(t, caKey, nil, nil)
+	gmNodeCert := issueCert(t, nodeKey, gmCACert, caKey)
+
+	// SM2 certificates are not supported by the standard x509
+	_, err = x509.ParseCertificate(gmNodeCert.Raw)
+	require.NotNil(t, err)
+	caCert, err := ParseCert(gmCACert.Raw)
+	require.Nil(t, err)
+	nodeCert, err := ParseCert(gmNodeCert.Raw)
+	require.Nil(t, err)
+	require.True(t, IsSM2Cert(nodeCert))
+
+	require.Nil(t, CheckSignatureFrom(nodeCert, caCert))
+	require.NotNil(t, CheckSignatureFrom(caCert, nodeCert))
+
+	// the public key of the certificate is the raw libp2p public key
+	_, pubKey, err := Libp2pKeyPairFromKey(nodeKey)
+	require.Nil(t, err)
+	raw, err := pubKey.Raw()
+	require.Nil(t, err)
+	certKey, err := MarshalCertPublicKey(nodeCert)
+	require.Nil(t, err)
+	require.Equal(t, raw, certKey)
+
+	crlData, err := gmCACert.CreateCRL(rand.Reader, caKey, []pkix.RevokedCertificate{{SerialNumber: nodeCert.SerialNumber, RevocationTime: time.Now()}}, time.Now(), time.Now().Add(time.Hour))
+	require.Nil(t, err)
+	crl, err := x509.ParseDERCRL(crlData)
+	require.Nil(t, err)
+	require.Nil(t, CheckCRLSignature(caCert, crl))
+	require.NotNil(t, CheckCRLSignature(nodeCert, crl))
+}
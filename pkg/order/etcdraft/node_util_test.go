@@ -2,9 +2,13 @@ package etcdraft
 
 import (
 	"context"
+	stdecdsa "crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
-	"io/ioutil"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -32,6 +36,7 @@ import (
 	raftproto "github.com/meshplus/bitxhub/pkg/order/etcdraft/proto"
 	"github.com/meshplus/bitxhub/pkg/order/mempool"
 	"github.com/meshplus/bitxhub/pkg/peermgr"
+	"github.com/meshplus/bitxhub/pkg/signer"
 	libp2pcert "github.com/meshplus/go-libp2p-cert"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -245,6 +250,47 @@ func convertToLibp2pPrivKey(privateKey crypto.PrivateKey) (crypto2.PrivKey, erro
 	return libp2pPrivKey, nil
 }
 
+// genCerts issues the node certificates for the libp2p keys of nodes by the same agency,
+// since the certificate presented by a peer must be issued for its key
+func genCerts(t *testing.T, nodeKeys []crypto2.PrivKey) []*libp2pcert.Certs {
+	issue := func(pubKey interface{}, key *stdecdsa.PrivateKey, parent *x509.Certificate, parentKey *stdecdsa.PrivateKey, isCA bool) (*x509.Certificate, []byte) {
+		template, err := libp2pcert.GenerateCert(key, isCA, "BitXHub")
+		require.Nil(t, err)
+		if parent == nil {
+			parent, parentKey = template, key
+		}
+		data, err := x509.CreateCertificate(rand.Reader, template, parent, pubKey, parentKey)
+		require.Nil(t, err)
+		cert, err := x509.ParseCertificate(data)
+		require.Nil(t, err)
+		return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: data})
+	}
+
+	caKey, err := stdecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	agencyKey, err := stdecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	caCert, _ := issue(caKey.Public(), caKey, nil, nil, true)
+	agencyCert, agencyData := issue(agencyKey.Public(), agencyKey, caCert, caKey, true)
+
+	var certs []*libp2pcert.Certs
+	for _, nodeKey := range nodeKeys {
+		raw, err := nodeKey.GetPublic().Raw()
+		require.Nil(t, err)
+		pubKey, err := x509.ParsePKIXPublicKey(raw)
+		require.Nil(t, err)
+		nodeCert, nodeData := issue(pubKey, agencyKey, agencyCert, agencyKey, false)
+		certs = append(certs, &libp2pcert.Certs{
+			NodeCertData:   nodeData,
+			AgencyCertData: agencyData,
+			NodeCert:       nodeCert,
+			AgencyCert:     agencyCert,
+			CACert:         caCert,
+		})
+	}
+	return certs
+}
+
 func newSwarms(t *testing.T, peerCnt int, certVerify bool) ([]*peermgr.Swarm, map[uint64]*pb.VpInfo) {
 	var swarms []*peermgr.Swarm
 	nodes := make(map[uint64]*pb.VpInfo)
@@ -269,19 +315,11 @@ func newSwarms(t *testing.T, peerCnt int, certVerify bool) ([]*peermgr.Swarm, ma
 		stateLedger.EXPECT().GetState(constant.NodeManagerContractAddr.Address(), []byte(node_mgr.NodeKey(accounts[i]))).Return(true, nodeData).AnyTimes()
 		stateLedger.EXPECT().GetState(constant.NodeManagerContractAddr.Address(), []byte(node_mgr.VpNodePidKey(pids[i]))).Return(true, []byte(accounts[i])).AnyTimes()
 	}
+	// no cert is revoked by governance
+	stateLedger.EXPECT().GetState(constant.NodeManagerContractAddr.Address(), gomock.Any()).Return(false, nil).AnyTimes()
 	stateLedger.EXPECT().Copy().Return(stateLedger).AnyTimes()
 
-	agencyData, err := ioutil.ReadFile("testdata/agency.cert")
-	require.Nil(t, err)
-
-	nodeData, err := ioutil.ReadFile("testdata/node.cert")
-	require.Nil(t, err)
-
-	caData, err := ioutil.ReadFile("testdata/ca.cert")
-	require.Nil(t, err)
-
-	cert, err := libp2pcert.ParseCert(caData)
-	require.Nil(t, err)
+	nodeCerts := genCerts(t, nodeKeys)
 
 	for i := 0; i < peerCnt; i++ {
 		ID := i + 1
@@ -291,11 +329,7 @@ func newSwarms(t *testing.T, peerCnt int, certVerify bool) ([]*peermgr.Swarm, ma
 				N:  uint64(peerCnt),
 				ID: uint64(ID),
 			},
-			Certs: &libp2pcert.Certs{
-				NodeCertData:   nodeData,
-				AgencyCertData: agencyData,
-				CACert:         cert,
-			},
+			Certs: nodeCerts[i],
 			Config: &repo.Config{
 				Ping: repo.Ping{
 					Duration: 2 * time.Second,
@@ -313,7 +347,7 @@ func newSwarms(t *testing.T, peerCnt int, certVerify bool) ([]*peermgr.Swarm, ma
 		local := addrs[i][:idx]
 		repo.NetworkConfig.LocalAddr = local
 		repo.Key.Libp2pPrivKey = nodeKeys[i]
-		repo.Key.PrivKey = privKeys[i]
+		repo.Key.PrivKey = signer.NewLocalSigner(privKeys[i])
 		repo.NetworkConfig.Nodes = peers(uint64(i), addrs, pids, accounts)
 
 		address, err := privKeys[i].PublicKey().Address()
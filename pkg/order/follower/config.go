@@ -0,0 +1,57 @@
+package follower
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type FollowerConfig struct {
+	Follower Follower
+}
+
+type Follower struct {
+	SyncInterval time.Duration `mapstructure:"sync_interval"`
+	BlockFetch   uint64        `mapstructure:"block_fetch"`
+}
+
+func defaultFollowerConfig() Follower {
+	return Follower{
+		SyncInterval: time.Second,
+		BlockFetch:   10,
+	}
+}
+
+func readConfig(repoRoot string) (*Follower, error) {
+	v := viper.New()
+	v.SetConfigFile(filepath.Join(repoRoot, "order.toml"))
+	v.SetConfigType("toml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("readInConfig error: %w", err)
+	}
+
+	config := &FollowerConfig{
+		Follower: defaultFollowerConfig(),
+	}
+
+	if err := v.Unmarshal(config); err != nil {
+		return nil, fmt.Errorf("unmarshal config error: %w", err)
+	}
+
+	if err := checkConfig(config); err != nil {
+		return nil, fmt.Errorf("check config failed: %w", err)
+	}
+	return &config.Follower, nil
+}
+
+func checkConfig(config *FollowerConfig) error {
+	if config.Follower.SyncInterval.Nanoseconds() <= 0 {
+		return fmt.Errorf("Illegal parameter, syncInterval must be a positive number. ")
+	}
+	if config.Follower.BlockFetch == 0 {
+		return fmt.Errorf("Illegal parameter, blockFetch must be a positive number. ")
+	}
+	return nil
+}
@@ -0,0 +1,540 @@
+package follower
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cbergoon/merkletree"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/meshplus/bitxhub-core/agency"
+	"github.com/meshplus/bitxhub-core/order"
+	orderPeerMgr "github.com/meshplus/bitxhub-core/peer-mgr"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/model"
+	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/meshplus/bitxhub/pkg/order/mempool"
+	"github.com/meshplus/bitxhub/pkg/peermgr"
+	"github.com/sirupsen/logrus"
+)
+
+// Node follows the chain from vp nodes without joining consensus. It pulls the blocks ahead of it,
+// checks them with the block signatures of a quorum of the validators recorded on chain and commits
+// them to be executed locally.
+type Node struct {
+	ID              uint64
+	commitC         chan *pb.CommitEvent // block channel
+	stateC          chan *mempool.ChainState
+	logger          logrus.FieldLogger
+	peerMgr         orderPeerMgr.OrderPeerManager // network manager
+	validators      validatorSource               // on-chain validator set
+	nodes           map[uint64]*pb.VpInfo         // vp nodes in network config
+	syncInterval    time.Duration                 // interval of pulling blocks
+	blockFetch      uint64                        // amount of blocks to be pulled at once
+	getAccountNonce func(address *types.Address) uint64
+	txFeed          event.Feed
+
+	lastExec uint64                   // the height of the last block committed to executor
+	lastHash *types.Hash              // the verified hash of the last committed block
+	pending  map[uint64]*pendingBlock // the verified blocks committed but not executed yet
+	refetch  uint64                   // the height of the block executed differently, 0 if there is none
+	excluded map[uint64]struct{}      // the vp nodes which served the block executed differently
+	forked   bool                     // the block is executed differently whichever vp node serves it
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+type pendingBlock struct {
+	header *pb.BlockHeader
+	hash   *types.Hash
+	from   uint64 // the vp node serving the block
+}
+
+// blockAnnouncer is implemented by the peer managers which gossip the headers of committed blocks
+type blockAnnouncer interface {
+	SubscribeTopic(topic string, ch chan<- peermgr.GossipMessageEvent) event.Subscription
+}
+
+// validatorSource is implemented by the peer managers which read the validators from node manager
+type validatorSource interface {
+	ValidatorSet() (map[uint64]*pb.VpInfo, error)
+}
+
+func init() {
+	agency.RegisterOrderConstructor("follower", NewNode)
+}
+
+func NewNode(opts ...order.Option) (order.Order, error) {
+	config, err := order.GenerateConfig(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("generate config: %w", err)
+	}
+	followerConfig, err := readConfig(config.RepoRoot)
+	if err != nil {
+		return nil, fmt.Errorf("read follower config: %w", err)
+	}
+
+	validators, ok := config.PeerMgr.(validatorSource)
+	if !ok {
+		return nil, fmt.Errorf("peer manager can't read the validator set")
+	}
+
+	node := &Node{
+		ID:              config.ID,
+		commitC:         make(chan *pb.CommitEvent, 1024),
+		stateC:          make(chan *mempool.ChainState),
+		logger:          config.Logger,
+		peerMgr:         config.PeerMgr,
+		validators:      validators,
+		nodes:           config.Nodes,
+		syncInterval:    followerConfig.SyncInterval,
+		blockFetch:      followerConfig.BlockFetch,
+		getAccountNonce: config.GetAccountNonce,
+		lastExec:        config.Applied,
+		lastHash:        types.NewHashByStr(config.Digest),
+		pending:         make(map[uint64]*pendingBlock),
+		excluded:        make(map[uint64]struct{}),
+	}
+	node.logger.Infof("Follower lastExec = %d", node.lastExec)
+	node.logger.Infof("Follower sync interval = %v", node.syncInterval)
+	return node, nil
+}
+
+func (n *Node) Start() error {
+	n.ctx, n.cancel = context.WithCancel(context.Background())
+	go n.listenChain()
+	return nil
+}
+
+func (n *Node) Stop() {
+	n.cancel()
+	n.logger.Info("follower stopped")
+}
+
+// Prepare refuses the transactions, which should be sent to vp nodes
+func (n *Node) Prepare(tx pb.Transaction) error {
+	return fmt.Errorf("follower is read-only, send transaction %s to vp nodes", tx.GetHash().String())
+}
+
+func (n *Node) Commit() chan *pb.CommitEvent {
+	return n.commitC
+}
+
+func (n *Node) Step([]byte) error {
+	return nil
+}
+
+func (n *Node) Ready() error {
+	return nil
+}
+
+func (n *Node) ReportState(height uint64, blockHash *types.Hash, txHashList []*types.Hash) {
+	n.stateC <- &mempool.ChainState{
+		Height:     height,
+		BlockHash:  blockHash,
+		TxHashList: txHashList,
+	}
+}
+
+// Quorum returns the bft quorum of the validators, which is also the number of block signatures a block needs
+func (n *Node) Quorum() uint64 {
+	validators, err := n.validators.ValidatorSet()
+	if err != nil {
+		n.logger.Warnf("Get validator set error: %s, use the vp nodes in network config", err)
+		return calcQuorum(len(n.nodes))
+	}
+	return calcQuorum(len(validators))
+}
+
+func calcQuorum(N int) uint64 {
+	F := (N - 1) / 3
+	return uint64(math.Ceil((float64(N) + float64(F) + 1) / 2.0))
+}
+
+func (n *Node) GetPendingNonceByAccount(account string) uint64 {
+	return n.getAccountNonce(types.NewAddressByStr(account))
+}
+
+func (n *Node) GetPendingTxByHash(hash *types.Hash) pb.Transaction {
+	return nil
+}
+
+func (n *Node) DelNode(uint64) error {
+	return nil
+}
+
+func (n *Node) SubscribeTxEvent(ch chan<- pb.Transactions) event.Subscription {
+	return n.txFeed.Subscribe(ch)
+}
+
+func (n *Node) listenChain() {
+	ticker := time.NewTicker(n.syncInterval)
+	defer ticker.Stop()
+
+	// announcements of committed blocks trigger syncing without waiting for the ticker
+	announceC := make(chan peermgr.GossipMessageEvent, 16)
+	if announcer, ok := n.peerMgr.(blockAnnouncer); ok {
+		sub := announcer.SubscribeTopic(peermgr.BlockTopic, announceC)
+		defer sub.Unsubscribe()
+	}
+
+	for {
+		select {
+		case <-n.ctx.Done():
+			n.logger.Info("----- Exit listen chain loop -----")
+			return
+
+		case state := <-n.stateC:
+			n.checkState(state)
+
+		case <-ticker.C:
+			n.trySync()
+
+		case ev := <-announceC:
+			header := &pb.BlockHeader{}
+			if err := header.Unmarshal(ev.Data); err != nil || header.Number <= n.lastExec {
+				continue
+			}
+			n.logger.WithFields(logrus.Fields{
+				"height": header.Number,
+				"from":   ev.From,
+			}).Debug("Receive block announcement")
+			n.trySync()
+		}
+	}
+}
+
+// trySync pulls the blocks ahead after all committed blocks are executed
+func (n *Node) trySync() {
+	if n.forked || len(n.pending) != 0 {
+		return
+	}
+	n.refetch = 0
+	if err := n.syncBlocks(); err != nil {
+		n.logger.WithFields(logrus.Fields{
+			"height": n.lastExec + 1,
+			"error":  err,
+		}).Warn("Sync blocks from vp nodes failed")
+	}
+}
+
+// checkState compares the executed block with the verified one. The block executed differently is refetched
+// from another vp node once the committed blocks are executed, and the executor rolls back to its parent when it
+// is committed again. The follower stops following if the block is executed differently whichever vp node serves it.
+func (n *Node) checkState(state *mempool.ChainState) {
+	block, ok := n.pending[state.Height]
+	if !ok {
+		return
+	}
+	delete(n.pending, state.Height)
+
+	// the blocks after the one executed differently are refetched as well
+	if n.refetch != 0 && state.Height > n.refetch {
+		return
+	}
+
+	if block.hash.String() == state.BlockHash.String() {
+		if len(n.excluded) != 0 {
+			n.excluded = make(map[uint64]struct{})
+		}
+		return
+	}
+
+	n.excluded[block.from] = struct{}{}
+	fields := logrus.Fields{
+		"height":   state.Height,
+		"expected": block.hash.String(),
+		"executed": state.BlockHash.String(),
+		"from":     block.from,
+	}
+	validators, err := n.validators.ValidatorSet()
+	if err != nil || len(n.excluded) >= len(n.vpPeers(validators)) {
+		n.forked = true
+		n.logger.WithFields(fields).Error("Executed block doesn't match the block of vp nodes, stop following")
+		return
+	}
+	n.refetch = state.Height
+	n.lastExec = state.Height - 1
+	n.lastHash = block.header.ParentHash
+	n.logger.WithFields(fields).Warn("Executed block doesn't match the block of vp nodes, refetch it from another vp node")
+}
+
+// syncBlocks pulls the blocks ahead of the follower and commits them if they are signed by a quorum of validators
+func (n *Node) syncBlocks() error {
+	validators, err := n.validators.ValidatorSet()
+	if err != nil {
+		return fmt.Errorf("get validator set: %w", err)
+	}
+	peers := n.vpPeers(validators)
+	if len(peers) == 0 {
+		return fmt.Errorf("no vp node to follow")
+	}
+	rand.Shuffle(len(peers), func(i, j int) {
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+
+	begin := n.lastExec + 1
+	headers, err := n.fetchBlockHeaders(peers, begin, n.lastExec+n.blockFetch)
+	if err != nil {
+		return err
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+
+	// the headers are chained, so the signatures of the last one cover all of them
+	last := headers[len(headers)-1]
+	lastHash := (&pb.Block{BlockHeader: last}).Hash()
+	if err := n.verifyBlockSigns(peers, calcQuorum(len(validators)), last.Number, lastHash); err != nil {
+		return err
+	}
+
+	blocks, from, err := n.fetchBlocks(peers, headers)
+	if err != nil {
+		return err
+	}
+
+	for i, block := range blocks {
+		n.pending[block.Height()] = &pendingBlock{
+			header: headers[i],
+			hash:   block.BlockHash,
+			from:   from,
+		}
+		n.commitC <- &pb.CommitEvent{
+			Block: &pb.Block{
+				BlockHeader:  block.BlockHeader,
+				Transactions: block.Transactions,
+			},
+		}
+	}
+	n.lastExec = last.Number
+	n.lastHash = lastHash
+	n.logger.WithFields(logrus.Fields{
+		"begin": begin,
+		"end":   last.Number,
+	}).Info("Commit blocks from vp nodes")
+
+	return nil
+}
+
+// fetchBlockHeaders fetches the headers in [begin, end] which follow the last committed block from any vp node
+func (n *Node) fetchBlockHeaders(peers []*pb.VpInfo, begin, end uint64) ([]*pb.BlockHeader, error) {
+	req := &pb.GetBlockHeadersRequest{
+		Start: begin,
+		End:   end,
+	}
+	data, err := req.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("marshal get block headers request error: %w", err)
+	}
+
+	var headers []*pb.BlockHeader
+	for _, p := range peers {
+		res, err := n.peerMgr.Send(p.Id, &pb.Message{Type: pb.Message_GET_BLOCK_HEADERS, Data: data})
+		if err != nil {
+			n.logger.Debugf("fetch block headers from %d error: %s", p.Id, err)
+			continue
+		}
+		resp := &pb.GetBlockHeadersResponse{}
+		if err := resp.Unmarshal(res.Data); err != nil {
+			n.logger.Warnf("unmarshal block headers from %d error: %s", p.Id, err)
+			continue
+		}
+		if err := n.verifyBlockHeaders(begin, resp.BlockHeaders); err != nil {
+			n.logger.Warnf("verify block headers from %d error: %s", p.Id, err)
+			continue
+		}
+		// prefer the vp node with the most blocks ahead
+		if len(resp.BlockHeaders) > len(headers) {
+			headers = resp.BlockHeaders
+		}
+		if uint64(len(headers)) == end-begin+1 {
+			break
+		}
+	}
+
+	return headers, nil
+}
+
+func (n *Node) verifyBlockHeaders(begin uint64, headers []*pb.BlockHeader) error {
+	parentHash := n.lastHash
+	for i, header := range headers {
+		if header.Number != begin+uint64(i) {
+			return fmt.Errorf("expect block %d, but got %d", begin+uint64(i), header.Number)
+		}
+		if header.ParentHash == nil || header.ParentHash.String() != parentHash.String() {
+			return fmt.Errorf("parent hash of block %d is %v, but expect %s", header.Number, header.ParentHash, parentHash.String())
+		}
+		parentHash = (&pb.Block{BlockHeader: header}).Hash()
+	}
+	return nil
+}
+
+// verifyBlockSigns collects the signatures of the block from validators until a quorum of them are valid
+func (n *Node) verifyBlockSigns(peers []*pb.VpInfo, quorum, height uint64, hash *types.Hash) error {
+	var signed uint64
+	for _, p := range peers {
+		if err := n.verifyBlockSign(p, height, hash); err != nil {
+			n.logger.Warnf("verify sign of block %d from %d error: %s", height, p.Id, err)
+			continue
+		}
+		signed++
+		if signed >= quorum {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("block %d is signed by %d vp nodes, less than quorum %d", height, signed, quorum)
+}
+
+func (n *Node) verifyBlockSign(p *pb.VpInfo, height uint64, hash *types.Hash) error {
+	res, err := n.peerMgr.Send(p.Id, &pb.Message{
+		Type: pb.Message_FETCH_BLOCK_SIGN,
+		Data: []byte(strconv.FormatUint(height, 10)),
+	})
+	if err != nil {
+		return fmt.Errorf("fetch block sign: %w", err)
+	}
+	if res == nil || res.Type != pb.Message_FETCH_BLOCK_SIGN_ACK {
+		return fmt.Errorf("invalid fetch block sign resp")
+	}
+
+	sign := &model.MerkleWrapperSign{}
+	if err := sign.Unmarshal(res.Data); err != nil {
+		return fmt.Errorf("unmarshal merkle wrapper sign error: %w", err)
+	}
+	addr := types.NewAddressByStr(p.Account)
+	if sign.Address != addr.String() {
+		return fmt.Errorf("block is signed by %s instead of %s", sign.Address, addr.String())
+	}
+	ok, err := repo.VerifySign(sign.Signature, hash.Bytes(), *addr)
+	if err != nil {
+		return fmt.Errorf("verify signature: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("invalid signature")
+	}
+
+	return nil
+}
+
+// fetchBlocks fetches the blocks of the verified headers from any vp node which didn't serve the block executed
+// differently, and returns the id of the vp node serving the blocks
+func (n *Node) fetchBlocks(peers []*pb.VpInfo, headers []*pb.BlockHeader) ([]*pb.Block, uint64, error) {
+	req := &pb.GetBlocksRequest{
+		Start: headers[0].Number,
+		End:   headers[len(headers)-1].Number,
+	}
+	data, err := req.Marshal()
+	if err != nil {
+		return nil, 0, fmt.Errorf("marshal get blocks request error: %w", err)
+	}
+
+	for _, p := range peers {
+		if _, ok := n.excluded[p.Id]; ok {
+			continue
+		}
+		res, err := n.peerMgr.Send(p.Id, &pb.Message{Type: pb.Message_GET_BLOCKS, Data: data})
+		if err != nil {
+			n.logger.Debugf("fetch blocks from %d error: %s", p.Id, err)
+			continue
+		}
+		resp := &pb.GetBlocksResponse{}
+		if err := resp.Unmarshal(res.Data); err != nil {
+			n.logger.Warnf("unmarshal blocks from %d error: %s", p.Id, err)
+			continue
+		}
+		if err := verifyBlocks(headers, resp.Blocks); err != nil {
+			n.logger.Warnf("verify blocks from %d error: %s", p.Id, err)
+			continue
+		}
+		return resp.Blocks, p.Id, nil
+	}
+
+	return nil, 0, fmt.Errorf("fetch blocks in [%d, %d] failed", req.Start, req.End)
+}
+
+func verifyBlocks(headers []*pb.BlockHeader, blocks []*pb.Block) error {
+	if len(blocks) != len(headers) {
+		return fmt.Errorf("expect %d blocks, but got %d", len(headers), len(blocks))
+	}
+	for i, block := range blocks {
+		hash := (&pb.Block{BlockHeader: headers[i]}).Hash()
+		if block.BlockHash == nil || block.BlockHash.String() != hash.String() {
+			return fmt.Errorf("hash of block %d doesn't match the header", headers[i].Number)
+		}
+		if block.Transactions == nil {
+			block.Transactions = &pb.Transactions{}
+		}
+		txRoot, err := calcTxRoot(block.Transactions.Transactions)
+		if err != nil {
+			return fmt.Errorf("calculate tx root of block %d: %w", headers[i].Number, err)
+		}
+		root := headers[i].TxRoot
+		if root == nil {
+			root = &types.Hash{}
+		}
+		if root.String() != txRoot.String() {
+			return fmt.Errorf("transactions of block %d don't match the tx root", headers[i].Number)
+		}
+		for _, tx := range block.Transactions.Transactions {
+			if err := tx.VerifySignature(); err != nil {
+				return fmt.Errorf("verify signature of tx %s in block %d: %w", tx.GetHash().String(), headers[i].Number, err)
+			}
+		}
+	}
+	return nil
+}
+
+// calcTxRoot calculates the tx root with the hashes recomputed from the transactions, the transactions
+// carrying other hashes are refused since the executor takes the hashes they carry
+func calcTxRoot(txs []pb.Transaction) (*types.Hash, error) {
+	if len(txs) == 0 {
+		return &types.Hash{}, nil
+	}
+
+	txHashes := make([]merkletree.Content, 0, len(txs))
+	for _, tx := range txs {
+		hash := txHash(tx)
+		if tx.GetHash() == nil || tx.GetHash().String() != hash.String() {
+			return nil, fmt.Errorf("tx hash %v doesn't match the computed one %s", tx.GetHash(), hash.String())
+		}
+		txHashes = append(txHashes, hash)
+	}
+	tree, err := merkletree.NewTree(txHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewHash(tree.MerkleRoot()), nil
+}
+
+func txHash(tx pb.Transaction) *types.Hash {
+	if bxhTx, ok := tx.(*pb.BxhTransaction); ok {
+		return bxhTx.Hash()
+	}
+	return tx.GetHash()
+}
+
+// vpPeers returns the connected vp nodes which are validators sorted by id, the accounts signing blocks
+// are the ones recorded on chain
+func (n *Node) vpPeers(validators map[uint64]*pb.VpInfo) []*pb.VpInfo {
+	peers := make([]*pb.VpInfo, 0)
+	for id := range n.peerMgr.OrderPeers() {
+		validator, ok := validators[id]
+		if id == n.ID || !ok {
+			continue
+		}
+		peers = append(peers, validator)
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Id < peers[j].Id
+	})
+	return peers
+}
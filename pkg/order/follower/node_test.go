@@ -0,0 +1,230 @@
+package follower
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/golang/mock/gomock"
+	"github.com/meshplus/bitxhub-core/order"
+	"github.com/meshplus/bitxhub-kit/crypto"
+	"github.com/meshplus/bitxhub-kit/crypto/asym"
+	"github.com/meshplus/bitxhub-kit/log"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/model"
+	"github.com/meshplus/bitxhub/pkg/peermgr"
+	"github.com/meshplus/bitxhub/pkg/peermgr/mock_peermgr"
+	"github.com/stretchr/testify/require"
+)
+
+const to = "0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b997"
+
+func TestNode_SyncBlocks(t *testing.T) {
+	repoRoot, err := ioutil.TempDir("", "follower")
+	require.Nil(t, err)
+	defer os.RemoveAll(repoRoot)
+	fileData, err := ioutil.ReadFile("./testdata/order.toml")
+	require.Nil(t, err)
+	require.Nil(t, ioutil.WriteFile(filepath.Join(repoRoot, "order.toml"), fileData, 0644))
+
+	// 4 vp nodes, the 4th one signs blocks with a wrong key
+	privKeys := make(map[uint64]crypto.PrivateKey)
+	peers := make(map[uint64]*pb.VpInfo)
+	for id := uint64(1); id <= 4; id++ {
+		privKey, err := asym.GenerateKeyPair(crypto.Secp256k1)
+		require.Nil(t, err)
+		addr, err := privKey.PublicKey().Address()
+		require.Nil(t, err)
+		privKeys[id] = privKey
+		peers[id] = &pb.VpInfo{Id: id, Account: addr.String()}
+	}
+	wrongKey, err := asym.GenerateKeyPair(crypto.Secp256k1)
+	require.Nil(t, err)
+	privKeys[4] = wrongKey
+
+	genesisHash := types.NewHashByStr("0x9f41dd84524bf8a42f8ab58ecfca6e1752d6fd93fe8dc00af4c71963c97db59f")
+	blocks := genBlocks(t, genesisHash, 8)
+	chainHeight := uint64(6)
+
+	mockCtl := gomock.NewController(t)
+	mockPeerMgr := mock_peermgr.NewMockPeerManager(mockCtl)
+	mockPeerMgr.EXPECT().OrderPeers().Return(peers).AnyTimes()
+	mockPeerMgr.EXPECT().ValidatorSet().Return(peers, nil).AnyTimes()
+	var announceFeed event.Feed
+	mockPeerMgr.EXPECT().SubscribeTopic(peermgr.BlockTopic, gomock.Any()).DoAndReturn(
+		func(topic string, ch chan<- peermgr.GossipMessageEvent) event.Subscription {
+			return announceFeed.Subscribe(ch)
+		}).AnyTimes()
+	mockPeerMgr.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(id uint64, m *pb.Message) (*pb.Message, error) {
+		switch m.Type {
+		case pb.Message_GET_BLOCK_HEADERS:
+			req := &pb.GetBlockHeadersRequest{}
+			require.Nil(t, req.Unmarshal(m.Data))
+			res := &pb.GetBlockHeadersResponse{}
+			for i := req.Start; i <= req.End && i <= atomic.LoadUint64(&chainHeight); i++ {
+				res.BlockHeaders = append(res.BlockHeaders, blocks[i].BlockHeader)
+			}
+			data, err := res.Marshal()
+			require.Nil(t, err)
+			return &pb.Message{Type: pb.Message_GET_BLOCK_HEADERS_ACK, Data: data}, nil
+		case pb.Message_GET_BLOCKS:
+			req := &pb.GetBlocksRequest{}
+			require.Nil(t, req.Unmarshal(m.Data))
+			res := &pb.GetBlocksResponse{}
+			for i := req.Start; i <= req.End && i <= atomic.LoadUint64(&chainHeight); i++ {
+				res.Blocks = append(res.Blocks, blocks[i])
+			}
+			data, err := res.Marshal()
+			require.Nil(t, err)
+			return &pb.Message{Type: pb.Message_GET_BLOCKS_ACK, Data: data}, nil
+		case pb.Message_FETCH_BLOCK_SIGN:
+			var height uint64
+			_, err := fmt.Sscanf(string(m.Data), "%d", &height)
+			require.Nil(t, err)
+			signed, err := privKeys[id].Sign(blocks[height].BlockHash.Bytes())
+			require.Nil(t, err)
+			sign := &model.MerkleWrapperSign{Address: peers[id].Account, Signature: signed}
+			data, err := sign.Marshal()
+			require.Nil(t, err)
+			return &pb.Message{Type: pb.Message_FETCH_BLOCK_SIGN_ACK, Data: data}, nil
+		}
+		return nil, fmt.Errorf("unsupported message type %s", m.Type)
+	}).AnyTimes()
+
+	follower, err := NewNode(
+		order.WithRepoRoot(repoRoot),
+		order.WithLogger(log.NewWithModule("consensus")),
+		order.WithPeerManager(mockPeerMgr),
+		order.WithID(5),
+		order.WithNodes(peers),
+		order.WithApplied(1),
+		order.WithDigest(genesisHash.String()),
+		order.WithGetAccountNonceFunc(func(address *types.Address) uint64 {
+			return 0
+		}),
+	)
+	require.Nil(t, err)
+	require.Equal(t, uint64(3), follower.Quorum())
+	require.Nil(t, follower.Step(nil))
+	require.Nil(t, follower.Ready())
+	require.NotNil(t, follower.Prepare(blocks[2].Transactions.Transactions[0]))
+
+	require.Nil(t, follower.Start())
+	defer follower.Stop()
+
+	// blocks are committed in order and executed
+	for height := uint64(2); height <= 6; height++ {
+		select {
+		case ev := <-follower.Commit():
+			require.Equal(t, height, ev.Block.Height())
+			require.Equal(t, 1, len(ev.Block.Transactions.Transactions))
+			follower.ReportState(height, blocks[height].BlockHash, nil)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, fmt.Sprintf("block %d is not committed", height))
+		}
+	}
+
+	// the block executed differently is refetched from another vp node
+	atomic.StoreUint64(&chainHeight, 7)
+	for i := 0; i < 2; i++ {
+		select {
+		case ev := <-follower.Commit():
+			require.Equal(t, uint64(7), ev.Block.Height())
+			if i == 0 {
+				follower.ReportState(7, blocks[2].BlockHash, nil)
+			} else {
+				follower.ReportState(7, blocks[7].BlockHash, nil)
+			}
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "block 7 is not committed")
+		}
+	}
+
+	// the block executed differently whichever vp node serves it stops the follower
+	atomic.StoreUint64(&chainHeight, 8)
+	for i := 0; i < 4; i++ {
+		select {
+		case ev := <-follower.Commit():
+			require.Equal(t, uint64(8), ev.Block.Height())
+			follower.ReportState(8, blocks[2].BlockHash, nil)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "block 8 is not committed")
+		}
+	}
+	select {
+	case ev := <-follower.Commit():
+		require.Fail(t, fmt.Sprintf("block %d is committed after fork", ev.Block.Height()))
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+func TestVerifyBlocks(t *testing.T) {
+	genesisHash := types.NewHashByStr("0x9f41dd84524bf8a42f8ab58ecfca6e1752d6fd93fe8dc00af4c71963c97db59f")
+	blocks := genBlocks(t, genesisHash, 3)
+	headers := []*pb.BlockHeader{blocks[2].BlockHeader, blocks[3].BlockHeader}
+	require.Nil(t, verifyBlocks(headers, []*pb.Block{blocks[2], blocks[3]}))
+
+	// transactions carrying the hash of another transaction
+	tx := blocks[3].Transactions.Transactions[0].(*pb.BxhTransaction)
+	forged := *tx
+	forged.Nonce++
+	block := *blocks[3]
+	block.Transactions = &pb.Transactions{Transactions: []pb.Transaction{&forged}}
+	require.NotNil(t, verifyBlocks(headers, []*pb.Block{blocks[2], &block}))
+
+	// transactions with invalid signatures
+	otherKey, err := asym.GenerateKeyPair(crypto.Secp256k1)
+	require.Nil(t, err)
+	require.Nil(t, forged.Sign(otherKey))
+	forged.TransactionHash = forged.Hash()
+	txRoot, err := calcTxRoot([]pb.Transaction{&forged})
+	require.Nil(t, err)
+	header := *blocks[3].BlockHeader
+	header.TxRoot = txRoot
+	block.BlockHeader = &header
+	block.BlockHash = block.Hash()
+	require.NotNil(t, verifyBlocks([]*pb.BlockHeader{blocks[2].BlockHeader, &header}, []*pb.Block{blocks[2], &block}))
+}
+
+func genBlocks(t *testing.T, genesisHash *types.Hash, height uint64) map[uint64]*pb.Block {
+	privKey, err := asym.GenerateKeyPair(crypto.Secp256k1)
+	require.Nil(t, err)
+	from, err := privKey.PublicKey().Address()
+	require.Nil(t, err)
+
+	blocks := make(map[uint64]*pb.Block)
+	parentHash := genesisHash
+	for i := uint64(2); i <= height; i++ {
+		tx := &pb.BxhTransaction{
+			From:      from,
+			To:        types.NewAddressByStr(to),
+			Timestamp: time.Now().UnixNano(),
+			Nonce:     i,
+		}
+		require.Nil(t, tx.Sign(privKey))
+		tx.TransactionHash = tx.Hash()
+		txs := []pb.Transaction{tx}
+		txRoot, err := calcTxRoot(txs)
+		require.Nil(t, err)
+
+		block := &pb.Block{
+			BlockHeader: &pb.BlockHeader{
+				Number:     i,
+				ParentHash: parentHash,
+				TxRoot:     txRoot,
+				Timestamp:  time.Now().UnixNano(),
+			},
+			Transactions: &pb.Transactions{Transactions: txs},
+		}
+		block.BlockHash = block.Hash()
+		blocks[i] = block
+		parentHash = block.BlockHash
+	}
+	return blocks
+}
@@ -0,0 +1,207 @@
+package peermgr
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/sec"
+	"github.com/meshplus/bitxhub/internal/model"
+	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/meshplus/bitxhub/pkg/crypto/sm2"
+	libp2pcert "github.com/meshplus/go-libp2p-cert"
+)
+
+const (
+	// certTransportID is the security protocol which exchanges the certs over the secured
+	// connection after the cert handshake, so that they are checked before the connection is set up
+	certTransportID = libp2pcert.ID + "/revocation/1.0.0"
+	// sm2CertTransportID is the security protocol of nodes with SM2 keys, the connection is secured
+	// by sm2Transport and the certs are exchanged and checked in the same way
+	sm2CertTransportID = libp2pcert.ID + "/sm2/revocation/1.0.0"
+
+	certExchangeTimeout = 10 * time.Second
+	maxCertsMessageSize = 64 * 1024
+)
+
+var _ sec.SecureTransport = (*certTransport)(nil)
+
+// certTransport wraps the cert transport of libp2p, or sm2Transport for SM2 keys, it refuses the
+// peers whose certs are revoked or not issued for their keys during the security handshake.
+// The certs can be replaced without closing the existing connections.
+type certTransport struct {
+	privKey    crypto.PrivKey
+	revocation *certRevocation
+
+	mu    sync.RWMutex
+	tpt   sec.SecureTransport
+	certs *libp2pcert.Certs
+
+	// peerCerts records the latest certs of every secured peer, pid -> *peerCerts
+	peerCerts sync.Map
+}
+
+type peerCerts struct {
+	pubKey     crypto.PubKey
+	nodeCert   *x509.Certificate
+	agencyCert *x509.Certificate
+}
+
+func newCertTransport(privKey crypto.PrivKey, certs *libp2pcert.Certs, revocation *certRevocation) (*certTransport, error) {
+	t := &certTransport{
+		privKey:    privKey,
+		revocation: revocation,
+	}
+	if err := t.setCerts(certs); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// setCerts replaces the local certs, which are used by the handshakes of new connections
+func (t *certTransport) setCerts(certs *libp2pcert.Certs) error {
+	var tpt sec.SecureTransport
+	var err error
+	if t.privKey.Type() == sm2.KeyType_SM2 {
+		tpt, err = newSM2Transport(t.privKey)
+	} else {
+		tpt, err = libp2pcert.New(t.privKey, certs)
+	}
+	if err != nil {
+		return fmt.Errorf("create transport: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tpt = tpt
+	t.certs = certs
+	return nil
+}
+
+// protocolID returns the security protocol of the transport, which depends on the type of the key
+func (t *certTransport) protocolID() string {
+	if t.privKey.Type() == sm2.KeyType_SM2 {
+		return sm2CertTransportID
+	}
+	return certTransportID
+}
+
+func (t *certTransport) current() (sec.SecureTransport, *libp2pcert.Certs) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tpt, t.certs
+}
+
+func (t *certTransport) SecureInbound(ctx context.Context, insecure net.Conn) (sec.SecureConn, error) {
+	tpt, certs := t.current()
+	conn, err := tpt.SecureInbound(ctx, insecure)
+	if err != nil {
+		return nil, err
+	}
+	return t.checkConn(ctx, conn, certs)
+}
+
+func (t *certTransport) SecureOutbound(ctx context.Context, insecure net.Conn, p peer.ID) (sec.SecureConn, error) {
+	tpt, certs := t.current()
+	conn, err := tpt.SecureOutbound(ctx, insecure, p)
+	if err != nil {
+		return nil, err
+	}
+	return t.checkConn(ctx, conn, certs)
+}
+
+// checkConn exchanges the certs with the remote peer and closes the connection if they are refused
+func (t *certTransport) checkConn(ctx context.Context, conn sec.SecureConn, certs *libp2pcert.Certs) (sec.SecureConn, error) {
+	nodeCert, agencyCert, err := exchangeCerts(ctx, conn, certs)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("exchange certs: %w", err)
+	}
+
+	pid := conn.RemotePeer().String()
+	if err := t.revocation.checkPeer(pid, conn.RemotePublicKey(), nodeCert, agencyCert); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	t.peerCerts.Store(pid, &peerCerts{
+		pubKey:     conn.RemotePublicKey(),
+		nodeCert:   nodeCert,
+		agencyCert: agencyCert,
+	})
+	return conn, nil
+}
+
+func (t *certTransport) getPeerCerts(pid string) (*peerCerts, bool) {
+	v, ok := t.peerCerts.Load(pid)
+	if !ok {
+		return nil, false
+	}
+	return v.(*peerCerts), true
+}
+
+// exchangeCerts sends the local certs and reads the certs of the remote peer at the same time
+func exchangeCerts(ctx context.Context, conn sec.SecureConn, certs *libp2pcert.Certs) (*x509.Certificate, *x509.Certificate, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(certExchangeTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, nil, err
+	}
+	defer conn.SetDeadline(time.Time{})
+
+	local := &model.CertsMessage{
+		AgencyCert: certs.AgencyCertData,
+		NodeCert:   certs.NodeCertData,
+	}
+	data, err := local.Marshal()
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal certs: %w", err)
+	}
+
+	writeC := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 4+len(data))
+		binary.BigEndian.PutUint32(buf, uint32(len(data)))
+		copy(buf[4:], data)
+		_, err := conn.Write(buf)
+		writeC <- err
+	}()
+
+	var size [4]byte
+	if _, err := io.ReadFull(conn, size[:]); err != nil {
+		return nil, nil, fmt.Errorf("read certs size: %w", err)
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxCertsMessageSize {
+		return nil, nil, fmt.Errorf("certs message of %d bytes is too large", n)
+	}
+	remoteData := make([]byte, n)
+	if _, err := io.ReadFull(conn, remoteData); err != nil {
+		return nil, nil, fmt.Errorf("read certs: %w", err)
+	}
+	if err := <-writeC; err != nil {
+		return nil, nil, fmt.Errorf("write certs: %w", err)
+	}
+
+	remote := &model.CertsMessage{}
+	if err := remote.Unmarshal(remoteData); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal certs: %w", err)
+	}
+	nodeCert, err := repo.ParseCert(remote.NodeCert)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse node cert: %w", err)
+	}
+	agencyCert, err := repo.ParseCert(remote.AgencyCert)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse agency cert: %w", err)
+	}
+	return nodeCert, agencyCert, nil
+}
@@ -0,0 +1,120 @@
+package peermgr
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/sec"
+	"github.com/meshplus/bitxhub-kit/log"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
+	"github.com/meshplus/bitxhub/internal/ledger"
+	"github.com/meshplus/bitxhub/internal/ledger/mock_ledger"
+	"github.com/meshplus/bitxhub/internal/repo"
+	libp2pcert "github.com/meshplus/go-libp2p-cert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCerts(t *testing.T, nodeCert, agencyCert, caCert *x509.Certificate) *libp2pcert.Certs {
+	return &libp2pcert.Certs{
+		NodeCertData:   pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: nodeCert.Raw}),
+		AgencyCertData: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: agencyCert.Raw}),
+		NodeCert:       nodeCert,
+		AgencyCert:     agencyCert,
+		CACert:         caCert,
+	}
+}
+
+func handshake(t *testing.T, local, remote *certTransport, remoteID peer.ID) (sec.SecureConn, error) {
+	c1, c2 := net.Pipe()
+	errC := make(chan error, 1)
+	go func() {
+		_, err := remote.SecureInbound(context.Background(), c2)
+		if err != nil {
+			_ = c2.Close()
+		}
+		errC <- err
+	}()
+	conn, err := local.SecureOutbound(context.Background(), c1, remoteID)
+	if err != nil {
+		_ = c1.Close()
+	}
+	<-errC
+	return conn, err
+}
+
+func TestCertTransport(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	agencyKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	caCert := issueTestCert(t, caKey, nil, nil, true)
+	agencyCert := issueTestCert(t, agencyKey, caCert, caKey, true)
+
+	var keys []crypto.PrivKey
+	var certs []*x509.Certificate
+	var pids []peer.ID
+	for i := 0; i < 2; i++ {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.Nil(t, err)
+		libp2pKey, _, err := crypto.ECDSAKeyPairFromKey(key)
+		require.Nil(t, err)
+		pid, err := peer.IDFromPrivateKey(libp2pKey)
+		require.Nil(t, err)
+		keys = append(keys, libp2pKey)
+		certs = append(certs, issueTestCert(t, key, agencyCert, agencyKey, false))
+		pids = append(pids, pid)
+	}
+
+	mockCtl := gomock.NewController(t)
+	stateLedger := mock_ledger.NewMockStateLedger(mockCtl)
+	revokedKey := contracts.CertRevocationKey(repo.CertFingerprint(certs[1]))
+	revoked := false
+	stateLedger.EXPECT().Copy().Return(stateLedger).AnyTimes()
+	stateLedger.EXPECT().GetState(gomock.Any(), gomock.Any()).DoAndReturn(func(addr *types.Address, key []byte) (bool, []byte) {
+		return revoked && string(key) == revokedKey, nil
+	}).AnyTimes()
+	lg := &ledger.Ledger{StateLedger: stateLedger}
+
+	r0 := newCertRevocation(log.NewWithModule("p2p"), lg, "", repo.Cert{}, caCert)
+	t0, err := newCertTransport(keys[0], testCerts(t, certs[0], agencyCert, caCert), r0)
+	require.Nil(t, err)
+	r1 := newCertRevocation(log.NewWithModule("p2p"), lg, "", repo.Cert{}, caCert)
+	t1, err := newCertTransport(keys[1], testCerts(t, certs[1], agencyCert, caCert), r1)
+	require.Nil(t, err)
+
+	// certs are exchanged and checked in the handshake
+	conn, err := handshake(t, t0, t1, pids[1])
+	require.Nil(t, err)
+	require.Nil(t, conn.Close())
+	peerCerts, ok := t0.getPeerCerts(pids[1].String())
+	require.True(t, ok)
+	require.Equal(t, certs[1].Raw, peerCerts.nodeCert.Raw)
+
+	// certs not issued for the key of the peer are refused without revoking the peer
+	require.Nil(t, t1.setCerts(testCerts(t, certs[0], agencyCert, caCert)))
+	_, err = handshake(t, t0, t1, pids[1])
+	require.NotNil(t, err)
+	require.False(t, r0.isRevoked(pids[1].String()))
+
+	// the rotated certs are used by new connections
+	require.Nil(t, t1.setCerts(testCerts(t, certs[1], agencyCert, caCert)))
+	conn, err = handshake(t, t0, t1, pids[1])
+	require.Nil(t, err)
+	require.Nil(t, conn.Close())
+
+	// peers with revoked certs are refused
+	revoked = true
+	_, err = handshake(t, t0, t1, pids[1])
+	require.NotNil(t, err)
+	require.True(t, r0.isRevoked(pids[1].String()))
+}
@@ -2,6 +2,7 @@ package peermgr
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/libp2p/go-libp2p-core/control"
@@ -18,16 +19,28 @@ import (
 type connectionGater struct {
 	logger logrus.FieldLogger
 	ledger *ledger.Ledger
+	scorer *peerScorer
+	// revocation refuses the peers with revoked certs, it is nil if certs are not verified
+	revocation *certRevocation
 }
 
-func newConnectionGater(logger logrus.FieldLogger, ledger *ledger.Ledger) *connectionGater {
+func newConnectionGater(logger logrus.FieldLogger, ledger *ledger.Ledger, scorer *peerScorer) *connectionGater {
 	return &connectionGater{
 		logger: logger,
 		ledger: ledger,
+		scorer: scorer,
 	}
 }
 
 func (g *connectionGater) InterceptPeerDial(p peer.ID) (allow bool) {
+	if g.scorer != nil && g.scorer.isBanned(p.String()) {
+		g.logger.Infof("Intercept dialing a banned peer, peer.Pid: %s", p.String())
+		return false
+	}
+	if g.revocation != nil && g.revocation.isRevoked(p.String()) {
+		g.logger.Infof("Intercept dialing a peer with revoked certs, peer.Pid: %s", p.String())
+		return false
+	}
 	return true
 }
 
@@ -40,22 +53,18 @@ func (g *connectionGater) InterceptAccept(addr network.ConnMultiaddrs) (allow bo
 }
 
 func (g *connectionGater) InterceptSecured(d network.Direction, p peer.ID, addr network.ConnMultiaddrs) (allow bool) {
-	lg := g.ledger.Copy()
-	ok, nodeAccount := lg.GetState(constant.NodeManagerContractAddr.Address(), []byte(node_mgr.VpNodePidKey(p.String())))
-	if !ok {
-		g.logger.Infof("Intercept a connection with an unavailable node(get node err: %s), peer.Pid: %s", string(nodeAccount), p.String())
+	if g.scorer != nil && g.scorer.isBanned(p.String()) {
+		g.logger.Infof("Intercept a connection with a banned peer, peer.Pid: %s", p.String())
 		return false
 	}
-	nodeAccountStr := strings.Trim(string(nodeAccount), "\"")
-	ok, nodeData := lg.GetState(constant.NodeManagerContractAddr.Address(), []byte(node_mgr.NodeKey(nodeAccountStr)))
-	if !ok {
-		g.logger.Errorf("InterceptSecured, node pid %s exist but node %s not exist: %v", p.String(), nodeAccountStr, string(nodeData))
+	if g.revocation != nil && g.revocation.isRevoked(p.String()) {
+		g.logger.Infof("Intercept a connection with a peer with revoked certs, peer.Pid: %s", p.String())
 		return false
 	}
 
-	node := &node_mgr.Node{}
-	if err := json.Unmarshal(nodeData, node); err != nil {
-		g.logger.Errorf("InterceptSecured, unmarshal node error: %v, nodeData: %s, pid: %s, account: %s", err, string(nodeData), p.String(), nodeAccountStr)
+	node, err := g.getNode(p.String())
+	if err != nil {
+		g.logger.Infof("Intercept a connection with an unavailable node(%s), peer.Pid: %s", err.Error(), p.String())
 		return false
 	}
 
@@ -68,6 +77,41 @@ func (g *connectionGater) InterceptSecured(d network.Direction, p peer.ID, addr
 	return false
 }
 
+// getNode gets the vp node or the follower nvp node with the pid from node manager
+func (g *connectionGater) getNode(pid string) (*node_mgr.Node, error) {
+	lg := g.ledger.Copy()
+	ok, nodeAccount := lg.GetState(constant.NodeManagerContractAddr.Address(), []byte(node_mgr.VpNodePidKey(pid)))
+	isVp := ok
+	if !ok {
+		// followers are nvp nodes registered with pid, whose pid is only occupied
+		ok, nodeAccount = lg.GetState(constant.NodeManagerContractAddr.Address(), []byte(node_mgr.NodeOccupyPidKey(pid)))
+		if !ok {
+			return nil, fmt.Errorf("get node err: %s", string(nodeAccount))
+		}
+	}
+	nodeAccountStr := strings.Trim(string(nodeAccount), "\"")
+	ok, nodeData := lg.GetState(constant.NodeManagerContractAddr.Address(), []byte(node_mgr.NodeKey(nodeAccountStr)))
+	if !ok {
+		return nil, fmt.Errorf("node pid %s exist but node %s not exist: %s", pid, nodeAccountStr, string(nodeData))
+	}
+
+	node := &node_mgr.Node{}
+	if err := json.Unmarshal(nodeData, node); err != nil {
+		return nil, fmt.Errorf("unmarshal node %s error: %w", nodeAccountStr, err)
+	}
+	if !isVp && (node.NodeType != node_mgr.NVPNode || node.Pid != pid) {
+		return nil, fmt.Errorf("node %s with pid %s is not a follower", nodeAccountStr, pid)
+	}
+
+	return node, nil
+}
+
+// isFollower checks whether the pid belongs to a follower nvp node
+func (g *connectionGater) isFollower(pid string) bool {
+	node, err := g.getNode(pid)
+	return err == nil && node.NodeType == node_mgr.NVPNode
+}
+
 func (g *connectionGater) InterceptUpgraded(conn network.Conn) (allow bool, reason control.DisconnectReason) {
 	return true, 0
 }
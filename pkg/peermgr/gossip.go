@@ -0,0 +1,274 @@
+package peermgr
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	ddht "github.com/libp2p/go-libp2p-kad-dht/dual"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/meshplus/bitxhub-model/pb"
+	network "github.com/meshplus/go-lightp2p"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// BlockTopic is the gossip topic of the headers of committed blocks
+	BlockTopic = "/bitxhub/blocks/1.0.0"
+)
+
+// ValidationResult is the result of a topic validator
+type ValidationResult int
+
+const (
+	// ValidationAccept delivers the message to subscribers and forwards it
+	ValidationAccept ValidationResult = iota
+	// ValidationReject drops the message and penalizes the peer it comes from
+	ValidationReject
+	// ValidationIgnore drops the message silently
+	ValidationIgnore
+)
+
+// TopicValidator validates the data of a gossip message published by the peer from
+type TopicValidator func(from string, data []byte) ValidationResult
+
+// GossipMessageEvent is a gossip message delivered to the subscribers of its topic
+type GossipMessageEvent struct {
+	Topic string
+	// From is the pid of the peer who published the message
+	From string
+	Data []byte
+}
+
+// gossipMessageID identifies the message by its content, so the same data published by
+// several peers is delivered only once
+func gossipMessageID(msg *pubsubpb.Message) string {
+	h := sha256.New()
+	for _, topic := range msg.GetTopicIDs() {
+		h.Write([]byte(topic))
+	}
+	h.Write(msg.GetData())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// gossip spreads messages of topics by gossipsub on the host of the swarm, so the messages
+// are exchanged on the gossipsub protocols apart from the direct messages. Every node,
+// including followers and audit nodes, subscribes the topics it joins and relays their messages.
+// The validators and the feeds of topics outlive the pubsub, which is recreated once the
+// swarm restarts.
+type gossip struct {
+	validators sync.Map // topic -> TopicValidator
+	feeds      sync.Map // topic -> *event.Feed
+
+	ps     *pubsub.PubSub
+	self   peer.ID
+	ctx    context.Context
+	topics map[string]*pubsub.Topic
+	lock   sync.Mutex
+}
+
+func newGossip() *gossip {
+	return &gossip{
+		topics: make(map[string]*pubsub.Topic),
+	}
+}
+
+func (g *gossip) feed(topic string) *event.Feed {
+	feed, _ := g.feeds.LoadOrStore(topic, &event.Feed{})
+	return feed.(*event.Feed)
+}
+
+// p2pHost returns the libp2p host of the network, which lightp2p only exposes through its DHT
+func p2pHost(p2p network.Network) (host.Host, error) {
+	light, ok := p2p.(*network.P2P)
+	if !ok {
+		return nil, fmt.Errorf("unsupported network %T", p2p)
+	}
+	dht, ok := light.Routing.(*ddht.DHT)
+	if !ok {
+		return nil, fmt.Errorf("unsupported routing %T", light.Routing)
+	}
+	return dht.WAN.Host(), nil
+}
+
+// start creates the gossipsub on the host and joins the topics which have been subscribed
+// or validated, the pubsub is shut down once the context is done
+func (g *gossip) start(ctx context.Context, h host.Host, validate pubsub.ValidatorEx) error {
+	ps, err := pubsub.NewGossipSub(ctx, h,
+		pubsub.WithMessageSigning(true),
+		pubsub.WithStrictSignatureVerification(true),
+		pubsub.WithMessageIdFn(gossipMessageID),
+		// messages are published to all peers of the topic rather than the mesh,
+		// so they are spread before the mesh is formed
+		pubsub.WithFloodPublish(true),
+	)
+	if err != nil {
+		return fmt.Errorf("create gossipsub: %w", err)
+	}
+
+	g.lock.Lock()
+	g.ps = ps
+	g.self = h.ID()
+	g.ctx = ctx
+	g.topics = make(map[string]*pubsub.Topic)
+	g.lock.Unlock()
+
+	var topics []string
+	collect := func(key, _ interface{}) bool {
+		topics = append(topics, key.(string))
+		return true
+	}
+	g.feeds.Range(collect)
+	g.validators.Range(collect)
+	for _, topic := range topics {
+		if _, err := g.join(topic, validate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// join joins and subscribes the topic once, the messages of the topic are sent to its feed
+func (g *gossip) join(topic string, validate pubsub.ValidatorEx) (*pubsub.Topic, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if g.ps == nil {
+		return nil, fmt.Errorf("gossip is not started")
+	}
+	if t, ok := g.topics[topic]; ok {
+		return t, nil
+	}
+
+	if err := g.ps.RegisterTopicValidator(topic, validate); err != nil {
+		return nil, fmt.Errorf("register validator of topic %s: %w", topic, err)
+	}
+	t, err := g.ps.Join(topic)
+	if err != nil {
+		return nil, fmt.Errorf("join topic %s: %w", topic, err)
+	}
+	sub, err := t.Subscribe()
+	if err != nil {
+		return nil, fmt.Errorf("subscribe topic %s: %w", topic, err)
+	}
+	g.topics[topic] = t
+
+	go g.deliver(g.ctx, topic, sub, g.self)
+	return t, nil
+}
+
+// deliver sends the messages of other peers to the feed of the topic
+func (g *gossip) deliver(ctx context.Context, topic string, sub *pubsub.Subscription, self peer.ID) {
+	defer sub.Cancel()
+	for {
+		msg, err := sub.Next(ctx)
+		if err != nil {
+			return
+		}
+		if msg.ReceivedFrom == self {
+			continue
+		}
+
+		gossipMessageCounter.WithLabelValues(topic, "delivered").Inc()
+		g.feed(topic).Send(GossipMessageEvent{
+			Topic: topic,
+			From:  msg.GetFrom().String(),
+			Data:  msg.GetData(),
+		})
+	}
+}
+
+func (g *gossip) validate(topic string, from string, data []byte) ValidationResult {
+	validator, ok := g.validators.Load(topic)
+	if !ok {
+		return ValidationAccept
+	}
+	return validator.(TopicValidator)(from, data)
+}
+
+// Publish signs the data and gossips it in the topic
+func (swarm *Swarm) Publish(topic string, data []byte) error {
+	t, err := swarm.gossip.join(topic, swarm.validateGossipMessage)
+	if err != nil {
+		return err
+	}
+	if err := t.Publish(swarm.ctx, data); err != nil {
+		return fmt.Errorf("publish gossip message: %w", err)
+	}
+
+	gossipMessageCounter.WithLabelValues(topic, "published").Inc()
+	return nil
+}
+
+// SubscribeTopic subscribes the gossip messages of the topic which are accepted by its validator
+func (swarm *Swarm) SubscribeTopic(topic string, ch chan<- GossipMessageEvent) event.Subscription {
+	sub := swarm.gossip.feed(topic).Subscribe(ch)
+	if _, err := swarm.gossip.join(topic, swarm.validateGossipMessage); err != nil {
+		swarm.logger.WithFields(logrus.Fields{
+			"topic": topic,
+			"error": err,
+		}).Debug("Join gossip topic failed")
+	}
+	return sub
+}
+
+// RegisterTopicValidator sets the validator of the topic, messages of a topic without
+// validator are always accepted
+func (swarm *Swarm) RegisterTopicValidator(topic string, validator TopicValidator) {
+	swarm.gossip.validators.Store(topic, validator)
+}
+
+// startGossip starts gossipsub on the host of the swarm
+func (swarm *Swarm) startGossip() error {
+	h, err := p2pHost(swarm.p2p)
+	if err != nil {
+		return err
+	}
+	return swarm.gossip.start(swarm.ctx, h, swarm.validateGossipMessage)
+}
+
+// validateGossipMessage applies the validator of the topic to the messages of other peers,
+// whose signatures have been verified by gossipsub
+func (swarm *Swarm) validateGossipMessage(_ context.Context, pid peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+	if pid == swarm.gossip.self {
+		return pubsub.ValidationAccept
+	}
+
+	topic := msg.GetTopicIDs()[0]
+	switch swarm.gossip.validate(topic, msg.GetFrom().String(), msg.GetData()) {
+	case ValidationReject:
+		swarm.logger.WithFields(logrus.Fields{
+			"topic":     topic,
+			"publisher": msg.GetFrom().String(),
+			"from":      pid.String(),
+		}).Warn("Gossip message is rejected")
+		gossipMessageCounter.WithLabelValues(topic, "rejected").Inc()
+		swarm.reportPid(pid.String(), InvalidResponse)
+		return pubsub.ValidationReject
+	case ValidationIgnore:
+		gossipMessageCounter.WithLabelValues(topic, "ignored").Inc()
+		return pubsub.ValidationIgnore
+	default:
+		return pubsub.ValidationAccept
+	}
+}
+
+// validateBlockAnnouncement accepts the headers of committed blocks announced by vp nodes
+func (swarm *Swarm) validateBlockAnnouncement(from string, data []byte) ValidationResult {
+	header := &pb.BlockHeader{}
+	if err := header.Unmarshal(data); err != nil || header.Number == 0 {
+		return ValidationReject
+	}
+	for _, info := range swarm.notifiee.getPeers() {
+		if info.Pid == from {
+			return ValidationAccept
+		}
+	}
+	return ValidationReject
+}
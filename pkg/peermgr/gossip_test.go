@@ -0,0 +1,142 @@
+package peermgr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwarm_Gossip(t *testing.T) {
+	peerCnt := 4
+	swarms := NewSwarms(t, peerCnt)
+	defer stopSwarms(t, swarms)
+
+	for _, swarm := range swarms {
+		for swarm.CountConnectedPeers() != uint64(peerCnt-1) {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	require.Eventually(t, func() bool {
+		for _, swarm := range swarms {
+			if len(swarm.notifiee.connectedPids()) != peerCnt-1 {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// the announcements are published once the peers of the topic are known
+	require.Eventually(t, func() bool {
+		for _, swarm := range swarms {
+			if len(topicPeers(swarm, BlockTopic)) != peerCnt-1 {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 100*time.Millisecond)
+
+	chs := make([]chan GossipMessageEvent, peerCnt)
+	for i, swarm := range swarms {
+		chs[i] = make(chan GossipMessageEvent, 10)
+		sub := swarm.SubscribeTopic(BlockTopic, chs[i])
+		defer sub.Unsubscribe()
+	}
+
+	header := &pb.BlockHeader{Number: 2}
+	data, err := header.Marshal()
+	require.Nil(t, err)
+	require.Nil(t, swarms[0].Publish(BlockTopic, data))
+
+	for i := 1; i < peerCnt; i++ {
+		select {
+		case ev := <-chs[i]:
+			require.Equal(t, BlockTopic, ev.Topic)
+			require.Equal(t, swarms[0].p2p.PeerID(), ev.From)
+			require.Equal(t, data, ev.Data)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "block announcement is not delivered")
+		}
+	}
+
+	// the same announcement from another vp node is a duplicate
+	require.Nil(t, swarms[1].Publish(BlockTopic, data))
+	// malformed announcements are rejected by the validator
+	require.Nil(t, swarms[2].Publish(BlockTopic, []byte("malformed")))
+	for i := 0; i < peerCnt; i++ {
+		select {
+		case ev := <-chs[i]:
+			require.Fail(t, "unexpected announcement", "node %d receives %v", i, ev.Data)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	// validators of custom topics are applied
+	topic := "test"
+	testCh := make(chan GossipMessageEvent, 10)
+	sub := swarms[3].SubscribeTopic(topic, testCh)
+	defer sub.Unsubscribe()
+	swarms[3].RegisterTopicValidator(topic, func(from string, data []byte) ValidationResult {
+		if string(data) == "ignore" {
+			return ValidationIgnore
+		}
+		return ValidationAccept
+	})
+	// the publisher joins the topic by subscribing it
+	joinSub := swarms[1].SubscribeTopic(topic, make(chan GossipMessageEvent, 10))
+	defer joinSub.Unsubscribe()
+	require.Eventually(t, func() bool {
+		for _, pid := range topicPeers(swarms[1], topic) {
+			if pid.String() == swarms[3].p2p.PeerID() {
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 100*time.Millisecond)
+	require.Nil(t, swarms[1].Publish(topic, []byte("ignore")))
+	require.Nil(t, swarms[1].Publish(topic, []byte("accept")))
+	select {
+	case ev := <-testCh:
+		require.Equal(t, "accept", string(ev.Data))
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "test message is not delivered")
+	}
+}
+
+func TestGossipMessageID(t *testing.T) {
+	msg := &pubsubpb.Message{
+		From:     []byte("from"),
+		Data:     []byte("data"),
+		Seqno:    []byte{1},
+		TopicIDs: []string{BlockTopic},
+	}
+	id := gossipMessageID(msg)
+
+	// the same data published by another peer is the same message
+	require.Equal(t, id, gossipMessageID(&pubsubpb.Message{
+		From:     []byte("another"),
+		Data:     []byte("data"),
+		Seqno:    []byte{2},
+		TopicIDs: []string{BlockTopic},
+	}))
+	require.NotEqual(t, id, gossipMessageID(&pubsubpb.Message{
+		From:     []byte("from"),
+		Data:     []byte("data"),
+		Seqno:    []byte{1},
+		TopicIDs: []string{"test"},
+	}))
+}
+
+func topicPeers(swarm *Swarm, topic string) []peer.ID {
+	swarm.gossip.lock.Lock()
+	defer swarm.gossip.lock.Unlock()
+
+	t, ok := swarm.gossip.topics[topic]
+	if !ok {
+		return nil
+	}
+	return t.ListPeers()
+}
@@ -17,6 +17,7 @@ func (swarm *Swarm) handleMessage(s network.Stream, data []byte) {
 	m := &pb.Message{}
 	if err := m.Unmarshal(data); err != nil {
 		swarm.logger.Errorf("unmarshal message error: %s", err.Error())
+		swarm.reportPid(s.RemotePeerID(), ProtocolViolation)
 		return
 	}
 
@@ -66,6 +67,7 @@ func (swarm *Swarm) handleMessage(s network.Stream, data []byte) {
 			go swarm.handleNotTssParties(s, m.Data)
 		default:
 			swarm.logger.WithField("module", "p2p").Errorf("can't handle msg[type: %v]", m.Type)
+			swarm.reportPid(s.RemotePeerID(), ProtocolViolation)
 			return nil
 		}
 
@@ -118,7 +120,12 @@ func (swarm *Swarm) handleGetBlockHeadersPack(s network.Stream, msg *pb.Message)
 
 	res := &pb.GetBlockHeadersResponse{}
 	blockHeaders := make([]*pb.BlockHeader, 0)
-	for i := req.Start; i <= req.End; i++ {
+	// the range is cut at the latest height, so that followers are able to pull the blocks ahead of them
+	end := req.End
+	if height := swarm.ledger.GetChainMeta().Height; end > height {
+		end = height
+	}
+	for i := req.Start; i <= end; i++ {
 		block, err := swarm.ledger.GetBlock(i, false)
 		if err != nil {
 			return fmt.Errorf("get block with height %d from ledger failed: %w", i, err)
@@ -391,7 +398,12 @@ func (swarm *Swarm) handleGetBlocksPack(s network.Stream, msg *pb.Message) error
 
 	res := &pb.GetBlocksResponse{}
 	blocks := make([]*pb.Block, 0)
-	for i := req.Start; i <= req.End; i++ {
+	// cut the range at the latest height as handleGetBlockHeadersPack does
+	end := req.End
+	if height := swarm.ledger.GetChainMeta().Height; end > height {
+		end = height
+	}
+	for i := req.Start; i <= end; i++ {
 		block, err := swarm.ledger.GetBlock(i, true)
 		if err != nil {
 			return fmt.Errorf("get block with height %d from ledger failed: %w", i, err)
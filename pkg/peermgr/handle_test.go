@@ -28,6 +28,7 @@ import (
 	"github.com/meshplus/bitxhub/internal/ledger"
 	"github.com/meshplus/bitxhub/internal/ledger/mock_ledger"
 	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/meshplus/bitxhub/pkg/signer"
 	libp2pcert "github.com/meshplus/go-libp2p-cert"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -113,6 +114,7 @@ func NewSwarms(t *testing.T, peerCnt int) []*Swarm {
 			Number: 1,
 		},
 	}, nil).AnyTimes()
+	chainLedger.EXPECT().GetChainMeta().Return(&pb.ChainMeta{Height: 1}).AnyTimes()
 
 	ibtp := &pb.IBTP{}
 	tx := &pb.BxhTransaction{IBTP: ibtp}
@@ -192,7 +194,7 @@ func NewSwarms(t *testing.T, peerCnt int) []*Swarm {
 		local := addrs[i][:idx]
 		repo.NetworkConfig.LocalAddr = local
 		repo.Key.Libp2pPrivKey = nodeKeys[i]
-		repo.Key.PrivKey = privKeys[i]
+		repo.Key.PrivKey = signer.NewLocalSigner(privKeys[i])
 		repo.NetworkConfig.Nodes = peers(uint64(i), addrs, pids, accounts)
 
 		swarm, err := New(repo, log.NewWithModule(fmt.Sprintf("swarm%d", i)), mockLedger)
@@ -341,7 +343,7 @@ func TestSwarm_Gater(t *testing.T) {
 	for swarms[0].CountConnectedPeers() != 3 {
 		time.Sleep(100 * time.Millisecond)
 	}
-	gater := newConnectionGater(swarms[0].logger, swarms[0].ledger)
+	gater := newConnectionGater(swarms[0].logger, swarms[0].ledger, swarms[0].scorer)
 	require.True(t, gater.InterceptPeerDial(peer.ID("1")))
 	require.True(t, gater.InterceptAddrDial("1", swarms[1].multiAddrs[1].Addrs[0]))
 	require.True(t, gater.InterceptAccept(new(swarm.Conn)))
@@ -448,7 +450,7 @@ func TestSwarm_Send(t *testing.T) {
 
 	getBlockHeadersReq := pb.GetBlockHeadersRequest{
 		Start: 1,
-		End:   1,
+		End:   3,
 	}
 	data, err = getBlockHeadersReq.Marshal()
 	require.Nil(t, err)
@@ -0,0 +1,44 @@
+package peermgr
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	peerScoreGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "bitxhub",
+		Subsystem: "p2p",
+		Name:      "peer_score",
+		Help:      "The health score of vp peers",
+	}, []string{"peer"})
+	peerRTTGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "bitxhub",
+		Subsystem: "p2p",
+		Name:      "peer_rtt_seconds",
+		Help:      "The ping rtt of vp peers",
+	}, []string{"peer"})
+	peerBannedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "bitxhub",
+		Subsystem: "p2p",
+		Name:      "peer_banned",
+		Help:      "Whether the vp peer is banned",
+	}, []string{"peer"})
+	peerMisbehaviourCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "bitxhub",
+		Subsystem: "p2p",
+		Name:      "peer_misbehaviours_total",
+		Help:      "The total number of misbehaviours of vp peers",
+	}, []string{"peer", "type"})
+	gossipMessageCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "bitxhub",
+		Subsystem: "p2p",
+		Name:      "gossip_messages_total",
+		Help:      "The total number of gossip messages by topic and result",
+	}, []string{"topic", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(peerScoreGauge)
+	prometheus.MustRegister(peerRTTGauge)
+	prometheus.MustRegister(peerBannedGauge)
+	prometheus.MustRegister(peerMisbehaviourCounter)
+	prometheus.MustRegister(gossipMessageCounter)
+}
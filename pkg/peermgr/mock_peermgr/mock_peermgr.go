@@ -159,6 +159,20 @@ func (mr *MockPeerManagerMockRecorder) OtherPeers() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OtherPeers", reflect.TypeOf((*MockPeerManager)(nil).OtherPeers))
 }
 
+// PeerScores mocks base method.
+func (m *MockPeerManager) PeerScores() map[uint64]*peermgr.PeerScore {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PeerScores")
+	ret0, _ := ret[0].(map[uint64]*peermgr.PeerScore)
+	return ret0
+}
+
+// PeerScores indicates an expected call of PeerScores.
+func (mr *MockPeerManagerMockRecorder) PeerScores() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PeerScores", reflect.TypeOf((*MockPeerManager)(nil).PeerScores))
+}
+
 // Peers mocks base method.
 func (m *MockPeerManager) Peers() map[string]*peer.AddrInfo {
 	m.ctrl.T.Helper()
@@ -173,6 +187,32 @@ func (mr *MockPeerManagerMockRecorder) Peers() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Peers", reflect.TypeOf((*MockPeerManager)(nil).Peers))
 }
 
+// Publish mocks base method.
+func (m *MockPeerManager) Publish(topic string, data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", topic, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockPeerManagerMockRecorder) Publish(topic, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockPeerManager)(nil).Publish), topic, data)
+}
+
+// RegisterTopicValidator mocks base method.
+func (m *MockPeerManager) RegisterTopicValidator(topic string, validator peermgr.TopicValidator) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterTopicValidator", topic, validator)
+}
+
+// RegisterTopicValidator indicates an expected call of RegisterTopicValidator.
+func (mr *MockPeerManagerMockRecorder) RegisterTopicValidator(topic, validator interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterTopicValidator", reflect.TypeOf((*MockPeerManager)(nil).RegisterTopicValidator), topic, validator)
+}
+
 // PierManager mocks base method.
 func (m *MockPeerManager) PierManager() peermgr.PierManager {
 	m.ctrl.T.Helper()
@@ -201,6 +241,18 @@ func (mr *MockPeerManagerMockRecorder) ReConfig(config interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReConfig", reflect.TypeOf((*MockPeerManager)(nil).ReConfig), config)
 }
 
+// ReportPeer mocks base method.
+func (m *MockPeerManager) ReportPeer(id uint64, misbehaviour peermgr.Misbehaviour) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReportPeer", id, misbehaviour)
+}
+
+// ReportPeer indicates an expected call of ReportPeer.
+func (mr *MockPeerManagerMockRecorder) ReportPeer(id, misbehaviour interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportPeer", reflect.TypeOf((*MockPeerManager)(nil).ReportPeer), id, misbehaviour)
+}
+
 // Send mocks base method.
 func (m *MockPeerManager) Send(arg0 peer_mgr.KeyType, arg1 *pb.Message) (*pb.Message, error) {
 	m.ctrl.T.Helper()
@@ -272,6 +324,20 @@ func (mr *MockPeerManagerMockRecorder) SubscribeOrderMessage(ch interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeOrderMessage", reflect.TypeOf((*MockPeerManager)(nil).SubscribeOrderMessage), ch)
 }
 
+// SubscribeTopic mocks base method.
+func (m *MockPeerManager) SubscribeTopic(topic string, ch chan<- peermgr.GossipMessageEvent) event.Subscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeTopic", topic, ch)
+	ret0, _ := ret[0].(event.Subscription)
+	return ret0
+}
+
+// SubscribeTopic indicates an expected call of SubscribeTopic.
+func (mr *MockPeerManagerMockRecorder) SubscribeTopic(topic, ch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeTopic", reflect.TypeOf((*MockPeerManager)(nil).SubscribeTopic), topic, ch)
+}
+
 // SubscribeTssCulprits mocks base method.
 func (m *MockPeerManager) SubscribeTssCulprits(ch chan<- *pb.Message) event.Subscription {
 	m.ctrl.T.Helper()
@@ -342,6 +408,21 @@ func (mr *MockPeerManagerMockRecorder) UpdateRouter(vpInfos, isNew interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRouter", reflect.TypeOf((*MockPeerManager)(nil).UpdateRouter), vpInfos, isNew)
 }
 
+// ValidatorSet mocks base method.
+func (m *MockPeerManager) ValidatorSet() (map[uint64]*pb.VpInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidatorSet")
+	ret0, _ := ret[0].(map[uint64]*pb.VpInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidatorSet indicates an expected call of ValidatorSet.
+func (mr *MockPeerManagerMockRecorder) ValidatorSet() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidatorSet", reflect.TypeOf((*MockPeerManager)(nil).ValidatorSet))
+}
+
 // MockPierManager is a mock of PierManager interface.
 type MockPierManager struct {
 	ctrl     *gomock.Controller
@@ -15,6 +15,13 @@ type notifiee struct {
 	newPeer string
 	mu      sync.RWMutex
 	logger  logrus.FieldLogger
+
+	// network is the libp2p network which notifies the connections
+	network   network.Network
+	networkMu sync.RWMutex
+
+	// isFollower checks whether a connected peer is a follower, which is never taken as a new vp peer
+	isFollower func(pid string) bool
 }
 
 func newNotifiee(peers map[uint64]*pb.VpInfo, logger logrus.FieldLogger) *notifiee {
@@ -31,6 +38,10 @@ func (n *notifiee) ListenClose(network network.Network, multiaddr ma.Multiaddr)
 }
 
 func (n *notifiee) Connected(network network.Network, conn network.Conn) {
+	n.networkMu.Lock()
+	n.network = network
+	n.networkMu.Unlock()
+
 	peers := n.getPeers()
 	newAddr := conn.RemotePeer().String()
 	// check if the newAddr has already in peers.
@@ -39,6 +50,10 @@ func (n *notifiee) Connected(network network.Network, conn network.Conn) {
 			return
 		}
 	}
+	if n.isFollower != nil && n.isFollower(newAddr) {
+		n.logger.Infof("Connected with follower %s", newAddr)
+		return
+	}
 	if n.newPeer == "" {
 		n.newPeer = newAddr
 		n.logger.Infof("Updating notifiee newPeer %s", newAddr)
@@ -67,3 +82,19 @@ func (n *notifiee) setPeers(peers map[uint64]*pb.VpInfo) {
 	defer n.mu.Unlock()
 	n.peers = peers
 }
+
+// connectedPids returns the pids of remote peers with open connections
+func (n *notifiee) connectedPids() []string {
+	n.networkMu.RLock()
+	defer n.networkMu.RUnlock()
+	if n.network == nil {
+		return nil
+	}
+
+	peers := n.network.Peers()
+	pids := make([]string, 0, len(peers))
+	for _, p := range peers {
+		pids = append(pids, p.String())
+	}
+	return pids
+}
@@ -1,6 +1,7 @@
 package peermgr
 
 import (
+	"github.com/ethereum/go-ethereum/event"
 	orderPeerMgr "github.com/meshplus/bitxhub-core/peer-mgr"
 	"github.com/meshplus/bitxhub-model/pb"
 	network "github.com/meshplus/go-lightp2p"
@@ -20,6 +21,24 @@ type PeerManager interface {
 
 	// ReConfig
 	ReConfig(config interface{}) error
+
+	// ReportPeer lowers the score of the vp peer for its misbehaviour
+	ReportPeer(id uint64, misbehaviour Misbehaviour)
+
+	// ValidatorSet returns the available vp nodes recorded by the node manager contract
+	ValidatorSet() (map[uint64]*pb.VpInfo, error)
+
+	// PeerScores returns the scores of vp peers
+	PeerScores() map[uint64]*PeerScore
+
+	// Publish gossips the data in the topic to vp, audit and follower nodes
+	Publish(topic string, data []byte) error
+
+	// SubscribeTopic subscribes the gossip messages of the topic
+	SubscribeTopic(topic string, ch chan<- GossipMessageEvent) event.Subscription
+
+	// RegisterTopicValidator sets the validator of gossip messages of the topic
+	RegisterTopicValidator(topic string, validator TopicValidator)
 }
 
 type PierManager interface {
@@ -0,0 +1,79 @@
+package peermgr
+
+import (
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/meshplus/bitxhub/internal/model/events"
+	"github.com/sirupsen/logrus"
+)
+
+// NodeEventSource posts the node governance outcomes and the persisted blocks
+type NodeEventSource interface {
+	SubscribeNodeEvent(chan<- events.NodeEvent) event.Subscription
+
+	SubscribeBlockEvent(chan<- events.ExecutedEvent) event.Subscription
+}
+
+// WatchNodeEvent makes the swarm revalidate its connections every time the governance of vp nodes
+// or followers changes their availability, it must be called before Start
+func (swarm *Swarm) WatchNodeEvent(source NodeEventSource) {
+	swarm.nodeEventSource = source
+}
+
+func (swarm *Swarm) listenNodeEvent() {
+	nodeCh := make(chan events.NodeEvent)
+	blockCh := make(chan events.ExecutedEvent)
+	nodeSub := swarm.nodeEventSource.SubscribeNodeEvent(nodeCh)
+	blockSub := swarm.nodeEventSource.SubscribeBlockEvent(blockCh)
+	defer nodeSub.Unsubscribe()
+	defer blockSub.Unsubscribe()
+
+	// node events are posted while the block is executed, so the connections are
+	// revalidated after the block is persisted and the node status is visible in ledger
+	pending := false
+	for {
+		select {
+		case <-nodeCh:
+			pending = true
+		case <-blockCh:
+			if pending {
+				pending = false
+				swarm.revalidatePeers()
+			}
+		case <-swarm.ctx.Done():
+			return
+		}
+	}
+}
+
+// revalidatePeers closes the connections and streams to the peers which are not available vp nodes or followers any more
+func (swarm *Swarm) revalidatePeers() {
+	for _, pid := range swarm.notifiee.connectedPids() {
+		node, err := swarm.gater.getNode(pid)
+		if err == nil && node.IsAvailable() {
+			continue
+		}
+
+		fields := logrus.Fields{"pid": pid}
+		if err != nil {
+			fields["error"] = err
+		} else {
+			fields["node"] = node.VPNodeId
+			fields["status"] = node.Status
+		}
+		swarm.logger.WithFields(fields).Info("Close connections to an unavailable node")
+		swarm.disconnectPid(pid)
+	}
+}
+
+// disconnectPid closes the connections and streams to the peer
+func (swarm *Swarm) disconnectPid(pid string) {
+	for id, info := range swarm.notifiee.getPeers() {
+		if info.Pid == pid {
+			swarm.connectedPeers.Delete(id)
+			break
+		}
+	}
+	if err := swarm.p2p.Disconnect(pid); err != nil {
+		swarm.logger.WithFields(logrus.Fields{"pid": pid, "error": err}).Error("Disconnect peer failed")
+	}
+}
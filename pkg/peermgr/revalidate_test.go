@@ -0,0 +1,65 @@
+package peermgr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/golang/mock/gomock"
+	"github.com/meshplus/bitxhub-core/governance"
+	"github.com/meshplus/bitxhub/internal/ledger"
+	"github.com/meshplus/bitxhub/internal/ledger/mock_ledger"
+	"github.com/meshplus/bitxhub/internal/model/events"
+	"github.com/stretchr/testify/require"
+)
+
+type mockNodeEventSource struct {
+	nodeFeed  event.Feed
+	blockFeed event.Feed
+}
+
+func (m *mockNodeEventSource) SubscribeNodeEvent(ch chan<- events.NodeEvent) event.Subscription {
+	return m.nodeFeed.Subscribe(ch)
+}
+
+func (m *mockNodeEventSource) SubscribeBlockEvent(ch chan<- events.ExecutedEvent) event.Subscription {
+	return m.blockFeed.Subscribe(ch)
+}
+
+func TestSwarm_RevalidatePeers(t *testing.T) {
+	peerCnt := 4
+	swarms := NewSwarms(t, peerCnt)
+	defer stopSwarms(t, swarms)
+
+	for swarms[0].CountConnectedPeers() != 3 {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	source := &mockNodeEventSource{}
+	swarms[0].WatchNodeEvent(source)
+	go swarms[0].listenNodeEvent()
+	for source.nodeFeed.Send(events.NodeEvent{NodeEventType: governance.EventRegister}) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// all nodes are still available
+	source.blockFeed.Send(events.ExecutedEvent{})
+	require.Equal(t, 3, len(swarms[0].notifiee.connectedPids()))
+
+	// all nodes are logouted
+	mockCtl := gomock.NewController(t)
+	stateLedger := mock_ledger.NewMockStateLedger(mockCtl)
+	stateLedger.EXPECT().Copy().Return(stateLedger).AnyTimes()
+	stateLedger.EXPECT().GetState(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	swarms[0].gater.ledger = &ledger.Ledger{StateLedger: stateLedger}
+
+	// connections are revalidated only after the block is persisted
+	source.nodeFeed.Send(events.NodeEvent{NodeId: 2, NodeEventType: governance.EventLogout})
+	require.Equal(t, 3, len(swarms[0].notifiee.connectedPids()))
+
+	source.blockFeed.Send(events.ExecutedEvent{})
+	require.Eventually(t, func() bool {
+		return len(swarms[0].notifiee.connectedPids()) == 0
+	}, 5*time.Second, 50*time.Millisecond)
+	require.Equal(t, uint64(0), swarms[0].CountConnectedPeers())
+}
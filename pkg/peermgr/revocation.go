@@ -0,0 +1,190 @@
+package peermgr
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
+	"github.com/meshplus/bitxhub/internal/ledger"
+	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/sirupsen/logrus"
+)
+
+// revocationCheckInterval is the interval to check the certs of connected peers again,
+// so that the certs revoked later are found out
+const revocationCheckInterval = time.Minute
+
+// errCertRevoked marks the certs listed by the CRLs or the revocation list of governance,
+// other errors of checking certs may be transient and are retried later
+var errCertRevoked = errors.New("cert is revoked")
+
+// certRevocation checks the certs of peers against the CRLs in the file and the revocation list
+// managed by governance. The certs are checked by the cert transport on every handshake, and the pids
+// of peers whose last presented certs are revoked are refused by the gater until they present valid ones.
+type certRevocation struct {
+	logger  logrus.FieldLogger
+	ledger  *ledger.Ledger
+	caCert  *x509.Certificate
+	crlPath string
+
+	crls       []*pkix.CertificateList
+	crlModTime time.Time
+	crlMu      sync.Mutex
+
+	revoked sync.Map // pid -> reason the last presented certs are revoked
+}
+
+func newCertRevocation(logger logrus.FieldLogger, ledger *ledger.Ledger, repoRoot string, config repo.Cert, caCert *x509.Certificate) *certRevocation {
+	crlPath := config.CRLPath
+	if crlPath != "" && !filepath.IsAbs(crlPath) {
+		crlPath = filepath.Join(repoRoot, crlPath)
+	}
+	return &certRevocation{
+		logger:  logger,
+		ledger:  ledger,
+		caCert:  caCert,
+		crlPath: crlPath,
+	}
+}
+
+func (r *certRevocation) isRevoked(pid string) bool {
+	_, ok := r.revoked.Load(pid)
+	return ok
+}
+
+func (r *certRevocation) revoke(pid string, reason string) {
+	r.revoked.Store(pid, reason)
+}
+
+// loadCRLs reloads the CRL file once it is modified
+func (r *certRevocation) loadCRLs() ([]*pkix.CertificateList, error) {
+	if r.crlPath == "" {
+		return nil, nil
+	}
+
+	r.crlMu.Lock()
+	defer r.crlMu.Unlock()
+
+	info, err := os.Stat(r.crlPath)
+	if err != nil {
+		return nil, fmt.Errorf("stat crl file: %w", err)
+	}
+	if info.ModTime().Equal(r.crlModTime) {
+		return r.crls, nil
+	}
+	crls, err := repo.LoadCRLs(r.crlPath)
+	if err != nil {
+		return nil, err
+	}
+	r.crls = crls
+	r.crlModTime = info.ModTime()
+	return crls, nil
+}
+
+// checkPeer checks the certs the peer presents and remembers the peer if they are revoked.
+// The certs are always checked, so a revoked peer is accepted again once it presents reissued certs.
+func (r *certRevocation) checkPeer(pid string, pubKey crypto.PubKey, nodeCert, agencyCert *x509.Certificate) error {
+	err := r.checkCerts(pubKey, nodeCert, agencyCert)
+	switch {
+	case errors.Is(err, errCertRevoked):
+		r.revoke(pid, err.Error())
+	case err == nil:
+		r.revoked.Delete(pid)
+	}
+	return err
+}
+
+// checkCerts checks that the node cert is issued by the agency for the public key of the peer,
+// and neither the node cert nor the agency cert is revoked, only the errors of revoked certs wrap errCertRevoked
+func (r *certRevocation) checkCerts(pubKey crypto.PubKey, nodeCert, agencyCert *x509.Certificate) error {
+	if err := repo.VerifyCertSign(agencyCert, r.caCert); err != nil {
+		return fmt.Errorf("verify agency cert: %w", err)
+	}
+	if err := repo.VerifyCertSign(nodeCert, agencyCert); err != nil {
+		return fmt.Errorf("verify node cert: %w", err)
+	}
+	// binds the certs to the peer, otherwise a revoked peer could present certs of others
+	if err := repo.CheckCertKey(nodeCert, pubKey); err != nil {
+		return fmt.Errorf("check node cert: %w", err)
+	}
+
+	crls, err := r.loadCRLs()
+	if err != nil {
+		return fmt.Errorf("load crls: %w", err)
+	}
+	for _, crl := range crls {
+		// CRLs of the ca revoke agency certs, and CRLs of an agency revoke its node certs
+		switch {
+		case repo.CheckCRLSignature(r.caCert, crl) == nil:
+			if isSerialRevoked(crl, agencyCert) {
+				return fmt.Errorf("agency cert %s is revoked by ca: %w", agencyCert.SerialNumber, errCertRevoked)
+			}
+		case repo.CheckCRLSignature(agencyCert, crl) == nil:
+			if isSerialRevoked(crl, nodeCert) {
+				return fmt.Errorf("node cert %s is revoked by agency: %w", nodeCert.SerialNumber, errCertRevoked)
+			}
+		}
+	}
+
+	lg := r.ledger.Copy()
+	for _, cert := range []*x509.Certificate{nodeCert, agencyCert} {
+		fingerprint := repo.CertFingerprint(cert)
+		if ok, _ := lg.GetState(constant.NodeManagerContractAddr.Address(), []byte(contracts.CertRevocationKey(fingerprint))); ok {
+			return fmt.Errorf("cert %s is revoked by governance: %w", fingerprint, errCertRevoked)
+		}
+	}
+	return nil
+}
+
+func isSerialRevoked(crl *pkix.CertificateList, cert *x509.Certificate) bool {
+	for _, revoked := range crl.TBSCertList.RevokedCertificates {
+		if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRevocation checks the certs of connected peers periodically, which may be revoked
+// after the connections are set up
+func (swarm *Swarm) checkRevocation() {
+	ticker := time.NewTicker(revocationCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, pid := range swarm.notifiee.connectedPids() {
+				swarm.checkPeerCerts(pid)
+			}
+		case <-swarm.ctx.Done():
+			return
+		}
+	}
+}
+
+// checkPeerCerts checks the certs the peer presented in the handshake again and closes the
+// connections if they are revoked, other errors are retried at the next check
+func (swarm *Swarm) checkPeerCerts(pid string) {
+	certs, ok := swarm.transport.getPeerCerts(pid)
+	if !ok {
+		return
+	}
+
+	err := swarm.revocation.checkPeer(pid, certs.pubKey, certs.nodeCert, certs.agencyCert)
+	switch {
+	case errors.Is(err, errCertRevoked):
+		swarm.logger.WithFields(logrus.Fields{"pid": pid, "error": err}).Warn("Close connections to a peer with revoked certs")
+		swarm.disconnectPid(pid)
+	case err != nil:
+		swarm.logger.WithFields(logrus.Fields{"pid": pid, "error": err}).Warn("Check certs of peer failed, retry later")
+	}
+}
@@ -0,0 +1,146 @@
+package peermgr
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/meshplus/bitxhub-kit/log"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
+	"github.com/meshplus/bitxhub/internal/ledger"
+	"github.com/meshplus/bitxhub/internal/ledger/mock_ledger"
+	"github.com/meshplus/bitxhub/internal/repo"
+	libp2pcert "github.com/meshplus/go-libp2p-cert"
+	"github.com/stretchr/testify/require"
+)
+
+func issueTestCert(t *testing.T, key *ecdsa.PrivateKey, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, isCA bool) *x509.Certificate {
+	template, err := libp2pcert.GenerateCert(key, isCA, "BitXHub")
+	require.Nil(t, err)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	data, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	require.Nil(t, err)
+	cert, err := x509.ParseCertificate(data)
+	require.Nil(t, err)
+	return cert
+}
+
+func writeTestCRL(t *testing.T, path string, issuers map[*x509.Certificate]*ecdsa.PrivateKey, revoked ...*x509.Certificate) {
+	buf := &bytes.Buffer{}
+	for issuer, key := range issuers {
+		var list []pkix.RevokedCertificate
+		for _, cert := range revoked {
+			list = append(list, pkix.RevokedCertificate{SerialNumber: cert.SerialNumber, RevocationTime: time.Now()})
+		}
+		data, err := issuer.CreateCRL(rand.Reader, key, list, time.Now(), time.Now().Add(time.Hour))
+		require.Nil(t, err)
+		require.Nil(t, pem.Encode(buf, &pem.Block{Type: "X509 CRL", Bytes: data}))
+	}
+	require.Nil(t, ioutil.WriteFile(path, buf.Bytes(), 0644))
+}
+
+func TestCertRevocation_CheckCerts(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	agencyKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	nodeKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	caCert := issueTestCert(t, caKey, nil, nil, true)
+	agencyCert := issueTestCert(t, agencyKey, caCert, caKey, true)
+	nodeCert := issueTestCert(t, nodeKey, agencyCert, agencyKey, false)
+	otherCert := issueTestCert(t, otherKey, agencyCert, agencyKey, false)
+	_, pubKey, err := crypto.ECDSAKeyPairFromKey(nodeKey)
+	require.Nil(t, err)
+
+	repoRoot, err := ioutil.TempDir("", "revocation")
+	require.Nil(t, err)
+	defer os.RemoveAll(repoRoot)
+
+	mockCtl := gomock.NewController(t)
+	stateLedger := mock_ledger.NewMockStateLedger(mockCtl)
+	revokedKey := contracts.CertRevocationKey(repo.CertFingerprint(otherCert))
+	stateLedger.EXPECT().Copy().Return(stateLedger).AnyTimes()
+	stateLedger.EXPECT().GetState(gomock.Any(), gomock.Any()).DoAndReturn(func(addr *types.Address, key []byte) (bool, []byte) {
+		return string(key) == revokedKey, nil
+	}).AnyTimes()
+
+	r := newCertRevocation(log.NewWithModule("p2p"), &ledger.Ledger{StateLedger: stateLedger}, repoRoot, repo.Cert{CRLPath: "crl.pem"}, caCert)
+
+	// no crl file is not taken as revoked
+	err = r.checkCerts(pubKey, nodeCert, agencyCert)
+	require.NotNil(t, err)
+	require.False(t, errors.Is(err, errCertRevoked))
+
+	crlPath := filepath.Join(repoRoot, "crl.pem")
+	writeTestCRL(t, crlPath, map[*x509.Certificate]*ecdsa.PrivateKey{caCert: caKey, agencyCert: agencyKey})
+	require.Nil(t, r.checkCerts(pubKey, nodeCert, agencyCert))
+
+	// certs are bound to the public key of the peer, but they are not revoked
+	err = r.checkCerts(pubKey, otherCert, agencyCert)
+	require.NotNil(t, err)
+	require.False(t, errors.Is(err, errCertRevoked))
+	err = r.checkCerts(pubKey, nodeCert, caCert)
+	require.NotNil(t, err)
+	require.False(t, errors.Is(err, errCertRevoked))
+
+	// node cert revoked by the agency
+	writeTestCRL(t, crlPath, map[*x509.Certificate]*ecdsa.PrivateKey{agencyCert: agencyKey}, nodeCert)
+	require.Nil(t, os.Chtimes(crlPath, time.Now(), time.Now().Add(time.Second)))
+	require.True(t, errors.Is(r.checkCerts(pubKey, nodeCert, agencyCert), errCertRevoked))
+
+	// agency cert revoked by the ca
+	writeTestCRL(t, crlPath, map[*x509.Certificate]*ecdsa.PrivateKey{caCert: caKey}, agencyCert)
+	require.Nil(t, os.Chtimes(crlPath, time.Now(), time.Now().Add(2*time.Second)))
+	require.True(t, errors.Is(r.checkCerts(pubKey, nodeCert, agencyCert), errCertRevoked))
+
+	// serials in CRLs of other issuers are ignored
+	writeTestCRL(t, crlPath, map[*x509.Certificate]*ecdsa.PrivateKey{otherCert: otherKey}, nodeCert, agencyCert)
+	require.Nil(t, os.Chtimes(crlPath, time.Now(), time.Now().Add(3*time.Second)))
+	require.Nil(t, r.checkCerts(pubKey, nodeCert, agencyCert))
+
+	// cert revoked by governance
+	_, otherPubKey, err := crypto.ECDSAKeyPairFromKey(otherKey)
+	require.Nil(t, err)
+	require.True(t, errors.Is(r.checkCerts(otherPubKey, otherCert, agencyCert), errCertRevoked))
+
+	// only the peers with revoked certs are refused by the gater
+	nodePid, err := peer.IDFromPublicKey(pubKey)
+	require.Nil(t, err)
+	pid, err := peer.IDFromPublicKey(otherPubKey)
+	require.Nil(t, err)
+	gater := newConnectionGater(log.NewWithModule("p2p"), nil, nil)
+	gater.revocation = r
+	require.NotNil(t, r.checkPeer(nodePid.String(), pubKey, otherCert, agencyCert))
+	require.True(t, gater.InterceptPeerDial(nodePid))
+	require.True(t, gater.InterceptPeerDial(pid))
+	require.NotNil(t, r.checkPeer(pid.String(), otherPubKey, otherCert, agencyCert))
+	require.False(t, gater.InterceptPeerDial(pid))
+	require.False(t, gater.InterceptSecured(0, pid, nil))
+
+	// the peer is accepted again once it presents a reissued cert
+	reissuedCert := issueTestCert(t, otherKey, agencyCert, agencyKey, false)
+	require.Nil(t, r.checkPeer(pid.String(), otherPubKey, reissuedCert, agencyCert))
+	require.True(t, gater.InterceptPeerDial(pid))
+	require.NotNil(t, r.checkPeer(pid.String(), otherPubKey, otherCert, agencyCert))
+	require.False(t, gater.InterceptPeerDial(pid))
+}
@@ -0,0 +1,339 @@
+package peermgr
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/sirupsen/logrus"
+)
+
+// Misbehaviour is a kind of misbehaviour of a peer which lowers its score
+type Misbehaviour int
+
+const (
+	// SlowResponse means the ping rtt of the peer is above the max latency
+	SlowResponse Misbehaviour = iota
+	// FailedRequest means a request to the peer failed or timed out
+	FailedRequest
+	// InvalidResponse means the peer replied with a malformed or unexpected message
+	InvalidResponse
+	// ProtocolViolation means the peer sent a message which can't be handled
+	ProtocolViolation
+)
+
+func (m Misbehaviour) String() string {
+	switch m {
+	case SlowResponse:
+		return "slow_response"
+	case FailedRequest:
+		return "failed_request"
+	case InvalidResponse:
+		return "invalid_response"
+	case ProtocolViolation:
+		return "protocol_violation"
+	default:
+		return "unknown"
+	}
+}
+
+// PeerScore is the health of a peer. The score starts at 0, drops with every misbehaviour
+// of the peer and recovers towards 0 over time.
+type PeerScore struct {
+	Score              float64       `json:"score"`
+	RTT                time.Duration `json:"rtt"`
+	SlowResponses      uint64        `json:"slow_responses"`
+	FailedRequests     uint64        `json:"failed_requests"`
+	InvalidResponses   uint64        `json:"invalid_responses"`
+	ProtocolViolations uint64        `json:"protocol_violations"`
+	Banned             bool          `json:"banned"`
+	BannedUntil        time.Time     `json:"banned_until"`
+
+	pid        string
+	lastUpdate time.Time
+}
+
+// PeerInfo is the vp info of an order peer along with its score
+type PeerInfo struct {
+	*pb.VpInfo
+	Score *PeerScore `json:"score,omitempty"`
+}
+
+type peerScorer struct {
+	config repo.PeerScore
+	scores map[uint64]*PeerScore
+	lock   sync.RWMutex
+	now    func() time.Time
+}
+
+func newPeerScorer(config repo.PeerScore) *peerScorer {
+	return &peerScorer{
+		config: config,
+		scores: make(map[uint64]*PeerScore),
+		now:    time.Now,
+	}
+}
+
+func (s *peerScorer) setConfig(config repo.PeerScore) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.config = config
+}
+
+func (s *peerScorer) getConfig() repo.PeerScore {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.config
+}
+
+// get returns the score of the peer with decay and expired ban applied, the lock must be held
+func (s *peerScorer) get(id uint64, pid string, now time.Time) *PeerScore {
+	score, ok := s.scores[id]
+	if !ok {
+		score = &PeerScore{lastUpdate: now}
+		s.scores[id] = score
+	}
+	if pid != "" {
+		score.pid = pid
+	}
+
+	if score.Banned && !now.Before(score.BannedUntil) {
+		score.Banned = false
+		score.Score = 0
+	}
+	if s.config.DecayHalfLife > 0 && score.Score != 0 {
+		elapsed := now.Sub(score.lastUpdate)
+		score.Score *= math.Pow(0.5, float64(elapsed)/float64(s.config.DecayHalfLife))
+		if math.Abs(score.Score) < 0.01 {
+			score.Score = 0
+		}
+	}
+	score.lastUpdate = now
+
+	return score
+}
+
+// report penalizes the peer for the misbehaviour, it returns true if the peer gets banned by it.
+// Misbehaviours of a banned peer are not counted.
+func (s *peerScorer) report(id uint64, pid string, m Misbehaviour) (*PeerScore, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	score := s.get(id, pid, now)
+	if score.Banned {
+		return score.copy(), false
+	}
+
+	score.Score -= s.penalty(m)
+	switch m {
+	case SlowResponse:
+		score.SlowResponses++
+	case FailedRequest:
+		score.FailedRequests++
+	case InvalidResponse:
+		score.InvalidResponses++
+	case ProtocolViolation:
+		score.ProtocolViolations++
+	}
+
+	if s.config.EnableBan && score.Score <= s.config.BanThreshold {
+		score.Banned = true
+		score.BannedUntil = now.Add(s.config.BanDuration)
+		return score.copy(), true
+	}
+
+	return score.copy(), false
+}
+
+// penalty returns the configured score penalty of the misbehaviour, the lock must be held
+func (s *peerScorer) penalty(m Misbehaviour) float64 {
+	switch m {
+	case SlowResponse:
+		return s.config.SlowResponsePenalty
+	case FailedRequest:
+		return s.config.FailedRequestPenalty
+	case InvalidResponse:
+		return s.config.InvalidResponsePenalty
+	case ProtocolViolation:
+		return s.config.ProtocolViolationPenalty
+	default:
+		return 0
+	}
+}
+
+// until returns the duration until the time by the clock of the scorer
+func (s *peerScorer) until(t time.Time) time.Duration {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return t.Sub(s.now())
+}
+
+// recordRTT records the ping rtt of the peer and penalizes it if the rtt is above the max latency
+func (s *peerScorer) recordRTT(id uint64, pid string, rtt time.Duration) (*PeerScore, bool) {
+	s.lock.Lock()
+	score := s.get(id, pid, s.now())
+	score.RTT = rtt
+	maxLatency := s.config.MaxLatency
+	s.lock.Unlock()
+
+	if maxLatency > 0 && rtt > maxLatency {
+		return s.report(id, pid, SlowResponse)
+	}
+
+	return s.snapshot(id), false
+}
+
+func (s *peerScorer) snapshot(id uint64) *PeerScore {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.scores[id]; !ok {
+		return nil
+	}
+	return s.get(id, "", s.now()).copy()
+}
+
+// isBanned checks whether the peer with the libp2p pid is banned
+func (s *peerScorer) isBanned(pid string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	for id, score := range s.scores {
+		if score.pid == pid {
+			return s.get(id, "", now).Banned
+		}
+	}
+	return false
+}
+
+func (s *peerScorer) remove(id uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.scores, id)
+}
+
+func (s *peerScorer) all() map[uint64]*PeerScore {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	scores := make(map[uint64]*PeerScore, len(s.scores))
+	for id := range s.scores {
+		scores[id] = s.get(id, "", now).copy()
+	}
+	return scores
+}
+
+func (score *PeerScore) copy() *PeerScore {
+	cp := *score
+	return &cp
+}
+
+// ReportPeer lowers the score of the vp peer for its misbehaviour, the peer is disconnected
+// and refused until the ban expires once its score drops to the ban threshold
+func (swarm *Swarm) ReportPeer(id uint64, m Misbehaviour) {
+	info, ok := swarm.notifiee.getPeers()[id]
+	if !ok || id == swarm.localID {
+		return
+	}
+
+	peerMisbehaviourCounter.WithLabelValues(strconv.FormatUint(id, 10), m.String()).Inc()
+	score, banned := swarm.scorer.report(id, info.Pid, m)
+	swarm.applyPeerScore(id, score, banned)
+}
+
+// PeerScores returns the scores of vp peers which have been scored
+func (swarm *Swarm) PeerScores() map[uint64]*PeerScore {
+	return swarm.scorer.all()
+}
+
+func (swarm *Swarm) reportPid(pid string, m Misbehaviour) {
+	for id, info := range swarm.notifiee.getPeers() {
+		if info.Pid == pid {
+			swarm.ReportPeer(id, m)
+			return
+		}
+	}
+}
+
+func (swarm *Swarm) recordPeerRTT(id uint64, pid string, rtt time.Duration) {
+	peerRTTGauge.WithLabelValues(strconv.FormatUint(id, 10)).Set(rtt.Seconds())
+	score, banned := swarm.scorer.recordRTT(id, pid, rtt)
+	swarm.applyPeerScore(id, score, banned)
+}
+
+func (swarm *Swarm) applyPeerScore(id uint64, score *PeerScore, banned bool) {
+	if score == nil {
+		return
+	}
+	peerScoreGauge.WithLabelValues(strconv.FormatUint(id, 10)).Set(score.Score)
+	if banned {
+		swarm.banPeer(id, score)
+	}
+}
+
+func (swarm *Swarm) banPeer(id uint64, score *PeerScore) {
+	label := strconv.FormatUint(id, 10)
+	swarm.logger.WithFields(logrus.Fields{
+		"node":  id,
+		"pid":   score.pid,
+		"score": score.Score,
+		"until": score.BannedUntil,
+	}).Warn("Ban misbehaving peer")
+
+	peerBannedGauge.WithLabelValues(label).Set(1)
+	connected, _ := swarm.connectedPeers.Load(id)
+	swarm.connectedPeers.Delete(id)
+	if err := swarm.p2p.Disconnect(score.pid); err != nil {
+		swarm.logger.Errorf("Disconnect banned peer %d failed, err: %s", id, err.Error())
+	}
+
+	addr, _ := connected.(*peer.AddrInfo)
+	time.AfterFunc(swarm.scorer.until(score.BannedUntil), func() {
+		swarm.unbanPeer(id, addr)
+	})
+}
+
+// unbanPeer reconnects the peer whose ban expired, addr is the address it was connected with
+func (swarm *Swarm) unbanPeer(id uint64, addr *peer.AddrInfo) {
+	peerBannedGauge.WithLabelValues(strconv.FormatUint(id, 10)).Set(0)
+	select {
+	case <-swarm.ctx.Done():
+		return
+	default:
+	}
+
+	info, ok := swarm.notifiee.getPeers()[id]
+	if !ok {
+		return
+	}
+	if addr == nil {
+		var err error
+		if addr, err = constructMultiaddr(info); err != nil {
+			swarm.logger.Errorf("Construct AddrInfo of peer %d failed: %s", id, err.Error())
+			return
+		}
+	}
+	swarm.logger.Infof("Ban of peer %d expired, reconnecting", id)
+	swarm.connectPeer(id, addr, swarm.ctx)
+}
+
+func (swarm *Swarm) removePeerScore(id uint64) {
+	label := strconv.FormatUint(id, 10)
+	swarm.scorer.remove(id)
+	peerScoreGauge.DeleteLabelValues(label)
+	peerRTTGauge.DeleteLabelValues(label)
+	peerBannedGauge.DeleteLabelValues(label)
+}
+
+func (swarm *Swarm) updatePeerScoreMetrics() {
+	for id, score := range swarm.scorer.all() {
+		peerScoreGauge.WithLabelValues(strconv.FormatUint(id, 10)).Set(score.Score)
+	}
+}
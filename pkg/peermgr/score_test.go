@@ -0,0 +1,155 @@
+package peermgr
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerScorer(t *testing.T) {
+	now := time.Now()
+	config := repo.PeerScore{
+		EnableBan:                true,
+		BanThreshold:             -30,
+		BanDuration:              time.Minute,
+		DecayHalfLife:            time.Minute,
+		MaxLatency:               time.Second,
+		InvalidResponsePenalty:   10,
+		ProtocolViolationPenalty: 20,
+	}
+	scorer := newPeerScorer(config)
+	scorer.now = func() time.Time { return now }
+
+	score, banned := scorer.recordRTT(1, "pid1", 500*time.Millisecond)
+	require.False(t, banned)
+	require.Equal(t, float64(0), score.Score)
+	require.Equal(t, 500*time.Millisecond, score.RTT)
+
+	// transport failures are counted without penalty
+	score, banned = scorer.recordRTT(1, "pid1", 2*time.Second)
+	require.False(t, banned)
+	require.Equal(t, float64(0), score.Score)
+	require.Equal(t, uint64(1), score.SlowResponses)
+	for i := 0; i < 100; i++ {
+		score, banned = scorer.report(1, "pid1", FailedRequest)
+		require.False(t, banned)
+	}
+	require.Equal(t, float64(0), score.Score)
+	require.Equal(t, uint64(100), score.FailedRequests)
+
+	score, banned = scorer.report(1, "pid1", ProtocolViolation)
+	require.False(t, banned)
+	require.Equal(t, float64(-20), score.Score)
+	require.False(t, scorer.isBanned("pid1"))
+
+	// penalties are halved after a half life
+	now = now.Add(time.Minute)
+	require.InDelta(t, -10, scorer.snapshot(1).Score, 0.001)
+
+	score, banned = scorer.report(1, "pid1", InvalidResponse)
+	require.False(t, banned)
+	require.InDelta(t, -20, score.Score, 0.001)
+
+	score, banned = scorer.report(1, "pid1", InvalidResponse)
+	require.True(t, banned)
+	require.True(t, score.Banned)
+	require.Equal(t, now.Add(time.Minute), score.BannedUntil)
+	require.Equal(t, uint64(2), score.InvalidResponses)
+	require.True(t, scorer.isBanned("pid1"))
+	require.False(t, scorer.isBanned("pid2"))
+
+	// misbehaviours of a banned peer are not counted
+	score, banned = scorer.report(1, "pid1", InvalidResponse)
+	require.False(t, banned)
+	require.Equal(t, uint64(2), score.InvalidResponses)
+
+	// the score is reset once the ban expires
+	now = now.Add(time.Minute)
+	require.False(t, scorer.isBanned("pid1"))
+	require.Equal(t, float64(0), scorer.snapshot(1).Score)
+
+	scorer.setConfig(repo.PeerScore{BanThreshold: -30, ProtocolViolationPenalty: 20})
+	for i := 0; i < 10; i++ {
+		_, banned = scorer.report(2, "pid2", ProtocolViolation)
+		require.False(t, banned)
+	}
+	require.Equal(t, float64(-200), scorer.all()[2].Score)
+
+	// transport failures are penalized if configured
+	config.FailedRequestPenalty = 15
+	scorer.setConfig(config)
+	score, banned = scorer.report(3, "pid3", FailedRequest)
+	require.False(t, banned)
+	require.Equal(t, float64(-15), score.Score)
+	score, banned = scorer.report(3, "pid3", FailedRequest)
+	require.True(t, banned)
+	require.Equal(t, uint64(2), score.FailedRequests)
+	scorer.remove(3)
+
+	scorer.remove(2)
+	require.Nil(t, scorer.snapshot(2))
+	require.Equal(t, 1, len(scorer.all()))
+}
+
+func TestSwarm_BanPeer(t *testing.T) {
+	peerCnt := 4
+	swarms := NewSwarms(t, peerCnt)
+	defer stopSwarms(t, swarms)
+
+	for swarms[0].CountConnectedPeers() != 3 {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// the ban expires by the clock of the scorer, so the test doesn't wait for it
+	var (
+		lock sync.Mutex
+		now  = time.Now()
+	)
+	swarms[0].scorer.lock.Lock()
+	swarms[0].scorer.now = func() time.Time {
+		lock.Lock()
+		defer lock.Unlock()
+		return now
+	}
+	swarms[0].scorer.lock.Unlock()
+	swarms[0].scorer.setConfig(repo.PeerScore{
+		EnableBan:                true,
+		BanThreshold:             -40,
+		BanDuration:              time.Hour,
+		ProtocolViolationPenalty: 20,
+	})
+
+	// malformed messages are protocol violations
+	pid, err := swarms[1].findPeer(1)
+	require.Nil(t, err)
+	for i := 0; i < 2; i++ {
+		require.Nil(t, swarms[1].p2p.AsyncSend(pid, []byte("malformed")))
+	}
+
+	require.Eventually(t, func() bool {
+		score, ok := swarms[0].PeerScores()[2]
+		return ok && score.Banned
+	}, 5*time.Second, 50*time.Millisecond)
+	score := swarms[0].PeerScores()[2]
+	require.Equal(t, uint64(2), score.ProtocolViolations)
+	require.Equal(t, float64(-40), score.Score)
+
+	bannedPid, err := peer.Decode(swarms[0].routers[2].Pid)
+	require.Nil(t, err)
+	require.False(t, swarms[0].gater.InterceptPeerDial(bannedPid))
+	require.False(t, swarms[0].gater.InterceptSecured(0, bannedPid, nil))
+	require.Equal(t, uint64(2), swarms[0].CountConnectedPeers())
+
+	// the peer is allowed again after the ban expires
+	lock.Lock()
+	now = now.Add(time.Hour)
+	lock.Unlock()
+	require.False(t, swarms[0].PeerScores()[2].Banned)
+	require.Equal(t, float64(0), swarms[0].PeerScores()[2].Score)
+	require.True(t, swarms[0].gater.InterceptPeerDial(bannedPid))
+	require.True(t, swarms[0].gater.InterceptSecured(0, bannedPid, nil))
+}
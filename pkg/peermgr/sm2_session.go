@@ -0,0 +1,180 @@
+package peermgr
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/sec"
+	"github.com/meshplus/bitxhub/pkg/crypto/sm2"
+	"github.com/tjfoc/gmsm/sm4"
+)
+
+const (
+	// sm2MaxFrameLen is the maximum length of the frames on the wire, including the GCM tag
+	sm2MaxFrameLen    = 0xffff
+	sm2FrameHeaderLen = 2
+)
+
+var _ sec.SecureConn = (*sm2Session)(nil)
+
+// sm2Session is the connection secured by sm2Transport, every write is sent in frames encrypted
+// by SM4 in GCM mode with the key of the direction and the frame counter as nonce
+type sm2Session struct {
+	initiator bool
+
+	localID   peer.ID
+	localKey  *sm2.Libp2pPrivKey
+	remoteID  peer.ID
+	remoteKey *sm2.Libp2pPubKey
+
+	insecure net.Conn
+	reader   *bufio.Reader
+
+	readLock sync.Mutex
+	dec      cipher.AEAD
+	decNonce uint64
+	queued   []byte // decrypted bytes not read yet
+
+	writeLock sync.Mutex
+	enc       cipher.AEAD
+	encNonce  uint64
+}
+
+func newSM2Session(insecure net.Conn, localID peer.ID, localKey *sm2.Libp2pPrivKey, remoteID peer.ID, initiator bool) *sm2Session {
+	return &sm2Session{
+		initiator: initiator,
+		localID:   localID,
+		localKey:  localKey,
+		remoteID:  remoteID,
+		insecure:  insecure,
+		reader:    bufio.NewReader(insecure),
+	}
+}
+
+// setCiphers sets the SM4 ciphers of both directions once the keys are exchanged
+func (s *sm2Session) setCiphers(encKey, decKey []byte) error {
+	enc, err := newSM4GCM(encKey)
+	if err != nil {
+		return err
+	}
+	dec, err := newSM4GCM(decKey)
+	if err != nil {
+		return err
+	}
+	s.enc = enc
+	s.dec = dec
+	return nil
+}
+
+func newSM4GCM(key []byte) (cipher.AEAD, error) {
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create sm4 cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// nonce returns the nonce of the next frame, the keys of both directions differ so that
+// the counters of both directions never produce the same nonce under the same key
+func nonce(aead cipher.AEAD, counter *uint64) []byte {
+	ret := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(ret[len(ret)-8:], *counter)
+	*counter++
+	return ret
+}
+
+func (s *sm2Session) Read(buf []byte) (int, error) {
+	s.readLock.Lock()
+	defer s.readLock.Unlock()
+
+	for len(s.queued) == 0 {
+		var size [sm2FrameHeaderLen]byte
+		if _, err := io.ReadFull(s.reader, size[:]); err != nil {
+			return 0, err
+		}
+		frame := make([]byte, binary.BigEndian.Uint16(size[:]))
+		if _, err := io.ReadFull(s.reader, frame); err != nil {
+			return 0, err
+		}
+		plain, err := s.dec.Open(frame[:0], nonce(s.dec, &s.decNonce), frame, nil)
+		if err != nil {
+			return 0, fmt.Errorf("decrypt frame: %w", err)
+		}
+		s.queued = plain
+	}
+
+	n := copy(buf, s.queued)
+	s.queued = s.queued[n:]
+	return n, nil
+}
+
+func (s *sm2Session) Write(data []byte) (int, error) {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	maxPlaintext := sm2MaxFrameLen - s.enc.Overhead()
+	written := 0
+	for written < len(data) {
+		end := written + maxPlaintext
+		if end > len(data) {
+			end = len(data)
+		}
+
+		frame := make([]byte, sm2FrameHeaderLen, sm2FrameHeaderLen+end-written+s.enc.Overhead())
+		frame = s.enc.Seal(frame, nonce(s.enc, &s.encNonce), data[written:end], nil)
+		binary.BigEndian.PutUint16(frame, uint16(len(frame)-sm2FrameHeaderLen))
+		if _, err := s.insecure.Write(frame); err != nil {
+			return written, err
+		}
+		written = end
+	}
+	return written, nil
+}
+
+func (s *sm2Session) LocalPeer() peer.ID {
+	return s.localID
+}
+
+func (s *sm2Session) LocalPrivateKey() crypto.PrivKey {
+	return s.localKey
+}
+
+func (s *sm2Session) RemotePeer() peer.ID {
+	return s.remoteID
+}
+
+func (s *sm2Session) RemotePublicKey() crypto.PubKey {
+	return s.remoteKey
+}
+
+func (s *sm2Session) LocalAddr() net.Addr {
+	return s.insecure.LocalAddr()
+}
+
+func (s *sm2Session) RemoteAddr() net.Addr {
+	return s.insecure.RemoteAddr()
+}
+
+func (s *sm2Session) SetDeadline(t time.Time) error {
+	return s.insecure.SetDeadline(t)
+}
+
+func (s *sm2Session) SetReadDeadline(t time.Time) error {
+	return s.insecure.SetReadDeadline(t)
+}
+
+func (s *sm2Session) SetWriteDeadline(t time.Time) error {
+	return s.insecure.SetWriteDeadline(t)
+}
+
+func (s *sm2Session) Close() error {
+	return s.insecure.Close()
+}
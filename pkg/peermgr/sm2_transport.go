@@ -0,0 +1,230 @@
+package peermgr
+
+import (
+	"context"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/sec"
+	"github.com/meshplus/bitxhub/pkg/crypto/sm2"
+	gmsm2 "github.com/tjfoc/gmsm/sm2"
+)
+
+// sm4KeyLen is the length of the SM4 keys of both directions derived by the key exchange
+const sm4KeyLen = 16
+
+var _ sec.SecureTransport = (*sm2Transport)(nil)
+
+// sm2Transport secures the connections of nodes with SM2 keys by the SM2 key exchange of
+// GB/T 32918.3. The identity keys of both peers take part in the key exchange together with
+// ephemeral keys, and the confirmation hashes are checked, so that the session keys are only
+// derived by the holders of the identity keys. The traffic is then encrypted by SM4 in GCM mode.
+type sm2Transport struct {
+	localID peer.ID
+	privKey *sm2.Libp2pPrivKey
+}
+
+// sm2Hello is the handshake message, the confirmation is absent in the first message of the initiator
+// and is the only field of its last message
+type sm2Hello struct {
+	IdentityKey  []byte `json:"identity_key,omitempty"`
+	EphemeralKey []byte `json:"ephemeral_key,omitempty"`
+	Confirm      []byte `json:"confirm,omitempty"`
+}
+
+func newSM2Transport(privKey crypto.PrivKey) (*sm2Transport, error) {
+	key, ok := privKey.(*sm2.Libp2pPrivKey)
+	if !ok {
+		return nil, fmt.Errorf("SM2 transport needs SM2 key, got key type %d", privKey.Type())
+	}
+	localID, err := peer.IDFromPrivateKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sm2Transport{
+		localID: localID,
+		privKey: key,
+	}, nil
+}
+
+func (t *sm2Transport) SecureInbound(ctx context.Context, insecure net.Conn) (sec.SecureConn, error) {
+	return t.secure(ctx, insecure, "", false)
+}
+
+func (t *sm2Transport) SecureOutbound(ctx context.Context, insecure net.Conn, p peer.ID) (sec.SecureConn, error) {
+	return t.secure(ctx, insecure, p, true)
+}
+
+// secure runs the handshake and closes the insecure connection if it fails or the context is done
+func (t *sm2Transport) secure(ctx context.Context, insecure net.Conn, remote peer.ID, initiator bool) (sec.SecureConn, error) {
+	s := newSM2Session(insecure, t.localID, t.privKey, remote, initiator)
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := insecure.SetDeadline(deadline); err == nil {
+			defer insecure.SetDeadline(time.Time{})
+		}
+	}
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- s.runHandshake()
+	}()
+
+	select {
+	case err := <-errC:
+		if err != nil {
+			_ = insecure.Close()
+			return nil, err
+		}
+		return s, nil
+	case <-ctx.Done():
+		_ = insecure.Close()
+		<-errC
+		return nil, ctx.Err()
+	}
+}
+
+// runHandshake exchanges the keys, the initiator is A and the responder is B of the key exchange.
+// A sends its identity key and ephemeral key, B replies with its keys and its confirmation, and
+// A sends its confirmation at last.
+func (s *sm2Session) runHandshake() error {
+	ephemeral, err := gmsm2.GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("generate ephemeral key: %w", err)
+	}
+	identityKey, err := s.localKey.GetPublic().Bytes()
+	if err != nil {
+		return fmt.Errorf("marshal identity key: %w", err)
+	}
+	local := &sm2Hello{
+		IdentityKey:  identityKey,
+		EphemeralKey: elliptic.Marshal(ephemeral.Curve, ephemeral.X, ephemeral.Y),
+	}
+
+	if s.initiator {
+		if err := s.writeHello(local); err != nil {
+			return err
+		}
+		remote, err := s.readHello()
+		if err != nil {
+			return err
+		}
+		expected := s.remoteID
+		remoteEphemeral, err := s.setRemote(remote)
+		if err != nil {
+			return err
+		}
+		if expected != s.remoteID {
+			return fmt.Errorf("peer id mismatch: expected %s, but remote key matches %s", expected.Pretty(), s.remoteID.Pretty())
+		}
+
+		k, s1, sa, err := gmsm2.KeyExchangeA(2*sm4KeyLen, []byte(s.localID), []byte(s.remoteID), s.localKey.K, s.remoteKey.K, ephemeral, remoteEphemeral)
+		if err != nil {
+			return fmt.Errorf("exchange key: %w", err)
+		}
+		if !hmac.Equal(s1, remote.Confirm) {
+			return fmt.Errorf("key confirmation of remote peer is invalid")
+		}
+		if err := s.writeHello(&sm2Hello{Confirm: sa}); err != nil {
+			return err
+		}
+		return s.setCiphers(k[:sm4KeyLen], k[sm4KeyLen:])
+	}
+
+	remote, err := s.readHello()
+	if err != nil {
+		return err
+	}
+	remoteEphemeral, err := s.setRemote(remote)
+	if err != nil {
+		return err
+	}
+
+	k, sb, s2, err := gmsm2.KeyExchangeB(2*sm4KeyLen, []byte(s.remoteID), []byte(s.localID), s.localKey.K, s.remoteKey.K, ephemeral, remoteEphemeral)
+	if err != nil {
+		return fmt.Errorf("exchange key: %w", err)
+	}
+	local.Confirm = sb
+	if err := s.writeHello(local); err != nil {
+		return err
+	}
+	confirm, err := s.readHello()
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(s2, confirm.Confirm) {
+		return fmt.Errorf("key confirmation of remote peer is invalid")
+	}
+	return s.setCiphers(k[sm4KeyLen:], k[:sm4KeyLen])
+}
+
+// setRemote sets the identity of the remote peer and returns its ephemeral key
+func (s *sm2Session) setRemote(hello *sm2Hello) (*gmsm2.PublicKey, error) {
+	pubKey, err := crypto.UnmarshalPublicKey(hello.IdentityKey)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal identity key: %w", err)
+	}
+	remoteKey, ok := pubKey.(*sm2.Libp2pPubKey)
+	if !ok {
+		return nil, fmt.Errorf("identity key of remote peer is not SM2 key")
+	}
+	id, err := peer.IDFromPublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	curve := gmsm2.P256Sm2()
+	x, y := elliptic.Unmarshal(curve, hello.EphemeralKey)
+	if x == nil {
+		return nil, fmt.Errorf("invalid ephemeral key")
+	}
+
+	s.remoteID = id
+	s.remoteKey = remoteKey
+	return &gmsm2.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func (s *sm2Session) writeHello(hello *sm2Hello) error {
+	data, err := json.Marshal(hello)
+	if err != nil {
+		return fmt.Errorf("marshal handshake message: %w", err)
+	}
+	if len(data) > sm2MaxFrameLen {
+		return fmt.Errorf("handshake message of %d bytes is too large", len(data))
+	}
+
+	buf := make([]byte, sm2FrameHeaderLen+len(data))
+	binary.BigEndian.PutUint16(buf, uint16(len(data)))
+	copy(buf[sm2FrameHeaderLen:], data)
+	if _, err := s.insecure.Write(buf); err != nil {
+		return fmt.Errorf("write handshake message: %w", err)
+	}
+	return nil
+}
+
+func (s *sm2Session) readHello() (*sm2Hello, error) {
+	var size [sm2FrameHeaderLen]byte
+	if _, err := io.ReadFull(s.reader, size[:]); err != nil {
+		return nil, fmt.Errorf("read handshake message: %w", err)
+	}
+	data := make([]byte, binary.BigEndian.Uint16(size[:]))
+	if _, err := io.ReadFull(s.reader, data); err != nil {
+		return nil, fmt.Errorf("read handshake message: %w", err)
+	}
+
+	hello := &sm2Hello{}
+	if err := json.Unmarshal(data, hello); err != nil {
+		return nil, fmt.Errorf("unmarshal handshake message: %w", err)
+	}
+	return hello, nil
+}
@@ -0,0 +1,177 @@
+package peermgr
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/sec"
+	"github.com/meshplus/bitxhub-kit/log"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
+	"github.com/meshplus/bitxhub/internal/ledger"
+	"github.com/meshplus/bitxhub/internal/ledger/mock_ledger"
+	"github.com/meshplus/bitxhub/internal/repo"
+	sm2crypto "github.com/meshplus/bitxhub/pkg/crypto/sm2"
+	"github.com/stretchr/testify/require"
+	"github.com/tjfoc/gmsm/sm2"
+	gmx509 "github.com/tjfoc/gmsm/x509"
+)
+
+func issueSM2TestCert(t *testing.T, key *sm2.PrivateKey, parent *x509.Certificate, parentKey *sm2.PrivateKey, isCA bool) *x509.Certificate {
+	template := &gmx509.Certificate{
+		SignatureAlgorithm:    gmx509.SM2WithSM3,
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+		KeyUsage:              gmx509.KeyUsageDigitalSignature | gmx509.KeyUsageCertSign | gmx509.KeyUsageCRLSign,
+		Subject:               pkix.Name{Organization: []string{"BitXHub"}},
+	}
+	gmParent := template
+	if parent == nil {
+		parentKey = key
+	} else {
+		var err error
+		gmParent, err = gmx509.ParseCertificate(parent.Raw)
+		require.Nil(t, err)
+	}
+	data, err := gmx509.CreateCertificate(template, gmParent, &key.PublicKey, parentKey)
+	require.Nil(t, err)
+	cert, err := sm2crypto.ParseCert(data)
+	require.Nil(t, err)
+	return cert
+}
+
+func TestSM2CertTransport(t *testing.T) {
+	caKey, err := sm2.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	agencyKey, err := sm2.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	caCert := issueSM2TestCert(t, caKey, nil, nil, true)
+	agencyCert := issueSM2TestCert(t, agencyKey, caCert, caKey, true)
+
+	var keys []crypto.PrivKey
+	var certs []*x509.Certificate
+	var pids []peer.ID
+	for i := 0; i < 2; i++ {
+		key, err := sm2.GenerateKey(rand.Reader)
+		require.Nil(t, err)
+		libp2pKey, _, err := sm2crypto.Libp2pKeyPairFromKey(key)
+		require.Nil(t, err)
+		pid, err := peer.IDFromPrivateKey(libp2pKey)
+		require.Nil(t, err)
+		keys = append(keys, libp2pKey)
+		certs = append(certs, issueSM2TestCert(t, key, agencyCert, agencyKey, false))
+		pids = append(pids, pid)
+	}
+
+	mockCtl := gomock.NewController(t)
+	stateLedger := mock_ledger.NewMockStateLedger(mockCtl)
+	revokedKey := contracts.CertRevocationKey(repo.CertFingerprint(certs[1]))
+	revoked := false
+	stateLedger.EXPECT().Copy().Return(stateLedger).AnyTimes()
+	stateLedger.EXPECT().GetState(gomock.Any(), gomock.Any()).DoAndReturn(func(addr *types.Address, key []byte) (bool, []byte) {
+		return revoked && string(key) == revokedKey, nil
+	}).AnyTimes()
+	lg := &ledger.Ledger{StateLedger: stateLedger}
+
+	r0 := newCertRevocation(log.NewWithModule("p2p"), lg, "", repo.Cert{}, caCert)
+	t0, err := newCertTransport(keys[0], testCerts(t, certs[0], agencyCert, caCert), r0)
+	require.Nil(t, err)
+	require.Equal(t, sm2CertTransportID, t0.protocolID())
+	r1 := newCertRevocation(log.NewWithModule("p2p"), lg, "", repo.Cert{}, caCert)
+	t1, err := newCertTransport(keys[1], testCerts(t, certs[1], agencyCert, caCert), r1)
+	require.Nil(t, err)
+
+	// the remote peer is authenticated by the key exchange
+	_, err = handshake(t, t0, t1, pids[0])
+	require.NotNil(t, err)
+
+	conn, err := handshake(t, t0, t1, pids[1])
+	require.Nil(t, err)
+	require.Equal(t, pids[1], conn.RemotePeer())
+	require.True(t, conn.RemotePublicKey().Equals(keys[1].GetPublic()))
+	peerCerts, ok := t0.getPeerCerts(pids[1].String())
+	require.True(t, ok)
+	require.Equal(t, certs[1].Raw, peerCerts.nodeCert.Raw)
+	require.Nil(t, conn.Close())
+
+	// certs not issued for the key of the peer are refused
+	require.Nil(t, t1.setCerts(testCerts(t, certs[0], agencyCert, caCert)))
+	_, err = handshake(t, t0, t1, pids[1])
+	require.NotNil(t, err)
+	require.False(t, r0.isRevoked(pids[1].String()))
+
+	// peers with revoked certs are refused
+	require.Nil(t, t1.setCerts(testCerts(t, certs[1], agencyCert, caCert)))
+	revoked = true
+	_, err = handshake(t, t0, t1, pids[1])
+	require.NotNil(t, err)
+	require.True(t, r0.isRevoked(pids[1].String()))
+}
+
+func TestSM2Session(t *testing.T) {
+	var tpts []*sm2Transport
+	var pids []peer.ID
+	for i := 0; i < 2; i++ {
+		key, err := sm2.GenerateKey(rand.Reader)
+		require.Nil(t, err)
+		libp2pKey, _, err := sm2crypto.Libp2pKeyPairFromKey(key)
+		require.Nil(t, err)
+		tpt, err := newSM2Transport(libp2pKey)
+		require.Nil(t, err)
+		tpts = append(tpts, tpt)
+		pids = append(pids, tpt.localID)
+	}
+
+	c1, c2 := net.Pipe()
+	connC := make(chan sec.SecureConn, 1)
+	go func() {
+		conn, _ := tpts[1].SecureInbound(context.Background(), c2)
+		connC <- conn
+	}()
+	local, err := tpts[0].SecureOutbound(context.Background(), c1, pids[1])
+	require.Nil(t, err)
+	remote := <-connC
+	require.NotNil(t, remote)
+	require.Equal(t, pids[0], remote.RemotePeer())
+	defer local.Close()
+	defer remote.Close()
+
+	// the data larger than a frame is split into several frames
+	data := make([]byte, 3*sm2MaxFrameLen)
+	_, err = rand.Read(data)
+	require.Nil(t, err)
+	errC := make(chan error, 1)
+	go func() {
+		_, err := local.Write(data)
+		errC <- err
+	}()
+	received := make([]byte, len(data))
+	_, err = io.ReadFull(remote, received)
+	require.Nil(t, err)
+	require.Nil(t, <-errC)
+	require.True(t, bytes.Equal(data, received))
+
+	go func() {
+		_, err := remote.Write([]byte("pong"))
+		errC <- err
+	}()
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(local, buf)
+	require.Nil(t, err)
+	require.Nil(t, <-errC)
+	require.Equal(t, "pong", string(buf))
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/Rican7/retry"
 	"github.com/Rican7/retry/strategy"
 	"github.com/ethereum/go-ethereum/event"
-	"github.com/libp2p/go-libp2p-core/connmgr"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
 	orderPeerMgr "github.com/meshplus/bitxhub-core/peer-mgr"
@@ -43,7 +42,13 @@ type Swarm struct {
 	connectedPeers sync.Map
 	notifiee       *notifiee
 	piers          *Piers
-	gater          connmgr.ConnectionGater
+	gater          *connectionGater
+	scorer         *peerScorer
+	gossip         *gossip
+	revocation     *certRevocation
+	transport      *certTransport
+
+	nodeEventSource NodeEventSource
 
 	ledger            *ledger.Ledger
 	orderMessageFeed  event.Feed
@@ -91,13 +96,27 @@ func (swarm *Swarm) init() error {
 		multiAddrs[id] = node
 	}
 
-	tpt, err := libp2pcert.New(swarm.repo.Key.Libp2pPrivKey, swarm.repo.Certs)
-	if err != nil {
-		return fmt.Errorf("create transport: %w", err)
-	}
-
 	notifiee := newNotifiee(routers, swarm.logger)
-	gater := newConnectionGater(swarm.logger, swarm.ledger)
+	if swarm.scorer == nil {
+		swarm.scorer = newPeerScorer(swarm.repo.Config.PeerScore)
+	}
+	if swarm.gossip == nil {
+		swarm.gossip = newGossip()
+		swarm.RegisterTopicValidator(BlockTopic, swarm.validateBlockAnnouncement)
+	}
+	gater := newConnectionGater(swarm.logger, swarm.ledger, swarm.scorer)
+	notifiee.isFollower = gater.isFollower
+	if swarm.repo.Config.Cert.Verify {
+		if swarm.revocation == nil {
+			swarm.revocation = newCertRevocation(swarm.logger, swarm.ledger, swarm.repo.Config.RepoRoot, swarm.repo.Config.Cert, swarm.repo.Certs.CACert)
+		}
+		gater.revocation = swarm.revocation
+		tpt, err := newCertTransport(swarm.repo.Key.Libp2pPrivKey, swarm.repo.Certs, swarm.revocation)
+		if err != nil {
+			return err
+		}
+		swarm.transport = tpt
+	}
 
 	opts := []network.Option{
 		network.WithLocalAddr(swarm.repo.NetworkConfig.LocalAddr),
@@ -112,8 +131,8 @@ func (swarm *Swarm) init() error {
 
 	if swarm.repo.Config.Cert.Verify {
 		opts = append(opts,
-			network.WithTransportId(libp2pcert.ID),
-			network.WithTransport(tpt),
+			network.WithTransportId(swarm.transport.protocolID()),
+			network.WithTransport(swarm.transport),
 		)
 	}
 
@@ -140,53 +159,67 @@ func (swarm *Swarm) init() error {
 func (swarm *Swarm) Start() error {
 	swarm.p2p.SetMessageHandler(swarm.handleMessage)
 
+	if err := swarm.startGossip(); err != nil {
+		return fmt.Errorf("start gossip failed: %w", err)
+	}
+
 	if err := swarm.p2p.Start(); err != nil {
 		return fmt.Errorf("start p2p failed: %w", err)
 	}
 
 	for id, addr := range swarm.multiAddrs {
-		go func(id uint64, addr *peer.AddrInfo, ctx context.Context) {
-			if err := retry.Retry(func(attempt uint) error {
-				select {
-				case <-ctx.Done():
-					return nil
-
-				default:
-					// for restart node, after updating the routing table, some nodes may not exist in routing table
-					routers := swarm.notifiee.getPeers()
-					if _, ok := routers[id]; !ok {
-						swarm.logger.Infof("Can't find node %d from routing table, stopping connect", id)
-						return nil
-					}
-					if err := swarm.p2p.Connect(*addr); err != nil {
-						swarm.logger.WithFields(logrus.Fields{
-							"node":  id,
-							"error": err,
-						}).Error("Connect failed")
-						return fmt.Errorf("connect failed: %w", err)
-					}
-
-					swarm.logger.WithFields(logrus.Fields{
-						"node": id,
-					}).Info("Connect successfully")
+		go swarm.connectPeer(id, addr, swarm.ctx)
+	}
 
-					swarm.connectedPeers.Store(id, addr)
+	go swarm.Ping()
 
-					return nil
-				}
-			},
-				strategy.Wait(1*time.Second),
-			); err != nil {
-				swarm.logger.Error(err)
-			}
-		}(id, addr, swarm.ctx)
+	if swarm.nodeEventSource != nil {
+		go swarm.listenNodeEvent()
 	}
 
-	go swarm.Ping()
+	if swarm.revocation != nil {
+		go swarm.checkRevocation()
+	}
 
 	return nil
 }
 
+func (swarm *Swarm) connectPeer(id uint64, addr *peer.AddrInfo, ctx context.Context) {
+	if err := retry.Retry(func(attempt uint) error {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		default:
+			// for restart node, after updating the routing table, some nodes may not exist in routing table
+			routers := swarm.notifiee.getPeers()
+			if _, ok := routers[id]; !ok {
+				swarm.logger.Infof("Can't find node %d from routing table, stopping connect", id)
+				return nil
+			}
+			if err := swarm.p2p.Connect(*addr); err != nil {
+				swarm.logger.WithFields(logrus.Fields{
+					"node":  id,
+					"error": err,
+				}).Error("Connect failed")
+				return fmt.Errorf("connect failed: %w", err)
+			}
+
+			swarm.logger.WithFields(logrus.Fields{
+				"node": id,
+			}).Info("Connect successfully")
+
+			swarm.connectedPeers.Store(id, addr)
+
+			return nil
+		}
+	},
+		strategy.Wait(1*time.Second),
+	); err != nil {
+		swarm.logger.Error(err)
+	}
+}
+
 func (swarm *Swarm) Stop() error {
 	swarm.cancel()
 	return swarm.p2p.Stop()
@@ -212,13 +245,20 @@ func (swarm *Swarm) Ping() {
 				}
 				select {
 				case res := <-pingCh:
+					if res.Error != nil {
+						swarm.ReportPeer(key.(uint64), FailedRequest)
+						return true
+					}
 					fields[fmt.Sprintf("%d", key.(uint64))] = res.RTT
+					swarm.recordPeerRTT(key.(uint64), info.ID.String(), res.RTT)
 				case <-time.After(time.Second * 5):
 					swarm.logger.Errorf("ping to node %d timeout", key.(uint64))
+					swarm.ReportPeer(key.(uint64), FailedRequest)
 				}
 				return true
 			})
 			swarm.logger.WithFields(fields).Info("ping time")
+			swarm.updatePeerScoreMetrics()
 		case pingConfig := <-swarm.pingC:
 			swarm.enablePing = pingConfig.Enable
 			swarm.pingTimeout = pingConfig.Duration
@@ -246,7 +286,11 @@ func (swarm *Swarm) AsyncSend(id orderPeerMgr.KeyType, msg *pb.Message) error {
 	if err != nil {
 		return fmt.Errorf("marshal message error: %w", err)
 	}
-	return swarm.p2p.AsyncSend(addr, data)
+	if err := swarm.p2p.AsyncSend(addr, data); err != nil {
+		swarm.ReportPeer(id.(uint64), FailedRequest)
+		return err
+	}
+	return nil
 }
 
 func (swarm *Swarm) SendWithStream(s network.Stream, msg *pb.Message) error {
@@ -274,11 +318,13 @@ func (swarm *Swarm) Send(id orderPeerMgr.KeyType, msg *pb.Message) (*pb.Message,
 
 	ret, err := swarm.p2p.Send(addr, data)
 	if err != nil {
+		swarm.ReportPeer(id.(uint64), FailedRequest)
 		return nil, fmt.Errorf("sync send: %w", err)
 	}
 
 	m := &pb.Message{}
 	if err := m.Unmarshal(ret); err != nil {
+		swarm.ReportPeer(id.(uint64), InvalidResponse)
 		return nil, fmt.Errorf("unmarshal message error: %w", err)
 	}
 
@@ -411,6 +457,7 @@ func (swarm *Swarm) DelNode(delID uint64) {
 	delete(swarm.routers, delID)
 	delete(swarm.multiAddrs, delID)
 	swarm.connectedPeers.Delete(delID)
+	swarm.removePeerScore(delID)
 
 	// 2. persist routers
 	if err := repo.RewriteNetworkConfig(swarm.repo.Config.RepoRoot, swarm.routers, false); err != nil {
@@ -425,12 +472,18 @@ func (swarm *Swarm) DelNode(delID uint64) {
 
 	// tss
 	if swarm.repo.Config.Tss.EnableTSS && delID != swarm.localID {
-		// 1. delete node
-		needRestartKeyGen, err := swarm.Tss.DeleteTssNodes([]string{strconv.Itoa(int(delID))})
-		if err != nil {
+		// 1. check whether the deleted node holds a share of the key before deleting it
+		isParty := false
+		if info, err := swarm.Tss.GetTssInfo(); err == nil {
+			_, isParty = info.PartiesPkMap[strconv.Itoa(int(delID))]
+		}
+		// 2. delete node
+		if _, err := swarm.Tss.DeleteTssNodes([]string{strconv.Itoa(int(delID))}); err != nil {
 			swarm.logger.Errorf("delete tss node error: %v", err)
 		}
-		if needRestartKeyGen {
+		// 3. reshare the key so that the share of deleted node is useless, keygen if the key can not be reshared.
+		// The key is unchanged if the deleted node holds no share, e.g. it is deleted before joining tss.
+		if isParty {
 			go swarm.tssKeygenReqFeed.Send(&pb.Message{Type: pb.Message_TSS_KEYGEN_REQ})
 		}
 	}
@@ -453,6 +506,7 @@ func (swarm *Swarm) UpdateRouter(vpInfos map[uint64]*pb.VpInfo, isNew bool) bool
 		if _, ok := vpInfos[id]; !ok {
 			delete(swarm.multiAddrs, id)
 			swarm.connectedPeers.Delete(id)
+			swarm.removePeerScore(id)
 		}
 	}
 
@@ -535,22 +589,39 @@ func (swarm *Swarm) PierManager() PierManager {
 func (swarm *Swarm) ReConfig(config interface{}) error {
 	switch cf := config.(type) {
 	case *repo.Config:
+		swarm.scorer.setConfig(cf.PeerScore)
 		swarm.pingC <- &cf.Ping
 	case *repo.NetworkConfig:
-		if err := swarm.Stop(); err != nil {
-			return fmt.Errorf("stop swarm failed: %w", err)
-		}
-		swarm.repo.NetworkConfig = cf
-		if err := swarm.init(); err != nil {
-			return fmt.Errorf("init swarm failed: %w", err)
-		}
-		if err := swarm.Start(); err != nil {
-			return fmt.Errorf("start swarm failed: %w", err)
+		return swarm.restart(func() {
+			swarm.repo.NetworkConfig = cf
+		})
+	case *libp2pcert.Certs:
+		// the rotated certs are only used in the handshakes of new connections,
+		// the existing connections are kept
+		swarm.repo.Certs = cf
+		if swarm.transport != nil {
+			return swarm.transport.setCerts(cf)
 		}
 	}
 	return nil
 }
 
+// restart stops the swarm, applies the update and starts the swarm again with new transport
+func (swarm *Swarm) restart(update func()) error {
+	if err := swarm.Stop(); err != nil {
+		return fmt.Errorf("stop swarm failed: %w", err)
+	}
+	update()
+	swarm.ctx, swarm.cancel = context.WithCancel(context.Background())
+	if err := swarm.init(); err != nil {
+		return fmt.Errorf("init swarm failed: %w", err)
+	}
+	if err := swarm.Start(); err != nil {
+		return fmt.Errorf("start swarm failed: %w", err)
+	}
+	return nil
+}
+
 func (swarm *Swarm) GetLocalID() uint64 {
 	return swarm.localID
 }
@@ -0,0 +1,52 @@
+package peermgr
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/iancoleman/orderedmap"
+	node_mgr "github.com/meshplus/bitxhub-core/node-mgr"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/ledger"
+)
+
+func (swarm *Swarm) ValidatorSet() (map[uint64]*pb.VpInfo, error) {
+	return getValidatorSet(swarm.ledger)
+}
+
+// getValidatorSet reads the available vp nodes from the node manager contract, which are
+// the nodes signing blocks no matter how many peers are configured in network.toml
+func getValidatorSet(lg *ledger.Ledger) (map[uint64]*pb.VpInfo, error) {
+	stateLedger := lg.Copy()
+	ok, data := stateLedger.GetState(constant.NodeManagerContractAddr.Address(), []byte(node_mgr.NodeTypeKey(string(node_mgr.VPNode))))
+	if !ok {
+		return nil, fmt.Errorf("no vp node in node manager")
+	}
+	accounts := orderedmap.New()
+	if err := json.Unmarshal(data, accounts); err != nil {
+		return nil, fmt.Errorf("unmarshal vp node accounts error: %w", err)
+	}
+
+	validators := make(map[uint64]*pb.VpInfo, len(accounts.Keys()))
+	for _, account := range accounts.Keys() {
+		ok, nodeData := stateLedger.GetState(constant.NodeManagerContractAddr.Address(), []byte(node_mgr.NodeKey(account)))
+		if !ok {
+			return nil, fmt.Errorf("vp node %s not exist", account)
+		}
+		node := &node_mgr.Node{}
+		if err := json.Unmarshal(nodeData, node); err != nil {
+			return nil, fmt.Errorf("unmarshal node %s error: %w", account, err)
+		}
+		if !node.IsAvailable() {
+			continue
+		}
+		validators[node.VPNodeId] = &pb.VpInfo{
+			Id:      node.VPNodeId,
+			Pid:     node.Pid,
+			Account: node.Account,
+		}
+	}
+
+	return validators, nil
+}
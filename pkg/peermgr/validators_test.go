@@ -0,0 +1,54 @@
+package peermgr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iancoleman/orderedmap"
+	"github.com/meshplus/bitxhub-core/governance"
+	node_mgr "github.com/meshplus/bitxhub-core/node-mgr"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub/internal/ledger"
+	"github.com/meshplus/bitxhub/internal/ledger/mock_ledger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetValidatorSet(t *testing.T) {
+	nodes := []*node_mgr.Node{
+		{Account: "0x01", NodeType: node_mgr.VPNode, Pid: "pid1", VPNodeId: 1, Status: governance.GovernanceAvailable},
+		{Account: "0x02", NodeType: node_mgr.VPNode, Pid: "pid2", VPNodeId: 2, Status: governance.GovernanceAvailable},
+		{Account: "0x03", NodeType: node_mgr.VPNode, Pid: "pid3", VPNodeId: 3, Status: governance.GovernanceForbidden},
+	}
+	states := make(map[string][]byte)
+	accounts := orderedmap.New()
+	for _, node := range nodes {
+		data, err := json.Marshal(node)
+		require.Nil(t, err)
+		states[node_mgr.NodeKey(node.Account)] = data
+		accounts.Set(node.Account, struct{}{})
+	}
+	data, err := json.Marshal(accounts)
+	require.Nil(t, err)
+
+	mockCtl := gomock.NewController(t)
+	stateLedger := mock_ledger.NewMockStateLedger(mockCtl)
+	stateLedger.EXPECT().Copy().Return(stateLedger).AnyTimes()
+	stateLedger.EXPECT().GetState(gomock.Any(), gomock.Any()).DoAndReturn(func(addr *types.Address, key []byte) (bool, []byte) {
+		v, ok := states[string(key)]
+		return ok, v
+	}).AnyTimes()
+	lg := &ledger.Ledger{StateLedger: stateLedger}
+
+	// no vp node recorded
+	_, err = getValidatorSet(lg)
+	require.NotNil(t, err)
+
+	// unavailable vp nodes are not validators
+	states[node_mgr.NodeTypeKey(string(node_mgr.VPNode))] = data
+	validators, err := getValidatorSet(lg)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(validators))
+	require.Equal(t, "0x02", validators[2].Account)
+	require.Equal(t, "pid1", validators[1].Pid)
+}
@@ -3,6 +3,7 @@ package proof
 import (
 	"bytes"
 	"crypto/sha256"
+	"encoding/asn1"
 	"encoding/json"
 	"fmt"
 	"sync"
@@ -12,12 +13,15 @@ import (
 	ruleMgr "github.com/meshplus/bitxhub-core/rule-mgr"
 	"github.com/meshplus/bitxhub-core/validator"
 	"github.com/meshplus/bitxhub-kit/crypto"
+	"github.com/meshplus/bitxhub-kit/crypto/asym"
 	"github.com/meshplus/bitxhub-kit/crypto/asym/ecdsa"
 	"github.com/meshplus/bitxhub-kit/log"
 	"github.com/meshplus/bitxhub-kit/types"
 	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
 	"github.com/meshplus/bitxhub/internal/ledger"
+	"github.com/meshplus/bitxhub/pkg/crypto/sm2"
 	"github.com/meshplus/bitxhub/pkg/utils"
 	"github.com/sirupsen/logrus"
 )
@@ -35,12 +39,14 @@ type VerifyPool struct {
 	ve        validator.Engine
 	logger    logrus.FieldLogger
 	bitxhubID string
+	verifiers map[string]Verifier // built-in proof verification schemes by rule address
 }
 
 var _ Verify = (*VerifyPool)(nil)
 
+// New creates the verify pool
 func New(ledger *ledger.Ledger, logger logrus.FieldLogger, bxhID, wasmGasLimit uint64) Verify {
-	ve := validator.NewValidationEngine(ledger, &sync.Map{}, log.NewWithModule("validator"), wasmGasLimit)
+	ve := newRuleEngine(ledger, log.NewWithModule("validator"), wasmGasLimit)
 
 	proofPool := &VerifyPool{
 		ledger:    ledger,
@@ -48,6 +54,7 @@ func New(ledger *ledger.Ledger, logger logrus.FieldLogger, bxhID, wasmGasLimit u
 		ve:        ve,
 		bitxhubID: fmt.Sprintf("%d", bxhID),
 	}
+	proofPool.registerSchemeVerifiers()
 
 	return proofPool
 }
@@ -133,17 +140,55 @@ func (pl *VerifyPool) verifyMultiSign(app *appchainMgr.Appchain, ibtp *pb.IBTP,
 	return false, 0, fmt.Errorf("%s: multi signs verify fail, counter: %d", ProofError, counter)
 }
 
+// recoverSignAddress recovers the signer of the signature made by repo.Sign, the 65 bytes
+// signatures are Secp256k1 ones, and others are prefixed with the key type and carry the public key
 func recoverSignAddress(sig, digest []byte) (*types.Address, error) {
-	pubKeyBytes, err := ecdsa.Ecrecover(digest, sig)
-	if err != nil {
-		return nil, fmt.Errorf("recover public key failed: %w", err)
+	if len(sig) == ecdsa.SignatureLength {
+		pubKeyBytes, err := ecdsa.Ecrecover(digest, sig)
+		if err != nil {
+			return nil, fmt.Errorf("recover public key failed: %w", err)
+		}
+		pubkey, err := ecdsa.UnmarshalPublicKey(pubKeyBytes, crypto.Secp256k1)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal public key error: %w", err)
+		}
+
+		return pubkey.Address()
+	}
+	if len(sig) == 0 {
+		return nil, fmt.Errorf("empty signature")
+	}
+
+	var (
+		typ    = crypto.KeyType(sig[0])
+		pubkey crypto.PublicKey
+		err    error
+	)
+	switch typ {
+	case crypto.ECDSA_P256, crypto.ECDSA_P384, crypto.ECDSA_P521:
+		sigStruct := &ecdsa.Sig{}
+		if _, err := asn1.Unmarshal(sig[1:], sigStruct); err != nil {
+			return nil, fmt.Errorf("unmarshal signature error: %w", err)
+		}
+		pubkey, err = ecdsa.UnmarshalPublicKey(sigStruct.Pub, typ)
+	case crypto.SM2:
+		pubkey, err = sm2.RecoverPublicKey(sig[1:])
+	default:
+		return nil, fmt.Errorf("unsupported key type %d of signature", typ)
 	}
-	pubkey, err := ecdsa.UnmarshalPublicKey(pubKeyBytes, crypto.Secp256k1)
 	if err != nil {
 		return nil, fmt.Errorf("unmarshal public key error: %w", err)
 	}
 
-	return pubkey.Address()
+	addr, err := pubkey.Address()
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := asym.Verify(typ, sig[1:], digest, *addr); err != nil || !ok {
+		return nil, fmt.Errorf("verify signature failed: %v", err)
+	}
+
+	return addr, nil
 }
 
 func (pl *VerifyPool) verifyProof(ibtp *pb.IBTP, proof []byte) (bool, uint64, error) {
@@ -161,6 +206,16 @@ func (pl *VerifyPool) verifyProof(ibtp *pb.IBTP, proof []byte) (bool, uint64, er
 	//	return false, 0, fmt.Errorf("%s: check serviceID failed: %w", ProofError, err)
 	//}
 
+	// the proof is checked against the appchain of the service submitting the ibtp, which should be
+	// identified by its id since a BNS name may be bound to another service before the ibtp is executed
+	submitter := ibtp.From
+	if ibtp.Category() != pb.IBTP_REQUEST {
+		submitter = ibtp.To
+	}
+	if contracts.IsBnsName(submitter) {
+		return false, 0, fmt.Errorf("%s: service %s submitting the ibtp is a BNS name", ProofError, submitter)
+	}
+
 	var (
 		bxhID   string
 		chainID string
@@ -168,14 +223,23 @@ func (pl *VerifyPool) verifyProof(ibtp *pb.IBTP, proof []byte) (bool, uint64, er
 
 	if ibtp.Category() == pb.IBTP_REQUEST {
 		bxhID, chainID, _ = ibtp.ParseFrom()
+		// notifications of rollback come from the destination bitxhub
+		if dstBxhID, _, _ := ibtp.ParseTo(); dstBxhID != bxhID && pl.isRelayed(ibtp) {
+			bxhID = dstBxhID
+		}
 	} else {
 		bxhID, chainID, _ = ibtp.ParseTo()
 	}
 
 	if bxhID != pl.bitxhubID {
-		app, err := pl.getAppchain(bxhID)
+		// IBTPs of remote bitxhubs are signed by the relay chain of previous hop
+		hop, err := pl.getNextHop(bxhID)
+		if err != nil {
+			return false, 0, fmt.Errorf("get next hop to bitxhub %s failed: %w", bxhID, err)
+		}
+		app, err := pl.getAppchain(hop)
 		if err != nil {
-			return false, 0, fmt.Errorf("get appchain %s failed: %w", bxhID, err)
+			return false, 0, fmt.Errorf("get appchain %s failed: %w", hop, err)
 		}
 		return pl.verifyMultiSign(app, ibtp, proof)
 	}
@@ -190,6 +254,11 @@ func (pl *VerifyPool) verifyProof(ibtp *pb.IBTP, proof []byte) (bool, uint64, er
 		return false, 0, fmt.Errorf("get validate address of chain %s failed: %w", chainID, err)
 	}
 
+	// trusted appchains binding a built-in scheme skip running the WASM rule
+	if verifier, ok := pl.verifiers[validateAddr]; ok {
+		return verifier.Verify(app, ibtp, proof)
+	}
+
 	ibtpBytes, err := ibtp.Marshal()
 	if err != nil {
 		return false, 0, fmt.Errorf("marshal ibtp: %w", err)
@@ -234,6 +303,29 @@ func (pl *VerifyPool) getValidateAddress(chainID string) (string, error) {
 	return "", fmt.Errorf("%s for chainID %s", NoBindRule, chainID)
 }
 
+// getNextHop returns the neighbour relay chain on the way to the bitxhub
+func (pl *VerifyPool) getNextHop(bxhID string) (string, error) {
+	ok, data := pl.getAccountState(constant.InterchainContractAddr, contracts.ROUTE_TABLE_KEY)
+	if !ok {
+		return bxhID, nil
+	}
+
+	routes := make(map[string]string)
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return "", fmt.Errorf("%s: unmarshal route table error: %w", internalError, err)
+	}
+	if hop, ok := routes[bxhID]; ok {
+		return hop, nil
+	}
+	return bxhID, nil
+}
+
+// isRelayed checks whether the inter-bitxhub request has been relayed by current bitxhub
+func (pl *VerifyPool) isRelayed(ibtp *pb.IBTP) bool {
+	ok, _ := pl.getAccountState(constant.InterchainContractAddr, contracts.IndexMapKey(ibtp.ID()))
+	return ok
+}
+
 func (pl *VerifyPool) getAccountState(address constant.BoltContractAddress, key string) (bool, []byte) {
 	return pl.ledger.Copy().GetState(address.Address(), []byte(key))
 }
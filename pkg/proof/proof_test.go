@@ -21,6 +21,7 @@ import (
 	"github.com/meshplus/bitxhub/internal/ledger"
 	"github.com/meshplus/bitxhub/internal/ledger/mock_ledger"
 	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/meshplus/bitxhub/pkg/signer"
 	"github.com/meshplus/bitxhub/pkg/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,6 +48,7 @@ func TestNew(t *testing.T) {
 
 	ve := New(mockLedger, logger, config.ChainID, config.GasLimit)
 	assert.NotNil(t, ve)
+	assert.Equal(t, 3, len(ve.(*VerifyPool).verifiers))
 }
 
 func TestPutProof(t *testing.T) {
@@ -265,7 +267,13 @@ func TestVerifyPool_CheckProof3(t *testing.T) {
 	}
 	mockEngine := mock_validator.NewMockEngine(mockCtl)
 
-	stateLedger.EXPECT().GetState(constant.AppchainMgrContractAddr.Address(), gomock.Any()).Return(true, []byte("123"))
+	// no route to the bitxhub of source chain
+	stateLedger.EXPECT().GetState(gomock.Any(), gomock.Any()).DoAndReturn(func(addr *types.Address, key []byte) (bool, []byte) {
+		if addr.String() == constant.InterchainContractAddr.Address().String() {
+			return false, nil
+		}
+		return true, []byte("123")
+	}).Times(2)
 	stateLedger.EXPECT().Copy().Return(stateLedger).AnyTimes()
 	mockEngine.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, uint64(0), nil).AnyTimes()
 
@@ -291,6 +299,102 @@ func TestVerifyPool_CheckProof3(t *testing.T) {
 	require.False(t, ok)
 }
 
+func TestVerifyPool_CheckProofMultiHop(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	stateLedger := mock_ledger.NewMockStateLedger(mockCtl)
+	mockLedger := &ledger.Ledger{StateLedger: stateLedger}
+	mockEngine := mock_validator.NewMockEngine(mockCtl)
+
+	keys := make([]crypto.PrivateKey, 0, 4)
+	var bv contracts.BxhValidators
+	for i := 0; i < 4; i++ {
+		keyPair, err := asym.GenerateKeyPair(crypto.Secp256k1)
+		require.Nil(t, err)
+		keys = append(keys, keyPair)
+		address, err := keyPair.PublicKey().Address()
+		require.Nil(t, err)
+		bv.Addresses = append(bv.Addresses, address.String())
+	}
+	addrsData, err := json.Marshal(bv)
+	require.Nil(t, err)
+
+	// the IBTP from bxh1 is relayed by bxh2
+	hop := &appchainMgr.Appchain{ID: "bxh2", Status: governance.GovernanceAvailable, TrustRoot: addrsData}
+	hopData, err := json.Marshal(hop)
+	require.Nil(t, err)
+	routesData, err := json.Marshal(map[string]string{"bxh1": "bxh2"})
+	require.Nil(t, err)
+
+	stateLedger.EXPECT().Copy().Return(stateLedger).AnyTimes()
+	stateLedger.EXPECT().GetState(gomock.Any(), gomock.Any()).DoAndReturn(func(addr *types.Address, key []byte) (bool, []byte) {
+		switch {
+		case addr.String() == constant.InterchainContractAddr.Address().String() && string(key) == contracts.ROUTE_TABLE_KEY:
+			return true, routesData
+		case addr.String() == constant.AppchainMgrContractAddr.Address().String() && string(key) == appchainMgr.AppchainKey("bxh2"):
+			return true, hopData
+		}
+		return false, nil
+	}).AnyTimes()
+
+	vp := &VerifyPool{
+		ledger:    mockLedger,
+		ve:        mockEngine,
+		logger:    log.NewWithModule("test_verify"),
+		bitxhubID: "1356",
+	}
+
+	ibtp := getIBTP(t, 1, pb.IBTP_INTERCHAIN, nil)
+	ibtp.From = "bxh1:chain0:0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b997"
+	txStatus := pb.TransactionStatus_BEGIN
+	hash, err := utils.EncodePackedAndHash(ibtp, txStatus)
+	require.Nil(t, err)
+	bxhProof := &pb.BxhProof{TxStatus: txStatus}
+	for _, key := range keys {
+		signData, err := key.Sign(hash[:])
+		require.Nil(t, err)
+		bxhProof.MultiSign = append(bxhProof.MultiSign, signData)
+	}
+	proof, err := bxhProof.Marshal()
+	require.Nil(t, err)
+	proofHash := sha256.Sum256(proof)
+	ibtp.Proof = proofHash[:]
+
+	ok, _, err := vp.verifyProof(ibtp, proof)
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	// bxh3 has no route and is not registered as a neighbour
+	ibtp.From = "bxh3:chain0:0x3f9d18f7c3a6e5e4c0b877fe3e688ab08840b997"
+	ok, _, err = vp.verifyProof(ibtp, proof)
+	require.NotNil(t, err)
+	require.False(t, ok)
+}
+
+func TestVerifyProof_BnsName(t *testing.T) {
+	vp := &VerifyPool{
+		logger:    log.NewWithModule("test_verify"),
+		bitxhubID: "1356",
+	}
+	proof := []byte("proof")
+	proofHash := sha256.Sum256(proof)
+
+	// the service submitting the request is named by BNS
+	ibtp := getIBTP(t, 1, pb.IBTP_INTERCHAIN, proofHash[:])
+	ibtp.From = "src.bank.hub"
+	ok, _, err := vp.verifyProof(ibtp, proof)
+	require.NotNil(t, err)
+	require.True(t, strings.Contains(err.Error(), "BNS name"))
+	require.False(t, ok)
+
+	// the service submitting the receipt is named by BNS
+	ibtp = getIBTP(t, 1, pb.IBTP_RECEIPT_SUCCESS, proofHash[:])
+	ibtp.To = "dst.bank.hub"
+	ok, _, err = vp.verifyProof(ibtp, proof)
+	require.NotNil(t, err)
+	require.True(t, strings.Contains(err.Error(), "BNS name"))
+	require.False(t, ok)
+}
+
 func getIBTP(t *testing.T, index uint64, typ pb.IBTP_Type, proof []byte) *pb.IBTP {
 	ct := &pb.Content{
 		Func: "set",
@@ -329,3 +433,53 @@ func generateMockConfig(t *testing.T) *repo.Config {
 
 	return config
 }
+
+func TestVerifyPool_VerifyMultiSignWithKeyTypes(t *testing.T) {
+	chain := &appchainMgr.Appchain{
+		Status: governance.GovernanceAvailable,
+		ID:     from,
+	}
+
+	ibtp := getIBTP(t, 1, pb.IBTP_RECEIPT_SUCCESS, nil)
+	txStatus := pb.TransactionStatus_SUCCESS
+	hash, err := utils.EncodePackedAndHash(ibtp, txStatus)
+	require.Nil(t, err)
+
+	var bv contracts.BxhValidators
+	bxhProof := &pb.BxhProof{TxStatus: txStatus}
+	for _, typ := range []crypto.KeyType{crypto.Secp256k1, crypto.SM2, crypto.ECDSA_P256, crypto.SM2} {
+		key, err := asym.GenerateKeyPair(typ)
+		require.Nil(t, err)
+		address, err := key.PublicKey().Address()
+		require.Nil(t, err)
+		bv.Addresses = append(bv.Addresses, address.String())
+
+		signData, err := repo.Sign(signer.NewLocalSigner(key), hash)
+		require.Nil(t, err)
+		addr, err := recoverSignAddress(signData, hash)
+		require.Nil(t, err)
+		require.Equal(t, address.String(), addr.String())
+
+		ok, err := repo.VerifySign(signData, hash, *address)
+		require.Nil(t, err)
+		require.True(t, ok)
+
+		// signatures of other digests are refused
+		_, err = recoverSignAddress(signData, hash[1:])
+		require.NotNil(t, err)
+
+		bxhProof.MultiSign = append(bxhProof.MultiSign, signData)
+	}
+	chain.TrustRoot, err = json.Marshal(bv)
+	require.Nil(t, err)
+	proof, err := bxhProof.Marshal()
+	require.Nil(t, err)
+
+	vp := &VerifyPool{
+		logger:    log.NewWithModule("test_verify"),
+		bitxhubID: "1356",
+	}
+	ok, _, err := vp.verifyMultiSign(chain, ibtp, proof)
+	require.Nil(t, err)
+	require.True(t, ok)
+}
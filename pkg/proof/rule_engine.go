@@ -0,0 +1,138 @@
+package proof
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/meshplus/bitxhub-core/validator"
+	"github.com/meshplus/bitxhub-core/validator/validatorlib"
+	"github.com/meshplus/bitxhub-core/wasm"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
+	"github.com/meshplus/bitxhub/internal/ledger"
+	"github.com/meshplus/bitxhub/pkg/vm/wasm/vmledger"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ validator.Engine           = (*ruleEngine)(nil)
+	_ contracts.ValidatorEvictor = (*ruleEngine)(nil)
+	_ validator.Engine           = (*wasmRule)(nil)
+)
+
+// ruleEngine keeps a validation engine for every rule address. The built-in rules are validated by
+// the validation engine of bitxhub-core, and the wasm rules by wasmRule which caches the rule code,
+// so the engine is dropped once the code of the rule is upgraded
+type ruleEngine struct {
+	ledger   *ledger.Ledger
+	logger   logrus.FieldLogger
+	gasLimit uint64
+
+	engines sync.Map // rule address -> validator.Engine
+}
+
+func newRuleEngine(ledger *ledger.Ledger, logger logrus.FieldLogger, gasLimit uint64) *ruleEngine {
+	return &ruleEngine{
+		ledger:   ledger,
+		logger:   logger,
+		gasLimit: gasLimit,
+	}
+}
+
+func (e *ruleEngine) Validate(address, from string, proof, payload []byte, validators string) (bool, uint64, error) {
+	return e.getEngine(address).Validate(address, from, proof, payload, validators)
+}
+
+// Evict drops the cached validators of the rule, the validators running now are not affected
+// and the following validations instantiate the current code of the rule
+func (e *ruleEngine) Evict(address string) {
+	e.engines.Delete(address)
+}
+
+func (e *ruleEngine) getEngine(address string) validator.Engine {
+	if engine, ok := e.engines.Load(address); ok {
+		return engine.(validator.Engine)
+	}
+
+	var engine validator.Engine
+	switch address {
+	case validator.FabricRuleAddr, validator.SimFabricRuleAddr, validator.HappyRuleAddr:
+		engine = validator.NewValidationEngine(e.ledger, &sync.Map{}, e.logger, e.gasLimit)
+	default:
+		engine = &wasmRule{ledger: e.ledger, gasLimit: e.gasLimit}
+	}
+	actual, _ := e.engines.LoadOrStore(address, engine)
+	return actual.(validator.Engine)
+}
+
+// wasmRule validates proofs with the wasm rule, which imports the validator libs of bitxhub-core
+// and the host functions of vmledger available to rules. A new instance is created for every
+// validation, since the host functions read arguments from the context the instance is created
+// with, which is replaced by the instance once it is executed.
+type wasmRule struct {
+	ledger   *ledger.Ledger
+	gasLimit uint64
+
+	codeMu sync.Mutex
+	code   []byte
+}
+
+func (r *wasmRule) Validate(address, from string, proof, payload []byte, validators string) (bool, uint64, error) {
+	code, err := r.getCode(address)
+	if err != nil {
+		return false, 0, err
+	}
+
+	context := make(map[string]interface{})
+	store := wasm.NewStore()
+	libs := append(validatorlib.NewValidatorLibs(context), vmledger.ImportRuleLib(context, store)...)
+	instance, err := wasm.NewWithStore(code, context, libs, store)
+	if err != nil {
+		return false, 0, fmt.Errorf("instantiate rule %s: %w", address, err)
+	}
+	instance.SetContext(vmledger.LEDGER, r.ledger)
+
+	input, err := (&pb.InvokePayload{
+		Method: "start_verify",
+		Args:   []*pb.Arg{pb.Bytes(proof), pb.Bytes([]byte(validators)), pb.Bytes(payload)},
+	}).Marshal()
+	if err != nil {
+		return false, 0, err
+	}
+
+	ret, gasUsed, err := instance.Execute(input, r.gasLimit)
+	if err != nil {
+		return false, gasUsed, err
+	}
+
+	result, err := strconv.Atoi(string(ret))
+	if err != nil {
+		return false, gasUsed, err
+	}
+
+	return result != 0, gasUsed, nil
+}
+
+func (r *wasmRule) getCode(address string) ([]byte, error) {
+	r.codeMu.Lock()
+	defer r.codeMu.Unlock()
+
+	if r.code != nil {
+		return r.code, nil
+	}
+
+	contractByte := r.ledger.GetCode(types.NewAddressByStr(address))
+	if contractByte == nil {
+		return nil, fmt.Errorf("this rule address %s does not exist", address)
+	}
+	contract := &wasm.Contract{}
+	if err := json.Unmarshal(contractByte, contract); err != nil {
+		return nil, fmt.Errorf("contract byte not correct")
+	}
+	r.code = contract.Code
+
+	return r.code, nil
+}
@@ -0,0 +1,84 @@
+package proof
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/bytecodealliance/wasmtime-go"
+	"github.com/golang/mock/gomock"
+	"github.com/meshplus/bitxhub-core/wasm"
+	"github.com/meshplus/bitxhub-kit/log"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub/internal/ledger"
+	"github.com/meshplus/bitxhub/internal/ledger/mock_ledger"
+	"github.com/stretchr/testify/require"
+)
+
+// hostRuleWat verifies the ed25519 signature of the payload, the proof is the signature
+// and the validators is the public key
+const hostRuleWat = `
+(module
+  (import "env" "verify_ed25519" (func $verify_ed25519 (param i32 i32 i32) (result i32)))
+  (memory (export "memory") 1)
+  (global $heap (mut i32) (i32.const 1024))
+  (func (export "allocate") (param $size i32) (result i32)
+    (local $ptr i32)
+    (local.set $ptr (global.get $heap))
+    (global.set $heap (i32.add (global.get $heap) (local.get $size)))
+    (local.get $ptr))
+  (func (export "deallocate") (param i32 i32))
+  (func (export "start_verify") (param $proof i32) (param $validators i32) (param $payload i32) (result i32)
+    (call $verify_ed25519 (local.get $proof) (local.get $payload) (local.get $validators))))
+`
+
+func TestRuleEngine_Evict(t *testing.T) {
+	addr := types.NewAddress([]byte{1}).String()
+	other := types.NewAddress([]byte{2}).String()
+	engine := newRuleEngine(nil, log.NewWithModule("validator"), 0)
+
+	// the engine caching validators is kept for the rule until it is evicted
+	first := engine.getEngine(addr)
+	require.True(t, first == engine.getEngine(addr))
+	otherEngine := engine.getEngine(other)
+
+	engine.Evict(addr)
+	require.True(t, first != engine.getEngine(addr))
+	require.True(t, otherEngine == engine.getEngine(other))
+}
+
+func TestRuleEngine_HostLib(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	stateLedger := mock_ledger.NewMockStateLedger(mockCtl)
+	engine := newRuleEngine(&ledger.Ledger{StateLedger: stateLedger}, log.NewWithModule("validator"), wasmGasLimit)
+
+	code, err := wasmtime.Wat2Wasm(hostRuleWat)
+	require.Nil(t, err)
+	contractData, err := json.Marshal(&wasm.Contract{Code: code})
+	require.Nil(t, err)
+	addr := types.NewAddress([]byte{3}).String()
+	stateLedger.EXPECT().GetCode(gomock.Any()).Return(contractData).Times(1)
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	payload := []byte("ibtp")
+	sig := ed25519.Sign(priv, payload)
+
+	// the code is loaded once and every validation runs on a new instance
+	for i := 0; i < 2; i++ {
+		ok, gasUsed, err := engine.Validate(addr, "", sig, payload, string(pub))
+		require.Nil(t, err)
+		require.True(t, ok)
+		require.True(t, gasUsed > 0)
+	}
+
+	ok, _, err := engine.Validate(addr, "", sig, []byte("another"), string(pub))
+	require.Nil(t, err)
+	require.False(t, ok)
+
+	// the rule not deployed fails
+	stateLedger.EXPECT().GetCode(gomock.Any()).Return(nil).Times(1)
+	_, _, err = engine.Validate(types.NewAddress([]byte{4}).String(), "", sig, payload, string(pub))
+	require.NotNil(t, err)
+}
@@ -0,0 +1,68 @@
+package proof
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	appchainMgr "github.com/meshplus/bitxhub-core/appchain-mgr"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
+)
+
+// Verifier verifies ibtp proofs of appchains binding a built-in proof verification scheme,
+// the gas used is accounted by the scheme itself
+type Verifier interface {
+	Verify(app *appchainMgr.Appchain, ibtp *pb.IBTP, proof []byte) (bool, uint64, error)
+}
+
+// ReceiptProver checks receipt proofs against the block headers of the appchain
+type ReceiptProver interface {
+	VerifyProof(receipt *ethtypes.Receipt, proof []byte) error
+}
+
+// MultiSigTrustRoot is the trust root of appchains verified by multi signature schemes,
+// PoPs are the proofs of possession of the public keys which are required by aggregated schemes
+type MultiSigTrustRoot struct {
+	PubKeys   [][]byte `json:"pub_keys"`
+	PoPs      [][]byte `json:"pops,omitempty"`
+	Threshold int      `json:"threshold"`
+}
+
+// RegisterVerifier binds the verifier to the rule address
+func (pl *VerifyPool) RegisterVerifier(ruleAddr string, verifier Verifier) {
+	if pl.verifiers == nil {
+		pl.verifiers = make(map[string]Verifier)
+	}
+	pl.verifiers[ruleAddr] = verifier
+}
+
+func (pl *VerifyPool) registerSchemeVerifiers() {
+	pl.RegisterVerifier(contracts.BLSAggregateRuleAddr, &BLSAggregateVerifier{})
+	pl.RegisterVerifier(contracts.Ed25519MultiSigRuleAddr, &Ed25519MultiSigVerifier{})
+	pl.RegisterVerifier(contracts.MPTReceiptRuleAddr, NewReceiptProofVerifier(&ledgerReceiptProver{pl: pl}))
+}
+
+// ibtpDigest is the message signed by appchain validators, which is the hash of ibtp without proof hash
+func ibtpDigest(ibtp *pb.IBTP) ([]byte, error) {
+	unsigned := *ibtp
+	unsigned.Proof = nil
+	data, err := unsigned.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("marshal ibtp: %w", err)
+	}
+	digest := sha256.Sum256(data)
+	return digest[:], nil
+}
+
+func unmarshalMultiSigTrustRoot(app *appchainMgr.Appchain) (*MultiSigTrustRoot, error) {
+	root := &MultiSigTrustRoot{}
+	if err := json.Unmarshal(app.TrustRoot, root); err != nil {
+		return nil, fmt.Errorf("%s: unmarshal trustRoot of appchain %s error: %w", ProofError, app.ID, err)
+	}
+	if root.Threshold <= 0 || root.Threshold > len(root.PubKeys) {
+		return nil, fmt.Errorf("%s: invalid threshold %d of %d public keys in appchain %s", ProofError, root.Threshold, len(root.PubKeys), app.ID)
+	}
+	return root, nil
+}
@@ -0,0 +1,136 @@
+package proof
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+	appchainMgr "github.com/meshplus/bitxhub-core/appchain-mgr"
+	"github.com/meshplus/bitxhub-model/pb"
+)
+
+const (
+	GasBLSBase       uint64 = 65000
+	GasBLSPerPairing uint64 = 43000
+	GasBLSPerPubKey  uint64 = 500
+
+	// blsPoPDomain separates the messages signed as proofs of possession from the ibtp digests
+	blsPoPDomain = "BITXHUB_BLS_POP_"
+)
+
+// BLSAggregateProof carries the aggregated signature of appchain validators on the ibtp digest,
+// public keys are uncompressed G1 points and the signature is an uncompressed G2 point
+type BLSAggregateProof struct {
+	Signers   []int  `json:"signers"`
+	Signature []byte `json:"signature"`
+}
+
+// BLSAggregateVerifier requires an aggregated signature of at least threshold distinct validators.
+// Every public key of the trust root must come with its proof of possession, which is the signature
+// of the key on itself, otherwise a rogue key could cancel out the keys of honest validators.
+type BLSAggregateVerifier struct{}
+
+var _ Verifier = (*BLSAggregateVerifier)(nil)
+
+func (v *BLSAggregateVerifier) Verify(app *appchainMgr.Appchain, ibtp *pb.IBTP, proof []byte) (bool, uint64, error) {
+	gasUsed := GasBLSBase
+	root, err := unmarshalMultiSigTrustRoot(app)
+	if err != nil {
+		return false, gasUsed, err
+	}
+
+	if len(root.PoPs) != len(root.PubKeys) {
+		return false, gasUsed, fmt.Errorf("%s: %d proofs of possession for %d bls public keys in appchain %s", ProofError, len(root.PoPs), len(root.PubKeys), app.ID)
+	}
+
+	aggProof := &BLSAggregateProof{}
+	if err := json.Unmarshal(proof, aggProof); err != nil {
+		return false, gasUsed, fmt.Errorf("%s: unmarshal bls proof error: %w", ProofError, err)
+	}
+
+	g1 := bls12381.NewG1()
+	aggPubKey := g1.Zero()
+	signed := make(map[int]struct{}, len(aggProof.Signers))
+	for _, index := range aggProof.Signers {
+		if index < 0 || index >= len(root.PubKeys) {
+			return false, gasUsed, fmt.Errorf("%s: signer index %d out of range", ProofError, index)
+		}
+		if _, ok := signed[index]; ok {
+			return false, gasUsed, fmt.Errorf("%s: duplicate signer %d", ProofError, index)
+		}
+		pubKey, err := g1.FromBytes(root.PubKeys[index])
+		if err != nil || !g1.InCorrectSubgroup(pubKey) {
+			return false, gasUsed, fmt.Errorf("%s: invalid bls public key of signer %d", ProofError, index)
+		}
+		gasUsed += GasBLSPerPubKey + 2*GasBLSPerPairing
+		if err := verifyBLSPoP(g1, pubKey, root.PubKeys[index], root.PoPs[index]); err != nil {
+			return false, gasUsed, fmt.Errorf("%s: signer %d: %w", ProofError, index, err)
+		}
+		g1.Add(aggPubKey, aggPubKey, pubKey)
+		signed[index] = struct{}{}
+	}
+	if len(signed) < root.Threshold {
+		return false, gasUsed, fmt.Errorf("%s: bls aggregated signers %d less than threshold %d", ProofError, len(signed), root.Threshold)
+	}
+
+	g2 := bls12381.NewG2()
+	sig, err := g2.FromBytes(aggProof.Signature)
+	if err != nil || !g2.InCorrectSubgroup(sig) {
+		return false, gasUsed, fmt.Errorf("%s: invalid bls signature", ProofError)
+	}
+
+	digest, err := ibtpDigest(ibtp)
+	if err != nil {
+		return false, gasUsed, err
+	}
+	msg, err := hashToG2(g2, digest)
+	if err != nil {
+		return false, gasUsed, fmt.Errorf("%s: hash to curve error: %w", ProofError, err)
+	}
+
+	// e(pk, H(m)) == e(g1, sig)
+	gasUsed += 2 * GasBLSPerPairing
+	engine := bls12381.NewPairingEngine()
+	engine.AddPair(aggPubKey, msg).AddPairInv(g1.One(), sig)
+	if !engine.Check() {
+		return false, gasUsed, fmt.Errorf("%s: bls aggregated signature verify fail", ProofError)
+	}
+
+	return true, gasUsed, nil
+}
+
+// verifyBLSPoP checks the proof of possession of the public key, e(pk, H(domain || pk)) == e(g1, pop)
+func verifyBLSPoP(g1 *bls12381.G1, pubKey *bls12381.PointG1, pubKeyData, pop []byte) error {
+	g2 := bls12381.NewG2()
+	sig, err := g2.FromBytes(pop)
+	if err != nil || !g2.InCorrectSubgroup(sig) {
+		return fmt.Errorf("invalid proof of possession")
+	}
+	msg, err := hashToG2(g2, blsPoPDigest(pubKeyData))
+	if err != nil {
+		return fmt.Errorf("hash to curve error: %w", err)
+	}
+	engine := bls12381.NewPairingEngine()
+	engine.AddPair(pubKey, msg).AddPairInv(g1.One(), sig)
+	if !engine.Check() {
+		return fmt.Errorf("proof of possession verify fail")
+	}
+	return nil
+}
+
+func blsPoPDigest(pubKey []byte) []byte {
+	digest := sha256.Sum256(append([]byte(blsPoPDomain), pubKey...))
+	return digest[:]
+}
+
+// hashToG2 maps the digest to a G2 point, the field element is made of two sha256
+// expansions of the digest which are always less than the modulus
+func hashToG2(g2 *bls12381.G2, digest []byte) (*bls12381.PointG2, error) {
+	fe := make([]byte, 96)
+	for i := 0; i < 2; i++ {
+		h := sha256.Sum256(append([]byte{byte(i)}, digest...))
+		copy(fe[48*i+16:48*(i+1)], h[:])
+	}
+	return g2.MapToCurve(fe)
+}
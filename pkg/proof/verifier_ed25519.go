@@ -0,0 +1,74 @@
+package proof
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+
+	appchainMgr "github.com/meshplus/bitxhub-core/appchain-mgr"
+	"github.com/meshplus/bitxhub-model/pb"
+)
+
+const (
+	GasEd25519Base   uint64 = 3000
+	GasEd25519PerSig uint64 = 2000
+)
+
+// Ed25519MultiSigProof carries the signatures of appchain validators on the ibtp digest,
+// Index is the position of the signer in the public keys of trust root
+type Ed25519MultiSigProof struct {
+	Signatures []IndexedSignature `json:"signatures"`
+}
+
+type IndexedSignature struct {
+	Index     int    `json:"index"`
+	Signature []byte `json:"signature"`
+}
+
+// Ed25519MultiSigVerifier requires signatures of at least threshold distinct validators
+type Ed25519MultiSigVerifier struct{}
+
+var _ Verifier = (*Ed25519MultiSigVerifier)(nil)
+
+func (v *Ed25519MultiSigVerifier) Verify(app *appchainMgr.Appchain, ibtp *pb.IBTP, proof []byte) (bool, uint64, error) {
+	gasUsed := GasEd25519Base
+	root, err := unmarshalMultiSigTrustRoot(app)
+	if err != nil {
+		return false, gasUsed, err
+	}
+
+	multiSig := &Ed25519MultiSigProof{}
+	if err := json.Unmarshal(proof, multiSig); err != nil {
+		return false, gasUsed, fmt.Errorf("%s: unmarshal ed25519 proof error: %w", ProofError, err)
+	}
+
+	digest, err := ibtpDigest(ibtp)
+	if err != nil {
+		return false, gasUsed, err
+	}
+
+	signed := make(map[int]struct{}, len(multiSig.Signatures))
+	for _, sig := range multiSig.Signatures {
+		if sig.Index < 0 || sig.Index >= len(root.PubKeys) {
+			return false, gasUsed, fmt.Errorf("%s: signer index %d out of range", ProofError, sig.Index)
+		}
+		if _, ok := signed[sig.Index]; ok {
+			return false, gasUsed, fmt.Errorf("%s: duplicate signature of signer %d", ProofError, sig.Index)
+		}
+		pubKey := root.PubKeys[sig.Index]
+		if len(pubKey) != ed25519.PublicKeySize {
+			return false, gasUsed, fmt.Errorf("%s: invalid ed25519 public key of signer %d", ProofError, sig.Index)
+		}
+
+		gasUsed += GasEd25519PerSig
+		if !ed25519.Verify(pubKey, digest, sig.Signature) {
+			return false, gasUsed, fmt.Errorf("%s: invalid signature of signer %d", ProofError, sig.Index)
+		}
+		signed[sig.Index] = struct{}{}
+		if len(signed) >= root.Threshold {
+			return true, gasUsed, nil
+		}
+	}
+
+	return false, gasUsed, fmt.Errorf("%s: ed25519 multi signs verify fail, counter: %d", ProofError, len(signed))
+}
@@ -0,0 +1,181 @@
+package proof
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	appchainMgr "github.com/meshplus/bitxhub-core/appchain-mgr"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
+	"github.com/meshplus/bitxhub/internal/executor/oracle/appchain"
+)
+
+const (
+	GasReceiptBase    uint64 = 5000
+	GasReceiptPerByte uint64 = 16
+)
+
+// ReceiptProof is the receipt of the appchain transaction which emits the ibtp
+// and its Merkle-Patricia proof in the receipt trie of the block
+type ReceiptProof struct {
+	Receipt *ethtypes.Receipt `json:"receipt"`
+	Proof   []byte            `json:"proof"`
+}
+
+// ibtpLogArgs are the non-indexed arguments of the ibtp event emitted by the appchain broker,
+// which is throwEvent(bytes32 indexed id, string from, string to, uint64 index, uint8 typ, bytes payload)
+var ibtpLogArgs = func() abi.Arguments {
+	newType := func(t string) abi.Type {
+		typ, _ := abi.NewType(t, "", nil)
+		return typ
+	}
+	return abi.Arguments{
+		{Name: "from", Type: newType("string")},
+		{Name: "to", Type: newType("string")},
+		{Name: "index", Type: newType("uint64")},
+		{Name: "typ", Type: newType("uint8")},
+		{Name: "payload", Type: newType("bytes")},
+	}
+}()
+
+// ibtpLog is the ibtp decoded from the log data of the appchain broker
+type ibtpLog struct {
+	From    string
+	To      string
+	Index   uint64
+	Typ     uint8
+	Payload []byte
+}
+
+// ReceiptProofVerifier verifies the receipt proof against the block headers of the appchain,
+// the receipt should contain a log of the appchain broker with the keccak256 hash of ibtp id as topic and
+// the ibtp fields as data, so that the proved receipt is bound to the content of the ibtp rather than its id
+type ReceiptProofVerifier struct {
+	prover ReceiptProver
+}
+
+var _ Verifier = (*ReceiptProofVerifier)(nil)
+
+func NewReceiptProofVerifier(prover ReceiptProver) *ReceiptProofVerifier {
+	return &ReceiptProofVerifier{prover: prover}
+}
+
+func (v *ReceiptProofVerifier) Verify(app *appchainMgr.Appchain, ibtp *pb.IBTP, proof []byte) (bool, uint64, error) {
+	gasUsed := GasReceiptBase + uint64(len(proof))*GasReceiptPerByte
+
+	receiptProof := &ReceiptProof{}
+	if err := json.Unmarshal(proof, receiptProof); err != nil {
+		return false, gasUsed, fmt.Errorf("%s: unmarshal receipt proof error: %w", ProofError, err)
+	}
+	if receiptProof.Receipt == nil {
+		return false, gasUsed, fmt.Errorf("%s: empty receipt", ProofError)
+	}
+
+	if !common.IsHexAddress(string(app.Broker)) {
+		return false, gasUsed, fmt.Errorf("%s: invalid broker %s of appchain %s", ProofError, string(app.Broker), app.ID)
+	}
+	broker := common.HexToAddress(string(app.Broker))
+	topic := crypto.Keccak256Hash([]byte(ibtp.ID()))
+	l := findIBTPLog(receiptProof.Receipt, broker, topic)
+	if l == nil {
+		return false, gasUsed, fmt.Errorf("%s: receipt %s has no log of ibtp %s", ProofError, receiptProof.Receipt.TxHash.String(), ibtp.ID())
+	}
+	if err := checkIBTPLog(l, ibtp); err != nil {
+		return false, gasUsed, fmt.Errorf("%s: log of ibtp %s mismatches: %w", ProofError, ibtp.ID(), err)
+	}
+
+	if err := v.prover.VerifyProof(receiptProof.Receipt, receiptProof.Proof); err != nil {
+		return false, gasUsed, fmt.Errorf("%s: %w", ProofError, err)
+	}
+
+	return true, gasUsed, nil
+}
+
+func findIBTPLog(receipt *ethtypes.Receipt, broker common.Address, topic common.Hash) *ethtypes.Log {
+	for _, l := range receipt.Logs {
+		if l.Address != broker {
+			continue
+		}
+		for _, t := range l.Topics {
+			if t == topic {
+				return l
+			}
+		}
+	}
+	return nil
+}
+
+// checkIBTPLog decodes the log data and compares it with the fields of the ibtp,
+// the proof of ibtp is bound by its hash which is checked before the verifier runs
+func checkIBTPLog(l *ethtypes.Log, ibtp *pb.IBTP) error {
+	values, err := ibtpLogArgs.Unpack(l.Data)
+	if err != nil {
+		return fmt.Errorf("unpack log data: %w", err)
+	}
+	decoded := &ibtpLog{}
+	if err := ibtpLogArgs.Copy(decoded, values); err != nil {
+		return fmt.Errorf("copy log data: %w", err)
+	}
+
+	switch {
+	case decoded.From != ibtp.From:
+		return fmt.Errorf("from %s, expected %s", decoded.From, ibtp.From)
+	case decoded.To != ibtp.To:
+		return fmt.Errorf("to %s, expected %s", decoded.To, ibtp.To)
+	case decoded.Index != ibtp.Index:
+		return fmt.Errorf("index %d, expected %d", decoded.Index, ibtp.Index)
+	case pb.IBTP_Type(decoded.Typ) != ibtp.Type:
+		return fmt.Errorf("type %d, expected %d", decoded.Typ, ibtp.Type)
+	case !bytes.Equal(decoded.Payload, ibtp.Payload):
+		return fmt.Errorf("payload differs")
+	}
+	return nil
+}
+
+// ledgerReceiptProver checks receipt proofs against the ethereum headers which the eth header manager
+// records in the ledger once the light client oracle accepts them, so every node gets the same result
+// whether it runs the oracle or not
+type ledgerReceiptProver struct {
+	pl *VerifyPool
+}
+
+var _ ReceiptProver = (*ledgerReceiptProver)(nil)
+
+func (p *ledgerReceiptProver) VerifyProof(receipt *ethtypes.Receipt, proof []byte) error {
+	if receipt.Status == ethtypes.ReceiptStatusFailed {
+		return fmt.Errorf("receipt %s is failed", receipt.TxHash.String())
+	}
+
+	hash := receipt.BlockHash.String()
+	header := &contracts.EthHeaderRecord{}
+	if err := p.getObject(contracts.EthHeaderKey(hash), header); err != nil {
+		return fmt.Errorf("header %s: %w", hash, err)
+	}
+	var canonical string
+	if err := p.getObject(contracts.EthCanonicalKey(header.Number), &canonical); err != nil || canonical != hash {
+		return fmt.Errorf("header %s is not on the canonical chain", hash)
+	}
+	var head uint64
+	if err := p.getObject(contracts.EthHeadKey, &head); err != nil {
+		return fmt.Errorf("head header: %w", err)
+	}
+	if head < header.Number || head-header.Number < appchain.MinConfirmNum {
+		return fmt.Errorf("header %s is not enough confirmed", hash)
+	}
+
+	return appchain.VerifyReceiptProof(header.ReceiptHash, receipt, proof)
+}
+
+func (p *ledgerReceiptProver) getObject(key string, ret interface{}) error {
+	ok, data := p.pl.getAccountState(constant.EthHeaderMgrContractAddr, key)
+	if !ok {
+		return fmt.Errorf("not found in ledger")
+	}
+	return json.Unmarshal(data, ret)
+}
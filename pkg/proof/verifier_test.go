@@ -0,0 +1,346 @@
+package proof
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/golang/mock/gomock"
+	appchainMgr "github.com/meshplus/bitxhub-core/appchain-mgr"
+	"github.com/meshplus/bitxhub-core/governance"
+	ruleMgr "github.com/meshplus/bitxhub-core/rule-mgr"
+	"github.com/meshplus/bitxhub-core/validator/mock_validator"
+	"github.com/meshplus/bitxhub-kit/log"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/executor/contracts"
+	"github.com/meshplus/bitxhub/internal/executor/oracle/appchain"
+	"github.com/meshplus/bitxhub/internal/ledger"
+	"github.com/meshplus/bitxhub/internal/ledger/mock_ledger"
+	"github.com/stretchr/testify/require"
+)
+
+const brokerAddr = "0x857133c5C69e6Ce66F7AD46F200B9B3573e77582"
+
+type mockProver struct {
+	err error
+}
+
+func (p *mockProver) VerifyProof(receipt *ethtypes.Receipt, proof []byte) error {
+	return p.err
+}
+
+func ed25519TrustRoot(t *testing.T, n, threshold int) ([]ed25519.PrivateKey, []byte) {
+	keys := make([]ed25519.PrivateKey, 0, n)
+	root := MultiSigTrustRoot{Threshold: threshold}
+	for i := 0; i < n; i++ {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		require.Nil(t, err)
+		keys = append(keys, priv)
+		root.PubKeys = append(root.PubKeys, pub)
+	}
+	data, err := json.Marshal(root)
+	require.Nil(t, err)
+	return keys, data
+}
+
+func ed25519Proof(t *testing.T, keys []ed25519.PrivateKey, ibtp *pb.IBTP, signers ...int) []byte {
+	digest, err := ibtpDigest(ibtp)
+	require.Nil(t, err)
+	multiSig := Ed25519MultiSigProof{}
+	for _, i := range signers {
+		multiSig.Signatures = append(multiSig.Signatures, IndexedSignature{Index: i, Signature: ed25519.Sign(keys[i], digest)})
+	}
+	data, err := json.Marshal(multiSig)
+	require.Nil(t, err)
+	return data
+}
+
+func TestEd25519MultiSigVerifier_Verify(t *testing.T) {
+	keys, trustRoot := ed25519TrustRoot(t, 4, 3)
+	app := &appchainMgr.Appchain{ID: "chain0", TrustRoot: trustRoot}
+	ibtp := getIBTP(t, 1, pb.IBTP_INTERCHAIN, nil)
+	v := &Ed25519MultiSigVerifier{}
+
+	ok, gasUsed, err := v.Verify(app, ibtp, ed25519Proof(t, keys, ibtp, 0, 2, 3))
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, GasEd25519Base+3*GasEd25519PerSig, gasUsed)
+
+	// not enough signers
+	ok, _, err = v.Verify(app, ibtp, ed25519Proof(t, keys, ibtp, 0, 2))
+	require.NotNil(t, err)
+	require.False(t, ok)
+
+	// duplicate signer
+	ok, _, err = v.Verify(app, ibtp, ed25519Proof(t, keys, ibtp, 0, 2, 2))
+	require.NotNil(t, err)
+	require.False(t, ok)
+
+	// the proof hash is not signed
+	signed := *ibtp
+	signed.Proof = []byte("proof hash")
+	ok, _, err = v.Verify(app, &signed, ed25519Proof(t, keys, ibtp, 0, 1, 2))
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	// signatures of another ibtp
+	ok, _, err = v.Verify(app, getIBTP(t, 2, pb.IBTP_INTERCHAIN, nil), ed25519Proof(t, keys, ibtp, 0, 1, 2))
+	require.NotNil(t, err)
+	require.False(t, ok)
+}
+
+func TestBLSAggregateVerifier_Verify(t *testing.T) {
+	g1 := bls12381.NewG1()
+	g2 := bls12381.NewG2()
+	secrets := make([]*big.Int, 0, 4)
+	root := MultiSigTrustRoot{Threshold: 3}
+	for i := 0; i < 4; i++ {
+		secret, err := rand.Int(rand.Reader, g1.Q())
+		require.Nil(t, err)
+		secrets = append(secrets, secret)
+		pubKey := g1.ToBytes(g1.MulScalar(g1.New(), g1.One(), secret))
+		popMsg, err := hashToG2(g2, blsPoPDigest(pubKey))
+		require.Nil(t, err)
+		root.PubKeys = append(root.PubKeys, pubKey)
+		root.PoPs = append(root.PoPs, g2.ToBytes(g2.MulScalar(g2.New(), popMsg, secret)))
+	}
+	trustRoot, err := json.Marshal(root)
+	require.Nil(t, err)
+	app := &appchainMgr.Appchain{ID: "chain0", TrustRoot: trustRoot}
+	ibtp := getIBTP(t, 1, pb.IBTP_INTERCHAIN, nil)
+
+	aggregate := func(signers ...int) []byte {
+		digest, err := ibtpDigest(ibtp)
+		require.Nil(t, err)
+		msg, err := hashToG2(g2, digest)
+		require.Nil(t, err)
+		sig := g2.Zero()
+		for _, i := range signers {
+			g2.Add(sig, sig, g2.MulScalar(g2.New(), msg, secrets[i]))
+		}
+		data, err := json.Marshal(BLSAggregateProof{Signers: signers, Signature: g2.ToBytes(sig)})
+		require.Nil(t, err)
+		return data
+	}
+
+	v := &BLSAggregateVerifier{}
+	ok, gasUsed, err := v.Verify(app, ibtp, aggregate(0, 1, 3))
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, GasBLSBase+3*(GasBLSPerPubKey+2*GasBLSPerPairing)+2*GasBLSPerPairing, gasUsed)
+
+	// not enough signers
+	ok, _, err = v.Verify(app, ibtp, aggregate(0, 1))
+	require.NotNil(t, err)
+	require.False(t, ok)
+
+	// claimed signers mismatch the aggregated signature
+	proof := &BLSAggregateProof{}
+	require.Nil(t, json.Unmarshal(aggregate(0, 1, 3), proof))
+	proof.Signers = []int{0, 1, 2}
+	data, err := json.Marshal(proof)
+	require.Nil(t, err)
+	ok, _, err = v.Verify(app, ibtp, data)
+	require.NotNil(t, err)
+	require.False(t, ok)
+
+	// keys without proofs of possession are refused
+	rogue := root
+	rogue.PoPs = root.PoPs[:3]
+	rogueRoot, err := json.Marshal(rogue)
+	require.Nil(t, err)
+	ok, _, err = v.Verify(&appchainMgr.Appchain{ID: "chain0", TrustRoot: rogueRoot}, ibtp, aggregate(0, 1, 3))
+	require.NotNil(t, err)
+	require.False(t, ok)
+
+	// proof of possession made by another key
+	rogue.PoPs = [][]byte{root.PoPs[0], root.PoPs[1], root.PoPs[2], root.PoPs[2]}
+	rogueRoot, err = json.Marshal(rogue)
+	require.Nil(t, err)
+	ok, _, err = v.Verify(&appchainMgr.Appchain{ID: "chain0", TrustRoot: rogueRoot}, ibtp, aggregate(0, 1, 3))
+	require.NotNil(t, err)
+	require.False(t, ok)
+}
+
+func TestReceiptProofVerifier_Verify(t *testing.T) {
+	app := &appchainMgr.Appchain{ID: "chain0", Broker: []byte(brokerAddr)}
+	ibtp := getIBTP(t, 1, pb.IBTP_INTERCHAIN, nil)
+
+	logData := func(ibtp *pb.IBTP) []byte {
+		data, err := ibtpLogArgs.Pack(ibtp.From, ibtp.To, ibtp.Index, uint8(ibtp.Type), ibtp.Payload)
+		require.Nil(t, err)
+		return data
+	}
+	receiptProof := func(topic common.Hash, data []byte) []byte {
+		receipt := &ethtypes.Receipt{
+			Status: ethtypes.ReceiptStatusSuccessful,
+			Logs: []*ethtypes.Log{{
+				Address: common.HexToAddress(brokerAddr),
+				Topics:  []common.Hash{crypto.Keccak256Hash([]byte("throwEvent")), topic},
+				Data:    data,
+			}},
+		}
+		data, err := json.Marshal(ReceiptProof{Receipt: receipt, Proof: []byte("proof")})
+		require.Nil(t, err)
+		return data
+	}
+
+	v := NewReceiptProofVerifier(&mockProver{})
+	topic := crypto.Keccak256Hash([]byte(ibtp.ID()))
+	proof := receiptProof(topic, logData(ibtp))
+	ok, gasUsed, err := v.Verify(app, ibtp, proof)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, GasReceiptBase+uint64(len(proof))*GasReceiptPerByte, gasUsed)
+
+	// receipt of another ibtp
+	ok, _, err = v.Verify(app, ibtp, receiptProof(crypto.Keccak256Hash([]byte("another")), logData(ibtp)))
+	require.NotNil(t, err)
+	require.False(t, ok)
+
+	// log of the ibtp id carrying another payload
+	forged := *ibtp
+	forged.Payload = []byte("forged payload")
+	ok, _, err = v.Verify(app, ibtp, receiptProof(topic, logData(&forged)))
+	require.NotNil(t, err)
+	require.False(t, ok)
+
+	// log data which is not an ibtp event
+	ok, _, err = v.Verify(app, ibtp, receiptProof(topic, []byte("data")))
+	require.NotNil(t, err)
+	require.False(t, ok)
+
+	// receipt not proved by stored headers
+	v = NewReceiptProofVerifier(&mockProver{err: fmt.Errorf("not found header")})
+	ok, _, err = v.Verify(app, ibtp, proof)
+	require.NotNil(t, err)
+	require.False(t, ok)
+}
+
+func TestLedgerReceiptProver_VerifyProof(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	stateLedger := mock_ledger.NewMockStateLedger(mockCtl)
+	mockLedger := &ledger.Ledger{StateLedger: stateLedger}
+
+	receipts := ethtypes.Receipts{
+		{Status: ethtypes.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*ethtypes.Log{}},
+		{Status: ethtypes.ReceiptStatusSuccessful, CumulativeGasUsed: 42000, Logs: []*ethtypes.Log{}, TransactionIndex: 1},
+	}
+	receiptsTrie := new(trie.Trie)
+	receiptHash := ethtypes.DeriveSha(receipts, receiptsTrie)
+	key, err := rlp.EncodeToBytes(uint(1))
+	require.Nil(t, err)
+	nodeSet := light.NewNodeSet()
+	require.Nil(t, receiptsTrie.Prove(key, 0, nodeSet))
+	proof, err := rlp.EncodeToBytes(nodeSet.NodeList())
+	require.Nil(t, err)
+
+	blockHash := common.HexToHash("0x01")
+	receipt := receipts[1]
+	receipt.BlockHash = blockHash
+
+	state := make(map[string][]byte)
+	setState := func(key string, value interface{}) {
+		data, err := json.Marshal(value)
+		require.Nil(t, err)
+		state[key] = data
+	}
+	stateLedger.EXPECT().Copy().Return(stateLedger).AnyTimes()
+	stateLedger.EXPECT().GetState(constant.EthHeaderMgrContractAddr.Address(), gomock.Any()).DoAndReturn(func(_ *types.Address, key []byte) (bool, []byte) {
+		data, ok := state[string(key)]
+		return ok, data
+	}).AnyTimes()
+
+	prover := &ledgerReceiptProver{pl: &VerifyPool{ledger: mockLedger}}
+
+	// the header is not recorded on chain
+	require.NotNil(t, prover.VerifyProof(receipt, proof))
+
+	setState(contracts.EthHeaderKey(blockHash.String()), &contracts.EthHeaderRecord{Number: 100, ReceiptHash: receiptHash})
+	setState(contracts.EthCanonicalKey(100), blockHash.String())
+	setState(contracts.EthHeadKey, uint64(100)+appchain.MinConfirmNum-1)
+	require.NotNil(t, prover.VerifyProof(receipt, proof))
+
+	setState(contracts.EthHeadKey, uint64(100)+appchain.MinConfirmNum)
+	require.Nil(t, prover.VerifyProof(receipt, proof))
+
+	// proof of another receipt
+	forged := *receipt
+	forged.CumulativeGasUsed = 1
+	require.NotNil(t, prover.VerifyProof(&forged, proof))
+
+	// the header is replaced by reorg
+	setState(contracts.EthCanonicalKey(100), common.HexToHash("0x02").String())
+	require.NotNil(t, prover.VerifyProof(receipt, proof))
+}
+
+func TestVerifyPool_CheckProofByScheme(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	chainLedger := mock_ledger.NewMockChainLedger(mockCtl)
+	stateLedger := mock_ledger.NewMockStateLedger(mockCtl)
+	mockLedger := &ledger.Ledger{
+		ChainLedger: chainLedger,
+		StateLedger: stateLedger,
+	}
+	mockEngine := mock_validator.NewMockEngine(mockCtl)
+
+	keys, trustRoot := ed25519TrustRoot(t, 4, 3)
+	chainData, err := json.Marshal(&appchainMgr.Appchain{ID: "chain1", TrustRoot: trustRoot})
+	require.Nil(t, err)
+	rlData, err := json.Marshal([]*ruleMgr.Rule{{
+		Address: contracts.Ed25519MultiSigRuleAddr,
+		Status:  governance.GovernanceAvailable,
+	}})
+	require.Nil(t, err)
+
+	stateLedger.EXPECT().Copy().Return(stateLedger).AnyTimes()
+	stateLedger.EXPECT().GetState(gomock.Any(), gomock.Any()).DoAndReturn(func(addr *types.Address, key []byte) (bool, []byte) {
+		switch addr.String() {
+		case constant.AppchainMgrContractAddr.Address().String():
+			return true, chainData
+		case constant.RuleManagerContractAddr.Address().String():
+			return true, rlData
+		}
+		return false, nil
+	}).AnyTimes()
+	// the WASM rule is skipped
+	mockEngine.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	vp := &VerifyPool{
+		ledger:    mockLedger,
+		ve:        mockEngine,
+		logger:    log.NewWithModule("test_verify"),
+		bitxhubID: "1356",
+	}
+	vp.registerSchemeVerifiers()
+
+	ibtp := getIBTP(t, 1, pb.IBTP_RECEIPT_SUCCESS, nil)
+	proof := ed25519Proof(t, keys, ibtp, 0, 1, 2)
+	proofHash := sha256.Sum256(proof)
+	ibtp.Proof = proofHash[:]
+	tx := &pb.BxhTransaction{
+		From:  types.NewAddressByStr(from),
+		To:    types.NewAddressByStr(to),
+		IBTP:  ibtp,
+		Extra: proof,
+	}
+	tx.TransactionHash = tx.Hash()
+
+	ok, gasUsed, err := vp.CheckProof(tx)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, GasEd25519Base+3*GasEd25519PerSig, gasUsed)
+}
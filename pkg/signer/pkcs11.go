@@ -0,0 +1,291 @@
+package signer
+
+import (
+	"bytes"
+	stdecdsa "crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/meshplus/bitxhub-kit/crypto"
+	"github.com/meshplus/bitxhub-kit/crypto/asym/ecdsa"
+	"github.com/miekg/pkcs11"
+)
+
+// OIDs of the named curves in CKA_EC_PARAMS
+var (
+	oidSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+	oidP256      = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidP384      = asn1.ObjectIdentifier{1, 3, 132, 0, 34}
+	oidP521      = asn1.ObjectIdentifier{1, 3, 132, 0, 35}
+)
+
+// PKCS11Config configures the HSM which keeps the key, the key pair is found by the label
+// of the private key and the public key objects on the token
+type PKCS11Config struct {
+	// Lib is the path of the PKCS#11 module, e.g. /usr/lib/softhsm/libsofthsm2.so
+	Lib        string `toml:"lib" json:"lib"`
+	TokenLabel string `mapstructure:"token_label" toml:"token_label" json:"token_label"`
+	// Pin is the user pin of the token
+	Pin      string `toml:"pin" json:"-"`
+	KeyLabel string `mapstructure:"key_label" toml:"key_label" json:"key_label"`
+}
+
+// PKCS11Signer signs with the ECDSA key kept in the HSM, Secp256k1, ECDSA_P256, ECDSA_P384
+// and ECDSA_P521 keys are supported
+type PKCS11Signer struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	privKey pkcs11.ObjectHandle
+	typ     crypto.KeyType
+	pubKey  crypto.PublicKey
+
+	// sign operations of a session are serial
+	lock sync.Mutex
+}
+
+var _ Signer = (*PKCS11Signer)(nil)
+
+// NewPKCS11Signer logins the token and finds the key pair of the key label
+func NewPKCS11Signer(config PKCS11Config) (*PKCS11Signer, error) {
+	if config.Lib == "" {
+		return nil, fmt.Errorf("PKCS#11 module is empty")
+	}
+	if config.KeyLabel == "" {
+		return nil, fmt.Errorf("PKCS#11 key label is empty")
+	}
+
+	ctx := pkcs11.New(config.Lib)
+	if ctx == nil {
+		return nil, fmt.Errorf("load PKCS#11 module %s failed", config.Lib)
+	}
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, fmt.Errorf("initialize PKCS#11 module: %w", err)
+	}
+
+	s := &PKCS11Signer{ctx: ctx}
+	if err := s.open(config); err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *PKCS11Signer) open(config PKCS11Config) error {
+	slot, err := findSlot(s.ctx, config.TokenLabel)
+	if err != nil {
+		return err
+	}
+
+	s.session, err = s.ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return fmt.Errorf("open session: %w", err)
+	}
+	if err := s.ctx.Login(s.session, pkcs11.CKU_USER, config.Pin); err != nil && err != pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+		return fmt.Errorf("login token %s: %w", config.TokenLabel, err)
+	}
+
+	pubHandle, err := s.findKey(pkcs11.CKO_PUBLIC_KEY, config.KeyLabel)
+	if err != nil {
+		return err
+	}
+	s.privKey, err = s.findKey(pkcs11.CKO_PRIVATE_KEY, config.KeyLabel)
+	if err != nil {
+		return err
+	}
+
+	attrs, err := s.ctx.GetAttributeValue(s.session, pubHandle, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, nil),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return fmt.Errorf("get public key of %s: %w", config.KeyLabel, err)
+	}
+	s.typ, s.pubKey, err = parsePKCS11PublicKey(attrs[0].Value, attrs[1].Value)
+	if err != nil {
+		return fmt.Errorf("parse public key of %s: %w", config.KeyLabel, err)
+	}
+
+	return nil
+}
+
+func (s *PKCS11Signer) Bytes() ([]byte, error) {
+	return nil, fmt.Errorf("private key is kept in HSM")
+}
+
+func (s *PKCS11Signer) Type() crypto.KeyType {
+	return s.typ
+}
+
+func (s *PKCS11Signer) PublicKey() crypto.PublicKey {
+	return s.pubKey
+}
+
+func (s *PKCS11Signer) Sign(digest []byte) ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}
+	if err := s.ctx.SignInit(s.session, mechanism, s.privKey); err != nil {
+		return nil, fmt.Errorf("init sign: %w", err)
+	}
+	raw, err := s.ctx.Sign(s.session, digest)
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+	if len(raw) == 0 || len(raw)%2 != 0 {
+		return nil, fmt.Errorf("invalid signature length %d", len(raw))
+	}
+
+	r := new(big.Int).SetBytes(raw[:len(raw)/2])
+	sv := new(big.Int).SetBytes(raw[len(raw)/2:])
+
+	return encodeSignature(s.typ, s.pubKey, digest, r, sv)
+}
+
+func (s *PKCS11Signer) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.ctx == nil {
+		return nil
+	}
+	if s.session != 0 {
+		_ = s.ctx.Logout(s.session)
+		_ = s.ctx.CloseSession(s.session)
+	}
+	err := s.ctx.Finalize()
+	s.ctx.Destroy()
+	s.ctx = nil
+
+	return err
+}
+
+func (s *PKCS11Signer) findKey(class uint, label string) (pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := s.ctx.FindObjectsInit(s.session, template); err != nil {
+		return 0, fmt.Errorf("find key %s: %w", label, err)
+	}
+	objs, _, err := s.ctx.FindObjects(s.session, 2)
+	if finalErr := s.ctx.FindObjectsFinal(s.session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("find key %s: %w", label, err)
+	}
+
+	switch len(objs) {
+	case 0:
+		return 0, fmt.Errorf("key %s not found", label)
+	case 1:
+		return objs[0], nil
+	default:
+		return 0, fmt.Errorf("multiple keys labeled %s", label)
+	}
+}
+
+func findSlot(ctx *pkcs11.Ctx, tokenLabel string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("get slots: %w", err)
+	}
+
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, fmt.Errorf("get token info of slot %d: %w", slot, err)
+		}
+		if strings.TrimRight(info.Label, " \x00") == tokenLabel {
+			return slot, nil
+		}
+	}
+
+	return 0, fmt.Errorf("token %s not found", tokenLabel)
+}
+
+// parsePKCS11PublicKey parses CKA_EC_PARAMS and CKA_EC_POINT of the public key object
+func parsePKCS11PublicKey(params, point []byte) (crypto.KeyType, crypto.PublicKey, error) {
+	oid := asn1.ObjectIdentifier{}
+	if rest, err := asn1.Unmarshal(params, &oid); err != nil || len(rest) != 0 {
+		return 0, nil, fmt.Errorf("only named curves are supported")
+	}
+
+	// CKA_EC_POINT is the DER encoded octet string of the uncompressed point,
+	// some modules return the point without encoding
+	var raw []byte
+	if rest, err := asn1.Unmarshal(point, &raw); err != nil || len(rest) != 0 {
+		raw = point
+	}
+
+	var (
+		typ   crypto.KeyType
+		curve elliptic.Curve
+	)
+	switch {
+	case oid.Equal(oidSecp256k1):
+		pubKey, err := ecdsa.UnmarshalPublicKey(raw, crypto.Secp256k1)
+		if err != nil {
+			return 0, nil, err
+		}
+		return crypto.Secp256k1, pubKey, nil
+	case oid.Equal(oidP256):
+		typ, curve = crypto.ECDSA_P256, elliptic.P256()
+	case oid.Equal(oidP384):
+		typ, curve = crypto.ECDSA_P384, elliptic.P384()
+	case oid.Equal(oidP521):
+		typ, curve = crypto.ECDSA_P521, elliptic.P521()
+	default:
+		return 0, nil, fmt.Errorf("unsupported curve %s", oid.String())
+	}
+
+	x, y := elliptic.Unmarshal(curve, raw)
+	if x == nil {
+		return 0, nil, fmt.Errorf("invalid public key point")
+	}
+	pubKey, err := ecdsa.NewPublicKey(stdecdsa.PublicKey{Curve: curve, X: x, Y: y})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return typ, pubKey, nil
+}
+
+// encodeSignature encodes the raw ECDSA signature in the format of the local key of the key type:
+// Secp256k1 signatures are the 65 bytes recoverable signatures with low S, and the signatures of
+// other curves carry the public key
+func encodeSignature(typ crypto.KeyType, pubKey crypto.PublicKey, digest []byte, r, s *big.Int) ([]byte, error) {
+	pubBytes, err := pubKey.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	if typ != crypto.Secp256k1 {
+		return asn1.Marshal(ecdsa.Sig{Pub: pubBytes, R: r, S: s})
+	}
+
+	n := ecdsa.S256().Params().N
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s = new(big.Int).Sub(n, s)
+	}
+
+	sig := make([]byte, ecdsa.SignatureLength)
+	copy(sig[:32], ecdsa.PaddedBigBytes(r, 32))
+	copy(sig[32:64], ecdsa.PaddedBigBytes(s, 32))
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		recovered, err := ecdsa.Ecrecover(digest, sig)
+		if err == nil && bytes.Equal(recovered, pubBytes) {
+			return sig, nil
+		}
+	}
+
+	return nil, fmt.Errorf("signature is not signed by the key")
+}
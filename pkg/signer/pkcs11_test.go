@@ -0,0 +1,210 @@
+package signer
+
+import (
+	stdecdsa "crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/meshplus/bitxhub-kit/crypto"
+	"github.com/meshplus/bitxhub-kit/crypto/asym"
+	"github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/require"
+)
+
+var curveOIDs = map[crypto.KeyType]asn1.ObjectIdentifier{
+	crypto.Secp256k1:  oidSecp256k1,
+	crypto.ECDSA_P256: oidP256,
+	crypto.ECDSA_P384: oidP384,
+	crypto.ECDSA_P521: oidP521,
+}
+
+func TestParsePKCS11PublicKey(t *testing.T) {
+	for typ, oid := range curveOIDs {
+		privKey, err := asym.GenerateKeyPair(typ)
+		require.Nil(t, err)
+		stdKey, err := asym.PrivKeyToStdKey(privKey)
+		require.Nil(t, err)
+		expected, err := privKey.PublicKey().Address()
+		require.Nil(t, err)
+
+		params, err := asn1.Marshal(oid)
+		require.Nil(t, err)
+		raw := elliptic.Marshal(stdKey.Curve, stdKey.X, stdKey.Y)
+		point, err := asn1.Marshal(raw)
+		require.Nil(t, err)
+
+		// both the DER encoded and the raw points are accepted
+		for _, p := range [][]byte{point, raw} {
+			keyType, pubKey, err := parsePKCS11PublicKey(params, p)
+			require.Nil(t, err)
+			require.Equal(t, typ, keyType)
+			addr, err := pubKey.Address()
+			require.Nil(t, err)
+			require.Equal(t, expected.String(), addr.String())
+		}
+	}
+
+	params, err := asn1.Marshal(asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301})
+	require.Nil(t, err)
+	_, _, err = parsePKCS11PublicKey(params, []byte{4})
+	require.NotNil(t, err)
+
+	params, err = asn1.Marshal(oidP256)
+	require.Nil(t, err)
+	_, _, err = parsePKCS11PublicKey(params, []byte{4, 1, 2})
+	require.NotNil(t, err)
+}
+
+func TestEncodeSignature(t *testing.T) {
+	digest := sha256.Sum256([]byte("bitxhub"))
+	for typ := range curveOIDs {
+		privKey, err := asym.GenerateKeyPair(typ)
+		require.Nil(t, err)
+		stdKey, err := asym.PrivKeyToStdKey(privKey)
+		require.Nil(t, err)
+		addr, err := privKey.PublicKey().Address()
+		require.Nil(t, err)
+
+		r, s, err := stdecdsa.Sign(rand.Reader, &stdKey, digest[:])
+		require.Nil(t, err)
+
+		// the raw signature of the HSM is verified as the signature of the local key
+		sig, err := encodeSignature(typ, privKey.PublicKey(), digest[:], r, s)
+		require.Nil(t, err)
+		ok, err := asym.Verify(typ, sig, digest[:], *addr)
+		require.Nil(t, err)
+		require.True(t, ok)
+
+		if typ == crypto.Secp256k1 {
+			// high S is normalized
+			highS := new(big.Int).Sub(stdKey.Curve.Params().N, s)
+			sig2, err := encodeSignature(typ, privKey.PublicKey(), digest[:], r, highS)
+			require.Nil(t, err)
+			require.Equal(t, sig, sig2)
+
+			other, err := asym.GenerateKeyPair(typ)
+			require.Nil(t, err)
+			_, err = encodeSignature(typ, other.PublicKey(), digest[:], r, s)
+			require.NotNil(t, err)
+		}
+	}
+}
+
+// TestPKCS11Signer runs against a PKCS#11 module, e.g. with SoftHSM:
+//
+//	softhsm2-util --init-token --free --label bitxhub --pin 1234 --so-pin 1234
+//	BITXHUB_PKCS11_LIB=/usr/lib/softhsm/libsofthsm2.so BITXHUB_PKCS11_PIN=1234 go test -run TestPKCS11Signer
+func TestPKCS11Signer(t *testing.T) {
+	lib := os.Getenv("BITXHUB_PKCS11_LIB")
+	if lib == "" {
+		t.Skip("BITXHUB_PKCS11_LIB is not set")
+	}
+	config := PKCS11Config{
+		Lib:        lib,
+		TokenLabel: "bitxhub",
+		Pin:        os.Getenv("BITXHUB_PKCS11_PIN"),
+	}
+	if label := os.Getenv("BITXHUB_PKCS11_TOKEN"); label != "" {
+		config.TokenLabel = label
+	}
+
+	digest := sha256.Sum256([]byte("bitxhub"))
+	for typ, oid := range curveOIDs {
+		config.KeyLabel = fmt.Sprintf("bitxhub-signer-test-%d", typ)
+		generatePKCS11Key(t, config, oid)
+
+		s, err := New(Config{Type: PKCS11Type, PKCS11: config}, nil)
+		require.Nil(t, err)
+		require.Equal(t, typ, s.Type())
+		addr, err := s.PublicKey().Address()
+		require.Nil(t, err)
+
+		_, err = s.Bytes()
+		require.NotNil(t, err)
+
+		sig, err := s.Sign(digest[:])
+		require.Nil(t, err)
+		ok, err := asym.Verify(typ, sig, digest[:], *addr)
+		require.Nil(t, err)
+		require.True(t, ok)
+
+		typedSig, err := asym.SignWithType(s, digest[:])
+		require.Nil(t, err)
+		ok, err = asym.VerifyWithType(typedSig, digest[:], *addr)
+		require.Nil(t, err)
+		require.True(t, ok)
+
+		require.Nil(t, s.Close())
+		destroyPKCS11Key(t, config)
+	}
+
+	config.KeyLabel = "bitxhub-signer-test-missing"
+	_, err := NewPKCS11Signer(config)
+	require.NotNil(t, err)
+}
+
+func openPKCS11Session(t *testing.T, config PKCS11Config) (*pkcs11.Ctx, pkcs11.SessionHandle) {
+	ctx := pkcs11.New(config.Lib)
+	require.NotNil(t, ctx)
+	require.Nil(t, ctx.Initialize())
+	slot, err := findSlot(ctx, config.TokenLabel)
+	require.Nil(t, err)
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	require.Nil(t, err)
+	require.Nil(t, ctx.Login(session, pkcs11.CKU_USER, config.Pin))
+
+	return ctx, session
+}
+
+func closePKCS11Session(ctx *pkcs11.Ctx, session pkcs11.SessionHandle) {
+	_ = ctx.Logout(session)
+	_ = ctx.CloseSession(session)
+	_ = ctx.Finalize()
+	ctx.Destroy()
+}
+
+func generatePKCS11Key(t *testing.T, config PKCS11Config, oid asn1.ObjectIdentifier) {
+	ctx, session := openPKCS11Session(t, config)
+	defer closePKCS11Session(ctx, session)
+
+	params, err := asn1.Marshal(oid)
+	require.Nil(t, err)
+	pubTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, params),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, config.KeyLabel),
+	}
+	privTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, config.KeyLabel),
+	}
+	mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)}
+	_, _, err = ctx.GenerateKeyPair(session, mechanism, pubTemplate, privTemplate)
+	require.Nil(t, err)
+}
+
+func destroyPKCS11Key(t *testing.T, config PKCS11Config) {
+	ctx, session := openPKCS11Session(t, config)
+	defer closePKCS11Session(ctx, session)
+
+	s := &PKCS11Signer{ctx: ctx, session: session}
+	for _, class := range []uint{pkcs11.CKO_PUBLIC_KEY, pkcs11.CKO_PRIVATE_KEY} {
+		obj, err := s.findKey(class, config.KeyLabel)
+		require.Nil(t, err)
+		require.Nil(t, ctx.DestroyObject(session, obj))
+	}
+}
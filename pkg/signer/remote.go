@@ -0,0 +1,254 @@
+package signer
+
+import (
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+	"time"
+
+	"github.com/meshplus/bitxhub-kit/crypto"
+	"github.com/meshplus/bitxhub-kit/crypto/asym"
+	"github.com/meshplus/bitxhub-kit/types"
+)
+
+// The remote signer protocol is JSON-RPC 1.0 over a unix socket, the service provides
+// Signer.PublicKey and Signer.Sign
+const (
+	remoteService        = "Signer"
+	defaultRemoteTimeout = 5 * time.Second
+)
+
+// RemoteConfig configures the remote signer
+type RemoteConfig struct {
+	// Socket is the path of the unix socket which the remote signer listens on
+	Socket string `toml:"socket" json:"socket"`
+	// Timeout is the time to wait for the replies of the remote signer
+	Timeout time.Duration `toml:"timeout" json:"timeout"`
+}
+
+// PublicKeyArgs are the args of Signer.PublicKey
+type PublicKeyArgs struct{}
+
+// PublicKeyReply is the reply of Signer.PublicKey, the public key is in the format of PublicKey.Bytes
+type PublicKeyReply struct {
+	Type      crypto.KeyType `json:"type"`
+	PublicKey []byte         `json:"public_key"`
+}
+
+// SignArgs are the args of Signer.Sign
+type SignArgs struct {
+	Digest []byte `json:"digest"`
+}
+
+// SignReply is the reply of Signer.Sign, the signature is in the format of PrivateKey.Sign
+type SignReply struct {
+	Signature []byte `json:"signature"`
+}
+
+// RemoteSigner signs with the key kept by the remote signer
+type RemoteSigner struct {
+	config  RemoteConfig
+	typ     crypto.KeyType
+	pubKey  crypto.PublicKey
+	address *types.Address
+
+	lock   sync.Mutex
+	client *rpc.Client
+}
+
+var _ Signer = (*RemoteSigner)(nil)
+
+// NewRemoteSigner connects to the remote signer and fetches the public key of the signing key
+func NewRemoteSigner(config RemoteConfig) (*RemoteSigner, error) {
+	if config.Socket == "" {
+		return nil, fmt.Errorf("socket of remote signer is empty")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = defaultRemoteTimeout
+	}
+
+	s := &RemoteSigner{config: config}
+
+	reply := &PublicKeyReply{}
+	if err := s.call("PublicKey", &PublicKeyArgs{}, reply); err != nil {
+		return nil, fmt.Errorf("get public key from remote signer: %w", err)
+	}
+	pubKey, err := UnmarshalPublicKey(reply.PublicKey, reply.Type)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal public key from remote signer: %w", err)
+	}
+	address, err := pubKey.Address()
+	if err != nil {
+		return nil, fmt.Errorf("get address from public key failed: %w", err)
+	}
+
+	s.typ = reply.Type
+	s.pubKey = pubKey
+	s.address = address
+
+	return s, nil
+}
+
+func (s *RemoteSigner) Bytes() ([]byte, error) {
+	return nil, fmt.Errorf("private key is kept by remote signer")
+}
+
+func (s *RemoteSigner) Type() crypto.KeyType {
+	return s.typ
+}
+
+func (s *RemoteSigner) PublicKey() crypto.PublicKey {
+	return s.pubKey
+}
+
+// Sign signs the digest by the remote signer, the signature is checked against the public key
+// fetched on connecting, so that a signer swapped behind the socket is detected
+func (s *RemoteSigner) Sign(digest []byte) ([]byte, error) {
+	reply := &SignReply{}
+	if err := s.call("Sign", &SignArgs{Digest: digest}, reply); err != nil {
+		return nil, fmt.Errorf("sign by remote signer: %w", err)
+	}
+
+	if _, err := asym.Verify(s.typ, reply.Signature, digest, *s.address); err != nil {
+		return nil, fmt.Errorf("verify signature of remote signer: %w", err)
+	}
+
+	return reply.Signature, nil
+}
+
+func (s *RemoteSigner) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.client == nil {
+		return nil
+	}
+	err := s.client.Close()
+	s.client = nil
+
+	return err
+}
+
+func (s *RemoteSigner) call(method string, args interface{}, reply interface{}) error {
+	client, err := s.getClient()
+	if err != nil {
+		return err
+	}
+
+	call := client.Go(remoteService+"."+method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		if call.Error == nil {
+			return nil
+		}
+		// errors other than the ones returned by the remote signer break the connection,
+		// reconnect on the next call in case the remote signer is restarted
+		if _, ok := call.Error.(rpc.ServerError); !ok {
+			s.resetClient(client)
+		}
+		return call.Error
+	case <-time.After(s.config.Timeout):
+		s.resetClient(client)
+		return fmt.Errorf("remote signer timeout")
+	}
+}
+
+func (s *RemoteSigner) getClient() (*rpc.Client, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.client != nil {
+		return s.client, nil
+	}
+
+	conn, err := net.DialTimeout("unix", s.config.Socket, s.config.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("dial remote signer %s: %w", s.config.Socket, err)
+	}
+	s.client = jsonrpc.NewClient(conn)
+
+	return s.client, nil
+}
+
+func (s *RemoteSigner) resetClient(client *rpc.Client) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.client == client {
+		_ = s.client.Close()
+		s.client = nil
+	}
+}
+
+// RemoteServer serves the private key to the nodes by the remote signer protocol
+type RemoteServer struct {
+	privKey crypto.PrivateKey
+}
+
+func (s *RemoteServer) PublicKey(args *PublicKeyArgs, reply *PublicKeyReply) error {
+	pubKey, err := s.privKey.PublicKey().Bytes()
+	if err != nil {
+		return err
+	}
+
+	reply.Type = s.privKey.Type()
+	reply.PublicKey = pubKey
+
+	return nil
+}
+
+func (s *RemoteServer) Sign(args *SignArgs, reply *SignReply) error {
+	if len(args.Digest) == 0 {
+		return fmt.Errorf("empty digest")
+	}
+
+	sig, err := s.privKey.Sign(args.Digest)
+	if err != nil {
+		return err
+	}
+	reply.Signature = sig
+
+	return nil
+}
+
+// ServeRemote serves the private key on the listener until the listener is closed,
+// the connections are closed along with the listener
+func ServeRemote(l net.Listener, privKey crypto.PrivateKey) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName(remoteService, &RemoteServer{privKey: privKey}); err != nil {
+		return fmt.Errorf("register remote signer: %w", err)
+	}
+
+	var (
+		lock  sync.Mutex
+		conns = make(map[net.Conn]struct{})
+	)
+	defer func() {
+		lock.Lock()
+		defer lock.Unlock()
+		for conn := range conns {
+			_ = conn.Close()
+		}
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		lock.Lock()
+		conns[conn] = struct{}{}
+		lock.Unlock()
+
+		go func() {
+			server.ServeCodec(jsonrpc.NewServerCodec(conn))
+
+			lock.Lock()
+			delete(conns, conn)
+			lock.Unlock()
+		}()
+	}
+}
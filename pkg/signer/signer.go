@@ -0,0 +1,79 @@
+package signer
+
+import (
+	"fmt"
+
+	"github.com/meshplus/bitxhub-kit/crypto"
+	"github.com/meshplus/bitxhub-kit/crypto/asym/ecdsa"
+	"github.com/meshplus/bitxhub/pkg/crypto/sm2"
+)
+
+const (
+	// LocalType signs with the key restored from key.json
+	LocalType = "local"
+	// PKCS11Type signs with the key kept in a HSM through PKCS#11
+	PKCS11Type = "pkcs11"
+	// RemoteType signs with the key kept by a remote signer listening on a local socket
+	RemoteType = "remote"
+)
+
+// Signer signs digests with the key of the node or the admin. It works as a private key whose
+// signatures are in the same format of the local key of the same type, so that it is accepted
+// everywhere a private key is, but the key may be kept out of the process by a HSM or a remote
+// signer, in which case Bytes fails.
+type Signer interface {
+	crypto.PrivateKey
+
+	// Close releases the session or the connection held by the signer
+	Close() error
+}
+
+// Config selects the signer of the node key
+type Config struct {
+	// Type is one of local, pkcs11 and remote
+	Type   string       `toml:"type" json:"type"`
+	PKCS11 PKCS11Config `mapstructure:"pkcs11" toml:"pkcs11" json:"pkcs11"`
+	Remote RemoteConfig `toml:"remote" json:"remote"`
+}
+
+// New creates the signer of the config, the local private key is used by the local signer
+func New(config Config, local crypto.PrivateKey) (Signer, error) {
+	switch config.Type {
+	case "", LocalType:
+		if local == nil {
+			return nil, fmt.Errorf("local private key is empty")
+		}
+		return NewLocalSigner(local), nil
+	case PKCS11Type:
+		return NewPKCS11Signer(config.PKCS11)
+	case RemoteType:
+		return NewRemoteSigner(config.Remote)
+	default:
+		return nil, fmt.Errorf("unsupported signer type %s", config.Type)
+	}
+}
+
+type localSigner struct {
+	crypto.PrivateKey
+}
+
+// NewLocalSigner wraps the private key restored in the process
+func NewLocalSigner(privKey crypto.PrivateKey) Signer {
+	return &localSigner{PrivateKey: privKey}
+}
+
+func (s *localSigner) Close() error {
+	return nil
+}
+
+// UnmarshalPublicKey parses the public key in the format of PublicKey.Bytes of the key type
+func UnmarshalPublicKey(data []byte, typ crypto.KeyType) (crypto.PublicKey, error) {
+	switch typ {
+	case crypto.Secp256k1, crypto.ECDSA_P256, crypto.ECDSA_P384, crypto.ECDSA_P521:
+		return ecdsa.UnmarshalPublicKey(data, typ)
+	case crypto.SM2:
+		return sm2.UnmarshalPublicKey(data)
+	default:
+		return nil, fmt.Errorf("unsupported key type %d", typ)
+	}
+}
@@ -0,0 +1,183 @@
+package signer
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/meshplus/bitxhub-kit/crypto"
+	"github.com/meshplus/bitxhub-kit/crypto/asym"
+	"github.com/stretchr/testify/require"
+)
+
+var keyTypes = []crypto.KeyType{crypto.Secp256k1, crypto.ECDSA_P256, crypto.ECDSA_P384, crypto.ECDSA_P521, crypto.SM2}
+
+func TestNew(t *testing.T) {
+	privKey, err := asym.GenerateKeyPair(crypto.Secp256k1)
+	require.Nil(t, err)
+
+	s, err := New(Config{Type: LocalType}, privKey)
+	require.Nil(t, err)
+	require.Equal(t, privKey.Type(), s.Type())
+	require.Nil(t, s.Close())
+
+	s, err = New(Config{}, privKey)
+	require.Nil(t, err)
+	require.Equal(t, privKey.Type(), s.Type())
+
+	_, err = New(Config{Type: LocalType}, nil)
+	require.NotNil(t, err)
+
+	_, err = New(Config{Type: "unknown"}, privKey)
+	require.NotNil(t, err)
+
+	_, err = New(Config{Type: PKCS11Type}, nil)
+	require.NotNil(t, err)
+
+	_, err = New(Config{Type: PKCS11Type, PKCS11: PKCS11Config{Lib: "libnotexist.so", KeyLabel: "node"}}, nil)
+	require.NotNil(t, err)
+
+	_, err = New(Config{Type: RemoteType}, nil)
+	require.NotNil(t, err)
+}
+
+func TestUnmarshalPublicKey(t *testing.T) {
+	for _, typ := range keyTypes {
+		privKey, err := asym.GenerateKeyPair(typ)
+		require.Nil(t, err)
+		data, err := privKey.PublicKey().Bytes()
+		require.Nil(t, err)
+
+		pubKey, err := UnmarshalPublicKey(data, typ)
+		require.Nil(t, err)
+		require.Equal(t, typ, pubKey.Type())
+		addr, err := pubKey.Address()
+		require.Nil(t, err)
+		expected, err := privKey.PublicKey().Address()
+		require.Nil(t, err)
+		require.Equal(t, expected.String(), addr.String())
+	}
+
+	_, err := UnmarshalPublicKey([]byte("pub"), crypto.Ed25519)
+	require.NotNil(t, err)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "signer.sock")
+
+	digest := sha256.Sum256([]byte("bitxhub"))
+	for _, typ := range keyTypes {
+		privKey, err := asym.GenerateKeyPair(typ)
+		require.Nil(t, err)
+		l := serve(t, socket, privKey)
+
+		s, err := New(Config{Type: RemoteType, Remote: RemoteConfig{Socket: socket}}, nil)
+		require.Nil(t, err)
+		require.Equal(t, typ, s.Type())
+		addr, err := s.PublicKey().Address()
+		require.Nil(t, err)
+		expected, err := privKey.PublicKey().Address()
+		require.Nil(t, err)
+		require.Equal(t, expected.String(), addr.String())
+
+		_, err = s.Bytes()
+		require.NotNil(t, err)
+
+		// signatures are in the format of the local key
+		sig, err := s.Sign(digest[:])
+		require.Nil(t, err)
+		ok, err := asym.Verify(typ, sig, digest[:], *addr)
+		require.Nil(t, err)
+		require.True(t, ok)
+
+		typedSig, err := asym.SignWithType(s, digest[:])
+		require.Nil(t, err)
+		ok, err = asym.VerifyWithType(typedSig, digest[:], *addr)
+		require.Nil(t, err)
+		require.True(t, ok)
+
+		// errors of the remote signer are returned
+		_, err = s.Sign(nil)
+		require.NotNil(t, err)
+
+		require.Nil(t, s.Close())
+		require.Nil(t, l.Close())
+	}
+
+	_, err = NewRemoteSigner(RemoteConfig{Socket: socket})
+	require.NotNil(t, err)
+}
+
+func TestRemoteSigner_Reconnect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "signer.sock")
+
+	privKey, err := asym.GenerateKeyPair(crypto.Secp256k1)
+	require.Nil(t, err)
+	l := serve(t, socket, privKey)
+
+	s, err := NewRemoteSigner(RemoteConfig{Socket: socket, Timeout: time.Second})
+	require.Nil(t, err)
+	defer s.Close()
+
+	digest := sha256.Sum256([]byte("bitxhub"))
+	_, err = s.Sign(digest[:])
+	require.Nil(t, err)
+
+	// the remote signer is restarted
+	require.Nil(t, l.Close())
+	_, err = s.Sign(digest[:])
+	require.NotNil(t, err)
+
+	l = serve(t, socket, privKey)
+	_, err = s.Sign(digest[:])
+	require.Nil(t, err)
+
+	// signatures of other keys are refused
+	require.Nil(t, l.Close())
+	other, err := asym.GenerateKeyPair(crypto.Secp256k1)
+	require.Nil(t, err)
+	l = serve(t, socket, other)
+	defer l.Close()
+	_, err = s.Sign(digest[:])
+	require.NotNil(t, err)
+	_, err = s.Sign(digest[:])
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "verify signature of remote signer")
+}
+
+type remoteServer struct {
+	net.Listener
+	done chan struct{}
+}
+
+// Close closes the listener and waits for the connections to be closed
+func (s *remoteServer) Close() error {
+	err := s.Listener.Close()
+	<-s.done
+
+	return err
+}
+
+func serve(t *testing.T, socket string, privKey crypto.PrivateKey) net.Listener {
+	_ = os.Remove(socket)
+	l, err := net.Listen("unix", socket)
+	require.Nil(t, err)
+
+	s := &remoteServer{Listener: l, done: make(chan struct{})}
+	go func() {
+		_ = ServeRemote(l, privKey)
+		close(s.done)
+	}()
+
+	return s
+}
@@ -0,0 +1,160 @@
+package tssmgr
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/meshplus/bitxhub-kit/storage"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	auditDirName = "audit"
+
+	// MaxAuditRecordsNum is the max number of sign audit records returned by one query
+	MaxAuditRecordsNum = 1000
+
+	auditIndexKey      = "audit-index"
+	auditRecordPrefix  = "audit-record-"
+	auditCulpritPrefix = "audit-culprit-"
+)
+
+// SignAuditRecord records a tss key sign request handled by the local node
+type SignAuditRecord struct {
+	Index     uint64   `json:"index"`
+	MsgID     string   `json:"msg_id"`
+	Messages  []string `json:"messages"`
+	Signers   []string `json:"signers"`
+	Culprits  []string `json:"culprits"`
+	Success   bool     `json:"success"`
+	Error     string   `json:"error"`
+	Timestamp int64    `json:"timestamp"`
+}
+
+// CulpritEvent is posted every time a party is blamed in a key sign request
+type CulpritEvent struct {
+	PartyID string
+	// Count is the times the party has been blamed on the local node
+	Count uint64
+}
+
+func auditRecordKey(index uint64) []byte {
+	key := make([]byte, len(auditRecordPrefix)+8)
+	copy(key, auditRecordPrefix)
+	binary.BigEndian.PutUint64(key[len(auditRecordPrefix):], index)
+	return key
+}
+
+func auditCulpritKey(partyID string) []byte {
+	return []byte(auditCulpritPrefix + partyID)
+}
+
+func getUint64(db storage.Storage, key []byte) uint64 {
+	data := db.Get(key)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+func putUint64(batch storage.Batch, key []byte, val uint64) {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, val)
+	batch.Put(key, data)
+}
+
+// recordKeySign appends the key sign request to the audit log and counts the culprits
+func (t *TssMgr) recordKeySign(msgID string, msgs, signers, culprits []string, signErr error) {
+	if t.auditDB == nil {
+		return
+	}
+
+	t.auditLock.Lock()
+	record := &SignAuditRecord{
+		Index:     getUint64(t.auditDB, []byte(auditIndexKey)) + 1,
+		MsgID:     msgID,
+		Messages:  msgs,
+		Signers:   signers,
+		Culprits:  culprits,
+		Success:   signErr == nil,
+		Timestamp: time.Now().UnixNano(),
+	}
+	if signErr != nil {
+		record.Error = signErr.Error()
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.auditLock.Unlock()
+		t.logger.WithFields(logrus.Fields{"msgID": msgID, "err": err}).Error("marshal sign audit record error")
+		return
+	}
+
+	events := make([]CulpritEvent, 0, len(culprits))
+	batch := t.auditDB.NewBatch()
+	batch.Put(auditRecordKey(record.Index), data)
+	putUint64(batch, []byte(auditIndexKey), record.Index)
+	for _, id := range culprits {
+		count := getUint64(t.auditDB, auditCulpritKey(id)) + 1
+		putUint64(batch, auditCulpritKey(id), count)
+		events = append(events, CulpritEvent{PartyID: id, Count: count})
+	}
+	batch.Commit()
+	t.auditLock.Unlock()
+
+	for _, ev := range events {
+		t.culpritFeed.Send(ev)
+	}
+}
+
+// GetSignAuditRecords returns the sign audit records with index in [start, end],
+// at most MaxAuditRecordsNum records are returned
+func (t *TssMgr) GetSignAuditRecords(start, end uint64) ([]*SignAuditRecord, error) {
+	if t.auditDB == nil {
+		return nil, fmt.Errorf("tss sign audit log is not enabled")
+	}
+	if start == 0 {
+		start = 1
+	}
+	if end < start {
+		return nil, fmt.Errorf("invalid audit record range [%d, %d]", start, end)
+	}
+	if end-start >= MaxAuditRecordsNum {
+		end = start + MaxAuditRecordsNum - 1
+	}
+
+	records := make([]*SignAuditRecord, 0)
+	it := t.auditDB.Iterator(auditRecordKey(start), auditRecordKey(end+1))
+	for it.Next() {
+		record := &SignAuditRecord{}
+		if err := json.Unmarshal(it.Value(), record); err != nil {
+			return nil, fmt.Errorf("unmarshal sign audit record error: %w", err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// GetCulpritCounts returns the times each party has been blamed in key sign requests
+func (t *TssMgr) GetCulpritCounts() (map[string]uint64, error) {
+	if t.auditDB == nil {
+		return nil, fmt.Errorf("tss sign audit log is not enabled")
+	}
+
+	counts := make(map[string]uint64)
+	it := t.auditDB.Prefix([]byte(auditCulpritPrefix))
+	for it.Next() {
+		if len(it.Value()) != 8 {
+			continue
+		}
+		counts[string(it.Key()[len(auditCulpritPrefix):])] = binary.BigEndian.Uint64(it.Value())
+	}
+	return counts, nil
+}
+
+// SubscribeCulprit subscribes the parties blamed in key sign requests
+func (t *TssMgr) SubscribeCulprit(ch chan<- CulpritEvent) event.Subscription {
+	return t.culpritFeed.Subscribe(ch)
+}
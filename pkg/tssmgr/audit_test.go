@@ -0,0 +1,92 @@
+package tssmgr
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/meshplus/bitxhub-kit/log"
+	"github.com/meshplus/bitxhub-kit/storage/leveldb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordKeySign(t *testing.T) {
+	mgr := &TssMgr{logger: log.NewWithModule("tss")}
+
+	// the audit log is not enabled
+	mgr.recordKeySign("msg0", []string{"a"}, []string{"1", "2"}, nil, nil)
+	_, err := mgr.GetSignAuditRecords(1, 1)
+	require.NotNil(t, err)
+	_, err = mgr.GetCulpritCounts()
+	require.NotNil(t, err)
+
+	dir, err := ioutil.TempDir("", "tss_audit")
+	require.Nil(t, err)
+	mgr.auditDB, err = leveldb.New(filepath.Join(dir, auditDirName))
+	require.Nil(t, err)
+	defer mgr.auditDB.Close()
+
+	ch := make(chan CulpritEvent, 10)
+	sub := mgr.SubscribeCulprit(ch)
+	defer sub.Unsubscribe()
+
+	mgr.recordKeySign("msg1", []string{"a"}, []string{"1", "2"}, nil, nil)
+	mgr.recordKeySign("msg2", []string{"b"}, []string{"1", "3"}, []string{"3"}, errors.New("blame"))
+	mgr.recordKeySign("msg3", []string{"c"}, []string{"2", "3"}, []string{"2", "3"}, errors.New("blame"))
+
+	records, err := mgr.GetSignAuditRecords(0, 10)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(records))
+	for i, record := range records {
+		require.Equal(t, uint64(i+1), record.Index)
+	}
+	require.True(t, records[0].Success)
+	require.Equal(t, "", records[0].Error)
+	require.Equal(t, "msg2", records[1].MsgID)
+	require.Equal(t, []string{"b"}, records[1].Messages)
+	require.Equal(t, []string{"1", "3"}, records[1].Signers)
+	require.Equal(t, []string{"3"}, records[1].Culprits)
+	require.False(t, records[1].Success)
+	require.Equal(t, "blame", records[1].Error)
+
+	records, err = mgr.GetSignAuditRecords(2, 2)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(records))
+	require.Equal(t, "msg2", records[0].MsgID)
+
+	_, err = mgr.GetSignAuditRecords(3, 2)
+	require.NotNil(t, err)
+
+	counts, err := mgr.GetCulpritCounts()
+	require.Nil(t, err)
+	require.Equal(t, map[string]uint64{"2": 1, "3": 2}, counts)
+
+	expected := []CulpritEvent{{PartyID: "3", Count: 1}, {PartyID: "2", Count: 1}, {PartyID: "3", Count: 2}}
+	for _, ev := range expected {
+		select {
+		case got := <-ch:
+			require.Equal(t, ev, got)
+		case <-time.After(time.Second):
+			t.Fatal("culprit event is not posted")
+		}
+	}
+}
+
+func TestGetSignAuditRecordsLimit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tss_audit")
+	require.Nil(t, err)
+	db, err := leveldb.New(filepath.Join(dir, auditDirName))
+	require.Nil(t, err)
+	defer db.Close()
+	mgr := &TssMgr{auditDB: db, logger: log.NewWithModule("tss")}
+
+	for i := 0; i < MaxAuditRecordsNum+1; i++ {
+		mgr.recordKeySign("msg", nil, nil, nil, nil)
+	}
+	records, err := mgr.GetSignAuditRecords(1, MaxAuditRecordsNum+1)
+	require.Nil(t, err)
+	require.Equal(t, MaxAuditRecordsNum, len(records))
+	require.Equal(t, uint64(MaxAuditRecordsNum), records[len(records)-1].Index)
+}
@@ -1,6 +1,7 @@
 package tssmgr
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"sync"
@@ -61,6 +62,14 @@ func (t *TssMgr) Keygen(isAddNodeReq bool) error {
 				t.logger.Infof("load tss successful, keygen stopped")
 				return nil
 			}
+		} else {
+			// 2.4 如果自己没有持久化数据，但其他节点已有公钥，通过resharing加入签名组合，公钥保持不变
+			if err := t.reshare(t.GetThreshold(), ""); err == nil {
+				t.logger.Infof("join tss by resharing, keygen stopped")
+				return nil
+			} else if !errors.Is(err, ErrKeyNotReshareable) {
+				return fmt.Errorf("join tss by resharing: %w", err)
+			}
 		}
 	}
 
@@ -22,29 +22,38 @@ import (
 // - signature data
 // - blame nodes id list
 // - error
+// Every request is recorded in the sign audit log.
 func (t *TssMgr) KeySign(signers []string, msgs []string, randomN string) ([]byte, []string, error) {
+	t.shareLocker.RLock()
+	msgID, signData, culprits, err := t.keySign(signers, msgs, randomN)
+	t.shareLocker.RUnlock()
+	t.recordKeySign(msgID, msgs, signers, culprits, err)
+	return signData, culprits, err
+}
+
+func (t *TssMgr) keySign(signers []string, msgs []string, randomN string) (string, []byte, []string, error) {
 	//t.keyRoundDone.Store(false)
 	//defer t.keyRoundDone.Store(true)
 	// 1. get pool pubKey
 	_, pk, err := t.GetTssPubkey()
 	if err != nil {
-		return nil, nil, fmt.Errorf("get tss pubkey error: %w", err)
+		return "", nil, nil, fmt.Errorf("get tss pubkey error: %w", err)
 	}
 
 	// 2. get signers pk
 	tssInfo, err := t.GetTssInfo()
 	if err != nil {
-		return nil, nil, fmt.Errorf("fail to get keygen parties pk map error: %w", err)
+		return "", nil, nil, fmt.Errorf("fail to get keygen parties pk map error: %w", err)
 	}
 	signersPk := make([]crypto3.PubKey, 0)
 	for _, id := range signers {
 		data, ok := tssInfo.PartiesPkMap[id]
 		if !ok {
-			return nil, nil, fmt.Errorf("party %s is not keygen party", id)
+			return "", nil, nil, fmt.Errorf("party %s is not keygen party", id)
 		}
 		pk, err := conversion.GetPubKeyFromPubKeyData(data)
 		if err != nil {
-			return nil, nil, fmt.Errorf("fail to conversion pubkeydata to pubkey: %w", err)
+			return "", nil, nil, fmt.Errorf("fail to conversion pubkeydata to pubkey: %w", err)
 		}
 		signersPk = append(signersPk, pk)
 	}
@@ -53,13 +62,13 @@ func (t *TssMgr) KeySign(signers []string, msgs []string, randomN string) ([]byt
 	keysignReq := keysign.NewRequest(pk, msgs, signersPk, randomN)
 	msgID, err := keysignReq.RequestToMsgId()
 	if err != nil {
-		return nil, nil, err
+		return "", nil, nil, err
 	}
 	tssInstance := t.tssPools.Get().(*tss.TssInstance)
 	defer t.tssPools.Put(tssInstance)
 	err = tssInstance.InitTssInfo(msgID, len(keysignReq.Messages), t.localPrivK, t.threshold, t.tssConf, t.keygenPreParams, t.keygenLocalState, t.peerMgr, t.logger)
 	if err != nil {
-		return nil, nil, fmt.Errorf("tss init error: %v", err)
+		return msgID, nil, nil, fmt.Errorf("tss init error: %v", err)
 	}
 
 	// for this msgID, start key sign round
@@ -67,7 +76,7 @@ func (t *TssMgr) KeySign(signers []string, msgs []string, randomN string) ([]byt
 
 	_, ok := t.tssInstances.Load(msgID)
 	if ok {
-		return nil, nil, fmt.Errorf("repeated msgID: %s", msgID)
+		return msgID, nil, nil, fmt.Errorf("repeated msgID: %s", msgID)
 	}
 	t.tssInstances.Store(msgID, tssInstance)
 	defer t.tssInstances.Delete(msgID)
@@ -75,7 +84,7 @@ func (t *TssMgr) KeySign(signers []string, msgs []string, randomN string) ([]byt
 	t.logger.WithFields(logrus.Fields{"resp": resp, "err": err}).Debug("get key sign")
 	if err != nil {
 		if errors.Is(err, tss.ErrNotActiveSigner) {
-			return nil, nil, err
+			return msgID, nil, nil, err
 		} else if resp != nil && len(resp.Blame.BlameNodes) != 0 {
 			culpritIDs := make([]string, 0)
 			for _, node := range resp.Blame.BlameNodes {
@@ -85,18 +94,18 @@ func (t *TssMgr) KeySign(signers []string, msgs []string, randomN string) ([]byt
 			if len(culpritIDs) != 0 {
 				t.broadcastCulprits(culpritIDs)
 			}
-			return nil, culpritIDs, err
+			return msgID, nil, culpritIDs, err
 		} else {
-			return nil, nil, fmt.Errorf("failed to tss key sign: %v", err)
+			return msgID, nil, nil, fmt.Errorf("failed to tss key sign: %v", err)
 		}
 	}
 
 	signData, err := json.Marshal(resp.Signatures)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to marshal tss signatures: %v", err)
+		return msgID, nil, nil, fmt.Errorf("failed to marshal tss signatures: %v", err)
 	}
 
-	return signData, nil, nil
+	return msgID, signData, nil, nil
 }
 
 func (t *TssMgr) broadcastCulprits(culprits []string) {
@@ -0,0 +1,985 @@
+package tssmgr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Rican7/retry"
+	"github.com/Rican7/retry/strategy"
+	bkg "github.com/binance-chain/tss-lib/ecdsa/keygen"
+	"github.com/binance-chain/tss-lib/ecdsa/resharing"
+	btss "github.com/binance-chain/tss-lib/tss"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/meshplus/bitxhub-core/tss/conversion"
+	"github.com/meshplus/bitxhub-core/tss/message"
+	"github.com/meshplus/bitxhub-core/tss/storage"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/sirupsen/logrus"
+)
+
+// The committee of a resharing party is carried in the moniker of its party id,
+// a node holding a share of the current key runs a party in both committees.
+const (
+	oldCommittee = "old"
+	newCommittee = "new"
+)
+
+// reshareQueryInterval is the interval to ask the new parties for their missing commits
+const reshareQueryInterval = time.Second
+
+// ErrKeyNotReshareable is returned if the current tss key can not be reshared no matter how many times
+// it is retried, e.g. there is no key in the network or less than t+1 holders of the key are left.
+// A new key has to be generated then, other resharing errors keep the current key.
+var ErrKeyNotReshareable = errors.New("tss key can not be reshared")
+
+// errReshareNotCommitted is returned if the local node does not get the commits of all the new parties in time.
+// Other parties may have replaced their shares, so resharing is not retried and the new shares are kept
+// pending until a party tells it has replaced its shares, see pendReshareCommit.
+var errReshareNotCommitted = errors.New("tss reshare is not committed")
+
+// Reshare moves the shares of the current tss key to all order peers with the new threshold.
+// The tss pubkey is kept unchanged, so the trust roots of appchains need not be updated
+// when the validator set changes. ErrKeyNotReshareable is returned if there is no key or less than t+1
+// holders of the key are still in the network.
+func (t *TssMgr) Reshare(newThreshold uint64) error {
+	t.keyGenLocker.Lock()
+	defer t.keyGenLocker.Unlock()
+
+	return t.reshare(newThreshold, "")
+}
+
+// Refresh proactively refreshes the shares of the current tss key at the epoch.
+// The tss pubkey and threshold are kept unchanged while the shares of the last epoch become useless,
+// so an attacker has to steal t+1 shares within one epoch to get the key.
+func (t *TssMgr) Refresh(epoch uint64) error {
+	t.keyGenLocker.Lock()
+	defer t.keyGenLocker.Unlock()
+
+	t.stateMgrLocker.Lock()
+	hasLocalState := t.keygenLocalState != nil
+	t.stateMgrLocker.Unlock()
+	if !hasLocalState {
+		return fmt.Errorf("no tss key to refresh")
+	}
+
+	return t.reshare(t.GetThreshold(), fmt.Sprintf("refresh-%d", epoch))
+}
+
+// reshare reshares the current tss key, round distinguishes the resharing rounds among the same parties
+func (t *TssMgr) reshare(newThreshold uint64, round string) error {
+	t.logger.Infof("============== Reshare start, t-%d, round: %s", newThreshold, round)
+
+	// wait for the running key signs, and the key signs wait for resharing
+	t.shareLocker.Lock()
+	defer t.shareLocker.Unlock()
+
+	// get the tss pool to reshare, a new node gets it from other peers
+	pool, err := t.getTssPool()
+	if err != nil {
+		return fmt.Errorf("get tss pool: %w", err)
+	}
+	if err := t.checkReshareable(pool); err != nil {
+		return err
+	}
+
+	var uncommitted error
+	if err := retry.Retry(func(attempt uint) error {
+		task, err := t.newReshareTask(pool, newThreshold, round)
+		if err != nil {
+			t.logger.WithFields(logrus.Fields{
+				"error": err,
+			}).Warnf("tss reshare init error, retry later...")
+			return err
+		}
+
+		state, err := t.runReshareTask(task)
+		if errors.Is(err, errReshareNotCommitted) {
+			uncommitted = err
+			return nil
+		}
+		if err != nil {
+			t.logger.WithFields(logrus.Fields{
+				"msgID": task.msgID,
+				"error": err,
+			}).Warnf("tss reshare error, retry later...")
+			return fmt.Errorf("tss reshare: %w", err)
+		}
+
+		// the new shares are committed by all the new parties, the old share is replaced only now
+		if err := t.replaceShares(state, newThreshold); err != nil {
+			return err
+		}
+		t.markReshareCommitted(task.msgID)
+		return nil
+	}, strategy.Limit(3), strategy.Wait(2*time.Second),
+	); err != nil {
+		t.logger.WithFields(logrus.Fields{
+			"error": err,
+		}).Errorf("tss reshare failed")
+		return fmt.Errorf("tss reshare failed: %w", err)
+	}
+	if uncommitted != nil {
+		t.logger.WithFields(logrus.Fields{
+			"error": uncommitted,
+		}).Errorf("tss reshare is not committed, the new shares are kept pending")
+		return fmt.Errorf("tss reshare failed: %w", uncommitted)
+	}
+
+	t.logger.Infof(">>>>>>>>>>>>>. reshare success, t-%d", newThreshold)
+	return nil
+}
+
+// checkReshareable checks whether t+1 holders of the key are still in the network,
+// all the nodes agreeing on the key and the peers get the same result
+func (t *TssMgr) checkReshareable(pool *pb.TssInfo) error {
+	var (
+		peers   = t.peerMgr.Peers()
+		holders uint64
+	)
+	for id := range pool.PartiesPkMap {
+		if _, ok := peers[id]; ok {
+			holders++
+		}
+	}
+	if holders <= t.GetThreshold() {
+		return fmt.Errorf("%w: only %d parties of the key left, at least t+1 parties are required: t-%d",
+			ErrKeyNotReshareable, holders, t.GetThreshold())
+	}
+	return nil
+}
+
+// reshareMsg is the resharing message sent to the parties of one node.
+// A confirm message carries the digest of the resharing result instead of a tss message, and so does
+// a commit message which is sent once the node gets the confirms of all the new parties. Committed is set
+// in the commit of a node which has replaced its shares, and Query asks the receiver to send its commit again.
+type reshareMsg struct {
+	From          string   `json:"from"`
+	FromCommittee string   `json:"from_committee"`
+	ToCommittees  []string `json:"to_committees"`
+	IsBroadcast   bool     `json:"is_broadcast"`
+	Confirm       bool     `json:"confirm,omitempty"`
+	Commit        bool     `json:"commit,omitempty"`
+	Committed     bool     `json:"committed,omitempty"`
+	Query         bool     `json:"query,omitempty"`
+	Message       []byte   `json:"message"`
+	Sig           []byte   `json:"signature"`
+}
+
+// reshareTask is a resharing round of the local node
+type reshareTask struct {
+	msgID        string
+	threshold    uint64
+	newThreshold uint64
+	pool         *pb.TssInfo
+
+	// committee -> partyID.id -> partyID
+	partyIDs map[string]map[string]*btss.PartyID
+	// partyID.id -> p2p pubkey
+	pubKeys map[string]crypto.PubKey
+	// committee -> local party, there is no old party if the local node holds no share
+	parties map[string]btss.Party
+
+	outCh     chan btss.Message
+	oldEnd    chan bkg.LocalPartySaveData
+	newEnd    chan bkg.LocalPartySaveData
+	confirmCh chan *reshareMsg
+	commitCh  chan *reshareMsg
+	errCh     chan error
+	logger    logrus.FieldLogger
+}
+
+var _ tssTask = (*reshareTask)(nil)
+
+func (t *TssMgr) newReshareTask(pool *pb.TssInfo, newThreshold uint64, round string) (*reshareTask, error) {
+	var err error
+	task := &reshareTask{
+		threshold:    t.GetThreshold(),
+		newThreshold: newThreshold,
+		pool:         pool,
+		partyIDs: map[string]map[string]*btss.PartyID{
+			oldCommittee: {},
+			newCommittee: {},
+		},
+		pubKeys: make(map[string]crypto.PubKey),
+		parties: make(map[string]btss.Party),
+		errCh:   make(chan error, 1),
+		logger:  t.logger,
+	}
+
+	// 1. the old committee is made up of the keygen parties still in the network
+	peers := t.peerMgr.Peers()
+	for id, pkData := range pool.PartiesPkMap {
+		if _, ok := peers[id]; !ok {
+			continue
+		}
+		pk, err := conversion.GetPubKeyFromPubKeyData(pkData)
+		if err != nil {
+			return nil, fmt.Errorf("fail to conversion pubkeydata to pubkey: %w", err)
+		}
+		task.pubKeys[id] = pk
+		task.partyIDs[oldCommittee][id] = btss.NewPartyID(id, oldCommittee, new(big.Int).SetBytes(pkData))
+	}
+
+	// 2. the new committee is made up of all order peers
+	keys := map[uint64]crypto.PubKey{t.localID: t.localPubK}
+	for id, key := range t.fetchPkFromOtherPeers() {
+		keys[id] = key
+	}
+	if len(keys) != len(peers) {
+		return nil, fmt.Errorf("get %d pubkeys of %d peers", len(keys), len(peers))
+	}
+	for pid, pk := range keys {
+		id := strconv.FormatUint(pid, 10)
+		if old, ok := task.pubKeys[id]; ok && !old.Equals(pk) {
+			return nil, fmt.Errorf("pubkey of node %s is changed", id)
+		}
+		task.pubKeys[id] = pk
+		task.partyIDs[newCommittee][id] = btss.NewPartyID(id, newCommittee, newCommitteeKey(pk))
+	}
+
+	oldIDs := sortedPartyIDs(task.partyIDs[oldCommittee])
+	newIDs := sortedPartyIDs(task.partyIDs[newCommittee])
+	if newThreshold >= uint64(len(newIDs)) {
+		return nil, fmt.Errorf("threshold %d is not less than the new parties num %d", newThreshold, len(newIDs))
+	}
+
+	// 3. all the nodes get the same msgID if they agree on the key, both committees and the round
+	task.msgID, err = reshareMsgID(pool.Pubkey, oldIDs, newIDs, newThreshold, round)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. construct the local parties
+	var (
+		oldCtx  = btss.NewPeerContext(oldIDs)
+		newCtx  = btss.NewPeerContext(newIDs)
+		localID = strconv.FormatUint(t.localID, 10)
+		partyN  = len(oldIDs) + len(newIDs)
+	)
+	task.outCh = make(chan btss.Message, partyN)
+	task.oldEnd = make(chan bkg.LocalPartySaveData, 1)
+	task.newEnd = make(chan bkg.LocalPartySaveData, 1)
+	task.confirmCh = make(chan *reshareMsg, 2*len(newIDs))
+	task.commitCh = make(chan *reshareMsg, 2*len(newIDs))
+
+	if partyID, ok := task.partyIDs[oldCommittee][localID]; ok {
+		if len(oldIDs) <= int(task.threshold) {
+			return nil, fmt.Errorf("only %d parties of the key left, at least t+1 parties are required: t-%d", len(oldIDs), task.threshold)
+		}
+		t.stateMgrLocker.Lock()
+		localState := t.keygenLocalState
+		t.stateMgrLocker.Unlock()
+		if localState == nil {
+			return nil, fmt.Errorf("local share of the tss key is lost")
+		}
+		key := localState.LocalData
+		// the old party clears its share at the end of resharing, keep the local state unchanged
+		key.Xi = new(big.Int).Set(key.Xi)
+
+		params := btss.NewReSharingParameters(oldCtx, newCtx, partyID, len(oldIDs), int(task.threshold), len(newIDs), int(newThreshold))
+		task.parties[oldCommittee] = resharing.NewLocalParty(t.logger, params, key, task.outCh, task.oldEnd)
+	}
+
+	if t.keygenPreParams == nil {
+		return nil, fmt.Errorf("empty keygen pre-parameters")
+	}
+	key := bkg.NewLocalPartySaveData(len(newIDs))
+	key.LocalPreParams = *t.keygenPreParams
+	params := btss.NewReSharingParameters(oldCtx, newCtx, task.partyIDs[newCommittee][localID], len(oldIDs), int(task.threshold), len(newIDs), int(newThreshold))
+	task.parties[newCommittee] = resharing.NewLocalParty(t.logger, params, key, task.outCh, task.newEnd)
+
+	return task, nil
+}
+
+func (t *TssMgr) runReshareTask(task *reshareTask) (*storage.KeygenLocalState, error) {
+	if _, ok := t.tssInstances.Load(task.msgID); ok {
+		return nil, fmt.Errorf("repeated msgID: %s", task.msgID)
+	}
+	if status, ok := t.getReshareStatus(task.msgID); ok && status != reshareCommitted {
+		return nil, fmt.Errorf("reshare %s is waiting for the commits of other parties", task.msgID)
+	}
+	t.tssInstances.Store(task.msgID, task)
+	defer t.tssInstances.Delete(task.msgID)
+
+	t.logger.WithFields(logrus.Fields{
+		"msgID":         task.msgID,
+		"old":           len(task.partyIDs[oldCommittee]),
+		"new":           len(task.partyIDs[newCommittee]),
+		"old_threshold": task.threshold,
+		"new_threshold": task.newThreshold,
+	}).Infof(">>>>>>>>>>>>>. reshare parties started")
+
+	for _, party := range task.parties {
+		go func(party btss.Party) {
+			if err := party.Start(); err != nil {
+				task.fail(fmt.Errorf("fail to start reshare party: %w", err))
+			}
+		}(party)
+	}
+
+	var (
+		save    *bkg.LocalPartySaveData
+		oldDone = task.parties[oldCommittee] == nil
+	)
+	timer := time.NewTimer(t.tssConf.KeyGenTimeout)
+	defer timer.Stop()
+	for save == nil || !oldDone {
+		select {
+		case <-t.ctx.Done():
+			return nil, fmt.Errorf("received exit signal")
+		case <-timer.C:
+			return nil, fmt.Errorf("fail to reshare key in time %s", t.tssConf.KeyGenTimeout.String())
+		case err := <-task.errCh:
+			return nil, err
+		case msg := <-task.outCh:
+			if err := t.sendReshareMsg(task, msg); err != nil {
+				return nil, err
+			}
+		case <-task.oldEnd:
+			oldDone = true
+		case data := <-task.newEnd:
+			save = &data
+		}
+	}
+
+	// The new shares are used only if all the new parties get the same result, otherwise the old shares
+	// are kept and the key is reshared again. The result of a node is the pubkey and the public shares
+	// of all the new parties, which are the same at all the nodes if resharing succeeds.
+	digest, err := reshareDigest(save)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.sendReshareDigest(task, &reshareMsg{Confirm: true, Message: digest}); err != nil {
+		return nil, err
+	}
+	confirmed := map[string]bool{strconv.FormatUint(t.localID, 10): true}
+	for len(confirmed) < len(task.partyIDs[newCommittee]) {
+		select {
+		case <-t.ctx.Done():
+			return nil, fmt.Errorf("received exit signal")
+		case <-timer.C:
+			return nil, fmt.Errorf("reshare is confirmed by %d of %d new parties in time %s",
+				len(confirmed), len(task.partyIDs[newCommittee]), t.tssConf.KeyGenTimeout.String())
+		case msg := <-task.confirmCh:
+			if !bytes.Equal(msg.Message, digest) {
+				return nil, fmt.Errorf("party %s got a different reshare result", msg.From)
+			}
+			confirmed[msg.From] = true
+		}
+	}
+
+	// A node which gets the confirms of all the new parties can't tell whether the others get them too,
+	// so a second round is run: the shares are replaced only if the node gets the commits of all the new parties,
+	// i.e. all the new parties have got the new shares and won't retry resharing. The commit of a party which
+	// is missing is asked again, and the new shares are kept pending if the commits are still missing in time.
+	state, err := task.localState(save, t.localPubK)
+	if err != nil {
+		return nil, err
+	}
+	t.stateMgrLocker.Lock()
+	base := t.keygenLocalState
+	t.stateMgrLocker.Unlock()
+	commit, err := t.signReshareMsg(task, &reshareMsg{Commit: true, Message: digest})
+	if err != nil {
+		return nil, err
+	}
+	rc := &reshareCommit{
+		task:      task,
+		commit:    commit,
+		state:     state,
+		base:      base,
+		threshold: task.newThreshold,
+	}
+	t.addReshareCommit(rc)
+	if err := t.sendReshareDigest(task, commit); err != nil {
+		return nil, err
+	}
+	committed := map[string]bool{strconv.FormatUint(t.localID, 10): true}
+	ticker := time.NewTicker(reshareQueryInterval)
+	defer ticker.Stop()
+	for len(committed) < len(task.partyIDs[newCommittee]) {
+		select {
+		case <-t.ctx.Done():
+			return nil, fmt.Errorf("received exit signal")
+		case <-timer.C:
+			t.pendReshareCommit(rc)
+			return nil, fmt.Errorf("%w: committed by %d of %d new parties in time %s", errReshareNotCommitted,
+				len(committed), len(task.partyIDs[newCommittee]), t.tssConf.KeyGenTimeout.String())
+		case <-ticker.C:
+			query := *commit
+			query.Query = true
+			for id := range task.partyIDs[newCommittee] {
+				if committed[id] {
+					continue
+				}
+				if err := t.sendReshareWireMsg(task.msgID, id, &query); err != nil {
+					return nil, err
+				}
+			}
+		case msg := <-task.commitCh:
+			if !bytes.Equal(msg.Message, digest) {
+				t.logger.WithFields(logrus.Fields{"from": msg.From}).Errorf("reshare commit of a different result")
+				continue
+			}
+			// the party has got all the commits, so all the new parties have got the new shares
+			if msg.Committed {
+				return state, nil
+			}
+			committed[msg.From] = true
+		}
+	}
+
+	return state, nil
+}
+
+// replaceShares replaces the local shares with the reshared ones
+func (t *TssMgr) replaceShares(state *storage.KeygenLocalState, threshold uint64) error {
+	if err := t.stateMgr.SaveLocalState(state); err != nil {
+		return err
+	}
+	t.stateMgrLocker.Lock()
+	t.keygenLocalState = state
+	t.stateMgrLocker.Unlock()
+	t.UpdateThreshold(threshold)
+	return nil
+}
+
+// signReshareMsg fills the sender and signature of the confirm or commit message of the local node
+func (t *TssMgr) signReshareMsg(task *reshareTask, msg *reshareMsg) (*reshareMsg, error) {
+	sig, err := conversion.GenerateSignature(msg.Message, reshareSignID(task.msgID, msg), t.localPrivK)
+	if err != nil {
+		return nil, fmt.Errorf("fail to sign reshare digest: %w", err)
+	}
+	msg.From = strconv.FormatUint(t.localID, 10)
+	msg.FromCommittee = newCommittee
+	msg.ToCommittees = []string{newCommittee}
+	msg.Sig = sig
+	return msg, nil
+}
+
+// sendReshareDigest signs the message carrying the digest of the local resharing result and sends it to
+// the other new parties
+func (t *TssMgr) sendReshareDigest(task *reshareTask, msg *reshareMsg) error {
+	if msg.Sig == nil {
+		var err error
+		if msg, err = t.signReshareMsg(task, msg); err != nil {
+			return err
+		}
+	}
+	for id := range task.partyIDs[newCommittee] {
+		if id == msg.From {
+			continue
+		}
+		if err := t.sendReshareWireMsg(task.msgID, id, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendReshareMsg signs the message produced by the local party and sends it to the nodes of receivers
+func (t *TssMgr) sendReshareMsg(task *reshareTask, msg btss.Message) error {
+	wireBytes, routing, err := msg.WireBytes()
+	if err != nil {
+		return fmt.Errorf("fail to get wire bytes from tss message: %w", err)
+	}
+	if len(routing.To) == 0 {
+		return fmt.Errorf("no receivers of reshare message %s", msg.Type())
+	}
+	sig, err := conversion.GenerateSignature(wireBytes, task.msgID, t.localPrivK)
+	if err != nil {
+		return fmt.Errorf("fail to sign reshare message: %w", err)
+	}
+
+	// group the receivers by node
+	receivers := make(map[string][]string)
+	for _, to := range routing.To {
+		receivers[to.Id] = append(receivers[to.Id], to.Moniker)
+	}
+	for id, committees := range receivers {
+		rMsg := &reshareMsg{
+			From:          routing.From.Id,
+			FromCommittee: routing.From.Moniker,
+			ToCommittees:  committees,
+			IsBroadcast:   routing.IsBroadcast,
+			Message:       wireBytes,
+			Sig:           sig,
+		}
+		if id == strconv.FormatUint(t.localID, 10) {
+			go task.handle(rMsg)
+			continue
+		}
+		if err := t.sendReshareWireMsg(task.msgID, id, rMsg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reshareCommit is the commit of a resharing round sent by the local node. It is kept for a while after the
+// round, so that the commit can be sent again to the parties missing it, and the new shares can be
+// used once a party tells it has replaced its shares if the local node doesn't get all the commits in time.
+type reshareCommit struct {
+	task      *reshareTask
+	commit    *reshareMsg
+	status    reshareStatus
+	state     *storage.KeygenLocalState
+	base      *storage.KeygenLocalState
+	threshold uint64
+}
+
+type reshareStatus int
+
+const (
+	reshareCommitting reshareStatus = iota
+	resharePending
+	reshareCommitted
+)
+
+// addReshareCommit keeps the local commit of the round until the parties missing it have given up
+func (t *TssMgr) addReshareCommit(rc *reshareCommit) {
+	t.reshareLocker.Lock()
+	defer t.reshareLocker.Unlock()
+	if t.reshareCommits == nil {
+		t.reshareCommits = make(map[string]*reshareCommit)
+	}
+	t.reshareCommits[rc.task.msgID] = rc
+
+	time.AfterFunc(2*t.tssConf.KeyGenTimeout, func() {
+		t.reshareLocker.Lock()
+		defer t.reshareLocker.Unlock()
+		if t.reshareCommits[rc.task.msgID] == rc {
+			delete(t.reshareCommits, rc.task.msgID)
+		}
+	})
+}
+
+func (t *TssMgr) getReshareCommit(msgID string) *reshareCommit {
+	t.reshareLocker.Lock()
+	defer t.reshareLocker.Unlock()
+	return t.reshareCommits[msgID]
+}
+
+func (t *TssMgr) getReshareStatus(msgID string) (reshareStatus, bool) {
+	t.reshareLocker.Lock()
+	defer t.reshareLocker.Unlock()
+	rc, ok := t.reshareCommits[msgID]
+	if !ok {
+		return 0, false
+	}
+	return rc.status, true
+}
+
+// markReshareCommitted marks the local shares replaced, the parties asking for the commit of the round
+// are told so from now on
+func (t *TssMgr) markReshareCommitted(msgID string) {
+	t.reshareLocker.Lock()
+	defer t.reshareLocker.Unlock()
+	rc, ok := t.reshareCommits[msgID]
+	if !ok {
+		return
+	}
+	committed, err := t.signReshareMsg(rc.task, &reshareMsg{Commit: true, Committed: true, Message: rc.commit.Message})
+	if err != nil {
+		t.logger.WithFields(logrus.Fields{"msgID": msgID, "err": err}).Errorf("sign reshare committed error")
+		return
+	}
+	rc.commit = committed
+	rc.status = reshareCommitted
+	rc.state = nil
+	rc.base = nil
+}
+
+// pendReshareCommit keeps the new shares of the round whose commits are missing. Other parties may have got
+// all the commits and replaced their shares, so the local node keeps asking for the missing commits and
+// replaces its shares once a party tells it has replaced its shares, see handleReshareCommit.
+// The new shares are dropped if no party has replaced its shares before the commit is dropped.
+func (t *TssMgr) pendReshareCommit(rc *reshareCommit) {
+	t.reshareLocker.Lock()
+	rc.status = resharePending
+	t.reshareLocker.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(reshareQueryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-t.ctx.Done():
+				return
+			case <-ticker.C:
+				if status, ok := t.getReshareStatus(rc.task.msgID); !ok || status != resharePending {
+					return
+				}
+				t.reshareLocker.Lock()
+				query := *rc.commit
+				t.reshareLocker.Unlock()
+				query.Query = true
+				if err := t.sendReshareDigest(rc.task, &query); err != nil {
+					t.logger.WithFields(logrus.Fields{"msgID": rc.task.msgID, "err": err}).Warnf("ask for reshare commits error")
+				}
+			}
+		}
+	}()
+}
+
+// handleReshareCommit handles the commit messages of the resharing rounds the local node has committed,
+// returns false if the message should be handled by the running task.
+// The commit of the local node is sent again to the party asking for it, and the pending shares of the round
+// replace the local shares if the party tells it has replaced its shares.
+func (t *TssMgr) handleReshareCommit(msg *pb.Message, msgID string) bool {
+	rc := t.getReshareCommit(msgID)
+	if rc == nil {
+		return false
+	}
+	wireMsg := &message.WireMessage{}
+	if err := json.Unmarshal(msg.Data, wireMsg); err != nil {
+		return false
+	}
+	rMsg := &reshareMsg{}
+	if err := json.Unmarshal(wireMsg.MsgData, rMsg); err != nil || !rMsg.Commit {
+		return false
+	}
+	if _, ok := rc.task.verify(rMsg); !ok {
+		return true
+	}
+
+	t.reshareLocker.Lock()
+	commit, status := rc.commit, rc.status
+	t.reshareLocker.Unlock()
+	if rMsg.Query {
+		if err := t.sendReshareWireMsg(msgID, rMsg.From, commit); err != nil {
+			t.logger.WithFields(logrus.Fields{"msgID": msgID, "err": err}).Warnf("send reshare commit error")
+		}
+	}
+	if _, ok := t.tssInstances.Load(msgID); ok {
+		return false
+	}
+	if status == resharePending && rMsg.Committed && bytes.Equal(rMsg.Message, commit.Message) {
+		go t.commitPendingReshare(rc)
+	}
+	return true
+}
+
+// commitPendingReshare replaces the local shares with the pending shares of the round,
+// unless the local shares have been replaced by another round
+func (t *TssMgr) commitPendingReshare(rc *reshareCommit) {
+	t.shareLocker.Lock()
+	defer t.shareLocker.Unlock()
+
+	t.reshareLocker.Lock()
+	pending := rc.status == resharePending && t.reshareCommits[rc.task.msgID] == rc
+	t.reshareLocker.Unlock()
+	t.stateMgrLocker.Lock()
+	replaced := t.keygenLocalState != rc.base
+	t.stateMgrLocker.Unlock()
+	if !pending || replaced {
+		return
+	}
+
+	if err := t.replaceShares(rc.state, rc.threshold); err != nil {
+		t.logger.WithFields(logrus.Fields{"msgID": rc.task.msgID, "err": err}).Errorf("replace shares with pending reshare error")
+		return
+	}
+	t.markReshareCommitted(rc.task.msgID)
+	t.logger.WithFields(logrus.Fields{"msgID": rc.task.msgID}).Infof("pending reshare is committed")
+}
+
+func (t *TssMgr) sendReshareWireMsg(msgID string, id string, rMsg *reshareMsg) error {
+	data, err := json.Marshal(rMsg)
+	if err != nil {
+		return err
+	}
+	wireMsg, err := json.Marshal(&message.WireMessage{
+		MsgID:   msgID,
+		MsgType: message.TSSKeyGenMsg,
+		MsgData: data,
+	})
+	if err != nil {
+		return err
+	}
+	pid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid party id %s: %w", id, err)
+	}
+	if err := t.peerMgr.AsyncSend(pid, &pb.Message{Type: pb.Message_TSS_TASK, Data: wireMsg}); err != nil {
+		t.logger.WithFields(logrus.Fields{"pid": pid, "err": err}).Warnf("send reshare message error")
+	}
+	return nil
+}
+
+func (r *reshareTask) PutTssMsg(msg *pb.Message) {
+	wireMsg := &message.WireMessage{}
+	if err := json.Unmarshal(msg.Data, wireMsg); err != nil {
+		r.logger.Errorf("wire msg unmarshal error: %v", err)
+		return
+	}
+	rMsg := &reshareMsg{}
+	if err := json.Unmarshal(wireMsg.MsgData, rMsg); err != nil {
+		r.logger.Errorf("reshare msg unmarshal error: %v", err)
+		return
+	}
+	r.handle(rMsg)
+}
+
+// verify verifies the sender of the message and returns its party id
+func (r *reshareTask) verify(msg *reshareMsg) (*btss.PartyID, bool) {
+	from, ok := r.partyIDs[msg.FromCommittee][msg.From]
+	if !ok {
+		r.logger.WithFields(logrus.Fields{"from": msg.From, "committee": msg.FromCommittee}).Errorf("reshare message from unknown party")
+		return nil, false
+	}
+	ok, err := conversion.VerifySignature(r.pubKeys[msg.From], msg.Message, msg.Sig, reshareSignID(r.msgID, msg))
+	if err != nil || !ok {
+		r.logger.WithFields(logrus.Fields{"from": msg.From, "err": err}).Errorf("fail to verify the signature of reshare message")
+		return nil, false
+	}
+	if (msg.Confirm || msg.Commit) && msg.FromCommittee != newCommittee {
+		r.logger.WithFields(logrus.Fields{"from": msg.From}).Errorf("reshare confirm from old party")
+		return nil, false
+	}
+	return from, true
+}
+
+// handle verifies the sender of the message and updates the local parties it is sent to
+func (r *reshareTask) handle(msg *reshareMsg) {
+	from, ok := r.verify(msg)
+	if !ok {
+		return
+	}
+
+	if msg.Confirm || msg.Commit {
+		ch := r.confirmCh
+		if msg.Commit {
+			ch = r.commitCh
+		}
+		select {
+		case ch <- msg:
+		default:
+		}
+		return
+	}
+
+	for _, committee := range msg.ToCommittees {
+		party, ok := r.parties[committee]
+		if !ok {
+			continue
+		}
+		if _, err := party.UpdateFromBytes(msg.Message, from, msg.IsBroadcast); err != nil {
+			r.fail(fmt.Errorf("fail to update reshare party: %w", err))
+		}
+	}
+}
+
+func (r *reshareTask) fail(err error) {
+	select {
+	case r.errCh <- err:
+	default:
+	}
+}
+
+// localState builds the local state of the reshared key, which is used by keysign as keygen result
+func (r *reshareTask) localState(save *bkg.LocalPartySaveData, localPk crypto.PubKey) (*storage.KeygenLocalState, error) {
+	ecdsaPk, err := conversion.GetTssPubKey(save.ECDSAPub)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get threshold pubkey: %w", err)
+	}
+	pubAddr, pubData, err := conversion.GetPubKeyInfoFromECDSAPubkey(ecdsaPk)
+	if err != nil {
+		return nil, fmt.Errorf("fail to convert ecdsa pubkey to pubkey addr and byte: %w", err)
+	}
+	if !bytes.Equal(pubData, r.pool.Pubkey) {
+		return nil, fmt.Errorf("tss pubkey is changed after resharing")
+	}
+
+	// restore the p2p pubkeys of new parties as the share ids, see newCommitteeKey
+	n := btss.EC().Params().N
+	for j, k := range save.Ks {
+		save.Ks[j] = new(big.Int).Sub(k, n)
+	}
+	save.ShareID = new(big.Int).Sub(save.ShareID, n)
+
+	pksMap := make(map[string][]byte, len(r.partyIDs[newCommittee]))
+	for id := range r.partyIDs[newCommittee] {
+		pkData, err := r.pubKeys[id].Raw()
+		if err != nil {
+			return nil, fmt.Errorf("fail to get pubkey bytes: %w", err)
+		}
+		pksMap[id] = new(big.Int).SetBytes(pkData).Bytes()
+	}
+	localPkData, err := localPk.Raw()
+	if err != nil {
+		return nil, fmt.Errorf("fail to get pubkey bytes: %w", err)
+	}
+
+	return &storage.KeygenLocalState{
+		PubKeyData:        pubData,
+		PubKeyAddr:        pubAddr,
+		LocalData:         *save,
+		ParticipantPksMap: pksMap,
+		LocalPartyPk:      new(big.Int).SetBytes(localPkData).Bytes(),
+	}, nil
+}
+
+// reshareSignID returns the id the digest in the message is signed with, so a confirm can't be used
+// as a commit and a commit can't be used as the commit of a node which has replaced its shares
+func reshareSignID(msgID string, msg *reshareMsg) string {
+	switch {
+	case msg.Committed:
+		return msgID + "-committed"
+	case msg.Commit:
+		return msgID + "-commit"
+	default:
+		return msgID
+	}
+}
+
+// reshareDigest returns the digest of the resharing result, i.e. the tss pubkey and the public shares
+// of the new parties, which are computed by all the new parties from the same broadcast commitments
+func reshareDigest(save *bkg.LocalPartySaveData) ([]byte, error) {
+	points := make([][2]*big.Int, 0, len(save.BigXj)+1)
+	points = append(points, [2]*big.Int{save.ECDSAPub.X(), save.ECDSAPub.Y()})
+	for _, xj := range save.BigXj {
+		points = append(points, [2]*big.Int{xj.X(), xj.Y()})
+	}
+	data, err := json.Marshal(points)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(data)
+	return hash[:], nil
+}
+
+// newCommitteeKey returns the party key of the node in the new committee.
+//
+// Keygen uses the p2p pubkey of a node as its party key, which becomes the share id that keysign
+// looks the party up by, so the new share of a node must have the p2p pubkey as share id too.
+// But a node holding a share runs a party in both committees, and tss-lib tells the committees of a
+// party apart by comparing its key with the keys of each committee (ReSharingParameters.IsOldCommittee
+// and IsNewCommittee), so the two party keys of a node must be different.
+//
+// The share of party j is the evaluation of the resharing polynomial at its key, which is computed
+// modulo the curve order N. So the p2p pubkey plus N is used as the key in the new committee, it gets
+// the same share as the p2p pubkey while differing from the key in the old committee. localState
+// subtracts N from the share ids after resharing, which restores the p2p pubkeys.
+func newCommitteeKey(pk crypto.PubKey) *big.Int {
+	raw, _ := pk.Raw()
+	key := new(big.Int).SetBytes(raw)
+	return key.Add(key, btss.EC().Params().N)
+}
+
+func sortedPartyIDs(partyIDs map[string]*btss.PartyID) btss.SortedPartyIDs {
+	ids := make(btss.UnSortedPartyIDs, 0, len(partyIDs))
+	for _, partyID := range partyIDs {
+		ids = append(ids, partyID)
+	}
+	return btss.SortPartyIDs(ids)
+}
+
+func reshareMsgID(pubkey []byte, oldIDs, newIDs btss.SortedPartyIDs, newThreshold uint64, round string) (string, error) {
+	keys := func(ids btss.SortedPartyIDs) []string {
+		ret := make([]string, 0, len(ids))
+		for _, id := range ids {
+			ret = append(ret, id.Id+":"+hex.EncodeToString(id.Key))
+		}
+		sort.Strings(ret)
+		return ret
+	}
+	data, err := json.Marshal(struct {
+		Pubkey       []byte   `json:"pubkey"`
+		Old          []string `json:"old"`
+		New          []string `json:"new"`
+		NewThreshold uint64   `json:"new_threshold"`
+		Round        string   `json:"round"`
+	}{pubkey, keys(oldIDs), keys(newIDs), newThreshold, round})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// getTssPool returns the local tss info, or the tss info held by most of other peers if there is no local state
+func (t *TssMgr) getTssPool() (*pb.TssInfo, error) {
+	t.stateMgrLocker.Lock()
+	hasLocalState := t.keygenLocalState != nil
+	t.stateMgrLocker.Unlock()
+	if hasLocalState {
+		return t.GetTssInfo()
+	}
+
+	var (
+		infos = make(map[string]*pb.TssInfo)
+		freq  = make(map[string]int)
+		wg    = sync.WaitGroup{}
+		lock  = sync.Mutex{}
+	)
+	wg.Add(len(t.peerMgr.OtherPeers()))
+	for pid := range t.peerMgr.OtherPeers() {
+		go func(pid uint64) {
+			defer wg.Done()
+			if err := retry.Retry(func(attempt uint) error {
+				info, err := t.requestTssInfoFromPeer(pid)
+				if err != nil {
+					return err
+				}
+				lock.Lock()
+				defer lock.Unlock()
+				pk := hex.EncodeToString(info.Pubkey)
+				infos[pk] = info
+				freq[pk]++
+				return nil
+			}, strategy.Limit(2), strategy.Wait(500*time.Millisecond),
+			); err != nil {
+				t.logger.WithFields(logrus.Fields{
+					"pid": pid,
+					"err": err.Error(),
+				}).Debugf("fetch tss info from peer error")
+			}
+		}(pid)
+	}
+	wg.Wait()
+
+	var (
+		pool    *pb.TssInfo
+		maxFreq int
+	)
+	for pk, counter := range freq {
+		if counter > maxFreq {
+			maxFreq = counter
+			pool = infos[pk]
+		}
+	}
+	if pool == nil {
+		return nil, fmt.Errorf("%w: there is no tss key in the network", ErrKeyNotReshareable)
+	}
+	return pool, nil
+}
+
+func (t *TssMgr) requestTssInfoFromPeer(pid uint64) (*pb.TssInfo, error) {
+	req := pb.Message{
+		Type: pb.Message_FETCH_TSS_INFO,
+	}
+
+	resp, err := t.peerMgr.Send(pid, &req)
+	if err != nil {
+		return nil, fmt.Errorf("send message to %d failed: %w", pid, err)
+	}
+	if resp == nil || resp.Type != pb.Message_FETCH_TSS_INFO_ACK {
+		return nil, fmt.Errorf("invalid fetch tss info resp")
+	}
+
+	info := &pb.TssInfo{}
+	if err := info.Unmarshal(resp.Data); err != nil {
+		return nil, fmt.Errorf("unmarshal tss info error: %w", err)
+	}
+	return info, nil
+}
@@ -0,0 +1,597 @@
+package tssmgr
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	bkg "github.com/binance-chain/tss-lib/ecdsa/keygen"
+	btss "github.com/binance-chain/tss-lib/tss"
+	"github.com/golang/mock/gomock"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	peer_mgr "github.com/meshplus/bitxhub-core/peer-mgr"
+	"github.com/meshplus/bitxhub-core/peer-mgr/mock_orderPeermgr"
+	"github.com/meshplus/bitxhub-core/tss"
+	"github.com/meshplus/bitxhub-core/tss/conversion"
+	"github.com/meshplus/bitxhub-core/tss/message"
+	"github.com/meshplus/bitxhub-core/tss/storage"
+	"github.com/meshplus/bitxhub-kit/log"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/stretchr/testify/require"
+)
+
+// testNet routes the messages among the tss managers of the test nodes
+type testNet struct {
+	mgrs  map[uint64]*TssMgr
+	peers map[string]*peer.AddrInfo
+	// drop returns true if the message sent by the node should be lost
+	drop func(from, to uint64, msg *reshareMsg) bool
+}
+
+func newTestNet(t *testing.T, n int) (*testNet, []crypto.PrivKey, []*bkg.LocalPreParams) {
+	preParams := loadTestPreParams(t)
+	require.True(t, len(preParams) >= n)
+
+	net := &testNet{
+		mgrs:  make(map[uint64]*TssMgr),
+		peers: make(map[string]*peer.AddrInfo),
+	}
+	keys := make([]crypto.PrivKey, 0, n)
+	for i := 1; i <= n; i++ {
+		key, _, err := crypto.GenerateECDSAKeyPair(rand.Reader)
+		require.Nil(t, err)
+		pid, err := peer.IDFromPrivateKey(key)
+		require.Nil(t, err)
+		keys = append(keys, key)
+		net.peers[strconv.Itoa(i)] = &peer.AddrInfo{ID: pid}
+	}
+	return net, keys, preParams[:n]
+}
+
+func loadTestPreParams(t *testing.T) []*bkg.LocalPreParams {
+	buf, err := ioutil.ReadFile("testdata/preParam_test.data")
+	require.Nil(t, err)
+	var preParams []*bkg.LocalPreParams
+	for _, item := range strings.Split(string(buf), "\n") {
+		if item == "" {
+			continue
+		}
+		data, err := hex.DecodeString(item)
+		require.Nil(t, err)
+		preParam := &bkg.LocalPreParams{}
+		require.Nil(t, json.Unmarshal(data, preParam))
+		preParams = append(preParams, preParam)
+	}
+	return preParams
+}
+
+func (net *testNet) newPeerMgr(ctrl *gomock.Controller, id uint64) peer_mgr.OrderPeerManager {
+	others := make(map[uint64]*peer.AddrInfo)
+	for k, info := range net.peers {
+		pid, _ := strconv.ParseUint(k, 10, 64)
+		if pid != id {
+			others[pid] = info
+		}
+	}
+
+	peerMgr := mock_orderPeermgr.NewMockOrderPeerManager(ctrl)
+	peerMgr.EXPECT().Peers().Return(net.peers).AnyTimes()
+	peerMgr.EXPECT().OtherPeers().Return(others).AnyTimes()
+	peerMgr.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(to peer_mgr.KeyType, msg *pb.Message) (*pb.Message, error) {
+		mgr, ok := net.mgrs[to.(uint64)]
+		if !ok {
+			return nil, fmt.Errorf("peer %v not found", to)
+		}
+		switch msg.Type {
+		case pb.Message_FETCH_P2P_PUBKEY:
+			data, err := mgr.localPubK.Raw()
+			if err != nil {
+				return nil, err
+			}
+			return &pb.Message{Type: pb.Message_FETCH_P2P_PUBKEY_ACK, Data: data}, nil
+		case pb.Message_FETCH_TSS_INFO:
+			info, err := mgr.GetTssInfo()
+			if err != nil {
+				return nil, err
+			}
+			data, err := info.Marshal()
+			if err != nil {
+				return nil, err
+			}
+			return &pb.Message{Type: pb.Message_FETCH_TSS_INFO_ACK, Data: data}, nil
+		}
+		return nil, fmt.Errorf("unexpected message %s", msg.Type)
+	}).AnyTimes()
+	peerMgr.EXPECT().AsyncSend(gomock.Any(), gomock.Any()).DoAndReturn(func(to peer_mgr.KeyType, msg *pb.Message) error {
+		mgr, ok := net.mgrs[to.(uint64)]
+		if !ok {
+			return fmt.Errorf("peer %v not found", to)
+		}
+		wireMsg := &message.WireMessage{}
+		if err := json.Unmarshal(msg.Data, wireMsg); err != nil {
+			return err
+		}
+		rMsg := &reshareMsg{}
+		if err := json.Unmarshal(wireMsg.MsgData, rMsg); err != nil {
+			return err
+		}
+		if net.drop != nil && net.drop(id, to.(uint64), rMsg) {
+			return nil
+		}
+		go mgr.PutTssMsg(msg, wireMsg.MsgID)
+		return nil
+	}).AnyTimes()
+	return peerMgr
+}
+
+func newTestTssMgr(t *testing.T, id uint64, privKey crypto.PrivKey, preParams *bkg.LocalPreParams, peerMgr peer_mgr.OrderPeerManager, threshold uint64) *TssMgr {
+	dir, err := ioutil.TempDir("", "tss")
+	require.Nil(t, err)
+	stateMgr, err := storage.NewFileStateMgr(dir)
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TssMgr{
+		localID:         id,
+		localPrivK:      privKey,
+		localPubK:       privKey.GetPublic(),
+		threshold:       threshold,
+		thresholdLocker: &sync.Mutex{},
+		tssConf:         tss.TssConfig{KeyGenTimeout: 2 * time.Minute},
+		tssRepo:         dir,
+		keygenPreParams: preParams,
+		tssInstances:    &sync.Map{},
+		peerMgr:         peerMgr,
+		stateMgr:        stateMgr,
+		stateMgrLocker:  &sync.Mutex{},
+		keyGenLocker:    &sync.Mutex{},
+		logger:          log.NewWithModule("tss"),
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+// testKeygen generates a key among the nodes in the way of keygen, the p2p pubkeys are the party keys
+func testKeygen(t *testing.T, keys []crypto.PrivKey, preParams []*bkg.LocalPreParams, threshold int) []*storage.KeygenLocalState {
+	var (
+		n       = len(keys)
+		ids     = make(btss.UnSortedPartyIDs, 0, n)
+		pksMap  = make(map[string][]byte, n)
+		parties = make(map[string]btss.Party, n)
+		outCh   = make(chan btss.Message, n*n)
+		endCh   = make(chan bkg.LocalPartySaveData, n)
+	)
+	for i, key := range keys {
+		pkData, err := key.GetPublic().Raw()
+		require.Nil(t, err)
+		id := strconv.Itoa(i + 1)
+		pksMap[id] = pkData
+		ids = append(ids, btss.NewPartyID(id, "", new(big.Int).SetBytes(pkData)))
+	}
+	sortedIDs := btss.SortPartyIDs(ids)
+	ctx := btss.NewPeerContext(sortedIDs)
+	for i, id := range sortedIDs {
+		idx, _ := strconv.Atoi(id.Id)
+		params := btss.NewParameters(ctx, sortedIDs[i], n, threshold)
+		parties[id.Id] = bkg.NewLocalParty(log.NewWithModule("tss"), params, outCh, endCh, *preParams[idx-1])
+	}
+	for _, party := range parties {
+		go func(party btss.Party) {
+			require.Nil(t, party.Start())
+		}(party)
+	}
+
+	saves := make(map[string]bkg.LocalPartySaveData, n)
+	timer := time.NewTimer(2 * time.Minute)
+	defer timer.Stop()
+	for len(saves) < n {
+		select {
+		case <-timer.C:
+			t.Fatal("keygen timeout")
+		case msg := <-outCh:
+			data, routing, err := msg.WireBytes()
+			require.Nil(t, err)
+			to := routing.To
+			if routing.IsBroadcast {
+				to = sortedIDs
+			}
+			for _, id := range to {
+				if id.Id == routing.From.Id {
+					continue
+				}
+				go func(party btss.Party) {
+					_, _ = party.UpdateFromBytes(data, routing.From, routing.IsBroadcast)
+				}(parties[id.Id])
+			}
+		case save := <-endCh:
+			for _, id := range sortedIDs {
+				if id.KeyInt().Cmp(save.ShareID) == 0 {
+					saves[id.Id] = save
+				}
+			}
+		}
+	}
+
+	states := make([]*storage.KeygenLocalState, 0, n)
+	for i := range keys {
+		id := strconv.Itoa(i + 1)
+		save := saves[id]
+		ecdsaPk, err := conversion.GetTssPubKey(save.ECDSAPub)
+		require.Nil(t, err)
+		pubAddr, pubData, err := conversion.GetPubKeyInfoFromECDSAPubkey(ecdsaPk)
+		require.Nil(t, err)
+		states = append(states, &storage.KeygenLocalState{
+			PubKeyData:        pubData,
+			PubKeyAddr:        pubAddr,
+			LocalData:         save,
+			ParticipantPksMap: pksMap,
+			LocalPartyPk:      pksMap[id],
+		})
+	}
+	return states
+}
+
+// recoverSecret recovers the private key from t+1 shares by lagrange interpolation at 0
+func recoverSecret(states []*storage.KeygenLocalState) *big.Int {
+	n := btss.EC().Params().N
+	secret := big.NewInt(0)
+	for i, si := range states {
+		xi := new(big.Int).Mod(si.LocalData.ShareID, n)
+		coef := big.NewInt(1)
+		for j, sj := range states {
+			if i == j {
+				continue
+			}
+			xj := new(big.Int).Mod(sj.LocalData.ShareID, n)
+			den := new(big.Int).Sub(xj, xi)
+			den.ModInverse(den.Mod(den, n), n)
+			coef.Mul(coef, xj).Mul(coef, den).Mod(coef, n)
+		}
+		secret.Add(secret, coef.Mul(coef, si.LocalData.Xi)).Mod(secret, n)
+	}
+	return secret
+}
+
+func TestReshare(t *testing.T) {
+	net, keys, preParams := newTestNet(t, 4)
+	states := testKeygen(t, keys[:3], preParams[:3], 1)
+	secret := recoverSecret(states[:2])
+
+	ctrl := gomock.NewController(t)
+	for i, key := range keys {
+		id := uint64(i + 1)
+		mgr := newTestTssMgr(t, id, key, preParams[i], net.newPeerMgr(ctrl, id), 1)
+		if i < len(states) {
+			mgr.keygenLocalState = states[i]
+		}
+		net.mgrs[id] = mgr
+	}
+
+	// the new node 4 joins and the threshold becomes 2
+	var wg sync.WaitGroup
+	errs := make([]error, len(keys))
+	for i := range keys {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = net.mgrs[uint64(i+1)].Reshare(2)
+		}(i)
+	}
+	wg.Wait()
+
+	newStates := make([]*storage.KeygenLocalState, 0, len(keys))
+	for i, key := range keys {
+		require.Nil(t, errs[i])
+		mgr := net.mgrs[uint64(i+1)]
+		require.Equal(t, uint64(2), mgr.GetThreshold())
+
+		state := mgr.keygenLocalState
+		require.Equal(t, states[0].PubKeyData, state.PubKeyData)
+		require.Equal(t, 4, len(state.ParticipantPksMap))
+		// the share id is the p2p pubkey as keygen
+		pkData, err := key.GetPublic().Raw()
+		require.Nil(t, err)
+		require.Equal(t, new(big.Int).SetBytes(pkData), state.LocalData.ShareID)
+		require.Equal(t, pkData, state.LocalPartyPk)
+
+		saved, err := mgr.stateMgr.GetLocalState(state.PubKeyAddr)
+		require.Nil(t, err)
+		require.Equal(t, state.LocalData.Xi, saved.LocalData.Xi)
+		newStates = append(newStates, state)
+	}
+
+	// any t+1 new shares recover the same key, while t shares are not enough
+	require.Equal(t, secret, recoverSecret(newStates[1:]))
+	require.Equal(t, secret, recoverSecret(newStates[:3]))
+	require.NotEqual(t, secret, recoverSecret(newStates[:2]))
+}
+
+func TestReshareNotConfirmed(t *testing.T) {
+	net, keys, preParams := newTestNet(t, 3)
+	states := testKeygen(t, keys, preParams, 1)
+
+	ctrl := gomock.NewController(t)
+	for i, key := range keys {
+		id := uint64(i + 1)
+		mgr := newTestTssMgr(t, id, key, preParams[i], net.newPeerMgr(ctrl, id), 1)
+		mgr.keygenLocalState = states[i]
+		mgr.tssConf.KeyGenTimeout = 30 * time.Second
+		require.Nil(t, mgr.stateMgr.SaveLocalState(states[i]))
+		net.mgrs[id] = mgr
+	}
+	// the confirms of node 3 are lost
+	net.drop = func(from, to uint64, msg *reshareMsg) bool {
+		return from == 3 && msg.Confirm
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(keys))
+	for i := range keys {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			mgr := net.mgrs[uint64(i+1)]
+			pool, err := mgr.GetTssInfo()
+			require.Nil(t, err)
+			task, err := mgr.newReshareTask(pool, 1, "refresh-1")
+			require.Nil(t, err)
+			_, errs[i] = mgr.runReshareTask(task)
+		}(i)
+	}
+	wg.Wait()
+
+	// node 3 gets all the confirms but not the commits of the others, no node replaces its shares
+	require.True(t, errors.Is(errs[2], errReshareNotCommitted))
+	for i := range keys {
+		require.NotNil(t, errs[i])
+		mgr := net.mgrs[uint64(i+1)]
+		require.Equal(t, states[i], mgr.keygenLocalState)
+		saved, err := mgr.stateMgr.GetLocalState(states[i].PubKeyAddr)
+		require.Nil(t, err)
+		require.Equal(t, states[i].LocalData.Xi, saved.LocalData.Xi)
+	}
+}
+
+func TestReshareCommitLost(t *testing.T) {
+	net, keys, preParams := newTestNet(t, 3)
+	states := testKeygen(t, keys, preParams, 1)
+	secret := recoverSecret(states[:2])
+
+	ctrl := gomock.NewController(t)
+	for i, key := range keys {
+		id := uint64(i + 1)
+		mgr := newTestTssMgr(t, id, key, preParams[i], net.newPeerMgr(ctrl, id), 1)
+		mgr.keygenLocalState = states[i]
+		mgr.tssConf.KeyGenTimeout = 90 * time.Second
+		net.mgrs[id] = mgr
+	}
+	// the first commit of node 3 to node 2 is lost, node 2 asks for it again.
+	// All the commits to node 1 are lost until it gives up.
+	var (
+		lock     sync.Mutex
+		lost     = true
+		dropped3 bool
+	)
+	net.drop = func(from, to uint64, msg *reshareMsg) bool {
+		lock.Lock()
+		defer lock.Unlock()
+		if !msg.Commit {
+			return false
+		}
+		if from == 3 && to == 2 && !dropped3 {
+			dropped3 = true
+			return true
+		}
+		return to == 1 && lost
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(keys))
+	for i := range keys {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = net.mgrs[uint64(i+1)].Refresh(1)
+		}(i)
+	}
+	wg.Wait()
+
+	// node 2 and 3 get all the commits and replace their shares, node 1 keeps the new shares pending
+	require.True(t, errors.Is(errs[0], errReshareNotCommitted))
+	require.Nil(t, errs[1])
+	require.Nil(t, errs[2])
+	require.True(t, dropped3)
+	require.Equal(t, states[0], net.mgrs[1].keygenLocalState)
+	require.NotEqual(t, states[1].LocalData.Xi, net.mgrs[2].keygenLocalState.LocalData.Xi)
+
+	// node 1 replaces its shares once it is told that node 2 or 3 has replaced their shares
+	lock.Lock()
+	lost = false
+	lock.Unlock()
+	require.Eventually(t, func() bool {
+		net.mgrs[1].stateMgrLocker.Lock()
+		defer net.mgrs[1].stateMgrLocker.Unlock()
+		return net.mgrs[1].keygenLocalState != states[0]
+	}, 10*time.Second, 100*time.Millisecond)
+
+	newStates := make([]*storage.KeygenLocalState, 0, len(keys))
+	for i := range keys {
+		mgr := net.mgrs[uint64(i+1)]
+		newStates = append(newStates, mgr.keygenLocalState)
+		saved, err := mgr.stateMgr.GetLocalState(mgr.keygenLocalState.PubKeyAddr)
+		require.Nil(t, err)
+		require.Equal(t, mgr.keygenLocalState.LocalData.Xi, saved.LocalData.Xi)
+	}
+	require.Equal(t, secret, recoverSecret(newStates[:2]))
+	require.Equal(t, secret, recoverSecret(newStates[1:]))
+}
+
+func TestRefresh(t *testing.T) {
+	net, keys, preParams := newTestNet(t, 3)
+	states := testKeygen(t, keys, preParams, 1)
+	secret := recoverSecret(states[:2])
+
+	ctrl := gomock.NewController(t)
+	for i, key := range keys {
+		id := uint64(i + 1)
+		mgr := newTestTssMgr(t, id, key, preParams[i], net.newPeerMgr(ctrl, id), 1)
+		mgr.keygenLocalState = states[i]
+		net.mgrs[id] = mgr
+	}
+
+	// node 1 is signing, the refresh waits for it
+	net.mgrs[1].shareLocker.RLock()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(keys))
+	for i := range keys {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = net.mgrs[uint64(i+1)].Refresh(1)
+		}(i)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("refresh during key sign")
+	case <-time.After(2 * time.Second):
+	}
+	net.mgrs[1].shareLocker.RUnlock()
+	<-done
+
+	newStates := make([]*storage.KeygenLocalState, 0, len(keys))
+	for i := range keys {
+		require.Nil(t, errs[i])
+		mgr := net.mgrs[uint64(i+1)]
+		require.Equal(t, uint64(1), mgr.GetThreshold())
+
+		state := mgr.keygenLocalState
+		require.Equal(t, states[i].PubKeyData, state.PubKeyData)
+		require.Equal(t, states[i].LocalData.ShareID, state.LocalData.ShareID)
+		require.NotEqual(t, states[i].LocalData.Xi, state.LocalData.Xi)
+		newStates = append(newStates, state)
+	}
+	require.Equal(t, secret, recoverSecret(newStates[1:]))
+	// the old shares can't be mixed with the new shares
+	require.NotEqual(t, secret, recoverSecret([]*storage.KeygenLocalState{states[0], newStates[1]}))
+}
+
+func TestReshareTaskHandleConfirm(t *testing.T) {
+	var keys []crypto.PrivKey
+	task := &reshareTask{
+		msgID: "msgID",
+		partyIDs: map[string]map[string]*btss.PartyID{
+			oldCommittee: {},
+			newCommittee: {},
+		},
+		pubKeys:   make(map[string]crypto.PubKey),
+		parties:   make(map[string]btss.Party),
+		confirmCh: make(chan *reshareMsg, 4),
+		commitCh:  make(chan *reshareMsg, 4),
+		errCh:     make(chan error, 1),
+		logger:    log.NewWithModule("tss"),
+	}
+	for i := 1; i <= 2; i++ {
+		key, _, err := crypto.GenerateECDSAKeyPair(rand.Reader)
+		require.Nil(t, err)
+		keys = append(keys, key)
+		id := strconv.Itoa(i)
+		task.pubKeys[id] = key.GetPublic()
+		task.partyIDs[oldCommittee][id] = btss.NewPartyID(id, oldCommittee, big.NewInt(int64(i)))
+		task.partyIDs[newCommittee][id] = btss.NewPartyID(id, newCommittee, big.NewInt(int64(i+2)))
+	}
+
+	digest := []byte("digest")
+	confirm := func(from string, committee string, key crypto.PrivKey) *reshareMsg {
+		sig, err := conversion.GenerateSignature(digest, task.msgID, key)
+		require.Nil(t, err)
+		return &reshareMsg{
+			From:          from,
+			FromCommittee: committee,
+			ToCommittees:  []string{newCommittee},
+			Confirm:       true,
+			Message:       digest,
+			Sig:           sig,
+		}
+	}
+
+	// unknown party
+	task.handle(confirm("3", newCommittee, keys[0]))
+	// signed by another node
+	task.handle(confirm("2", newCommittee, keys[0]))
+	// confirm of old committee
+	task.handle(confirm("1", oldCommittee, keys[0]))
+	require.Equal(t, 0, len(task.confirmCh))
+
+	task.handle(confirm("2", newCommittee, keys[1]))
+	require.Equal(t, 1, len(task.confirmCh))
+	msg := <-task.confirmCh
+	require.Equal(t, "2", msg.From)
+	require.Equal(t, digest, msg.Message)
+
+	// a confirm can't be used as a commit, nor a commit as the commit of a node which has replaced its shares
+	commit := confirm("2", newCommittee, keys[1])
+	commit.Commit = true
+	task.handle(commit)
+	committed := confirm("2", newCommittee, keys[1])
+	committed.Sig, _ = conversion.GenerateSignature(digest, reshareSignID(task.msgID, commit), keys[1])
+	committed.Commit, committed.Committed = true, true
+	task.handle(committed)
+	require.Equal(t, 0, len(task.commitCh))
+
+	commit.Sig, _ = conversion.GenerateSignature(digest, reshareSignID(task.msgID, commit), keys[1])
+	task.handle(commit)
+	require.Equal(t, 0, len(task.confirmCh))
+	require.Equal(t, 1, len(task.commitCh))
+	msg = <-task.commitCh
+	require.False(t, msg.Committed)
+}
+
+func TestCheckReshareable(t *testing.T) {
+	net, keys, preParams := newTestNet(t, 3)
+	ctrl := gomock.NewController(t)
+	mgr := newTestTssMgr(t, 1, keys[0], preParams[0], net.newPeerMgr(ctrl, 1), 1)
+	net.mgrs[1] = mgr
+
+	// there is no key in the network
+	_, err := mgr.getTssPool()
+	require.True(t, errors.Is(err, ErrKeyNotReshareable))
+	err = mgr.Reshare(1)
+	require.True(t, errors.Is(err, ErrKeyNotReshareable))
+
+	// node 4 and 5 holding the key left
+	pool := &pb.TssInfo{PartiesPkMap: map[string][]byte{"1": nil, "4": nil, "5": nil}}
+	require.True(t, errors.Is(mgr.checkReshareable(pool), ErrKeyNotReshareable))
+	pool.PartiesPkMap["2"] = nil
+	require.Nil(t, mgr.checkReshareable(pool))
+}
+
+func TestNewCommitteeKey(t *testing.T) {
+	key, _, err := crypto.GenerateECDSAKeyPair(rand.Reader)
+	require.Nil(t, err)
+	raw, err := key.GetPublic().Raw()
+	require.Nil(t, err)
+
+	n := btss.EC().Params().N
+	pk := new(big.Int).SetBytes(raw)
+	newKey := newCommitteeKey(key.GetPublic())
+	require.NotEqual(t, pk, newKey)
+	require.Equal(t, new(big.Int).Mod(pk, n), new(big.Int).Mod(newKey, n))
+	require.Equal(t, pk, newKey.Sub(newKey, n))
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/Rican7/retry"
 	"github.com/Rican7/retry/strategy"
 	bkg "github.com/binance-chain/tss-lib/ecdsa/keygen"
+	"github.com/ethereum/go-ethereum/event"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	peer_mgr "github.com/meshplus/bitxhub-core/peer-mgr"
@@ -22,6 +23,8 @@ import (
 	"github.com/meshplus/bitxhub-core/tss/conversion"
 	"github.com/meshplus/bitxhub-core/tss/message"
 	"github.com/meshplus/bitxhub-core/tss/storage"
+	storage2 "github.com/meshplus/bitxhub-kit/storage"
+	"github.com/meshplus/bitxhub-kit/storage/leveldb"
 	"github.com/meshplus/bitxhub-model/pb"
 	"github.com/meshplus/bitxhub/internal/repo"
 	"github.com/sirupsen/logrus"
@@ -58,6 +61,15 @@ type TssMgr struct {
 	peerMgr        peer_mgr.OrderPeerManager
 	logger         logrus.FieldLogger
 	keyGenLocker   *sync.Mutex
+	// shareLocker keeps resharing from replacing the shares during key sign
+	shareLocker sync.RWMutex
+	// msgID -> commit of the resharing round sent by the local node
+	reshareCommits map[string]*reshareCommit
+	reshareLocker  sync.Mutex
+
+	auditDB     storage2.Storage
+	auditLock   sync.Mutex
+	culpritFeed event.Feed
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -95,6 +107,12 @@ func NewTssMgr(
 		return nil, fmt.Errorf("fail to create file state manager: %w", err)
 	}
 
+	// Persistent audit log of key sign requests
+	auditDB, err := leveldb.New(filepath.Join(tssRepo, auditDirName))
+	if err != nil {
+		return nil, fmt.Errorf("fail to create tss audit storage: %w", err)
+	}
+
 	tssPools := &sync.Pool{
 		New: func() interface{} {
 			return new(tss.TssInstance)
@@ -123,6 +141,7 @@ func NewTssMgr(
 		stateMgr:        stateManager,
 		stateMgrLocker:  &sync.Mutex{},
 		keyGenLocker:    &sync.Mutex{},
+		auditDB:         auditDB,
 		logger:          logger,
 		ctx:             ctx,
 		cancel:          cancel,
@@ -276,10 +295,18 @@ func (t *TssMgr) loadTssLocalState() error {
 
 func (t *TssMgr) Stop() {
 	t.cancel()
+	if t.auditDB != nil {
+		if err := t.auditDB.Close(); err != nil {
+			t.logger.Errorf("close tss audit storage: %v", err)
+		}
+	}
 	t.logger.Info("The Tss and p2p server has been stopped successfully")
 }
 
 func (t *TssMgr) PutTssMsg(msg *pb.Message, msgID string) {
+	if t.handleReshareCommit(msg, msgID) {
+		return
+	}
 	if err := retry.Retry(func(attempt uint) error {
 		instance, ok := t.tssInstances.Load(msgID)
 		if !ok {
@@ -291,7 +318,7 @@ func (t *TssMgr) PutTssMsg(msg *pb.Message, msgID string) {
 			t.logger.WithFields(logrus.Fields{"msgID": wireMsg.MsgID, "type": wireMsg.MsgType}).Debug("load tss instance err")
 			return fmt.Errorf("tss instance not found, msgID: %s", msgID)
 		} else {
-			instance.(*tss.TssInstance).PutTssMsg(msg)
+			instance.(tssTask).PutTssMsg(msg)
 			return nil
 		}
 	}, strategy.Wait(500*time.Millisecond), strategy.Limit(5),
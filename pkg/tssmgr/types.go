@@ -14,6 +14,12 @@ type TssManager interface {
 
 	Keygen(isKeygenReq bool) error
 
+	// Reshare moves the shares of current tss key to the order peers and keeps the tss pubkey unchanged
+	Reshare(threshold uint64) error
+
+	// Refresh refreshes the shares of current tss key at the epoch and keeps the tss pubkey unchanged
+	Refresh(epoch uint64) error
+
 	KeySign(signers []string, msgs []string, randomN string) ([]byte, []string, error)
 
 	PutTssMsg(msg *pb.Message, msgID string)
@@ -29,6 +35,17 @@ type TssManager interface {
 	UpdateThreshold(threshold uint64)
 
 	GetThreshold() uint64
+
+	// GetSignAuditRecords returns the key sign requests recorded with index in [start, end]
+	GetSignAuditRecords(start, end uint64) ([]*SignAuditRecord, error)
+
+	// GetCulpritCounts returns the times each party has been blamed in key sign requests
+	GetCulpritCounts() (map[string]uint64, error)
+}
+
+// tssTask is a running tss round which handles the tss messages of other parties
+type tssTask interface {
+	PutTssMsg(msg *pb.Message)
 }
 
 type KeyRoundDoneInfo struct {
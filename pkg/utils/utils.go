@@ -18,12 +18,13 @@ import (
 	crypto3 "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/meshplus/bitxhub-core/tss/conversion"
 	"github.com/meshplus/bitxhub-core/tss/keysign"
-	crypto2 "github.com/meshplus/bitxhub-kit/crypto"
 	"github.com/meshplus/bitxhub-kit/types"
 	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
 	"github.com/meshplus/bitxhub/internal/executor/contracts"
 	"github.com/meshplus/bitxhub/internal/ledger"
+	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/meshplus/bitxhub/pkg/signer"
 	"github.com/meshplus/bitxhub/pkg/tssmgr"
 	"github.com/sirupsen/logrus"
 )
@@ -38,7 +39,7 @@ var AuditEventStrHash = types.NewHash([]byte(fmt.Sprintf("event%d%d%d%d%d%d%d%d"
 	pb.Event_AUDIT_INTERCHAIN,
 	pb.Event_AUDIT_DAPP)))
 
-func GetIBTPSign(ledger *ledger.Ledger, id string, isReq bool, privKey crypto2.PrivateKey) (string, []byte, error) {
+func GetIBTPSign(ledger *ledger.Ledger, id string, isReq bool, privKey signer.Signer) (string, []byte, error) {
 	ibtp, err := GetIBTP(ledger, id, isReq)
 	if err != nil {
 		return "", nil, fmt.Errorf("get ibtp %s isReq %v: %w", id, isReq, err)
@@ -54,7 +55,7 @@ func GetIBTPSign(ledger *ledger.Ledger, id string, isReq bool, privKey crypto2.P
 		return "", nil, fmt.Errorf("encode packed and hash for ibtp %s isReq %v: %w", id, isReq, err)
 	}
 
-	sign, err := privKey.Sign(hash)
+	sign, err := repo.Sign(privKey, hash)
 	if err != nil {
 		return "", nil, fmt.Errorf("bitxhub sign ibtp %s isReq %v: %w", id, isReq, err)
 	}
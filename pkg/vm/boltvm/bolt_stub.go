@@ -2,6 +2,8 @@ package boltvm
 
 import (
 	"encoding/json"
+	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -9,10 +11,12 @@ import (
 	"github.com/meshplus/bitxhub-core/boltvm"
 	"github.com/meshplus/bitxhub-core/validator"
 	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub-model/constant"
 	"github.com/meshplus/bitxhub-model/pb"
 	"github.com/meshplus/bitxhub/internal/ledger"
 	"github.com/meshplus/bitxhub/pkg/vm"
 	vm1 "github.com/meshplus/eth-kit/evm"
+	ledger2 "github.com/meshplus/eth-kit/ledger"
 	"github.com/sirupsen/logrus"
 )
 
@@ -73,6 +77,7 @@ func (b *BoltStubImpl) Get(key string) (bool, []byte) {
 }
 
 func (b *BoltStubImpl) Delete(key string) {
+	b.checkWritable()
 	b.ctx.Ledger.SetState(b.ctx.Callee, []byte(key), nil, b.ctx.Changer)
 }
 
@@ -87,10 +92,12 @@ func (b *BoltStubImpl) GetObject(key string, ret interface{}) bool {
 }
 
 func (b *BoltStubImpl) Set(key string, value []byte) {
+	b.checkWritable()
 	b.ctx.Ledger.SetState(b.ctx.Callee, []byte(key), value, b.ctx.Changer)
 }
 
 func (b *BoltStubImpl) Add(key string, value []byte) {
+	b.checkWritable()
 	b.ctx.Ledger.AddState(b.ctx.Callee, []byte(key), value)
 }
 
@@ -125,6 +132,7 @@ func (b *BoltStubImpl) PostInterchainEvent(event interface{}) {
 }
 
 func (b *BoltStubImpl) postEvent(eventType pb.Event_EventType, event interface{}) {
+	b.checkWritable()
 	data, err := json.Marshal(event)
 	if err != nil {
 		panic(err)
@@ -137,6 +145,13 @@ func (b *BoltStubImpl) postEvent(eventType pb.Event_EventType, event interface{}
 	})
 }
 
+// checkWritable aborts the invocation in read-only mode, the panic is recovered as the error of the vm
+func (b *BoltStubImpl) checkWritable() {
+	if b.ctx.ReadOnly {
+		panic(fmt.Sprintf("contract %s can not change state in read-only mode", b.ctx.Callee.String()))
+	}
+}
+
 func (b *BoltStubImpl) CrossInvoke(address, method string, args ...*pb.Arg) *boltvm.Response {
 	addr := types.NewAddressByStr(address)
 
@@ -154,6 +169,45 @@ func (b *BoltStubImpl) CrossInvoke(address, method string, args ...*pb.Arg) *bol
 		Tx:               b.bvm.ctx.Tx,
 		CurrentHeight:    b.bvm.ctx.CurrentHeight,
 		Logger:           b.bvm.ctx.Logger,
+		ReadOnly:         b.bvm.ctx.ReadOnly,
+	}
+
+	data, err := payload.Marshal()
+	if err != nil {
+		return boltvm.Error(boltvm.OtherInternalErrCode, err.Error())
+	}
+	bvm := New(ctx, b.ve, nil, b.bvm.contracts)
+	ret, _, err := bvm.Run(data, 0)
+	if err != nil {
+		return boltvm.Error(boltvm.OtherInternalErrCode, err.Error())
+	}
+
+	return boltvm.Success(ret)
+}
+
+// CrossInvokeAs invokes a bolt contract with the given account as both caller and current caller.
+// It is only available to the role contract which dispatches confirmed multisig txs by it.
+func (b *BoltStubImpl) CrossInvokeAs(caller, address, method string, args ...*pb.Arg) *boltvm.Response {
+	if b.ctx.Callee.String() != constant.RoleContractAddr.Address().String() {
+		return boltvm.Error(boltvm.OtherInternalErrCode, fmt.Sprintf("contract %s can not invoke on behalf of %s", b.ctx.Callee.String(), caller))
+	}
+
+	payload := &pb.InvokePayload{
+		Method: method,
+		Args:   args,
+	}
+
+	callerAddr := types.NewAddressByStr(caller)
+	ctx := &vm.Context{
+		Caller:           callerAddr,
+		Callee:           types.NewAddressByStr(address),
+		CurrentCaller:    callerAddr,
+		Ledger:           b.bvm.ctx.Ledger,
+		TransactionIndex: b.bvm.ctx.TransactionIndex,
+		Tx:               b.bvm.ctx.Tx,
+		CurrentHeight:    b.bvm.ctx.CurrentHeight,
+		Logger:           b.bvm.ctx.Logger,
+		ReadOnly:         b.bvm.ctx.ReadOnly,
 	}
 
 	data, err := payload.Marshal()
@@ -170,6 +224,10 @@ func (b *BoltStubImpl) CrossInvoke(address, method string, args ...*pb.Arg) *bol
 }
 
 func (b *BoltStubImpl) CrossInvokeEVM(address string, data []byte) *boltvm.Response {
+	if b.ctx.ReadOnly {
+		return boltvm.Error(boltvm.OtherInternalErrCode, fmt.Sprintf("contract %s can not invoke evm in read-only mode", b.ctx.Callee.String()))
+	}
+
 	addr := types.NewAddressByStr(address)
 	ctx := b.bvm.ctx
 
@@ -216,6 +274,35 @@ func (b *BoltStubImpl) ValidationEngine() validator.Engine {
 func (b *BoltStubImpl) GetAccount(address string) interface{} {
 	addr := types.NewAddressByStr(address)
 	account := b.ctx.Ledger.GetOrCreateAccount(addr)
+	if b.ctx.ReadOnly {
+		return &readOnlyAccount{IAccount: account, contract: b.ctx.Callee.String()}
+	}
 
 	return account
 }
+
+// readOnlyAccount is the account got by contracts in read-only mode, which rejects any change to the account
+type readOnlyAccount struct {
+	ledger2.IAccount
+	contract string
+}
+
+func (a *readOnlyAccount) reject() {
+	panic(fmt.Sprintf("contract %s can not change account %s in read-only mode", a.contract, a.GetAddress().String()))
+}
+
+func (a *readOnlyAccount) SetState([]byte, []byte, interface{}) { a.reject() }
+
+func (a *readOnlyAccount) AddState([]byte, []byte) { a.reject() }
+
+func (a *readOnlyAccount) SetCodeAndHash([]byte) { a.reject() }
+
+func (a *readOnlyAccount) SetNonce(uint64) { a.reject() }
+
+func (a *readOnlyAccount) SetBalance(*big.Int) { a.reject() }
+
+func (a *readOnlyAccount) SubBalance(*big.Int) { a.reject() }
+
+func (a *readOnlyAccount) AddBalance(*big.Int) { a.reject() }
+
+func (a *readOnlyAccount) SetSuicided(bool) { a.reject() }
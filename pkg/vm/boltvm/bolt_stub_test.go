@@ -133,5 +133,7 @@ func Test(t *testing.T) {
 
 	args.Type = 8
 	boltStub.CrossInvoke(constant.RuleManagerContractAddr.Address().String(), "ClearRule", args)
-
+	// only the role contract can invoke on behalf of other accounts
+	res := boltStub.CrossInvokeAs(from, constant.GovernanceContractAddr.Address().String(), "GetProposal", pb.String("id"))
+	assert.False(t, res.Ok)
 }
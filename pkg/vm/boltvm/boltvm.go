@@ -10,9 +10,12 @@ import (
 	"github.com/meshplus/bitxhub-core/agency"
 	"github.com/meshplus/bitxhub-core/boltvm"
 	"github.com/meshplus/bitxhub-core/validator"
+	"github.com/meshplus/bitxhub-kit/types"
 	"github.com/meshplus/bitxhub-model/pb"
 	"github.com/meshplus/bitxhub/internal/executor/contracts"
 	"github.com/meshplus/bitxhub/pkg/vm"
+	evm2 "github.com/meshplus/bitxhub/pkg/vm/evm"
+	"github.com/meshplus/bitxhub/pkg/vm/wasm/vmledger"
 	evm "github.com/meshplus/eth-kit/evm"
 )
 
@@ -54,6 +57,9 @@ func (bvm *BoltVM) HandleIBTP(ibtp *pb.IBTP, cache *sync.Map) (ret []byte, err e
 	}()
 
 	con := &contracts.InterchainManager{ServiceCache: cache}
+	if bvm.ctx.Tx != nil {
+		con.FencingToken = contracts.ParseFencingToken(bvm.ctx.Tx.GetPayload())
+	}
 	con.Stub = &BoltStubImpl{
 		bvm: bvm,
 		ctx: bvm.ctx,
@@ -117,6 +123,60 @@ func (bvm *BoltVM) InvokeBVM(address string, input []byte) (ret []byte, _ uint64
 	return res.Result, 0, err
 }
 
+// NewReadOnlyInvoker creates the invoker with which wasm contracts query bolt contracts,
+// the bolt contracts run in read-only mode and fail once they change any state
+func NewReadOnlyInvoker(ctx *vm.Context, ve validator.Engine, contracts map[string]agency.Contract) vmledger.CrossInvoker {
+	return func(address string, input []byte) (ret []byte, err error) {
+		bvm := New(&vm.Context{
+			Caller:           ctx.Caller,
+			Callee:           types.NewAddressByStr(address),
+			CurrentCaller:    ctx.Callee,
+			Ledger:           ctx.Ledger,
+			TransactionIndex: ctx.TransactionIndex,
+			Tx:               ctx.Tx,
+			CurrentHeight:    ctx.CurrentHeight,
+			Logger:           ctx.Logger,
+			ReadOnly:         true,
+		}, ve, nil, contracts)
+
+		snapshot := ctx.Ledger.Snapshot()
+		defer ctx.Ledger.RevertToSnapshot(snapshot)
+		ret, _, err = bvm.Run(input, 0)
+		return ret, err
+	}
+}
+
+// NewEVMInvoker creates the invoker with which evm precompiles call bolt contracts,
+// the bolt contracts invoked in read-only mode fail once they change any state
+func NewEVMInvoker(ctx *vm.Context, ve validator.Engine, contracts map[string]agency.Contract) evm2.BoltInvoker {
+	return func(caller, address string, input []byte, readOnly bool) (ret []byte, err error) {
+		defer func() {
+			if e := recover(); e != nil {
+				err = fmt.Errorf("%v", e)
+			}
+		}()
+
+		bvm := New(&vm.Context{
+			Caller:           ctx.Caller,
+			Callee:           types.NewAddressByStr(address),
+			CurrentCaller:    types.NewAddressByStr(caller),
+			Ledger:           ctx.Ledger,
+			TransactionIndex: ctx.TransactionIndex,
+			Tx:               ctx.Tx,
+			CurrentHeight:    ctx.CurrentHeight,
+			Logger:           ctx.Logger,
+			ReadOnly:         readOnly,
+		}, ve, nil, contracts)
+
+		if readOnly {
+			snapshot := ctx.Ledger.Snapshot()
+			defer ctx.Ledger.RevertToSnapshot(snapshot)
+		}
+		ret, _, err = bvm.InvokeBVM(address, input)
+		return ret, err
+	}
+}
+
 func parseArgs(in []*pb.Arg) ([]reflect.Value, error) {
 	args := make([]reflect.Value, len(in))
 	for i := 0; i < len(in); i++ {
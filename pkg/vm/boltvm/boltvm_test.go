@@ -2,6 +2,7 @@ package boltvm
 
 import (
 	"encoding/json"
+	"math/big"
 	"sync"
 	"testing"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/meshplus/bitxhub/internal/ledger"
 	"github.com/meshplus/bitxhub/internal/ledger/mock_ledger"
 	"github.com/meshplus/bitxhub/pkg/vm"
+	ledger2 "github.com/meshplus/eth-kit/ledger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -121,6 +123,99 @@ func TestBoltVM_Run(t *testing.T) {
 	require.NotNil(t, err)
 }
 
+func TestNewReadOnlyInvoker(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockEngine := mock_validator.NewMockEngine(ctr)
+	stateLedger := mock_ledger.NewMockStateLedger(ctr)
+	mockLedger := &ledger.Ledger{StateLedger: stateLedger}
+
+	tx := &pb.BxhTransaction{
+		From: types.NewAddressByStr(from),
+		To:   types.NewAddressByStr(from),
+	}
+	tx.TransactionHash = tx.Hash()
+	ctx := vm.NewContext(tx, 1, nil, 100, mockLedger, log.NewWithModule("vm"), true, nil)
+	invoker := NewReadOnlyInvoker(ctx, mockEngine, GetBoltContracts())
+	store := constant.StoreContractAddr.Address().String()
+	stateLedger.EXPECT().Snapshot().Return(1).AnyTimes()
+	stateLedger.EXPECT().RevertToSnapshot(1).AnyTimes()
+
+	// methods changing state fail whatever their names are
+	input, err := (&pb.InvokePayload{Method: "Set", Args: []*pb.Arg{pb.String("key"), pb.String("value")}}).Marshal()
+	require.Nil(t, err)
+	_, err = invoker(store, input)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "read-only mode")
+
+	stateLedger.EXPECT().GetState(gomock.Any(), []byte("key")).Return(true, []byte(`"value"`)).Times(1)
+	input, err = (&pb.InvokePayload{Method: "Get", Args: []*pb.Arg{pb.String("key")}}).Marshal()
+	require.Nil(t, err)
+	ret, err := invoker(store, input)
+	require.Nil(t, err)
+	require.Equal(t, "value", string(ret))
+}
+
+func TestNewEVMInvoker(t *testing.T) {
+	ctr := gomock.NewController(t)
+	mockEngine := mock_validator.NewMockEngine(ctr)
+	stateLedger := mock_ledger.NewMockStateLedger(ctr)
+	mockLedger := &ledger.Ledger{StateLedger: stateLedger}
+
+	tx := &pb.BxhTransaction{
+		From: types.NewAddressByStr(from),
+		To:   types.NewAddressByStr(from),
+	}
+	tx.TransactionHash = tx.Hash()
+	ctx := vm.NewContext(tx, 1, nil, 100, mockLedger, log.NewWithModule("vm"), true, nil)
+	invoker := NewEVMInvoker(ctx, mockEngine, GetBoltContracts())
+	store := constant.StoreContractAddr.Address().String()
+	input, err := (&pb.InvokePayload{Method: "Set", Args: []*pb.Arg{pb.String("key"), pb.String("value")}}).Marshal()
+	require.Nil(t, err)
+
+	stateLedger.EXPECT().Snapshot().Return(1).Times(1)
+	stateLedger.EXPECT().RevertToSnapshot(1).Times(1)
+	_, err = invoker(from, store, input, true)
+	require.NotNil(t, err)
+
+	stateLedger.EXPECT().SetState(gomock.Any(), []byte("key"), []byte(`"value"`), gomock.Any()).Times(1)
+	_, err = invoker(from, store, input, false)
+	require.Nil(t, err)
+}
+
+func TestBoltStubImpl_ReadOnly(t *testing.T) {
+	ctr := gomock.NewController(t)
+	stateLedger := mock_ledger.NewMockStateLedger(ctr)
+	account := mock_ledger.NewMockIAccount(ctr)
+	mockLedger := &ledger.Ledger{StateLedger: stateLedger}
+
+	tx := &pb.BxhTransaction{
+		From: types.NewAddressByStr(from),
+		To:   constant.StoreContractAddr.Address(),
+	}
+	tx.TransactionHash = tx.Hash()
+	ctx := vm.NewContext(tx, 1, nil, 100, mockLedger, log.NewWithModule("vm"), true, nil)
+	ctx.ReadOnly = true
+	stub := &BoltStubImpl{bvm: New(ctx, nil, nil, GetBoltContracts()), ctx: ctx}
+
+	require.Panics(t, func() { stub.Set("key", []byte("value")) })
+	require.Panics(t, func() { stub.Add("key", []byte("value")) })
+	require.Panics(t, func() { stub.Delete("key") })
+	require.Panics(t, func() { stub.PostInterchainEvent(map[string]uint64{}) })
+	require.False(t, stub.CrossInvokeEVM(to, nil).Ok)
+
+	// the read-only mode is kept by the contracts invoked
+	res := stub.CrossInvoke(constant.StoreContractAddr.Address().String(), "Set", pb.String("key"), pb.String("value"))
+	require.False(t, res.Ok)
+
+	stateLedger.EXPECT().GetOrCreateAccount(gomock.Any()).Return(account).Times(1)
+	account.EXPECT().GetAddress().Return(types.NewAddressByStr(to)).AnyTimes()
+	account.EXPECT().GetBalance().Return(big.NewInt(1)).Times(1)
+	acc := stub.GetAccount(to).(ledger2.IAccount)
+	require.Equal(t, big.NewInt(1), acc.GetBalance())
+	require.Panics(t, func() { acc.AddBalance(big.NewInt(1)) })
+	require.Panics(t, func() { acc.SetState([]byte("key"), nil, nil) })
+}
+
 func mockIBTP(t *testing.T, index uint64, typ pb.IBTP_Type) *pb.IBTP {
 	content := pb.Content{
 		Func: "set",
@@ -21,6 +21,9 @@ type Context struct {
 	Logger           logrus.FieldLogger
 	EnableAudit      bool
 	Changer          *ledger.ChangeInstance
+
+	// ReadOnly forbids the contract and contracts it invokes from changing any state
+	ReadOnly bool
 }
 
 // NewContext creates a context of wasm instance
@@ -0,0 +1,255 @@
+package evm
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	vm "github.com/meshplus/eth-kit/evm"
+	"github.com/meshplus/eth-kit/ledger"
+)
+
+// Gas charged by precompiles calling bolt contracts, which only depends on the method and input size
+const (
+	GasBoltQuery          uint64 = 2000
+	GasBoltEmitInterchain uint64 = 40000
+	GasBoltPerByte        uint64 = 16
+)
+
+// Reserved addresses of precompiles calling bolt contracts, 0xc8 is taken by the interchain log precompile
+var (
+	InterBrokerPrecompileAddr     = common.BytesToAddress([]byte{201})
+	ServiceManagerPrecompileAddr  = common.BytesToAddress([]byte{202})
+	AppchainManagerPrecompileAddr = common.BytesToAddress([]byte{203})
+)
+
+// BoltInvoker invokes the bolt contract on behalf of the evm contract with the marshaled invoke payload,
+// the bolt contract fails once it changes any state if it is invoked in read-only mode
+type BoltInvoker func(caller, address string, payload []byte, readOnly bool) ([]byte, error)
+
+// boltStateLedger is the state ledger of an evm transaction which carries the invoker of bolt contracts,
+// so that every evm calls bolt contracts in the context of its own transaction
+type boltStateLedger struct {
+	ledger.StateLedger
+	invoker BoltInvoker
+	tracer  *StaticTracer
+}
+
+// WithBoltInvoker wraps the state ledger passed to the evm, the precompiles of the evm call bolt
+// contracts with the invoker, and they fail on the evm running on a state ledger without invoker.
+// The tracer should be the tracer of the evm, bolt contracts are only invoked in read-only mode
+// if the evm is not traced by it
+func WithBoltInvoker(stateLedger ledger.StateLedger, invoker BoltInvoker, tracer *StaticTracer) ledger.StateLedger {
+	return &boltStateLedger{
+		StateLedger: stateLedger,
+		invoker:     invoker,
+		tracer:      tracer,
+	}
+}
+
+func getBoltInvoker(evm *vm.EVM) (BoltInvoker, *StaticTracer) {
+	if stateLedger, ok := evm.StateDB.(*boltStateLedger); ok {
+		return stateLedger.invoker, stateLedger.tracer
+	}
+	return nil, nil
+}
+
+// StaticTracer follows the calls of the evm to tell whether the running precompile is in the static
+// context of STATICCALL, which the interpreter keeps to itself
+type StaticTracer struct {
+	// whether each frame of the call stack runs in static context
+	frames []bool
+	// the last opcode of the innermost frame, which is the call of the running precompile
+	op vm.OpCode
+}
+
+var _ vm.Tracer = (*StaticTracer)(nil)
+
+func NewStaticTracer() *StaticTracer {
+	return &StaticTracer{}
+}
+
+// Static returns whether the precompile is called by STATICCALL or from a frame in static context
+func (t *StaticTracer) Static() bool {
+	n := len(t.frames)
+	return n > 0 && (t.frames[n-1] || t.op == vm.STATICCALL)
+}
+
+func (t *StaticTracer) CaptureStart(*vm.EVM, common.Address, common.Address, bool, []byte, uint64, *big.Int) {
+	t.frames = t.frames[:0]
+	t.op = vm.STOP
+}
+
+func (t *StaticTracer) CaptureState(_ *vm.EVM, _ uint64, op vm.OpCode, _, _ uint64, _ *vm.ScopeContext, _ []byte, depth int, _ error) {
+	// a new frame is entered by the last opcode of its parent frame
+	for len(t.frames) < depth {
+		n := len(t.frames)
+		t.frames = append(t.frames, (n > 0 && t.frames[n-1]) || t.op == vm.STATICCALL)
+	}
+	t.frames = t.frames[:depth]
+	t.op = op
+}
+
+func (t *StaticTracer) CaptureFault(*vm.EVM, uint64, vm.OpCode, uint64, uint64, *vm.ScopeContext, int, error) {
+}
+
+func (t *StaticTracer) CaptureEnd([]byte, uint64, time.Duration, error) {}
+
+type boltMethod struct {
+	abi.Method
+	gas uint64
+
+	// whether the full service id of evm caller is passed as the first argument
+	withSource bool
+
+	// whether the bolt contract is invoked in read-only mode
+	readOnly bool
+}
+
+// boltPrecompile exposes methods of the bolt contract to solidity with the abi encoding,
+// every method returns the raw result of bolt contract as bytes
+type boltPrecompile struct {
+	address string
+	methods map[string]*boltMethod
+}
+
+var _ vm.PrecompiledContract = (*boltPrecompile)(nil)
+
+func newBoltMethod(name string, gas uint64, withSource, readOnly bool, inputs ...string) *boltMethod {
+	args := make(abi.Arguments, 0, len(inputs))
+	for _, input := range inputs {
+		typ, err := abi.NewType(input, "", nil)
+		if err != nil {
+			panic(err)
+		}
+		args = append(args, abi.Argument{Type: typ})
+	}
+	bytesTyp, _ := abi.NewType("bytes", "", nil)
+
+	return &boltMethod{
+		Method:     abi.NewMethod(name, name, abi.Function, "", false, false, args, abi.Arguments{{Type: bytesTyp}}),
+		gas:        gas,
+		withSource: withSource,
+		readOnly:   readOnly,
+	}
+}
+
+func newBoltPrecompile(address string, methods ...*boltMethod) *boltPrecompile {
+	p := &boltPrecompile{
+		address: address,
+		methods: make(map[string]*boltMethod),
+	}
+	for _, m := range methods {
+		p.methods[string(m.ID)] = m
+	}
+	return p
+}
+
+func (p *boltPrecompile) method(input []byte) (*boltMethod, bool) {
+	if len(input) < 4 {
+		return nil, false
+	}
+	m, ok := p.methods[string(input[:4])]
+	return m, ok
+}
+
+func (p *boltPrecompile) RequiredGas(input []byte) uint64 {
+	gas := GasBoltQuery
+	if m, ok := p.method(input); ok {
+		gas = m.gas
+	}
+	return gas + uint64(len(input))*GasBoltPerByte
+}
+
+func (p *boltPrecompile) Run(input []byte, caller common.Address, evm *vm.EVM) ([]byte, error) {
+	m, ok := p.method(input)
+	if !ok {
+		return nil, fmt.Errorf("unknown method of bolt contract %s", p.address)
+	}
+	unpacked, err := m.Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, fmt.Errorf("unpack input of %s: %w", m.Name, err)
+	}
+
+	var args []*pb.Arg
+	if m.withSource {
+		chainID := evm.ChainConfig().ChainID.String()
+		args = append(args, pb.String(fmt.Sprintf("%s:%s:%s", chainID, chainID, caller.String())))
+	}
+	for _, v := range unpacked {
+		switch val := v.(type) {
+		case string:
+			args = append(args, pb.String(val))
+		case uint64:
+			args = append(args, pb.Uint64(val))
+		case []byte:
+			args = append(args, pb.Bytes(val))
+		case bool:
+			args = append(args, pb.Bool(val))
+		default:
+			return nil, fmt.Errorf("unsupported argument type %T of %s", v, m.Name)
+		}
+	}
+
+	invoker, tracer := getBoltInvoker(evm)
+	if invoker == nil {
+		return nil, fmt.Errorf("bolt contracts can not be invoked in current context")
+	}
+	if !m.readOnly && (tracer == nil || tracer.Static()) {
+		return nil, fmt.Errorf("invoke %s of bolt contract %s: %w", m.Name, p.address, vm.ErrWriteProtection)
+	}
+	payload, err := (&pb.InvokePayload{Method: m.Name, Args: args}).Marshal()
+	if err != nil {
+		return nil, err
+	}
+	ret, err := invoker(caller.String(), p.address, payload, m.readOnly)
+	if err != nil {
+		return nil, fmt.Errorf("invoke %s of bolt contract %s: %w", m.Name, p.address, err)
+	}
+
+	return m.Outputs.Pack(ret)
+}
+
+// boltPrecompiles are the precompiles through which solidity calls bolt contracts
+var boltPrecompiles = map[common.Address]vm.PrecompiledContract{
+	InterBrokerPrecompileAddr: newBoltPrecompile(constant.InterBrokerContractAddr.Address().String(),
+		newBoltMethod("EmitInterchain", GasBoltEmitInterchain, true, false, "string", "string", "string", "string", "string"),
+		newBoltMethod("GetInMessage", GasBoltQuery, false, true, "string", "string", "uint64"),
+		newBoltMethod("GetOutMessage", GasBoltQuery, false, true, "string", "string", "uint64"),
+	),
+	ServiceManagerPrecompileAddr: newBoltPrecompile(constant.ServiceMgrContractAddr.Address().String(),
+		newBoltMethod("GetServiceInfo", GasBoltQuery, false, true, "string"),
+		newBoltMethod("GetServicesByAppchainID", GasBoltQuery, false, true, "string"),
+	),
+	AppchainManagerPrecompileAddr: newBoltPrecompile(constant.AppchainMgrContractAddr.Address().String(),
+		newBoltMethod("GetAppchain", GasBoltQuery, false, true, "string"),
+		newBoltMethod("GetAdminByChainId", GasBoltQuery, false, true, "string"),
+		newBoltMethod("IsAvailable", GasBoltQuery, false, true, "string"),
+	),
+}
+
+var registerOnce sync.Once
+
+// RegisterBoltPrecompiles adds the precompiles calling bolt contracts to the precompiles of evm,
+// it should be called before any evm is created
+func RegisterBoltPrecompiles() {
+	registerOnce.Do(registerBoltPrecompiles)
+}
+
+func registerBoltPrecompiles() {
+	for addr, p := range boltPrecompiles {
+		vm.PrecompiledContractsHomestead[addr] = p
+		vm.PrecompiledContractsByzantium[addr] = p
+		vm.PrecompiledContractsIstanbul[addr] = p
+		vm.PrecompiledContractsBerlin[addr] = p
+		vm.PrecompiledAddressesHomestead = append(vm.PrecompiledAddressesHomestead, addr)
+		vm.PrecompiledAddressesByzantium = append(vm.PrecompiledAddressesByzantium, addr)
+		vm.PrecompiledAddressesIstanbul = append(vm.PrecompiledAddressesIstanbul, addr)
+		vm.PrecompiledAddressesBerlin = append(vm.PrecompiledAddressesBerlin, addr)
+	}
+}
@@ -0,0 +1,201 @@
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/golang/mock/gomock"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	vm "github.com/meshplus/eth-kit/evm"
+	"github.com/meshplus/eth-kit/ledger"
+	"github.com/meshplus/eth-kit/ledger/mock_ledger"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEVM(invoker BoltInvoker) *vm.EVM {
+	var stateLedger ledger.StateLedger
+	if invoker != nil {
+		stateLedger = WithBoltInvoker(nil, invoker, NewStaticTracer())
+	}
+	return vm.NewEVM(vm.BlockContext{BlockNumber: big.NewInt(1)}, vm.TxContext{}, stateLedger,
+		&params.ChainConfig{ChainID: big.NewInt(1356), ByzantiumBlock: big.NewInt(0)}, vm.Config{})
+}
+
+func TestBoltPrecompile(t *testing.T) {
+	caller := common.HexToAddress("0x2962b85e2bEe2e1eA9C4CD69f2758cF7bbc3297E")
+	var invoked *pb.InvokePayload
+	var invokedReadOnly bool
+	invoker := func(from, address string, input []byte, readOnly bool) ([]byte, error) {
+		require.Equal(t, caller.String(), from)
+		// only EmitInterchain changes state
+		invokedReadOnly = readOnly
+		invoked = &pb.InvokePayload{}
+		require.Nil(t, invoked.Unmarshal(input))
+		if address != constant.AppchainMgrContractAddr.Address().String() {
+			return []byte("ok"), nil
+		}
+		if string(invoked.Args[0].Value) == "chain0" {
+			return []byte(`{"id":"chain0"}`), nil
+		}
+		return nil, fmt.Errorf("appchain not found")
+	}
+
+	RegisterBoltPrecompiles()
+	p, ok := vm.PrecompiledContractsByzantium[AppchainManagerPrecompileAddr]
+	require.True(t, ok)
+	m := p.(*boltPrecompile).methods
+	require.Equal(t, 3, len(m))
+
+	// query the appchain
+	getAppchain := newBoltMethod("GetAppchain", GasBoltQuery, false, true, "string")
+	args, err := getAppchain.Inputs.Pack("chain0")
+	require.Nil(t, err)
+	input := append(getAppchain.ID, args...)
+	require.Equal(t, GasBoltQuery+uint64(len(input))*GasBoltPerByte, p.RequiredGas(input))
+
+	ret, _, err := vm.RunPrecompiledContract(p, input, p.RequiredGas(input), caller, newTestEVM(invoker))
+	require.Nil(t, err)
+	unpacked, err := getAppchain.Outputs.Unpack(ret)
+	require.Nil(t, err)
+	require.Equal(t, []byte(`{"id":"chain0"}`), unpacked[0])
+	require.Equal(t, "GetAppchain", invoked.Method)
+	require.True(t, invokedReadOnly)
+
+	// error of bolt contract reverts
+	args, err = getAppchain.Inputs.Pack("chain1")
+	require.Nil(t, err)
+	_, _, err = vm.RunPrecompiledContract(p, append(getAppchain.ID, args...), 100000, caller, newTestEVM(invoker))
+	require.NotNil(t, err)
+
+	// not enough gas
+	_, _, err = vm.RunPrecompiledContract(p, input, GasBoltQuery, caller, newTestEVM(invoker))
+	require.Equal(t, vm.ErrOutOfGas, err)
+
+	// unknown method
+	_, _, err = vm.RunPrecompiledContract(p, []byte{1, 2, 3, 4}, 100000, caller, newTestEVM(invoker))
+	require.NotNil(t, err)
+
+	// emit interchain with the evm caller as source service
+	p = vm.PrecompiledContractsByzantium[InterBrokerPrecompileAddr]
+	emit := newBoltMethod("EmitInterchain", GasBoltEmitInterchain, true, false, "string", "string", "string", "string", "string")
+	args, err = emit.Inputs.Pack("1356:chain1:0x01", "set,,", "a,b", "", "")
+	require.Nil(t, err)
+	input = append(emit.ID, args...)
+	require.Equal(t, GasBoltEmitInterchain+uint64(len(input))*GasBoltPerByte, p.RequiredGas(input))
+	_, _, err = vm.RunPrecompiledContract(p, input, p.RequiredGas(input), caller, newTestEVM(invoker))
+	require.Nil(t, err)
+	require.Equal(t, "EmitInterchain", invoked.Method)
+	require.False(t, invokedReadOnly)
+	require.Equal(t, 6, len(invoked.Args))
+	require.Equal(t, fmt.Sprintf("1356:1356:%s", caller.String()), string(invoked.Args[0].Value))
+	require.Equal(t, "1356:chain1:0x01", string(invoked.Args[1].Value))
+
+	// state changing methods are refused on the evm without tracer
+	noTracerEVM := newTestEVM(invoker)
+	noTracerEVM.StateDB = WithBoltInvoker(nil, invoker, nil)
+	_, _, err = vm.RunPrecompiledContract(p, input, p.RequiredGas(input), caller, noTracerEVM)
+	require.True(t, errors.Is(err, vm.ErrWriteProtection))
+
+	// no invoker outside evm transactions
+	_, _, err = vm.RunPrecompiledContract(p, input, p.RequiredGas(input), caller, newTestEVM(nil))
+	require.NotNil(t, err)
+}
+
+func TestBoltPrecompile_StaticCall(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	stateLedger := mock_ledger.NewMockStateLedger(mockCtl)
+
+	// the contract forwards its calldata to the bolt precompile and returns whether the call succeeds
+	forward := func(call byte) []byte {
+		code := []byte{
+			byte(vm.CALLDATASIZE), byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.CALLDATACOPY),
+			byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.CALLDATASIZE), byte(vm.PUSH1), 0,
+		}
+		if vm.OpCode(call) == vm.CALL {
+			code = append(code, byte(vm.PUSH1), 0)
+		}
+		code = append(code, byte(vm.PUSH1), InterBrokerPrecompileAddr.Bytes()[19], byte(vm.GAS), call,
+			byte(vm.PUSH1), 0, byte(vm.MSTORE), byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.RETURN))
+		return code
+	}
+	staticCaller := common.HexToAddress("0x1001")
+	caller := common.HexToAddress("0x1002")
+	codes := map[common.Address][]byte{
+		staticCaller: forward(byte(vm.STATICCALL)),
+		caller:       forward(byte(vm.CALL)),
+	}
+	stateLedger.EXPECT().GetEVMCode(gomock.Any()).DoAndReturn(func(addr common.Address) []byte {
+		return codes[addr]
+	}).AnyTimes()
+	stateLedger.EXPECT().GetEVMCodeHash(gomock.Any()).Return(common.Hash{}).AnyTimes()
+	stateLedger.EXPECT().ExistEVM(gomock.Any()).Return(true).AnyTimes()
+	stateLedger.EXPECT().Snapshot().Return(0).AnyTimes()
+	stateLedger.EXPECT().RevertToSnapshot(gomock.Any()).AnyTimes()
+	stateLedger.EXPECT().AddEVMBalance(gomock.Any(), gomock.Any()).AnyTimes()
+	stateLedger.EXPECT().SubEVMBalance(gomock.Any(), gomock.Any()).AnyTimes()
+
+	var invoked []string
+	invoker := func(from, address string, input []byte, readOnly bool) ([]byte, error) {
+		payload := &pb.InvokePayload{}
+		require.Nil(t, payload.Unmarshal(input))
+		invoked = append(invoked, payload.Method)
+		return []byte("ok"), nil
+	}
+
+	RegisterBoltPrecompiles()
+	tracer := NewStaticTracer()
+	evm := vm.NewEVM(vm.BlockContext{BlockNumber: big.NewInt(1), CanTransfer: vm.CanTransfer, Transfer: vm.Transfer},
+		vm.TxContext{}, WithBoltInvoker(stateLedger, invoker, tracer),
+		&params.ChainConfig{ChainID: big.NewInt(1356), EIP150Block: big.NewInt(0), ByzantiumBlock: big.NewInt(0)},
+		vm.Config{Debug: true, Tracer: tracer})
+
+	emit := newBoltMethod("EmitInterchain", GasBoltEmitInterchain, true, false, "string", "string", "string", "string", "string")
+	args, err := emit.Inputs.Pack("1356:chain1:0x01", "set,,", "a,b", "", "")
+	require.Nil(t, err)
+	emitInput := append(emit.ID, args...)
+	getInMessage := newBoltMethod("GetInMessage", GasBoltQuery, false, true, "string", "string", "uint64")
+	args, err = getInMessage.Inputs.Pack("1356:chain0:0x01", "1356:chain1:0x01", uint64(1))
+	require.Nil(t, err)
+	queryInput := append(getInMessage.ID, args...)
+
+	succeed := func(ret []byte) bool {
+		require.Equal(t, 32, len(ret))
+		return new(big.Int).SetBytes(ret).Sign() != 0
+	}
+	from := vm.AccountRef(common.HexToAddress("0x1003"))
+
+	// emitting interchain by STATICCALL fails without invoking the bolt contract
+	ret, _, err := evm.Call(from, staticCaller, emitInput, 1000000, big.NewInt(0))
+	require.Nil(t, err)
+	require.False(t, succeed(ret))
+	require.Empty(t, invoked)
+
+	// queries are allowed by STATICCALL
+	ret, _, err = evm.Call(from, staticCaller, queryInput, 1000000, big.NewInt(0))
+	require.Nil(t, err)
+	require.True(t, succeed(ret))
+	require.Equal(t, []string{"GetInMessage"}, invoked)
+
+	// the static context is left once STATICCALL returns
+	ret, _, err = evm.Call(from, caller, emitInput, 1000000, big.NewInt(0))
+	require.Nil(t, err)
+	require.True(t, succeed(ret))
+	require.Equal(t, []string{"GetInMessage", "EmitInterchain"}, invoked)
+
+	// the contract called by STATICCALL inherits the static context
+	codes[common.HexToAddress("0x1004")] = []byte{
+		byte(vm.CALLDATASIZE), byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.CALLDATACOPY),
+		byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.CALLDATASIZE), byte(vm.PUSH1), 0,
+		byte(vm.PUSH2), caller.Bytes()[18], caller.Bytes()[19], byte(vm.GAS), byte(vm.STATICCALL),
+		byte(vm.POP), byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.RETURN),
+	}
+	ret, _, err = evm.Call(from, common.HexToAddress("0x1004"), emitInput, 1000000, big.NewInt(0))
+	require.Nil(t, err)
+	require.False(t, succeed(ret))
+	require.Equal(t, []string{"GetInMessage", "EmitInterchain"}, invoked)
+}
@@ -0,0 +1,268 @@
+package vmledger
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bytecodealliance/wasmtime-go"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	appchainMgr "github.com/meshplus/bitxhub-core/appchain-mgr"
+	"github.com/meshplus/bitxhub-core/wasm"
+	"github.com/meshplus/bitxhub-core/wasm/wasmlib"
+	"github.com/meshplus/bitxhub-model/constant"
+	ledger1 "github.com/meshplus/bitxhub/internal/ledger"
+	"github.com/meshplus/bitxhub/pkg/crypto/sm2"
+	"golang.org/x/crypto/sha3"
+)
+
+// Gas charged by host functions, which is consumed from the fuel of wasm instance
+const (
+	GasVerifySecp256k1 uint64 = 3000
+	GasVerifyEd25519   uint64 = 2000
+	GasVerifySM2       uint64 = 3000
+	GasHashBase        uint64 = 30
+	GasHashPerWord     uint64 = 6
+	GasReadBase        uint64 = 800
+	GasReadPerByte     uint64 = 3
+	GasCrossInvoke     uint64 = 5000
+)
+
+// CrossInvoker invokes the bolt contract in read-only mode with the marshaled invoke payload,
+// the invocation fails once the bolt contract changes any state
+type CrossInvoker func(address string, payload []byte) ([]byte, error)
+
+func consumeGas(store *wasmtime.Store, gas uint64) *wasmtime.Trap {
+	if _, err := store.ConsumeFuel(gas); err != nil {
+		return wasmtime.NewTrap(fmt.Sprintf("out of gas: %v", err))
+	}
+	return nil
+}
+
+func hashGas(data []byte) uint64 {
+	return GasHashBase + uint64((len(data)+31)/32)*GasHashPerWord
+}
+
+// readArg reads the argument whose length is set by set_data
+func readArg(context map[string]interface{}, mem []byte, ptr int32) ([]byte, error) {
+	argmap := context[wasm.CONTEXT_ARGMAP].(map[int32]int32)
+	length, ok := argmap[ptr]
+	if !ok {
+		return nil, fmt.Errorf("length of argument %d is not set", ptr)
+	}
+	if ptr < 0 || length < 0 || int(ptr)+int(length) > len(mem) {
+		return nil, fmt.Errorf("argument %d out of memory", ptr)
+	}
+	return mem[ptr : ptr+length], nil
+}
+
+// readArgs reads all the arguments, it sets the error into context if any argument is invalid
+func readArgs(context map[string]interface{}, caller *wasmtime.Caller, store *wasmtime.Store, ptrs ...int32) ([][]byte, bool) {
+	mem := caller.GetExport("memory").Memory().UnsafeData(store)
+	args := make([][]byte, 0, len(ptrs))
+	for _, ptr := range ptrs {
+		arg, err := readArg(context, mem, ptr)
+		if err != nil {
+			context[wasm.ERROR] = err
+			return nil, false
+		}
+		args = append(args, arg)
+	}
+	return args, true
+}
+
+func boolToInt32(ok bool) int32 {
+	if ok {
+		return 1
+	}
+	return 0
+}
+
+// verifySecp256k1 verifies the 64 or 65 bytes signature with the compressed or uncompressed public key
+func verifySecp256k1(context map[string]interface{}, store *wasmtime.Store) *wasmlib.ImportLib {
+	return &wasmlib.ImportLib{
+		Module: "env",
+		Name:   "verify_secp256k1",
+		Func: func(caller *wasmtime.Caller, sig_ptr int32, digest_ptr int32, pubkey_ptr int32) (int32, *wasmtime.Trap) {
+			if trap := consumeGas(store, GasVerifySecp256k1); trap != nil {
+				return -1, trap
+			}
+			args, ok := readArgs(context, caller, store, sig_ptr, digest_ptr, pubkey_ptr)
+			if !ok {
+				return -1, nil
+			}
+			sig, digest, pubkey := args[0], args[1], args[2]
+			if len(sig) == 65 {
+				sig = sig[:64]
+			}
+			return boolToInt32(len(sig) == 64 && ethcrypto.VerifySignature(pubkey, digest, sig)), nil
+		},
+	}
+}
+
+func verifyEd25519(context map[string]interface{}, store *wasmtime.Store) *wasmlib.ImportLib {
+	return &wasmlib.ImportLib{
+		Module: "env",
+		Name:   "verify_ed25519",
+		Func: func(caller *wasmtime.Caller, sig_ptr int32, msg_ptr int32, pubkey_ptr int32) (int32, *wasmtime.Trap) {
+			if trap := consumeGas(store, GasVerifyEd25519); trap != nil {
+				return -1, trap
+			}
+			args, ok := readArgs(context, caller, store, sig_ptr, msg_ptr, pubkey_ptr)
+			if !ok {
+				return -1, nil
+			}
+			sig, msg, pubkey := args[0], args[1], args[2]
+			return boolToInt32(len(pubkey) == ed25519.PublicKeySize && ed25519.Verify(pubkey, msg, sig)), nil
+		},
+	}
+}
+
+// verifySM2 verifies the DER encoded signature of SM2 keys with the uncompressed public key
+func verifySM2(context map[string]interface{}, store *wasmtime.Store) *wasmlib.ImportLib {
+	return &wasmlib.ImportLib{
+		Module: "env",
+		Name:   "verify_sm2",
+		Func: func(caller *wasmtime.Caller, sig_ptr int32, digest_ptr int32, pubkey_ptr int32) (int32, *wasmtime.Trap) {
+			if trap := consumeGas(store, GasVerifySM2); trap != nil {
+				return -1, trap
+			}
+			args, ok := readArgs(context, caller, store, sig_ptr, digest_ptr, pubkey_ptr)
+			if !ok {
+				return -1, nil
+			}
+			sig, digest, pubkey := args[0], args[1], args[2]
+			pub, err := sm2.UnmarshalPublicKey(pubkey)
+			if err != nil {
+				return 0, nil
+			}
+			ok, err = pub.Verify(digest, sig)
+			return boolToInt32(err == nil && ok), nil
+		},
+	}
+}
+
+func sha3Hash(context map[string]interface{}, store *wasmtime.Store) *wasmlib.ImportLib {
+	return hashLib(context, store, "sha3_256", func(data []byte) []byte {
+		hash := sha3.Sum256(data)
+		return hash[:]
+	})
+}
+
+func keccak256Hash(context map[string]interface{}, store *wasmtime.Store) *wasmlib.ImportLib {
+	return hashLib(context, store, "keccak256", func(data []byte) []byte {
+		return ethcrypto.Keccak256(data)
+	})
+}
+
+func hashLib(context map[string]interface{}, store *wasmtime.Store, name string, hash func([]byte) []byte) *wasmlib.ImportLib {
+	return &wasmlib.ImportLib{
+		Module: "env",
+		Name:   name,
+		Func: func(caller *wasmtime.Caller, data_ptr int32) (int32, *wasmtime.Trap) {
+			args, ok := readArgs(context, caller, store, data_ptr)
+			if !ok {
+				return -1, nil
+			}
+			if trap := consumeGas(store, hashGas(args[0])); trap != nil {
+				return -1, trap
+			}
+			inputPointer, err := setBytes(caller, store, hash(args[0]))
+			if err != nil {
+				context[wasm.ERROR] = err
+				return -1, nil
+			}
+			return inputPointer, nil
+		},
+	}
+}
+
+// getTrustRoot returns the trust root of the registered appchain
+func getTrustRoot(context map[string]interface{}, store *wasmtime.Store) *wasmlib.ImportLib {
+	return &wasmlib.ImportLib{
+		Module: "env",
+		Name:   "get_trust_root",
+		Func: func(caller *wasmtime.Caller, chain_id_ptr int32) (int32, *wasmtime.Trap) {
+			if trap := consumeGas(store, GasReadBase); trap != nil {
+				return -1, trap
+			}
+			args, ok := readArgs(context, caller, store, chain_id_ptr)
+			if !ok {
+				return -1, nil
+			}
+			ledger := context[LEDGER].(*ledger1.Ledger)
+			ok, data := ledger.GetState(constant.AppchainMgrContractAddr.Address(), []byte(appchainMgr.AppchainKey(string(args[0]))))
+			if !ok {
+				context[wasm.ERROR] = fmt.Errorf("appchain %s is not registered", string(args[0]))
+				return -1, nil
+			}
+			app := &appchainMgr.Appchain{}
+			if err := json.Unmarshal(data, app); err != nil {
+				context[wasm.ERROR] = fmt.Errorf("unmarshal appchain error: %w", err)
+				return -1, nil
+			}
+			if trap := consumeGas(store, uint64(len(app.TrustRoot))*GasReadPerByte); trap != nil {
+				return -1, trap
+			}
+			inputPointer, err := setBytes(caller, store, app.TrustRoot)
+			if err != nil {
+				context[wasm.ERROR] = err
+				return -1, nil
+			}
+			return inputPointer, nil
+		},
+	}
+}
+
+// crossInvoke invokes the bolt contract in read-only mode, the payload is the marshaled pb.InvokePayload
+func crossInvoke(context map[string]interface{}, store *wasmtime.Store) *wasmlib.ImportLib {
+	return &wasmlib.ImportLib{
+		Module: "env",
+		Name:   "cross_invoke",
+		Func: func(caller *wasmtime.Caller, addr_ptr int32, payload_ptr int32) (int32, *wasmtime.Trap) {
+			if trap := consumeGas(store, GasCrossInvoke); trap != nil {
+				return -1, trap
+			}
+			args, ok := readArgs(context, caller, store, addr_ptr, payload_ptr)
+			if !ok {
+				return -1, nil
+			}
+			invoker, ok := context[CROSS_INVOKER].(CrossInvoker)
+			if !ok {
+				context[wasm.ERROR] = fmt.Errorf("cross invoke is not supported")
+				return -1, nil
+			}
+			ret, err := invoker(string(args[0]), args[1])
+			if err != nil {
+				context[wasm.ERROR] = err
+				return -1, nil
+			}
+			if trap := consumeGas(store, uint64(len(ret))*GasReadPerByte); trap != nil {
+				return -1, trap
+			}
+			inputPointer, err := setBytes(caller, store, ret)
+			if err != nil {
+				context[wasm.ERROR] = err
+				return -1, nil
+			}
+			return inputPointer, nil
+		},
+	}
+}
+
+func ImportHostLib(context map[string]interface{}, store *wasmtime.Store) []*wasmlib.ImportLib {
+	return append(ImportRuleLib(context, store), crossInvoke(context, store))
+}
+
+// ImportRuleLib imports the host functions available to validation rules, which only read the ledger
+func ImportRuleLib(context map[string]interface{}, store *wasmtime.Store) []*wasmlib.ImportLib {
+	var libs []*wasmlib.ImportLib
+	libs = append(libs, verifySecp256k1(context, store))
+	libs = append(libs, verifyEd25519(context, store))
+	libs = append(libs, verifySM2(context, store))
+	libs = append(libs, sha3Hash(context, store))
+	libs = append(libs, keccak256Hash(context, store))
+	libs = append(libs, getTrustRoot(context, store))
+
+	return libs
+}
@@ -0,0 +1,192 @@
+package vmledger
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/bytecodealliance/wasmtime-go"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang/mock/gomock"
+	appchainMgr "github.com/meshplus/bitxhub-core/appchain-mgr"
+	"github.com/meshplus/bitxhub-core/wasm"
+	"github.com/meshplus/bitxhub-kit/types"
+	"github.com/meshplus/bitxhub-model/constant"
+	"github.com/meshplus/bitxhub-model/pb"
+	"github.com/meshplus/bitxhub/internal/ledger"
+	"github.com/meshplus/bitxhub/internal/ledger/mock_ledger"
+	"github.com/meshplus/bitxhub/pkg/crypto/sm2"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+)
+
+const hostGasLimit = 5000000
+
+const hostTestWat = `
+(module
+  (import "env" "set_result" (func $set_result (param i32 i32)))
+  (import "env" "keccak256" (func $keccak256 (param i32) (result i32)))
+  (import "env" "sha3_256" (func $sha3_256 (param i32) (result i32)))
+  (import "env" "verify_ed25519" (func $verify_ed25519 (param i32 i32 i32) (result i32)))
+  (import "env" "verify_secp256k1" (func $verify_secp256k1 (param i32 i32 i32) (result i32)))
+  (import "env" "verify_sm2" (func $verify_sm2 (param i32 i32 i32) (result i32)))
+  (import "env" "get_trust_root" (func $get_trust_root (param i32) (result i32)))
+  (import "env" "cross_invoke" (func $cross_invoke (param i32 i32) (result i32)))
+  (memory (export "memory") 1)
+  (global $heap (mut i32) (i32.const 1024))
+  (func (export "allocate") (param $size i32) (result i32)
+    (local $ptr i32)
+    (local.set $ptr (global.get $heap))
+    (global.set $heap (i32.add (global.get $heap) (local.get $size)))
+    (local.get $ptr))
+  (func (export "deallocate") (param i32 i32))
+  (func (export "keccak") (param $data i32) (result i32)
+    (call $set_result (call $keccak256 (local.get $data)) (i32.const 32))
+    (i32.const 0))
+  (func (export "sha3") (param $data i32) (result i32)
+    (call $set_result (call $sha3_256 (local.get $data)) (i32.const 32))
+    (i32.const 0))
+  (func (export "ed25519") (param $sig i32) (param $msg i32) (param $pubkey i32) (result i32)
+    (call $verify_ed25519 (local.get $sig) (local.get $msg) (local.get $pubkey)))
+  (func (export "secp256k1") (param $sig i32) (param $digest i32) (param $pubkey i32) (result i32)
+    (call $verify_secp256k1 (local.get $sig) (local.get $digest) (local.get $pubkey)))
+  (func (export "sm2") (param $sig i32) (param $digest i32) (param $pubkey i32) (result i32)
+    (call $verify_sm2 (local.get $sig) (local.get $digest) (local.get $pubkey)))
+  (func (export "trust_root") (param $chain_id i32) (param $len i32) (result i32)
+    (call $set_result (call $get_trust_root (local.get $chain_id)) (local.get $len))
+    (i32.const 0))
+  (func (export "query") (param $addr i32) (param $payload i32) (param $len i32) (result i32)
+    (call $set_result (call $cross_invoke (local.get $addr) (local.get $payload)) (local.get $len))
+    (i32.const 0)))
+`
+
+func newHostTestWasm(t *testing.T, l *ledger.Ledger, invoker CrossInvoker) *wasm.Wasm {
+	code, err := wasmtime.Wat2Wasm(hostTestWat)
+	require.Nil(t, err)
+
+	context := make(map[string]interface{})
+	store := wasm.NewStore()
+	w, err := wasm.NewWithStore(code, context, NewLedgerWasmLibs(context, store), store)
+	require.Nil(t, err)
+	w.SetContext(LEDGER, l)
+	if invoker != nil {
+		w.SetContext(CROSS_INVOKER, invoker)
+	}
+	return w
+}
+
+func execute(t *testing.T, w *wasm.Wasm, method string, args ...*pb.Arg) ([]byte, uint64, error) {
+	payload, err := (&pb.InvokePayload{Method: method, Args: args}).Marshal()
+	require.Nil(t, err)
+	return w.Execute(payload, hostGasLimit)
+}
+
+func TestHostLib_Hash(t *testing.T) {
+	data := []byte("bitxhub")
+
+	ret, gasUsed, err := execute(t, newHostTestWasm(t, nil, nil), "keccak", pb.Bytes(data))
+	require.Nil(t, err)
+	require.Equal(t, ethcrypto.Keccak256(data), ret)
+	require.True(t, gasUsed >= GasHashBase+GasHashPerWord)
+
+	ret, _, err = execute(t, newHostTestWasm(t, nil, nil), "sha3", pb.Bytes(data))
+	require.Nil(t, err)
+	hash := sha3.Sum256(data)
+	require.Equal(t, hash[:], ret)
+}
+
+func TestHostLib_VerifySignature(t *testing.T) {
+	msg := []byte("ibtp")
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	sig := ed25519.Sign(priv, msg)
+
+	ret, gasUsed, err := execute(t, newHostTestWasm(t, nil, nil), "ed25519", pb.Bytes(sig), pb.Bytes(msg), pb.Bytes(pub))
+	require.Nil(t, err)
+	require.Equal(t, "1", string(ret))
+	require.True(t, gasUsed >= GasVerifyEd25519)
+
+	ret, _, err = execute(t, newHostTestWasm(t, nil, nil), "ed25519", pb.Bytes(sig), pb.Bytes([]byte("another")), pb.Bytes(pub))
+	require.Nil(t, err)
+	require.Equal(t, "0", string(ret))
+
+	key, err := ethcrypto.GenerateKey()
+	require.Nil(t, err)
+	digest := ethcrypto.Keccak256(msg)
+	secpSig, err := ethcrypto.Sign(digest, key)
+	require.Nil(t, err)
+	ret, _, err = execute(t, newHostTestWasm(t, nil, nil), "secp256k1", pb.Bytes(secpSig), pb.Bytes(digest), pb.Bytes(ethcrypto.CompressPubkey(&key.PublicKey)))
+	require.Nil(t, err)
+	require.Equal(t, "1", string(ret))
+
+	sm2Key, err := sm2.New()
+	require.Nil(t, err)
+	sm2Sig, err := sm2Key.Sign(digest)
+	require.Nil(t, err)
+	sm2Pub, err := sm2Key.PublicKey().Bytes()
+	require.Nil(t, err)
+	ret, gasUsed, err = execute(t, newHostTestWasm(t, nil, nil), "sm2", pb.Bytes(sm2Sig), pb.Bytes(digest), pb.Bytes(sm2Pub))
+	require.Nil(t, err)
+	require.Equal(t, "1", string(ret))
+	require.True(t, gasUsed >= GasVerifySM2)
+
+	// the signature is verified with the given public key instead of the one carried in it
+	otherKey, err := sm2.New()
+	require.Nil(t, err)
+	otherPub, err := otherKey.PublicKey().Bytes()
+	require.Nil(t, err)
+	ret, _, err = execute(t, newHostTestWasm(t, nil, nil), "sm2", pb.Bytes(sm2Sig), pb.Bytes(digest), pb.Bytes(otherPub))
+	require.Nil(t, err)
+	require.Equal(t, "0", string(ret))
+
+	// running out of gas traps the host call
+	payload, err := (&pb.InvokePayload{Method: "secp256k1", Args: []*pb.Arg{pb.Bytes(secpSig), pb.Bytes(digest), pb.Bytes(ethcrypto.FromECDSAPub(&key.PublicKey))}}).Marshal()
+	require.Nil(t, err)
+	_, _, err = newHostTestWasm(t, nil, nil).Execute(payload, GasVerifySecp256k1)
+	require.NotNil(t, err)
+}
+
+func TestHostLib_GetTrustRoot(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	stateLedger := mock_ledger.NewMockStateLedger(mockCtl)
+	l := &ledger.Ledger{StateLedger: stateLedger}
+
+	trustRoot := []byte(`{"pub_keys":[],"threshold":1}`)
+	data, err := json.Marshal(&appchainMgr.Appchain{ID: "chain0", TrustRoot: trustRoot})
+	require.Nil(t, err)
+	stateLedger.EXPECT().GetState(gomock.Any(), gomock.Any()).DoAndReturn(func(addr *types.Address, key []byte) (bool, []byte) {
+		if addr.String() == constant.AppchainMgrContractAddr.Address().String() && string(key) == appchainMgr.AppchainKey("chain0") {
+			return true, data
+		}
+		return false, nil
+	}).AnyTimes()
+
+	ret, gasUsed, err := execute(t, newHostTestWasm(t, l, nil), "trust_root", pb.Bytes([]byte("chain0")), pb.Int32(int32(len(trustRoot))))
+	require.Nil(t, err)
+	require.Equal(t, trustRoot, ret)
+	require.True(t, gasUsed >= GasReadBase+uint64(len(trustRoot))*GasReadPerByte)
+}
+
+func TestHostLib_CrossInvoke(t *testing.T) {
+	addr := constant.AppchainMgrContractAddr.Address().String()
+	invoker := func(address string, input []byte) ([]byte, error) {
+		payload := &pb.InvokePayload{}
+		if err := payload.Unmarshal(input); err != nil {
+			return nil, err
+		}
+		if address != addr {
+			return nil, fmt.Errorf("contract %s is not found", address)
+		}
+		return []byte(payload.Method + strconv.Itoa(len(payload.Args))), nil
+	}
+
+	input, err := (&pb.InvokePayload{Method: "GetAppchain", Args: []*pb.Arg{pb.String("chain0")}}).Marshal()
+	require.Nil(t, err)
+	ret, gasUsed, err := execute(t, newHostTestWasm(t, nil, invoker), "query", pb.Bytes([]byte(addr)), pb.Bytes(input), pb.Int32(12))
+	require.Nil(t, err)
+	require.Equal(t, "GetAppchain1", string(ret))
+	require.True(t, gasUsed >= GasCrossInvoke)
+}
@@ -13,8 +13,9 @@ const (
 	CURRENT_HEIGHT = "current_height"
 	CALLER         = "caller"
 	CURRENT_CALLER = "current_caller"
+	CROSS_INVOKER  = "cross_invoker"
 )
 
 func NewLedgerWasmLibs(context map[string]interface{}, store *wasmtime.Store) []*wasmlib.ImportLib {
-	return ImportLedgerLib(context, store)
+	return append(ImportLedgerLib(context, store), ImportHostLib(context, store)...)
 }
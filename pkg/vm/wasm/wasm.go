@@ -16,7 +16,12 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-const GasXVMDeploy = 21000 * 10
+const (
+	GasXVMDeploy = 21000 * 10
+
+	// MigrateMethod is the method run once after the code of contract is upgraded
+	MigrateMethod = "migrate"
+)
 
 var _ vm.VM = (*WasmVM)(nil)
 
@@ -27,6 +32,9 @@ type WasmVM struct {
 
 	// wasm
 	w *wasm.Wasm
+
+	// deployed contract
+	contract *Contract
 }
 
 // Contract represents the smart contract structure used in the wasm vm
@@ -36,6 +44,15 @@ type Contract struct {
 
 	// contract hash
 	Hash types.Hash
+
+	// account which deployed the contract and can upgrade its code
+	Owner string
+
+	// code version, which is increased on every upgrade
+	Version uint64
+
+	// whether the migrate method should be run before the next invocation
+	Migrating bool
 }
 
 func NewStore() *wasmtime.Store {
@@ -56,7 +73,7 @@ func New(ctx *vm.Context, libs []*wasmlib.ImportLib, context map[string]interfac
 	if contractByte == nil {
 		return nil, fmt.Errorf("this rule address %s does not exist", ctx.Callee)
 	}
-	contract := &wasm.Contract{}
+	contract := &Contract{}
 	if err := json.Unmarshal(contractByte, contract); err != nil {
 		return nil, fmt.Errorf("contract byte not correct")
 	}
@@ -72,6 +89,7 @@ func New(ctx *vm.Context, libs []*wasmlib.ImportLib, context map[string]interfac
 	w.SetContext(vmledger.CALLER, ctx.Caller.String())
 	w.SetContext(vmledger.CURRENT_CALLER, ctx.CurrentCaller.String())
 	wasmVM.w = w
+	wasmVM.contract = contract
 
 	return wasmVM, nil
 }
@@ -82,19 +100,48 @@ func (w *WasmVM) Run(input []byte, gasLimit uint64) (ret []byte, gasUsed uint64,
 		return w.deploy()
 	}
 
+	if w.contract.Migrating {
+		fuel, err := w.migrate(gasLimit)
+		if err != nil {
+			gasUsed, _ = w.w.Store.FuelConsumed()
+			return nil, gasUsed, fmt.Errorf("migrate contract %s: %w", w.ctx.Callee.String(), err)
+		}
+		// the rest fuel of migration is left for the invocation
+		gasLimit -= fuel
+	}
+
 	return w.w.Execute(input, gasLimit)
 }
 
+// migrate runs the migrate method of upgraded contract if it is exported, and returns the fuel added for it
+func (w *WasmVM) migrate(gasLimit uint64) (uint64, error) {
+	var fuel uint64
+	if migrate := w.w.Instance.GetFunc(w.w.Store, MigrateMethod); migrate != nil {
+		if err := w.w.Store.AddFuel(gasLimit); err != nil {
+			return 0, err
+		}
+		fuel = gasLimit
+		w.w.SetContext(wasm.CONTEXT_ARGMAP, make(map[int32]int32))
+		if _, err := migrate.Call(w.w.Store); err != nil {
+			return fuel, err
+		}
+	}
+
+	w.contract.Migrating = false
+	data, err := json.Marshal(w.contract)
+	if err != nil {
+		return fuel, fmt.Errorf("marshal wasm struct error: %w", err)
+	}
+	w.ctx.Ledger.SetCode(w.ctx.Callee, data)
+	return fuel, nil
+}
+
 func (w *WasmVM) deploy() ([]byte, uint64, error) {
 	w.ctx.Logger.WithFields(logrus.Fields{}).Info("Rule is deploying")
 	if len(w.ctx.TransactionData.Payload) == 0 {
 		return nil, 0, fmt.Errorf("contract cannot be empty")
 	}
-	context := make(map[string]interface{})
-	store := wasm.NewStore()
-	libs := vmledger.NewLedgerWasmLibs(context, store)
-	_, err := wasm.NewWithStore(w.ctx.TransactionData.Payload, context, libs, store)
-	if err != nil {
+	if err := ValidateCode(w.ctx.TransactionData.Payload); err != nil {
 		w.ctx.Logger.WithFields(logrus.Fields{}).Error("new instance:", err)
 		return nil, 0, err
 	}
@@ -102,8 +149,10 @@ func (w *WasmVM) deploy() ([]byte, uint64, error) {
 
 	contractAddr := createAddress(w.ctx.Caller, contractNonce)
 	wasmStruct := &Contract{
-		Code: w.ctx.TransactionData.Payload,
-		Hash: *types.NewHash(w.ctx.TransactionData.Payload),
+		Code:    w.ctx.TransactionData.Payload,
+		Hash:    *CodeHash(w.ctx.TransactionData.Payload),
+		Owner:   w.ctx.Caller.String(),
+		Version: 1,
 	}
 	wasmByte, err := json.Marshal(wasmStruct)
 	if err != nil {
@@ -116,6 +165,21 @@ func (w *WasmVM) deploy() ([]byte, uint64, error) {
 	return contractAddr.Bytes(), GasXVMDeploy, nil
 }
 
+// CodeHash returns the sha256 digest of the contract code
+func CodeHash(code []byte) *types.Hash {
+	digest := sha256.Sum256(code)
+	return types.NewHash(digest[:])
+}
+
+// ValidateCode checks whether the code can be instantiated with the ledger libs
+func ValidateCode(code []byte) error {
+	context := make(map[string]interface{})
+	store := wasm.NewStore()
+	libs := vmledger.NewLedgerWasmLibs(context, store)
+	_, err := wasm.NewWithStore(code, context, libs, store)
+	return err
+}
+
 func createAddress(b *types.Address, nonce uint64) *types.Address {
 	var data []byte
 	nonceBytes := make([]byte, 8)
@@ -1,6 +1,8 @@
 package wasm
 
 import (
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -9,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/bytecodealliance/wasmtime-go"
 	"github.com/meshplus/bitxhub-core/wasm/wasmlib"
 	"github.com/meshplus/bitxhub-kit/crypto"
 	"github.com/meshplus/bitxhub-kit/crypto/asym"
@@ -19,6 +22,7 @@ import (
 	"github.com/meshplus/bitxhub-model/pb"
 	"github.com/meshplus/bitxhub/internal/ledger"
 	"github.com/meshplus/bitxhub/internal/repo"
+	"github.com/meshplus/bitxhub/pkg/signer"
 	"github.com/meshplus/bitxhub/pkg/vm"
 	"github.com/meshplus/bitxhub/pkg/vm/wasm/vmledger"
 	libp2pcert "github.com/meshplus/go-libp2p-cert"
@@ -216,6 +220,11 @@ func TestDeploy(t *testing.T) {
 	require.Nil(t, err)
 	require.NotNil(t, deployData)
 	fmt.Printf("%s", string(deployData))
+
+	contract := &Contract{}
+	require.Nil(t, json.Unmarshal(ctx.Ledger.GetCode(types.NewAddress(deployData)), contract))
+	digest := sha256.Sum256(ctx.TransactionData.Payload)
+	require.Equal(t, types.NewHash(digest[:]).String(), contract.Hash.String())
 }
 
 func TestExecute(t *testing.T) {
@@ -452,7 +461,7 @@ BcNwjTDCxyxLNjFKQfMAc6sY6iJs+Ma59WZyC/4uhjE=
 
 	rep := &repo.Repo{
 		Key: &repo.Key{
-			PrivKey: privKey,
+			PrivKey: signer.NewLocalSigner(privKey),
 			Address: address.String(),
 		},
 		Config: &repo.Config{},
@@ -461,3 +470,77 @@ BcNwjTDCxyxLNjFKQfMAc6sY6iJs+Ma59WZyC/4uhjE=
 
 	return rep
 }
+
+const migrateTestWat = `
+(module
+  (import "env" "set_result" (func $set_result (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $heap (mut i32) (i32.const 1024))
+  (global $migrated (mut i32) (i32.const 48))
+  (func (export "allocate") (param $size i32) (result i32)
+    (local $ptr i32)
+    (local.set $ptr (global.get $heap))
+    (global.set $heap (i32.add (global.get $heap) (local.get $size)))
+    (local.get $ptr))
+  (func (export "deallocate") (param i32 i32))
+  (func (export "migrate")
+    (global.set $migrated (i32.const 49)))
+  (func (export "migrated") (result i32)
+    (i32.store8 (i32.const 0) (global.get $migrated))
+    (call $set_result (i32.const 0) (i32.const 1))
+    (i32.const 0)))
+`
+
+func TestMigrate(t *testing.T) {
+	ctx := initCreateContext(t, "migrate")
+	code, err := wasmtime.Wat2Wasm(migrateTestWat)
+	require.Nil(t, err)
+	ctx.TransactionData.Payload = code
+
+	w, err := New(ctx, nil, make(map[string]interface{}), NewStore())
+	require.Nil(t, err)
+	ret, _, err := w.deploy()
+	require.Nil(t, err)
+	addr := types.NewAddress(ret)
+
+	contract := &Contract{}
+	require.Nil(t, json.Unmarshal(ctx.Ledger.GetCode(addr), contract))
+	require.Equal(t, ctx.Caller.String(), contract.Owner)
+	require.Equal(t, uint64(1), contract.Version)
+	require.False(t, contract.Migrating)
+
+	payload, err := (&pb.InvokePayload{Method: "migrated"}).Marshal()
+	require.Nil(t, err)
+	run := func() string {
+		callCtx := &vm.Context{
+			Caller:          ctx.Caller,
+			CurrentCaller:   ctx.CurrentCaller,
+			Callee:          addr,
+			TransactionData: &pb.TransactionData{Payload: payload},
+			Ledger:          ctx.Ledger,
+			Tx:              ctx.Tx,
+		}
+		context := make(map[string]interface{})
+		store := NewStore()
+		w, err := New(callCtx, vmledger.NewLedgerWasmLibs(context, store), context, store)
+		require.Nil(t, err)
+		result, _, err := w.Run(payload, wasmGasLimit)
+		require.Nil(t, err)
+		return string(result)
+	}
+
+	require.Equal(t, "0", run())
+
+	// migrate method runs only once after upgrade
+	contract.Version++
+	contract.Migrating = true
+	data, err := json.Marshal(contract)
+	require.Nil(t, err)
+	ctx.Ledger.SetCode(addr, data)
+	require.Equal(t, "1", run())
+	require.Equal(t, "0", run())
+
+	require.Nil(t, json.Unmarshal(ctx.Ledger.GetCode(addr), contract))
+	require.False(t, contract.Migrating)
+	require.Equal(t, uint64(2), contract.Version)
+}